@@ -13,6 +13,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
@@ -28,6 +29,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/defaults"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/context"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/sqlite"
 	"github.com/hyperledger/aries-framework-go/pkg/vdri/httpbinding"
 )
 
@@ -53,6 +55,14 @@ const (
 	agentDBPathFlagUsage     = "Path to database." +
 		" Alternatively, this can be set with the following environment variable: " + agentDBPathEnvKey
 
+	// database type flag
+	agentDatabaseTypeFlagName  = "database-type"
+	agentDatabaseTypeEnvKey    = "ARIESD_DATABASE_TYPE"
+	agentDatabaseTypeFlagUsage = "Database type, used along with the path to database." +
+		" Possible values [leveldb] [sqlite]. Defaults to leveldb if not set." +
+		" With sqlite, the path to database is the path of the database file." +
+		" Alternatively, this can be set with the following environment variable: " + agentDatabaseTypeEnvKey
+
 	// webhook url flag
 	agentWebhookFlagName      = "webhook-url"
 	agentWebhookEnvKey        = "ARIESD_WEBHOOK_URL"
@@ -126,8 +136,18 @@ const (
 		" Refer https://github.com/hyperledger/aries-framework-go/blob/8449c727c7c44f47ed7c9f10f35f0cd051dcb4e9/pkg/framework/aries/framework.go#L165-L168." + // nolint lll
 		" Alternatively, this can be set with the following environment variable: " + agentTransportReturnRouteEnvKey
 
+	// key rotation max age flag
+	agentKeyRotationMaxAgeFlagName  = "key-rotation-max-age"
+	agentKeyRotationMaxAgeEnvKey    = "ARIESD_KEY_ROTATION_MAX_AGE"
+	agentKeyRotationMaxAgeFlagUsage = "Maximum age of the keys, the older keys are rotated and the peer DID" +
+		" documents using them are sent to their connections. Duration such as 720h. Keys aren't rotated if not set." +
+		" Alternatively, this can be set with the following environment variable: " + agentKeyRotationMaxAgeEnvKey
+
 	httpProtocol      = "http"
 	websocketProtocol = "ws"
+
+	databaseTypeLevelDBOption = "leveldb"
+	databaseTypeSQLiteOption  = "sqlite"
 )
 
 var errMissingHost = errors.New("host not provided")
@@ -136,10 +156,11 @@ var logger = log.New("aries-framework/agent-rest")
 type agentParameters struct {
 	server                                           server
 	host, dbPath, defaultLabel, transportReturnRoute string
-	token                                            string
+	token, databaseType                              string
 	webhookURLs, httpResolvers, outboundTransports   []string
 	inboundHostInternals, inboundHostExternals       []string
 	autoAccept                                       bool
+	keyRotationMaxAge                                time.Duration
 	msgHandler                                       command.MessageHandler
 }
 
@@ -207,6 +228,11 @@ func createStartCMD(server server) *cobra.Command { //nolint funlen gocyclo
 				return err
 			}
 
+			databaseType, err := getUserSetVar(cmd, agentDatabaseTypeFlagName, agentDatabaseTypeEnvKey, true)
+			if err != nil {
+				return err
+			}
+
 			defaultLabel, err := getUserSetVar(cmd, agentDefaultLabelFlagName, agentDefaultLabelEnvKey, true)
 			if err != nil {
 				return err
@@ -239,6 +265,11 @@ func createStartCMD(server server) *cobra.Command { //nolint funlen gocyclo
 				return err
 			}
 
+			keyRotationMaxAge, err := getKeyRotationMaxAge(cmd)
+			if err != nil {
+				return err
+			}
+
 			parameters := &agentParameters{
 				server:               server,
 				host:                 host,
@@ -246,12 +277,14 @@ func createStartCMD(server server) *cobra.Command { //nolint funlen gocyclo
 				inboundHostInternals: inboundHosts,
 				inboundHostExternals: inboundHostExternals,
 				dbPath:               dbPath,
+				databaseType:         databaseType,
 				defaultLabel:         defaultLabel,
 				webhookURLs:          webhookURLs,
 				httpResolvers:        httpResolvers,
 				outboundTransports:   outboundTransports,
 				autoAccept:           autoAccept,
 				transportReturnRoute: transportReturnRoute,
+				keyRotationMaxAge:    keyRotationMaxAge,
 			}
 
 			return startAgent(parameters)
@@ -272,6 +305,19 @@ func getAutoAcceptValue(cmd *cobra.Command) (bool, error) {
 	return strconv.ParseBool(v)
 }
 
+func getKeyRotationMaxAge(cmd *cobra.Command) (time.Duration, error) {
+	v, err := getUserSetVar(cmd, agentKeyRotationMaxAgeFlagName, agentKeyRotationMaxAgeEnvKey, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if v == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(v)
+}
+
 func createFlags(startCmd *cobra.Command) {
 	// agent host flag
 	startCmd.Flags().StringP(agentHostFlagName, agentHostFlagShorthand, "", agentHostFlagUsage)
@@ -290,6 +336,9 @@ func createFlags(startCmd *cobra.Command) {
 	// db path flag
 	startCmd.Flags().StringP(agentDBPathFlagName, agentDBPathFlagShorthand, "", agentDBPathFlagUsage)
 
+	// database type flag
+	startCmd.Flags().StringP(agentDatabaseTypeFlagName, "", "", agentDatabaseTypeFlagUsage)
+
 	// webhook url flag
 	startCmd.Flags().StringSliceP(agentWebhookFlagName, agentWebhookFlagShorthand, []string{}, agentWebhookFlagUsage)
 
@@ -313,6 +362,9 @@ func createFlags(startCmd *cobra.Command) {
 
 	// transport return route option flag
 	startCmd.Flags().StringP(agentTransportReturnRouteFlagName, "", "", agentTransportReturnRouteFlagUsage)
+
+	// key rotation max age flag
+	startCmd.Flags().StringP(agentKeyRotationMaxAgeFlagName, "", "", agentKeyRotationMaxAgeFlagUsage)
 }
 
 func getUserSetVar(cmd *cobra.Command, flagName, envKey string, isOptional bool) (string, error) {
@@ -387,6 +439,21 @@ func getResolverOpts(httpResolvers []string) ([]aries.Option, error) {
 	return opts, nil
 }
 
+func getStoreOpts(databaseType, dbPath string) ([]aries.Option, error) {
+	if dbPath == "" {
+		return nil, nil
+	}
+
+	switch databaseType {
+	case "", databaseTypeLevelDBOption:
+		return []aries.Option{defaults.WithStorePath(dbPath)}, nil
+	case databaseTypeSQLiteOption:
+		return []aries.Option{aries.WithStoreProvider(sqlite.NewProvider(dbPath))}, nil
+	default:
+		return nil, fmt.Errorf("database type not supported : %s", databaseType)
+	}
+}
+
 func getOutboundTransportOpts(outboundTransports []string) ([]aries.Option, error) {
 	var opts []aries.Option
 
@@ -552,10 +619,14 @@ func startAgent(parameters *agentParameters) error {
 func createAriesAgent(parameters *agentParameters) (*context.Provider, error) {
 	var opts []aries.Option
 
-	if parameters.dbPath != "" {
-		opts = append(opts, defaults.WithStorePath(parameters.dbPath))
+	storeOpts, err := getStoreOpts(parameters.databaseType, parameters.dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start aries agent rest on port [%s], failed to store opts : %w",
+			parameters.host, err)
 	}
 
+	opts = append(opts, storeOpts...)
+
 	if parameters.transportReturnRoute != "" {
 		opts = append(opts, aries.WithTransportReturnRoute(parameters.transportReturnRoute))
 	}
@@ -586,6 +657,10 @@ func createAriesAgent(parameters *agentParameters) (*context.Provider, error) {
 	opts = append(opts, outboundTransportOpts...)
 	opts = append(opts, aries.WithMessageServiceProvider(parameters.msgHandler))
 
+	if parameters.keyRotationMaxAge != 0 {
+		opts = append(opts, aries.WithKeyRotation(parameters.keyRotationMaxAge))
+	}
+
 	framework, err := aries.New(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start aries agent rest on port [%s], failed to initialize framework :  %w",
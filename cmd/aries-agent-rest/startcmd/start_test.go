@@ -14,6 +14,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -81,6 +82,7 @@ func TestStartCmdContents(t *testing.T) {
 	checkFlagPropertiesCorrect(t, startCmd, agentInboundHostFlagName,
 		agentInboundHostFlagShorthand, agentInboundHostFlagUsage, "[]")
 	checkFlagPropertiesCorrect(t, startCmd, agentDBPathFlagName, agentDBPathFlagShorthand, agentDBPathFlagUsage, "")
+	checkFlagPropertiesCorrect(t, startCmd, agentDatabaseTypeFlagName, "", agentDatabaseTypeFlagUsage, "")
 }
 
 func checkFlagPropertiesCorrect(t *testing.T, cmd *cobra.Command, flagName,
@@ -459,6 +461,58 @@ func TestStartCmdWithoutWebhookURLAndAutoAccept(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestStartCmdWithKeyRotationMaxAge(t *testing.T) {
+	t.Run("start with key rotation", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+
+		args := []string{
+			"--" + agentHostFlagName,
+			randomURL(),
+			"--" + agentInboundHostFlagName,
+			httpProtocol + "@" + randomURL(),
+			"--" + agentDBPathFlagName,
+			path,
+			"--" + agentAutoAcceptFlagName,
+			"true",
+			"--" + agentKeyRotationMaxAgeFlagName,
+			"720h",
+		}
+		startCmd.SetArgs(args)
+
+		require.NoError(t, startCmd.Execute())
+	})
+
+	t.Run("invalid key rotation max age", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+
+		args := []string{
+			"--" + agentHostFlagName,
+			randomURL(),
+			"--" + agentInboundHostFlagName,
+			httpProtocol + "@" + randomURL(),
+			"--" + agentDBPathFlagName,
+			path,
+			"--" + agentAutoAcceptFlagName,
+			"true",
+			"--" + agentKeyRotationMaxAgeFlagName,
+			"a month",
+		}
+		startCmd.SetArgs(args)
+
+		err = startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid duration")
+	})
+}
+
 func TestStartCmdValidArgs(t *testing.T) {
 	startCmd, err := Cmd(&mockServer{})
 	require.NoError(t, err)
@@ -487,6 +541,62 @@ func TestStartCmdValidArgs(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestStartCmdWithDatabaseType(t *testing.T) {
+	t.Run("start cmd with sqlite database", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+
+		args := []string{
+			"--" + agentHostFlagName,
+			randomURL(),
+			"--" + agentInboundHostFlagName,
+			httpProtocol + "@" + randomURL(),
+			"--" + agentDBPathFlagName,
+			filepath.Join(path, "aries.db"),
+			"--" + agentDatabaseTypeFlagName,
+			databaseTypeSQLiteOption,
+			"--" + agentWebhookFlagName,
+			"",
+		}
+		startCmd.SetArgs(args)
+
+		err = startCmd.Execute()
+		require.NoError(t, err)
+
+		_, err = os.Stat(filepath.Join(path, "aries.db"))
+		require.NoError(t, err)
+	})
+
+	t.Run("start cmd with unsupported database", func(t *testing.T) {
+		startCmd, err := Cmd(&mockServer{})
+		require.NoError(t, err)
+
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+
+		args := []string{
+			"--" + agentHostFlagName,
+			randomURL(),
+			"--" + agentInboundHostFlagName,
+			httpProtocol + "@" + randomURL(),
+			"--" + agentDBPathFlagName,
+			path,
+			"--" + agentDatabaseTypeFlagName,
+			"unknown",
+			"--" + agentWebhookFlagName,
+			"",
+		}
+		startCmd.SetArgs(args)
+
+		err = startCmd.Execute()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "database type not supported : unknown")
+	})
+}
+
 func TestStartCmdValidArgsEnvVar(t *testing.T) {
 	startCmd, err := Cmd(&mockServer{})
 	require.NoError(t, err)
@@ -265,9 +265,15 @@ func addAriesHandlers(pkgMap map[string]map[string]func(*command) *result) {
 			return newErrResult(c.ID, err.Error())
 		}
 
+		// closed when the agent is stopped, so that the commands of a restarted agent don't keep notifying
+		shutdown := make(chan struct{})
+
 		commands, err := controller.GetCommandHandlers(ctx, controller.WithMessageHandler(msgHandler),
-			controller.WithDefaultLabel(cOpts.Label), controller.WithNotifier(&jsNotifier{}))
+			controller.WithDefaultLabel(cOpts.Label), controller.WithNotifier(&jsNotifier{}),
+			controller.WithShutdown(shutdown))
 		if err != nil {
+			close(shutdown)
+
 			return newErrResult(c.ID, err.Error())
 		}
 
@@ -275,7 +281,7 @@ func addAriesHandlers(pkgMap map[string]map[string]func(*command) *result) {
 		addCommandHandlers(commands, pkgMap)
 
 		// add stop aries handler
-		addStopAriesHandler(a, pkgMap)
+		addStopAriesHandler(a, shutdown, pkgMap)
 
 		return &result{
 			ID:      c.ID,
@@ -298,7 +304,7 @@ func addCommandHandlers(commands []cmdctrl.Handler, pkgMap map[string]map[string
 	}
 }
 
-func addStopAriesHandler(a *aries.Aries, pkgMap map[string]map[string]func(*command) *result) {
+func addStopAriesHandler(a *aries.Aries, shutdown chan struct{}, pkgMap map[string]map[string]func(*command) *result) {
 	fnMap := make(map[string]func(*command) *result)
 	fnMap[ariesStopFn] = func(c *command) *result {
 		err := a.Close()
@@ -306,6 +312,8 @@ func addStopAriesHandler(a *aries.Aries, pkgMap map[string]map[string]func(*comm
 			return newErrResult(c.ID, err.Error())
 		}
 
+		close(shutdown)
+
 		// reset handlers when stopped
 		for k := range pkgMap {
 			delete(pkgMap, k)
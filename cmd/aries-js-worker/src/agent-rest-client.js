@@ -43,8 +43,9 @@ const pkgs = {
             pathParam:"id"
         },
         QueryConnections: {
-            path: "/connections",
-            method: "GET"
+            path: "/connections?state={state}&my_did={my_did}&their_did={their_did}&their_label={their_label}&sort_by={sort_by}&order={order}&cursor={cursor}&limit={limit}",
+            method: "GET",
+            queryStrings: ["state", "my_did", "their_did", "their_label", "sort_by", "order", "cursor", "limit"]
         },
     },
     vdri: {
@@ -133,8 +134,9 @@ const pkgs = {
             pathParam:"name"
         },
         GetCredentials: {
-            path: "/verifiable/credentials",
+            path: "/verifiable/credentials?type={type}&subjectId={subjectId}&sortBy={sortBy}&order={order}&cursor={cursor}&limit={limit}",
             method: "GET",
+            queryStrings: ["type", "subjectId", "sortBy", "order", "cursor", "limit"]
         },
         GeneratePresentation: {
             path: "/verifiable/presentation/generate",
@@ -276,6 +278,35 @@ const pkgs = {
             path: "/kms/keyset",
             method: "POST",
         },
+        ListKeys: {
+            path: "/kms/keys",
+            method: "GET",
+        },
+        GetKeyMetadata: {
+            path: "/kms/keys/{keyID}",
+            method: "GET",
+            pathParam:"keyID"
+        },
+        UpdateKeyMetadata: {
+            path: "/kms/keys/{keyID}/metadata",
+            method: "POST",
+            pathParam:"keyID"
+        },
+        DeleteKey: {
+            path: "/kms/keys/{keyID}",
+            method: "DELETE",
+            pathParam:"keyID"
+        },
+    },
+    wallet: {
+        Export: {
+            path: "/wallet/export",
+            method: "POST",
+        },
+        Import: {
+            path: "/wallet/import",
+            method: "POST",
+        },
     },
 }
 
@@ -304,7 +335,7 @@ export const Client = class {
 
         if (r.queryStrings){
             r.queryStrings.forEach(p => {
-                url = url.replace("{"+ p +"}", (request.payload[p]) ? request.payload[p] : "");
+                url = url.replace("{"+ p +"}", (request.payload && request.payload[p]) ? request.payload[p] : "");
             })
         }
 
@@ -722,10 +722,12 @@ const Aries = function (opts) {
             /**
              * Retrieves verifiable credential records containing name and id.
              *
+             * @param req - optional json document with filters (type, subjectId), sort (sortBy, order) and paging
+             * (cursor, limit) params
              * @returns {Promise<Object>}
              */
-            getCredentials: async function () {
-                return invoke(aw, pending, this.pkgname, "GetCredentials", {}, "timeout while retrieving verifiable credentials")
+            getCredentials: async function (req) {
+                return invoke(aw, pending, this.pkgname, "GetCredentials", req || {}, "timeout while retrieving verifiable credentials")
             },
 
             /**
@@ -793,6 +795,68 @@ const Aries = function (opts) {
             createKeySet: async function (req) {
                 return invoke(aw, pending, this.pkgname, "CreateKeySet", req, "timeout while creating key set")
             },
+
+            /**
+             * Lists the metadata of the keys.
+             *
+             * @returns {Promise<Object>}
+             */
+            listKeys: async function () {
+                return invoke(aw, pending, this.pkgname, "ListKeys", {}, "timeout while listing keys")
+            },
+
+            /**
+             * Retrieves the metadata of a key.
+             *
+             * @returns {Promise<Object>}
+             */
+            getKeyMetadata: async function (req) {
+                return invoke(aw, pending, this.pkgname, "GetKeyMetadata", req, "timeout while getting key metadata")
+            },
+
+            /**
+             * Records what a key is used for: its purpose, DID and verification method.
+             *
+             * @returns {Promise<Object>}
+             */
+            updateKeyMetadata: async function (req) {
+                return invoke(aw, pending, this.pkgname, "UpdateKeyMetadata", req, "timeout while updating key metadata")
+            },
+
+            /**
+             * Deletes a key.
+             *
+             * @returns {Promise<Object>}
+             */
+            deleteKey: async function (req) {
+                return invoke(aw, pending, this.pkgname, "DeleteKey", req, "timeout while deleting key")
+            },
+        },
+
+        /**
+         * Wallet backup - Refer to [OpenAPI spec](docs/rest/openapi_spec.md#generate-openapi-spec) for
+         * input params and output return json values.
+         */
+        wallet: {
+            pkgname: "wallet",
+
+            /**
+             * Exports every store of the wallet into an archive encrypted with the given passphrase.
+             *
+             * @returns {Promise<Object>}
+             */
+            export: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Export", req, "timeout while exporting wallet")
+            },
+
+            /**
+             * Imports the stores of an archive returned by export.
+             *
+             * @returns {Promise<Object>}
+             */
+            import: async function (req) {
+                return invoke(aw, pending, this.pkgname, "Import", req, "timeout while importing wallet")
+            },
         }
     }
 
@@ -11,12 +11,13 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
@@ -26,6 +27,10 @@ const (
 	InvitationMsgType = didexchange.InvitationMsgType
 	// RequestMsgType defines the did-exchange request message type.
 	RequestMsgType = didexchange.RequestMsgType
+	// SortAscending is the ascending order of queried connections, the default.
+	SortAscending = "asc"
+	// SortDescending is the descending order of queried connections.
+	SortDescending = "desc"
 )
 
 // ErrConnectionNotFound is returned when connection not found
@@ -34,7 +39,7 @@ var ErrConnectionNotFound = errors.New("connection not found")
 // provider contains dependencies for the DID exchange protocol and is typically created by using aries.Context()
 type provider interface {
 	Service(id string) (interface{}, error)
-	LegacyKMS() legacykms.KeyManager
+	KMS() kms.KeyManager
 	ServiceEndpoint() string
 	StorageProvider() storage.Provider
 	TransientStorageProvider() storage.Provider
@@ -45,7 +50,7 @@ type Client struct {
 	service.Event
 	didexchangeSvc  protocolService
 	routeSvc        route.ProtocolService
-	legacyKMS       legacykms.KeyManager
+	kms             kms.KeyManager
 	serviceEndpoint string
 	connectionStore *connection.Recorder
 }
@@ -97,7 +102,7 @@ func New(ctx provider) (*Client, error) {
 		Event:           didexchangeSvc,
 		didexchangeSvc:  didexchangeSvc,
 		routeSvc:        routeSvc,
-		legacyKMS:       ctx.LegacyKMS(),
+		kms:             ctx.KMS(),
 		serviceEndpoint: ctx.ServiceEndpoint(),
 		connectionStore: connectionStore,
 	}, nil
@@ -109,11 +114,13 @@ func New(ctx provider) (*Client, error) {
 func (c *Client) CreateInvitation(label string) (*Invitation, error) {
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/623 'alias' should be passed as arg and persisted
 	//  with connection record
-	_, sigPubKey, err := c.legacyKMS.CreateKeySet()
+	_, pubKey, err := c.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
 	if err != nil {
 		return nil, fmt.Errorf("failed CreateSigningKey: %w", err)
 	}
 
+	sigPubKey := base58.Encode(pubKey)
+
 	// get the route configs
 	serviceEndpoint, routingKeys, err := route.GetRouterConfig(c.routeSvc, c.serviceEndpoint)
 	if err != nil {
@@ -219,21 +226,46 @@ func (c *Client) CreateImplicitInvitationWithDID(inviter, invitee *DIDInfo) (str
 
 // QueryConnections queries connections matching given criteria(parameters)
 func (c *Client) QueryConnections(request *QueryConnectionsParams) ([]*Connection, error) {
-	// TODO https://github.com/hyperledger/aries-framework-go/issues/655 - query all connections from all criteria and
-	//  also results needs to be paged.
-	records, err := c.connectionStore.QueryConnectionRecords()
+	page, err := c.QueryConnectionsPage(request)
 	if err != nil {
-		return nil, fmt.Errorf("failed query connections: %w", err)
+		return nil, err
 	}
 
-	var result []*Connection
+	return page.Connections, nil
+}
 
-	for _, record := range records {
-		if request.State != "" && request.State != record.State {
-			continue
-		}
+// QueryConnectionsPage queries a page of connections matching given criteria(parameters), in the requested order.
+// The next page is queried with the same criteria and the next cursor of the page.
+func (c *Client) QueryConnectionsPage(request *QueryConnectionsParams) (*ConnectionsPage, error) {
+	// TODO https://github.com/hyperledger/aries-framework-go/issues/655 - query all connections from all criteria
+	var descending bool
+
+	switch request.Order {
+	case "", SortAscending:
+	case SortDescending:
+		descending = true
+	default:
+		return nil, fmt.Errorf("failed query connections: invalid order %s", request.Order)
+	}
+
+	page, err := c.connectionStore.SearchConnectionRecords(&connection.Query{
+		State:      request.State,
+		MyDID:      request.MyDID,
+		TheirDID:   request.TheirDID,
+		TheirLabel: request.TheirLabel,
+		SortBy:     connection.SortField(request.SortBy),
+		Descending: descending,
+		Cursor:     request.Cursor,
+		Limit:      request.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed query connections: %w", err)
+	}
+
+	result := &ConnectionsPage{NextCursor: page.NextCursor}
 
-		result = append(result, &Connection{Record: record})
+	for _, record := range page.Records {
+		result.Connections = append(result.Connections, &Connection{Record: record})
 	}
 
 	return result, nil
@@ -26,9 +26,10 @@ import (
 	mocksvc "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/didexchange"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/route"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
@@ -140,7 +141,7 @@ func TestClient_CreateInvitation(t *testing.T) {
 				didexchange.DIDExchange: svc,
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 
 		require.NoError(t, err)
@@ -169,7 +170,7 @@ func TestClient_CreateInvitation(t *testing.T) {
 				didexchange.DIDExchange: svc,
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue: &mockkms.CloseableKMS{CreateKeyErr: fmt.Errorf("createKeyErr")}})
+			KMSValue: &mockkms.KeyManager{CrAndExportPubKeyErr: fmt.Errorf("createKeyErr")}})
 		require.NoError(t, err)
 		_, err = c.CreateInvitation("agent")
 		require.Error(t, err)
@@ -198,7 +199,7 @@ func TestClient_CreateInvitation(t *testing.T) {
 				didexchange.DIDExchange: svc,
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue: &mockkms.CloseableKMS{}})
+			KMSValue: &mockkms.KeyManager{}})
 		require.NoError(t, err)
 		_, err = c.CreateInvitation("agent")
 		require.Error(t, err)
@@ -224,7 +225,7 @@ func TestClient_CreateInvitation(t *testing.T) {
 				didexchange.DIDExchange: svc,
 				route.Coordination:      &mockroute.MockRouteSvc{RoutingKeys: routingKeys, RouterEndpoint: endpoint},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint",
 		})
 		require.NoError(t, err)
@@ -254,7 +255,7 @@ func TestClient_CreateInvitation(t *testing.T) {
 				didexchange.DIDExchange: svc,
 				route.Coordination:      &mockroute.MockRouteSvc{ConfigErr: errors.New("router config error")},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint",
 		})
 		require.NoError(t, err)
@@ -288,7 +289,7 @@ func TestClient_CreateInvitation(t *testing.T) {
 					AddKeyErr:      errors.New("failed to add key to the router"),
 				},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint",
 		})
 		require.NoError(t, err)
@@ -317,7 +318,7 @@ func TestClient_CreateInvitationWithDID(t *testing.T) {
 				didexchange.DIDExchange: svc,
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 		require.NoError(t, err)
 
@@ -353,7 +354,7 @@ func TestClient_CreateInvitationWithDID(t *testing.T) {
 				didexchange.DIDExchange: svc,
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue: &mockkms.CloseableKMS{}})
+			KMSValue: &mockkms.KeyManager{}})
 		require.NoError(t, err)
 
 		_, err = c.CreateInvitationWithDID("agent", "did:sidetree:123")
@@ -384,7 +385,7 @@ func TestClient_QueryConnectionByID(t *testing.T) {
 				didexchange.DIDExchange: svc,
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 		require.NoError(t, err)
 
@@ -408,10 +409,7 @@ func TestClient_QueryConnectionByID(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, svc)
 
-		store := &mockstore.MockStore{
-			Store:  make(map[string][]byte),
-			ErrGet: fmt.Errorf(errMsg),
-		}
+		store := &mockstore.MockStore{Store: make(map[string][]byte)}
 
 		c, err := New(&mockprovider.Provider{
 			TransientStorageProviderValue: mockstore.NewCustomMockStoreProvider(store),
@@ -420,7 +418,7 @@ func TestClient_QueryConnectionByID(t *testing.T) {
 				didexchange.DIDExchange: svc,
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 		require.NoError(t, err)
 
@@ -428,6 +426,9 @@ func TestClient_QueryConnectionByID(t *testing.T) {
 
 		require.NoError(t, err)
 		require.NoError(t, c.connectionStore.SaveConnectionRecord(connRec))
+
+		store.ErrGet = fmt.Errorf(errMsg)
+
 		_, err = c.GetConnection(connID)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), errMsg)
@@ -449,7 +450,7 @@ func TestClient_QueryConnectionByID(t *testing.T) {
 				didexchange.DIDExchange: svc,
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 		require.NoError(t, err)
 
@@ -590,7 +591,7 @@ func TestClient_HandleInvitation(t *testing.T) {
 				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 
 		require.NoError(t, err)
@@ -613,7 +614,7 @@ func TestClient_HandleInvitation(t *testing.T) {
 				route.Coordination: &mockroute.MockRouteSvc{},
 			},
 
-			LegacyKMSValue: &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"}, ServiceEndpointValue: "endpoint"})
+			KMSValue: &mockkms.KeyManager{}, ServiceEndpointValue: "endpoint"})
 		require.NoError(t, err)
 		inviteReq, err := c.CreateInvitation("agent")
 		require.NoError(t, err)
@@ -633,7 +634,7 @@ func TestClient_CreateImplicitInvitation(t *testing.T) {
 				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 		require.NoError(t, err)
 
@@ -651,7 +652,7 @@ func TestClient_CreateImplicitInvitation(t *testing.T) {
 					ImplicitInvitationErr: errors.New("implicit error")},
 				route.Coordination: &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 		require.NoError(t, err)
 
@@ -674,7 +675,7 @@ func TestClient_CreateImplicitInvitationWithDID(t *testing.T) {
 				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 		require.NoError(t, err)
 
@@ -692,7 +693,7 @@ func TestClient_CreateImplicitInvitationWithDID(t *testing.T) {
 					ImplicitInvitationErr: errors.New("implicit with DID error")},
 				route.Coordination: &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 		require.NoError(t, err)
 
@@ -710,7 +711,7 @@ func TestClient_CreateImplicitInvitationWithDID(t *testing.T) {
 				didexchange.DIDExchange: &mocksvc.MockDIDExchangeSvc{},
 				route.Coordination:      &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue:       &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+			KMSValue:             &mockkms.KeyManager{},
 			ServiceEndpointValue: "endpoint"})
 		require.NoError(t, err)
 
@@ -748,15 +749,12 @@ func TestClient_QueryConnectionsByParams(t *testing.T) {
 		require.NoError(t, err)
 
 		const count = 10
-		const keyPrefix = "conn_"
 		const state = "completed"
 		for i := 0; i < count; i++ {
-			val, e := json.Marshal(&connection.Record{
-				ConnectionID: string(i),
+			require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
+				ConnectionID: fmt.Sprintf("abc%d", i),
 				State:        state,
-			})
-			require.NoError(t, e)
-			require.NoError(t, storageProvider.Store.Put(fmt.Sprintf("%sabc%d", keyPrefix, i), val))
+			}))
 		}
 
 		results, err := c.QueryConnections(&QueryConnectionsParams{})
@@ -789,7 +787,6 @@ func TestClient_QueryConnectionsByParams(t *testing.T) {
 
 		const count = 10
 		const countWithState = 5
-		const keyPrefix = "conn_"
 		const state = "completed"
 		for i := 0; i < count; i++ {
 			var queryState string
@@ -797,12 +794,10 @@ func TestClient_QueryConnectionsByParams(t *testing.T) {
 				queryState = state
 			}
 
-			val, e := json.Marshal(&connection.Record{
-				ConnectionID: string(i),
+			require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
+				ConnectionID: fmt.Sprintf("abc%d", i),
 				State:        queryState,
-			})
-			require.NoError(t, e)
-			require.NoError(t, storageProvider.Store.Put(fmt.Sprintf("%sabc%d", keyPrefix, i), val))
+			}))
 		}
 
 		results, err := c.QueryConnections(&QueryConnectionsParams{})
@@ -842,12 +837,68 @@ func TestClient_QueryConnectionsByParams(t *testing.T) {
 		})
 		require.NoError(t, err)
 
+		require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
+			ConnectionID: "abc",
+			State:        "completed",
+		}))
 		require.NoError(t, storageProvider.Store.Put(fmt.Sprintf("%sabc", keyPrefix), []byte("----")))
 
 		results, err := c.QueryConnections(&QueryConnectionsParams{})
 		require.Error(t, err)
 		require.Empty(t, results)
 	})
+
+	t.Run("test query connections page", func(t *testing.T) {
+		svc, err := didexchange.New(&mockprotocol.MockProvider{
+			ServiceMap: map[string]interface{}{
+				route.Coordination: &mockroute.MockRouteSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		// pages are read in the key order of the stores
+		c, err := New(&mockprovider.Provider{
+			TransientStorageProviderValue: mem.NewProvider(),
+			StorageProviderValue:          mem.NewProvider(),
+			ServiceMap: map[string]interface{}{
+				didexchange.DIDExchange: svc,
+				route.Coordination:      &mockroute.MockRouteSvc{},
+			},
+		})
+		require.NoError(t, err)
+
+		for i, label := range []string{"bob", "alice", "carol"} {
+			require.NoError(t, c.connectionStore.SaveConnectionRecord(&connection.Record{
+				ConnectionID: fmt.Sprintf("conn%d", i),
+				TheirLabel:   label,
+			}))
+		}
+
+		page, err := c.QueryConnectionsPage(&QueryConnectionsParams{SortBy: "label", Order: SortDescending, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Connections, 2)
+		require.Equal(t, "carol", page.Connections[0].TheirLabel)
+		require.Equal(t, "bob", page.Connections[1].TheirLabel)
+		require.NotEmpty(t, page.NextCursor)
+
+		page, err = c.QueryConnectionsPage(&QueryConnectionsParams{SortBy: "label", Order: SortDescending, Limit: 2,
+			Cursor: page.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, page.Connections, 1)
+		require.Equal(t, "alice", page.Connections[0].TheirLabel)
+		require.Empty(t, page.NextCursor)
+
+		results, err := c.QueryConnections(&QueryConnectionsParams{TheirLabel: "alice"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, "conn1", results[0].ConnectionID)
+
+		_, err = c.QueryConnectionsPage(&QueryConnectionsParams{Order: "sideways"})
+		require.EqualError(t, err, "failed query connections: invalid order sideways")
+
+		_, err = c.QueryConnectionsPage(&QueryConnectionsParams{SortBy: "unknown"})
+		require.Error(t, err)
+	})
 }
 
 func TestServiceEvents(t *testing.T) {
@@ -870,7 +921,7 @@ func TestServiceEvents(t *testing.T) {
 			didexchange.DIDExchange: didExSvc,
 			route.Coordination:      &mockroute.MockRouteSvc{},
 		},
-		LegacyKMSValue: &mockkms.CloseableKMS{CreateSigningKeyValue: "sample-key"}})
+		KMSValue: &mockkms.KeyManager{CrAndExportPubKeyValue: []byte("sample-key")}})
 	require.NoError(t, err)
 	require.NotNil(t, c)
 
@@ -961,7 +1012,7 @@ func TestAcceptExchangeRequest(t *testing.T) {
 			didexchange.DIDExchange: didExSvc,
 			route.Coordination:      &mockroute.MockRouteSvc{},
 		},
-		LegacyKMSValue: &mockkms.CloseableKMS{CreateSigningKeyValue: "sample-key"}},
+		KMSValue: &mockkms.KeyManager{CrAndExportPubKeyValue: []byte("sample-key")}},
 	)
 	require.NoError(t, err)
 	require.NotNil(t, c)
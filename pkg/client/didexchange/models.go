@@ -37,6 +37,32 @@ type QueryConnectionsParams struct {
 
 	// TheirRole is other party's role
 	TheirRole string `json:"their_role,omitempty"`
+
+	// TheirLabel is other party's label
+	TheirLabel string `json:"their_label,omitempty"`
+
+	// SortBy is the field connections are sorted by: created (default), label or state
+	SortBy string `json:"sort_by,omitempty"`
+
+	// Order of the sort: asc (default) or desc
+	Order string `json:"order,omitempty"`
+
+	// Cursor is the next cursor of the previous page, empty for the first page
+	Cursor string `json:"cursor,omitempty"`
+
+	// Limit is the maximum number of connections returned, zero for no limit
+	Limit int `json:"limit,omitempty"`
+}
+
+// ConnectionsPage model
+//
+// A page of connections matching a query
+//
+type ConnectionsPage struct {
+	Connections []*Connection
+
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string
 }
 
 // Connection model
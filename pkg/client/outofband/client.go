@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
@@ -18,7 +19,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 type (
@@ -66,7 +67,7 @@ type oobService interface {
 type Provider interface {
 	ServiceEndpoint() string
 	Service(id string) (interface{}, error)
-	LegacyKMS() legacykms.KeyManager
+	KMS() kms.KeyManager
 }
 
 // Client for the Out-Of-Band protocol:
@@ -267,11 +268,13 @@ func didServiceBlockFunc(p Provider) func() (*did.Service, error) {
 	return func() (*did.Service, error) {
 		// TODO https://github.com/hyperledger/aries-framework-go/issues/623 'alias' should be passed as arg and persisted
 		//  with connection record
-		_, verKey, err := p.LegacyKMS().CreateKeySet()
+		_, pubKey, err := p.KMS().CreateAndExportPubKeyBytes(kms.ED25519Type)
 		if err != nil {
 			return nil, fmt.Errorf("failed CreateSigningKey: %w", err)
 		}
 
+		verKey := base58.Encode(pubKey)
+
 		s, err := p.Service(route.Coordination)
 		if err != nil {
 			return nil, err
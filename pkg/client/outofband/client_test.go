@@ -24,7 +24,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/route"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 )
 
@@ -177,7 +177,7 @@ func TestCreateRequest(t *testing.T) {
 	t.Run("wraps did service block creation error when KMS fails", func(t *testing.T) {
 		expected := errors.New("test")
 		provider := withTestProvider()
-		provider.LegacyKMSValue = &mockkms.CloseableKMS{CreateKeyErr: expected}
+		provider.KMSValue = &mockkms.KeyManager{CrAndExportPubKeyErr: expected}
 		c, err := New(provider)
 		require.NoError(t, err)
 		_, err = c.CreateRequest([]*decorator.Attachment{dummyAttachment(t)})
@@ -436,7 +436,7 @@ func withTestProvider() *mockprovider.Provider {
 	return &mockprovider.Provider{
 		TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
 		StorageProviderValue:          mockstore.NewMockStoreProvider(),
-		LegacyKMSValue:                &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+		KMSValue:                      &mockkms.KeyManager{},
 		ServiceMap: map[string]interface{}{
 			route.Coordination: &mockroute.MockRouteSvc{},
 			outofband.Name:     &stubOOBService{},
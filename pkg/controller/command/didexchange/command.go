@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/hyperledger/aries-framework-go/pkg/client/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
@@ -18,8 +19,9 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
 var logger = log.New("aries-framework/controller/did-exchange")
@@ -28,12 +30,17 @@ const (
 	// command name
 	commandName = "didexchange"
 
-	// webhook notifier topic
-	connectionsWebhookTopic = "connections"
+	// webhook notifier topics
+	connectionsWebhookTopic       = "connections"
+	connectionRecordsWebhookTopic = "connection_records"
+
+	// number of record notifications sent concurrently
+	maxPendingRecordNotifications = 16
 
 	// error messages
 	errEmptyInviterDID = "empty inviter DID"
 	errEmptyConnID     = "empty connection ID"
+	errNegativeLimit   = "limit can't be negative"
 
 	// command methods
 	acceptExchangeRequestCommandMethod    = "AcceptExchangeRequest"
@@ -51,6 +58,7 @@ const (
 	successString              = "success"
 	invitationIDString         = "invitationID"
 	sendConnectionNotification = "sendConnectionNotification"
+	sendRecordNotification     = "sendRecordNotification"
 )
 
 const (
@@ -83,7 +91,7 @@ const (
 // provider contains dependencies for the DID Exchange command and is typically created by using aries.Context()
 type provider interface {
 	Service(id string) (interface{}, error)
-	LegacyKMS() legacykms.KeyManager
+	KMS() kms.KeyManager
 	ServiceEndpoint() string
 	StorageProvider() storage.Provider
 	TransientStorageProvider() storage.Provider
@@ -120,6 +128,11 @@ func New(ctx provider, notifier command.Notifier, defaultLabel string, autoAccep
 		return nil, fmt.Errorf("event listener startup failed: %w", err)
 	}
 
+	cmd.stopRecordEvents, err = cmd.startRecordEventListener()
+	if err != nil {
+		return nil, fmt.Errorf("record event listener startup failed: %w", err)
+	}
+
 	return cmd, nil
 }
 
@@ -130,6 +143,13 @@ type Command struct {
 	msgCh        chan service.StateMsg
 	notifier     command.Notifier
 	defaultLabel string
+	// stops the notifications of the connection record changes
+	stopRecordEvents func() error
+}
+
+// Close stops the notifications of the connection record changes, on the shutdown of the agent.
+func (c *Command) Close() error {
+	return c.stopRecordEvents()
 }
 
 // GetHandlers returns list of all commands supported by this controller command
@@ -328,14 +348,20 @@ func (c *Command) QueryConnections(rw io.Writer, req io.Reader) command.Error {
 		return command.NewValidationError(InvalidRequestErrorCode, err)
 	}
 
-	results, err := c.client.QueryConnections(&request.QueryConnectionsParams)
+	if request.Limit < 0 {
+		logutil.LogDebug(logger, commandName, queryConnectionsCommandMethod, errNegativeLimit)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errNegativeLimit))
+	}
+
+	page, err := c.client.QueryConnectionsPage(&request.QueryConnectionsParams)
 	if err != nil {
 		logutil.LogError(logger, commandName, queryConnectionsCommandMethod, err.Error())
 		return command.NewExecuteError(QueryConnectionsErrorCode, err)
 	}
 
 	command.WriteNillableResponse(rw, &QueryConnectionsResponse{
-		Results: results,
+		Results:    page.Connections,
+		NextCursor: page.NextCursor,
 	}, logger)
 
 	logutil.LogDebug(logger, commandName, queryConnectionsCommandMethod, successString)
@@ -426,6 +452,68 @@ func (c *Command) startClientEventListener() error {
 	return nil
 }
 
+// startRecordEventListener notifies the changes of connection records, provided the storage reports them.
+// The returned func stops the notifications.
+func (c *Command) startRecordEventListener() (func() error, error) {
+	lookup, err := connection.NewLookup(c.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create connection lookup: %w", err)
+	}
+
+	events := make(chan connection.RecordEvent)
+
+	err = lookup.RegisterRecordEvent(events)
+	if errors.Is(err, storage.ErrChangeFeedNotSupported) {
+		logger.Debugf("connection record notifications disabled: %s", err)
+
+		return func() error { return nil }, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("connection record event registration failed: %w", err)
+	}
+
+	stop := make(chan struct{})
+
+	// webhooks are notified asynchronously so that a slow webhook doesn't hold up the record events
+	go func() {
+		pending := make(chan struct{}, maxPendingRecordNotifications)
+
+		for {
+			select {
+			case e := <-events:
+				pending <- struct{}{}
+
+				go func(e connection.RecordEvent) {
+					defer func() { <-pending }()
+
+					err := c.sendRecordNotification(e)
+					if err != nil {
+						logger.Errorf("handle record events failed : %s", err)
+					}
+				}(e)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var (
+		once          sync.Once
+		unregisterErr error
+	)
+
+	return func() error {
+		once.Do(func() {
+			unregisterErr = lookup.UnregisterRecordEvent(events)
+
+			close(stop)
+		})
+
+		return unregisterErr
+	}, nil
+}
+
 func (c *Command) handleMessageEvents(e service.StateMsg) error {
 	if e.Type == service.PostState {
 		switch v := e.Properties.(type) {
@@ -491,3 +579,26 @@ func (c *Command) sendConnectionNotification(connectionID, stateID string) error
 
 	return nil
 }
+
+func (c *Command) sendRecordNotification(e connection.RecordEvent) error {
+	jsonMessage, err := json.Marshal(&ConnectionRecordMsg{
+		ConnectionID: e.ConnectionID,
+		Operation:    string(e.Operation),
+	})
+	if err != nil {
+		return fmt.Errorf("connection record notification json marshal : %w", err)
+	}
+
+	err = c.notifier.Notify(connectionRecordsWebhookTopic, jsonMessage)
+	if err != nil {
+		logutil.LogError(logger, commandName, sendRecordNotification, err.Error(),
+			logutil.CreateKeyValueString(connectionIDString, e.ConnectionID))
+
+		return fmt.Errorf("connection record notification webhook : %w", err)
+	}
+
+	logutil.LogDebug(logger, commandName, sendRecordNotification, successString,
+		logutil.CreateKeyValueString(connectionIDString, e.ConnectionID))
+
+	return nil
+}
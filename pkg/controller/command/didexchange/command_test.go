@@ -32,9 +32,11 @@ import (
 	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/didexchange"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/route"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
 
@@ -323,13 +325,7 @@ func TestCommand_QueryConnections(t *testing.T) {
 		const state = "requested"
 
 		prov := mockProvider()
-		store := mockstore.MockStore{Store: make(map[string][]byte)}
-		connRec := &connection.Record{State: state, ConnectionID: connID, ThreadID: "th1234"}
-
-		connBytes, err := json.Marshal(connRec)
-		require.NoError(t, err)
-		require.NoError(t, store.Put("conn_"+connID, connBytes))
-		prov.StorageProviderValue = &mockstore.MockStoreProvider{Store: &store}
+		saveConnectionRecords(t, prov, &connection.Record{State: state, ConnectionID: connID, ThreadID: "th1234"})
 
 		cmd, err := New(prov, mockwebhook.NewMockWebhookNotifier(), "", false)
 		require.NoError(t, err)
@@ -370,13 +366,7 @@ func TestCommand_QueryConnections(t *testing.T) {
 		const connID = "1234"
 
 		prov := mockProvider()
-		store := mockstore.MockStore{Store: make(map[string][]byte)}
-		connRec := &connection.Record{State: "completed", ConnectionID: connID, ThreadID: "th1234"}
-
-		connBytes, err := json.Marshal(connRec)
-		require.NoError(t, err)
-		require.NoError(t, store.Put("conn_"+connID, connBytes))
-		prov.StorageProviderValue = &mockstore.MockStoreProvider{Store: &store}
+		saveConnectionRecords(t, prov, &connection.Record{State: "completed", ConnectionID: connID, ThreadID: "th1234"})
 
 		cmd, err := New(prov, mockwebhook.NewMockWebhookNotifier(), "", false)
 		require.NoError(t, err)
@@ -407,6 +397,49 @@ func TestCommand_QueryConnections(t *testing.T) {
 		require.Error(t, cmdErr)
 		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
 		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		cmdErr = cmd.QueryConnections(&b, bytes.NewBufferString(`{"limit":-1}`))
+		require.EqualError(t, cmdErr, errNegativeLimit)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		cmdErr = cmd.QueryConnections(&b, bytes.NewBufferString(`{"cursor":"--"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, QueryConnectionsErrorCode, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+
+	t.Run("test query connections pages", func(t *testing.T) {
+		prov := mockProvider()
+
+		for _, id := range []string{"1", "2", "3"} {
+			saveConnectionRecords(t, prov, &connection.Record{State: "completed", ConnectionID: id, TheirLabel: "label" + id})
+		}
+
+		cmd, err := New(prov, mockwebhook.NewMockWebhookNotifier(), "", false)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		cmdErr := cmd.QueryConnections(&b, bytes.NewBufferString(`{"sort_by":"label","order":"desc","limit":2}`))
+		require.NoError(t, cmdErr)
+
+		response := QueryConnectionsResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Len(t, response.Results, 2)
+		require.Equal(t, "3", response.Results[0].ConnectionID)
+		require.Equal(t, "2", response.Results[1].ConnectionID)
+		require.NotEmpty(t, response.NextCursor)
+
+		b.Reset()
+		cmdErr = cmd.QueryConnections(&b, bytes.NewBufferString(
+			fmt.Sprintf(`{"sort_by":"label","order":"desc","limit":2,"cursor":%q}`, response.NextCursor)))
+		require.NoError(t, cmdErr)
+
+		response = QueryConnectionsResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Len(t, response.Results, 1)
+		require.Equal(t, "1", response.Results[0].ConnectionID)
+		require.Empty(t, response.NextCursor)
 	})
 }
 
@@ -709,7 +742,7 @@ func TestCommand_AcceptExchangeRequest(t *testing.T) {
 				didexsvc.DIDExchange: didExSvc,
 				route.Coordination:   &mockroute.MockRouteSvc{},
 			},
-			LegacyKMSValue: &mockkms.CloseableKMS{CreateSigningKeyValue: "sample-key"}},
+			KMSValue: &mockkms.KeyManager{CrAndExportPubKeyValue: []byte("sample-key")}},
 			&mockwebhook.Notifier{
 				NotifyFunc: func(topic string, message []byte) error {
 					require.Equal(t, connectionsWebhookTopic, topic)
@@ -968,6 +1001,61 @@ func TestSendConnectionNotification(t *testing.T) {
 	})
 }
 
+func TestSendRecordNotification(t *testing.T) {
+	t.Run("send notification on connection record changes", func(t *testing.T) {
+		prov := mockProvider()
+		prov.StorageProviderValue = mem.NewProvider()
+		prov.TransientStorageProviderValue = mem.NewProvider()
+
+		notifications := make(chan []byte)
+
+		cmd, err := New(prov, &mockwebhook.Notifier{NotifyFunc: func(topic string, message []byte) error {
+			if topic == connectionRecordsWebhookTopic {
+				notifications <- message
+			}
+
+			return nil
+		}}, "", false)
+		require.NoError(t, err)
+
+		store, err := prov.TransientStorageProviderValue.OpenStore(connection.Namespace)
+		require.NoError(t, err)
+
+		go func() {
+			require.NoError(t, store.Put("conn_id1", []byte("{}")))
+		}()
+
+		var msg ConnectionRecordMsg
+
+		require.NoError(t, json.Unmarshal(<-notifications, &msg))
+		require.Equal(t, ConnectionRecordMsg{ConnectionID: "id1", Operation: "put"}, msg)
+
+		// no more notifications once closed
+		require.NoError(t, cmd.Close())
+		require.NoError(t, cmd.Close())
+		require.NoError(t, store.Put("conn_id2", []byte("{}")))
+
+		select {
+		case <-notifications:
+			require.Fail(t, "notification sent after close")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("send notification webhook error", func(t *testing.T) {
+		op, err := New(mockProvider(), &mockwebhook.Notifier{NotifyFunc: func(topic string, message []byte) error {
+			return errors.New("webhook error")
+		}}, "", false)
+		require.NoError(t, err)
+
+		err = op.sendRecordNotification(connection.RecordEvent{ConnectionID: "id1", Operation: storage.ChangeDelete})
+		require.EqualError(t, err, "connection record notification webhook : webhook error")
+
+		// the storage doesn't report changes, there are no notifications to stop
+		require.NoError(t, op.Close())
+	})
+}
+
 func mockProvider() *mockprovider.Provider {
 	return &mockprovider.Provider{
 		TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
@@ -976,11 +1064,27 @@ func mockProvider() *mockprovider.Provider {
 			didexsvc.DIDExchange: &mockdidexchange.MockDIDExchangeSvc{},
 			route.Coordination:   &mockroute.MockRouteSvc{},
 		},
-		LegacyKMSValue:       &mockkms.CloseableKMS{},
+		KMSValue:             &mockkms.KeyManager{},
 		ServiceEndpointValue: mockSvcEndpoint,
 	}
 }
 
+// saveConnectionRecords saves connection records in new memory stores of the provider.
+func saveConnectionRecords(t *testing.T, prov *mockprovider.Provider, records ...*connection.Record) {
+	t.Helper()
+
+	if _, ok := prov.StorageProviderValue.(*mem.Provider); !ok {
+		prov.StorageProviderValue, prov.TransientStorageProviderValue = mem.NewProvider(), mem.NewProvider()
+	}
+
+	recorder, err := connection.NewRecorder(prov)
+	require.NoError(t, err)
+
+	for _, record := range records {
+		require.NoError(t, recorder.SaveConnectionRecord(record))
+	}
+}
+
 func stateKey(connID, state string) string {
 	return fmt.Sprintf("connstate_%s_%s", connID, state)
 }
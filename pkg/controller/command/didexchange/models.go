@@ -208,6 +208,9 @@ type QueryConnectionResponse struct {
 //
 type QueryConnectionsResponse struct {
 	Results []*didexchange.Connection `json:"results,omitempty"`
+
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // AcceptExchangeRequestArgs model
@@ -317,3 +320,9 @@ type ConnectionMsg struct {
 	InvitationMode      string `json:"invitation_mode"`
 	Alias               string `json:"alias"`
 }
+
+// ConnectionRecordMsg is sent when a connection record is saved ("put") or removed ("delete").
+type ConnectionRecordMsg struct {
+	ConnectionID string `json:"connection_id"`
+	Operation    string `json:"operation"`
+}
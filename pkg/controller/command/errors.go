@@ -58,6 +58,9 @@ const (
 
 	// PresentProof error group for present proof command errors
 	PresentProof = 9000
+
+	// Wallet error group for wallet backup command errors
+	Wallet Group = 10000
 )
 
 // Error is the  interface for representing an command error condition, with the nil value representing no error.
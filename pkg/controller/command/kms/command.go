@@ -17,7 +17,6 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
 )
 
 var logger = log.New("aries-framework/command/kms")
@@ -28,6 +27,14 @@ const (
 	InvalidRequestErrorCode = command.Code(iota + command.VC)
 	// CreateKeySetError is for failures while creating key set
 	CreateKeySetError
+	// ListKeysError is for failures while listing keys
+	ListKeysError
+	// GetKeyMetadataError is for failures while getting key metadata
+	GetKeyMetadataError
+	// UpdateKeyMetadataError is for failures while updating key metadata
+	UpdateKeyMetadataError
+	// DeleteKeyError is for failures while deleting a key
+	DeleteKeyError
 )
 
 const (
@@ -35,10 +42,15 @@ const (
 	commandName = "kms"
 
 	// command methods
-	createKeySetCommandMethod = "CreateKeySet"
+	createKeySetCommandMethod      = "CreateKeySet"
+	listKeysCommandMethod          = "ListKeys"
+	getKeyMetadataCommandMethod    = "GetKeyMetadata"
+	updateKeyMetadataCommandMethod = "UpdateKeyMetadata"
+	deleteKeyCommandMethod         = "DeleteKey"
 
 	// error messages
 	errEmptyKeyType = "key type is mandatory"
+	errEmptyKeyID   = "key id is mandatory"
 )
 
 // provider contains dependencies for the kms command and is typically created by using aries.Context().
@@ -58,12 +70,7 @@ func New(p provider) *Command {
 	return &Command{
 		ctx: p,
 		exportPubKeyBytes: func(id string) ([]byte, error) {
-			k, ok := p.KMS().(*localkms.LocalKMS)
-			if !ok {
-				return nil, fmt.Errorf("kms is not LocalKMS type")
-			}
-
-			return k.ExportPubKeyBytes(id)
+			return p.KMS().ExportPubKeyBytes(id)
 		},
 	}
 }
@@ -72,6 +79,10 @@ func New(p provider) *Command {
 func (o *Command) GetHandlers() []command.Handler {
 	return []command.Handler{
 		cmdutil.NewCommandHandler(commandName, createKeySetCommandMethod, o.CreateKeySet),
+		cmdutil.NewCommandHandler(commandName, listKeysCommandMethod, o.ListKeys),
+		cmdutil.NewCommandHandler(commandName, getKeyMetadataCommandMethod, o.GetKeyMetadata),
+		cmdutil.NewCommandHandler(commandName, updateKeyMetadataCommandMethod, o.UpdateKeyMetadata),
+		cmdutil.NewCommandHandler(commandName, deleteKeyCommandMethod, o.DeleteKey),
 	}
 }
 
@@ -111,3 +122,113 @@ func (o *Command) CreateKeySet(rw io.Writer, req io.Reader) command.Error {
 
 	return nil
 }
+
+// ListKeys lists the metadata of the keys of the KMS.
+func (o *Command) ListKeys(rw io.Writer, req io.Reader) command.Error {
+	keys, err := o.ctx.KMS().List()
+	if err != nil {
+		logutil.LogError(logger, commandName, listKeysCommandMethod, err.Error())
+		return command.NewExecuteError(ListKeysError, err)
+	}
+
+	command.WriteNillableResponse(rw, &ListKeysResponse{Keys: keys}, logger)
+
+	logutil.LogDebug(logger, commandName, listKeysCommandMethod, "success")
+
+	return nil
+}
+
+// GetKeyMetadata returns the metadata of a key: its type, creation time, purpose, DID and rotation lineage.
+func (o *Command) GetKeyMetadata(rw io.Writer, req io.Reader) command.Error {
+	var request KeyIDArgs
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, commandName, getKeyMetadataCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	if request.KeyID == "" {
+		logutil.LogDebug(logger, commandName, getKeyMetadataCommandMethod, errEmptyKeyID)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyKeyID))
+	}
+
+	md, err := o.ctx.KMS().GetMetadata(request.KeyID)
+	if err != nil {
+		logutil.LogError(logger, commandName, getKeyMetadataCommandMethod, err.Error())
+		return command.NewExecuteError(GetKeyMetadataError, err)
+	}
+
+	command.WriteNillableResponse(rw, &KeyMetadataResponse{KeyMetadata: md}, logger)
+
+	logutil.LogDebug(logger, commandName, getKeyMetadataCommandMethod, "success")
+
+	return nil
+}
+
+// UpdateKeyMetadata records what a key is used for: its purpose and the DID verification method it belongs to.
+// Empty fields of the request are left unchanged.
+func (o *Command) UpdateKeyMetadata(rw io.Writer, req io.Reader) command.Error {
+	var request UpdateKeyMetadataRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, commandName, updateKeyMetadataCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	if request.KeyID == "" {
+		logutil.LogDebug(logger, commandName, updateKeyMetadataCommandMethod, errEmptyKeyID)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyKeyID))
+	}
+
+	var opts []kms.MetadataOpts
+
+	if request.Purpose != "" {
+		opts = append(opts, kms.WithPurpose(request.Purpose))
+	}
+
+	if request.DID != "" || request.VerificationMethod != "" {
+		opts = append(opts, kms.WithDID(request.DID, request.VerificationMethod))
+	}
+
+	err = o.ctx.KMS().UpdateMetadata(request.KeyID, opts...)
+	if err != nil {
+		logutil.LogError(logger, commandName, updateKeyMetadataCommandMethod, err.Error())
+		return command.NewExecuteError(UpdateKeyMetadataError, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, commandName, updateKeyMetadataCommandMethod, "success")
+
+	return nil
+}
+
+// DeleteKey deletes a key from the KMS, e.g. when it is compromised.
+func (o *Command) DeleteKey(rw io.Writer, req io.Reader) command.Error {
+	var request KeyIDArgs
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, commandName, deleteKeyCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	if request.KeyID == "" {
+		logutil.LogDebug(logger, commandName, deleteKeyCommandMethod, errEmptyKeyID)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyKeyID))
+	}
+
+	err = o.ctx.KMS().Delete(request.KeyID)
+	if err != nil {
+		logutil.LogError(logger, commandName, deleteKeyCommandMethod, err.Error())
+		return command.NewExecuteError(DeleteKeyError, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, commandName, deleteKeyCommandMethod, "success")
+
+	return nil
+}
@@ -12,10 +12,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 )
 
@@ -27,18 +29,18 @@ func TestNew(t *testing.T) {
 		require.NotNil(t, cmd)
 
 		handlers := cmd.GetHandlers()
-		require.Equal(t, 1, len(handlers))
+		require.Equal(t, 5, len(handlers))
 	})
 
 	t.Run("test new command - error from export public key", func(t *testing.T) {
 		cmd := New(&mockprovider.Provider{
-			KMSValue: &mockkms.KeyManager{},
+			KMSValue: &mockkms.KeyManager{ExportPubKeyErr: fmt.Errorf("export error")},
 		})
 		require.NotNil(t, cmd)
 
 		_, err := cmd.exportPubKeyBytes("id")
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "kms is not LocalKMS type")
+		require.Contains(t, err.Error(), "export error")
 	})
 }
 
@@ -132,3 +134,190 @@ func TestCreateKeySet(t *testing.T) {
 		require.Contains(t, err.Error(), "error export public key")
 	})
 }
+
+func TestListKeys(t *testing.T) {
+	t.Run("test list keys - success", func(t *testing.T) {
+		keys := []*kms.KeyMetadata{
+			{ID: "key1", Type: kms.ED25519Type, Created: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: "key2", Type: kms.AES256GCMType, Purpose: "keyAgreement", PreviousKeyIDs: []string{"key0"}},
+		}
+
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{ListValue: keys},
+		})
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.ListKeys(&b, bytes.NewBuffer(nil)))
+
+		response := ListKeysResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Equal(t, keys, response.Keys)
+	})
+
+	t.Run("test list keys - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{ListErr: fmt.Errorf("list error")},
+		})
+
+		var b bytes.Buffer
+		err := cmd.ListKeys(&b, bytes.NewBuffer(nil))
+		require.Error(t, err)
+		require.Equal(t, ListKeysError, err.Code())
+		require.Contains(t, err.Error(), "list error")
+	})
+}
+
+func TestGetKeyMetadata(t *testing.T) {
+	t.Run("test get key metadata - success", func(t *testing.T) {
+		md := &kms.KeyMetadata{ID: "key1", Type: kms.ED25519Type, DID: "did:example:123"}
+
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{MetadataValue: md},
+		})
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.GetKeyMetadata(&b, bytes.NewBufferString(`{"keyID":"key1"}`)))
+
+		response := KeyMetadataResponse{}
+		require.NoError(t, json.NewDecoder(&b).Decode(&response))
+		require.Equal(t, md, response.KeyMetadata)
+	})
+
+	t.Run("test get key metadata - validation errors", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+
+		var b bytes.Buffer
+		err := cmd.GetKeyMetadata(&b, bytes.NewBuffer(nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed request decode")
+
+		err = cmd.GetKeyMetadata(&b, bytes.NewBufferString(`{}`))
+		require.Error(t, err)
+		require.Equal(t, InvalidRequestErrorCode, err.Code())
+		require.Contains(t, err.Error(), errEmptyKeyID)
+	})
+
+	t.Run("test get key metadata - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{MetadataErr: kms.ErrKeyNotFound},
+		})
+
+		var b bytes.Buffer
+		err := cmd.GetKeyMetadata(&b, bytes.NewBufferString(`{"keyID":"key1"}`))
+		require.Error(t, err)
+		require.Equal(t, GetKeyMetadataError, err.Code())
+		require.Contains(t, err.Error(), kms.ErrKeyNotFound.Error())
+	})
+}
+
+func TestUpdateKeyMetadata(t *testing.T) {
+	t.Run("test update key metadata - success", func(t *testing.T) {
+		km := &recordingKeyManager{}
+		cmd := New(&mockprovider.Provider{KMSValue: km})
+
+		reqBytes, err := json.Marshal(UpdateKeyMetadataRequest{
+			KeyID:              "key1",
+			Purpose:            "authentication",
+			DID:                "did:example:123",
+			VerificationMethod: "did:example:123#key-1",
+		})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.UpdateKeyMetadata(&b, bytes.NewBuffer(reqBytes)))
+		require.Equal(t, "key1", km.keyID)
+		require.Equal(t, &kms.KeyMetadata{
+			Purpose:            "authentication",
+			DID:                "did:example:123",
+			VerificationMethod: "did:example:123#key-1",
+		}, km.metadata)
+
+		// empty fields are left unchanged
+		km.metadata = &kms.KeyMetadata{Purpose: "authentication", DID: "did:example:123"}
+
+		require.NoError(t, cmd.UpdateKeyMetadata(&b, bytes.NewBufferString(`{"keyID":"key1","purpose":"keyAgreement"}`)))
+		require.Equal(t, &kms.KeyMetadata{Purpose: "keyAgreement", DID: "did:example:123"}, km.metadata)
+	})
+
+	t.Run("test update key metadata - validation errors", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+
+		var b bytes.Buffer
+		err := cmd.UpdateKeyMetadata(&b, bytes.NewBuffer(nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed request decode")
+
+		err = cmd.UpdateKeyMetadata(&b, bytes.NewBufferString(`{"purpose":"authentication"}`))
+		require.Error(t, err)
+		require.Equal(t, InvalidRequestErrorCode, err.Code())
+		require.Contains(t, err.Error(), errEmptyKeyID)
+	})
+
+	t.Run("test update key metadata - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{UpdateErr: fmt.Errorf("update error")},
+		})
+
+		var b bytes.Buffer
+		err := cmd.UpdateKeyMetadata(&b, bytes.NewBufferString(`{"keyID":"key1"}`))
+		require.Error(t, err)
+		require.Equal(t, UpdateKeyMetadataError, err.Code())
+		require.Contains(t, err.Error(), "update error")
+	})
+}
+
+func TestDeleteKey(t *testing.T) {
+	t.Run("test delete key - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+
+		var b bytes.Buffer
+		require.NoError(t, cmd.DeleteKey(&b, bytes.NewBufferString(`{"keyID":"key1"}`)))
+	})
+
+	t.Run("test delete key - validation errors", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}})
+
+		var b bytes.Buffer
+		err := cmd.DeleteKey(&b, bytes.NewBuffer(nil))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed request decode")
+
+		err = cmd.DeleteKey(&b, bytes.NewBufferString(`{}`))
+		require.Error(t, err)
+		require.Equal(t, InvalidRequestErrorCode, err.Code())
+		require.Contains(t, err.Error(), errEmptyKeyID)
+	})
+
+	t.Run("test delete key - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{DeleteErr: fmt.Errorf("delete error")},
+		})
+
+		var b bytes.Buffer
+		err := cmd.DeleteKey(&b, bytes.NewBufferString(`{"keyID":"key1"}`))
+		require.Error(t, err)
+		require.Equal(t, DeleteKeyError, err.Code())
+		require.Contains(t, err.Error(), "delete error")
+	})
+}
+
+// recordingKeyManager applies the metadata options of UpdateMetadata to its metadata.
+type recordingKeyManager struct {
+	mockkms.KeyManager
+	keyID    string
+	metadata *kms.KeyMetadata
+}
+
+func (k *recordingKeyManager) UpdateMetadata(keyID string, opts ...kms.MetadataOpts) error {
+	k.keyID = keyID
+
+	if k.metadata == nil {
+		k.metadata = &kms.KeyMetadata{}
+	}
+
+	for _, opt := range opts {
+		opt(k.metadata)
+	}
+
+	return nil
+}
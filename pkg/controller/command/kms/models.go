@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 
 package kms
 
+import "github.com/hyperledger/aries-framework-go/pkg/kms"
+
 // CreateKeySetRequest is model for createKeySey request.
 type CreateKeySetRequest struct {
 	KeyType string `json:"keyType,omitempty"`
@@ -18,3 +20,36 @@ type CreateKeySetResponse struct {
 	//  public key base64 encoded
 	PublicKey string `json:"publicKey,omitempty"`
 }
+
+// KeyIDArgs model
+//
+// This is used to reference a key of the KMS.
+type KeyIDArgs struct {
+	KeyID string `json:"keyID"`
+}
+
+// ListKeysResponse model
+//
+// This is used for returning the metadata of the keys of the KMS.
+type ListKeysResponse struct {
+	Keys []*kms.KeyMetadata `json:"keys"`
+}
+
+// KeyMetadataResponse model
+//
+// This is used for returning the metadata of a key.
+type KeyMetadataResponse struct {
+	*kms.KeyMetadata
+}
+
+// UpdateKeyMetadataRequest model
+//
+// This is used for recording what a key is used for.
+type UpdateKeyMetadataRequest struct {
+	KeyID string `json:"keyID"`
+	// Purpose of the key, e.g. "authentication"
+	Purpose string `json:"purpose,omitempty"`
+	// DID and verification method the key belongs to
+	DID                string `json:"did,omitempty"`
+	VerificationMethod string `json:"verificationMethod,omitempty"`
+}
@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/btcsuite/btcutil/base58"
+
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
@@ -18,7 +20,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messaging/service/http"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
@@ -86,7 +88,7 @@ type provider interface {
 	Messenger() service.Messenger
 	TransientStorageProvider() storage.Provider
 	StorageProvider() storage.Provider
-	LegacyKMS() legacykms.KeyManager
+	KMS() kms.KeyManager
 }
 
 // Command contains basic command operations provided by messaging controller command
@@ -396,7 +398,7 @@ func (o *Command) sendToDestination(rqst *SendNewMessageArgs) command.Error {
 		return command.NewExecuteError(SendMsgError, fmt.Errorf(errMsgDestinationMissing))
 	}
 
-	_, sigPubKey, err := o.ctx.LegacyKMS().CreateKeySet()
+	_, sigPubKey, err := o.ctx.KMS().CreateAndExportPubKeyBytes(kms.ED25519Type)
 	if err != nil {
 		logutil.LogError(logger, commandName, sendNewMessageCommandMethod, err.Error(),
 			logutil.CreateKeyValueString(destinationString, dest.ServiceEndpoint))
@@ -411,7 +413,7 @@ func (o *Command) sendToDestination(rqst *SendNewMessageArgs) command.Error {
 		return command.NewExecuteError(SendMsgError, err)
 	}
 
-	err = o.ctx.Messenger().SendToDestination(didcommMsg, sigPubKey, dest)
+	err = o.ctx.Messenger().SendToDestination(didcommMsg, base58.Encode(sigPubKey), dest)
 	if err != nil {
 		logutil.LogError(logger, commandName, sendNewMessageCommandMethod, err.Error(),
 			logutil.CreateKeyValueString(destinationString, dest.ServiceEndpoint))
@@ -25,7 +25,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/generic"
 	mocksvc "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/service"
 	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
@@ -471,7 +471,7 @@ func TestCommand_Send(t *testing.T) {
 			name           string
 			testConnection *connection.Record
 			messenger      *mocksvc.MockMessenger
-			kms            *mockkms.CloseableKMS
+			kms            *mockkms.KeyManager
 			vdri           *mockvdri.MockVDRIRegistry
 			requestJSON    string
 			errorCode      command.Code
@@ -513,7 +513,7 @@ func TestCommand_Send(t *testing.T) {
 				name: "send message to destination",
 				requestJSON: `{"message_body": {"text":"sample"},"service_endpoint": {"serviceEndpoint": "sdfsdf",
 	"recipientKeys":["test"]}}`,
-				kms:       &mockkms.CloseableKMS{CreateKeyErr: fmt.Errorf("sample-kmserr-01")},
+				kms:       &mockkms.KeyManager{CrAndExportPubKeyErr: fmt.Errorf("sample-kmserr-01")},
 				errorCode: SendMsgError,
 				errorMsg:  "sample-kmserr-01",
 			},
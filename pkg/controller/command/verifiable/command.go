@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	"github.com/btcsuite/btcutil/base58"
 
@@ -80,6 +81,13 @@ const (
 	// command name
 	commandName = "verifiable"
 
+	// webhook notifier topics
+	credentialsWebhookTopic   = "credentials"
+	presentationsWebhookTopic = "presentations"
+
+	// number of record notifications sent concurrently
+	maxPendingRecordNotifications = 16
+
 	// command methods
 	validateCredentialCommandMethod       = "ValidateCredential"
 	saveCredentialCommandMethod           = "SaveCredential"
@@ -91,6 +99,7 @@ const (
 	getPresentationsCommandMethod         = "GetPresentations"
 	generatePresentationCommandMethod     = "GeneratePresentation"
 	generatePresentationByIDCommandMethod = "GeneratePresentationByID"
+	sendRecordNotification                = "sendRecordNotification"
 
 	// error messages
 	errEmptyCredentialName   = "credential name is mandatory"
@@ -98,6 +107,7 @@ const (
 	errEmptyCredentialID     = "credential id is mandatory"
 	errEmptyPresentationID   = "presentation id is mandatory"
 	errEmptyDID              = "did is mandatory"
+	errNegativeLimit         = "limit can't be negative"
 
 	// log constants
 	vcID   = "vcID"
@@ -106,6 +116,10 @@ const (
 
 	creatorParts = 2
 
+	// sort orders of queried records
+	sortAscending  = "asc"
+	sortDescending = "desc"
+
 	// Ed25519Signature2018 ed25519 signature suite
 	Ed25519Signature2018 = "Ed25519Signature2018"
 	// JSONWebSignature2020 json web signature suite
@@ -198,10 +212,14 @@ type Command struct {
 	didStore        *didstore.Store
 	kResolver       keyResolver
 	ctx             provider
+	notifier        command.Notifier
+	// stops the notifications of the credential and presentation record changes
+	stopRecordEvents func() error
 }
 
-// New returns new verifiable credential controller command instance.
-func New(p provider) (*Command, error) {
+// New returns new verifiable credential controller command instance. The notifier is sent the changes of the
+// named credentials and presentations, provided the storage reports them.
+func New(p provider, notifier command.Notifier) (*Command, error) {
 	verifiableStore, err := verifiablestore.New(p)
 	if err != nil {
 		return nil, fmt.Errorf("new vc store : %w", err)
@@ -212,14 +230,109 @@ func New(p provider) (*Command, error) {
 		return nil, fmt.Errorf("new did store : %w", err)
 	}
 
-	return &Command{
+	cmd := &Command{
 		verifiableStore: verifiableStore,
 		didStore:        didStore,
 		kResolver:       verifiable.NewDIDKeyResolver(p.VDRIRegistry()),
 		ctx:             p,
+		notifier:        notifier,
+	}
+
+	cmd.stopRecordEvents, err = cmd.startRecordEventListener()
+	if err != nil {
+		return nil, fmt.Errorf("record event listener startup failed: %w", err)
+	}
+
+	return cmd, nil
+}
+
+// Close stops the notifications of the credential and presentation record changes, on the shutdown of the agent.
+func (o *Command) Close() error {
+	return o.stopRecordEvents()
+}
+
+// startRecordEventListener notifies the changes of credential and presentation records.
+// The returned func stops the notifications.
+func (o *Command) startRecordEventListener() (func() error, error) {
+	events := make(chan verifiablestore.RecordEvent)
+
+	err := o.verifiableStore.RegisterRecordEvent(events)
+	if errors.Is(err, storage.ErrChangeFeedNotSupported) {
+		logger.Debugf("verifiable record notifications disabled: %s", err)
+
+		return func() error { return nil }, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("verifiable record event registration failed: %w", err)
+	}
+
+	stop := make(chan struct{})
+
+	// webhooks are notified asynchronously so that a slow webhook doesn't hold up the record events
+	go func() {
+		pending := make(chan struct{}, maxPendingRecordNotifications)
+
+		for {
+			select {
+			case e := <-events:
+				pending <- struct{}{}
+
+				go func(e verifiablestore.RecordEvent) {
+					defer func() { <-pending }()
+
+					err := o.sendRecordNotification(e)
+					if err != nil {
+						logger.Errorf("handle record events failed : %s", err)
+					}
+				}(e)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var (
+		once          sync.Once
+		unregisterErr error
+	)
+
+	return func() error {
+		once.Do(func() {
+			unregisterErr = o.verifiableStore.UnregisterRecordEvent(events)
+
+			close(stop)
+		})
+
+		return unregisterErr
 	}, nil
 }
 
+func (o *Command) sendRecordNotification(e verifiablestore.RecordEvent) error {
+	topic := credentialsWebhookTopic
+	if e.Kind == verifiablestore.PresentationRecord {
+		topic = presentationsWebhookTopic
+	}
+
+	jsonMessage, err := json.Marshal(&RecordMsg{Name: e.Name, Operation: string(e.Operation)})
+	if err != nil {
+		return fmt.Errorf("verifiable record notification json marshal : %w", err)
+	}
+
+	err = o.notifier.Notify(topic, jsonMessage)
+	if err != nil {
+		logutil.LogError(logger, commandName, sendRecordNotification, err.Error(),
+			logutil.CreateKeyValueString(vcName, e.Name))
+
+		return fmt.Errorf("verifiable record notification webhook : %w", err)
+	}
+
+	logutil.LogDebug(logger, commandName, sendRecordNotification, "success",
+		logutil.CreateKeyValueString(vcName, e.Name))
+
+	return nil
+}
+
 // GetHandlers returns list of all commands supported by this controller command.
 func (o *Command) GetHandlers() []command.Handler {
 	return []command.Handler{
@@ -456,8 +569,27 @@ func (o *Command) GetCredentialByName(rw io.Writer, req io.Reader) command.Error
 }
 
 // GetCredentials retrieves the verifiable credential records containing name and fields of interest.
+// The optional request narrows the records down and pages them, refer QueryCredentialsArgs.
 func (o *Command) GetCredentials(rw io.Writer, req io.Reader) command.Error {
-	vcRecords, err := o.verifiableStore.GetCredentials()
+	var request QueryCredentialsArgs
+
+	if req != nil {
+		err := json.NewDecoder(req).Decode(&request)
+		if err != nil && !errors.Is(err, io.EOF) {
+			logutil.LogInfo(logger, commandName, getCredentialsCommandMethod, "request decode : "+err.Error())
+
+			return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("request decode : %w", err))
+		}
+	}
+
+	query, err := credentialsQuery(&request)
+	if err != nil {
+		logutil.LogDebug(logger, commandName, getCredentialsCommandMethod, err.Error())
+
+		return command.NewValidationError(InvalidRequestErrorCode, err)
+	}
+
+	page, err := o.verifiableStore.QueryCredentials(query)
 	if err != nil {
 		logutil.LogError(logger, commandName, getCredentialsCommandMethod, "get credential records : "+err.Error())
 
@@ -465,7 +597,8 @@ func (o *Command) GetCredentials(rw io.Writer, req io.Reader) command.Error {
 	}
 
 	command.WriteNillableResponse(rw, &RecordResult{
-		Result: vcRecords,
+		Result:     page.Records,
+		NextCursor: page.NextCursor,
 	}, logger)
 
 	logutil.LogDebug(logger, commandName, getCredentialsCommandMethod, "success")
@@ -473,6 +606,32 @@ func (o *Command) GetCredentials(rw io.Writer, req io.Reader) command.Error {
 	return nil
 }
 
+// credentialsQuery returns the store query of the given arguments.
+func credentialsQuery(a *QueryCredentialsArgs) (*verifiablestore.Query, error) {
+	if a.Limit < 0 {
+		return nil, errors.New(errNegativeLimit)
+	}
+
+	var descending bool
+
+	switch a.Order {
+	case "", sortAscending:
+	case sortDescending:
+		descending = true
+	default:
+		return nil, fmt.Errorf("invalid order : %s", a.Order)
+	}
+
+	return &verifiablestore.Query{
+		Type:       a.Type,
+		SubjectID:  a.SubjectID,
+		SortBy:     verifiablestore.SortField(a.SortBy),
+		Descending: descending,
+		Cursor:     a.Cursor,
+		Limit:      a.Limit,
+	}, nil
+}
+
 // GetPresentations retrieves the verifiable presentation records containing name and fields of interest.
 func (o *Command) GetPresentations(rw io.Writer, req io.Reader) command.Error {
 	vpRecords, err := o.verifiableStore.GetPresentations()
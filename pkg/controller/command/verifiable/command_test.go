@@ -23,6 +23,8 @@ import (
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 
+	mockwebhook "github.com/hyperledger/aries-framework-go/pkg/controller/internal/mocks/webhook"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
@@ -31,6 +33,8 @@ import (
 	kmsmock "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
 	verifiablestore "github.com/hyperledger/aries-framework-go/pkg/store/verifiable"
 )
 
@@ -314,7 +318,7 @@ func TestNew(t *testing.T) {
 	t.Run("test new command - success", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -327,7 +331,7 @@ func TestNew(t *testing.T) {
 			StorageProviderValue: &mockstore.MockStoreProvider{
 				ErrOpenStoreHandle: fmt.Errorf("error opening the store"),
 			},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "new vc store")
@@ -335,11 +339,64 @@ func TestNew(t *testing.T) {
 	})
 }
 
+func TestSendRecordNotification(t *testing.T) {
+	t.Run("test send notification - saved credential", func(t *testing.T) {
+		notifications := make(chan []byte)
+
+		cmd, err := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()},
+			&mockwebhook.Notifier{NotifyFunc: func(topic string, message []byte) error {
+				require.Equal(t, credentialsWebhookTopic, topic)
+				notifications <- message
+
+				return nil
+			}})
+		require.NoError(t, err)
+
+		go func() {
+			require.NoError(t, cmd.verifiableStore.SaveCredential("vc1", &verifiable.Credential{ID: "http://vc1"}))
+		}()
+
+		var msg RecordMsg
+
+		require.NoError(t, json.Unmarshal(<-notifications, &msg))
+		require.Equal(t, RecordMsg{Name: "vc1", Operation: "put"}, msg)
+
+		// no more notifications once closed
+		require.NoError(t, cmd.Close())
+		require.NoError(t, cmd.Close())
+		require.NoError(t, cmd.verifiableStore.SaveCredential("vc2", &verifiable.Credential{ID: "http://vc2"}))
+
+		select {
+		case <-notifications:
+			require.Fail(t, "notification sent after close")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("test send notification - webhook error", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{StorageProviderValue: mockstore.NewMockStoreProvider()},
+			&mockwebhook.Notifier{NotifyFunc: func(topic string, message []byte) error {
+				require.Equal(t, presentationsWebhookTopic, topic)
+
+				return errors.New("webhook error")
+			}})
+		require.NoError(t, err)
+
+		err = cmd.sendRecordNotification(verifiablestore.RecordEvent{
+			Kind: verifiablestore.PresentationRecord, Name: "vp1", Operation: storage.ChangePut,
+		})
+		require.EqualError(t, err, "verifiable record notification webhook : webhook error")
+
+		// the storage doesn't report changes, there are no notifications to stop
+		require.NoError(t, cmd.Close())
+	})
+}
+
 func TestValidateVC(t *testing.T) {
 	t.Run("test register - success", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -355,7 +412,7 @@ func TestValidateVC(t *testing.T) {
 	t.Run("test register - invalid request", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -369,7 +426,7 @@ func TestValidateVC(t *testing.T) {
 	t.Run("test register - validation error", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -389,7 +446,7 @@ func TestSaveVC(t *testing.T) {
 	t.Run("test save vc - success", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -408,7 +465,7 @@ func TestSaveVC(t *testing.T) {
 	t.Run("test save vc - invalid request", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -422,7 +479,7 @@ func TestSaveVC(t *testing.T) {
 	t.Run("test save vc - validation error", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -447,7 +504,7 @@ func TestSaveVC(t *testing.T) {
 					ErrPut: fmt.Errorf("put error"),
 				},
 			},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -473,7 +530,7 @@ func TestGetVC(t *testing.T) {
 
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -495,7 +552,7 @@ func TestGetVC(t *testing.T) {
 	t.Run("test get vc - invalid request", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -508,7 +565,7 @@ func TestGetVC(t *testing.T) {
 	t.Run("test get vc - no id in the request", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -527,7 +584,7 @@ func TestGetVC(t *testing.T) {
 					ErrGet: fmt.Errorf("get error"),
 				},
 			},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -544,7 +601,7 @@ func TestGetCredentialByName(t *testing.T) {
 	t.Run("test get vc by name - success", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -579,7 +636,7 @@ func TestGetCredentialByName(t *testing.T) {
 	t.Run("test get vc - invalid request", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -592,7 +649,7 @@ func TestGetCredentialByName(t *testing.T) {
 	t.Run("test get vc - no name in the request", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -611,7 +668,7 @@ func TestGetCredentialByName(t *testing.T) {
 					ErrGet: fmt.Errorf("get error"),
 				},
 			},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -628,7 +685,7 @@ func TestGetCredentials(t *testing.T) {
 	t.Run("test get credentials", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -658,6 +715,70 @@ func TestGetCredentials(t *testing.T) {
 		require.Len(t, response.Result[0].Context, 1)
 		require.Len(t, response.Result[0].Type, 1)
 	})
+
+	t.Run("test get credentials - pages", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{
+			StorageProviderValue: mem.NewProvider(),
+		}, webnotifier.NewHTTPNotifier(nil))
+		require.NoError(t, err)
+
+		for _, name := range []string{"vc1", "vc2", "vc3"} {
+			require.NoError(t, cmd.verifiableStore.SaveCredential(name, &verifiable.Credential{
+				ID:    "http://example.edu/credentials/" + name,
+				Types: []string{"VerifiableCredential"},
+			}))
+		}
+
+		var getRW bytes.Buffer
+		cmdErr := cmd.GetCredentials(&getRW, bytes.NewBufferString(`{"sortBy":"name","order":"desc","limit":2}`))
+		require.NoError(t, cmdErr)
+
+		var response RecordResult
+		require.NoError(t, json.NewDecoder(&getRW).Decode(&response))
+		require.Len(t, response.Result, 2)
+		require.Equal(t, "vc3", response.Result[0].Name)
+		require.Equal(t, "vc2", response.Result[1].Name)
+		require.NotEmpty(t, response.NextCursor)
+
+		getRW.Reset()
+		cmdErr = cmd.GetCredentials(&getRW, bytes.NewBufferString(
+			fmt.Sprintf(`{"sortBy":"name","order":"desc","limit":2,"cursor":%q}`, response.NextCursor)))
+		require.NoError(t, cmdErr)
+
+		response = RecordResult{}
+		require.NoError(t, json.NewDecoder(&getRW).Decode(&response))
+		require.Len(t, response.Result, 1)
+		require.Equal(t, "vc1", response.Result[0].Name)
+		require.Empty(t, response.NextCursor)
+
+		getRW.Reset()
+		cmdErr = cmd.GetCredentials(&getRW, bytes.NewBufferString(""))
+		require.NoError(t, cmdErr)
+	})
+
+	t.Run("test get credentials - invalid request", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{
+			StorageProviderValue: mockstore.NewMockStoreProvider(),
+		}, webnotifier.NewHTTPNotifier(nil))
+		require.NoError(t, err)
+
+		var getRW bytes.Buffer
+		cmdErr := cmd.GetCredentials(&getRW, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.GetCredentials(&getRW, bytes.NewBufferString(`{"limit":-1}`))
+		require.EqualError(t, cmdErr, errNegativeLimit)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.GetCredentials(&getRW, bytes.NewBufferString(`{"order":"up"}`))
+		require.EqualError(t, cmdErr, "invalid order : up")
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+		cmdErr = cmd.GetCredentials(&getRW, bytes.NewBufferString(`{"sortBy":"type"}`))
+		require.Error(t, cmdErr)
+		require.Equal(t, GetCredentialsErrorCode, cmdErr.Code())
+	})
 }
 
 func TestGeneratePresentation(t *testing.T) {
@@ -687,7 +808,7 @@ func TestGeneratePresentation(t *testing.T) {
 		},
 		KMSValue:    &kmsmock.KeyManager{},
 		CryptoValue: &cryptomock.Crypto{},
-	})
+	}, webnotifier.NewHTTPNotifier(nil))
 
 	require.NotNil(t, cmd)
 	require.NoError(t, cmdErr)
@@ -1132,7 +1253,7 @@ func TestGeneratePresentationByID(t *testing.T) {
 		},
 		KMSValue:    &kmsmock.KeyManager{},
 		CryptoValue: &cryptomock.Crypto{},
-	})
+	}, webnotifier.NewHTTPNotifier(nil))
 	require.NotNil(t, cmd)
 	require.NoError(t, cmdErr)
 
@@ -1206,7 +1327,7 @@ func TestGeneratePresentationByID(t *testing.T) {
 					ErrGet: fmt.Errorf("get error"),
 				},
 			},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
@@ -1238,7 +1359,7 @@ func TestGeneratePresentationHelperFunctions(t *testing.T) {
 			},
 		},
 		KMSValue: &kmsmock.KeyManager{},
-	})
+	}, webnotifier.NewHTTPNotifier(nil))
 	require.NotNil(t, cmd)
 	require.NoError(t, cmdErr)
 
@@ -1371,7 +1492,7 @@ func TestGeneratePresentationHelperFunctions(t *testing.T) {
 			},
 			KMSValue:    &kmsmock.KeyManager{},
 			CryptoValue: &cryptomock.Crypto{SignErr: errors.New("invalid signer")},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 
 		cred := &verifiable.Credential{}
 		err := json.Unmarshal([]byte(vc), cred)
@@ -1394,7 +1515,7 @@ func TestSaveVP(t *testing.T) {
 	t.Run("test save vp - success", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -1437,7 +1558,7 @@ func TestSaveVP(t *testing.T) {
 	t.Run("test save vp - invalid request", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -1451,7 +1572,7 @@ func TestSaveVP(t *testing.T) {
 	t.Run("test save vp - validation error", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -1489,7 +1610,7 @@ func TestSaveVP(t *testing.T) {
 					ErrPut: fmt.Errorf("put error"),
 				},
 			},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -1515,7 +1636,7 @@ func TestGetVP(t *testing.T) {
 
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -1549,7 +1670,7 @@ func TestGetVP(t *testing.T) {
 	t.Run("test get vp - invalid request", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -1562,7 +1683,7 @@ func TestGetVP(t *testing.T) {
 	t.Run("test get vp - no id in the request", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -1581,7 +1702,7 @@ func TestGetVP(t *testing.T) {
 					ErrGet: fmt.Errorf("get error"),
 				},
 			},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -1598,7 +1719,7 @@ func TestGetPresentations(t *testing.T) {
 	t.Run("test get credentials", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NotNil(t, cmd)
 		require.NoError(t, err)
 
@@ -98,9 +98,39 @@ type NameArg struct {
 type RecordResult struct {
 	// Result
 	Result []*verifiable.Record `json:"result,omitempty"`
+
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// QueryCredentialsArgs is model for querying credential records, every field is optional.
+type QueryCredentialsArgs struct {
+	// Type of the credentials
+	Type string `json:"type,omitempty"`
+
+	// SubjectID is the ID of the subject of the credentials
+	SubjectID string `json:"subjectId,omitempty"`
+
+	// SortBy is the field records are sorted by: created (default) or name
+	SortBy string `json:"sortBy,omitempty"`
+
+	// Order of the sort: asc (default) or desc
+	Order string `json:"order,omitempty"`
+
+	// Cursor is the next cursor of the previous page, empty for the first page
+	Cursor string `json:"cursor,omitempty"`
+
+	// Limit is the maximum number of records returned, zero for no limit
+	Limit int `json:"limit,omitempty"`
 }
 
 // Presentation is model for verifiable presentation.
 type Presentation struct {
 	VerifiablePresentation json.RawMessage `json:"verifiablePresentation,omitempty"`
 }
+
+// RecordMsg is sent when a named credential or presentation is saved ("put") or removed ("delete").
+type RecordMsg struct {
+	Name      string `json:"name"`
+	Operation string `json:"operation"`
+}
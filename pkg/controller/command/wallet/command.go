@@ -0,0 +1,215 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/introduce"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/presentproof"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/backup"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/pkg/store/did"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
+	"github.com/hyperledger/aries-framework-go/pkg/store/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/vdri/peer"
+)
+
+var logger = log.New("aries-framework/command/wallet")
+
+// Error codes
+const (
+	// InvalidRequestErrorCode is typically a code for invalid requests
+	InvalidRequestErrorCode = command.Code(iota + command.Wallet)
+	// ExportError is for failures while exporting the wallet
+	ExportError
+	// ImportError is for failures while importing the wallet
+	ImportError
+)
+
+const (
+	// command name
+	commandName = "wallet"
+
+	// command methods
+	exportCommandMethod = "Export"
+	importCommandMethod = "Import"
+
+	// error messages
+	errEmptyPassphrase = "passphrase is mandatory"
+	errEmptyArchive    = "archive is mandatory"
+)
+
+// storeNames are the permanent stores of the framework making up the agent wallet: keys, DIDs,
+// connections, credentials and protocol state. Transient stores aren't part of it.
+// nolint: gochecknoglobals
+var storeNames = []string{
+	localkms.Namespace,
+	localkms.MetadataNamespace,
+	legacykms.KeyStoreNamespace,
+	peer.StoreNamespace,
+	did.NameSpace,
+	did.StoreName,
+	connection.Namespace,
+	verifiable.NameSpace,
+	route.Coordination,
+	introduce.Introduce,
+	issuecredential.Name,
+	presentproof.Name,
+	migration.StoreName,
+}
+
+// provider contains dependencies for the wallet command and is typically created by using aries.Context().
+type provider interface {
+	StorageProvider() storage.Provider
+}
+
+// Command contains command operations provided by wallet controller.
+type Command struct {
+	ctx provider
+}
+
+// New returns new wallet command instance.
+func New(p provider) *Command {
+	return &Command{ctx: p}
+}
+
+// GetHandlers returns list of all commands supported by this controller command.
+func (o *Command) GetHandlers() []command.Handler {
+	return []command.Handler{
+		cmdutil.NewCommandHandler(commandName, exportCommandMethod, o.Export),
+		cmdutil.NewCommandHandler(commandName, importCommandMethod, o.Import),
+	}
+}
+
+// Export writes every store of the wallet into a single archive encrypted with the given passphrase.
+// Keys stay encrypted with the secret lock of the agent: the archive is only usable by an agent running with the
+// same secret lock, e.g. the same master key file and passphrase, which aren't part of the archive.
+// The archive is streamed to rw, base64 encoded in the archive of an ExportResponse, as the stores are read, rather
+// than being held in memory. A failure once the archive has started leaves rw with a truncated response, which
+// Import rejects since the archive is authenticated.
+func (o *Command) Export(rw io.Writer, req io.Reader) command.Error {
+	var request ExportRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, commandName, exportCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	if request.Passphrase == "" {
+		logutil.LogDebug(logger, commandName, exportCommandMethod, errEmptyPassphrase)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyPassphrase))
+	}
+
+	// the stores are opened first so that their failures are reported before anything is written to rw
+	for _, name := range storeNames {
+		if _, err = o.ctx.StorageProvider().OpenStore(name); err != nil {
+			logutil.LogError(logger, commandName, exportCommandMethod, err.Error())
+			return command.NewExecuteError(ExportError, fmt.Errorf("open store %s: %w", name, err))
+		}
+	}
+
+	archive := &archiveWriter{w: rw}
+
+	err = backup.Export(archive, o.ctx.StorageProvider(), request.Passphrase, storeNames...)
+	if err == nil {
+		err = archive.Close()
+	}
+
+	if err != nil {
+		logutil.LogError(logger, commandName, exportCommandMethod, err.Error())
+		return command.NewExecuteError(ExportError, err)
+	}
+
+	logutil.LogDebug(logger, commandName, exportCommandMethod, "success")
+
+	return nil
+}
+
+// Import restores the stores of an archive returned by Export, replacing the records of the same keys.
+// The agent should be restarted afterwards so that every service picks up the restored data.
+func (o *Command) Import(rw io.Writer, req io.Reader) command.Error {
+	var request ImportRequest
+
+	err := json.NewDecoder(req).Decode(&request)
+	if err != nil {
+		logutil.LogInfo(logger, commandName, importCommandMethod, err.Error())
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf("failed request decode : %w", err))
+	}
+
+	if request.Passphrase == "" {
+		logutil.LogDebug(logger, commandName, importCommandMethod, errEmptyPassphrase)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyPassphrase))
+	}
+
+	if len(request.Archive) == 0 {
+		logutil.LogDebug(logger, commandName, importCommandMethod, errEmptyArchive)
+		return command.NewValidationError(InvalidRequestErrorCode, fmt.Errorf(errEmptyArchive))
+	}
+
+	err = backup.Import(bytes.NewReader(request.Archive), o.ctx.StorageProvider(), request.Passphrase)
+	if err != nil {
+		logutil.LogError(logger, commandName, importCommandMethod, err.Error())
+		return command.NewExecuteError(ImportError, err)
+	}
+
+	command.WriteNillableResponse(rw, nil, logger)
+
+	logutil.LogDebug(logger, commandName, importCommandMethod, "success")
+
+	return nil
+}
+
+// archiveWriter writes an archive to w as the JSON of an ExportResponse, base64 encoding it as it's written.
+// Nothing is written to w until the archive starts.
+type archiveWriter struct {
+	w       io.Writer
+	encoder io.WriteCloser
+}
+
+func (a *archiveWriter) Write(p []byte) (int, error) {
+	if a.encoder == nil {
+		if _, err := io.WriteString(a.w, `{"archive":"`); err != nil {
+			return 0, err
+		}
+
+		a.encoder = base64.NewEncoder(base64.StdEncoding, a.w)
+	}
+
+	return a.encoder.Write(p)
+}
+
+// Close writes the end of the archive and of the response.
+func (a *archiveWriter) Close() error {
+	if a.encoder == nil {
+		_, err := io.WriteString(a.w, "{}\n")
+
+		return err
+	}
+
+	if err := a.encoder.Close(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(a.w, "\"}\n")
+
+	return err
+}
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/backup"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+)
+
+const passphrase = "passphrase"
+
+func TestNew(t *testing.T) {
+	cmd := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+	require.NotNil(t, cmd)
+	require.Equal(t, 2, len(cmd.GetHandlers()))
+}
+
+func TestExportImport(t *testing.T) {
+	source := mem.NewProvider()
+
+	store, err := source.OpenStore(connection.Namespace)
+	require.NoError(t, err)
+	require.NoError(t, store.Put("conn_1", []byte("record")))
+
+	var archive []byte
+
+	t.Run("test export - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{StorageProviderValue: source})
+
+		var rw bytes.Buffer
+		cmdErr := cmd.Export(&rw, bytes.NewBufferString(`{"passphrase":"`+passphrase+`"}`))
+		require.NoError(t, cmdErr)
+
+		var response ExportResponse
+		require.NoError(t, json.NewDecoder(&rw).Decode(&response))
+		require.NotEmpty(t, response.Archive)
+
+		archive = response.Archive
+	})
+
+	t.Run("test export - the archive is streamed", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			require.NoError(t, store.Put(fmt.Sprintf("conn_record_%d", i), bytes.Repeat([]byte("r"), 1000)))
+		}
+
+		cmd := New(&mockprovider.Provider{StorageProviderValue: source})
+
+		rw := &recordingWriter{}
+		cmdErr := cmd.Export(rw, bytes.NewBufferString(`{"passphrase":"`+passphrase+`"}`))
+		require.NoError(t, cmdErr)
+
+		// the response is written as the stores are read, not all at once
+		require.Less(t, rw.maxWrite, rw.Len()/10)
+
+		var response ExportResponse
+		require.NoError(t, json.NewDecoder(rw).Decode(&response))
+
+		target := mem.NewProvider()
+		require.NoError(t, backup.Import(bytes.NewReader(response.Archive), target, passphrase))
+
+		targetStore, err := target.OpenStore(connection.Namespace)
+		require.NoError(t, err)
+
+		v, err := targetStore.Get("conn_record_99")
+		require.NoError(t, err)
+		require.Len(t, v, 1000)
+	})
+
+	t.Run("test import - success", func(t *testing.T) {
+		target := mem.NewProvider()
+		cmd := New(&mockprovider.Provider{StorageProviderValue: target})
+
+		reqBytes, err := json.Marshal(ImportRequest{Passphrase: passphrase, Archive: archive})
+		require.NoError(t, err)
+
+		var rw bytes.Buffer
+		cmdErr := cmd.Import(&rw, bytes.NewBuffer(reqBytes))
+		require.NoError(t, cmdErr)
+
+		store, err := target.OpenStore(connection.Namespace)
+		require.NoError(t, err)
+
+		v, err := store.Get("conn_1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("record"), v)
+	})
+
+	t.Run("test import - wrong passphrase", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+
+		reqBytes, err := json.Marshal(ImportRequest{Passphrase: "wrong", Archive: archive})
+		require.NoError(t, err)
+
+		var rw bytes.Buffer
+		cmdErr := cmd.Import(&rw, bytes.NewBuffer(reqBytes))
+		require.Error(t, cmdErr)
+		require.Equal(t, ImportError, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+	})
+}
+
+func TestExport_Errors(t *testing.T) {
+	t.Run("test export - invalid request", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+
+		var rw bytes.Buffer
+		cmdErr := cmd.Export(&rw, bytes.NewBufferString("--"))
+		require.Error(t, cmdErr)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+		require.Equal(t, command.ValidationError, cmdErr.Type())
+
+		cmdErr = cmd.Export(&rw, bytes.NewBufferString("{}"))
+		require.EqualError(t, cmdErr, errEmptyPassphrase)
+		require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+	})
+
+	t.Run("test export - storage error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstorage.MockStoreProvider{ErrOpenStoreHandle: fmt.Errorf("open error")},
+		})
+
+		var rw bytes.Buffer
+		cmdErr := cmd.Export(&rw, bytes.NewBufferString(`{"passphrase":"`+passphrase+`"}`))
+		require.Error(t, cmdErr)
+		require.Contains(t, cmdErr.Error(), "open error")
+		require.Equal(t, ExportError, cmdErr.Code())
+		require.Equal(t, command.ExecuteError, cmdErr.Type())
+		require.Zero(t, rw.Len())
+	})
+}
+
+func TestImport_Errors(t *testing.T) {
+	cmd := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+
+	var rw bytes.Buffer
+	cmdErr := cmd.Import(&rw, bytes.NewBufferString("--"))
+	require.Error(t, cmdErr)
+	require.Equal(t, InvalidRequestErrorCode, cmdErr.Code())
+
+	cmdErr = cmd.Import(&rw, bytes.NewBufferString(`{"archive":"e30="}`))
+	require.EqualError(t, cmdErr, errEmptyPassphrase)
+
+	cmdErr = cmd.Import(&rw, bytes.NewBufferString(`{"passphrase":"`+passphrase+`"}`))
+	require.EqualError(t, cmdErr, errEmptyArchive)
+
+	cmdErr = cmd.Import(&rw, bytes.NewBufferString(`{"passphrase":"`+passphrase+`","archive":"eyJ2ZXJzaW9uIjowfQ=="}`))
+	require.Error(t, cmdErr)
+	require.Equal(t, ImportError, cmdErr.Code())
+}
+
+// recordingWriter is a buffer recording the size of the largest write.
+type recordingWriter struct {
+	bytes.Buffer
+	maxWrite int
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.maxWrite {
+		w.maxWrite = len(p)
+	}
+
+	return w.Buffer.Write(p)
+}
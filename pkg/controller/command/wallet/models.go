@@ -0,0 +1,27 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+// ExportRequest is model for export request.
+type ExportRequest struct {
+	// Passphrase protecting the archive
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// ExportResponse for returning the archive of the agent stores.
+type ExportResponse struct {
+	// Archive encrypted with the passphrase, base64 encoded
+	Archive []byte `json:"archive,omitempty"`
+}
+
+// ImportRequest is model for import request.
+type ImportRequest struct {
+	// Passphrase the archive was exported with
+	Passphrase string `json:"passphrase,omitempty"`
+	// Archive as returned by export, base64 encoded
+	Archive []byte `json:"archive,omitempty"`
+}
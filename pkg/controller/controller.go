@@ -9,6 +9,7 @@ package controller
 import (
 	"fmt"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	didexchangecmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
 	issuecredentialcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/issuecredential"
@@ -18,6 +19,7 @@ import (
 	routercmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/route"
 	vdricmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/verifiable"
+	walletcmd "github.com/hyperledger/aries-framework-go/pkg/controller/command/wallet"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
 	didexchangerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/didexchange"
 	issuecredentialrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/issuecredential"
@@ -27,16 +29,20 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest/route"
 	vdrirest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/vdri"
 	verifiablerest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/verifiable"
+	walletrest "github.com/hyperledger/aries-framework-go/pkg/controller/rest/wallet"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/context"
 )
 
+var logger = log.New("aries-framework/controller")
+
 type allOpts struct {
 	webhookURLs  []string
 	defaultLabel string
 	autoAccept   bool
 	msgHandler   command.MessageHandler
 	notifier     command.Notifier
+	shutdown     <-chan struct{}
 }
 
 const wsPath = "/ws"
@@ -79,6 +85,14 @@ func WithMessageHandler(handler command.MessageHandler) Opt {
 	}
 }
 
+// WithShutdown is an option setting a channel closed on the shutdown of the agent, the record change notifications
+// of the controller stop along with it.
+func WithShutdown(shutdown <-chan struct{}) Opt {
+	return func(opts *allOpts) {
+		opts.shutdown = shutdown
+	}
+}
+
 // GetRESTHandlers returns all REST handlers provided by controller.
 func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error) { // nolint: funlen,gocyclo
 	restAPIOpts := &allOpts{}
@@ -118,7 +132,7 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 	}
 
 	// verifiable command operation
-	verifiablecmd, err := verifiablerest.New(ctx)
+	verifiablecmd, err := verifiablerest.New(ctx, notifier)
 	if err != nil {
 		return nil, fmt.Errorf("create verifiable rest command : %w", err)
 	}
@@ -135,9 +149,14 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 		return nil, fmt.Errorf("create present-proof rest command : %w", err)
 	}
 
+	closeOnShutdown(restAPIOpts.shutdown, exchangeOp, verifiablecmd)
+
 	// kms command operation
 	kmscmd := kmsrest.New(ctx)
 
+	// wallet command operation
+	walletOp := walletrest.New(ctx)
+
 	// creat handlers from all operations
 	var allHandlers []rest.Handler
 	allHandlers = append(allHandlers, exchangeOp.GetRESTHandlers()...)
@@ -148,6 +167,7 @@ func GetRESTHandlers(ctx *context.Provider, opts ...Opt) ([]rest.Handler, error)
 	allHandlers = append(allHandlers, issuecredentialOp.GetRESTHandlers()...)
 	allHandlers = append(allHandlers, presentproofOp.GetRESTHandlers()...)
 	allHandlers = append(allHandlers, kmscmd.GetRESTHandlers()...)
+	allHandlers = append(allHandlers, walletOp.GetRESTHandlers()...)
 
 	nhp, ok := notifier.(handlerProvider)
 	if ok {
@@ -200,7 +220,7 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 	}
 
 	// verifiable command operation
-	verifiablecmd, err := verifiable.New(ctx)
+	verifiablecmd, err := verifiable.New(ctx, notifier)
 	if err != nil {
 		return nil, fmt.Errorf("create verifiable command : %w", err)
 	}
@@ -217,9 +237,14 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 		return nil, fmt.Errorf("create present-proof command : %w", err)
 	}
 
+	closeOnShutdown(cmdOpts.shutdown, didexcmd, verifiablecmd)
+
 	// kms command operation
 	kmscmd := kms.New(ctx)
 
+	// wallet command operation
+	wallet := walletcmd.New(ctx)
+
 	var allHandlers []command.Handler
 	allHandlers = append(allHandlers, didexcmd.GetHandlers()...)
 	allHandlers = append(allHandlers, vcmd.GetHandlers()...)
@@ -229,6 +254,28 @@ func GetCommandHandlers(ctx *context.Provider, opts ...Opt) ([]command.Handler,
 	allHandlers = append(allHandlers, kmscmd.GetHandlers()...)
 	allHandlers = append(allHandlers, issuecredential.GetHandlers()...)
 	allHandlers = append(allHandlers, presentproof.GetHandlers()...)
+	allHandlers = append(allHandlers, wallet.GetHandlers()...)
 
 	return allHandlers, nil
 }
+
+type closer interface {
+	Close() error
+}
+
+// closeOnShutdown closes the operations once shutdown is closed, if set.
+func closeOnShutdown(shutdown <-chan struct{}, operations ...closer) {
+	if shutdown == nil {
+		return
+	}
+
+	go func() {
+		<-shutdown
+
+		for _, o := range operations {
+			if err := o.Close(); err != nil {
+				logger.Warnf("close controller operation: %s", err)
+			}
+		}
+	}()
+}
@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package controller
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -160,6 +162,55 @@ func TestWithMessageHandler(t *testing.T) {
 	require.NotNil(t, controllerOpts.msgHandler)
 }
 
+func TestWithShutdown(t *testing.T) {
+	controllerOpts := &allOpts{}
+
+	shutdown := make(chan struct{})
+
+	opt := WithShutdown(shutdown)
+
+	opt(controllerOpts)
+
+	require.NotNil(t, controllerOpts.shutdown)
+
+	closed := make(chan struct{}, 2)
+
+	closeOnShutdown(controllerOpts.shutdown, &mockCloser{closed: closed}, &mockCloser{
+		closed: closed, err: errors.New("close error"),
+	})
+
+	select {
+	case <-closed:
+		require.Fail(t, "closed before the shutdown")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(shutdown)
+
+	// an operation failing to close doesn't prevent the others from closing
+	for i := 0; i < 2; i++ {
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			require.Fail(t, "operation not closed on shutdown")
+		}
+	}
+
+	// without a shutdown channel, operations are left open
+	closeOnShutdown(nil, &mockCloser{closed: closed})
+}
+
+type mockCloser struct {
+	closed chan struct{}
+	err    error
+}
+
+func (c *mockCloser) Close() error {
+	c.closed <- struct{}{}
+
+	return c.err
+}
+
 func generateTempDir(t testing.TB) (string, func()) {
 	path, err := ioutil.TempDir("", "db")
 	if err != nil {
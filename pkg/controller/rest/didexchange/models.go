@@ -150,6 +150,9 @@ type queryConnectionsResponse struct { // nolint: unused,deadcode
 
 	// in: body
 	Results []*didexchangeSvc.Connection `json:"results,omitempty"`
+
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // acceptExchangeRequestParams model
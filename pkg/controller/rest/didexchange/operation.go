@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/gorilla/mux"
 
@@ -19,7 +20,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
@@ -33,12 +34,14 @@ const (
 	connectionsByID              = operationID + "/{id}"
 	acceptExchangeRequest        = operationID + "/{id}/accept-request"
 	removeConnection             = operationID + "/{id}/remove"
+
+	limitParam = "limit"
 )
 
 // provider contains dependencies for the Exchange protocol and is typically created by using aries.Context()
 type provider interface {
 	Service(id string) (interface{}, error)
-	LegacyKMS() legacykms.KeyManager
+	KMS() kms.KeyManager
 	ServiceEndpoint() string
 	StorageProvider() storage.Provider
 	TransientStorageProvider() storage.Provider
@@ -68,6 +71,11 @@ func (c *Operation) GetRESTHandlers() []rest.Handler {
 	return c.handlers
 }
 
+// Close stops the notifications of the connection record changes, on the shutdown of the agent.
+func (c *Operation) Close() error {
+	return c.command.Close()
+}
+
 // registerHandler register handlers to be exposed from this protocol service as REST API endpoints
 func (c *Operation) registerHandler() {
 	// Add more protocol endpoints here to expose them as controller API endpoints
@@ -172,7 +180,7 @@ func (c *Operation) AcceptExchangeRequest(rw http.ResponseWriter, req *http.Requ
 //    default: genericError
 //        200: queryConnectionsResponse
 func (c *Operation) QueryConnections(rw http.ResponseWriter, req *http.Request) {
-	reqBytes, err := queryValuesAsJSON(req.URL.Query())
+	reqBytes, err := queryConnectionsAsJSON(req.URL.Query())
 	if err != nil {
 		rest.SendHTTPStatusError(rw, http.StatusBadRequest, didexchange.InvalidRequestErrorCode, err)
 		return
@@ -232,6 +240,30 @@ func queryValuesAsJSON(vals url.Values) ([]byte, error) {
 	return json.Marshal(args)
 }
 
+// queryConnectionsAsJSON normalizes the query string of a connections query, keeping the limit a number.
+func queryConnectionsAsJSON(vals url.Values) ([]byte, error) {
+	args := make(map[string]interface{})
+
+	for k, v := range vals {
+		if len(v) > 0 {
+			args[k] = v[0]
+		}
+	}
+
+	if v := vals.Get(limitParam); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit : %w", err)
+		}
+
+		args[limitParam] = limit
+	} else {
+		delete(args, limitParam)
+	}
+
+	return json.Marshal(args)
+}
+
 // getIDFromRequest returns ID from request
 func getIDFromRequest(rw http.ResponseWriter, req *http.Request) (string, bool) {
 	id := mux.Vars(req)["id"]
@@ -31,7 +31,7 @@ import (
 	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/didexchange"
 	mockroute "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/route"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
@@ -187,7 +187,7 @@ func TestOperation_QueryConnectionByParams(t *testing.T) {
 		// perform test
 		handler = getHandler(t, connections)
 		buf, err := getSuccessResponseFromHandler(handler, nil,
-			operationID+"?state=complete")
+			operationID+"?state=completed")
 		require.NoError(t, err)
 
 		response := didexchange.QueryConnectionsResponse{}
@@ -224,6 +224,37 @@ func TestOperation_QueryConnectionByParams(t *testing.T) {
 	})
 }
 
+func TestOperation_QueryConnectionPages(t *testing.T) {
+	handler := getHandler(t, connections)
+
+	t.Run("test query connections with limit", func(t *testing.T) {
+		buf, err := getSuccessResponseFromHandler(handler, nil,
+			operationID+"?sort_by=label&order=desc&limit=1")
+		require.NoError(t, err)
+
+		response := didexchange.QueryConnectionsResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Len(t, response.Results, 1)
+	})
+
+	t.Run("test query connections with empty limit", func(t *testing.T) {
+		buf, err := getSuccessResponseFromHandler(handler, nil, operationID+"?limit=")
+		require.NoError(t, err)
+
+		response := didexchange.QueryConnectionsResponse{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.NotEmpty(t, response.Results)
+		require.Empty(t, response.NextCursor)
+	})
+
+	t.Run("test query connections with invalid limit", func(t *testing.T) {
+		buf, code, err := sendRequestToHandler(handler, nil, operationID+"?limit=ten")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyRESTError(t, didexchange.InvalidRequestErrorCode, buf.Bytes())
+	})
+}
+
 func TestOperation_ReceiveInvitationFailure(t *testing.T) {
 	// Failure in service
 	var jsonStr = []byte(`{
@@ -383,7 +414,7 @@ func TestEmptyID(t *testing.T) {
 			didexsvc.DIDExchange: &mockdidexchange.MockDIDExchangeSvc{},
 			route.Coordination:   &mockroute.MockRouteSvc{},
 		},
-		LegacyKMSValue:       &mockkms.CloseableKMS{},
+		KMSValue:             &mockkms.KeyManager{},
 		ServiceEndpointValue: "endppint",
 	}
 
@@ -447,11 +478,14 @@ type fails struct {
 func getHandlerWithError(t *testing.T, lookup string, f *fails) rest.Handler {
 	transientStore := mockstore.MockStore{Store: make(map[string][]byte)}
 	store := mockstore.MockStore{Store: make(map[string][]byte)}
-	connRec := &connection.Record{State: "complete", ConnectionID: "1234", ThreadID: "th1234"}
+	connRec := &connection.Record{State: "completed", ConnectionID: "1234", ThreadID: "th1234"}
 
-	connBytes, err := json.Marshal(connRec)
+	recorder, err := connection.NewRecorder(&mockprovider.Provider{
+		TransientStorageProviderValue: &mockstore.MockStoreProvider{Store: &transientStore},
+		StorageProviderValue:          &mockstore.MockStoreProvider{Store: &store},
+	})
 	require.NoError(t, err)
-	require.NoError(t, store.Put("conn_1234", connBytes))
+	require.NoError(t, recorder.SaveConnectionRecord(connRec))
 
 	h := crypto.SHA256.New()
 	hash := h.Sum([]byte(connRec.ConnectionID))
@@ -476,7 +510,7 @@ func getHandlerWithError(t *testing.T, lookup string, f *fails) rest.Handler {
 			},
 			route.Coordination: &mockroute.MockRouteSvc{},
 		},
-		LegacyKMSValue:                &mockkms.CloseableKMS{CreateEncryptionKeyValue: "sample-key"},
+		KMSValue:                      &mockkms.KeyManager{},
 		ServiceEndpointValue:          "endpoint",
 		TransientStorageProviderValue: &mockstore.MockStoreProvider{Store: &transientStore},
 		StorageProviderValue:          &mockstore.MockStoreProvider{Store: &store}},
@@ -32,3 +32,75 @@ type createKeySetRes struct { // nolint: unused,deadcode
 	// in: body
 	kms.CreateKeySetResponse
 }
+
+// listKeysRes model
+//
+// This is used for returning the metadata of the keys.
+//
+// swagger:response listKeysRes
+type listKeysRes struct { // nolint: unused,deadcode
+
+	// in: body
+	kms.ListKeysResponse
+}
+
+// getKeyMetadataReq model
+//
+// This is used to retrieve the metadata of a key.
+//
+// swagger:parameters getKeyMetadataReq
+type getKeyMetadataReq struct { // nolint: unused,deadcode
+	// Key ID
+	//
+	// in: path
+	// required: true
+	KeyID string `json:"keyID"`
+}
+
+// keyMetadataRes model
+//
+// This is used for returning the metadata of a key.
+//
+// swagger:response keyMetadataRes
+type keyMetadataRes struct { // nolint: unused,deadcode
+
+	// in: body
+	kms.KeyMetadataResponse
+}
+
+// updateKeyMetadataReq model
+//
+// This is used to record what a key is used for.
+//
+// swagger:parameters updateKeyMetadataReq
+type updateKeyMetadataReq struct { // nolint: unused,deadcode
+	// Key ID
+	//
+	// in: path
+	// required: true
+	KeyID string `json:"keyID"`
+
+	// Params for updating the key metadata
+	//
+	// in: body
+	Params struct {
+		// Purpose of the key, e.g. "authentication"
+		Purpose string `json:"purpose,omitempty"`
+		// DID and verification method the key belongs to
+		DID                string `json:"did,omitempty"`
+		VerificationMethod string `json:"verificationMethod,omitempty"`
+	}
+}
+
+// deleteKeyReq model
+//
+// This is used to delete a key.
+//
+// swagger:parameters deleteKeyReq
+type deleteKeyReq struct { // nolint: unused,deadcode
+	// Key ID
+	//
+	// in: path
+	// required: true
+	KeyID string `json:"keyID"`
+}
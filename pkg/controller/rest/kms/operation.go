@@ -7,9 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package kms
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/gorilla/mux"
+
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 
 	cmdkms "github.com/hyperledger/aries-framework-go/pkg/controller/command/kms"
@@ -21,6 +27,9 @@ import (
 const (
 	kmseOperationID  = "/kms"
 	createKeySetPath = kmseOperationID + "/keyset"
+	keysPath         = kmseOperationID + "/keys"
+	keyPath          = keysPath + "/{keyID}"
+	keyMetadataPath  = keyPath + "/metadata"
 )
 
 // provider contains dependencies for the kms command and is typically created by using aries.Context().
@@ -30,6 +39,10 @@ type provider interface {
 
 type kmsCommand interface {
 	CreateKeySet(rw io.Writer, req io.Reader) command.Error
+	ListKeys(rw io.Writer, req io.Reader) command.Error
+	GetKeyMetadata(rw io.Writer, req io.Reader) command.Error
+	UpdateKeyMetadata(rw io.Writer, req io.Reader) command.Error
+	DeleteKey(rw io.Writer, req io.Reader) command.Error
 }
 
 // Operation contains basic common operations provided by controller REST API
@@ -57,6 +70,10 @@ func (o *Operation) GetRESTHandlers() []rest.Handler {
 func (o *Operation) registerHandler() {
 	o.handlers = []rest.Handler{
 		cmdutil.NewHTTPHandler(createKeySetPath, http.MethodPost, o.CreateKeySet),
+		cmdutil.NewHTTPHandler(keysPath, http.MethodGet, o.ListKeys),
+		cmdutil.NewHTTPHandler(keyPath, http.MethodGet, o.GetKeyMetadata),
+		cmdutil.NewHTTPHandler(keyMetadataPath, http.MethodPost, o.UpdateKeyMetadata),
+		cmdutil.NewHTTPHandler(keyPath, http.MethodDelete, o.DeleteKey),
 	}
 }
 
@@ -70,3 +87,67 @@ func (o *Operation) registerHandler() {
 func (o *Operation) CreateKeySet(rw http.ResponseWriter, req *http.Request) {
 	rest.Execute(o.command.CreateKeySet, rw, req.Body)
 }
+
+// ListKeys swagger:route GET /kms/keys kms listKeys
+//
+// Lists the metadata of the keys.
+//
+// Responses:
+//    default: genericError
+//        200: listKeysRes
+func (o *Operation) ListKeys(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.ListKeys, rw, req.Body)
+}
+
+// GetKeyMetadata swagger:route GET /kms/keys/{keyID} kms getKeyMetadataReq
+//
+// Retrieves the metadata of a key.
+//
+// Responses:
+//    default: genericError
+//        200: keyMetadataRes
+func (o *Operation) GetKeyMetadata(rw http.ResponseWriter, req *http.Request) {
+	request := fmt.Sprintf(`{"keyID":%q}`, mux.Vars(req)["keyID"])
+
+	rest.Execute(o.command.GetKeyMetadata, rw, bytes.NewBufferString(request))
+}
+
+// UpdateKeyMetadata swagger:route POST /kms/keys/{keyID}/metadata kms updateKeyMetadataReq
+//
+// Records what a key is used for.
+//
+// Responses:
+//    default: genericError
+func (o *Operation) UpdateKeyMetadata(rw http.ResponseWriter, req *http.Request) {
+	var request cmdkms.UpdateKeyMetadataRequest
+
+	if req.Body != nil {
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+			rest.SendHTTPStatusError(rw, http.StatusBadRequest, cmdkms.InvalidRequestErrorCode,
+				fmt.Errorf("failed request decode : %w", err))
+			return
+		}
+	}
+
+	request.KeyID = mux.Vars(req)["keyID"]
+
+	reqBytes, err := json.Marshal(request)
+	if err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, cmdkms.InvalidRequestErrorCode, err)
+		return
+	}
+
+	rest.Execute(o.command.UpdateKeyMetadata, rw, bytes.NewBuffer(reqBytes))
+}
+
+// DeleteKey swagger:route DELETE /kms/keys/{keyID} kms deleteKeyReq
+//
+// Deletes a key.
+//
+// Responses:
+//    default: genericError
+func (o *Operation) DeleteKey(rw http.ResponseWriter, req *http.Request) {
+	request := fmt.Sprintf(`{"keyID":%q}`, mux.Vars(req)["keyID"])
+
+	rest.Execute(o.command.DeleteKey, rw, bytes.NewBufferString(request))
+}
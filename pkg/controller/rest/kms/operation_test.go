@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	kmsapi "github.com/hyperledger/aries-framework-go/pkg/kms"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 )
 
@@ -31,7 +32,7 @@ func TestNew(t *testing.T) {
 			KMSValue: &mockkms.KeyManager{},
 		})
 		require.NotNil(t, cmd)
-		require.Equal(t, 1, len(cmd.GetRESTHandlers()))
+		require.Equal(t, 5, len(cmd.GetRESTHandlers()))
 	})
 }
 
@@ -70,6 +71,95 @@ func TestCreateKeySet(t *testing.T) {
 	})
 }
 
+func TestKeys(t *testing.T) {
+	t.Run("test list keys", func(t *testing.T) {
+		keys := []*kmsapi.KeyMetadata{{ID: "key1", Type: kmsapi.ED25519Type}}
+
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{ListValue: keys},
+		})
+
+		handler := lookupHandler(t, cmd, keysPath, http.MethodGet)
+		buf, err := getSuccessResponseFromHandler(handler, nil, keysPath)
+		require.NoError(t, err)
+
+		response := listKeysRes{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Equal(t, "key1", response.Keys[0].ID)
+	})
+
+	t.Run("test get key metadata", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{MetadataValue: &kmsapi.KeyMetadata{ID: "key1", Purpose: "authentication"}},
+		})
+
+		handler := lookupHandler(t, cmd, keyPath, http.MethodGet)
+		buf, err := getSuccessResponseFromHandler(handler, nil, keysPath+"/key1")
+		require.NoError(t, err)
+
+		response := keyMetadataRes{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Equal(t, "key1", response.ID)
+		require.Equal(t, "authentication", response.Purpose)
+
+		cmd = New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{MetadataErr: kmsapi.ErrKeyNotFound},
+		})
+
+		handler = lookupHandler(t, cmd, keyPath, http.MethodGet)
+		buf, code, err := sendRequestToHandler(handler, nil, keysPath+"/key1")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, kms.GetKeyMetadataError, kmsapi.ErrKeyNotFound.Error(), buf.Bytes())
+	})
+
+	t.Run("test update key metadata", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{},
+		})
+
+		handler := lookupHandler(t, cmd, keyMetadataPath, http.MethodPost)
+		_, err := getSuccessResponseFromHandler(handler,
+			bytes.NewBufferString(`{"purpose":"authentication","did":"did:example:123"}`), keysPath+"/key1/metadata")
+		require.NoError(t, err)
+
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`{`), keysPath+"/key1/metadata")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, kms.InvalidRequestErrorCode, "failed request decode", buf.Bytes())
+
+		cmd = New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{UpdateErr: fmt.Errorf("update error")},
+		})
+
+		handler = lookupHandler(t, cmd, keyMetadataPath, http.MethodPost)
+		buf, code, err = sendRequestToHandler(handler, nil, keysPath+"/key1/metadata")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, kms.UpdateKeyMetadataError, "update error", buf.Bytes())
+	})
+
+	t.Run("test delete key", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{},
+		})
+
+		handler := lookupHandler(t, cmd, keyPath, http.MethodDelete)
+		_, err := getSuccessResponseFromHandler(handler, nil, keysPath+"/key1")
+		require.NoError(t, err)
+
+		cmd = New(&mockprovider.Provider{
+			KMSValue: &mockkms.KeyManager{DeleteErr: fmt.Errorf("delete error")},
+		})
+
+		handler = lookupHandler(t, cmd, keyPath, http.MethodDelete)
+		buf, code, err := sendRequestToHandler(handler, nil, keysPath+"/key1")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, kms.DeleteKeyError, "delete error", buf.Bytes())
+	})
+}
+
 func lookupHandler(t *testing.T, op *Operation, path, method string) rest.Handler {
 	handlers := op.GetRESTHandlers()
 	require.NotEmpty(t, handlers)
@@ -144,3 +234,19 @@ type mockKMSCommand struct {
 func (m *mockKMSCommand) CreateKeySet(rw io.Writer, req io.Reader) command.Error {
 	return nil
 }
+
+func (m *mockKMSCommand) ListKeys(rw io.Writer, req io.Reader) command.Error {
+	return nil
+}
+
+func (m *mockKMSCommand) GetKeyMetadata(rw io.Writer, req io.Reader) command.Error {
+	return nil
+}
+
+func (m *mockKMSCommand) UpdateKeyMetadata(rw io.Writer, req io.Reader) command.Error {
+	return nil
+}
+
+func (m *mockKMSCommand) DeleteKey(rw io.Writer, req io.Reader) command.Error {
+	return nil
+}
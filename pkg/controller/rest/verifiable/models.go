@@ -122,6 +122,46 @@ type credentialRecord struct {
 type credentialRecordResult struct {
 	// in: body
 	Result []*verifiablestore.Record `json:"result,omitempty"`
+
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// getCredentialsReq model
+//
+// This is used to query credential records.
+//
+// swagger:parameters getCredentials
+type getCredentialsReq struct { // nolint: unused,deadcode
+	// Type of the credentials
+	//
+	// in: query
+	Type string `json:"type"`
+
+	// SubjectID is the ID of the subject of the credentials
+	//
+	// in: query
+	SubjectID string `json:"subjectId"`
+
+	// SortBy is the field records are sorted by: created (default) or name
+	//
+	// in: query
+	SortBy string `json:"sortBy"`
+
+	// Order of the sort: asc (default) or desc
+	//
+	// in: query
+	Order string `json:"order"`
+
+	// Cursor is the next cursor of the previous page, empty for the first page
+	//
+	// in: query
+	Cursor string `json:"cursor"`
+
+	// Limit is the maximum number of records returned, zero for no limit
+	//
+	// in: query
+	Limit int `json:"limit"`
 }
 
 // presentationRecordResult model
@@ -9,11 +9,15 @@ package verifiable
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/gorilla/mux"
 
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
@@ -59,8 +63,8 @@ type Operation struct {
 }
 
 // New returns new common operations rest client instance
-func New(p provider) (*Operation, error) {
-	cmd, err := verifiable.New(p)
+func New(p provider, notifier command.Notifier) (*Operation, error) {
+	cmd, err := verifiable.New(p, notifier)
 	if err != nil {
 		return nil, fmt.Errorf("verfiable new: %w", err)
 	}
@@ -76,6 +80,11 @@ func (o *Operation) GetRESTHandlers() []rest.Handler {
 	return o.handlers
 }
 
+// Close stops the notifications of the credential and presentation record changes, on the shutdown of the agent.
+func (o *Operation) Close() error {
+	return o.command.Close()
+}
+
 // registerHandler register handlers to be exposed from this protocol service as REST API endpoints
 func (o *Operation) registerHandler() {
 	o.handlers = []rest.Handler{
@@ -190,7 +199,41 @@ func (o *Operation) GetCredentialByName(rw http.ResponseWriter, req *http.Reques
 //    default: genericError
 //        200: credentialRecordResult
 func (o *Operation) GetCredentials(rw http.ResponseWriter, req *http.Request) {
-	rest.Execute(o.command.GetCredentials, rw, req.Body)
+	request, err := queryCredentialsArgs(req.URL.Query())
+	if err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, verifiable.InvalidRequestErrorCode, err)
+		return
+	}
+
+	reqBytes, err := json.Marshal(request)
+	if err != nil {
+		rest.SendHTTPStatusError(rw, http.StatusBadRequest, verifiable.InvalidRequestErrorCode, err)
+		return
+	}
+
+	rest.Execute(o.command.GetCredentials, rw, bytes.NewReader(reqBytes))
+}
+
+// queryCredentialsArgs reads the arguments of a credentials query from the query string.
+func queryCredentialsArgs(vals url.Values) (*verifiable.QueryCredentialsArgs, error) {
+	request := &verifiable.QueryCredentialsArgs{
+		Type:      vals.Get("type"),
+		SubjectID: vals.Get("subjectId"),
+		SortBy:    vals.Get("sortBy"),
+		Order:     vals.Get("order"),
+		Cursor:    vals.Get("cursor"),
+	}
+
+	if v := vals.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit : %w", err)
+		}
+
+		request.Limit = limit
+	}
+
+	return request, nil
 }
 
 // GetPresentations swagger:route GET /verifiable/presentations verifiable
@@ -25,6 +25,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/command/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/webnotifier"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	verifiableapi "github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
@@ -200,7 +201,7 @@ func TestNew(t *testing.T) {
 	t.Run("test new command - success", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 		require.Equal(t, 10, len(cmd.GetRESTHandlers()))
@@ -211,7 +212,7 @@ func TestNew(t *testing.T) {
 			StorageProviderValue: &mockstore.MockStoreProvider{
 				ErrOpenStoreHandle: fmt.Errorf("error opening the store"),
 			},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "new vc store")
 		require.Nil(t, cmd)
@@ -222,7 +223,7 @@ func TestValidateVC(t *testing.T) {
 	t.Run("test validate vc - success", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -245,7 +246,7 @@ func TestValidateVC(t *testing.T) {
 	t.Run("test validate vc - error", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -266,7 +267,7 @@ func TestSaveVC(t *testing.T) {
 	t.Run("test save vc - success", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -292,7 +293,7 @@ func TestSaveVC(t *testing.T) {
 	t.Run("test save vc - error", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -317,7 +318,7 @@ func TestGetVC(t *testing.T) {
 
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 		fmt.Println(base64.StdEncoding.EncodeToString([]byte("http://example.edu/credentials/1989")))
@@ -341,7 +342,7 @@ func TestGetVC(t *testing.T) {
 
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -359,7 +360,7 @@ func TestGetCredentialByName(t *testing.T) {
 	t.Run("test get vc by name - success", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -393,7 +394,7 @@ func TestGetCredentialByName(t *testing.T) {
 	t.Run("test get vc by name - error", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -412,7 +413,7 @@ func TestGetCredentials(t *testing.T) {
 	t.Run("test get credentials", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -442,6 +443,27 @@ func TestGetCredentials(t *testing.T) {
 		require.Len(t, response.Result[0].Context, 1)
 		require.Len(t, response.Result[0].Type, 1)
 	})
+
+	t.Run("test get credentials - query", func(t *testing.T) {
+		cmd, err := New(&mockprovider.Provider{
+			StorageProviderValue: mockstore.NewMockStoreProvider(),
+		}, webnotifier.NewHTTPNotifier(nil))
+		require.NoError(t, err)
+
+		handler := lookupHandler(t, cmd, getCredentialsPath, http.MethodGet)
+		buf, err := getSuccessResponseFromHandler(handler, nil,
+			getCredentialsPath+"?type=VerifiableCredential&sortBy=name&order=desc&limit=10")
+		require.NoError(t, err)
+
+		var response credentialRecordResult
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.Empty(t, response.Result)
+
+		buf, code, err := sendRequestToHandler(handler, nil, getCredentialsPath+"?limit=ten")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Contains(t, buf.String(), "invalid limit")
+	})
 }
 
 func TestGeneratePresentation(t *testing.T) {
@@ -463,7 +485,7 @@ func TestGeneratePresentation(t *testing.T) {
 		},
 		KMSValue:    &kmsmock.KeyManager{},
 		CryptoValue: &cryptomock.Crypto{},
-	})
+	}, webnotifier.NewHTTPNotifier(nil))
 	require.NotNil(t, cmd)
 	require.NoError(t, cmdErr)
 
@@ -655,7 +677,7 @@ func TestGeneratePresentationByID(t *testing.T) {
 		},
 		KMSValue:    &kmsmock.KeyManager{},
 		CryptoValue: &cryptomock.Crypto{},
-	})
+	}, webnotifier.NewHTTPNotifier(nil))
 	require.NotNil(t, cmd)
 	require.NoError(t, cmdErr)
 
@@ -720,7 +742,7 @@ func TestSaveVP(t *testing.T) {
 	t.Run("test save vp - success", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -750,7 +772,7 @@ func TestSaveVP(t *testing.T) {
 	t.Run("test save vp - error", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -775,7 +797,7 @@ func TestGetVP(t *testing.T) {
 
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -818,7 +840,7 @@ func TestGetVP(t *testing.T) {
 
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
@@ -836,7 +858,7 @@ func TestGetPresentations(t *testing.T) {
 	t.Run("test get presentations", func(t *testing.T) {
 		cmd, err := New(&mockprovider.Provider{
 			StorageProviderValue: mockstore.NewMockStoreProvider(),
-		})
+		}, webnotifier.NewHTTPNotifier(nil))
 		require.NoError(t, err)
 		require.NotNil(t, cmd)
 
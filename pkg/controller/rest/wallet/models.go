@@ -0,0 +1,54 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/wallet"
+)
+
+// exportWalletReq model
+//
+// This is used for exportWallet request.
+//
+// swagger:parameters exportWallet
+type exportWalletReq struct { // nolint: unused,deadcode
+	// Params for exportWallet
+	//
+	// in: body
+	wallet.ExportRequest
+}
+
+// exportWalletRes model
+//
+// This is used for returning the archive of the wallet.
+//
+// swagger:response exportWalletRes
+type exportWalletRes struct { // nolint: unused,deadcode
+
+	// in: body
+	wallet.ExportResponse
+}
+
+// importWalletReq model
+//
+// This is used for importWallet request.
+//
+// swagger:parameters importWallet
+type importWalletReq struct { // nolint: unused,deadcode
+	// Params for importWallet
+	//
+	// in: body
+	wallet.ImportRequest
+}
+
+// importWalletRes model
+//
+// This is used for returning the import response, which is empty.
+//
+// swagger:response importWalletRes
+type importWalletRes struct { // nolint: unused,deadcode
+}
@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	cmdwallet "github.com/hyperledger/aries-framework-go/pkg/controller/command/wallet"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/internal/cmdutil"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	walletOperationID = "/wallet"
+	exportPath        = walletOperationID + "/export"
+	importPath        = walletOperationID + "/import"
+)
+
+// provider contains dependencies for the wallet command and is typically created by using aries.Context().
+type provider interface {
+	StorageProvider() storage.Provider
+}
+
+type walletCommand interface {
+	Export(rw io.Writer, req io.Reader) command.Error
+	Import(rw io.Writer, req io.Reader) command.Error
+}
+
+// Operation contains basic common operations provided by controller REST API
+type Operation struct {
+	handlers []rest.Handler
+	command  walletCommand
+}
+
+// New returns new wallet operations rest client instance
+func New(p provider) *Operation {
+	o := &Operation{command: cmdwallet.New(p)}
+	o.registerHandler()
+
+	return o
+}
+
+// GetRESTHandlers get all controller API handler available for this service
+func (o *Operation) GetRESTHandlers() []rest.Handler {
+	return o.handlers
+}
+
+// registerHandler register handlers to be exposed from this protocol service as REST API endpoints
+func (o *Operation) registerHandler() {
+	o.handlers = []rest.Handler{
+		cmdutil.NewHTTPHandler(exportPath, http.MethodPost, o.Export),
+		cmdutil.NewHTTPHandler(importPath, http.MethodPost, o.Import),
+	}
+}
+
+// Export swagger:route POST /wallet/export wallet exportWallet
+//
+// Exports every store of the wallet into a single archive encrypted with the given passphrase.
+// Keys stay wrapped by the secret lock of the agent, which isn't part of the archive.
+// The archive is streamed in the response as the stores are read.
+//
+// Responses:
+//    default: genericError
+//        200: exportWalletRes
+func (o *Operation) Export(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Export, rw, req.Body)
+}
+
+// Import swagger:route POST /wallet/import wallet importWallet
+//
+// Imports the stores of an archive returned by export, the agent should be restarted afterwards.
+//
+// Responses:
+//    default: genericError
+//        200: importWalletRes
+func (o *Operation) Import(rw http.ResponseWriter, req *http.Request) {
+	rest.Execute(o.command.Import, rw, req.Body)
+}
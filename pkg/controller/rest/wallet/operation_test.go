@@ -0,0 +1,157 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/command/wallet"
+	"github.com/hyperledger/aries-framework-go/pkg/controller/rest"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestNew(t *testing.T) {
+	cmd := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+	require.NotNil(t, cmd)
+	require.Equal(t, 2, len(cmd.GetRESTHandlers()))
+}
+
+func TestExportImport(t *testing.T) {
+	var archive []byte
+
+	t.Run("test export - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+
+		handler := lookupHandler(t, cmd, exportPath, http.MethodPost)
+		buf, err := getSuccessResponseFromHandler(handler, bytes.NewBufferString(`{"passphrase":"pass"}`), exportPath)
+		require.NoError(t, err)
+
+		var response exportWalletRes
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &response))
+		require.NotEmpty(t, response.Archive)
+
+		archive = response.Archive
+	})
+
+	t.Run("test import - success", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+
+		reqBytes, err := json.Marshal(importWalletReq{ImportRequest: wallet.ImportRequest{
+			Passphrase: "pass",
+			Archive:    archive,
+		}})
+		require.NoError(t, err)
+
+		handler := lookupHandler(t, cmd, importPath, http.MethodPost)
+		_, err = getSuccessResponseFromHandler(handler, bytes.NewBuffer(reqBytes), importPath)
+		require.NoError(t, err)
+	})
+
+	t.Run("test export - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{
+			StorageProviderValue: &mockstorage.MockStoreProvider{ErrOpenStoreHandle: fmt.Errorf("open error")},
+		})
+
+		handler := lookupHandler(t, cmd, exportPath, http.MethodPost)
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`{"passphrase":"pass"}`), exportPath)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusInternalServerError, code)
+		verifyError(t, wallet.ExportError, "open error", buf.Bytes())
+	})
+
+	t.Run("test import - error", func(t *testing.T) {
+		cmd := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+
+		handler := lookupHandler(t, cmd, importPath, http.MethodPost)
+		buf, code, err := sendRequestToHandler(handler, bytes.NewBufferString(`{"passphrase":"pass"}`), importPath)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusBadRequest, code)
+		verifyError(t, wallet.InvalidRequestErrorCode, "archive is mandatory", buf.Bytes())
+	})
+}
+
+func lookupHandler(t *testing.T, op *Operation, path, method string) rest.Handler {
+	handlers := op.GetRESTHandlers()
+	require.NotEmpty(t, handlers)
+
+	for _, h := range handlers {
+		if h.Path() == path && h.Method() == method {
+			return h
+		}
+	}
+
+	require.Fail(t, "unable to find handler")
+
+	return nil
+}
+
+// getSuccessResponseFromHandler reads response from given http handle func.
+// expects http status OK.
+func getSuccessResponseFromHandler(handler rest.Handler, requestBody io.Reader,
+	path string) (*bytes.Buffer, error) {
+	response, status, err := sendRequestToHandler(handler, requestBody, path)
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: got %v, want %v",
+			status, http.StatusOK)
+	}
+
+	return response, err
+}
+
+// sendRequestToHandler reads response from given http handle func.
+func sendRequestToHandler(handler rest.Handler, requestBody io.Reader, path string) (*bytes.Buffer, int, error) {
+	// prepare request
+	req, err := http.NewRequest(handler.Method(), path, requestBody)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// prepare router
+	router := mux.NewRouter()
+
+	router.HandleFunc(handler.Path(), handler.Handle()).Methods(handler.Method())
+
+	// create a ResponseRecorder (which satisfies http.ResponseWriter) to record the response.
+	rr := httptest.NewRecorder()
+
+	// serve http on given response and request
+	router.ServeHTTP(rr, req)
+
+	return rr.Body, rr.Code, nil
+}
+
+func verifyError(t *testing.T, expectedCode command.Code, expectedMsg string, data []byte) {
+	// Parser generic error response
+	errResponse := struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{}
+	err := json.Unmarshal(data, &errResponse)
+	require.NoError(t, err)
+
+	// verify response
+	require.EqualValues(t, expectedCode, errResponse.Code)
+	require.NotEmpty(t, errResponse.Message)
+
+	if expectedMsg != "" {
+		require.Contains(t, errResponse.Message, expectedMsg)
+	}
+}
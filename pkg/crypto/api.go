@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 
 package crypto
 
+import "io"
+
 // package Crypto contains the consolidated Crypto interface to be used by the framework.
 // it will be created via Options creation of pkg/framework/provider/crypto (implementation of
 // pkg/framework/spi/crypto)
@@ -23,6 +25,19 @@ type Crypto interface {
 	//		plainText in []byte
 	//		error in case of errors
 	Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, error)
+	// EncryptStream will return a writer encrypting the data written to it with aad into w using a matching
+	// streaming AEAD primitive in kh key handle, the ciphertext is written in segments so that large payloads don't
+	// have to be held in memory. The writer must be closed to write the last segment.
+	// returns:
+	// 		io.WriteCloser encrypting into w
+	//		error in case of errors
+	EncryptStream(w io.Writer, aad []byte, kh interface{}) (io.WriteCloser, error)
+	// DecryptStream will return a reader decrypting the ciphertext read from r with aad using a matching streaming
+	// AEAD primitive in kh key handle, its Read fails if a segment of the ciphertext was tampered with or truncated.
+	// returns:
+	// 		io.Reader of the plaintext
+	//		error in case of errors
+	DecryptStream(r io.Reader, aad []byte, kh interface{}) (io.Reader, error)
 	// Sign will sign msg using a matching signature primitive in kh key handle
 	// returns:
 	// 		signature in []byte
@@ -38,4 +53,108 @@ type Crypto interface {
 	// VerifyMAC determines if mac is a correct authentication code (MAC) for data
 	// using a matching MAC primitive in kh key handle and returns nil if so, otherwise it returns an error.
 	VerifyMAC(mac, data []byte, kh interface{}) error
+	// WrapKey will wrap cek for the recipient public key recPubKey, with a key encryption key derived using ECDH-ES
+	// and Concat KDF (with apu and apv as party info) followed by AES key wrap. If the sender key handle is set with
+	// the WithSender() option, the key encryption key is derived using ECDH-1PU instead (authenticated encryption).
+	// returns:
+	// 		the RecipientWrappedKey containing the wrapped cek and the ephemeral public key
+	//		error in case of errors
+	WrapKey(cek, apu, apv []byte, recPubKey *PublicKey, opts ...WrapKeyOpts) (*RecipientWrappedKey, error)
+	// UnwrapKey will unwrap the cek of recWK using the recipient private key in kh key handle. A key wrapped with
+	// ECDH-1PU requires the public key of the sender set with the WithSender() option.
+	// returns:
+	// 		the unwrapped cek in []byte
+	//		error in case of errors
+	UnwrapKey(recWK *RecipientWrappedKey, kh interface{}, opts ...WrapKeyOpts) ([]byte, error)
+	// Easy will seal payload with nonce for the X25519 public key theirPub (NaCl crypto_box), using the ED25519
+	// private key in kh key handle converted to X25519
+	// returns:
+	// 		the sealed payload in []byte
+	//		error in case of errors
+	Easy(payload, nonce, theirPub []byte, kh interface{}) ([]byte, error)
+	// EasyOpen will open cipherText sealed with Easy and nonce by the owner of the X25519 public key theirPub, using
+	// the ED25519 private key in kh key handle converted to X25519
+	// returns:
+	// 		the payload in []byte
+	//		error in case of errors
+	EasyOpen(cipherText, nonce, theirPub []byte, kh interface{}) ([]byte, error)
+	// SealOpen will open cipherText anonymously sealed (libsodium crypto_box_seal) for the public key of the ED25519
+	// key in kh key handle converted to X25519, the ephemeral sender public key being prepended to cipherText
+	// returns:
+	// 		the payload in []byte
+	//		error in case of errors
+	SealOpen(cipherText []byte, kh interface{}) ([]byte, error)
+	// DeriveKEK will derive a key encryption key with Concat KDF (with alg and apu as info) from the X25519 key
+	// agreement of the ED25519 private key in kh key handle, converted to X25519, with the X25519 public key theirPub
+	// returns:
+	// 		the key encryption key in []byte
+	//		error in case of errors
+	DeriveKEK(alg, apu, theirPub []byte, kh interface{}) ([]byte, error)
+	// SignMulti will create a BBS+ signature of messages using a matching BBS+ signing primitive in kh key handle
+	// returns:
+	// 		signature in []byte
+	//		error in case of errors
+	SignMulti(messages [][]byte, kh interface{}) ([]byte, error)
+	// VerifyMulti will verify the BBS+ signature of messages using a matching BBS+ primitive in kh public key handle
+	// returns:
+	// 		error in case of errors or nil if signature verification was successful
+	VerifyMulti(messages [][]byte, signature []byte, kh interface{}) error
+	// DeriveProof will create a proof of knowledge of the BBS+ signature of messages which only reveals the messages
+	// at revealedIndexes, using a matching BBS+ primitive in kh public key handle. nonce binds the proof to a single
+	// presentation.
+	// returns:
+	// 		proof in []byte
+	//		error in case of errors
+	DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int, kh interface{}) ([]byte, error)
+	// VerifyProof will verify a proof created by DeriveProof for the revealed messages using a matching BBS+
+	// primitive in kh public key handle
+	// returns:
+	// 		error in case of errors or nil if proof verification was successful
+	VerifyProof(revealedMessages [][]byte, proof, nonce []byte, kh interface{}) error
+}
+
+const (
+	// ECDHESA256KWAlg is the key wrapping algorithm using ECDH-ES and AES-256 key wrap.
+	ECDHESA256KWAlg = "ECDH-ES+A256KW"
+	// ECDH1PUA256KWAlg is the key wrapping algorithm using ECDH-1PU and AES-256 key wrap.
+	ECDH1PUA256KWAlg = "ECDH-1PU+A256KW"
+	// X25519Curve is the PublicKey curve name of X25519 keys.
+	X25519Curve = "X25519"
+)
+
+// RecipientWrappedKey contains the recipient key material required to unwrap a cek.
+type RecipientWrappedKey struct {
+	KID          string    `json:"kid,omitempty"`
+	EncryptedCEK []byte    `json:"encryptedCEK,omitempty"`
+	EPK          PublicKey `json:"epk,omitempty"`
+	Alg          string    `json:"alg,omitempty"`
+	APU          []byte    `json:"apu,omitempty"`
+	APV          []byte    `json:"apv,omitempty"`
+}
+
+// PublicKey is an elliptic curve public key, the Curve name is one of NIST_P256, NIST_P384 or NIST_P521 (or
+// their P-256, P-384 and P-521 aliases), or X25519Curve for X25519 keys whose raw 32 bytes key is in X.
+type PublicKey struct {
+	KID   string `json:"kid,omitempty"`
+	X     []byte `json:"x,omitempty"`
+	Y     []byte `json:"y,omitempty"`
+	Curve string `json:"curve,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
+// WrapKeyOptions holds the options of WrapKey and UnwrapKey.
+type WrapKeyOptions struct {
+	// SenderKey is the sender key handle for WrapKey, or the sender *PublicKey for UnwrapKey.
+	SenderKey interface{}
+}
+
+// WrapKeyOpts are the WrapKey and UnwrapKey options.
+type WrapKeyOpts func(opts *WrapKeyOptions)
+
+// WithSender option sets the sender key to use ECDH-1PU: the sender key handle for WrapKey and the sender
+// *PublicKey for UnwrapKey.
+func WithSender(senderKey interface{}) WrapKeyOpts {
+	return func(opts *WrapKeyOptions) {
+		opts.SenderKey = senderKey
+	}
 }
@@ -0,0 +1,371 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package remotecrypto provides a crypto.Crypto executing the crypto operations with the keys of a remote key
+// server, the key handles are the *remotekms.KeyHandle references returned by remotekms.RemoteKMS.
+package remotecrypto
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/remotekms"
+)
+
+var (
+	errBadKeyHandleFormat = errors.New("bad key handle format")
+	// the key server protocol exchanges whole messages, it can't stream
+	errStreamingNotSupported = errors.New("streaming encryption is not supported by the key server")
+)
+
+// Crypto implements crypto.Crypto by sending the crypto operations to a key server.
+type Crypto struct {
+	transport remotekms.Transport
+}
+
+// New creates a Crypto instance executing the crypto operations with the key server reached through transport.
+func New(transport remotekms.Transport) *Crypto {
+	return &Crypto{transport: transport}
+}
+
+// Encrypt will encrypt msg and aad with the key server key referenced by kh, it returns the ciphertext and nonce.
+func (c *Crypto) Encrypt(msg, aad []byte, kh interface{}) ([]byte, []byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := c.transport.Send(&remotekms.Request{Op: remotekms.EncryptOp, KeyID: keyID, Message: msg, AAD: aad})
+	if err != nil {
+		return nil, nil, fmt.Errorf("encrypt msg: %w", err)
+	}
+
+	return resp.Data, resp.Nonce, nil
+}
+
+// Decrypt will decrypt cipher with aad and nonce with the key server key referenced by kh.
+func (c *Crypto) Decrypt(cipher, nonce, aad []byte, kh interface{}) ([]byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport.Send(&remotekms.Request{
+		Op:         remotekms.DecryptOp,
+		KeyID:      keyID,
+		Ciphertext: cipher,
+		Nonce:      nonce,
+		AAD:        aad,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decrypt cipher: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// EncryptStream is not supported by the key server, it returns an error.
+func (c *Crypto) EncryptStream(w io.Writer, aad []byte, kh interface{}) (io.WriteCloser, error) {
+	return nil, errStreamingNotSupported
+}
+
+// DecryptStream is not supported by the key server, it returns an error.
+func (c *Crypto) DecryptStream(r io.Reader, aad []byte, kh interface{}) (io.Reader, error) {
+	return nil, errStreamingNotSupported
+}
+
+// Sign will sign msg with the key server key referenced by kh.
+func (c *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport.Send(&remotekms.Request{Op: remotekms.SignOp, KeyID: keyID, Message: msg})
+	if err != nil {
+		return nil, fmt.Errorf("sign msg: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// Verify will verify sig signature of msg with the public key of the key server key referenced by kh.
+func (c *Crypto) Verify(sig, msg []byte, kh interface{}) error {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.transport.Send(&remotekms.Request{Op: remotekms.VerifyOp, KeyID: keyID, Message: msg, Signature: sig})
+	if err != nil {
+		return fmt.Errorf("verify msg: %w", err)
+	}
+
+	return nil
+}
+
+// ComputeMAC computes the message authentication code (MAC) of data with the key server key referenced by kh.
+func (c *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport.Send(&remotekms.Request{Op: remotekms.ComputeMACOp, KeyID: keyID, Message: data})
+	if err != nil {
+		return nil, fmt.Errorf("compute MAC: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// VerifyMAC determines if mac is the MAC of data with the key server key referenced by kh.
+func (c *Crypto) VerifyMAC(mac, data []byte, kh interface{}) error {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.transport.Send(&remotekms.Request{Op: remotekms.VerifyMACOp, KeyID: keyID, Message: data, MAC: mac})
+	if err != nil {
+		return fmt.Errorf("verify MAC: %w", err)
+	}
+
+	return nil
+}
+
+// WrapKey will wrap cek for recPubKey, with ECDH-1PU if the key server key handle of the sender is set with the
+// crypto.WithSender() option.
+func (c *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *crypto.PublicKey,
+	opts ...crypto.WrapKeyOpts) (*crypto.RecipientWrappedKey, error) {
+	pOpts := &crypto.WrapKeyOptions{}
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	req := &remotekms.Request{Op: remotekms.WrapKeyOp, CEK: cek, APU: apu, APV: apv, RecipientPubKey: recPubKey}
+
+	if pOpts.SenderKey != nil {
+		senderKeyID, err := keyIDOf(pOpts.SenderKey)
+		if err != nil {
+			return nil, fmt.Errorf("wrapKey: sender key: %w", err)
+		}
+
+		req.SenderKeyID = senderKeyID
+	}
+
+	resp, err := c.transport.Send(req)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
+	return resp.WrappedKey, nil
+}
+
+// UnwrapKey will unwrap the cek of recWK with the key server key referenced by kh, ECDH-1PU wrapped keys require
+// the sender *crypto.PublicKey set with the crypto.WithSender() option.
+func (c *Crypto) UnwrapKey(recWK *crypto.RecipientWrappedKey, kh interface{},
+	opts ...crypto.WrapKeyOpts) ([]byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	pOpts := &crypto.WrapKeyOptions{}
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	req := &remotekms.Request{Op: remotekms.UnwrapKeyOp, KeyID: keyID, WrappedKey: recWK}
+
+	if pOpts.SenderKey != nil {
+		senderPubKey, ok := pOpts.SenderKey.(*crypto.PublicKey)
+		if !ok {
+			return nil, errors.New("unwrapKey: sender key is not a public key")
+		}
+
+		req.SenderPubKey = senderPubKey
+	}
+
+	resp, err := c.transport.Send(req)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// Easy will seal payload with nonce for theirPub with the key server key referenced by kh.
+func (c *Crypto) Easy(payload, nonce, theirPub []byte, kh interface{}) ([]byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport.Send(&remotekms.Request{
+		Op:          remotekms.EasyOp,
+		KeyID:       keyID,
+		Message:     payload,
+		Nonce:       nonce,
+		TheirPubKey: theirPub,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("easy: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// EasyOpen will open cipherText sealed with nonce by the owner of theirPub with the key server key referenced by kh.
+func (c *Crypto) EasyOpen(cipherText, nonce, theirPub []byte, kh interface{}) ([]byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport.Send(&remotekms.Request{
+		Op:          remotekms.EasyOpenOp,
+		KeyID:       keyID,
+		Ciphertext:  cipherText,
+		Nonce:       nonce,
+		TheirPubKey: theirPub,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("easyOpen: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// SealOpen will open cipherText anonymously sealed for the key server key referenced by kh.
+func (c *Crypto) SealOpen(cipherText []byte, kh interface{}) ([]byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport.Send(&remotekms.Request{Op: remotekms.SealOpenOp, KeyID: keyID, Ciphertext: cipherText})
+	if err != nil {
+		return nil, fmt.Errorf("sealOpen: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// DeriveKEK will derive a key encryption key from the key agreement of the key server key referenced by kh with
+// theirPub.
+func (c *Crypto) DeriveKEK(alg, apu, theirPub []byte, kh interface{}) ([]byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport.Send(&remotekms.Request{
+		Op:          remotekms.DeriveKEKOp,
+		KeyID:       keyID,
+		Alg:         alg,
+		APU:         apu,
+		TheirPubKey: theirPub,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("deriveKEK: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// SignMulti will create a BBS+ signature of messages with the key server key referenced by kh.
+func (c *Crypto) SignMulti(messages [][]byte, kh interface{}) ([]byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport.Send(&remotekms.Request{Op: remotekms.SignMultiOp, KeyID: keyID, Messages: messages})
+	if err != nil {
+		return nil, fmt.Errorf("BBS+ sign msg: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// VerifyMulti will verify the BBS+ signature of messages with the key server key referenced by kh.
+func (c *Crypto) VerifyMulti(messages [][]byte, bbsSignature []byte, kh interface{}) error {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.transport.Send(&remotekms.Request{
+		Op:        remotekms.VerifyMultiOp,
+		KeyID:     keyID,
+		Messages:  messages,
+		Signature: bbsSignature,
+	})
+	if err != nil {
+		return fmt.Errorf("BBS+ verify msg: %w", err)
+	}
+
+	return nil
+}
+
+// DeriveProof will create a proof of knowledge of the BBS+ signature of messages revealing the messages at
+// revealedIndexes only, with the key server key referenced by kh.
+func (c *Crypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int,
+	kh interface{}) ([]byte, error) {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.transport.Send(&remotekms.Request{
+		Op:              remotekms.DeriveProofOp,
+		KeyID:           keyID,
+		Messages:        messages,
+		Signature:       bbsSignature,
+		Nonce:           nonce,
+		RevealedIndexes: revealedIndexes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("BBS+ derive proof msg: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// VerifyProof will verify a BBS+ proof of the revealed messages with the key server key referenced by kh.
+func (c *Crypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byte, kh interface{}) error {
+	keyID, err := keyIDOf(kh)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.transport.Send(&remotekms.Request{
+		Op:       remotekms.VerifyProofOp,
+		KeyID:    keyID,
+		Messages: revealedMessages,
+		Proof:    proof,
+		Nonce:    nonce,
+	})
+	if err != nil {
+		return fmt.Errorf("BBS+ verify proof msg: %w", err)
+	}
+
+	return nil
+}
+
+func keyIDOf(kh interface{}) (string, error) {
+	keyHandle, ok := kh.(*remotekms.KeyHandle)
+	if !ok || keyHandle == nil {
+		return "", errBadKeyHandleFormat
+	}
+
+	return keyHandle.KeyID, nil
+}
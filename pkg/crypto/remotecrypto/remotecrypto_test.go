@@ -0,0 +1,260 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotecrypto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/remotekms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/remotekms/keyserver"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestCrypto_EncryptDecrypt(t *testing.T) {
+	km, c := newRemoteKMSAndCrypto(t)
+	msg, aad := []byte("lorem ipsum"), []byte("aad")
+
+	_, kh, err := km.Create(kms.AES256GCMType)
+	require.NoError(t, err)
+
+	cipherText, nonce, err := c.Encrypt(msg, aad, kh)
+	require.NoError(t, err)
+
+	plainText, err := c.Decrypt(cipherText, nonce, aad, kh)
+	require.NoError(t, err)
+	require.Equal(t, msg, plainText)
+
+	_, err = c.Decrypt(cipherText, nonce, []byte("other aad"), kh)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "decrypt cipher")
+
+	_, _, err = c.Encrypt(msg, aad, &remotekms.KeyHandle{KeyID: "unknown"})
+	require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+}
+
+func TestCrypto_EncryptDecryptStream(t *testing.T) {
+	_, c := newRemoteKMSAndCrypto(t)
+
+	_, err := c.EncryptStream(nil, nil, &remotekms.KeyHandle{KeyID: "key"})
+	require.Equal(t, errStreamingNotSupported, err)
+
+	_, err = c.DecryptStream(nil, nil, &remotekms.KeyHandle{KeyID: "key"})
+	require.Equal(t, errStreamingNotSupported, err)
+}
+
+func TestCrypto_SignVerify(t *testing.T) {
+	km, c := newRemoteKMSAndCrypto(t)
+	msg := []byte("lorem ipsum")
+
+	for _, kt := range []kms.KeyType{kms.ED25519Type, kms.ECDSAP256TypeIEEEP1363, kms.ECDSASecp256k1TypeDER} {
+		_, kh, err := km.Create(kt)
+		require.NoError(t, err)
+
+		sig, err := c.Sign(msg, kh)
+		require.NoError(t, err)
+
+		require.NoError(t, c.Verify(sig, msg, kh))
+
+		err = c.Verify(sig, []byte("other msg"), kh)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "verify msg")
+	}
+
+	_, err := c.Sign(msg, &remotekms.KeyHandle{KeyID: "unknown"})
+	require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+}
+
+func TestCrypto_ComputeVerifyMAC(t *testing.T) {
+	km, c := newRemoteKMSAndCrypto(t)
+	data := []byte("lorem ipsum")
+
+	_, kh, err := km.Create(kms.HMACSHA256Tag256Type)
+	require.NoError(t, err)
+
+	mac, err := c.ComputeMAC(data, kh)
+	require.NoError(t, err)
+
+	require.NoError(t, c.VerifyMAC(mac, data, kh))
+
+	err = c.VerifyMAC(mac, []byte("other data"), kh)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "verify MAC")
+
+	_, err = c.ComputeMAC(data, &remotekms.KeyHandle{KeyID: "unknown"})
+	require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+}
+
+func TestCrypto_WrapUnwrapKey(t *testing.T) {
+	km, c := newRemoteKMSAndCrypto(t)
+	cek, apu, apv := []byte("0123456789abcdef0123456789abcdef"), []byte("sender"), []byte("recipient")
+
+	recKH, recPubKey := newX25519Key(t, km)
+
+	t.Run("wrap and unwrap key with ECDH-ES", func(t *testing.T) {
+		wk, err := c.WrapKey(cek, apu, apv, recPubKey)
+		require.NoError(t, err)
+		require.Equal(t, crypto.ECDHESA256KWAlg, wk.Alg)
+
+		key, err := c.UnwrapKey(wk, recKH)
+		require.NoError(t, err)
+		require.Equal(t, cek, key)
+	})
+
+	t.Run("wrap and unwrap key with ECDH-1PU", func(t *testing.T) {
+		senderKH, senderPubKey := newX25519Key(t, km)
+
+		wk, err := c.WrapKey(cek, apu, apv, recPubKey, crypto.WithSender(senderKH))
+		require.NoError(t, err)
+		require.Equal(t, crypto.ECDH1PUA256KWAlg, wk.Alg)
+
+		key, err := c.UnwrapKey(wk, recKH, crypto.WithSender(senderPubKey))
+		require.NoError(t, err)
+		require.Equal(t, cek, key)
+
+		_, err = c.UnwrapKey(wk, recKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unwrapKey")
+
+		_, err = c.UnwrapKey(wk, recKH, crypto.WithSender(senderKH))
+		require.EqualError(t, err, "unwrapKey: sender key is not a public key")
+
+		_, err = c.WrapKey(cek, apu, apv, recPubKey, crypto.WithSender("bad key handle"))
+		require.EqualError(t, err, "wrapKey: sender key: "+errBadKeyHandleFormat.Error())
+	})
+
+	t.Run("wrap key without recipient key", func(t *testing.T) {
+		_, err := c.WrapKey(cek, apu, apv, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "recipient public key is empty")
+	})
+}
+
+func TestCrypto_BBS(t *testing.T) {
+	km, c := newRemoteKMSAndCrypto(t)
+	messages := [][]byte{[]byte("message 1"), []byte("message 2"), []byte("message 3")}
+	nonce := []byte("nonce")
+
+	_, kh, err := km.Create(kms.BLS12381G2Type)
+	require.NoError(t, err)
+
+	sig, err := c.SignMulti(messages, kh)
+	require.NoError(t, err)
+
+	require.NoError(t, c.VerifyMulti(messages, sig, kh))
+
+	err = c.VerifyMulti(messages[1:], sig, kh)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "BBS+ verify msg")
+
+	proof, err := c.DeriveProof(messages, sig, nonce, []int{0, 2}, kh)
+	require.NoError(t, err)
+
+	require.NoError(t, c.VerifyProof([][]byte{messages[0], messages[2]}, proof, nonce, kh))
+
+	err = c.VerifyProof([][]byte{messages[0], messages[1]}, proof, nonce, kh)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "BBS+ verify proof msg")
+
+	_, err = c.DeriveProof(messages[1:], sig, nonce, []int{0}, kh)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "BBS+ derive proof msg")
+
+	_, err = c.SignMulti(messages, &remotekms.KeyHandle{KeyID: "unknown"})
+	require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+}
+
+func TestCrypto_BadKeyHandle(t *testing.T) {
+	_, c := newRemoteKMSAndCrypto(t)
+
+	for _, kh := range []interface{}{"bad key handle", (*remotekms.KeyHandle)(nil)} {
+		_, _, err := c.Encrypt(nil, nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		_, err = c.Decrypt(nil, nil, nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		_, err = c.Sign(nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		err = c.Verify(nil, nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		_, err = c.ComputeMAC(nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		err = c.VerifyMAC(nil, nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		_, err = c.UnwrapKey(nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		_, err = c.SignMulti(nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		err = c.VerifyMulti(nil, nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		_, err = c.DeriveProof(nil, nil, nil, nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		err = c.VerifyProof(nil, nil, nil, kh)
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+	}
+}
+
+func newRemoteKMSAndCrypto(t *testing.T) (*remotekms.RemoteKMS, *Crypto) {
+	t.Helper()
+
+	km, err := localkms.New("local-lock://test/key/uri", &kmsProvider{
+		storage:    mem.NewProvider(),
+		secretLock: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	// the in-process key server is its own transport
+	srv := keyserver.New(km, c)
+
+	return remotekms.New(srv), New(srv)
+}
+
+func newX25519Key(t *testing.T, km kms.KeyManager) (interface{}, *crypto.PublicKey) {
+	t.Helper()
+
+	keyID, pubKey, err := km.CreateAndExportPubKeyBytes(kms.X25519Type)
+	require.NoError(t, err)
+
+	kh, err := km.Get(keyID)
+	require.NoError(t, err)
+
+	return kh, &crypto.PublicKey{KID: keyID, X: pubKey, Curve: crypto.X25519Curve, Type: "OKP"}
+}
+
+type kmsProvider struct {
+	storage    storage.Provider
+	secretLock secretlock.Service
+}
+
+func (p *kmsProvider) StorageProvider() storage.Provider {
+	return p.storage
+}
+
+func (p *kmsProvider) SecretLock() secretlock.Service {
+	return p.secretLock
+}
@@ -13,14 +13,24 @@ package tinkcrypto
 import (
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/core/primitiveset"
 	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/mac"
 	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/streamingaead"
 	aeadsubtle "github.com/google/tink/go/subtle/aead"
 	"golang.org/x/crypto/chacha20poly1305"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs"
+	bbsapi "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs/api"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+
+	// register the key managers of the secp256k1 signature keys and of the X25519 key agreement keys.
+	_ "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/secp256k1"
+	_ "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/x25519"
 )
 
 var errBadKeyHandleFormat = errors.New("bad key handle format")
@@ -40,9 +50,9 @@ func New() (*Crypto, error) {
 
 // Encrypt will encrypt msg using the implementation's corresponding encryption key and primitive in kh
 func (t *Crypto) Encrypt(msg, aad []byte, kh interface{}) ([]byte, []byte, error) {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return nil, nil, errBadKeyHandleFormat
+	keyHandle, err := authorizedKeyHandle(kh, kms.EncryptOp, "")
+	if err != nil {
+		return nil, nil, err
 	}
 
 	a, err := aead.New(keyHandle)
@@ -86,9 +96,9 @@ func nonceSize(ps *primitiveset.PrimitiveSet) int {
 
 // Decrypt will decrypt cipher using the implementation's corresponding encryption key referenced by kh
 func (t *Crypto) Decrypt(cipher, nonce, aad []byte, kh interface{}) ([]byte, error) {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return nil, errBadKeyHandleFormat
+	keyHandle, err := authorizedKeyHandle(kh, kms.DecryptOp, "")
+	if err != nil {
+		return nil, err
 	}
 
 	a, err := aead.New(keyHandle)
@@ -115,11 +125,53 @@ func (t *Crypto) Decrypt(cipher, nonce, aad []byte, kh interface{}) ([]byte, err
 	return pt, nil
 }
 
+// EncryptStream will return a writer encrypting the data written to it with aad into w using the implementation's
+// corresponding streaming AEAD key referenced by kh, the writer must be closed to write the last segment.
+func (t *Crypto) EncryptStream(w io.Writer, aad []byte, kh interface{}) (io.WriteCloser, error) {
+	keyHandle, err := authorizedKeyHandle(kh, kms.EncryptOp, "")
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := streamingaead.New(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("create new streaming aead: %w", err)
+	}
+
+	ew, err := a.NewEncryptingWriter(w, aad)
+	if err != nil {
+		return nil, fmt.Errorf("create encrypting writer: %w", err)
+	}
+
+	return ew, nil
+}
+
+// DecryptStream will return a reader decrypting the ciphertext read from r with aad using the implementation's
+// corresponding streaming AEAD key referenced by kh.
+func (t *Crypto) DecryptStream(r io.Reader, aad []byte, kh interface{}) (io.Reader, error) {
+	keyHandle, err := authorizedKeyHandle(kh, kms.DecryptOp, "")
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := streamingaead.New(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("create new streaming aead: %w", err)
+	}
+
+	dr, err := a.NewDecryptingReader(r, aad)
+	if err != nil {
+		return nil, fmt.Errorf("create decrypting reader: %w", err)
+	}
+
+	return dr, nil
+}
+
 // Sign will sign msg using the implementation's corresponding signing key referenced by kh
 func (t *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return nil, errBadKeyHandleFormat
+	keyHandle, err := authorizedKeyHandle(kh, kms.SignOp, "")
+	if err != nil {
+		return nil, err
 	}
 
 	signer, err := signature.NewSigner(keyHandle)
@@ -137,9 +189,9 @@ func (t *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
 
 // Verify will verify sig signature of msg using the implementation's corresponding signing key referenced by kh
 func (t *Crypto) Verify(sig, msg []byte, kh interface{}) error {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return errBadKeyHandleFormat
+	keyHandle, err := verificationKeyHandle(kh, kms.VerifyOp)
+	if err != nil {
+		return err
 	}
 
 	verifier, err := signature.NewVerifier(keyHandle)
@@ -158,9 +210,9 @@ func (t *Crypto) Verify(sig, msg []byte, kh interface{}) error {
 // ComputeMAC computes message authentication code (MAC) for code data
 // using a matching MAC primitive in kh key handle
 func (t *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return nil, errBadKeyHandleFormat
+	keyHandle, err := authorizedKeyHandle(kh, kms.ComputeMACOp, "")
+	if err != nil {
+		return nil, err
 	}
 
 	macPrimitive, err := mac.New(keyHandle)
@@ -174,9 +226,9 @@ func (t *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
 // VerifyMAC determines if mac is a correct authentication code (MAC) for data
 // using a matching MAC primitive in kh key handle and returns nil if so, otherwise it returns an error.
 func (t *Crypto) VerifyMAC(macBytes, data []byte, kh interface{}) error {
-	keyHandle, ok := kh.(*keyset.Handle)
-	if !ok {
-		return errBadKeyHandleFormat
+	keyHandle, err := authorizedKeyHandle(kh, kms.VerifyMACOp, "")
+	if err != nil {
+		return err
 	}
 
 	macPrimitive, err := mac.New(keyHandle)
@@ -186,3 +238,122 @@ func (t *Crypto) VerifyMAC(macBytes, data []byte, kh interface{}) error {
 
 	return macPrimitive.VerifyMAC(macBytes, data)
 }
+
+// SignMulti will create a BBS+ signature of messages using a matching BBS+ signing primitive in kh key handle
+func (t *Crypto) SignMulti(messages [][]byte, kh interface{}) ([]byte, error) {
+	keyHandle, err := authorizedKeyHandle(kh, kms.SignOp, "")
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := bbs.NewSigner(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("create new BBS+ signer: %w", err)
+	}
+
+	s, err := signer.Sign(messages)
+	if err != nil {
+		return nil, fmt.Errorf("BBS+ sign msg: %w", err)
+	}
+
+	return s, nil
+}
+
+// VerifyMulti will verify the BBS+ signature of messages using a matching BBS+ primitive in kh public key handle
+func (t *Crypto) VerifyMulti(messages [][]byte, bbsSignature []byte, kh interface{}) error {
+	verifier, err := newBBSVerifier(kh, kms.VerifyOp)
+	if err != nil {
+		return err
+	}
+
+	err = verifier.Verify(messages, bbsSignature)
+	if err != nil {
+		err = fmt.Errorf("BBS+ verify msg: %w", err)
+	}
+
+	return err
+}
+
+// DeriveProof will create a proof of knowledge of the BBS+ signature of messages which only reveals the messages at
+// revealedIndexes, using a matching BBS+ primitive in kh public key handle
+func (t *Crypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int,
+	kh interface{}) ([]byte, error) {
+	verifier, err := newBBSVerifier(kh, kms.DeriveProofOp)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := verifier.DeriveProof(messages, bbsSignature, nonce, revealedIndexes)
+	if err != nil {
+		return nil, fmt.Errorf("BBS+ derive proof msg: %w", err)
+	}
+
+	return proof, nil
+}
+
+// VerifyProof will verify a proof created by DeriveProof for the revealed messages using a matching BBS+ primitive
+// in kh public key handle
+func (t *Crypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byte, kh interface{}) error {
+	verifier, err := newBBSVerifier(kh, kms.VerifyOp)
+	if err != nil {
+		return err
+	}
+
+	err = verifier.VerifyProof(revealedMessages, proof, nonce)
+	if err != nil {
+		err = fmt.Errorf("BBS+ verify proof msg: %w", err)
+	}
+
+	return err
+}
+
+func newBBSVerifier(kh interface{}, op kms.KeyOperation) (bbsapi.Verifier, error) {
+	keyHandle, err := verificationKeyHandle(kh, op)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := bbs.NewVerifier(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("create new BBS+ verifier: %w", err)
+	}
+
+	return verifier, nil
+}
+
+// authorizedKeyHandle returns the keyset handle of kh. If kh is the handle of a key with a policy, the operation op
+// with the algorithm alg must be allowed by the policy.
+func authorizedKeyHandle(kh interface{}, op kms.KeyOperation, alg string) (*keyset.Handle, error) {
+	if policyKH, ok := kh.(kms.PolicyKeyHandle); ok {
+		var err error
+
+		kh, err = policyKH.Authorize(op, alg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keyHandle, ok := kh.(*keyset.Handle)
+	if !ok {
+		return nil, errBadKeyHandleFormat
+	}
+
+	return keyHandle, nil
+}
+
+// verificationKeyHandle returns the keyset handle verifying with kh, the handles of the keys with a policy reference
+// private keys which verify with their public keys.
+func verificationKeyHandle(kh interface{}, op kms.KeyOperation) (*keyset.Handle, error) {
+	keyHandle, err := authorizedKeyHandle(kh, op, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := kh.(kms.PolicyKeyHandle); ok {
+		if pubKH, e := keyHandle.Public(); e == nil {
+			return pubKH, nil
+		}
+	}
+
+	return keyHandle, nil
+}
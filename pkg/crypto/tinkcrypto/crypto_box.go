@@ -0,0 +1,181 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"golang.org/x/crypto/nacl/box"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const ed25519PrivateKeyTypeURL = "type.googleapis.com/google.crypto.tink.Ed25519PrivateKey"
+
+var errBoxOpen = errors.New("failed to open box")
+
+// Easy will seal payload with nonce for the X25519 public key theirPub, with the ED25519 private key in kh converted
+// to X25519.
+func (t *Crypto) Easy(payload, nonce, theirPub []byte, kh interface{}) ([]byte, error) {
+	priv, _, err := curve25519Key(kh, kms.WrapKeyOp)
+	if err != nil {
+		return nil, err
+	}
+
+	var theirPubBytes [cryptoutil.Curve25519KeySize]byte
+
+	copy(theirPubBytes[:], theirPub)
+
+	var nonceBytes [cryptoutil.NonceSize]byte
+
+	copy(nonceBytes[:], nonce)
+
+	return box.Seal(nil, payload, &nonceBytes, &theirPubBytes, priv), nil
+}
+
+// EasyOpen will open cipherText sealed with Easy by the owner of theirPub, with the ED25519 private key in kh
+// converted to X25519.
+func (t *Crypto) EasyOpen(cipherText, nonce, theirPub []byte, kh interface{}) ([]byte, error) {
+	priv, _, err := curve25519Key(kh, kms.UnwrapKeyOp)
+	if err != nil {
+		return nil, err
+	}
+
+	var theirPubBytes [cryptoutil.Curve25519KeySize]byte
+
+	copy(theirPubBytes[:], theirPub)
+
+	var nonceBytes [cryptoutil.NonceSize]byte
+
+	copy(nonceBytes[:], nonce)
+
+	out, ok := box.Open(nil, cipherText, &nonceBytes, &theirPubBytes, priv)
+	if !ok {
+		return nil, errBoxOpen
+	}
+
+	return out, nil
+}
+
+// SealOpen will open cipherText anonymously sealed for the ED25519 key in kh converted to X25519, cipherText starts
+// with the ephemeral public key of the sender.
+func (t *Crypto) SealOpen(cipherText []byte, kh interface{}) ([]byte, error) {
+	if len(cipherText) < cryptoutil.Curve25519KeySize {
+		return nil, errors.New("sealed box too short")
+	}
+
+	priv, pub, err := curve25519Key(kh, kms.UnwrapKeyOp)
+	if err != nil {
+		return nil, err
+	}
+
+	var epk [cryptoutil.Curve25519KeySize]byte
+
+	copy(epk[:], cipherText[:cryptoutil.Curve25519KeySize])
+
+	nonce, err := cryptoutil.Nonce(epk[:], pub)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := box.Open(nil, cipherText[cryptoutil.Curve25519KeySize:], nonce, &epk, priv)
+	if !ok {
+		return nil, errBoxOpen
+	}
+
+	return out, nil
+}
+
+// DeriveKEK will derive a key encryption key from the key agreement of the ED25519 private key in kh, converted to
+// X25519, with the X25519 public key theirPub.
+func (t *Crypto) DeriveKEK(alg, apu, theirPub []byte, kh interface{}) ([]byte, error) {
+	if theirPub == nil {
+		return nil, cryptoutil.ErrInvalidKey
+	}
+
+	priv, _, err := curve25519Key(kh, kms.DeriveKEKOp)
+	if err != nil {
+		return nil, err
+	}
+
+	theirKey := new([cryptoutil.Curve25519KeySize]byte)
+	copy(theirKey[:], theirPub)
+
+	return cryptoutil.Derive25519KEK(alg, apu, priv, theirKey)
+}
+
+// curve25519Key returns the private and public keys of the ED25519 key in kh, converted to X25519, once the
+// operation op is authorized.
+func curve25519Key(kh interface{}, op kms.KeyOperation) (*[cryptoutil.Curve25519KeySize]byte, []byte, error) {
+	keyHandle, err := authorizedKeyHandle(kh, op, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privKey, err := ed25519PrivateKey(keyHandle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encPriv, err := cryptoutil.SecretEd25519toCurve25519(privKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encPub, err := cryptoutil.PublicEd25519toCurve25519(privKey.Public().(ed25519.PublicKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	priv := new([cryptoutil.Curve25519KeySize]byte)
+	copy(priv[:], encPriv)
+
+	return priv, encPub, nil
+}
+
+// ed25519PrivateKey returns the primary ED25519 private key of kh.
+func ed25519PrivateKey(kh *keyset.Handle) (ed25519.PrivateKey, error) {
+	ks := &keyset.MemReaderWriter{}
+
+	err := insecurecleartextkeyset.Write(kh, ks)
+	if err != nil {
+		return nil, fmt.Errorf("read keyset: %w", err)
+	}
+
+	for _, key := range ks.Keyset.Key {
+		if key.KeyId != ks.Keyset.PrimaryKeyId || key.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		if key.KeyData.TypeUrl != ed25519PrivateKeyTypeURL {
+			return nil, fmt.Errorf("key type not supported for crypto box: %s", key.KeyData.TypeUrl)
+		}
+
+		privKeyProto := new(ed25519pb.Ed25519PrivateKey)
+
+		err = proto.Unmarshal(key.KeyData.Value, privKeyProto)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal ED25519 private key: %w", err)
+		}
+
+		if len(privKeyProto.KeyValue) != ed25519.SeedSize {
+			return nil, errors.New("invalid ED25519 private key")
+		}
+
+		return ed25519.NewKeyFromSeed(privKeyProto.KeyValue), nil
+	}
+
+	return nil, errors.New("primary key not found")
+}
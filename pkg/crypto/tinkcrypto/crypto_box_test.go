@@ -0,0 +1,140 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/box"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+func TestCrypto_Box(t *testing.T) {
+	c := Crypto{}
+
+	kh1, pub1 := createBoxKey(t)
+	kh2, pub2 := createBoxKey(t)
+
+	nonce := []byte("abcdefghijklmnopqrstuvwx")
+	msg := []byte("lorem ipsum dolor sit amet consectetur adipiscing elit ")
+
+	t.Run("easy and easyOpen", func(t *testing.T) {
+		enc, err := c.Easy(msg, nonce, pub2, kh1)
+		require.NoError(t, err)
+
+		dec, err := c.EasyOpen(enc, nonce, pub1, kh2)
+		require.NoError(t, err)
+		require.Equal(t, msg, dec)
+
+		enc[0]++ // garbling
+
+		_, err = c.EasyOpen(enc, nonce, pub1, kh2)
+		require.Equal(t, errBoxOpen, err)
+	})
+
+	t.Run("sealOpen", func(t *testing.T) {
+		epk, esk, err := box.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		sealNonce, err := cryptoutil.Nonce(epk[:], pub1)
+		require.NoError(t, err)
+
+		var recPub [cryptoutil.Curve25519KeySize]byte
+
+		copy(recPub[:], pub1)
+
+		enc := box.Seal(epk[:], msg, sealNonce, &recPub, esk)
+
+		dec, err := c.SealOpen(enc, kh1)
+		require.NoError(t, err)
+		require.Equal(t, msg, dec)
+
+		_, err = c.SealOpen(enc, kh2)
+		require.Equal(t, errBoxOpen, err)
+
+		_, err = c.SealOpen([]byte("Bad message"), kh1)
+		require.EqualError(t, err, "sealed box too short")
+	})
+
+	t.Run("deriveKEK", func(t *testing.T) {
+		kek1, err := c.DeriveKEK([]byte("alg"), []byte("apu"), pub2, kh1)
+		require.NoError(t, err)
+
+		kek2, err := c.DeriveKEK([]byte("alg"), []byte("apu"), pub1, kh2)
+		require.NoError(t, err)
+		require.Equal(t, kek1, kek2)
+
+		_, err = c.DeriveKEK([]byte("alg"), []byte("apu"), nil, kh1)
+		require.Equal(t, cryptoutil.ErrInvalidKey, err)
+	})
+
+	t.Run("key errors", func(t *testing.T) {
+		_, err := c.Easy(msg, nonce, pub2, "bad")
+		require.Equal(t, errBadKeyHandleFormat, err)
+
+		_, err = c.EasyOpen(msg, nonce, pub1, "bad")
+		require.Equal(t, errBadKeyHandleFormat, err)
+
+		_, err = c.SealOpen(msg, "bad")
+		require.Equal(t, errBadKeyHandleFormat, err)
+
+		_, err = c.DeriveKEK(nil, nil, pub1, "bad")
+		require.Equal(t, errBadKeyHandleFormat, err)
+
+		aesKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.Easy(msg, nonce, pub2, aesKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key type not supported for crypto box")
+	})
+
+	t.Run("operations authorized by the key policy", func(t *testing.T) {
+		policyKH := &mockPolicyKeyHandle{kh: kh1}
+
+		enc, err := c.Easy(msg, nonce, pub2, policyKH)
+		require.NoError(t, err)
+
+		dec, err := c.EasyOpen(enc, nonce, pub2, policyKH)
+		require.NoError(t, err)
+		require.Equal(t, msg, dec)
+
+		_, err = c.DeriveKEK([]byte("alg"), nil, pub2, policyKH)
+		require.NoError(t, err)
+
+		require.Equal(t, []kms.KeyOperation{kms.WrapKeyOp, kms.UnwrapKeyOp, kms.DeriveKEKOp}, policyKH.ops)
+
+		errDenied := fmt.Errorf("%w: denied", kms.ErrKeyPolicyViolation)
+
+		_, err = c.SealOpen(enc, &mockPolicyKeyHandle{kh: kh1, err: errDenied})
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+	})
+}
+
+// createBoxKey creates an ED25519 key handle and returns it with its X25519 public key.
+func createBoxKey(t *testing.T) (*keyset.Handle, []byte) {
+	kh, err := keyset.NewHandle(signature.ED25519KeyWithoutPrefixTemplate())
+	require.NoError(t, err)
+
+	privKey, err := ed25519PrivateKey(kh)
+	require.NoError(t, err)
+
+	pub, err := cryptoutil.PublicEd25519toCurve25519(privKey.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+
+	return kh, pub
+}
@@ -7,17 +7,26 @@ SPDX-License-Identifier: Apache-2.0
 package tinkcrypto
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"testing"
 
 	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/mac"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/streamingaead"
 	aeadsubtle "github.com/google/tink/go/subtle/aead"
 	"github.com/stretchr/testify/require"
 	chacha "golang.org/x/crypto/chacha20poly1305"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/secp256k1"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 const testMessage = "test message"
@@ -107,6 +116,67 @@ func TestCrypto_EncryptDecrypt(t *testing.T) {
 	})
 }
 
+func TestCrypto_EncryptDecryptStream(t *testing.T) {
+	kh, err := keyset.NewHandle(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	require.NoError(t, err)
+
+	c := Crypto{}
+	// several segments of 4KB
+	msg := bytes.Repeat([]byte(testMessage), 1000)
+	aad := []byte("some additional data")
+
+	ct := new(bytes.Buffer)
+
+	w, err := c.EncryptStream(ct, aad, kh)
+	require.NoError(t, err)
+
+	_, err = w.Write(msg)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	t.Run("decrypt stream", func(t *testing.T) {
+		r, err := c.DecryptStream(bytes.NewReader(ct.Bytes()), aad, kh)
+		require.NoError(t, err)
+
+		plainText, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, msg, plainText)
+	})
+
+	t.Run("decrypt truncated or tampered stream - should fail", func(t *testing.T) {
+		r, err := c.DecryptStream(bytes.NewReader(ct.Bytes()[:ct.Len()-100]), aad, kh)
+		require.NoError(t, err)
+
+		_, err = ioutil.ReadAll(r)
+		require.Error(t, err)
+
+		r, err = c.DecryptStream(bytes.NewReader(ct.Bytes()), []byte("other aad"), kh)
+		require.NoError(t, err)
+
+		_, err = ioutil.ReadAll(r)
+		require.Error(t, err)
+	})
+
+	t.Run("bad key handles - should fail", func(t *testing.T) {
+		aeadKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.EncryptStream(ct, aad, aeadKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create new streaming aead")
+
+		_, err = c.DecryptStream(ct, aad, aeadKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create new streaming aead")
+
+		_, err = c.EncryptStream(ct, aad, "bad key handle")
+		require.True(t, errors.Is(err, errBadKeyHandleFormat))
+
+		_, err = c.DecryptStream(ct, aad, "bad key handle")
+		require.True(t, errors.Is(err, errBadKeyHandleFormat))
+	})
+}
+
 func TestCrypto_SignVerify(t *testing.T) {
 	t.Run("test with Ed25519 signature", func(t *testing.T) {
 		kh, err := keyset.NewHandle(signature.ED25519KeyTemplate())
@@ -165,6 +235,90 @@ func TestCrypto_SignVerify(t *testing.T) {
 		err = c.Verify(s, msg, badKH)
 		require.Error(t, err)
 	})
+
+	t.Run("test with secp256k1 signature", func(t *testing.T) {
+		for _, kt := range []*tinkpb.KeyTemplate{secp256k1.DERKeyTemplate(), secp256k1.IEEEP1363KeyTemplate()} {
+			kh, err := keyset.NewHandle(kt)
+			require.NoError(t, err)
+
+			c := Crypto{}
+			msg := []byte(testMessage)
+			s, err := c.Sign(msg, kh)
+			require.NoError(t, err)
+
+			// get corresponding public key handle to verify
+			pubKH, err := kh.Public()
+			require.NoError(t, err)
+
+			err = c.Verify(s, msg, pubKH)
+			require.NoError(t, err)
+
+			// verify a different message - should fail
+			err = c.Verify(s, []byte("other message"), pubKH)
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestCrypto_BBS(t *testing.T) {
+	c := Crypto{}
+	messages := [][]byte{[]byte("message 1"), []byte("message 2"), []byte("message 3")}
+	nonce := []byte("nonce")
+
+	kh, err := keyset.NewHandle(bbs.BLS12381G2KeyTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	t.Run("test BBS+ sign, verify, derive and verify proof", func(t *testing.T) {
+		s, err := c.SignMulti(messages, kh)
+		require.NoError(t, err)
+
+		err = c.VerifyMulti(messages, s, pubKH)
+		require.NoError(t, err)
+
+		err = c.VerifyMulti(messages[1:], s, pubKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "BBS+ verify msg")
+
+		proof, err := c.DeriveProof(messages, s, nonce, []int{0, 2}, pubKH)
+		require.NoError(t, err)
+
+		err = c.VerifyProof([][]byte{messages[0], messages[2]}, proof, nonce, pubKH)
+		require.NoError(t, err)
+
+		err = c.VerifyProof([][]byte{messages[0], messages[1]}, proof, nonce, pubKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "BBS+ verify proof msg")
+
+		_, err = c.DeriveProof(messages[1:], s, nonce, []int{0}, pubKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "BBS+ derive proof msg")
+	})
+
+	t.Run("test BBS+ with bad key handles", func(t *testing.T) {
+		_, err := c.SignMulti(messages, "bad key handle")
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		err = c.VerifyMulti(messages, nil, "bad key handle")
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		_, err = c.DeriveProof(messages, nil, nonce, nil, "bad key handle")
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		err = c.VerifyProof(messages, nil, nonce, "bad key handle")
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		// BBS+ primitives are created from BBS+ keys only
+		_, err = c.SignMulti(messages, pubKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create new BBS+ signer")
+
+		err = c.VerifyMulti(messages, nil, kh)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "create new BBS+ verifier")
+	})
 }
 
 func TestCrypto_ComputeMAC(t *testing.T) {
@@ -238,3 +392,92 @@ func TestCrypto_VerifyMAC(t *testing.T) {
 		require.EqualError(t, err, "mac_factory: not a MAC primitive")
 	})
 }
+
+func TestCrypto_PolicyKeyHandle(t *testing.T) {
+	c := Crypto{}
+	msg := []byte(testMessage)
+
+	t.Run("sign and verify with a policy key handle", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+		require.NoError(t, err)
+
+		policyKH := &mockPolicyKeyHandle{kh: kh}
+
+		s, err := c.Sign(msg, policyKH)
+		require.NoError(t, err)
+		require.Equal(t, []kms.KeyOperation{kms.SignOp}, policyKH.ops)
+
+		// the public key of the private key handle verifies the signature
+		err = c.Verify(s, msg, policyKH)
+		require.NoError(t, err)
+		require.Equal(t, []kms.KeyOperation{kms.SignOp, kms.VerifyOp}, policyKH.ops)
+	})
+
+	t.Run("encrypt, decrypt and MAC with a policy key handle", func(t *testing.T) {
+		kh, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		policyKH := &mockPolicyKeyHandle{kh: kh}
+
+		cipherText, nonce, err := c.Encrypt(msg, nil, policyKH)
+		require.NoError(t, err)
+
+		plainText, err := c.Decrypt(cipherText, nonce, nil, policyKH)
+		require.NoError(t, err)
+		require.Equal(t, msg, plainText)
+		require.Equal(t, []kms.KeyOperation{kms.EncryptOp, kms.DecryptOp}, policyKH.ops)
+
+		macKH, err := keyset.NewHandle(mac.HMACSHA256Tag256KeyTemplate())
+		require.NoError(t, err)
+
+		policyKH = &mockPolicyKeyHandle{kh: macKH}
+
+		macBytes, err := c.ComputeMAC(msg, policyKH)
+		require.NoError(t, err)
+
+		err = c.VerifyMAC(macBytes, msg, policyKH)
+		require.NoError(t, err)
+		require.Equal(t, []kms.KeyOperation{kms.ComputeMACOp, kms.VerifyMACOp}, policyKH.ops)
+	})
+
+	t.Run("operations denied by the key policy", func(t *testing.T) {
+		kh, err := keyset.NewHandle(signature.ED25519KeyTemplate())
+		require.NoError(t, err)
+
+		errDenied := fmt.Errorf("%w: denied", kms.ErrKeyPolicyViolation)
+		policyKH := &mockPolicyKeyHandle{kh: kh, err: errDenied}
+
+		_, err = c.Sign(msg, policyKH)
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+
+		err = c.Verify(nil, msg, policyKH)
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+
+		_, _, err = c.Encrypt(msg, nil, policyKH)
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+
+		_, err = c.ComputeMAC(msg, policyKH)
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+	})
+
+	t.Run("policy key handle of a bad key handle", func(t *testing.T) {
+		_, err := c.Sign(msg, &mockPolicyKeyHandle{kh: "bad"})
+		require.Equal(t, errBadKeyHandleFormat, err)
+	})
+}
+
+type mockPolicyKeyHandle struct {
+	kh  interface{}
+	err error
+	ops []kms.KeyOperation
+}
+
+func (m *mockPolicyKeyHandle) Authorize(op kms.KeyOperation, _ string) (interface{}, error) {
+	m.ops = append(m.ops, op)
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.kh, nil
+}
@@ -0,0 +1,366 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/subtle/hybrid"
+	josecipher "github.com/square/go-jose/v3/cipher"
+	"golang.org/x/crypto/curve25519"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	ecdhespb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdhes_aead_go_proto"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const (
+	ecdhesPrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.EcdhesAeadPrivateKey"
+	x25519PrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.X25519PrivateKey"
+	// kekSize is the size of the AES-256 key encryption key
+	kekSize = 32
+)
+
+// WrapKey will wrap cek for the recipient public key recPubKey, using ECDH-ES (or ECDH-1PU if a sender key handle
+// is set with the crypto.WithSender() option), Concat KDF and AES-256 key wrap. recPubKey is either a NIST P-curve
+// key or an X25519 key.
+func (t *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *cryptoapi.PublicKey,
+	opts ...cryptoapi.WrapKeyOpts) (*cryptoapi.RecipientWrappedKey, error) {
+	if recPubKey == nil {
+		return nil, errors.New("wrapKey: recipient public key is empty")
+	}
+
+	pOpts := &cryptoapi.WrapKeyOptions{}
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	ephemeralPriv, epk, err := newEphemeralKey(recPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
+	alg := cryptoapi.ECDHESA256KWAlg
+
+	z, err := deriveZ(ephemeralPriv, recPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
+	if pOpts.SenderKey != nil {
+		senderKH, e := authorizedKeyHandle(pOpts.SenderKey, kms.WrapKeyOp, cryptoapi.ECDH1PUA256KWAlg)
+		if e != nil {
+			return nil, e
+		}
+
+		senderPriv, e := keyAgreementPrivateKey(senderKH)
+		if e != nil {
+			return nil, fmt.Errorf("wrapKey: %w", e)
+		}
+
+		zs, e := deriveZ(senderPriv, recPubKey)
+		if e != nil {
+			return nil, fmt.Errorf("wrapKey: %w", e)
+		}
+
+		alg = cryptoapi.ECDH1PUA256KWAlg
+		z = append(z, zs...)
+	}
+
+	block, err := aes.NewCipher(deriveKEK(alg, z, apu, apv))
+	if err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
+	wk, err := josecipher.KeyWrap(block, cek)
+	if err != nil {
+		return nil, fmt.Errorf("wrapKey: %w", err)
+	}
+
+	return &cryptoapi.RecipientWrappedKey{
+		KID:          recPubKey.KID,
+		EncryptedCEK: wk,
+		EPK:          *epk,
+		Alg:          alg,
+		APU:          apu,
+		APV:          apv,
+	}, nil
+}
+
+// UnwrapKey will unwrap the cek of recWK using the recipient private key in kh key handle. ECDH-1PU wrapped keys
+// require the sender public key set with the crypto.WithSender() option.
+func (t *Crypto) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, kh interface{},
+	opts ...cryptoapi.WrapKeyOpts) ([]byte, error) {
+	if recWK == nil {
+		return nil, errors.New("unwrapKey: RecipientWrappedKey is empty")
+	}
+
+	keyHandle, err := authorizedKeyHandle(kh, kms.UnwrapKeyOp, recWK.Alg)
+	if err != nil {
+		return nil, err
+	}
+
+	pOpts := &cryptoapi.WrapKeyOptions{}
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	recPriv, err := keyAgreementPrivateKey(keyHandle)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	z, err := deriveZ(recPriv, &recWK.EPK)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	switch recWK.Alg {
+	case cryptoapi.ECDHESA256KWAlg:
+	case cryptoapi.ECDH1PUA256KWAlg:
+		senderPubKey, ok := pOpts.SenderKey.(*cryptoapi.PublicKey)
+		if !ok {
+			return nil, errors.New("unwrapKey: sender public key is required for " + cryptoapi.ECDH1PUA256KWAlg)
+		}
+
+		zs, e := deriveZ(recPriv, senderPubKey)
+		if e != nil {
+			return nil, fmt.Errorf("unwrapKey: %w", e)
+		}
+
+		z = append(z, zs...)
+	default:
+		return nil, fmt.Errorf("unwrapKey: unsupported key wrapping algorithm: %s", recWK.Alg)
+	}
+
+	block, err := aes.NewCipher(deriveKEK(recWK.Alg, z, recWK.APU, recWK.APV))
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	cek, err := josecipher.KeyUnwrap(block, recWK.EncryptedCEK)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapKey: %w", err)
+	}
+
+	return cek, nil
+}
+
+// newEphemeralKey generates an ephemeral private key on the curve of recPubKey, it returns it with its public key.
+func newEphemeralKey(recPubKey *cryptoapi.PublicKey) (interface{}, *cryptoapi.PublicKey, error) {
+	if recPubKey.Curve == cryptoapi.X25519Curve {
+		priv := make([]byte, curve25519.ScalarSize)
+
+		_, err := rand.Read(priv)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generate ephemeral key: %w", err)
+		}
+
+		pub, err := curve25519.X25519(priv, curve25519.Basepoint)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generate ephemeral key: %w", err)
+		}
+
+		return x25519PrivateKey(priv), &cryptoapi.PublicKey{X: pub, Curve: cryptoapi.X25519Curve, Type: "OKP"}, nil
+	}
+
+	recPub, err := ecPublicKey(recPubKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	priv, err := ecdsa.GenerateKey(recPub.Curve, rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate ephemeral key: %w", err)
+	}
+
+	return priv, &cryptoapi.PublicKey{
+		X:     priv.PublicKey.X.Bytes(),
+		Y:     priv.PublicKey.Y.Bytes(),
+		Curve: priv.PublicKey.Curve.Params().Name,
+		Type:  "EC",
+	}, nil
+}
+
+// deriveZ computes the shared secret of the private key priv (an *ecdsa.PrivateKey or a x25519PrivateKey) and pub.
+func deriveZ(priv interface{}, pub *cryptoapi.PublicKey) ([]byte, error) {
+	switch pk := priv.(type) {
+	case *ecdsa.PrivateKey:
+		ecPub, err := ecPublicKey(pub)
+		if err != nil {
+			return nil, err
+		}
+
+		return ecdh(pk, ecPub)
+	case x25519PrivateKey:
+		if pub.Curve != cryptoapi.X25519Curve {
+			return nil, errors.New("public key is not on the curve of the private key")
+		}
+
+		return curve25519.X25519(pk, pub.X)
+	default:
+		return nil, fmt.Errorf("unsupported private key type: %T", priv)
+	}
+}
+
+// ecdh computes the shared secret of priv and pub, padded to the size of the curve.
+func ecdh(priv *ecdsa.PrivateKey, pub *ecdsa.PublicKey) ([]byte, error) {
+	if !priv.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, errors.New("public key is not on the curve of the private key")
+	}
+
+	x, _ := priv.Curve.ScalarMult(pub.X, pub.Y, priv.D.Bytes())
+
+	z := make([]byte, (priv.Curve.Params().BitSize+7)/8)
+	xBytes := x.Bytes()
+	copy(z[len(z)-len(xBytes):], xBytes)
+
+	return z, nil
+}
+
+// deriveKEK derives an AES-256 key encryption key from the shared secret z using Concat KDF, as defined in
+// RFC7518 section 4.6.2.
+func deriveKEK(alg string, z, apu, apv []byte) []byte {
+	supPubInfo := make([]byte, 4)
+	binary.BigEndian.PutUint32(supPubInfo, uint32(kekSize)*8)
+
+	reader := josecipher.NewConcatKDF(crypto.SHA256, z, lengthPrefixed([]byte(alg)), lengthPrefixed(apu),
+		lengthPrefixed(apv), supPubInfo, []byte{})
+
+	kek := make([]byte, kekSize)
+	_, _ = reader.Read(kek) //nolint:errcheck // Concat KDF reads never fail
+
+	return kek
+}
+
+func lengthPrefixed(data []byte) []byte {
+	out := make([]byte, len(data)+4)
+	binary.BigEndian.PutUint32(out, uint32(len(data)))
+	copy(out[4:], data)
+
+	return out
+}
+
+func ecPublicKey(pubKey *cryptoapi.PublicKey) (*ecdsa.PublicKey, error) {
+	curve, err := hybrid.GetCurve(pubKey.Curve)
+	if err != nil {
+		return nil, fmt.Errorf("public key curve %s: %w", pubKey.Curve, err)
+	}
+
+	pub := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(pubKey.X),
+		Y:     new(big.Int).SetBytes(pubKey.Y),
+	}
+
+	if !curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, errors.New("public key is not on curve " + pubKey.Curve)
+	}
+
+	return pub, nil
+}
+
+// x25519PrivateKey is a raw X25519 private key.
+type x25519PrivateKey []byte
+
+// keyAgreementPrivateKey returns the primary private key of kh, an *ecdsa.PrivateKey for ECDH-ES keys or a
+// x25519PrivateKey for X25519 and ED25519 keys.
+func keyAgreementPrivateKey(kh *keyset.Handle) (interface{}, error) {
+	ks := &keyset.MemReaderWriter{}
+
+	err := insecurecleartextkeyset.Write(kh, ks)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range ks.Keyset.Key {
+		if key.KeyId != ks.Keyset.PrimaryKeyId || key.Status != tinkpb.KeyStatusType_ENABLED {
+			continue
+		}
+
+		switch key.KeyData.TypeUrl {
+		case ecdhesPrivateKeyTypeURL:
+			privKeyProto := new(ecdhespb.EcdhesAeadPrivateKey)
+
+			err = proto.Unmarshal(key.KeyData.Value, privKeyProto)
+			if err != nil {
+				return nil, err
+			}
+
+			return ecPrivateKey(privKeyProto)
+		case x25519PrivateKeyTypeURL:
+			privKeyProto := new(ed25519pb.Ed25519PrivateKey)
+
+			err = proto.Unmarshal(key.KeyData.Value, privKeyProto)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(privKeyProto.KeyValue) != curve25519.ScalarSize {
+				return nil, errors.New("invalid X25519 private key")
+			}
+
+			return x25519PrivateKey(privKeyProto.KeyValue), nil
+		case ed25519PrivateKeyTypeURL:
+			// the ED25519 keys of the DIDComm messaging are converted to X25519, as for the crypto box
+			priv, e := ed25519PrivateKey(kh)
+			if e != nil {
+				return nil, e
+			}
+
+			encPriv, e := cryptoutil.SecretEd25519toCurve25519(priv)
+			if e != nil {
+				return nil, e
+			}
+
+			return x25519PrivateKey(encPriv), nil
+		default:
+			return nil, fmt.Errorf("key type not supported for key wrapping: %s", key.KeyData.TypeUrl)
+		}
+	}
+
+	return nil, errors.New("primary key not found")
+}
+
+func ecPrivateKey(privKeyProto *ecdhespb.EcdhesAeadPrivateKey) (*ecdsa.PrivateKey, error) {
+	if privKeyProto.PublicKey == nil || privKeyProto.PublicKey.Params == nil ||
+		privKeyProto.PublicKey.Params.KwParams == nil {
+		return nil, errors.New("invalid ECDH-ES private key")
+	}
+
+	curveType := privKeyProto.PublicKey.Params.KwParams.CurveType
+
+	curve, err := hybrid.GetCurve(commonpb.EllipticCurveType_name[int32(curveType)])
+	if err != nil {
+		return nil, err
+	}
+
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(privKeyProto.PublicKey.X),
+			Y:     new(big.Int).SetBytes(privKeyProto.PublicKey.Y),
+		},
+		D: new(big.Int).SetBytes(privKeyProto.KeyValue),
+	}, nil
+}
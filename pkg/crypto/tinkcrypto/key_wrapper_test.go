@@ -0,0 +1,305 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tinkcrypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	josecipher "github.com/square/go-jose/v3/cipher"
+	"github.com/stretchr/testify/require"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdhes"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/x25519"
+)
+
+func TestCrypto_WrapKeyUnwrapKey(t *testing.T) {
+	c := Crypto{}
+	cek := random(t, 32)
+	apu := []byte("sender")
+	apv := []byte("recipient")
+
+	recKH, recPubKey := newECDHESKey(t)
+
+	t.Run("test ECDH-ES key wrapping", func(t *testing.T) {
+		wk, err := c.WrapKey(cek, apu, apv, recPubKey)
+		require.NoError(t, err)
+		require.Equal(t, cryptoapi.ECDHESA256KWAlg, wk.Alg)
+		require.Equal(t, recPubKey.KID, wk.KID)
+		require.Equal(t, apu, wk.APU)
+		require.Equal(t, apv, wk.APV)
+		require.NotEqual(t, cek, wk.EncryptedCEK)
+
+		unwrapped, err := c.UnwrapKey(wk, recKH)
+		require.NoError(t, err)
+		require.Equal(t, cek, unwrapped)
+
+		// a different recipient key can't unwrap the cek
+		otherKH, _ := newECDHESKey(t)
+
+		_, err = c.UnwrapKey(wk, otherKH)
+		require.Error(t, err)
+	})
+
+	t.Run("test ECDH-1PU key wrapping", func(t *testing.T) {
+		senderKH, senderPubKey := newECDHESKey(t)
+
+		wk, err := c.WrapKey(cek, apu, apv, recPubKey, cryptoapi.WithSender(senderKH))
+		require.NoError(t, err)
+		require.Equal(t, cryptoapi.ECDH1PUA256KWAlg, wk.Alg)
+
+		unwrapped, err := c.UnwrapKey(wk, recKH, cryptoapi.WithSender(senderPubKey))
+		require.NoError(t, err)
+		require.Equal(t, cek, unwrapped)
+
+		_, err = c.UnwrapKey(wk, recKH)
+		require.EqualError(t, err, "unwrapKey: sender public key is required for ECDH-1PU+A256KW")
+
+		// the wrong sender can't be authenticated
+		_, otherPubKey := newECDHESKey(t)
+
+		_, err = c.UnwrapKey(wk, recKH, cryptoapi.WithSender(otherPubKey))
+		require.Error(t, err)
+	})
+
+	t.Run("test X25519 key wrapping", func(t *testing.T) {
+		x25519RecKH, x25519RecPubKey := newX25519Key(t)
+		x25519SenderKH, x25519SenderPubKey := newX25519Key(t)
+
+		wk, err := c.WrapKey(cek, apu, apv, x25519RecPubKey)
+		require.NoError(t, err)
+		require.Equal(t, cryptoapi.ECDHESA256KWAlg, wk.Alg)
+		require.Equal(t, cryptoapi.X25519Curve, wk.EPK.Curve)
+		require.Equal(t, "OKP", wk.EPK.Type)
+
+		unwrapped, err := c.UnwrapKey(wk, x25519RecKH)
+		require.NoError(t, err)
+		require.Equal(t, cek, unwrapped)
+
+		wk, err = c.WrapKey(cek, apu, apv, x25519RecPubKey, cryptoapi.WithSender(x25519SenderKH))
+		require.NoError(t, err)
+		require.Equal(t, cryptoapi.ECDH1PUA256KWAlg, wk.Alg)
+
+		unwrapped, err = c.UnwrapKey(wk, x25519RecKH, cryptoapi.WithSender(x25519SenderPubKey))
+		require.NoError(t, err)
+		require.Equal(t, cek, unwrapped)
+
+		// X25519 and NIST P-curve keys can't be mixed
+		_, err = c.WrapKey(cek, apu, apv, recPubKey, cryptoapi.WithSender(x25519SenderKH))
+		require.EqualError(t, err, "wrapKey: public key is not on the curve of the private key")
+
+		_, err = c.WrapKey(cek, apu, apv, x25519RecPubKey, cryptoapi.WithSender(recKH))
+		require.Error(t, err)
+
+		// low order points are rejected
+		_, err = c.WrapKey(cek, apu, apv, &cryptoapi.PublicKey{X: make([]byte, 32), Curve: cryptoapi.X25519Curve})
+		require.Error(t, err)
+	})
+
+	t.Run("test key wrapping with ED25519 keys converted to X25519", func(t *testing.T) {
+		edRecKH, edRecPub := createBoxKey(t)
+		edSenderKH, edSenderPub := createBoxKey(t)
+
+		wk, err := c.WrapKey(cek, apu, apv, &cryptoapi.PublicKey{X: edRecPub, Curve: cryptoapi.X25519Curve})
+		require.NoError(t, err)
+
+		unwrapped, err := c.UnwrapKey(wk, edRecKH)
+		require.NoError(t, err)
+		require.Equal(t, cek, unwrapped)
+
+		wk, err = c.WrapKey(cek, apu, apv, &cryptoapi.PublicKey{X: edRecPub, Curve: cryptoapi.X25519Curve},
+			cryptoapi.WithSender(edSenderKH))
+		require.NoError(t, err)
+
+		unwrapped, err = c.UnwrapKey(wk, edRecKH,
+			cryptoapi.WithSender(&cryptoapi.PublicKey{X: edSenderPub, Curve: cryptoapi.X25519Curve}))
+		require.NoError(t, err)
+		require.Equal(t, cek, unwrapped)
+	})
+
+	t.Run("test unwrap ECDH-ES key wrapped with go-jose", func(t *testing.T) {
+		recPub, err := ecPublicKey(recPubKey)
+		require.NoError(t, err)
+
+		ephemeralPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		kek := josecipher.DeriveECDHES(cryptoapi.ECDHESA256KWAlg, apu, apv, ephemeralPriv, recPub, kekSize)
+
+		block, err := aes.NewCipher(kek)
+		require.NoError(t, err)
+
+		encryptedCEK, err := josecipher.KeyWrap(block, cek)
+		require.NoError(t, err)
+
+		unwrapped, err := c.UnwrapKey(&cryptoapi.RecipientWrappedKey{
+			EncryptedCEK: encryptedCEK,
+			EPK: cryptoapi.PublicKey{
+				X:     ephemeralPriv.X.Bytes(),
+				Y:     ephemeralPriv.Y.Bytes(),
+				Curve: "P-256",
+			},
+			Alg: cryptoapi.ECDHESA256KWAlg,
+			APU: apu,
+			APV: apv,
+		}, recKH)
+		require.NoError(t, err)
+		require.Equal(t, cek, unwrapped)
+	})
+
+	t.Run("test wrap key errors", func(t *testing.T) {
+		_, err := c.WrapKey(cek, apu, apv, nil)
+		require.EqualError(t, err, "wrapKey: recipient public key is empty")
+
+		_, err = c.WrapKey(cek, apu, apv, &cryptoapi.PublicKey{Curve: "unknown"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "public key curve unknown")
+
+		_, err = c.WrapKey(cek, apu, apv, &cryptoapi.PublicKey{X: []byte{1}, Y: []byte{2}, Curve: "NIST_P256"})
+		require.EqualError(t, err, "wrapKey: public key is not on curve NIST_P256")
+
+		_, err = c.WrapKey(cek, apu, apv, recPubKey, cryptoapi.WithSender("bad key handle"))
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		aesKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.WrapKey(cek, apu, apv, recPubKey, cryptoapi.WithSender(aesKH))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key type not supported for key wrapping")
+
+		// the cek must be a multiple of 8 bytes
+		_, err = c.WrapKey([]byte("bad cek"), apu, apv, recPubKey)
+		require.Error(t, err)
+
+		// sender and recipient keys must be on the same curve
+		p384PubKey := ecPublicKeyOf(t, elliptic.P384())
+
+		_, err = c.WrapKey(cek, apu, apv, p384PubKey, cryptoapi.WithSender(recKH))
+		require.EqualError(t, err, "wrapKey: public key is not on the curve of the private key")
+	})
+
+	t.Run("test unwrap key errors", func(t *testing.T) {
+		wk, err := c.WrapKey(cek, apu, apv, recPubKey)
+		require.NoError(t, err)
+
+		_, err = c.UnwrapKey(nil, recKH)
+		require.EqualError(t, err, "unwrapKey: RecipientWrappedKey is empty")
+
+		_, err = c.UnwrapKey(wk, "bad key handle")
+		require.EqualError(t, err, errBadKeyHandleFormat.Error())
+
+		aesKH, err := keyset.NewHandle(aead.AES256GCMKeyTemplate())
+		require.NoError(t, err)
+
+		_, err = c.UnwrapKey(wk, aesKH)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key type not supported for key wrapping")
+
+		badWK := *wk
+		badWK.EPK.Curve = "unknown"
+
+		_, err = c.UnwrapKey(&badWK, recKH)
+		require.Error(t, err)
+
+		badWK = *wk
+		badWK.EPK = *ecPublicKeyOf(t, elliptic.P384())
+
+		_, err = c.UnwrapKey(&badWK, recKH)
+		require.EqualError(t, err, "unwrapKey: public key is not on the curve of the private key")
+
+		badWK = *wk
+		badWK.Alg = "unknown"
+
+		_, err = c.UnwrapKey(&badWK, recKH)
+		require.EqualError(t, err, "unwrapKey: unsupported key wrapping algorithm: unknown")
+
+		badWK = *wk
+		badWK.Alg = cryptoapi.ECDH1PUA256KWAlg
+
+		_, err = c.UnwrapKey(&badWK, recKH, cryptoapi.WithSender(&cryptoapi.PublicKey{Curve: "unknown"}))
+		require.Error(t, err)
+
+		_, err = c.UnwrapKey(&badWK, recKH, cryptoapi.WithSender(ecPublicKeyOf(t, elliptic.P384())))
+		require.EqualError(t, err, "unwrapKey: public key is not on the curve of the private key")
+
+		badWK = *wk
+		badWK.EncryptedCEK = []byte("bad encrypted cek")
+
+		_, err = c.UnwrapKey(&badWK, recKH)
+		require.Error(t, err)
+	})
+}
+
+func newECDHESKey(t *testing.T) (*keyset.Handle, *cryptoapi.PublicKey) {
+	kh, err := keyset.NewHandle(ecdhes.ECDHES256KWAES256GCMKeyTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	buf := new(bytes.Buffer)
+
+	err = pubKH.WriteWithNoSecrets(ecdhes.NewWriter(buf))
+	require.NoError(t, err)
+
+	pubKey := &cryptoapi.PublicKey{}
+
+	err = json.Unmarshal(buf.Bytes(), pubKey)
+	require.NoError(t, err)
+
+	pubKey.KID = "kid"
+
+	return kh, pubKey
+}
+
+func newX25519Key(t *testing.T) (*keyset.Handle, *cryptoapi.PublicKey) {
+	kh, err := keyset.NewHandle(x25519.KeyTemplate())
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	ks := &keyset.MemReaderWriter{}
+	require.NoError(t, insecurecleartextkeyset.Write(pubKH, ks))
+
+	pubKey := new(ed25519pb.Ed25519PublicKey)
+	require.NoError(t, proto.Unmarshal(ks.Keyset.Key[0].KeyData.Value, pubKey))
+
+	return kh, &cryptoapi.PublicKey{KID: "kid", X: pubKey.KeyValue, Curve: cryptoapi.X25519Curve, Type: "OKP"}
+}
+
+func ecPublicKeyOf(t *testing.T, curve elliptic.Curve) *cryptoapi.PublicKey {
+	priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+
+	return &cryptoapi.PublicKey{
+		X:     priv.X.Bytes(),
+		Y:     priv.Y.Bytes(),
+		Curve: curve.Params().Name,
+	}
+}
+
+func random(t *testing.T, size int) []byte {
+	b := make([]byte, size)
+
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+
+	return b
+}
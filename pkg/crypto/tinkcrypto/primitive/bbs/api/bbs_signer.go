@@ -0,0 +1,15 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+// Signer signs a list of messages with a single BBS+ signature. Each message can later be selectively disclosed
+// through a proof of knowledge of the signature derived by a Verifier.
+type Signer interface {
+	// Sign signs messages as a whole, their order matters.
+	// returns the BBS+ signature of messages.
+	Sign(messages [][]byte) ([]byte, error)
+}
@@ -0,0 +1,24 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+// Verifier verifies BBS+ signatures and the proofs of knowledge of BBS+ signatures. Since a proof is derived with the
+// signer's public key only, the holder of a signature derives its proofs through this interface too.
+type Verifier interface {
+	// Verify verifies the BBS+ signature of messages, given in their signing order.
+	// returns an error if signature is invalid.
+	Verify(messages [][]byte, signature []byte) error
+
+	// DeriveProof creates a proof of knowledge of the BBS+ signature of messages which only reveals the messages at
+	// revealedIndexes. nonce binds the proof to a single presentation.
+	// returns the serialized proof.
+	DeriveProof(messages [][]byte, signature, nonce []byte, revealedIndexes []int) ([]byte, error)
+
+	// VerifyProof verifies a proof created by DeriveProof, given the revealed messages in their signing order.
+	// returns an error if proof is invalid.
+	VerifyProof(revealedMessages [][]byte, proof, nonce []byte) error
+}
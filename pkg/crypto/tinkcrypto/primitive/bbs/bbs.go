@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package bbs provides the Tink key managers, key template and primitive factories of BBS+ signature keys over the
+// BLS12-381 curve with public keys in G2, used for privacy-preserving credentials: a BBS+ signature signs a list of
+// messages at once, its holder can then derive proofs of knowledge of the signature which disclose a subset of the
+// messages only.
+//
+// Tink's Ed25519 key protos are reused to serialize the keys since they hold raw key values only: a 32 bytes Fr
+// scalar for private keys and a 96 bytes compressed G2 point for public keys, the key type being given by the key
+// type URL. The primitives are not Tink signature primitives since they sign multiple messages, they're created with
+// the factories of this package:
+//
+//	kh, err := keyset.NewHandle(bbs.BLS12381G2KeyTemplate())
+//	s, err := bbs.NewSigner(kh)
+//	sig, err := s.Sign(messages)
+package bbs
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// TODO - find a better way to setup tink than init.
+// nolint: gochecknoinits
+func init() {
+	// TODO - avoid the tink registry singleton.
+	err := registry.RegisterKeyManager(newBBSSignerKeyManager())
+	if err != nil {
+		panic(fmt.Sprintf("bbs.init() failed: %v", err))
+	}
+
+	err = registry.RegisterKeyManager(newBBSVerifierKeyManager())
+	if err != nil {
+		panic(fmt.Sprintf("bbs.init() failed: %v", err))
+	}
+}
+
+// BLS12381G2KeyTemplate is a KeyTemplate that generates a BBS+ key on the BLS12-381 curve with its public key in G2.
+func BLS12381G2KeyTemplate() *tinkpb.KeyTemplate {
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          bbsSignerTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBBSFactories(t *testing.T) {
+	kh, err := keyset.NewHandle(BLS12381G2KeyTemplate())
+	require.NoError(t, err)
+
+	signer, err := NewSigner(kh)
+	require.NoError(t, err)
+
+	messages := [][]byte{[]byte("message 1"), []byte("message 2"), []byte("message 3")}
+	nonce := []byte("nonce")
+
+	sig, err := signer.Sign(messages)
+	require.NoError(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	verifier, err := NewVerifier(pubKH)
+	require.NoError(t, err)
+
+	require.NoError(t, verifier.Verify(messages, sig))
+	require.EqualError(t, verifier.Verify(messages[1:], sig), "bbs_verifier_factory: invalid signature")
+
+	proof, err := verifier.DeriveProof(messages, sig, nonce, []int{1})
+	require.NoError(t, err)
+
+	require.NoError(t, verifier.VerifyProof(messages[1:2], proof, nonce))
+	require.EqualError(t, verifier.VerifyProof(messages[0:1], proof, nonce), "bbs_verifier_factory: invalid proof")
+
+	_, err = verifier.DeriveProof(messages[1:], sig, nonce, []int{1})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bbs_verifier_factory: derive proof")
+
+	t.Run("verify signatures of a rotated key", func(t *testing.T) {
+		km := keyset.NewManagerFromHandle(kh)
+		require.NoError(t, km.Rotate(BLS12381G2KeyTemplate()))
+
+		rotatedKH, err := km.Handle()
+		require.NoError(t, err)
+
+		rotatedSigner, err := NewSigner(rotatedKH)
+		require.NoError(t, err)
+
+		newSig, err := rotatedSigner.Sign(messages)
+		require.NoError(t, err)
+
+		rotatedPubKH, err := rotatedKH.Public()
+		require.NoError(t, err)
+
+		rotatedVerifier, err := NewVerifier(rotatedPubKH)
+		require.NoError(t, err)
+
+		require.NoError(t, rotatedVerifier.Verify(messages, newSig))
+		require.NoError(t, rotatedVerifier.Verify(messages, sig))
+		require.Error(t, verifier.Verify(messages, newSig))
+	})
+
+	t.Run("create primitives from other keys", func(t *testing.T) {
+		otherKH, err := keyset.NewHandle(signature.ED25519KeyWithoutPrefixTemplate())
+		require.NoError(t, err)
+
+		_, err = NewSigner(otherKH)
+		require.EqualError(t, err, "bbs_sign_factory: not a BBS Signer primitive")
+
+		otherPubKH, err := otherKH.Public()
+		require.NoError(t, err)
+
+		_, err = NewVerifier(otherPubKH)
+		require.EqualError(t, err, "bbs_verifier_factory: not a BBS Verifier primitive")
+
+		_, err = NewVerifier(kh)
+		require.EqualError(t, err, "bbs_verifier_factory: not a BBS Verifier primitive")
+	})
+}
@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBBSSignerKeyManager(t *testing.T) {
+	km := newBBSSignerKeyManager()
+
+	require.True(t, km.DoesSupport(bbsSignerTypeURL))
+	require.Equal(t, bbsSignerTypeURL, km.TypeURL())
+
+	keyData, err := km.NewKeyData(nil)
+	require.NoError(t, err)
+	require.Equal(t, bbsSignerTypeURL, keyData.TypeUrl)
+
+	pubKeyData, err := km.PublicKeyData(keyData.Value)
+	require.NoError(t, err)
+	require.Equal(t, bbsVerifierTypeURL, pubKeyData.TypeUrl)
+
+	t.Run("test invalid key", func(t *testing.T) {
+		_, err := km.Primitive(nil)
+		require.EqualError(t, err, errInvalidBBSSignerKey.Error())
+
+		_, err = km.Primitive([]byte("bad key"))
+		require.EqualError(t, err, errInvalidBBSSignerKey.Error())
+
+		serializedKey, err := proto.Marshal(&ed25519pb.Ed25519PrivateKey{Version: 1})
+		require.NoError(t, err)
+
+		_, err = km.Primitive(serializedKey)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "bbs_signer_key_manager: invalid key")
+
+		serializedKey, err = proto.Marshal(&ed25519pb.Ed25519PrivateKey{KeyValue: []byte("bad key value")})
+		require.NoError(t, err)
+
+		_, err = km.Primitive(serializedKey)
+		require.EqualError(t, err, "bbs_signer_key_manager: bbs: invalid private key size")
+
+		_, err = km.PublicKeyData([]byte("bad key"))
+		require.EqualError(t, err, errInvalidBBSSignerKey.Error())
+	})
+}
+
+func TestBBSVerifierKeyManager(t *testing.T) {
+	km := newBBSVerifierKeyManager()
+
+	require.True(t, km.DoesSupport(bbsVerifierTypeURL))
+	require.Equal(t, bbsVerifierTypeURL, km.TypeURL())
+
+	_, err := km.NewKey(nil)
+	require.EqualError(t, err, errBBSVerifierNotImplemented.Error())
+
+	_, err = km.NewKeyData(nil)
+	require.EqualError(t, err, errBBSVerifierNotImplemented.Error())
+
+	_, err = km.Primitive(nil)
+	require.EqualError(t, err, errInvalidBBSVerifierKey.Error())
+
+	_, err = km.Primitive([]byte("bad key"))
+	require.EqualError(t, err, errInvalidBBSVerifierKey.Error())
+
+	serializedKey, err := proto.Marshal(&ed25519pb.Ed25519PublicKey{Version: 1})
+	require.NoError(t, err)
+
+	_, err = km.Primitive(serializedKey)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bbs_verifier_key_manager: invalid key")
+
+	serializedKey, err = proto.Marshal(&ed25519pb.Ed25519PublicKey{KeyValue: []byte("bad key value")})
+	require.NoError(t, err)
+
+	_, err = km.Primitive(serializedKey)
+	require.EqualError(t, err, "bbs_verifier_key_manager: bbs: invalid public key size")
+}
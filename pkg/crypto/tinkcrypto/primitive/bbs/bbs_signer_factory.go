@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs/api"
+)
+
+// NewSigner returns a BBS+ Signer primitive from the given keyset handle.
+func NewSigner(h *keyset.Handle) (api.Signer, error) {
+	return NewSignerWithKeyManager(h, nil /*keyManager*/)
+}
+
+// NewSignerWithKeyManager returns a BBS+ Signer primitive from the given keyset handle and custom key manager.
+func NewSignerWithKeyManager(h *keyset.Handle, km registry.KeyManager) (api.Signer, error) {
+	ps, err := h.PrimitivesWithKeyManager(km)
+	if err != nil {
+		return nil, fmt.Errorf("bbs_sign_factory: cannot obtain primitive set: %w", err)
+	}
+
+	return newWrappedSigner(ps)
+}
+
+// wrappedSigner is a BBS+ Signer implementation that uses the underlying primitive set for signing.
+type wrappedSigner struct {
+	ps *primitiveset.PrimitiveSet
+}
+
+// Asserts that wrappedSigner implements the Signer interface.
+var _ api.Signer = (*wrappedSigner)(nil)
+
+func newWrappedSigner(ps *primitiveset.PrimitiveSet) (*wrappedSigner, error) {
+	if _, ok := (ps.Primary.Primitive).(api.Signer); !ok {
+		return nil, fmt.Errorf("bbs_sign_factory: not a BBS Signer primitive")
+	}
+
+	return &wrappedSigner{ps: ps}, nil
+}
+
+// Sign signs messages with the primary key of the keyset. BBS+ keys have a RAW output prefix, the signature is
+// not prefixed with the key ID.
+func (s *wrappedSigner) Sign(messages [][]byte) ([]byte, error) {
+	signer, ok := (s.ps.Primary.Primitive).(api.Signer)
+	if !ok {
+		return nil, fmt.Errorf("bbs_sign_factory: not a BBS Signer primitive")
+	}
+
+	return signer.Sign(messages)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs/subtle"
+)
+
+const (
+	bbsSignerKeyVersion = 0
+	bbsSignerTypeURL    = "type.hyperledger.org/hyperledger.aries.crypto.tink.BBSPrivateKey"
+)
+
+// common errors
+var errInvalidBBSSignerKey = errors.New("bbs_signer_key_manager: invalid key")
+
+// bbsSignerKeyManager is an implementation of PrivateKeyManager interface.
+// It generates new BBS+ private keys and produces new instances of BLS12381G2Signer subtle.
+type bbsSignerKeyManager struct{}
+
+// Assert that bbsSignerKeyManager implements the PrivateKeyManager interface.
+var _ registry.PrivateKeyManager = (*bbsSignerKeyManager)(nil)
+
+// newBBSSignerKeyManager creates a new bbsSignerKeyManager.
+func newBBSSignerKeyManager() *bbsSignerKeyManager {
+	return new(bbsSignerKeyManager)
+}
+
+// Primitive creates a BLS12381G2Signer subtle for the given serialized private key proto.
+func (km *bbsSignerKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidBBSSignerKey
+	}
+
+	key := new(ed25519pb.Ed25519PrivateKey)
+
+	err := proto.Unmarshal(serializedKey, key)
+	if err != nil {
+		return nil, errInvalidBBSSignerKey
+	}
+
+	err = keyset.ValidateKeyVersion(key.Version, bbsSignerKeyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("bbs_signer_key_manager: invalid key: %w", err)
+	}
+
+	ret, err := subtle.NewBLS12381G2Signer(key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("bbs_signer_key_manager: %w", err)
+	}
+
+	return ret, nil
+}
+
+// NewKey creates a new BBS+ key. The key format is ignored, BLS12-381 G2 keys have no parameters.
+func (km *bbsSignerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	pubKey, privKey, err := subtle.GenerateKeyPair()
+	if err != nil {
+		return nil, fmt.Errorf("bbs_signer_key_manager: %w", err)
+	}
+
+	return &ed25519pb.Ed25519PrivateKey{
+		Version: bbsSignerKeyVersion,
+		PublicKey: &ed25519pb.Ed25519PublicKey{
+			Version:  bbsSignerKeyVersion,
+			KeyValue: pubKey.Marshal(),
+		},
+		KeyValue: privKey.Marshal(),
+	}, nil
+}
+
+// NewKeyData creates a new KeyData containing a new BBS+ key.
+// It should be used solely by the key management API.
+func (km *bbsSignerKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidBBSSignerKey
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         bbsSignerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *bbsSignerKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(ed25519pb.Ed25519PrivateKey)
+
+	err := proto.Unmarshal(serializedPrivKey, privKey)
+	if err != nil || privKey.PublicKey == nil {
+		return nil, errInvalidBBSSignerKey
+	}
+
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidBBSSignerKey
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         bbsVerifierTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *bbsSignerKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == bbsSignerTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *bbsSignerKeyManager) TypeURL() string {
+	return bbsSignerTypeURL
+}
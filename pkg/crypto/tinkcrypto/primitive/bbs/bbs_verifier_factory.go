@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/core/primitiveset"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs/api"
+)
+
+// NewVerifier returns a BBS+ Verifier primitive from the given keyset handle.
+func NewVerifier(h *keyset.Handle) (api.Verifier, error) {
+	return NewVerifierWithKeyManager(h, nil /*keyManager*/)
+}
+
+// NewVerifierWithKeyManager returns a BBS+ Verifier primitive from the given keyset handle and custom key manager.
+func NewVerifierWithKeyManager(h *keyset.Handle, km registry.KeyManager) (api.Verifier, error) {
+	ps, err := h.PrimitivesWithKeyManager(km)
+	if err != nil {
+		return nil, fmt.Errorf("bbs_verifier_factory: cannot obtain primitive set: %w", err)
+	}
+
+	return newWrappedVerifier(ps)
+}
+
+// wrappedVerifier is a BBS+ Verifier implementation that uses the underlying primitive set. Since BBS+ keys have a
+// RAW output prefix, signatures and proofs are checked against all the keys of the keyset, eg to still verify the
+// signatures of a rotated key.
+type wrappedVerifier struct {
+	verifiers []api.Verifier
+}
+
+// Asserts that wrappedVerifier implements the Verifier interface.
+var _ api.Verifier = (*wrappedVerifier)(nil)
+
+func newWrappedVerifier(ps *primitiveset.PrimitiveSet) (*wrappedVerifier, error) {
+	entries, err := ps.RawEntries()
+	if err != nil {
+		return nil, fmt.Errorf("bbs_verifier_factory: %w", err)
+	}
+
+	ret := new(wrappedVerifier)
+
+	// the primary key is tried first
+	for _, e := range append([]*primitiveset.Entry{ps.Primary}, entries...) {
+		v, ok := (e.Primitive).(api.Verifier)
+		if !ok {
+			return nil, fmt.Errorf("bbs_verifier_factory: not a BBS Verifier primitive")
+		}
+
+		ret.verifiers = append(ret.verifiers, v)
+	}
+
+	return ret, nil
+}
+
+var errInvalidSignature = errors.New("bbs_verifier_factory: invalid signature")
+
+// Verify verifies the BBS+ signature of messages.
+func (v *wrappedVerifier) Verify(messages [][]byte, signature []byte) error {
+	for _, verifier := range v.verifiers {
+		if err := verifier.Verify(messages, signature); err == nil {
+			return nil
+		}
+	}
+
+	return errInvalidSignature
+}
+
+// DeriveProof creates a proof of knowledge of the BBS+ signature of messages with the key of the keyset which
+// signed messages.
+func (v *wrappedVerifier) DeriveProof(messages [][]byte, signature, nonce []byte,
+	revealedIndexes []int) ([]byte, error) {
+	var err error
+
+	for _, verifier := range v.verifiers {
+		var proof []byte
+
+		proof, err = verifier.DeriveProof(messages, signature, nonce, revealedIndexes)
+		if err == nil {
+			return proof, nil
+		}
+	}
+
+	return nil, fmt.Errorf("bbs_verifier_factory: derive proof: %w", err)
+}
+
+// VerifyProof verifies a proof of knowledge of a BBS+ signature revealing revealedMessages.
+func (v *wrappedVerifier) VerifyProof(revealedMessages [][]byte, proof, nonce []byte) error {
+	for _, verifier := range v.verifiers {
+		if err := verifier.VerifyProof(revealedMessages, proof, nonce); err == nil {
+			return nil
+		}
+	}
+
+	return errors.New("bbs_verifier_factory: invalid proof")
+}
@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bbs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs/subtle"
+)
+
+const (
+	bbsVerifierKeyVersion = 0
+	bbsVerifierTypeURL    = "type.hyperledger.org/hyperledger.aries.crypto.tink.BBSPublicKey"
+)
+
+// common errors
+var errInvalidBBSVerifierKey = errors.New("bbs_verifier_key_manager: invalid key")
+var errBBSVerifierNotImplemented = errors.New("bbs_verifier_key_manager: not implemented")
+
+// bbsVerifierKeyManager is an implementation of KeyManager interface.
+// It doesn't support key generation.
+type bbsVerifierKeyManager struct{}
+
+// Assert that bbsVerifierKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*bbsVerifierKeyManager)(nil)
+
+// newBBSVerifierKeyManager creates a new bbsVerifierKeyManager.
+func newBBSVerifierKeyManager() *bbsVerifierKeyManager {
+	return new(bbsVerifierKeyManager)
+}
+
+// Primitive creates a BLS12381G2Verifier subtle for the given serialized public key proto.
+func (km *bbsVerifierKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidBBSVerifierKey
+	}
+
+	key := new(ed25519pb.Ed25519PublicKey)
+
+	err := proto.Unmarshal(serializedKey, key)
+	if err != nil {
+		return nil, errInvalidBBSVerifierKey
+	}
+
+	err = keyset.ValidateKeyVersion(key.Version, bbsVerifierKeyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("bbs_verifier_key_manager: invalid key: %w", err)
+	}
+
+	ret, err := subtle.NewBLS12381G2Verifier(key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("bbs_verifier_key_manager: %w", err)
+	}
+
+	return ret, nil
+}
+
+// NewKey is not implemented.
+func (km *bbsVerifierKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errBBSVerifierNotImplemented
+}
+
+// NewKeyData is not implemented.
+func (km *bbsVerifierKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errBBSVerifierNotImplemented
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *bbsVerifierKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == bbsVerifierTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *bbsVerifierKeyManager) TypeURL() string {
+	return bbsVerifierTypeURL
+}
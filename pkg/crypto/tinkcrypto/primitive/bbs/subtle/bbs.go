@@ -0,0 +1,220 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package subtle provides the BBS+ signature scheme over the BLS12-381 curve with public keys in G2, as described in
+// https://eprint.iacr.org/2016/663.pdf section 4.3, along with the zero-knowledge proof of knowledge of a signature
+// which reveals a subset of the signed messages only.
+package subtle
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	frCompressedSize = 32
+	g1CompressedSize = 48
+	g2CompressedSize = 96
+
+	// PrivateKeySize is the size of a serialized BBS+ private key.
+	PrivateKeySize = frCompressedSize
+	// PublicKeySize is the size of a serialized (compressed) BBS+ public key.
+	PublicKeySize = g2CompressedSize
+)
+
+// generatorsDST is the domain separation tag used to hash the public key into the message generators.
+var generatorsDST = []byte("BLS12381G1_XMD:SHA-256_SSWU_RO_BBS+_SIGNATURES:1_0_0")
+
+// PrivateKey is a BBS+ private key, a scalar of the BLS12-381 Fr field.
+type PrivateKey struct {
+	x *bls12381.Fr
+}
+
+// PublicKey is a BBS+ public key, a point of the BLS12-381 G2 group.
+type PublicKey struct {
+	w *bls12381.PointG2
+}
+
+// GenerateKeyPair creates a new BBS+ key pair.
+func GenerateKeyPair() (*PublicKey, *PrivateKey, error) {
+	x, err := randomFr()
+	if err != nil {
+		return nil, nil, fmt.Errorf("bbs: cannot generate key: %w", err)
+	}
+
+	privKey := &PrivateKey{x: x}
+
+	return privKey.PublicKey(), privKey, nil
+}
+
+// UnmarshalPrivateKey parses a BBS+ private key serialized as a big endian Fr element.
+func UnmarshalPrivateKey(privKey []byte) (*PrivateKey, error) {
+	if len(privKey) != PrivateKeySize {
+		return nil, errors.New("bbs: invalid private key size")
+	}
+
+	x := bls12381.NewFr().FromBytes(privKey)
+	if x.IsZero() || !bytes.Equal(x.ToBytes(), privKey) {
+		return nil, errors.New("bbs: invalid private key")
+	}
+
+	return &PrivateKey{x: x}, nil
+}
+
+// Marshal serializes the private key.
+func (k *PrivateKey) Marshal() []byte {
+	return k.x.ToBytes()
+}
+
+// PublicKey returns the public key of the private key.
+func (k *PrivateKey) PublicKey() *PublicKey {
+	g2 := bls12381.NewG2()
+	w := g2.New()
+
+	g2.MulScalar(w, g2.One(), k.x)
+
+	return &PublicKey{w: w}
+}
+
+// UnmarshalPublicKey parses a BBS+ public key serialized as a compressed G2 point.
+func UnmarshalPublicKey(pubKey []byte) (*PublicKey, error) {
+	if len(pubKey) != PublicKeySize {
+		return nil, errors.New("bbs: invalid public key size")
+	}
+
+	g2 := bls12381.NewG2()
+
+	w, err := g2.FromCompressed(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("bbs: invalid public key: %w", err)
+	}
+
+	if g2.IsZero(w) {
+		return nil, errors.New("bbs: invalid public key: point at infinity")
+	}
+
+	return &PublicKey{w: w}, nil
+}
+
+// Marshal serializes the public key as a compressed G2 point.
+func (k *PublicKey) Marshal() []byte {
+	return bls12381.NewG2().ToCompressed(k.w)
+}
+
+// generators are the G1 points the messages and the signature blinding factor are committed to.
+type generators struct {
+	h0 *bls12381.PointG1
+	h  []*bls12381.PointG1
+}
+
+// generators deterministically derives messagesCount+1 generators from the public key, so that signers and
+// verifiers get the same generators without exchanging them.
+func (k *PublicKey) generators(messagesCount int) (*generators, error) {
+	g1 := bls12381.NewG1()
+
+	data := make([]byte, 0, g2CompressedSize+8)
+	data = append(data, k.Marshal()...)
+	data = append(data, uint32ToBytes(uint32(messagesCount))...)
+
+	h := make([]*bls12381.PointG1, messagesCount+1)
+
+	for i := range h {
+		p, err := g1.HashToCurve(append(data[:len(data):len(data)], uint32ToBytes(uint32(i))...), generatorsDST)
+		if err != nil {
+			return nil, fmt.Errorf("bbs: cannot create generators: %w", err)
+		}
+
+		h[i] = p
+	}
+
+	return &generators{h0: h[0], h: h[1:]}, nil
+}
+
+// messagesToFr maps the messages to Fr elements.
+func messagesToFr(messages [][]byte) []*bls12381.Fr {
+	frs := make([]*bls12381.Fr, len(messages))
+
+	for i, msg := range messages {
+		frs[i] = hashToFr(msg)
+	}
+
+	return frs
+}
+
+// hashToFr hashes data to an Fr element, the 512 bits digest reduced modulo r has a negligible bias.
+func hashToFr(data ...[]byte) *bls12381.Fr {
+	h, _ := blake2b.New512(nil) //nolint:errcheck // a nil key never fails
+
+	for _, d := range data {
+		h.Write(d) //nolint:errcheck,gosec // hash writes never fail
+	}
+
+	return bls12381.NewFr().FromBytes(h.Sum(nil))
+}
+
+// randomFr returns a random non zero Fr element.
+func randomFr() (*bls12381.Fr, error) {
+	for {
+		fr, err := bls12381.NewFr().Rand(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+
+		if !fr.IsZero() {
+			return fr, nil
+		}
+	}
+}
+
+// computeB computes b = g1 * h0^s * h1^m1 * ... * hn^mn.
+func computeB(s *bls12381.Fr, messages []*bls12381.Fr, gens *generators) *bls12381.PointG1 {
+	g1 := bls12381.NewG1()
+
+	b := g1.One()
+	g1.Add(b, b, mulG1(gens.h0, s))
+
+	for i, m := range messages {
+		g1.Add(b, b, mulG1(gens.h[i], m))
+	}
+
+	return b
+}
+
+// mulG1 returns a new point p*scalar.
+func mulG1(p *bls12381.PointG1, scalar *bls12381.Fr) *bls12381.PointG1 {
+	g1 := bls12381.NewG1()
+	return g1.MulScalar(g1.New(), p, scalar)
+}
+
+// invFr returns a new element 1/e, e must not be zero.
+// Note: Fr.Inverse() isn't used as it returns an element in Montgomery form.
+func invFr(e *bls12381.Fr) *bls12381.Fr {
+	inv := new(big.Int).ModInverse(e.ToBig(), bls12381.NewG1().Q())
+
+	return bls12381.NewFr().FromBytes(inv.Bytes())
+}
+
+// negFr returns a new element -e.
+func negFr(e *bls12381.Fr) *bls12381.Fr {
+	r := bls12381.NewFr()
+	r.Neg(e)
+
+	return r
+}
+
+func uint32ToBytes(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+
+	return b
+}
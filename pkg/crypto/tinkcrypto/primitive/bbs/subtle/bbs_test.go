@@ -0,0 +1,212 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeys(t *testing.T) {
+	pubKey, privKey, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	t.Run("marshal and unmarshal keys", func(t *testing.T) {
+		privKeyBytes := privKey.Marshal()
+		require.Len(t, privKeyBytes, PrivateKeySize)
+
+		parsedPrivKey, err := UnmarshalPrivateKey(privKeyBytes)
+		require.NoError(t, err)
+		require.Equal(t, privKeyBytes, parsedPrivKey.Marshal())
+
+		pubKeyBytes := pubKey.Marshal()
+		require.Len(t, pubKeyBytes, PublicKeySize)
+
+		parsedPubKey, err := UnmarshalPublicKey(pubKeyBytes)
+		require.NoError(t, err)
+		require.Equal(t, pubKeyBytes, parsedPubKey.Marshal())
+		require.Equal(t, pubKeyBytes, parsedPrivKey.PublicKey().Marshal())
+	})
+
+	t.Run("unmarshal invalid keys", func(t *testing.T) {
+		_, err := UnmarshalPrivateKey([]byte("bad key"))
+		require.EqualError(t, err, "bbs: invalid private key size")
+
+		_, err = UnmarshalPrivateKey(make([]byte, PrivateKeySize))
+		require.EqualError(t, err, "bbs: invalid private key")
+
+		// larger than the Fr modulus
+		overflow := make([]byte, PrivateKeySize)
+		for i := range overflow {
+			overflow[i] = 0xff
+		}
+
+		_, err = UnmarshalPrivateKey(overflow)
+		require.EqualError(t, err, "bbs: invalid private key")
+
+		_, err = UnmarshalPublicKey([]byte("bad key"))
+		require.EqualError(t, err, "bbs: invalid public key size")
+
+		_, err = UnmarshalPublicKey(make([]byte, PublicKeySize))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "bbs: invalid public key")
+
+		infinity := make([]byte, PublicKeySize)
+		infinity[0] = 0xc0
+
+		_, err = UnmarshalPublicKey(infinity)
+		require.EqualError(t, err, "bbs: invalid public key: point at infinity")
+	})
+}
+
+func TestSignVerify(t *testing.T) {
+	pubKey, privKey, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	messages := [][]byte{[]byte("message 1"), []byte("message 2"), []byte("message 3")}
+
+	sig, err := Sign(messages, privKey)
+	require.NoError(t, err)
+	require.Len(t, sig, SignatureSize)
+
+	require.NoError(t, Verify(messages, sig, pubKey))
+
+	t.Run("verify with other messages", func(t *testing.T) {
+		err := Verify([][]byte{messages[0], messages[2], messages[1]}, sig, pubKey)
+		require.EqualError(t, err, "bbs: invalid signature")
+
+		err = Verify(messages[:2], sig, pubKey)
+		require.EqualError(t, err, "bbs: invalid signature")
+
+		err = Verify(nil, sig, pubKey)
+		require.EqualError(t, err, "bbs: messages are not defined")
+	})
+
+	t.Run("verify with other public key", func(t *testing.T) {
+		otherPubKey, _, err := GenerateKeyPair()
+		require.NoError(t, err)
+
+		require.EqualError(t, Verify(messages, sig, otherPubKey), "bbs: invalid signature")
+	})
+
+	t.Run("verify invalid signature", func(t *testing.T) {
+		require.EqualError(t, Verify(messages, sig[1:], pubKey), "bbs: invalid signature size")
+
+		badSig := append([]byte{}, sig...)
+		badSig[SignatureSize-1] ^= 1
+
+		require.EqualError(t, Verify(messages, badSig, pubKey), "bbs: invalid signature")
+
+		badSig = append([]byte{}, sig...)
+		badSig[0] &^= 0x80
+
+		require.Error(t, Verify(messages, badSig, pubKey))
+
+		badSig = make([]byte, SignatureSize)
+		badSig[0] = 0xc0
+
+		require.EqualError(t, Verify(messages, badSig, pubKey), "bbs: invalid signature: point at infinity")
+	})
+
+	t.Run("sign without messages", func(t *testing.T) {
+		_, err := Sign(nil, privKey)
+		require.EqualError(t, err, "bbs: messages are not defined")
+	})
+}
+
+func TestDeriveVerifyProof(t *testing.T) {
+	pubKey, privKey, err := GenerateKeyPair()
+	require.NoError(t, err)
+
+	messages := [][]byte{
+		[]byte("message 1"), []byte("message 2"), []byte("message 3"),
+		[]byte("message 4"), []byte("message 5"), []byte("message 6"),
+		[]byte("message 7"), []byte("message 8"), []byte("message 9"),
+	}
+	nonce := []byte("nonce")
+
+	sig, err := Sign(messages, privKey)
+	require.NoError(t, err)
+
+	t.Run("reveal a subset of the messages", func(t *testing.T) {
+		proof, err := DeriveProof(messages, sig, nonce, []int{8, 0, 3}, pubKey)
+		require.NoError(t, err)
+
+		revealed := [][]byte{messages[0], messages[3], messages[8]}
+		require.NoError(t, VerifyProof(revealed, proof, nonce, pubKey))
+
+		// proofs are randomized
+		otherProof, err := DeriveProof(messages, sig, nonce, []int{0, 3, 8}, pubKey)
+		require.NoError(t, err)
+		require.NotEqual(t, proof, otherProof)
+		require.NoError(t, VerifyProof(revealed, otherProof, nonce, pubKey))
+
+		err = VerifyProof(revealed, proof, []byte("other nonce"), pubKey)
+		require.EqualError(t, err, "bbs: invalid proof")
+
+		err = VerifyProof([][]byte{messages[0], messages[4], messages[8]}, proof, nonce, pubKey)
+		require.EqualError(t, err, "bbs: invalid proof")
+
+		err = VerifyProof(revealed[:2], proof, nonce, pubKey)
+		require.EqualError(t, err, "bbs: revealed messages don't match the proof")
+
+		otherPubKey, _, err := GenerateKeyPair()
+		require.NoError(t, err)
+
+		err = VerifyProof(revealed, proof, nonce, otherPubKey)
+		require.EqualError(t, err, "bbs: invalid proof")
+	})
+
+	t.Run("reveal all or none of the messages", func(t *testing.T) {
+		proof, err := DeriveProof(messages, sig, nonce, nil, pubKey)
+		require.NoError(t, err)
+		require.NoError(t, VerifyProof(nil, proof, nonce, pubKey))
+
+		proof, err = DeriveProof(messages, sig, nonce, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}, pubKey)
+		require.NoError(t, err)
+		require.NoError(t, VerifyProof(messages, proof, nonce, pubKey))
+	})
+
+	t.Run("tampered proof", func(t *testing.T) {
+		proof, err := DeriveProof(messages, sig, nonce, []int{1}, pubKey)
+		require.NoError(t, err)
+
+		badProof := append([]byte{}, proof...)
+		badProof[len(badProof)-1] ^= 1
+
+		require.EqualError(t, VerifyProof(messages[1:2], badProof, nonce, pubKey), "bbs: invalid proof")
+
+		// reveal another message than the one of the proof
+		badProof = append([]byte{}, proof...)
+		badProof[proofHeaderSize] = 1
+
+		require.EqualError(t, VerifyProof(messages[0:1], badProof, nonce, pubKey), "bbs: invalid proof")
+
+		require.EqualError(t, VerifyProof(messages[1:2], proof[:1], nonce, pubKey), "bbs: invalid proof size")
+		require.EqualError(t, VerifyProof(messages[1:2], proof[:len(proof)-1], nonce, pubKey),
+			"bbs: invalid proof size")
+		require.EqualError(t, VerifyProof(nil, []byte{0, 0}, nonce, pubKey), "bbs: invalid proof size")
+	})
+
+	t.Run("derive proof errors", func(t *testing.T) {
+		_, err := DeriveProof(nil, sig, nonce, nil, pubKey)
+		require.EqualError(t, err, "bbs: messages are not defined")
+
+		_, err = DeriveProof(messages, sig, nonce, []int{9}, pubKey)
+		require.EqualError(t, err, "bbs: revealed index 9 is out of range")
+
+		_, err = DeriveProof(messages, sig, nonce, []int{1, 1}, pubKey)
+		require.EqualError(t, err, "bbs: revealed index 1 is duplicated")
+
+		_, err = DeriveProof(messages, sig[1:], nonce, nil, pubKey)
+		require.EqualError(t, err, "bbs: invalid signature size")
+
+		_, err = DeriveProof(messages[1:], sig, nonce, nil, pubKey)
+		require.EqualError(t, err, "bbs: derive proof: bbs: invalid signature")
+	})
+}
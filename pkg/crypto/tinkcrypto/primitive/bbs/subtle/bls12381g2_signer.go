@@ -0,0 +1,34 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs/api"
+)
+
+// BLS12381G2Signer is a BBS+ signer using a BLS12-381 private key whose public key is in G2.
+type BLS12381G2Signer struct {
+	privateKey *PrivateKey
+}
+
+// Assert that BLS12381G2Signer implements the Signer interface.
+var _ api.Signer = (*BLS12381G2Signer)(nil)
+
+// NewBLS12381G2Signer creates a new instance of BLS12381G2Signer with the serialized privateKey.
+func NewBLS12381G2Signer(privateKey []byte) (*BLS12381G2Signer, error) {
+	privKey, err := UnmarshalPrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BLS12381G2Signer{privateKey: privKey}, nil
+}
+
+// Sign signs messages with a single BBS+ signature.
+func (s *BLS12381G2Signer) Sign(messages [][]byte) ([]byte, error) {
+	return Sign(messages, s.privateKey)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs/api"
+)
+
+// BLS12381G2Verifier is a BBS+ verifier using a BLS12-381 G2 public key.
+type BLS12381G2Verifier struct {
+	publicKey *PublicKey
+}
+
+// Assert that BLS12381G2Verifier implements the Verifier interface.
+var _ api.Verifier = (*BLS12381G2Verifier)(nil)
+
+// NewBLS12381G2Verifier creates a new instance of BLS12381G2Verifier with the compressed publicKey.
+func NewBLS12381G2Verifier(publicKey []byte) (*BLS12381G2Verifier, error) {
+	pubKey, err := UnmarshalPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BLS12381G2Verifier{publicKey: pubKey}, nil
+}
+
+// Verify verifies the BBS+ signature of messages.
+func (v *BLS12381G2Verifier) Verify(messages [][]byte, signature []byte) error {
+	return Verify(messages, signature, v.publicKey)
+}
+
+// DeriveProof creates a proof of knowledge of the BBS+ signature of messages revealing the messages at
+// revealedIndexes only.
+func (v *BLS12381G2Verifier) DeriveProof(messages [][]byte, signature, nonce []byte,
+	revealedIndexes []int) ([]byte, error) {
+	return DeriveProof(messages, signature, nonce, revealedIndexes, v.publicKey)
+}
+
+// VerifyProof verifies a proof of knowledge of a BBS+ signature revealing revealedMessages.
+func (v *BLS12381G2Verifier) VerifyProof(revealedMessages [][]byte, proof, nonce []byte) error {
+	return VerifyProof(revealedMessages, proof, nonce, v.publicKey)
+}
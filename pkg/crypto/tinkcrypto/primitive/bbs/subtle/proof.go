@@ -0,0 +1,319 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// A proof of knowledge of a signature (A, e, s) randomizes the signature with r1 and r2 as A' = A^r1,
+// Abar = A'^-e * b^r1, d = b^r1 * h0^-r2 and s' = s - r2*r3 with r3 = 1/r1, then proves in zero knowledge that:
+//  - Abar/d = A'^-e * h0^r2
+//  - g1 * Π(revealed hi^mi) = d^r3 * h0^-s' * Π(hidden hj^-mj)
+// The verifier also checks that e(A', w) == e(Abar, g2).
+//
+// A serialized proof is:
+//  messages count (2 bytes) || revealed messages bit vector || A' || Abar || d || challenge ||
+//  responses of the first statement (2) || responses of the second statement (2 + hidden messages count)
+
+const proofHeaderSize = 2
+
+// DeriveProof creates a proof of knowledge of the BBS+ signature sig of messages which reveals the messages at
+// revealedIndexes only. The nonce given by the verifier binds the proof to a presentation.
+func DeriveProof(messages [][]byte, sig, nonce []byte, revealedIndexes []int, pubKey *PublicKey) ([]byte, error) {
+	if len(messages) == 0 {
+		return nil, errors.New("bbs: messages are not defined")
+	}
+
+	if len(messages) > math.MaxUint16 {
+		return nil, errors.New("bbs: too many messages")
+	}
+
+	revealed, err := revealedSet(revealedIndexes, len(messages))
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := unmarshalSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	gens, err := pubKey.generators(len(messages))
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := messagesToFr(messages)
+
+	err = s.verify(msgs, pubKey, gens)
+	if err != nil {
+		return nil, fmt.Errorf("bbs: derive proof: %w", err)
+	}
+
+	return newProof(s, msgs, revealed, nonce, gens)
+}
+
+func newProof(s *signature, msgs []*bls12381.Fr, revealed []bool, nonce []byte, gens *generators) ([]byte, error) {
+	g1 := bls12381.NewG1()
+
+	r1, err := randomFr()
+	if err != nil {
+		return nil, fmt.Errorf("bbs: derive proof: %w", err)
+	}
+
+	r2, err := randomFr()
+	if err != nil {
+		return nil, fmt.Errorf("bbs: derive proof: %w", err)
+	}
+
+	r3 := invFr(r1)
+
+	b := computeB(s.s, msgs, gens)
+	br1 := mulG1(b, r1)
+
+	aPrime := mulG1(s.a, r1)
+	aBar := g1.Add(g1.New(), mulG1(aPrime, negFr(s.e)), br1)
+	d := g1.Add(g1.New(), br1, mulG1(gens.h0, negFr(r2)))
+
+	sPrime := bls12381.NewFr()
+	sPrime.Mul(r2, r3)
+	sPrime.Sub(s.s, sPrime)
+
+	// first statement: Abar/d = A'^-e * h0^r2
+	bases1 := []*bls12381.PointG1{aPrime, gens.h0}
+	secrets1 := []*bls12381.Fr{negFr(s.e), r2}
+
+	// second statement: g1 * Π(revealed hi^mi) = d^r3 * h0^-s' * Π(hidden hj^-mj)
+	bases2 := []*bls12381.PointG1{d, gens.h0}
+	secrets2 := []*bls12381.Fr{r3, negFr(sPrime)}
+
+	for i, m := range msgs {
+		if !revealed[i] {
+			bases2 = append(bases2, gens.h[i])
+			secrets2 = append(secrets2, negFr(m))
+		}
+	}
+
+	blindings1, t1, err := commit(bases1)
+	if err != nil {
+		return nil, fmt.Errorf("bbs: derive proof: %w", err)
+	}
+
+	blindings2, t2, err := commit(bases2)
+	if err != nil {
+		return nil, fmt.Errorf("bbs: derive proof: %w", err)
+	}
+
+	header := proofHeader(revealed)
+	c := challenge(header, aPrime, aBar, d, t1, t2, nonce)
+
+	out := header
+	out = append(out, g1.ToCompressed(aPrime)...)
+	out = append(out, g1.ToCompressed(aBar)...)
+	out = append(out, g1.ToCompressed(d)...)
+	out = append(out, c.ToBytes()...)
+
+	for _, z := range append(responses(blindings1, secrets1, c), responses(blindings2, secrets2, c)...) {
+		out = append(out, z.ToBytes()...)
+	}
+
+	return out, nil
+}
+
+// VerifyProof verifies a proof of knowledge of a BBS+ signature created by DeriveProof, revealedMessages are the
+// messages revealed by the proof in their signing order.
+func VerifyProof(revealedMessages [][]byte, proof, nonce []byte, pubKey *PublicKey) error { //nolint:funlen
+	if len(proof) < proofHeaderSize {
+		return errors.New("bbs: invalid proof size")
+	}
+
+	messagesCount := int(binary.BigEndian.Uint16(proof))
+	bitVectorSize := (messagesCount + 7) / 8 //nolint:gomnd
+	headerSize := proofHeaderSize + bitVectorSize
+
+	if messagesCount == 0 || len(proof) < headerSize {
+		return errors.New("bbs: invalid proof size")
+	}
+
+	revealed := make([]bool, messagesCount)
+	revealedCount := 0
+
+	for i := range revealed {
+		revealed[i] = proof[proofHeaderSize+i/8]&(1<<uint(i%8)) != 0 //nolint:gomnd
+
+		if revealed[i] {
+			revealedCount++
+		}
+	}
+
+	if revealedCount != len(revealedMessages) {
+		return errors.New("bbs: revealed messages don't match the proof")
+	}
+
+	hiddenCount := messagesCount - revealedCount
+	if len(proof) != headerSize+3*g1CompressedSize+(5+hiddenCount)*frCompressedSize {
+		return errors.New("bbs: invalid proof size")
+	}
+
+	g1 := bls12381.NewG1()
+	offset := headerSize
+
+	points := make([]*bls12381.PointG1, 3)
+
+	for i := range points {
+		p, err := g1.FromCompressed(proof[offset : offset+g1CompressedSize])
+		if err != nil {
+			return fmt.Errorf("bbs: invalid proof: %w", err)
+		}
+
+		points[i] = p
+		offset += g1CompressedSize
+	}
+
+	aPrime, aBar, d := points[0], points[1], points[2]
+
+	if g1.IsZero(aPrime) {
+		return errors.New("bbs: invalid proof: point at infinity")
+	}
+
+	frs := make([]*bls12381.Fr, 5+hiddenCount)
+	for i := range frs {
+		frs[i] = bls12381.NewFr().FromBytes(proof[offset : offset+frCompressedSize])
+		offset += frCompressedSize
+	}
+
+	c, z1, z2 := frs[0], frs[1:3], frs[3:]
+
+	gens, err := pubKey.generators(messagesCount)
+	if err != nil {
+		return err
+	}
+
+	engine := bls12381.NewEngine()
+	engine.AddPair(aPrime, pubKey.w)
+	engine.AddPairInv(aBar, engine.G2.One())
+
+	if !engine.Check() {
+		return errors.New("bbs: invalid proof")
+	}
+
+	y1 := g1.Sub(g1.New(), aBar, d)
+	t1 := recommit(y1, c, []*bls12381.PointG1{aPrime, gens.h0}, z1)
+
+	y2 := g1.One()
+	bases2 := []*bls12381.PointG1{d, gens.h0}
+	revealedFrs := messagesToFr(revealedMessages)
+
+	for i := range revealed {
+		if revealed[i] {
+			g1.Add(y2, y2, mulG1(gens.h[i], revealedFrs[0]))
+			revealedFrs = revealedFrs[1:]
+		} else {
+			bases2 = append(bases2, gens.h[i])
+		}
+	}
+
+	t2 := recommit(y2, c, bases2, z2)
+
+	if !challenge(proof[:headerSize], aPrime, aBar, d, t1, t2, nonce).Equal(c) {
+		return errors.New("bbs: invalid proof")
+	}
+
+	return nil
+}
+
+// commit returns random blindings along with the commitment Π(bases^blindings) of a Schnorr proof.
+func commit(bases []*bls12381.PointG1) ([]*bls12381.Fr, *bls12381.PointG1, error) {
+	g1 := bls12381.NewG1()
+	t := g1.Zero()
+	blindings := make([]*bls12381.Fr, len(bases))
+
+	for i, base := range bases {
+		r, err := randomFr()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		blindings[i] = r
+		g1.Add(t, t, mulG1(base, r))
+	}
+
+	return blindings, t, nil
+}
+
+// responses returns the Schnorr proof responses blinding - c*secret.
+func responses(blindings, secrets []*bls12381.Fr, c *bls12381.Fr) []*bls12381.Fr {
+	z := make([]*bls12381.Fr, len(blindings))
+
+	for i := range blindings {
+		z[i] = bls12381.NewFr()
+		z[i].Mul(c, secrets[i])
+		z[i].Sub(blindings[i], z[i])
+	}
+
+	return z
+}
+
+// recommit recomputes the commitment of a Schnorr proof of the statement y = Π(bases^secrets) from its responses,
+// as y^c * Π(bases^responses).
+func recommit(y *bls12381.PointG1, c *bls12381.Fr, bases []*bls12381.PointG1, z []*bls12381.Fr) *bls12381.PointG1 {
+	g1 := bls12381.NewG1()
+	t := mulG1(y, c)
+
+	for i, base := range bases {
+		g1.Add(t, t, mulG1(base, z[i]))
+	}
+
+	return t
+}
+
+func challenge(header []byte, aPrime, aBar, d, t1, t2 *bls12381.PointG1, nonce []byte) *bls12381.Fr {
+	g1 := bls12381.NewG1()
+
+	return hashToFr(header, g1.ToCompressed(aPrime), g1.ToCompressed(aBar), g1.ToCompressed(d),
+		g1.ToCompressed(t1), g1.ToCompressed(t2), nonce)
+}
+
+func proofHeader(revealed []bool) []byte {
+	header := make([]byte, proofHeaderSize+(len(revealed)+7)/8) //nolint:gomnd
+	binary.BigEndian.PutUint16(header, uint16(len(revealed)))
+
+	for i, r := range revealed {
+		if r {
+			header[proofHeaderSize+i/8] |= 1 << uint(i%8) //nolint:gomnd
+		}
+	}
+
+	return header
+}
+
+func revealedSet(revealedIndexes []int, messagesCount int) ([]bool, error) {
+	revealed := make([]bool, messagesCount)
+	indexes := append([]int{}, revealedIndexes...)
+	sort.Ints(indexes)
+
+	for i, idx := range indexes {
+		if idx < 0 || idx >= messagesCount {
+			return nil, fmt.Errorf("bbs: revealed index %d is out of range", idx)
+		}
+
+		if i > 0 && indexes[i-1] == idx {
+			return nil, fmt.Errorf("bbs: revealed index %d is duplicated", idx)
+		}
+
+		revealed[idx] = true
+	}
+
+	return revealed, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"errors"
+	"fmt"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+// SignatureSize is the size of a serialized BBS+ signature: A (compressed G1 point) || e || s.
+const SignatureSize = g1CompressedSize + 2*frCompressedSize
+
+// signature is a BBS+ signature (A, e, s).
+type signature struct {
+	a *bls12381.PointG1
+	e *bls12381.Fr
+	s *bls12381.Fr
+}
+
+// Sign signs messages with privKey, the messages are signed as a whole and their order matters.
+func Sign(messages [][]byte, privKey *PrivateKey) ([]byte, error) {
+	if len(messages) == 0 {
+		return nil, errors.New("bbs: messages are not defined")
+	}
+
+	gens, err := privKey.PublicKey().generators(len(messages))
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		e, err := randomFr()
+		if err != nil {
+			return nil, fmt.Errorf("bbs: sign: %w", err)
+		}
+
+		s, err := randomFr()
+		if err != nil {
+			return nil, fmt.Errorf("bbs: sign: %w", err)
+		}
+
+		exp := bls12381.NewFr()
+		exp.Add(privKey.x, e)
+
+		// retry in the (negligible) case x+e has no inverse
+		if exp.IsZero() {
+			continue
+		}
+
+		b := computeB(s, messagesToFr(messages), gens)
+
+		sig := &signature{a: mulG1(b, invFr(exp)), e: e, s: s}
+
+		return sig.marshal(), nil
+	}
+}
+
+// Verify verifies the BBS+ signature sig of messages with pubKey.
+func Verify(messages [][]byte, sig []byte, pubKey *PublicKey) error {
+	if len(messages) == 0 {
+		return errors.New("bbs: messages are not defined")
+	}
+
+	s, err := unmarshalSignature(sig)
+	if err != nil {
+		return err
+	}
+
+	gens, err := pubKey.generators(len(messages))
+	if err != nil {
+		return err
+	}
+
+	return s.verify(messagesToFr(messages), pubKey, gens)
+}
+
+// verify checks e(A, w * g2^e) == e(b, g2).
+func (s *signature) verify(messages []*bls12381.Fr, pubKey *PublicKey, gens *generators) error {
+	b := computeB(s.s, messages, gens)
+
+	engine := bls12381.NewEngine()
+	g2 := engine.G2
+
+	p2 := g2.New()
+	g2.MulScalar(p2, g2.One(), s.e)
+	g2.Add(p2, p2, pubKey.w)
+
+	engine.AddPair(s.a, p2)
+	engine.AddPairInv(b, g2.One())
+
+	if !engine.Check() {
+		return errors.New("bbs: invalid signature")
+	}
+
+	return nil
+}
+
+func (s *signature) marshal() []byte {
+	out := make([]byte, 0, SignatureSize)
+	out = append(out, bls12381.NewG1().ToCompressed(s.a)...)
+	out = append(out, s.e.ToBytes()...)
+	out = append(out, s.s.ToBytes()...)
+
+	return out
+}
+
+func unmarshalSignature(sig []byte) (*signature, error) {
+	if len(sig) != SignatureSize {
+		return nil, errors.New("bbs: invalid signature size")
+	}
+
+	g1 := bls12381.NewG1()
+
+	a, err := g1.FromCompressed(sig[:g1CompressedSize])
+	if err != nil {
+		return nil, fmt.Errorf("bbs: invalid signature: %w", err)
+	}
+
+	if g1.IsZero(a) {
+		return nil, errors.New("bbs: invalid signature: point at infinity")
+	}
+
+	return &signature{
+		a: a,
+		e: bls12381.NewFr().FromBytes(sig[g1CompressedSize : g1CompressedSize+frCompressedSize]),
+		s: bls12381.NewFr().FromBytes(sig[g1CompressedSize+frCompressedSize:]),
+	}, nil
+}
@@ -13,6 +13,10 @@ SPDX-License-Identifier: Apache-2.0
 //
 //  * ECDHESDecrypt for decryption of data for a certain recipient key and returning decrypted plaintext
 //
+// Recipients keys are NIST P curves keys or X25519 keys (ECDHESX25519KWXChaCha20Poly1305KeyTemplate), the key
+// agreement keys of the jwe/authcrypt packer. The content is decrypted with the content encryption algorithm set by
+// the sender (the JWE enc header), whatever the content encryption of the recipient key template.
+//
 //
 // Example:
 //
@@ -15,6 +15,8 @@ import (
 	"github.com/google/tink/go/keyset"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/google/tink/go/subtle/hybrid"
+	"github.com/google/tink/go/subtle/random"
+	"golang.org/x/crypto/curve25519"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdhes/subtle"
 	ecdhespb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdhes_aead_go_proto"
@@ -59,16 +61,17 @@ func (km *ecdhesPrivateKeyManager) Primitive(serializedKey []byte) (interface{},
 		return nil, errInvalidECDHESPrivateKey
 	}
 
-	pvt := hybrid.GetECPrivateKey(curve, key.KeyValue)
-
-	rEnc, err := newRegisterECDHESAEADEncHelper(key.PublicKey.Params.EncParams.AeadEnc)
-	if err != nil {
-		return nil, err
+	// the content encryption of the key template is the one used by senders, the content is decrypted with the
+	// algorithm of the encrypted data
+	if key.PublicKey.Params.KwParams.CurveType == x25519CurveType {
+		return subtle.NewECDHESX25519AEADCompositeDecrypt(key.KeyValue, newEncHelperForEncAlg)
 	}
 
+	pvt := hybrid.GetECPrivateKey(curve, key.KeyValue)
+
 	ptFormat := key.PublicKey.Params.EcPointFormat.String()
 
-	return subtle.NewECDHESAEADCompositeDecrypt(pvt, ptFormat, rEnc)
+	return subtle.NewECDHESAEADCompositeDecrypt(pvt, ptFormat, newEncHelperForEncAlg)
 }
 
 // NewKey creates a new key according to the specification of ECDHESPrivateKey format.
@@ -89,6 +92,10 @@ func (km *ecdhesPrivateKeyManager) NewKey(serializedKeyFormat []byte) (proto.Mes
 		return nil, errInvalidECDHESPrivateKeyFormat
 	}
 
+	if keyFormat.Params.KwParams.CurveType == x25519CurveType {
+		return newX25519PrivateKey(keyFormat.Params)
+	}
+
 	pvt, err := hybrid.GenerateECDHKeyPair(curve)
 	if err != nil {
 		return nil, err
@@ -106,6 +113,26 @@ func (km *ecdhesPrivateKeyManager) NewKey(serializedKeyFormat []byte) (proto.Mes
 	}, nil
 }
 
+// newX25519PrivateKey generates an X25519 ECDHESPrivateKey, its raw public key being set in X.
+func newX25519PrivateKey(params *ecdhespb.EcdhesAeadParams) (*ecdhespb.EcdhesAeadPrivateKey, error) {
+	pvt := random.GetRandomBytes(curve25519.ScalarSize)
+
+	pub, err := curve25519.X25519(pvt, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ecdhespb.EcdhesAeadPrivateKey{
+		Version:  ecdhesPrivateKeyVersion,
+		KeyValue: pvt,
+		PublicKey: &ecdhespb.EcdhesAeadPublicKey{
+			Version: ecdhesPrivateKeyVersion,
+			Params:  params,
+			X:       pub,
+		},
+	}, nil
+}
+
 // NewKeyData creates a new KeyData according to the specification of ECDHESPrivateKey Format.
 // It should be used solely by the key management API.
 func (km *ecdhesPrivateKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
@@ -167,11 +194,18 @@ func (km *ecdhesPrivateKeyManager) validateKey(key *ecdhespb.EcdhesAeadPrivateKe
 	return validateKeyFormat(key.PublicKey.Params)
 }
 
-// validateKeyFormat validates the given ECDHESKeyFormat and returns the KW Curve.
+// validateKeyFormat validates the given ECDHESKeyFormat and returns the KW Curve, nil for X25519 keys.
 func validateKeyFormat(params *ecdhespb.EcdhesAeadParams) (elliptic.Curve, error) {
-	c, err := hybrid.GetCurve(params.KwParams.CurveType.String())
-	if err != nil {
-		return nil, err
+	var (
+		c   elliptic.Curve
+		err error
+	)
+
+	if params.KwParams.CurveType != x25519CurveType {
+		c, err = hybrid.GetCurve(params.KwParams.CurveType.String())
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	km, err := registry.GetKeyManager(params.EncParams.AeadEnc.TypeUrl)
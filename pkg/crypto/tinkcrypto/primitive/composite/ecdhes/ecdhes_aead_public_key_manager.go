@@ -16,6 +16,7 @@ import (
 	"github.com/google/tink/go/keyset"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/google/tink/go/subtle/hybrid"
+	"golang.org/x/crypto/curve25519"
 
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdhes/subtle"
 	ecdhespb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdhes_aead_go_proto"
@@ -59,7 +60,10 @@ func (km *ecdhesPublicKeyManager) Primitive(serializedKey []byte) (interface{},
 		return nil, errInvalidECDHESPublicKey
 	}
 
-	var recipientsKeys []*hybrid.ECPublicKey
+	var (
+		recipientsKeys       []*hybrid.ECPublicKey
+		x25519RecipientsKeys [][]byte
+	)
 
 	for _, recKey := range ecdhesPubKey.Params.KwParams.Recipients {
 		recCurve, e := km.validateRecKey(recKey)
@@ -67,6 +71,12 @@ func (km *ecdhesPublicKeyManager) Primitive(serializedKey []byte) (interface{},
 			return nil, errInvalidECDHESPublicKey
 		}
 
+		if recKey.CurveType == x25519CurveType {
+			x25519RecipientsKeys = append(x25519RecipientsKeys, recKey.X)
+
+			continue
+		}
+
 		pub := &hybrid.ECPublicKey{
 			Curve: recCurve,
 			Point: hybrid.ECPoint{
@@ -85,7 +95,7 @@ func (km *ecdhesPublicKeyManager) Primitive(serializedKey []byte) (interface{},
 
 	ptFormat := ecdhesPubKey.Params.EcPointFormat.String()
 
-	return subtle.NewECDHESAEADCompositeEncrypt(recipientsKeys, ptFormat, rEnc)
+	return subtle.NewECDHESAEADCompositeEncrypt(recipientsKeys, x25519RecipientsKeys, ptFormat, rEnc)
 }
 
 // DoesSupport indicates if this key manager supports the given key type.
@@ -118,13 +128,21 @@ func (km *ecdhesPublicKeyManager) validateKey(key *ecdhespb.EcdhesAeadPublicKey)
 	return validateKeyFormat(key.Params)
 }
 
-// validateRecKey validates the given recipient's ECDHESPublicKey.
+// validateRecKey validates the given recipient's ECDHESPublicKey and returns its curve, nil for X25519 keys.
 func (km *ecdhesPublicKeyManager) validateRecKey(key *ecdhespb.EcdhesAeadRecipientPublicKey) (elliptic.Curve, error) {
 	err := keyset.ValidateKeyVersion(key.Version, ecdhesPublicKeyVersion)
 	if err != nil {
 		return nil, fmt.Errorf("ecdhes_public_key_manager: invalid key: %s", err)
 	}
 
+	if key.CurveType == x25519CurveType {
+		if len(key.X) != curve25519.PointSize {
+			return nil, fmt.Errorf("ecdhes_public_key_manager: invalid X25519 key")
+		}
+
+		return nil, nil
+	}
+
 	c, err := hybrid.GetCurve(key.CurveType.String())
 	if err != nil {
 		return nil, err
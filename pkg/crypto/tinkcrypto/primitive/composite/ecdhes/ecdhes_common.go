@@ -12,6 +12,10 @@ import (
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 )
 
+// x25519CurveType is the curve type of X25519 keys. The Tink version in use doesn't define it yet, its value is the
+// one of CURVE25519 in later versions.
+const x25519CurveType = commonpb.EllipticCurveType(5)
+
 // GetCurveType is a utility function that converts a string EC curve name into an EC curve proto type
 func GetCurveType(curve string) (commonpb.EllipticCurveType, error) {
 	switch curve {
@@ -21,6 +25,8 @@ func GetCurveType(curve string) (commonpb.EllipticCurveType, error) {
 		return commonpb.EllipticCurveType_NIST_P384, nil
 	case "secp521r1", "NIST_P521", "P-521", "EllipticCurveType_NIST_P521":
 		return commonpb.EllipticCurveType_NIST_P521, nil
+	case "X25519", "CURVE25519":
+		return x25519CurveType, nil
 	default:
 		return 0, fmt.Errorf("curve %s not supported", curve)
 	}
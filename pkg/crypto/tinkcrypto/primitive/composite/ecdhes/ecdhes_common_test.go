@@ -92,6 +92,12 @@ func TestGetCurveType(t *testing.T) {
 			expectedType: commonpb.EllipticCurveType_NIST_P521,
 			isError:      false,
 		},
+		{
+			tcName:       "test get X25519 curve type",
+			curveName:    "X25519",
+			expectedType: x25519CurveType,
+			isError:      false,
+		},
 		{
 			tcName:       "test unsupported curve type",
 			curveName:    "bad.curve",
@@ -90,8 +90,9 @@ func writePubKey(w io.Writer, key *tinkpb.Keyset_Key) (bool, error) {
 
 		curveName := commonpb.EllipticCurveType_name[int32(pubKeyProto.Params.KwParams.CurveType)]
 
-		curve := subtle.GetCurve(curveName)
-		if curve == nil {
+		if pubKeyProto.Params.KwParams.CurveType == x25519CurveType {
+			curveName = ecdhessubtle.X25519Curve
+		} else if subtle.GetCurve(curveName) == nil {
 			return false, fmt.Errorf("undefined curve")
 		}
 
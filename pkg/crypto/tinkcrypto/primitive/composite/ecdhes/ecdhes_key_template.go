@@ -40,6 +40,53 @@ func ECDHES256KWAES256GCMKeyTemplateWithRecipients(recPublicKeys []subtle.ECPubl
 		aead.AES256GCMKeyTemplate(), tinkpb.OutputPrefixType_RAW, ecdhesRecipientKeys), nil
 }
 
+// ECDHES256KWXChaCha20Poly1305KeyTemplate is similar to ECDHES256KWAES256GCMKeyTemplate but with XChaCha20Poly1305
+// content encryption (XC20P), the content encryption of the jwe/authcrypt packer.
+func ECDHES256KWXChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(commonpb.EllipticCurveType_NIST_P256, commonpb.EcPointFormat_UNCOMPRESSED,
+		aead.XChaCha20Poly1305KeyTemplate(), tinkpb.OutputPrefixType_RAW, nil)
+}
+
+// ECDHES256KWXChaCha20Poly1305KeyTemplateWithRecipients is similar to ECDHES256KWXChaCha20Poly1305KeyTemplate but
+// adding recipients keys to execute the CompositeEncrypt primitive, its keys should not be stored in the KMS.
+func ECDHES256KWXChaCha20Poly1305KeyTemplateWithRecipients(
+	recPublicKeys []subtle.ECPublicKey) (*tinkpb.KeyTemplate, error) {
+	ecdhesRecipientKeys, err := createECDHESPublicKeys(recPublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return createKeyTemplate(commonpb.EllipticCurveType_NIST_P256, commonpb.EcPointFormat_UNCOMPRESSED,
+		aead.XChaCha20Poly1305KeyTemplate(), tinkpb.OutputPrefixType_RAW, ecdhesRecipientKeys), nil
+}
+
+// ECDHES256KWChaCha20Poly1305KeyTemplate is similar to ECDHES256KWAES256GCMKeyTemplate but with ChaCha20Poly1305
+// content encryption (C20P).
+func ECDHES256KWChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(commonpb.EllipticCurveType_NIST_P256, commonpb.EcPointFormat_UNCOMPRESSED,
+		aead.ChaCha20Poly1305KeyTemplate(), tinkpb.OutputPrefixType_RAW, nil)
+}
+
+// ECDHES256KWChaCha20Poly1305KeyTemplateWithRecipients is similar to ECDHES256KWChaCha20Poly1305KeyTemplate but
+// adding recipients keys to execute the CompositeEncrypt primitive, its keys should not be stored in the KMS.
+func ECDHES256KWChaCha20Poly1305KeyTemplateWithRecipients(
+	recPublicKeys []subtle.ECPublicKey) (*tinkpb.KeyTemplate, error) {
+	ecdhesRecipientKeys, err := createECDHESPublicKeys(recPublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return createKeyTemplate(commonpb.EllipticCurveType_NIST_P256, commonpb.EcPointFormat_UNCOMPRESSED,
+		aead.ChaCha20Poly1305KeyTemplate(), tinkpb.OutputPrefixType_RAW, ecdhesRecipientKeys), nil
+}
+
+// ECDHESX25519KWXChaCha20Poly1305KeyTemplate is similar to ECDHES256KWXChaCha20Poly1305KeyTemplate but generates an
+// X25519 key, the key agreement key type of the jwe/authcrypt packer.
+func ECDHESX25519KWXChaCha20Poly1305KeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(x25519CurveType, commonpb.EcPointFormat_UNCOMPRESSED,
+		aead.XChaCha20Poly1305KeyTemplate(), tinkpb.OutputPrefixType_RAW, nil)
+}
+
 func createECDHESPublicKeys(recRawPublicKeys []subtle.ECPublicKey) ([]*ecdhespb.EcdhesAeadRecipientPublicKey, error) {
 	var recKeys []*ecdhespb.EcdhesAeadRecipientPublicKey
 
@@ -62,8 +109,6 @@ func createECDHESPublicKeys(recRawPublicKeys []subtle.ECPublicKey) ([]*ecdhespb.
 	return recKeys, nil
 }
 
-// TODO add chacha key templates as well https://github.com/hyperledger/aries-framework-go/issues/1637
-
 // createKeyTemplate creates a new ECDHES-AEAD key template with the given key
 // size in bytes.
 func createKeyTemplate(c commonpb.EllipticCurveType, epf commonpb.EcPointFormat, contentEncKeyT *tinkpb.KeyTemplate,
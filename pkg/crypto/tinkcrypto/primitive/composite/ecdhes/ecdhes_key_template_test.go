@@ -12,45 +12,133 @@ import (
 	"testing"
 
 	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/stretchr/testify/require"
 
 	ecdhessubtle "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdhes/subtle"
 )
 
 func TestECDHESKeyTemplateTest(t *testing.T) {
-	recPubKeys, recKHs := createRecipients(t, 10)
-	kt, err := ECDHES256KWAES256GCMKeyTemplateWithRecipients(recPubKeys)
-	require.NoError(t, err)
+	tests := []struct {
+		name                  string
+		recipientKT           func() *tinkpb.KeyTemplate
+		senderKTWithRecipient func([]ecdhessubtle.ECPublicKey) (*tinkpb.KeyTemplate, error)
+		encAlg                string
+	}{
+		{
+			name:                  "AES256GCM content encryption",
+			recipientKT:           ECDHES256KWAES256GCMKeyTemplate,
+			senderKTWithRecipient: ECDHES256KWAES256GCMKeyTemplateWithRecipients,
+			encAlg:                ecdhessubtle.A256GCM,
+		},
+		{
+			name:                  "XChaCha20Poly1305 content encryption",
+			recipientKT:           ECDHES256KWXChaCha20Poly1305KeyTemplate,
+			senderKTWithRecipient: ECDHES256KWXChaCha20Poly1305KeyTemplateWithRecipients,
+			encAlg:                ecdhessubtle.XC20P,
+		},
+		{
+			name:                  "X25519 keys with XChaCha20Poly1305 content encryption",
+			recipientKT:           ECDHESX25519KWXChaCha20Poly1305KeyTemplate,
+			senderKTWithRecipient: ECDHES256KWXChaCha20Poly1305KeyTemplateWithRecipients,
+			encAlg:                ecdhessubtle.XC20P,
+		},
+		{
+			name:                  "ChaCha20Poly1305 content encryption",
+			recipientKT:           ECDHES256KWChaCha20Poly1305KeyTemplate,
+			senderKTWithRecipient: ECDHES256KWChaCha20Poly1305KeyTemplateWithRecipients,
+			encAlg:                ecdhessubtle.C20P,
+		},
+	}
 
-	kh, err := keyset.NewHandle(kt)
-	require.NoError(t, err)
+	for _, tt := range tests {
+		tc := tt
 
-	pubKH, err := kh.Public()
-	require.NoError(t, err)
+		t.Run(tc.name, func(t *testing.T) {
+			recPubKeys, recKHs := createRecipients(t, 10, tc.recipientKT())
+			kt, err := tc.senderKTWithRecipient(recPubKeys)
+			require.NoError(t, err)
 
-	e, err := NewECDHESEncrypt(pubKH)
-	require.NoError(t, err)
+			kh, err := keyset.NewHandle(kt)
+			require.NoError(t, err)
 
-	pt := []byte("secret message")
-	aad := []byte("aad message")
+			pubKH, err := kh.Public()
+			require.NoError(t, err)
 
-	ct, err := e.Encrypt(pt, aad)
-	require.NoError(t, err)
-	require.NotEmpty(t, ct)
+			e, err := NewECDHESEncrypt(pubKH)
+			require.NoError(t, err)
+
+			pt := []byte("secret message")
+			aad := []byte("aad message")
+
+			ct, err := e.Encrypt(pt, aad)
+			require.NoError(t, err)
+			require.NotEmpty(t, ct)
+
+			encData := &ecdhessubtle.EncryptedData{}
+			require.NoError(t, json.Unmarshal(ct, encData))
+			require.Equal(t, tc.encAlg, encData.EncAlg)
+
+			for _, rec := range encData.Recipients {
+				if recPubKeys[0].Curve == ecdhessubtle.X25519Curve {
+					require.Equal(t, ecdhessubtle.X25519Curve, rec.EPK.Curve)
+				}
+			}
 
-	// decrypt for all Recipients
-	for _, recKH := range recKHs {
-		d, er := NewECDHESDecrypt(recKH)
-		require.NoError(t, er)
+			// decrypt for all Recipients
+			for _, recKH := range recKHs {
+				d, er := NewECDHESDecrypt(recKH)
+				require.NoError(t, er)
 
-		dpt, er := d.Decrypt(ct, aad)
-		require.NoError(t, er)
-		require.Equal(t, pt, dpt)
+				dpt, er := d.Decrypt(ct, aad)
+				require.NoError(t, er)
+				require.Equal(t, pt, dpt)
+			}
+
+			// the content encryption is set by the sender, a recipient key created for another one decrypts too
+			otherKT := ECDHES256KWAES256GCMKeyTemplate()
+			if tc.encAlg == ecdhessubtle.A256GCM {
+				otherKT = ECDHES256KWXChaCha20Poly1305KeyTemplate()
+			}
+
+			otherRecPubKeys, otherRecKHs := createRecipients(t, 1, otherKT)
+			kt, err = tc.senderKTWithRecipient(otherRecPubKeys)
+			require.NoError(t, err)
+
+			kh, err = keyset.NewHandle(kt)
+			require.NoError(t, err)
+
+			pubKH, err = kh.Public()
+			require.NoError(t, err)
+
+			e, err = NewECDHESEncrypt(pubKH)
+			require.NoError(t, err)
+
+			ct, err = e.Encrypt(pt, aad)
+			require.NoError(t, err)
+
+			d, err := NewECDHESDecrypt(otherRecKHs[0])
+			require.NoError(t, err)
+
+			dpt, err := d.Decrypt(ct, aad)
+			require.NoError(t, err)
+			require.Equal(t, pt, dpt)
+
+			// a key which is not a recipient can't decrypt
+			_, otherKH := createAndMarshalRecipient(t, tc.recipientKT())
+
+			d, err = NewECDHESDecrypt(otherKH)
+			require.NoError(t, err)
+
+			_, err = d.Decrypt(ct, aad)
+			require.EqualError(t, err, "ecdhes_factory: decryption failed")
+		})
 	}
 }
 
 // createRecipients and return their public key and keyset.Handle
-func createRecipients(t *testing.T, numberOfRecipients int) ([]ecdhessubtle.ECPublicKey, []*keyset.Handle) {
+func createRecipients(t *testing.T, numberOfRecipients int,
+	kt *tinkpb.KeyTemplate) ([]ecdhessubtle.ECPublicKey, []*keyset.Handle) {
 	t.Helper()
 
 	var (
@@ -59,7 +147,7 @@ func createRecipients(t *testing.T, numberOfRecipients int) ([]ecdhessubtle.ECPu
 	)
 
 	for i := 0; i < numberOfRecipients; i++ {
-		mrKey, kh := createAndMarshalRecipient(t)
+		mrKey, kh := createAndMarshalRecipient(t, kt)
 		ecPubKey := new(ecdhessubtle.ECPublicKey)
 		err := json.Unmarshal(mrKey, ecPubKey)
 		require.NoError(t, err)
@@ -73,10 +161,10 @@ func createRecipients(t *testing.T, numberOfRecipients int) ([]ecdhessubtle.ECPu
 
 // createAndMarshalRecipient creates a new recipient keyset.Handle, extracts public key, marshals it and returns
 // both marshalled public key and original recipient keyset.Handle
-func createAndMarshalRecipient(t *testing.T) ([]byte, *keyset.Handle) {
+func createAndMarshalRecipient(t *testing.T, kt *tinkpb.KeyTemplate) ([]byte, *keyset.Handle) {
 	t.Helper()
 
-	kh, err := keyset.NewHandle(ECDHES256KWAES256GCMKeyTemplate())
+	kh, err := keyset.NewHandle(kt)
 	require.NoError(t, err)
 
 	pubKH, err := kh.Public()
@@ -10,12 +10,13 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/core/registry"
 	gcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
 	chachapb "github.com/google/tink/go/proto/chacha20_poly1305_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	xchachapb "github.com/google/tink/go/proto/xchacha20_poly1305_go_proto"
-	"github.com/google/tink/go/subtle/aead"
+	subtleaead "github.com/google/tink/go/subtle/aead"
 	"github.com/google/tink/go/tink"
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/poly1305"
@@ -27,6 +28,8 @@ const (
 	aesGCMTypeURL            = "type.googleapis.com/google.crypto.tink.AesGcmKey"
 	chaCha20Poly1305TypeURL  = "type.googleapis.com/google.crypto.tink.ChaCha20Poly1305Key"
 	xChaCha20Poly1305TypeURL = "type.googleapis.com/google.crypto.tink.XChaCha20Poly1305Key"
+	// a128GCM is the JWA content encryption algorithm of AES128-GCM keys
+	a128GCM = "A128GCM"
 )
 
 // registerECDHESAEADEncHelper registers a content encryption helper
@@ -36,6 +39,7 @@ type registerECDHESAEADEncHelper struct {
 	symmetricKeySize int
 	tagSize          int
 	ivSize           int
+	encAlg           string
 }
 
 var _ subtle.EncrypterHelper = (*registerECDHESAEADEncHelper)(nil)
@@ -44,6 +48,7 @@ var _ subtle.EncrypterHelper = (*registerECDHESAEADEncHelper)(nil)
 func newRegisterECDHESAEADEncHelper(k *tinkpb.KeyTemplate) (*registerECDHESAEADEncHelper, error) {
 	var (
 		keySize, tagSize, ivSize int
+		encAlg                   string
 		skf                      []byte
 		err                      error
 	)
@@ -58,8 +63,9 @@ func newRegisterECDHESAEADEncHelper(k *tinkpb.KeyTemplate) (*registerECDHESAEADE
 		}
 
 		keySize = int(gcmKeyFormat.KeySize)
-		tagSize = aead.AESGCMTagSize
-		ivSize = aead.AESGCMIVSize
+		tagSize = subtleaead.AESGCMTagSize
+		ivSize = subtleaead.AESGCMIVSize
+		encAlg = fmt.Sprintf("A%dGCM", keySize*8)
 
 		skf, err = proto.Marshal(gcmKeyFormat)
 		if err != nil {
@@ -69,10 +75,12 @@ func newRegisterECDHESAEADEncHelper(k *tinkpb.KeyTemplate) (*registerECDHESAEADE
 		keySize = chacha20poly1305.KeySize
 		tagSize = poly1305.TagSize
 		ivSize = chacha20poly1305.NonceSize
+		encAlg = subtle.C20P
 	case xChaCha20Poly1305TypeURL:
 		keySize = chacha20poly1305.KeySize
 		tagSize = poly1305.TagSize
 		ivSize = chacha20poly1305.NonceSizeX
+		encAlg = subtle.XC20P
 	default:
 		return nil, fmt.Errorf("registerECDHESAEADEncHelper: unsupported AEAD content encryption key type: %s",
 			k.TypeUrl)
@@ -100,9 +108,27 @@ func newRegisterECDHESAEADEncHelper(k *tinkpb.KeyTemplate) (*registerECDHESAEADE
 		symmetricKeySize: keySize,
 		tagSize:          tagSize,
 		ivSize:           ivSize,
+		encAlg:           encAlg,
 	}, nil
 }
 
+// newEncHelperForEncAlg returns the registerECDHESAEADEncHelper of the JWA content encryption algorithm encAlg, it is
+// the subtle.EncrypterHelperFactory of the decryption primitives.
+func newEncHelperForEncAlg(encAlg string) (subtle.EncrypterHelper, error) {
+	switch encAlg {
+	case a128GCM:
+		return newRegisterECDHESAEADEncHelper(aead.AES128GCMKeyTemplate())
+	case subtle.A256GCM:
+		return newRegisterECDHESAEADEncHelper(aead.AES256GCMKeyTemplate())
+	case subtle.XC20P:
+		return newRegisterECDHESAEADEncHelper(aead.XChaCha20Poly1305KeyTemplate())
+	case subtle.C20P:
+		return newRegisterECDHESAEADEncHelper(aead.ChaCha20Poly1305KeyTemplate())
+	default:
+		return nil, fmt.Errorf("registerECDHESAEADEncHelper: content encryption algorithm '%s' not supported", encAlg)
+	}
+}
+
 // GetSymmetricKeySize returns the symmetric key size
 func (r *registerECDHESAEADEncHelper) GetSymmetricKeySize() int {
 	return r.symmetricKeySize
@@ -118,6 +144,11 @@ func (r *registerECDHESAEADEncHelper) GetIVSize() int {
 	return r.ivSize
 }
 
+// GetEncAlg returns the JWA content encryption algorithm of the primitive
+func (r *registerECDHESAEADEncHelper) GetEncAlg() string {
+	return r.encAlg
+}
+
 // GetAEAD returns the AEAD primitive from the DEM
 func (r *registerECDHESAEADEncHelper) GetAEAD(symmetricKeyValue []byte) (tink.AEAD, error) {
 	if len(symmetricKeyValue) != r.GetSymmetricKeySize() {
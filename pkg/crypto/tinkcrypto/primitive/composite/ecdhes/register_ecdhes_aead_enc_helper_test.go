@@ -19,6 +19,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/poly1305"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdhes/subtle"
 )
 
 var (
@@ -52,6 +54,17 @@ func TestCipherGetters(t *testing.T) {
 	}
 }
 
+func TestNewEncHelperForEncAlg(t *testing.T) {
+	for _, encAlg := range []string{a128GCM, subtle.A256GCM, subtle.XC20P, subtle.C20P} {
+		encHelper, err := newEncHelperForEncAlg(encAlg)
+		require.NoError(t, err)
+		require.Equal(t, encAlg, encHelper.GetEncAlg())
+	}
+
+	_, err := newEncHelperForEncAlg("A192GCM")
+	require.EqualError(t, err, "registerECDHESAEADEncHelper: content encryption algorithm 'A192GCM' not supported")
+}
+
 func TestUnsupportedKeyTemplates(t *testing.T) {
 	var uTemplates = []*tinkpb.KeyTemplate{
 		signature.ECDSAP256KeyTemplate(),
@@ -16,30 +16,40 @@ import (
 // ECDHESAEADCompositeDecrypt is an instance of ECDH-ES decryption with Concat KDF
 // and AEAD content decryption
 type ECDHESAEADCompositeDecrypt struct {
-	privateKey  *hybrid.ECPrivateKey
-	pointFormat string
-	encHelper   EncrypterHelper
+	privateKey       *hybrid.ECPrivateKey
+	x25519PrivateKey []byte
+	pointFormat      string
+	newEncHelper     EncrypterHelperFactory
 }
 
 // NewECDHESAEADCompositeDecrypt returns ECDH-ES composite decryption construct with Concat KDF/ECDH-ES key unwrapping
-// and AEAD payload decryption.
+// and AEAD payload decryption. The AEAD primitive is created by newEncHelper for the content encryption algorithm
+// of the encrypted data.
 func NewECDHESAEADCompositeDecrypt(pvt *hybrid.ECPrivateKey, ptFormat string,
-	encHelper EncrypterHelper) (*ECDHESAEADCompositeDecrypt, error) {
+	newEncHelper EncrypterHelperFactory) (*ECDHESAEADCompositeDecrypt, error) {
 	return &ECDHESAEADCompositeDecrypt{
-		privateKey:  pvt,
-		pointFormat: ptFormat,
-		encHelper:   encHelper,
+		privateKey:   pvt,
+		pointFormat:  ptFormat,
+		newEncHelper: newEncHelper,
+	}, nil
+}
+
+// NewECDHESX25519AEADCompositeDecrypt returns ECDH-ES composite decryption construct like
+// NewECDHESAEADCompositeDecrypt for the raw X25519 private key pvt.
+func NewECDHESX25519AEADCompositeDecrypt(pvt []byte,
+	newEncHelper EncrypterHelperFactory) (*ECDHESAEADCompositeDecrypt, error) {
+	return &ECDHESAEADCompositeDecrypt{
+		x25519PrivateKey: pvt,
+		newEncHelper:     newEncHelper,
 	}, nil
 }
 
 // Decrypt using composite ECDH-ES with a Concat KDF key unwrap and AEAD content decryption
 func (d *ECDHESAEADCompositeDecrypt) Decrypt(ciphertext, aad []byte) ([]byte, error) {
-	if d.privateKey == nil {
+	if d.privateKey == nil && d.x25519PrivateKey == nil {
 		return nil, fmt.Errorf("ECDHESAEADCompositeDecrypt: missing recipient private key for key unwrapping")
 	}
 
-	keySize := d.encHelper.GetSymmetricKeySize()
-
 	var cek []byte
 
 	encData := new(EncryptedData)
@@ -49,18 +59,16 @@ func (d *ECDHESAEADCompositeDecrypt) Decrypt(ciphertext, aad []byte) ([]byte, er
 		return nil, err
 	}
 
-	// TODO: add support for Chacha content encryption https://github.com/hyperledger/aries-framework-go/issues/1684
-	if encData.EncAlg != A256GCM {
-		return nil, fmt.Errorf("invalid content encryption algorihm '%s' for Decrypt()", encData.EncAlg)
+	// the content encryption algorithm is set by the sender (the JWE enc header)
+	encHelper, err := d.newEncHelper(encData.EncAlg)
+	if err != nil {
+		return nil, fmt.Errorf("ecdh-es decrypt: %w", err)
 	}
 
-	for _, rec := range encData.Recipients {
-		recipientKW := &ECDHESConcatKDFRecipientKW{
-			recipientPrivateKey: d.privateKey,
-		}
+	keySize := encHelper.GetSymmetricKeySize()
 
-		// TODO: add support for 25519 key unwrapping https://github.com/hyperledger/aries-framework-go/issues/1637
-		cek, err = recipientKW.unwrapKey(rec, keySize)
+	for _, rec := range encData.Recipients {
+		cek, err = d.unwrapKey(rec, keySize)
 		if err == nil {
 			break
 		}
@@ -70,7 +78,7 @@ func (d *ECDHESAEADCompositeDecrypt) Decrypt(ciphertext, aad []byte) ([]byte, er
 		return nil, fmt.Errorf("ecdh-es decrypt: cek unwrap failed for all recipients keys")
 	}
 
-	aead, err := d.encHelper.GetAEAD(cek)
+	aead, err := encHelper.GetAEAD(cek)
 	if err != nil {
 		return nil, err
 	}
@@ -83,3 +91,19 @@ func (d *ECDHESAEADCompositeDecrypt) Decrypt(ciphertext, aad []byte) ([]byte, er
 
 	return aead.Decrypt(finalCT, aad)
 }
+
+func (d *ECDHESAEADCompositeDecrypt) unwrapKey(rec *RecipientWrappedKey, keySize int) ([]byte, error) {
+	if d.x25519PrivateKey != nil {
+		recipientKW := &ECDHESX25519ConcatKDFRecipientKW{
+			recipientPrivateKey: d.x25519PrivateKey,
+		}
+
+		return recipientKW.unwrapKey(rec, keySize)
+	}
+
+	recipientKW := &ECDHESConcatKDFRecipientKW{
+		recipientPrivateKey: d.privateKey,
+	}
+
+	return recipientKW.unwrapKey(rec, keySize)
+}
@@ -16,24 +16,34 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/api"
 )
 
-// A256GCM is the default content encryption algorithm value as per
-// the JWA specification: https://tools.ietf.org/html/rfc7518#section-5.1
-const A256GCM = "A256GCM"
+const (
+	// A256GCM is the default content encryption algorithm value as per
+	// the JWA specification: https://tools.ietf.org/html/rfc7518#section-5.1
+	A256GCM = "A256GCM"
+	// XC20P is the XChacha20Poly1305 content encryption algorithm value as per
+	// https://tools.ietf.org/html/draft-amringer-jose-chacha-02#section-4.1
+	XC20P = "XC20P"
+	// C20P is the Chacha20Poly1305 content encryption algorithm value as per
+	// https://tools.ietf.org/html/draft-amringer-jose-chacha-02#section-4.1
+	C20P = "C20P"
+)
 
 // ECDHESAEADCompositeEncrypt is an instance of ECDH-ES encryption with Concat KDF
 // and AEAD content encryption
 type ECDHESAEADCompositeEncrypt struct {
-	recPublicKeys []*hybrid.ECPublicKey
-	pointFormat   string
-	encHelper     EncrypterHelper
+	recPublicKeys       []*hybrid.ECPublicKey
+	x25519RecPublicKeys [][]byte
+	pointFormat         string
+	encHelper           EncrypterHelper
 }
 
 var _ api.CompositeEncrypt = (*ECDHESAEADCompositeEncrypt)(nil)
 
 // NewECDHESAEADCompositeEncrypt returns ECDH-ES encryption construct with Concat KDF key wrapping
-// and AEAD content encryption
-func NewECDHESAEADCompositeEncrypt(recipientsKeys []*hybrid.ECPublicKey, ptFormat string,
-	encHelper EncrypterHelper) (*ECDHESAEADCompositeEncrypt, error) {
+// and AEAD content encryption. The CEK is wrapped for the NIST curves recipientsKeys and the raw X25519
+// x25519RecipientsKeys.
+func NewECDHESAEADCompositeEncrypt(recipientsKeys []*hybrid.ECPublicKey, x25519RecipientsKeys [][]byte,
+	ptFormat string, encHelper EncrypterHelper) (*ECDHESAEADCompositeEncrypt, error) {
 	var recipients []*hybrid.ECPublicKey
 
 	for _, pub := range recipientsKeys {
@@ -48,16 +58,23 @@ func NewECDHESAEADCompositeEncrypt(recipientsKeys []*hybrid.ECPublicKey, ptForma
 		recipients = append(recipients, pubKey)
 	}
 
+	var x25519Recipients [][]byte
+
+	for _, pub := range x25519RecipientsKeys {
+		x25519Recipients = append(x25519Recipients, append([]byte{}, pub...))
+	}
+
 	return &ECDHESAEADCompositeEncrypt{
-		recPublicKeys: recipients,
-		pointFormat:   ptFormat,
-		encHelper:     encHelper,
+		recPublicKeys:       recipients,
+		x25519RecPublicKeys: x25519Recipients,
+		pointFormat:         ptFormat,
+		encHelper:           encHelper,
 	}, nil
 }
 
 // Encrypt using composite ECDH-ES with a Concat KDF key wrap and AEAD content encryption
 func (e *ECDHESAEADCompositeEncrypt) Encrypt(plaintext, aad []byte) ([]byte, error) {
-	if len(e.recPublicKeys) == 0 {
+	if len(e.recPublicKeys) == 0 && len(e.x25519RecPublicKeys) == 0 {
 		return nil, fmt.Errorf("ECDHESAEADCompositeEncrypt: missing recipients public keys for key wrapping")
 	}
 
@@ -74,7 +91,20 @@ func (e *ECDHESAEADCompositeEncrypt) Encrypt(plaintext, aad []byte) ([]byte, err
 			cek:                cek,
 		}
 
-		// TODO: add support for 25519 key wrapping https://github.com/hyperledger/aries-framework-go/issues/1637
+		kek, err := senderKW.wrapKey(A256KWAlg, keySize)
+		if err != nil {
+			return nil, err
+		}
+
+		recipientsWK = append(recipientsWK, kek)
+	}
+
+	for _, rec := range e.x25519RecPublicKeys {
+		senderKW := &ECDHESX25519ConcatKDFSenderKW{
+			recipientPublicKey: rec,
+			cek:                cek,
+		}
+
 		kek, err := senderKW.wrapKey(A256KWAlg, keySize)
 		if err != nil {
 			return nil, err
@@ -98,7 +128,7 @@ func (e *ECDHESAEADCompositeEncrypt) Encrypt(plaintext, aad []byte) ([]byte, err
 	tagOffset := len(ctAndTag) - tagSize
 
 	encData := &EncryptedData{
-		EncAlg:     A256GCM, // TODO add chacha alg too, https://github.com/hyperledger/aries-framework-go/issues/1684
+		EncAlg:     e.encHelper.GetEncAlg(),
 		Ciphertext: ctAndTag[:tagOffset],
 		IV:         iv,
 		Tag:        ctAndTag[tagOffset:],
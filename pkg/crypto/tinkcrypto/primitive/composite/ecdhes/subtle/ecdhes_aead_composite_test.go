@@ -17,8 +17,12 @@ import (
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	subtleaead "github.com/google/tink/go/subtle/aead"
 	"github.com/google/tink/go/subtle/hybrid"
+	"github.com/google/tink/go/subtle/random"
 	"github.com/google/tink/go/tink"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/poly1305"
 )
 
 func TestEncryptDecrypt(t *testing.T) {
@@ -30,9 +34,10 @@ func TestEncryptDecrypt(t *testing.T) {
 		AEADValue:    aeadPrimitive,
 		TagSizeValue: subtleaead.AESGCMTagSize,
 		IVSizeValue:  subtleaead.AESGCMIVSize,
+		EncAlgValue:  A256GCM,
 	}
 
-	cEnc, err := NewECDHESAEADCompositeEncrypt(recipientsPubKeys, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+	cEnc, err := NewECDHESAEADCompositeEncrypt(recipientsPubKeys, nil, commonpb.EcPointFormat_UNCOMPRESSED.String(),
 		mEncHelper)
 	require.NoError(t, err)
 
@@ -43,7 +48,8 @@ func TestEncryptDecrypt(t *testing.T) {
 	require.NoError(t, err)
 
 	for _, privKey := range recipientsPrivKeys {
-		dEnc, err := NewECDHESAEADCompositeDecrypt(privKey, commonpb.EcPointFormat_UNCOMPRESSED.String(), mEncHelper)
+		dEnc, err := NewECDHESAEADCompositeDecrypt(privKey, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+			mEncHelper.NewEncHelper)
 		require.NoError(t, err)
 
 		dpt, err := dEnc.Decrypt(ct, aad)
@@ -52,6 +58,61 @@ func TestEncryptDecrypt(t *testing.T) {
 	}
 }
 
+func TestEncryptDecryptX25519(t *testing.T) {
+	recipientsPrivKeys, recipientsPubKeys := buildRecipientsKeys(t, 2)
+	x25519PrivKeys, x25519PubKeys := buildX25519RecipientsKeys(t, 2)
+
+	mEncHelper := &MockEncHelper{
+		KeySizeValue: chacha20poly1305.KeySize,
+		AEADValue:    getAEADPrimitive(t, aead.XChaCha20Poly1305KeyTemplate()),
+		TagSizeValue: poly1305.TagSize,
+		IVSizeValue:  chacha20poly1305.NonceSizeX,
+		EncAlgValue:  XC20P,
+	}
+
+	cEnc, err := NewECDHESAEADCompositeEncrypt(recipientsPubKeys, x25519PubKeys,
+		commonpb.EcPointFormat_UNCOMPRESSED.String(), mEncHelper)
+	require.NoError(t, err)
+
+	pt := []byte("secret message")
+	aad := []byte("aad message")
+
+	ct, err := cEnc.Encrypt(pt, aad)
+	require.NoError(t, err)
+
+	encData := new(EncryptedData)
+	require.NoError(t, json.Unmarshal(ct, encData))
+	require.Len(t, encData.Recipients, 4)
+	require.Equal(t, X25519Curve, encData.Recipients[3].EPK.Curve)
+
+	for _, privKey := range x25519PrivKeys {
+		dEnc, err := NewECDHESX25519AEADCompositeDecrypt(privKey, mEncHelper.NewEncHelper)
+		require.NoError(t, err)
+
+		dpt, err := dEnc.Decrypt(ct, aad)
+		require.NoError(t, err)
+		require.EqualValues(t, pt, dpt)
+	}
+
+	for _, privKey := range recipientsPrivKeys {
+		dEnc, err := NewECDHESAEADCompositeDecrypt(privKey, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+			mEncHelper.NewEncHelper)
+		require.NoError(t, err)
+
+		dpt, err := dEnc.Decrypt(ct, aad)
+		require.NoError(t, err)
+		require.EqualValues(t, pt, dpt)
+	}
+
+	otherPrivKeys, _ := buildX25519RecipientsKeys(t, 1)
+
+	dEnc, err := NewECDHESX25519AEADCompositeDecrypt(otherPrivKeys[0], mEncHelper.NewEncHelper)
+	require.NoError(t, err)
+
+	_, err = dEnc.Decrypt(ct, aad)
+	require.EqualError(t, err, "ecdh-es decrypt: cek unwrap failed for all recipients keys")
+}
+
 func TestEncryptDecryptNegativeTCs(t *testing.T) {
 	recipientsPrivKeys, recipientsPubKeys := buildRecipientsKeys(t, 10)
 	aeadPrimitive := getAEADPrimitive(t, aead.AES256GCMKeyTemplate())
@@ -61,13 +122,14 @@ func TestEncryptDecryptNegativeTCs(t *testing.T) {
 		AEADValue:    aeadPrimitive,
 		TagSizeValue: subtleaead.AESGCMTagSize,
 		IVSizeValue:  subtleaead.AESGCMIVSize,
+		EncAlgValue:  A256GCM,
 	}
 
 	pt := []byte("secret message")
 	aad := []byte("aad message")
 
 	// test with empty recipients public keys
-	cEnc, err := NewECDHESAEADCompositeEncrypt(nil, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+	cEnc, err := NewECDHESAEADCompositeEncrypt(nil, nil, commonpb.EcPointFormat_UNCOMPRESSED.String(),
 		mEncHelper)
 	require.NoError(t, err)
 
@@ -78,7 +140,7 @@ func TestEncryptDecryptNegativeTCs(t *testing.T) {
 	// test with large key size
 	mEncHelper.KeySizeValue = 100
 
-	cEnc, err = NewECDHESAEADCompositeEncrypt(recipientsPubKeys, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+	cEnc, err = NewECDHESAEADCompositeEncrypt(recipientsPubKeys, nil, commonpb.EcPointFormat_UNCOMPRESSED.String(),
 		mEncHelper)
 	require.NoError(t, err)
 
@@ -91,7 +153,7 @@ func TestEncryptDecryptNegativeTCs(t *testing.T) {
 	// test with GetAEAD() returning error
 	mEncHelper.AEADErrValue = fmt.Errorf("error from GetAEAD")
 
-	cEnc, err = NewECDHESAEADCompositeEncrypt(recipientsPubKeys, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+	cEnc, err = NewECDHESAEADCompositeEncrypt(recipientsPubKeys, nil, commonpb.EcPointFormat_UNCOMPRESSED.String(),
 		mEncHelper)
 	require.NoError(t, err)
 
@@ -102,7 +164,7 @@ func TestEncryptDecryptNegativeTCs(t *testing.T) {
 	mEncHelper.AEADErrValue = nil
 
 	// create a valid ciphertext to test Decrypt for all recipients
-	cEnc, err = NewECDHESAEADCompositeEncrypt(recipientsPubKeys, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+	cEnc, err = NewECDHESAEADCompositeEncrypt(recipientsPubKeys, nil, commonpb.EcPointFormat_UNCOMPRESSED.String(),
 		mEncHelper)
 	require.NoError(t, err)
 
@@ -124,7 +186,8 @@ func TestEncryptDecryptNegativeTCs(t *testing.T) {
 
 	for _, privKey := range recipientsPrivKeys {
 		// test with nil recipient private key
-		dEnc, err := NewECDHESAEADCompositeDecrypt(nil, commonpb.EcPointFormat_UNCOMPRESSED.String(), mEncHelper)
+		dEnc, err := NewECDHESAEADCompositeDecrypt(nil, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+			mEncHelper.NewEncHelper)
 		require.NoError(t, err)
 
 		_, err = dEnc.Decrypt(ct, aad)
@@ -132,7 +195,8 @@ func TestEncryptDecryptNegativeTCs(t *testing.T) {
 
 		// test with large key size
 		mEncHelper.KeySizeValue = 100
-		dEnc, err = NewECDHESAEADCompositeDecrypt(privKey, commonpb.EcPointFormat_UNCOMPRESSED.String(), mEncHelper)
+		dEnc, err = NewECDHESAEADCompositeDecrypt(privKey, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+			mEncHelper.NewEncHelper)
 		require.NoError(t, err)
 
 		_, err = dEnc.Decrypt(ct, aad)
@@ -143,7 +207,8 @@ func TestEncryptDecryptNegativeTCs(t *testing.T) {
 		// test with GetAEAD() returning error
 		mEncHelper.AEADErrValue = fmt.Errorf("error from GetAEAD")
 
-		dEnc, err = NewECDHESAEADCompositeDecrypt(privKey, commonpb.EcPointFormat_UNCOMPRESSED.String(), mEncHelper)
+		dEnc, err = NewECDHESAEADCompositeDecrypt(privKey, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+			mEncHelper.NewEncHelper)
 		require.NoError(t, err)
 
 		_, err = dEnc.Decrypt(ct, aad)
@@ -152,7 +217,8 @@ func TestEncryptDecryptNegativeTCs(t *testing.T) {
 		mEncHelper.AEADErrValue = nil
 
 		// create a valid Decrypt message and test against ct
-		dEnc, err = NewECDHESAEADCompositeDecrypt(privKey, commonpb.EcPointFormat_UNCOMPRESSED.String(), mEncHelper)
+		dEnc, err = NewECDHESAEADCompositeDecrypt(privKey, commonpb.EcPointFormat_UNCOMPRESSED.String(),
+			mEncHelper.NewEncHelper)
 		require.NoError(t, err)
 
 		// try decrypting empty ct
@@ -202,6 +268,24 @@ func buildRecipientsKeys(t *testing.T, nbOfRecipients int) ([]*hybrid.ECPrivateK
 	return recipientsPrivKeys, recipientsPubKeys
 }
 
+func buildX25519RecipientsKeys(t *testing.T, nbOfRecipients int) ([][]byte, [][]byte) {
+	t.Helper()
+
+	var recipientsPrivKeys, recipientsPubKeys [][]byte
+
+	for i := 0; i < nbOfRecipients; i++ {
+		recipientPriv := random.GetRandomBytes(curve25519.ScalarSize)
+
+		recipientPub, err := curve25519.X25519(recipientPriv, curve25519.Basepoint)
+		require.NoError(t, err)
+
+		recipientsPrivKeys = append(recipientsPrivKeys, recipientPriv)
+		recipientsPubKeys = append(recipientsPubKeys, recipientPub)
+	}
+
+	return recipientsPrivKeys, recipientsPubKeys
+}
+
 func getAEADPrimitive(t *testing.T, kt *tinkpb.KeyTemplate) tink.AEAD {
 	t.Helper()
 
@@ -224,6 +308,7 @@ type MockEncHelper struct {
 	AEADErrValue error
 	TagSizeValue int
 	IVSizeValue  int
+	EncAlgValue  string
 }
 
 // GetSymmetricKeySize gives the size of the Encryption key (CEK) in bytes
@@ -245,3 +330,17 @@ func (me *MockEncHelper) GetTagSize() int {
 func (me *MockEncHelper) GetIVSize() int {
 	return me.IVSizeValue
 }
+
+// GetEncAlg provides the JWA content encryption algorithm of the aead primitive
+func (me *MockEncHelper) GetEncAlg() string {
+	return me.EncAlgValue
+}
+
+// NewEncHelper returns the mocked AEAD helper for its content encryption algorithm
+func (me *MockEncHelper) NewEncHelper(encAlg string) (EncrypterHelper, error) {
+	if encAlg != me.EncAlgValue {
+		return nil, fmt.Errorf("content encryption algorithm '%s' not supported", encAlg)
+	}
+
+	return me, nil
+}
@@ -23,4 +23,11 @@ type EncrypterHelper interface {
 
 	// GetIVSize provides the aead primitive nonce size
 	GetIVSize() int
+
+	// GetEncAlg provides the JWA content encryption algorithm of the aead primitive, eg A256GCM or XC20P
+	GetEncAlg() string
 }
+
+// EncrypterHelperFactory returns the EncrypterHelper of the JWA content encryption algorithm encAlg, eg A256GCM or
+// XC20P. It is used for decryption since the content encryption algorithm is chosen by the sender.
+type EncrypterHelperFactory func(encAlg string) (EncrypterHelper, error)
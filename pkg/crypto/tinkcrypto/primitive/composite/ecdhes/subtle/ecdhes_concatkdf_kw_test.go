@@ -47,3 +47,41 @@ func TestWrap(t *testing.T) {
 	_, err = recipientKW.unwrapKey(nil, keySize)
 	require.Error(t, err)
 }
+
+func TestWrapX25519(t *testing.T) {
+	keySize := 32
+	x25519PrivKeys, x25519PubKeys := buildX25519RecipientsKeys(t, 1)
+
+	senderKW := &ECDHESX25519ConcatKDFSenderKW{
+		recipientPublicKey: x25519PubKeys[0],
+		cek:                random.GetRandomBytes(uint32(keySize)),
+	}
+
+	wrappedKey, err := senderKW.wrapKey(A256KWAlg, keySize)
+	require.NoError(t, err)
+	require.EqualValues(t, A256KWAlg, wrappedKey.Alg)
+	require.Equal(t, X25519Curve, wrappedKey.EPK.Curve)
+	require.Len(t, wrappedKey.EPK.X, 32)
+
+	recipientKW := &ECDHESX25519ConcatKDFRecipientKW{
+		recipientPrivateKey: x25519PrivKeys[0],
+	}
+
+	cek, err := recipientKW.unwrapKey(wrappedKey, keySize)
+	require.NoError(t, err)
+	require.EqualValues(t, senderKW.cek, cek)
+
+	// error test cases
+	_, err = recipientKW.unwrapKey(nil, keySize)
+	require.Error(t, err)
+
+	wrappedKey.EPK.Curve = commonpb.EllipticCurveType_NIST_P256.String()
+
+	_, err = recipientKW.unwrapKey(wrappedKey, keySize)
+	require.EqualError(t, err, "unwrapKey: EPK curve 'NIST_P256' is not X25519")
+
+	senderKW.recipientPublicKey = []byte("bad key")
+
+	_, err = senderKW.wrapKey(A256KWAlg, keySize)
+	require.Error(t, err)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"crypto"
+	"crypto/aes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/google/tink/go/subtle/random"
+	josecipher "github.com/square/go-jose/v3/cipher"
+	"golang.org/x/crypto/curve25519"
+)
+
+// X25519Curve is the curve name of X25519 keys, the raw 32 bytes key being set in the X field of ECPublicKey
+const X25519Curve = "X25519"
+
+// ECDHESX25519ConcatKDFSenderKW represents concat KDF based ECDH-ES KW (key wrapping)
+// for ECDH-ES sender with an X25519 recipient key
+type ECDHESX25519ConcatKDFSenderKW struct {
+	recipientPublicKey []byte
+	cek                []byte
+}
+
+// wrapKey will do ECDH-ES key wrapping with an X25519 ephemeral key
+func (s *ECDHESX25519ConcatKDFSenderKW) wrapKey(kwAlg string, keySize int) (*RecipientWrappedKey, error) {
+	ephemeralPriv := random.GetRandomBytes(curve25519.ScalarSize)
+
+	ephemeralPub, err := curve25519.X25519(ephemeralPriv, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := curve25519.X25519(ephemeralPriv, s.recipientPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(deriveX25519KEK(kwAlg, z, keySize))
+	if err != nil {
+		return nil, err
+	}
+
+	wk, err := josecipher.KeyWrap(block, s.cek)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecipientWrappedKey{
+		EncryptedCEK: wk,
+		EPK: ECPublicKey{
+			X:     ephemeralPub,
+			Curve: X25519Curve,
+		},
+		Alg: kwAlg,
+	}, nil
+}
+
+// ECDHESX25519ConcatKDFRecipientKW represents concat KDF based ECDH-ES KW (key wrapping)
+// for ECDH-ES recipient's unwrapping of CEK with an X25519 private key
+type ECDHESX25519ConcatKDFRecipientKW struct {
+	recipientPrivateKey []byte
+}
+
+// unwrapKey will do ECDH-ES key unwrapping with an X25519 ephemeral key
+func (s *ECDHESX25519ConcatKDFRecipientKW) unwrapKey(recWK *RecipientWrappedKey, keySize int) ([]byte, error) {
+	if recWK == nil {
+		return nil, fmt.Errorf("unwrapKey: RecipientWrappedKey is empty")
+	}
+
+	if recWK.EPK.Curve != X25519Curve {
+		return nil, fmt.Errorf("unwrapKey: EPK curve '%s' is not %s", recWK.EPK.Curve, X25519Curve)
+	}
+
+	z, err := curve25519.X25519(s.recipientPrivateKey, recWK.EPK.X)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(deriveX25519KEK(recWK.Alg, z, keySize))
+	if err != nil {
+		return nil, err
+	}
+
+	return josecipher.KeyUnwrap(block, recWK.EncryptedCEK)
+}
+
+// deriveX25519KEK derives a key encryption key of keySize bytes from the X25519 shared secret z with Concat KDF, the
+// same way josecipher.DeriveECDHES does for NIST curves keys
+func deriveX25519KEK(kwAlg string, z []byte, keySize int) []byte {
+	supPubInfo := make([]byte, 4)
+	binary.BigEndian.PutUint32(supPubInfo, uint32(keySize)*8)
+
+	reader := josecipher.NewConcatKDF(crypto.SHA256, z, lengthPrefixed([]byte(kwAlg)), lengthPrefixed([]byte{}),
+		lengthPrefixed([]byte{}), supPubInfo, []byte{})
+
+	kek := make([]byte, keySize)
+	_, _ = reader.Read(kek) //nolint:errcheck // Concat KDF reads never fail
+
+	return kek
+}
+
+func lengthPrefixed(data []byte) []byte {
+	out := make([]byte, len(data)+4)
+	binary.BigEndian.PutUint32(out, uint32(len(data)))
+	copy(out[4:], data)
+
+	return out
+}
@@ -0,0 +1,38 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package secp256k1 provides the Tink key managers and key templates of ECDSA signature keys over the secp256k1
+// curve, as used by the EcdsaSecp256k1Signature2019 suite and ES256K JWS.
+//
+// Tink's ECDSA key protos are reused to serialize the keys, the curve being given by the key type URL (the proto
+// curve is left UNKNOWN_CURVE since Tink doesn't define secp256k1). Once this package is imported, the keys are
+// created, signed and verified with the usual Tink APIs:
+//
+//  kh, err := keyset.NewHandle(secp256k1.IEEEP1363KeyTemplate())
+//  s, err := signature.NewSigner(kh)
+//  sig, err := s.Sign(msg)
+package secp256k1
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+)
+
+// TODO - find a better way to setup tink than init.
+// nolint: gochecknoinits
+func init() {
+	// TODO - avoid the tink registry singleton.
+	err := registry.RegisterKeyManager(newSecp256k1SignerKeyManager())
+	if err != nil {
+		panic(fmt.Sprintf("secp256k1.init() failed: %v", err))
+	}
+
+	err = registry.RegisterKeyManager(newSecp256k1VerifierKeyManager())
+	if err != nil {
+		panic(fmt.Sprintf("secp256k1.init() failed: %v", err))
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secp256k1
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"github.com/google/tink/go/signature"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecp256k1KeyTemplates(t *testing.T) {
+	for _, kt := range []*tinkpb.KeyTemplate{DERKeyTemplate(), IEEEP1363KeyTemplate()} {
+		kh, err := keyset.NewHandle(kt)
+		require.NoError(t, err)
+
+		s, err := signature.NewSigner(kh)
+		require.NoError(t, err)
+
+		msg := []byte("test message")
+
+		sig, err := s.Sign(msg)
+		require.NoError(t, err)
+
+		pubKH, err := kh.Public()
+		require.NoError(t, err)
+
+		v, err := signature.NewVerifier(pubKH)
+		require.NoError(t, err)
+
+		require.NoError(t, v.Verify(sig, msg))
+		require.Error(t, v.Verify(sig, []byte("other message")))
+	}
+}
+
+func TestSecp256k1SignerKeyManager(t *testing.T) {
+	km := newSecp256k1SignerKeyManager()
+
+	require.True(t, km.DoesSupport(secp256k1SignerTypeURL))
+	require.Equal(t, secp256k1SignerTypeURL, km.TypeURL())
+
+	t.Run("test invalid key", func(t *testing.T) {
+		_, err := km.Primitive(nil)
+		require.EqualError(t, err, errInvalidSecp256k1SignKey.Error())
+
+		_, err = km.Primitive([]byte("bad key"))
+		require.EqualError(t, err, errInvalidSecp256k1SignKey.Error())
+
+		key := &ecdsapb.EcdsaPrivateKey{Version: 1}
+		serializedKey, err := proto.Marshal(key)
+		require.NoError(t, err)
+
+		_, err = km.Primitive(serializedKey)
+		require.Error(t, err)
+
+		key.Version = 0
+		serializedKey, err = proto.Marshal(key)
+		require.NoError(t, err)
+
+		_, err = km.Primitive(serializedKey)
+		require.EqualError(t, err, errInvalidSecp256k1SignKey.Error())
+
+		key.PublicKey = &ecdsapb.EcdsaPublicKey{Params: &ecdsapb.EcdsaParams{
+			HashType: commonpb.HashType_SHA256,
+			Encoding: ecdsapb.EcdsaSignatureEncoding_DER,
+		}}
+		serializedKey, err = proto.Marshal(key)
+		require.NoError(t, err)
+
+		_, err = km.Primitive(serializedKey)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "secp256k1_signer: invalid private key")
+
+		_, err = km.PublicKeyData([]byte("bad key"))
+		require.EqualError(t, err, errInvalidSecp256k1SignKey.Error())
+	})
+
+	t.Run("test invalid key format", func(t *testing.T) {
+		_, err := km.NewKey(nil)
+		require.EqualError(t, err, errInvalidSecp256k1SignKeyFormat.Error())
+
+		_, err = km.NewKey([]byte("bad key format"))
+		require.EqualError(t, err, errInvalidSecp256k1SignKeyFormat.Error())
+
+		_, err = km.NewKeyData(nil)
+		require.EqualError(t, err, errInvalidSecp256k1SignKeyFormat.Error())
+
+		for _, params := range []*ecdsapb.EcdsaParams{
+			nil,
+			{HashType: commonpb.HashType_SHA512, Encoding: ecdsapb.EcdsaSignatureEncoding_DER},
+			{HashType: commonpb.HashType_SHA256, Encoding: ecdsapb.EcdsaSignatureEncoding_UNKNOWN_ENCODING},
+		} {
+			serializedFormat, err := proto.Marshal(&ecdsapb.EcdsaKeyFormat{Params: params})
+			require.NoError(t, err)
+
+			_, err = km.NewKey(serializedFormat)
+			require.EqualError(t, err, errInvalidSecp256k1SignKeyFormat.Error())
+		}
+	})
+}
+
+func TestSecp256k1VerifierKeyManager(t *testing.T) {
+	km := newSecp256k1VerifierKeyManager()
+
+	require.True(t, km.DoesSupport(secp256k1VerifierTypeURL))
+	require.Equal(t, secp256k1VerifierTypeURL, km.TypeURL())
+
+	_, err := km.NewKey(nil)
+	require.EqualError(t, err, errSecp256k1VerifierNotImplemented.Error())
+
+	_, err = km.NewKeyData(nil)
+	require.EqualError(t, err, errSecp256k1VerifierNotImplemented.Error())
+
+	_, err = km.Primitive(nil)
+	require.EqualError(t, err, errInvalidSecp256k1VerifierKey.Error())
+
+	_, err = km.Primitive([]byte("bad key"))
+	require.EqualError(t, err, errInvalidSecp256k1VerifierKey.Error())
+
+	key := &ecdsapb.EcdsaPublicKey{Version: 1}
+	serializedKey, err := proto.Marshal(key)
+	require.NoError(t, err)
+
+	_, err = km.Primitive(serializedKey)
+	require.Error(t, err)
+
+	key.Version = 0
+	key.X = []byte{1}
+	key.Y = []byte{2}
+	serializedKey, err = proto.Marshal(key)
+	require.NoError(t, err)
+
+	_, err = km.Primitive(serializedKey)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "secp256k1: missing params")
+
+	key.Params = &ecdsapb.EcdsaParams{HashType: commonpb.HashType_SHA256, Encoding: ecdsapb.EcdsaSignatureEncoding_DER}
+	serializedKey, err = proto.Marshal(key)
+	require.NoError(t, err)
+
+	_, err = km.Primitive(serializedKey)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "secp256k1_verifier: invalid public key")
+}
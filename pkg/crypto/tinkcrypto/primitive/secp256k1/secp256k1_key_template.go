@@ -0,0 +1,41 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secp256k1
+
+import (
+	"github.com/golang/protobuf/proto"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// DERKeyTemplate is a KeyTemplate that generates a secp256k1 ECDSA key signing SHA-256 digests, with DER encoded
+// signatures.
+func DERKeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(ecdsapb.EcdsaSignatureEncoding_DER)
+}
+
+// IEEEP1363KeyTemplate is a KeyTemplate that generates a secp256k1 ECDSA key signing SHA-256 digests, with IEEE P1363
+// encoded signatures (R || S, as used by ES256K JWS).
+func IEEEP1363KeyTemplate() *tinkpb.KeyTemplate {
+	return createKeyTemplate(ecdsapb.EcdsaSignatureEncoding_IEEE_P1363)
+}
+
+func createKeyTemplate(encoding ecdsapb.EcdsaSignatureEncoding) *tinkpb.KeyTemplate {
+	format := &ecdsapb.EcdsaKeyFormat{Params: &ecdsapb.EcdsaParams{
+		HashType: commonpb.HashType_SHA256,
+		Curve:    commonpb.EllipticCurveType_UNKNOWN_CURVE,
+		Encoding: encoding,
+	}}
+	serializedFormat, _ := proto.Marshal(format) //nolint:errcheck
+
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          secp256k1SignerTypeURL,
+		Value:            serializedFormat,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secp256k1
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	commonpb "github.com/google/tink/go/proto/common_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/secp256k1/subtle"
+)
+
+const (
+	secp256k1SignerKeyVersion = 0
+	secp256k1SignerTypeURL    = "type.hyperledger.org/hyperledger.aries.crypto.tink.Secp256k1PrivateKey"
+)
+
+// common errors
+var errInvalidSecp256k1SignKey = errors.New("secp256k1_signer_key_manager: invalid key")
+var errInvalidSecp256k1SignKeyFormat = errors.New("secp256k1_signer_key_manager: invalid key format")
+
+// secp256k1SignerKeyManager is an implementation of PrivateKeyManager interface.
+// It generates new secp256k1 private keys and produces new instances of Secp256k1Signer subtle.
+type secp256k1SignerKeyManager struct{}
+
+// Assert that secp256k1SignerKeyManager implements the PrivateKeyManager interface.
+var _ registry.PrivateKeyManager = (*secp256k1SignerKeyManager)(nil)
+
+// newSecp256k1SignerKeyManager creates a new secp256k1SignerKeyManager.
+func newSecp256k1SignerKeyManager() *secp256k1SignerKeyManager {
+	return new(secp256k1SignerKeyManager)
+}
+
+// Primitive creates a Secp256k1Signer subtle for the given serialized EcdsaPrivateKey proto.
+func (km *secp256k1SignerKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidSecp256k1SignKey
+	}
+
+	key := new(ecdsapb.EcdsaPrivateKey)
+
+	err := proto.Unmarshal(serializedKey, key)
+	if err != nil {
+		return nil, errInvalidSecp256k1SignKey
+	}
+
+	err = km.validateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := subtle.NewSecp256k1Signer(key.PublicKey.Params.Encoding.String(), key.KeyValue)
+	if err != nil {
+		return nil, fmt.Errorf("secp256k1_signer_key_manager: %w", err)
+	}
+
+	return ret, nil
+}
+
+// NewKey creates a new key according to the specification of the EcdsaKeyFormat.
+func (km *secp256k1SignerKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	if len(serializedKeyFormat) == 0 {
+		return nil, errInvalidSecp256k1SignKeyFormat
+	}
+
+	keyFormat := new(ecdsapb.EcdsaKeyFormat)
+
+	err := proto.Unmarshal(serializedKeyFormat, keyFormat)
+	if err != nil {
+		return nil, errInvalidSecp256k1SignKeyFormat
+	}
+
+	err = validateParams(keyFormat.Params)
+	if err != nil {
+		return nil, errInvalidSecp256k1SignKeyFormat
+	}
+
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("secp256k1_signer_key_manager: cannot generate key: %w", err)
+	}
+
+	return &ecdsapb.EcdsaPrivateKey{
+		Version: secp256k1SignerKeyVersion,
+		PublicKey: &ecdsapb.EcdsaPublicKey{
+			Version: secp256k1SignerKeyVersion,
+			Params:  keyFormat.Params,
+			X:       privKey.X.Bytes(),
+			Y:       privKey.Y.Bytes(),
+		},
+		KeyValue: privKey.D.Bytes(),
+	}, nil
+}
+
+// NewKeyData creates a new KeyData according to the specification of the EcdsaKeyFormat.
+// It should be used solely by the key management API.
+func (km *secp256k1SignerKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidSecp256k1SignKeyFormat
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         secp256k1SignerTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *secp256k1SignerKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(ecdsapb.EcdsaPrivateKey)
+
+	err := proto.Unmarshal(serializedPrivKey, privKey)
+	if err != nil {
+		return nil, errInvalidSecp256k1SignKey
+	}
+
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidSecp256k1SignKey
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         secp256k1VerifierTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *secp256k1SignerKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == secp256k1SignerTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *secp256k1SignerKeyManager) TypeURL() string {
+	return secp256k1SignerTypeURL
+}
+
+// validateKey validates the given EcdsaPrivateKey.
+func (km *secp256k1SignerKeyManager) validateKey(key *ecdsapb.EcdsaPrivateKey) error {
+	err := keyset.ValidateKeyVersion(key.Version, secp256k1SignerKeyVersion)
+	if err != nil {
+		return fmt.Errorf("secp256k1_signer_key_manager: invalid key: %w", err)
+	}
+
+	if key.PublicKey == nil {
+		return errInvalidSecp256k1SignKey
+	}
+
+	return validateParams(key.PublicKey.Params)
+}
+
+// validateParams validates the secp256k1 ECDSA params: SHA-256 digests with DER or IEEE P1363 signatures.
+func validateParams(params *ecdsapb.EcdsaParams) error {
+	if params == nil {
+		return errors.New("secp256k1: missing params")
+	}
+
+	if params.HashType != commonpb.HashType_SHA256 {
+		return fmt.Errorf("secp256k1: unsupported hash type: %s", params.HashType)
+	}
+
+	switch params.Encoding {
+	case ecdsapb.EcdsaSignatureEncoding_DER, ecdsapb.EcdsaSignatureEncoding_IEEE_P1363:
+		return nil
+	default:
+		return fmt.Errorf("secp256k1: unsupported encoding: %s", params.Encoding)
+	}
+}
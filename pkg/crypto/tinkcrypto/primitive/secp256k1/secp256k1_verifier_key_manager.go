@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package secp256k1
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	"github.com/google/tink/go/keyset"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/secp256k1/subtle"
+)
+
+const (
+	secp256k1VerifierKeyVersion = 0
+	secp256k1VerifierTypeURL    = "type.hyperledger.org/hyperledger.aries.crypto.tink.Secp256k1PublicKey"
+)
+
+// common errors
+var errInvalidSecp256k1VerifierKey = errors.New("secp256k1_verifier_key_manager: invalid key")
+var errSecp256k1VerifierNotImplemented = errors.New("secp256k1_verifier_key_manager: not implemented")
+
+// secp256k1VerifierKeyManager is an implementation of KeyManager interface.
+// It doesn't support key generation.
+type secp256k1VerifierKeyManager struct{}
+
+// Assert that secp256k1VerifierKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*secp256k1VerifierKeyManager)(nil)
+
+// newSecp256k1VerifierKeyManager creates a new secp256k1VerifierKeyManager.
+func newSecp256k1VerifierKeyManager() *secp256k1VerifierKeyManager {
+	return new(secp256k1VerifierKeyManager)
+}
+
+// Primitive creates a Secp256k1Verifier subtle for the given serialized EcdsaPublicKey proto.
+func (km *secp256k1VerifierKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	if len(serializedKey) == 0 {
+		return nil, errInvalidSecp256k1VerifierKey
+	}
+
+	key := new(ecdsapb.EcdsaPublicKey)
+
+	err := proto.Unmarshal(serializedKey, key)
+	if err != nil {
+		return nil, errInvalidSecp256k1VerifierKey
+	}
+
+	err = keyset.ValidateKeyVersion(key.Version, secp256k1VerifierKeyVersion)
+	if err != nil {
+		return nil, fmt.Errorf("secp256k1_verifier_key_manager: invalid key: %w", err)
+	}
+
+	err = validateParams(key.Params)
+	if err != nil {
+		return nil, fmt.Errorf("secp256k1_verifier_key_manager: invalid key: %w", err)
+	}
+
+	ret, err := subtle.NewSecp256k1Verifier(key.Params.Encoding.String(), key.X, key.Y)
+	if err != nil {
+		return nil, fmt.Errorf("secp256k1_verifier_key_manager: %w", err)
+	}
+
+	return ret, nil
+}
+
+// NewKey is not implemented.
+func (km *secp256k1VerifierKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errSecp256k1VerifierNotImplemented
+}
+
+// NewKeyData is not implemented.
+func (km *secp256k1VerifierKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errSecp256k1VerifierNotImplemented
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *secp256k1VerifierKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == secp256k1VerifierTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *secp256k1VerifierKeyManager) TypeURL() string {
+	return secp256k1VerifierTypeURL
+}
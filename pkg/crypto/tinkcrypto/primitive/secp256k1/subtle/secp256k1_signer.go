@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package subtle provides the secp256k1 ECDSA signer and verifier primitives.
+package subtle
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/google/tink/go/tink"
+)
+
+const (
+	// DER signature encoding.
+	DER = "DER"
+	// IEEEP1363 signature encoding, the concatenation of the fixed size R and S values (as used by ES256K JWS).
+	IEEEP1363 = "IEEE_P1363"
+
+	// coordinateSize is the size of secp256k1 R and S values.
+	coordinateSize = 32
+)
+
+// Secp256k1Signer is an ECDSA signer over the secp256k1 curve, signing SHA-256 digests with deterministic (RFC6979)
+// nonces and low S values.
+type Secp256k1Signer struct {
+	privateKey *btcec.PrivateKey
+	encoding   string
+}
+
+// Assert that Secp256k1Signer implements the Signer interface.
+var _ tink.Signer = (*Secp256k1Signer)(nil)
+
+// NewSecp256k1Signer creates a new instance of Secp256k1Signer for the private key value keyValue.
+func NewSecp256k1Signer(encoding string, keyValue []byte) (*Secp256k1Signer, error) {
+	if err := validateEncoding(encoding); err != nil {
+		return nil, err
+	}
+
+	if len(keyValue) == 0 || len(keyValue) > coordinateSize {
+		return nil, errors.New("secp256k1_signer: invalid private key")
+	}
+
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), keyValue)
+
+	return &Secp256k1Signer{privateKey: privKey, encoding: encoding}, nil
+}
+
+// Sign computes a signature for the given data.
+func (s *Secp256k1Signer) Sign(data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+
+	sig, err := s.privateKey.Sign(digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("secp256k1_signer: signing failed: %w", err)
+	}
+
+	if s.encoding == DER {
+		return sig.Serialize(), nil
+	}
+
+	ieeeSig := make([]byte, 2*coordinateSize)
+	copyPadded(ieeeSig[:coordinateSize], sig.R)
+	copyPadded(ieeeSig[coordinateSize:], sig.S)
+
+	return ieeeSig, nil
+}
+
+func copyPadded(dst []byte, v *big.Int) {
+	b := v.Bytes()
+	copy(dst[len(dst)-len(b):], b)
+}
+
+func validateEncoding(encoding string) error {
+	switch encoding {
+	case DER, IEEEP1363:
+		return nil
+	default:
+		return fmt.Errorf("secp256k1: unsupported encoding: %s", encoding)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecp256k1SignVerify(t *testing.T) {
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+
+	data := []byte("test data")
+
+	for _, encoding := range []string{DER, IEEEP1363} {
+		encoding := encoding
+
+		t.Run("test sign and verify with "+encoding+" encoding", func(t *testing.T) {
+			signer, err := NewSecp256k1Signer(encoding, privKey.D.Bytes())
+			require.NoError(t, err)
+
+			sig, err := signer.Sign(data)
+			require.NoError(t, err)
+
+			verifier, err := NewSecp256k1Verifier(encoding, privKey.X.Bytes(), privKey.Y.Bytes())
+			require.NoError(t, err)
+
+			require.NoError(t, verifier.Verify(sig, data))
+			require.EqualError(t, verifier.Verify(sig, []byte("other data")), errInvalidSignature.Error())
+
+			// signatures of a different encoding are invalid
+			otherEncoding := DER
+			if encoding == DER {
+				otherEncoding = IEEEP1363
+			}
+
+			otherVerifier, err := NewSecp256k1Verifier(otherEncoding, privKey.X.Bytes(), privKey.Y.Bytes())
+			require.NoError(t, err)
+			require.EqualError(t, otherVerifier.Verify(sig, data), errInvalidSignature.Error())
+		})
+	}
+
+	t.Run("test IEEE P1363 signature is R || S", func(t *testing.T) {
+		signer, err := NewSecp256k1Signer(IEEEP1363, privKey.D.Bytes())
+		require.NoError(t, err)
+
+		sig, err := signer.Sign(data)
+		require.NoError(t, err)
+		require.Len(t, sig, 64)
+
+		digest := sha256.Sum256(data)
+		require.True(t, ecdsa.Verify(privKey.PubKey().ToECDSA(), digest[:],
+			new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])))
+	})
+
+	t.Run("test invalid parameters", func(t *testing.T) {
+		_, err := NewSecp256k1Signer("unknown", privKey.D.Bytes())
+		require.EqualError(t, err, "secp256k1: unsupported encoding: unknown")
+
+		_, err = NewSecp256k1Signer(DER, nil)
+		require.EqualError(t, err, "secp256k1_signer: invalid private key")
+
+		_, err = NewSecp256k1Verifier("unknown", privKey.X.Bytes(), privKey.Y.Bytes())
+		require.EqualError(t, err, "secp256k1: unsupported encoding: unknown")
+
+		_, err = NewSecp256k1Verifier(DER, []byte{1}, []byte{2})
+		require.EqualError(t, err, "secp256k1_verifier: invalid public key")
+	})
+}
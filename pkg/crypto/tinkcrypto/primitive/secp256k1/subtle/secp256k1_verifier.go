@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package subtle
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/google/tink/go/tink"
+)
+
+var errInvalidSignature = errors.New("secp256k1_verifier: invalid signature")
+
+// Secp256k1Verifier is an ECDSA verifier over the secp256k1 curve, verifying signatures of SHA-256 digests.
+type Secp256k1Verifier struct {
+	publicKey *ecdsa.PublicKey
+	encoding  string
+}
+
+// Assert that Secp256k1Verifier implements the Verifier interface.
+var _ tink.Verifier = (*Secp256k1Verifier)(nil)
+
+// NewSecp256k1Verifier creates a new instance of Secp256k1Verifier for the public key point (x, y).
+func NewSecp256k1Verifier(encoding string, x, y []byte) (*Secp256k1Verifier, error) {
+	if err := validateEncoding(encoding); err != nil {
+		return nil, err
+	}
+
+	pubKey := &ecdsa.PublicKey{
+		Curve: btcec.S256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+
+	if !pubKey.Curve.IsOnCurve(pubKey.X, pubKey.Y) {
+		return nil, errors.New("secp256k1_verifier: invalid public key")
+	}
+
+	return &Secp256k1Verifier{publicKey: pubKey, encoding: encoding}, nil
+}
+
+// Verify verifies whether the given signature is valid for the given data.
+func (v *Secp256k1Verifier) Verify(signature, data []byte) error {
+	r, s, err := v.decodeSignature(signature)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(data)
+
+	if !ecdsa.Verify(v.publicKey, digest[:], r, s) {
+		return errInvalidSignature
+	}
+
+	return nil
+}
+
+func (v *Secp256k1Verifier) decodeSignature(signature []byte) (*big.Int, *big.Int, error) {
+	if v.encoding == DER {
+		sig, err := btcec.ParseDERSignature(signature, btcec.S256())
+		if err != nil {
+			return nil, nil, errInvalidSignature
+		}
+
+		return sig.R, sig.S, nil
+	}
+
+	if len(signature) != 2*coordinateSize {
+		return nil, nil, errInvalidSignature
+	}
+
+	return new(big.Int).SetBytes(signature[:coordinateSize]), new(big.Int).SetBytes(signature[coordinateSize:]), nil
+}
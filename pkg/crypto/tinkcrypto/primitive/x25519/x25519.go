@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package x25519 provides the Tink key managers and key template of X25519 key agreement keys, as used by DIDComm
+// and did:key `z6LS` keys.
+//
+// Tink's Ed25519 key protos are reused to serialize the keys since they hold the same raw 32 bytes key values, the
+// key type being given by the key type URL. X25519 keys have no Tink primitive, they are used for key agreement
+// through crypto.Crypto's WrapKey and UnwrapKey.
+package x25519
+
+import (
+	"fmt"
+
+	"github.com/google/tink/go/core/registry"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+)
+
+// TODO - find a better way to setup tink than init.
+// nolint: gochecknoinits
+func init() {
+	// TODO - avoid the tink registry singleton.
+	err := registry.RegisterKeyManager(newX25519PrivateKeyManager())
+	if err != nil {
+		panic(fmt.Sprintf("x25519.init() failed: %v", err))
+	}
+
+	err = registry.RegisterKeyManager(newX25519PublicKeyManager())
+	if err != nil {
+		panic(fmt.Sprintf("x25519.init() failed: %v", err))
+	}
+}
+
+// KeyTemplate is a KeyTemplate that generates an X25519 key agreement key.
+func KeyTemplate() *tinkpb.KeyTemplate {
+	return &tinkpb.KeyTemplate{
+		TypeUrl:          x25519PrivateKeyTypeURL,
+		OutputPrefixType: tinkpb.OutputPrefixType_RAW,
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package x25519
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/core/registry"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"golang.org/x/crypto/curve25519"
+)
+
+const (
+	x25519KeyVersion        = 0
+	x25519PrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.X25519PrivateKey"
+	x25519PublicKeyTypeURL  = "type.hyperledger.org/hyperledger.aries.crypto.tink.X25519PublicKey"
+)
+
+// common errors
+var errInvalidX25519PrivateKey = errors.New("x25519_private_key_manager: invalid key")
+var errX25519NoPrimitive = errors.New("x25519: key agreement keys have no primitive")
+var errX25519PublicKeyNotImplemented = errors.New("x25519_public_key_manager: not implemented")
+
+// x25519PrivateKeyManager is an implementation of PrivateKeyManager interface.
+// It generates new X25519 private keys, it doesn't produce primitives.
+type x25519PrivateKeyManager struct{}
+
+// Assert that x25519PrivateKeyManager implements the PrivateKeyManager interface.
+var _ registry.PrivateKeyManager = (*x25519PrivateKeyManager)(nil)
+
+// newX25519PrivateKeyManager creates a new x25519PrivateKeyManager.
+func newX25519PrivateKeyManager() *x25519PrivateKeyManager {
+	return new(x25519PrivateKeyManager)
+}
+
+// Primitive is not supported, X25519 keys are used with crypto.Crypto's WrapKey and UnwrapKey.
+func (km *x25519PrivateKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	return nil, errX25519NoPrimitive
+}
+
+// NewKey creates a new X25519 key, serialized as an Ed25519PrivateKey proto. The key format is ignored.
+func (km *x25519PrivateKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	privKey := make([]byte, curve25519.ScalarSize)
+
+	_, err := rand.Read(privKey)
+	if err != nil {
+		return nil, fmt.Errorf("x25519_private_key_manager: cannot generate key: %w", err)
+	}
+
+	pubKey, err := curve25519.X25519(privKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, fmt.Errorf("x25519_private_key_manager: cannot generate key: %w", err)
+	}
+
+	return &ed25519pb.Ed25519PrivateKey{
+		Version: x25519KeyVersion,
+		PublicKey: &ed25519pb.Ed25519PublicKey{
+			Version:  x25519KeyVersion,
+			KeyValue: pubKey,
+		},
+		KeyValue: privKey,
+	}, nil
+}
+
+// NewKeyData creates a new KeyData containing a new X25519 key.
+// It should be used solely by the key management API.
+func (km *x25519PrivateKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	key, err := km.NewKey(serializedKeyFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	serializedKey, err := proto.Marshal(key)
+	if err != nil {
+		return nil, errInvalidX25519PrivateKey
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         x25519PrivateKeyTypeURL,
+		Value:           serializedKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PRIVATE,
+	}, nil
+}
+
+// PublicKeyData extracts the public key data from the private key.
+func (km *x25519PrivateKeyManager) PublicKeyData(serializedPrivKey []byte) (*tinkpb.KeyData, error) {
+	privKey := new(ed25519pb.Ed25519PrivateKey)
+
+	err := proto.Unmarshal(serializedPrivKey, privKey)
+	if err != nil || privKey.PublicKey == nil {
+		return nil, errInvalidX25519PrivateKey
+	}
+
+	serializedPubKey, err := proto.Marshal(privKey.PublicKey)
+	if err != nil {
+		return nil, errInvalidX25519PrivateKey
+	}
+
+	return &tinkpb.KeyData{
+		TypeUrl:         x25519PublicKeyTypeURL,
+		Value:           serializedPubKey,
+		KeyMaterialType: tinkpb.KeyData_ASYMMETRIC_PUBLIC,
+	}, nil
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *x25519PrivateKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == x25519PrivateKeyTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *x25519PrivateKeyManager) TypeURL() string {
+	return x25519PrivateKeyTypeURL
+}
+
+// x25519PublicKeyManager is an implementation of KeyManager interface for X25519 public keys.
+// It neither generates keys nor produces primitives.
+type x25519PublicKeyManager struct{}
+
+// Assert that x25519PublicKeyManager implements the KeyManager interface.
+var _ registry.KeyManager = (*x25519PublicKeyManager)(nil)
+
+// newX25519PublicKeyManager creates a new x25519PublicKeyManager.
+func newX25519PublicKeyManager() *x25519PublicKeyManager {
+	return new(x25519PublicKeyManager)
+}
+
+// Primitive is not supported, X25519 keys are used with crypto.Crypto's WrapKey and UnwrapKey.
+func (km *x25519PublicKeyManager) Primitive(serializedKey []byte) (interface{}, error) {
+	return nil, errX25519NoPrimitive
+}
+
+// NewKey is not implemented.
+func (km *x25519PublicKeyManager) NewKey(serializedKeyFormat []byte) (proto.Message, error) {
+	return nil, errX25519PublicKeyNotImplemented
+}
+
+// NewKeyData is not implemented.
+func (km *x25519PublicKeyManager) NewKeyData(serializedKeyFormat []byte) (*tinkpb.KeyData, error) {
+	return nil, errX25519PublicKeyNotImplemented
+}
+
+// DoesSupport indicates if this key manager supports the given key type.
+func (km *x25519PublicKeyManager) DoesSupport(typeURL string) bool {
+	return typeURL == x25519PublicKeyTypeURL
+}
+
+// TypeURL returns the key type of keys managed by this key manager.
+func (km *x25519PublicKeyManager) TypeURL() string {
+	return x25519PublicKeyTypeURL
+}
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package x25519
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
+)
+
+func TestKeyTemplate(t *testing.T) {
+	kh, err := keyset.NewHandle(KeyTemplate())
+	require.NoError(t, err)
+
+	_, err = kh.Primitives()
+	require.Error(t, err)
+
+	pubKH, err := kh.Public()
+	require.NoError(t, err)
+
+	ks := &keyset.MemReaderWriter{}
+	require.NoError(t, insecurecleartextkeyset.Write(kh, ks))
+
+	privKey := new(ed25519pb.Ed25519PrivateKey)
+	require.NoError(t, proto.Unmarshal(ks.Keyset.Key[0].KeyData.Value, privKey))
+
+	pubKS := &keyset.MemReaderWriter{}
+	require.NoError(t, insecurecleartextkeyset.Write(pubKH, pubKS))
+	require.Equal(t, x25519PublicKeyTypeURL, pubKS.Keyset.Key[0].KeyData.TypeUrl)
+
+	pubKey := new(ed25519pb.Ed25519PublicKey)
+	require.NoError(t, proto.Unmarshal(pubKS.Keyset.Key[0].KeyData.Value, pubKey))
+
+	expectedPubKey, err := curve25519.X25519(privKey.KeyValue, curve25519.Basepoint)
+	require.NoError(t, err)
+	require.Equal(t, expectedPubKey, pubKey.KeyValue)
+}
+
+func TestX25519KeyManagers(t *testing.T) {
+	privKM := newX25519PrivateKeyManager()
+
+	require.True(t, privKM.DoesSupport(x25519PrivateKeyTypeURL))
+	require.Equal(t, x25519PrivateKeyTypeURL, privKM.TypeURL())
+
+	_, err := privKM.Primitive(nil)
+	require.EqualError(t, err, errX25519NoPrimitive.Error())
+
+	_, err = privKM.PublicKeyData([]byte("bad key"))
+	require.EqualError(t, err, errInvalidX25519PrivateKey.Error())
+
+	pubKM := newX25519PublicKeyManager()
+
+	require.True(t, pubKM.DoesSupport(x25519PublicKeyTypeURL))
+	require.Equal(t, x25519PublicKeyTypeURL, pubKM.TypeURL())
+
+	_, err = pubKM.Primitive(nil)
+	require.EqualError(t, err, errX25519NoPrimitive.Error())
+
+	_, err = pubKM.NewKey(nil)
+	require.EqualError(t, err, errX25519PublicKeyNotImplemented.Error())
+
+	_, err = pubKM.NewKeyData(nil)
+	require.EqualError(t, err, errX25519PublicKeyNotImplemented.Error())
+}
@@ -6,5 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 
 package service
 
+import "time"
+
 // ForwardMsgType defines the route forward message type.
 const ForwardMsgType = "https://didcomm.org/routing/1.0/forward"
+
+// ThreadDataTTL is how long the data needed only while a thread is active, e.g. the payload of an action
+// waiting for Continue or Stop, is kept. Data of a thread left idle expires after it.
+const ThreadDataTTL = 7 * 24 * time.Hour
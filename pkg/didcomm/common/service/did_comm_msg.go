@@ -38,6 +38,10 @@ type Metadata struct {
 	Payload map[string]interface{} `json:"_internal_metadata,omitempty"`
 }
 
+// SenderVerKeyMetadata is the metadata entry of an inbound message holding the key (base58) its sender packed it
+// with, it's set only for the messages packed with a sender key.
+const SenderVerKeyMetadata = "sender_verkey"
+
 // DIDCommMsgMap did comm msg
 type DIDCommMsgMap map[string]interface{}
 
@@ -20,7 +20,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 // provider interface for outbound ctx
@@ -29,7 +29,7 @@ type provider interface {
 	OutboundTransports() []transport.OutboundTransport
 	TransportReturnRoute() string
 	VDRIRegistry() vdri.Registry
-	LegacyKMS() legacykms.KeyManager
+	KMS() kms.KeyManager
 }
 
 // OutboundDispatcher dispatch msgs to destination
@@ -38,7 +38,7 @@ type OutboundDispatcher struct {
 	packager             commontransport.Packager
 	transportReturnRoute string
 	vdRegistry           vdri.Registry
-	kms                  legacykms.KeyManager
+	kms                  kms.KeyManager
 }
 
 // NewOutbound return new dispatcher outbound instance
@@ -48,7 +48,7 @@ func NewOutbound(prov provider) *OutboundDispatcher {
 		packager:             prov.Packager(),
 		transportReturnRoute: prov.TransportReturnRoute(),
 		vdRegistry:           prov.VDRIRegistry(),
-		kms:                  prov.LegacyKMS(),
+		kms:                  prov.KMS(),
 	}
 }
 
@@ -174,7 +174,7 @@ func (o *OutboundDispatcher) createForwardMessage(msg []byte, des *service.Desti
 	}
 
 	// create key set
-	_, senderVerKey, err := o.kms.CreateKeySet()
+	_, senderVerKey, err := o.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
 	if err != nil {
 		return nil, fmt.Errorf("failed CreateSigningKey: %w", err)
 	}
@@ -183,7 +183,7 @@ func (o *OutboundDispatcher) createForwardMessage(msg []byte, des *service.Desti
 	// TODO https://github.com/hyperledger/aries-framework-go/issues/1112 Configurable packing
 	//  algorithm(auth/anon crypt) for Forward(router) message
 	packedMsg, err := o.packager.PackMessage(
-		&commontransport.Envelope{Message: req, FromVerKey: base58.Decode(senderVerKey), ToVerKeys: des.RoutingKeys})
+		&commontransport.Envelope{Message: req, FromVerKey: senderVerKey, ToVerKeys: des.RoutingKeys})
 	if err != nil {
 		return nil, fmt.Errorf("pack forward msg: %w", err)
 	}
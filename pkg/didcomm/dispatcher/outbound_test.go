@@ -23,8 +23,9 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	mockdidcomm "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm"
 	mockpackager "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/packager"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
 )
 
@@ -79,8 +80,8 @@ func TestOutboundDispatcher_Send(t *testing.T) {
 		o := NewOutbound(&mockProvider{
 			packagerValue:           &mockpackager.Packager{PackValue: createPackedMsgForForward(t)},
 			outboundTransportsValue: []transport.OutboundTransport{&mockdidcomm.MockOutboundTransport{AcceptValue: true}},
-			legacyKMS: &mockKMS{
-				CreateKeyErr: errors.New("create key error"),
+			kms: &mockkms.KeyManager{
+				CrAndExportPubKeyErr: errors.New("create key error"),
 			},
 		})
 
@@ -292,7 +293,7 @@ type mockProvider struct {
 	outboundTransportsValue []transport.OutboundTransport
 	transportReturnRoute    string
 	vdriRegistry            vdri.Registry
-	legacyKMS               legacykms.KMS
+	kms                     kms.KeyManager
 }
 
 func (p *mockProvider) Packager() commontransport.Packager {
@@ -311,12 +312,12 @@ func (p *mockProvider) VDRIRegistry() vdri.Registry {
 	return p.vdriRegistry
 }
 
-func (p *mockProvider) LegacyKMS() legacykms.KeyManager {
-	if p.legacyKMS != nil {
-		return p.legacyKMS
+func (p *mockProvider) KMS() kms.KeyManager {
+	if p.kms != nil {
+		return p.kms
 	}
 
-	return &mockKMS{}
+	return &mockkms.KeyManager{}
 }
 
 // mockOutboundTransport mock outbound transport
@@ -356,38 +357,3 @@ func (m *mockPackager) PackMessage(e *commontransport.Envelope) ([]byte, error)
 func (m *mockPackager) UnpackMessage(encMessage []byte) (*commontransport.Envelope, error) {
 	return nil, nil
 }
-
-// mockKMS mock Key Management Service (LegacyKMS)
-type mockKMS struct {
-	CreateEncryptionKeyValue string
-	CreateSigningKeyValue    string
-	CreateKeyErr             error
-}
-
-func (m *mockKMS) Close() error {
-	return nil
-}
-
-func (m *mockKMS) CreateKeySet() (string, string, error) {
-	return m.CreateEncryptionKeyValue, m.CreateSigningKeyValue, m.CreateKeyErr
-}
-
-func (m *mockKMS) FindVerKey(candidateKeys []string) (int, error) {
-	return 0, nil
-}
-
-func (m *mockKMS) SignMessage(message []byte, fromVerKey string) ([]byte, error) {
-	return nil, nil
-}
-
-func (m *mockKMS) DeriveKEK(alg, apu, fromKey, toPubKey []byte) ([]byte, error) {
-	return nil, nil
-}
-
-func (m *mockKMS) GetEncryptionKey(verKey []byte) ([]byte, error) {
-	return nil, nil
-}
-
-func (m *mockKMS) ConvertToEncryptionKey(key []byte) ([]byte, error) {
-	return nil, nil
-}
@@ -235,12 +235,12 @@ func (m *Messenger) getRecord(msgID string) (*record, error) {
 	return r, nil
 }
 
-// saveRecord saves incoming message payload
+// saveRecord saves incoming message payload, which expires after service.ThreadDataTTL
 func (m *Messenger) saveRecord(msgID string, rec record) error {
 	src, err := json.Marshal(rec)
 	if err != nil {
 		return fmt.Errorf("marshal record: %w", err)
 	}
 
-	return m.store.Put(msgID, src)
+	return m.store.Batch([]storage.Operation{{Key: msgID, Value: src, TTL: service.ThreadDataTTL}})
 }
@@ -68,7 +68,7 @@ func TestMessenger_HandleInbound(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
-		store.EXPECT().Put(ID, gomock.Any()).Return(nil)
+		store.EXPECT().Batch(gomock.Any()).Return(nil)
 		store.EXPECT().Get(gomock.Any()).Return([]byte(`{}`), nil)
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
@@ -87,7 +87,7 @@ func TestMessenger_HandleInbound(t *testing.T) {
 
 	t.Run("success without metadata", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
-		store.EXPECT().Put(ID, gomock.Any()).Return(nil)
+		store.EXPECT().Batch(gomock.Any()).Return(nil)
 		store.EXPECT().Get(gomock.Any()).Return(nil, storage.ErrDataNotFound)
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
@@ -142,7 +142,7 @@ func TestMessenger_HandleInbound(t *testing.T) {
 	t.Run("success with metadata", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
 		payload := []byte(`{"my_did":"myDID","their_did":"theirDID","thread_id":"thID","parent_thread_id":"pthID"}`)
-		store.EXPECT().Put(ID, payload).Return(nil)
+		store.EXPECT().Batch([]storage.Operation{{Key: ID, Value: payload, TTL: service.ThreadDataTTL}}).Return(nil)
 		store.EXPECT().Get(gomock.Any()).Return([]byte(`{"metadata":{"key":"val"}}`), nil)
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
@@ -166,7 +166,7 @@ func TestMessenger_HandleInbound(t *testing.T) {
 
 	t.Run("success with metadata (thread is nil)", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
-		store.EXPECT().Put(ID, gomock.Any()).Return(nil)
+		store.EXPECT().Batch(gomock.Any()).Return(nil)
 		store.EXPECT().Get(gomock.Any()).Return([]byte(`{"metadata":{"key":"val"}}`), nil)
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
@@ -277,7 +277,7 @@ func TestMessenger_Send(t *testing.T) {
 
 	t.Run("save metadata error", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(errors.New(errMsg)).AnyTimes()
+		store.EXPECT().Batch(gomock.Any()).Return(errors.New(errMsg)).AnyTimes()
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
@@ -371,7 +371,7 @@ func TestMessenger_ReplyTo(t *testing.T) {
 	t.Run("save metadata error", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
 		store.EXPECT().Get(ID).Return([]byte(`{}`), nil)
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(errors.New(errMsg))
+		store.EXPECT().Batch(gomock.Any()).Return(errors.New(errMsg))
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
@@ -436,7 +436,7 @@ func TestMessenger_ReplyToNested(t *testing.T) {
 
 	t.Run("save metadata error", func(t *testing.T) {
 		store := storageMocks.NewMockStore(ctrl)
-		store.EXPECT().Put(gomock.Any(), gomock.Any()).Return(errors.New(errMsg))
+		store.EXPECT().Batch(gomock.Any()).Return(errors.New(errMsg))
 
 		storageProvider := storageMocks.NewMockProvider(ctrl)
 		storageProvider.EXPECT().OpenStore(gomock.Any()).Return(store, nil)
@@ -14,6 +14,8 @@ import (
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	. "github.com/hyperledger/aries-framework-go/pkg/didcomm/packager"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
@@ -21,19 +23,23 @@ import (
 	legacy "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/legacy/authcrypt"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
 func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 	t.Run("test failed to unmarshal encMessage", func(t *testing.T) {
-		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		w, err := newKMS()
 		require.NoError(t, err)
 
 		mockedProviders := &mockProvider{
 			storage:       mockstorage.NewMockStoreProvider(),
 			kms:           w,
+			crypto:        &tinkcrypto.Crypto{},
 			primaryPacker: nil,
 			packers:       nil,
 		}
@@ -49,12 +55,13 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 	})
 
 	t.Run("test bad encoding type", func(t *testing.T) {
-		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		w, err := newKMS()
 		require.NoError(t, err)
 
 		mockedProviders := &mockProvider{
 			storage:       mockstorage.NewMockStoreProvider(),
 			kms:           w,
+			crypto:        &tinkcrypto.Crypto{},
 			primaryPacker: nil,
 			packers:       nil,
 		}
@@ -85,30 +92,33 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 	})
 
 	t.Run("test key not found", func(t *testing.T) {
-		wp := newMockKMSProvider(mockstorage.NewMockStoreProvider())
-		w, err := legacykms.New(wp)
+		w, err := newKMS()
 		require.NoError(t, err)
 
 		mockedProviders := &mockProvider{
 			storage:       mockstorage.NewMockStoreProvider(),
 			kms:           w,
+			crypto:        &tinkcrypto.Crypto{},
 			primaryPacker: nil,
 			packers:       nil,
 		}
 		testPacker, err := jwe.New(mockedProviders, jwe.XC20P)
 		require.NoError(t, err)
 
-		// use a real testPacker with a mocked LegacyKMS to validate pack/unpack
+		// use a real testPacker with a KMS to validate pack/unpack
 		mockedProviders.primaryPacker = testPacker
 		packager, err := New(mockedProviders)
 		require.NoError(t, err)
 
-		// fromKey is stored in the LegacyKMS
-		_, base58FromVerKey, err := w.CreateKeySet()
+		// fromKey is stored in the KMS
+		base58FromVerKey, err := createKey(w)
 		require.NoError(t, err)
 
-		// toVerKey is stored in the LegacyKMS as well
-		base58ToEncKey, base58ToVerKey, err := w.CreateKeySet()
+		// toVerKey is stored in another KMS
+		recipientKMS, err := newKMS()
+		require.NoError(t, err)
+
+		base58ToVerKey, err := createKey(recipientKMS)
 		require.NoError(t, err)
 
 		// PackMessage should pass with both value from and to verification keys
@@ -117,17 +127,14 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 			ToVerKeys:  []string{base58ToVerKey}})
 		require.NoError(t, err)
 
-		// mock LegacyKMS without ToVerKey and ToEncKey then try UnpackMessage
-		delete(wp.storage.Store.Store, base58ToVerKey)
-		delete(wp.storage.Store.Store, base58ToEncKey)
-		// It should fail since Recipient keys are not found in the LegacyKMS
+		// It should fail since Recipient keys are not found in the KMS
 		_, err = packager.UnpackMessage(packMsg)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "key not found")
 	})
 
 	t.Run("test Pack/Unpack fails", func(t *testing.T) {
-		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		w, err := newKMS()
 		require.NoError(t, err)
 
 		decryptValue := func(envelope []byte) (*transport.Envelope, error) {
@@ -137,11 +144,12 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 		mockedProviders := &mockProvider{
 			storage:       mockstorage.NewMockStoreProvider(),
 			kms:           w,
+			crypto:        &tinkcrypto.Crypto{},
 			primaryPacker: nil,
 			packers:       nil,
 		}
 
-		// use a mocked packager with a mocked LegacyKMS to validate pack/unpack
+		// use a mocked packager with a KMS to validate pack/unpack
 		e := func(payload []byte, senderPubKey []byte, recipientsKeys [][]byte) (bytes []byte, e error) {
 			p, e := jwe.New(mockedProviders, jwe.XC20P)
 			require.NoError(t, e)
@@ -155,10 +163,10 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 		packager, err := New(mockedProviders)
 		require.NoError(t, err)
 
-		_, base58FromVerKey, err := w.CreateKeySet()
+		base58FromVerKey, err := createKey(w)
 		require.NoError(t, err)
 
-		_, base58ToVerKey, err := w.CreateKeySet()
+		base58ToVerKey, err := createKey(w)
 		require.NoError(t, err)
 
 		// try pack with nil envelope - should fail
@@ -196,12 +204,13 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 	})
 
 	t.Run("test Pack/Unpack success", func(t *testing.T) {
-		// create a mock LegacyKMS with storage as a map
-		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		// create a KMS with storage as a map
+		w, err := newKMS()
 		require.NoError(t, err)
 		mockedProviders := &mockProvider{
 			storage:       mockstorage.NewMockStoreProvider(),
 			kms:           w,
+			crypto:        &tinkcrypto.Crypto{},
 			primaryPacker: nil,
 			packers:       nil,
 		}
@@ -218,10 +227,10 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 		packager, err := New(mockedProviders)
 		require.NoError(t, err)
 
-		_, base58FromVerKey, err := w.CreateKeySet()
+		base58FromVerKey, err := createKey(w)
 		require.NoError(t, err)
 
-		_, base58ToVerKey, err := w.CreateKeySet()
+		base58ToVerKey, err := createKey(w)
 		require.NoError(t, err)
 
 		// pack an non empty envelope - should pass
@@ -254,12 +263,13 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 	})
 
 	t.Run("test success - dids not found", func(t *testing.T) {
-		// create a mock LegacyKMS with storage as a map
-		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		// create a KMS with storage as a map
+		w, err := newKMS()
 		require.NoError(t, err)
 		mockedProviders := &mockProvider{
 			storage:       mockstorage.NewMockStoreProvider(),
 			kms:           w,
+			crypto:        &tinkcrypto.Crypto{},
 			primaryPacker: nil,
 			packers:       nil,
 		}
@@ -275,10 +285,10 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 		packager, err := New(mockedProviders)
 		require.NoError(t, err)
 
-		_, base58FromVerKey, err := w.CreateKeySet()
+		base58FromVerKey, err := createKey(w)
 		require.NoError(t, err)
 
-		_, base58ToVerKey, err := w.CreateKeySet()
+		base58ToVerKey, err := createKey(w)
 		require.NoError(t, err)
 
 		// pack an non empty envelope - should pass
@@ -294,9 +304,9 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 	})
 
 	t.Run("test failure - did lookup broke", func(t *testing.T) {
-		// create a mock LegacyKMS with storage as a map
+		// create a KMS with storage as a map
 
-		w, err := legacykms.New(newMockKMSProvider(mockstorage.NewMockStoreProvider()))
+		w, err := newKMS()
 		require.NoError(t, err)
 
 		mockedProviders := &mockProvider{
@@ -305,6 +315,7 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 				ErrGet: fmt.Errorf("bad error"),
 			}),
 			kms:           w,
+			crypto:        &tinkcrypto.Crypto{},
 			primaryPacker: nil,
 			packers:       nil,
 		}
@@ -320,10 +331,10 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 		packager, err := New(mockedProviders)
 		require.NoError(t, err)
 
-		_, base58FromVerKey, err := w.CreateKeySet()
+		base58FromVerKey, err := createKey(w)
 		require.NoError(t, err)
 
-		_, base58ToVerKey, err := w.CreateKeySet()
+		base58ToVerKey, err := createKey(w)
 		require.NoError(t, err)
 
 		// pack an non empty envelope - should pass
@@ -340,14 +351,26 @@ func TestBaseKMSInPackager_UnpackMessage(t *testing.T) {
 	})
 }
 
-func newMockKMSProvider(storagePvdr *mockstorage.MockStoreProvider) *mockProvider {
-	return &mockProvider{storagePvdr, nil, nil, nil, nil}
+func newKMS() (*localkms.LocalKMS, error) {
+	return localkms.New("local-lock://test/key/uri",
+		mockkms.NewProvider(mockstorage.NewMockStoreProvider(), &noop.NoLock{}))
 }
 
-// mockProvider mocks provider for LegacyKMS
+// createKey creates an ED25519 key in k and returns its base58 encoded public key.
+func createKey(k kms.KeyManager) (string, error) {
+	_, pubKey, err := k.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	if err != nil {
+		return "", err
+	}
+
+	return base58.Encode(pubKey), nil
+}
+
+// mockProvider mocks provider for the packers and packager
 type mockProvider struct {
-	storage       *mockstorage.MockStoreProvider
-	kms           legacykms.KeyManager
+	storage       storage.Provider
+	kms           kms.KeyManager
+	crypto        crypto.Crypto
 	packers       []packer.Packer
 	primaryPacker packer.Packer
 	vdriRegistry  vdriapi.Registry
@@ -357,10 +380,14 @@ func (m *mockProvider) Packers() []packer.Packer {
 	return m.packers
 }
 
-func (m *mockProvider) LegacyKMS() legacykms.KeyManager {
+func (m *mockProvider) KMS() kms.KeyManager {
 	return m.kms
 }
 
+func (m *mockProvider) Crypto() crypto.Crypto {
+	return m.crypto
+}
+
 func (m *mockProvider) StorageProvider() storage.Provider {
 	return m.storage
 }
@@ -45,7 +45,7 @@ type PackerCreator struct {
 	Creator    packer.Creator
 }
 
-// New return new instance of LegacyKMS implementation
+// New return new instance of the Packager
 func New(ctx Provider) (*Packager, error) {
 	didConnStore, err := did.NewConnectionStore(ctx)
 	if err != nil {
@@ -7,13 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package packer
 
 import (
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 // Provider interface for Packer ctx
 type Provider interface {
-	LegacyKMS() legacykms.KeyManager
+	KMS() kms.KeyManager
+	Crypto() crypto.Crypto
 }
 
 // Creator method to create new Packer service
@@ -30,7 +32,7 @@ type Packer interface {
 	// TODO add key type of recipients and sender keys to be validated by the implementation - Issue #272
 	Pack(payload []byte, senderKey []byte, recipients [][]byte) ([]byte, error)
 	// Unpack an envelope in an Aries compliant format.
-	// 		The recipient's key will be the one found in LegacyKMS that matches one of the list of recipients in the envelope
+	// 		The recipient's key will be the one found in KMS that matches one of the list of recipients in the envelope
 	//
 	// returns:
 	// 		Envelope containing the message, decryption key, and sender key
@@ -13,9 +13,10 @@ import (
 
 	chacha "golang.org/x/crypto/chacha20poly1305"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 // This package deals with Authcrypt encryption for Packing/Unpacking DID Comm exchange
@@ -43,7 +44,8 @@ var errUnsupportedAlg = errors.New("algorithm not supported")
 type Packer struct {
 	alg        ContentEncryption
 	nonceSize  int
-	legacyKMS  legacykms.KeyManager
+	kms        kms.KeyManager
+	crypto     crypto.Crypto
 	randReader io.Reader
 }
 
@@ -88,7 +90,7 @@ type jwk struct {
 // XC20P (xchacha20-poly1305 ietf)
 // The returned Packer contains all the information required to pack and unpack payloads.
 func New(ctx packer.Provider, alg ContentEncryption) (*Packer, error) {
-	k := ctx.LegacyKMS()
+	k := ctx.KMS()
 
 	var nonceSize int
 
@@ -104,7 +106,8 @@ func New(ctx packer.Provider, alg ContentEncryption) (*Packer, error) {
 	return &Packer{
 		alg:        alg,
 		nonceSize:  nonceSize,
-		legacyKMS:  k,
+		kms:        k,
+		crypto:     ctx.Crypto(),
 		randReader: rand.Reader,
 	}, nil
 }
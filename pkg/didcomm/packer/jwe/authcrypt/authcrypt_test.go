@@ -12,15 +12,31 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/btcsuite/btcutil/base58"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	subtleaead "github.com/google/tink/go/subtle/aead"
 	"github.com/stretchr/testify/require"
+	chacha "golang.org/x/crypto/chacha20poly1305"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdhes"
+	ecdhessubtle "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdhes/subtle"
+	ecdhespb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdhes_aead_go_proto"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
 )
 
 func TestEncodingType(t *testing.T) {
@@ -48,8 +64,7 @@ func TestEncodingType(t *testing.T) {
 		},
 	}
 
-	kmsProvider, err := mockkms.NewMockProvider(kp)
-	require.NoError(t, err)
+	kmsProvider := newKMSProvider(t, kp)
 
 	packer, e := New(kmsProvider, XC20P)
 	require.NoError(t, e)
@@ -166,20 +181,15 @@ func TestEncrypt(t *testing.T) {
 	t.Logf("rec3 Enc pub: %v", base64.RawURLEncoding.EncodeToString(rec3.EncKeyPair.Pub))
 	t.Logf("rec3 Enc priv: %v", base64.RawURLEncoding.EncodeToString(rec3.EncKeyPair.Priv))
 
-	allKMSProvider, err := mockkms.NewMockProvider(sender, rec1, rec2, rec3)
-	require.NoError(t, err)
+	allKMSProvider := newKMSProvider(t, sender, rec1, rec2, rec3)
 
-	senderKMSProvider, err := mockkms.NewMockProvider(sender)
-	require.NoError(t, err)
+	senderKMSProvider := newKMSProvider(t, sender)
 
-	recipient1KMSProvider, err := mockkms.NewMockProvider(rec1)
-	require.NoError(t, err)
+	recipient1KMSProvider := newKMSProvider(t, rec1)
 
-	recipient2KMSProvider, err := mockkms.NewMockProvider(rec2)
-	require.NoError(t, err)
+	recipient2KMSProvider := newKMSProvider(t, rec2)
 
-	recipient3KMSProvider, err := mockkms.NewMockProvider(rec3)
-	require.NoError(t, err)
+	recipient3KMSProvider := newKMSProvider(t, rec3)
 
 	badKey := cryptoutil.KeyPair{
 		Pub:  nil,
@@ -212,7 +222,14 @@ func TestEncrypt(t *testing.T) {
 
 		enc, e := packer.Pack([]byte("lorem ipsum dolor sit amet"),
 			badKey.Pub, [][]byte{rec1.SigKeyPair.Pub, rec2.SigKeyPair.Pub, rec3.SigKeyPair.Pub})
-		require.EqualError(t, e, "key not found")
+		require.EqualError(t, e, "6-byte key size is invalid")
+		require.Empty(t, enc)
+
+		// test sender key missing from the KMS
+		enc, e = packer.Pack([]byte("lorem ipsum dolor sit amet"),
+			rec1.SigKeyPair.Pub, [][]byte{rec2.SigKeyPair.Pub, rec3.SigKeyPair.Pub})
+		require.True(t, errors.Is(e, kms.ErrKeyNotFound))
+		require.Contains(t, e.Error(), "get sender key")
 		require.Empty(t, enc)
 
 		// reset badKey
@@ -288,7 +305,7 @@ func TestEncrypt(t *testing.T) {
 	})
 
 	t.Run("Success test case: Decrypting a message (with the same packer)", func(t *testing.T) {
-		// not a real life scenario, the legacyKMS is using both sender and rec1 key pairs
+		// not a real life scenario, the KMS is using both sender and rec1 key pairs
 		// allKMSProvider is used here for testing purposes only
 		packer, e := New(allKMSProvider, XC20P)
 		require.NoError(t, e)
@@ -304,7 +321,7 @@ func TestEncrypt(t *testing.T) {
 		t.Logf("Encryption with unescaped XC20P: %s", enc)
 		t.Logf("Encryption with XC20P: %s", m)
 
-		// decrypt for rec1 (as found in legacyKMS)
+		// decrypt for rec1 (as found in KMS)
 		env, e := packer.Unpack(enc)
 		require.NoError(t, e)
 		require.NotEmpty(t, env)
@@ -324,14 +341,13 @@ func TestEncrypt(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, recPacker)
 
-		encSign, _, err := allKMSProvider.LegacyKMS().CreateKeySet()
+		_, senderKey, err := allKMSProvider.KMS().CreateAndExportPubKeyBytes(kms.ED25519Type)
 		require.NoError(t, err)
-		_, recSign, err := allKMSProvider.LegacyKMS().CreateKeySet()
+		_, recKey, err := allKMSProvider.KMS().CreateAndExportPubKeyBytes(kms.ED25519Type)
 		require.NoError(t, err)
 
 		msgIn := []byte("Lorem ipsum dolor sit amet, consectetur adipiscing elit.")
-		enc, err := sendPacker.Pack(msgIn, base58.Decode(encSign),
-			[][]byte{base58.Decode(recSign)})
+		enc, err := sendPacker.Pack(msgIn, senderKey, [][]byte{recKey})
 		require.NoError(t, err)
 
 		env, err := recPacker.Unpack(enc)
@@ -339,12 +355,12 @@ func TestEncrypt(t *testing.T) {
 		require.NotEmpty(t, env)
 		require.NotEmpty(t, env.Message)
 		require.Equal(t, msgIn, env.Message)
-		require.NotEmpty(t, env.FromVerKey)
-		require.Equal(t, base58.Encode(env.FromVerKey), encSign)
-		// this won't work, since input keys and output keys to this Packer
-		// are different types.
-		// require.Equal(t, base58.Encode(recKey), recSign)
-		require.NotEmpty(t, env.ToVerKey)
+
+		// the sender key is unpacked as an encryption key
+		senderEncKey, err := cryptoutil.PublicEd25519toCurve25519(senderKey)
+		require.NoError(t, err)
+		require.Equal(t, senderEncKey, env.FromVerKey)
+		require.Equal(t, recKey, env.ToVerKey)
 	})
 
 	t.Run("Success test case: Decrypting a message with two PackerValue instances to simulate two agents", func(t *testing.T) { //nolint:lll
@@ -606,61 +622,175 @@ func TestBadCreateCipher(t *testing.T) {
 	require.Error(t, err)
 }
 
-func TestRefEncrypt(t *testing.T) {
-	// reference php crypto material similar to
-	// https://github.com/hyperledger/aries-rfcs/issues/133#issuecomment-518922447
-	var recipientPrivStr = "c8CSJr_27PN9xWCpzXNmepRndD6neQcnO9DS0YWjhNs"
-	recipientPriv, err := base64.RawURLEncoding.DecodeString(recipientPrivStr)
+func TestInteropWithJWEEncrypt(t *testing.T) {
+	sender := newMessagingKeys(t)
+	recipient := newMessagingKeys(t)
+
+	packer, err := New(newKMSProvider(t, sender, recipient), XC20P)
+	require.NoError(t, err)
+
+	payload := []byte("lorem ipsum dolor sit amet")
+
+	t.Run("JWE encrypted with jose.JWEEncrypt for an X25519 key is unpacked by the packer", func(t *testing.T) {
+		// with several recipients, the JWE is serialized with a recipients list as the envelopes of the packer
+		jweEncrypter, err := jose.NewJWEEncrypt(jose.XC20P, []ecdhessubtle.ECPublicKey{
+			{X: recipient.EncKeyPair.Pub, Curve: ecdhessubtle.X25519Curve},
+			{X: sender.EncKeyPair.Pub, Curve: ecdhessubtle.X25519Curve},
+		})
+		require.NoError(t, err)
+
+		jwe, err := jweEncrypter.Encrypt(payload, []byte("aad value"))
+		require.NoError(t, err)
+		require.Len(t, jwe.Recipients, 2)
+
+		// the packer finds the key of a recipient from its KID
+		jwe.Recipients[0].Header.KID = base58.Encode(recipient.SigKeyPair.Pub)
+		jwe.Recipients[1].Header.KID = base58.Encode(sender.SigKeyPair.Pub)
+
+		serializedJWE, err := jwe.Serialize(json.Marshal)
+		require.NoError(t, err)
+
+		envelope, err := packer.Unpack([]byte(serializedJWE))
+		require.NoError(t, err)
+		require.Equal(t, payload, envelope.Message)
+		require.Empty(t, envelope.FromVerKey)
+		require.Equal(t, []byte(recipient.SigKeyPair.Pub), envelope.ToVerKey)
+	})
+
+	t.Run("envelope packed by PackAnoncrypt is decrypted by jose.JWEDecrypt", func(t *testing.T) {
+		envelope, err := packer.PackAnoncrypt(payload, [][]byte{recipient.SigKeyPair.Pub})
+		require.NoError(t, err)
+
+		jwe, err := jose.Deserialize(string(envelope))
+		require.NoError(t, err)
+
+		msg, err := jose.NewJWEDecrypt(newX25519KeyHandle(t, recipient.EncKeyPair)).Decrypt(jwe)
+		require.NoError(t, err)
+		require.Equal(t, payload, msg)
+
+		// the recipient unpacks it too, without a sender key
+		unpacked, err := packer.Unpack(envelope)
+		require.NoError(t, err)
+		require.Equal(t, payload, unpacked.Message)
+		require.Empty(t, unpacked.FromVerKey)
+	})
+
+	t.Run("envelope packed by the packer is read with jose and decrypted by the Tink XC20P primitive", func(t *testing.T) {
+		nonce := make([]byte, chacha.NonceSizeX)
+		cek := make([]byte, chacha.KeySize)
+
+		_, err := rand.Read(append(nonce, cek...))
+		require.NoError(t, err)
+
+		// Pack reads the nonce then the cek of the payload encryption
+		packer.randReader = io.MultiReader(bytes.NewReader(append(nonce, cek...)), rand.Reader)
+		defer func() { packer.randReader = rand.Reader }()
+
+		envelope, err := packer.Pack(payload, sender.SigKeyPair.Pub, [][]byte{recipient.SigKeyPair.Pub})
+		require.NoError(t, err)
+
+		jwe, err := jose.Deserialize(string(envelope))
+		require.NoError(t, err)
+		require.Len(t, jwe.Recipients, 1)
+		require.Equal(t, base58.Encode(recipient.SigKeyPair.Pub), jwe.Recipients[0].Header.KID)
+
+		encAlg, ok := jwe.ProtectedHeaders.Encryption()
+		require.True(t, ok)
+		require.Equal(t, string(jose.XC20P), encAlg)
+
+		aeadPrimitive, err := subtleaead.NewXChaCha20Poly1305(cek)
+		require.NoError(t, err)
+
+		rawEnvelope := &Envelope{}
+		require.NoError(t, json.Unmarshal(envelope, rawEnvelope))
+
+		ct := append([]byte(jwe.IV), []byte(jwe.Ciphertext)...)
+		ct = append(ct, []byte(jwe.Tag)...)
+
+		msg, err := aeadPrimitive.Decrypt(ct, []byte(rawEnvelope.Protected+"."+rawEnvelope.AAD))
+		require.NoError(t, err)
+		require.Equal(t, payload, msg)
+	})
+
+	t.Run("anoncrypt envelope with an epk which isn't an X25519 key fails to unpack", func(t *testing.T) {
+		envelope, err := packer.PackAnoncrypt(payload, [][]byte{recipient.SigKeyPair.Pub})
+		require.NoError(t, err)
+
+		jwe := &Envelope{}
+		require.NoError(t, json.Unmarshal(envelope, jwe))
+
+		jwe.Recipients[0].Header.EPK = []byte(`{"kty":"EC","crv":"P-256","x":"AA","y":"AA"}`)
+
+		envelope, err = json.Marshal(jwe)
+		require.NoError(t, err)
+
+		_, err = packer.Unpack(envelope)
+		require.EqualError(t, err, "unpack: epk curve 'P-256' isn't supported")
+	})
+}
+
+func newMessagingKeys(t *testing.T) *cryptoutil.MessagingKeys {
+	t.Helper()
+
+	sigPubKey, sigPrivKey, err := ed25519.GenerateKey(rand.Reader)
 	require.NoError(t, err)
 
-	var recipientPubStr = "AAjrHjiFLw6kf6CZ5zqH1ooG3y2aQhuqxmUvqJnIvDI"
-	recipientPub, err := base64.RawURLEncoding.DecodeString(recipientPubStr)
+	encPubKey, err := cryptoutil.PublicEd25519toCurve25519(sigPubKey)
 	require.NoError(t, err)
 
-	senderVerKeypCombo := &cryptoutil.MessagingKeys{
+	encPrivKey, err := cryptoutil.SecretEd25519toCurve25519(sigPrivKey)
+	require.NoError(t, err)
+
+	return &cryptoutil.MessagingKeys{
 		SigKeyPair: &cryptoutil.SigKeyPair{
-			KeyPair: cryptoutil.KeyPair{Priv: recipientPub, Pub: recipientPub}, // mocking signature keys as enc keys
+			KeyPair: cryptoutil.KeyPair{Pub: sigPubKey, Priv: sigPrivKey},
 			Alg:     cryptoutil.EdDSA,
 		},
 		EncKeyPair: &cryptoutil.EncKeyPair{
-			KeyPair: cryptoutil.KeyPair{Pub: recipientPub, Priv: recipientPriv},
+			KeyPair: cryptoutil.KeyPair{Pub: encPubKey, Priv: encPrivKey},
 			Alg:     cryptoutil.Curve25519,
 		},
 	}
-	// create mockkms provider with the above keys
-	mockKMSProvider, err := mockkms.NewMockProvider(senderVerKeypCombo)
+}
+
+// newKMSProvider creates a provider with a crypto and a KMS holding the signature private keys of kp
+func newKMSProvider(t *testing.T, kp ...*cryptoutil.MessagingKeys) *mockprovider.Provider {
+	k, err := localkms.New("local-lock://test/key/uri",
+		mockkms.NewProvider(mockstorage.NewMockStoreProvider(), &noop.NoLock{}))
 	require.NoError(t, err)
 
-	// refJWE created by executing PHP test code at:
-	// https://github.com/gamringer/php-authcrypt/blob/master/examples/1-crypt.php
-	//nolint:lll
-	const refJWE = `{
-    "protected": "eyJ0eXAiOiJwcnMuaHlwZXJsZWRnZXIuYXJpZXMtYXV0aC1tZXNzYWdlIiwiYWxnIjoiRUNESC1TUytYQzIwUEtXIiwiZW5jIjoiWEMyMFAifQ",
-    "recipients": [
-        {
-            "encrypted_key": "46R0uW5KUbaZYt5PpIW5j1v_H8BS2SLrdPEzUaK8V0U",
-            "header": {
-                "apu": "tDzm-bgMblZUgzONI7NTHcSqObP9NX21Vkeid8RFf-PzbJrdU3ApC_f0fDfZVxTwyw-5OZQcTti1H1esIfBFvg",
-                "iv": "5HTxplQx5sOfwWtfR5oK416ahbRChh-b",
-                "tag": "qrtr29m4EKh5WV6l47fcCw",
-                "kid": "18tUZoFCoRVEHdxTyNLRxzcKYV7ZyBm98gunvcChKr1",
-                "spk": "eyJ0eXAiOiJqb3NlIiwiY3R5IjoiandrK2pzb24iLCJhbGciOiJFQ0RILUVTK1hDMjBQS1ciLCJlbmMiOiJYQzIwUCIsImVwayI6eyJrdHkiOiJPS1AiLCJjcnYiOiJYMjU1MTkiLCJ4IjoiT0ZkRlN3bTR5Sm5oZmxZNUNZZ1FSVG9ra2ExNHQ0VnNCM216M0N4XzZuayJ9LCJpdiI6Ik5SZkp6Z1N5UE9JU3dOMURSR3lTSERXcXVqdUVXQmgtIiwidGFnIjoibTFsekRSTTl5VEp5cEJOYkVnSE5adyJ9.KIcpv4hUlq0gAb8FpWkSWFnlcshrdNRz51iVoTFyy7E.53YTian9wG5u-S2J2YTjI1TayqW-YMuL.uw6ucr25OIZTfsGQRp8t9fllV0ClBmuhblnTHG6hlh0EEqAWal9jgd6jDbf6Xb_HPzpLSfX7uwYTA11Ui7jZloP8aRjnAKsiEO1-4d-R.GTwXUgcy89zjIAi1Z4WpIA"
-            }
-        }
-    ],
-    "aad": "rC0KS-IDOnn39WJvPXJQmP3M5qd_Ax4sYidWXdXSIek",
-    "iv": "JS2FxjEKdndnt-J7QX5pEnVwyBTu0_3d",
-    "tag": "2FqZMMQuNPYfL0JsSkj8LQ",
-    "ciphertext": "qQyzvajdvCDJbwxM"
-}`
-
-	packer, err := New(mockKMSProvider, XC20P)
+	for _, keys := range kp {
+		_, _, err = k.ImportPrivateKey(ed25519.PrivateKey(keys.SigKeyPair.Priv), kms.ED25519Type)
+		require.NoError(t, err)
+	}
+
+	c, err := tinkcrypto.New()
 	require.NoError(t, err)
-	require.NotNil(t, packer)
 
-	env, err := packer.Unpack([]byte(refJWE))
+	return &mockprovider.Provider{KMSValue: k, CryptoValue: c}
+}
+
+// newX25519KeyHandle creates an ECDH-ES X25519 key handle, as used by jose.JWEDecrypt, holding the key pair kp
+func newX25519KeyHandle(t *testing.T, kp *cryptoutil.EncKeyPair) *keyset.Handle {
+	t.Helper()
+
+	kh, err := keyset.NewHandle(ecdhes.ECDHESX25519KWXChaCha20Poly1305KeyTemplate())
+	require.NoError(t, err)
+
+	ks := &keyset.MemReaderWriter{}
+	require.NoError(t, insecurecleartextkeyset.Write(kh, ks))
+
+	privKey := new(ecdhespb.EcdhesAeadPrivateKey)
+	require.NoError(t, proto.Unmarshal(ks.Keyset.Key[0].KeyData.Value, privKey))
+
+	privKey.KeyValue = kp.Priv
+	privKey.PublicKey.X = kp.Pub
+
+	ks.Keyset.Key[0].KeyData.Value, err = proto.Marshal(privKey)
 	require.NoError(t, err)
-	require.NotEmpty(t, env)
-	require.NotEmpty(t, env.Message)
-	require.NotEmpty(t, env.FromVerKey)
+
+	kh, err = insecurecleartextkeyset.Read(ks)
+	require.NoError(t, err)
+
+	return kh
 }
@@ -11,14 +11,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
-
-	chacha "golang.org/x/crypto/chacha20poly1305"
 )
 
 // decryptSPK will decrypt a recipient's encrypted SPK (in the case of this package, it is represented as
-// the sender's public key as a jwk). It uses the recipent's private/public keypair for decryption
+// the sender's public key as a jwk). It uses the recipent's private key of recipientKH for decryption
 // the returned decrypted value is the sender's public key
-func (p *Packer) decryptSPK(recipientPubKey *[chacha.KeySize]byte, spk string) ([]byte, error) {
+func (p *Packer) decryptSPK(recipientKH interface{}, spk string) ([]byte, error) {
 	const jweNumComponents = 5
 
 	// Since spk is a raw JSON message it'll be surrounded by quotes. Need to trim them off first.
@@ -65,7 +63,7 @@ func (p *Packer) decryptSPK(recipientPubKey *[chacha.KeySize]byte, spk string) (
 		return nil, err
 	}
 
-	sharedKey, err := p.decryptJWKSharedKey(cipherKEK, headersJSON, recipientPubKey[:])
+	sharedKey, err := p.decryptJWKSharedKey(recipientKH, cipherKEK, headersJSON)
 	if err != nil {
 		return nil, err
 	}
@@ -74,15 +72,16 @@ func (p *Packer) decryptSPK(recipientPubKey *[chacha.KeySize]byte, spk string) (
 	return p.decryptSenderJWK(nonce, sharedKey, []byte(headersEncoded), cipherJWK, tag)
 }
 
-// decryptJWKSharedKey will decrypt the cek using recPrivKey for decryption and rebuild the cipher text, nonce
-// kek from headersJSON, the result is the sharedKey to be used for decrypting the sender JWK
-func (p *Packer) decryptJWKSharedKey(cipherKEK []byte, headersJSON *recipientSPKJWEHeaders, recPubKey []byte) ([]byte, error) { //nolint:lll
+// decryptJWKSharedKey will decrypt the cek using the private key of recipientKH for decryption and rebuild
+// the cipher text, nonce kek from headersJSON, the result is the sharedKey to be used for decrypting the sender JWK
+func (p *Packer) decryptJWKSharedKey(recipientKH interface{}, cipherKEK []byte,
+	headersJSON *recipientSPKJWEHeaders) ([]byte, error) {
 	epk, err := base64.RawURLEncoding.DecodeString(headersJSON.EPK.X)
 	if err != nil {
 		return nil, err
 	}
 
-	kek, err := p.legacyKMS.DeriveKEK([]byte(p.alg+"KW"), nil, recPubKey, epk)
+	kek, err := p.crypto.DeriveKEK([]byte(p.alg+"KW"), nil, epk, recipientKH)
 	if err != nil {
 		return nil, err
 	}
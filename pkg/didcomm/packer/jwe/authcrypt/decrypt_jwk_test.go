@@ -12,16 +12,11 @@ import (
 
 	"github.com/stretchr/testify/require"
 	chacha "golang.org/x/crypto/chacha20poly1305"
-
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
 )
 
 //nolint:lll
 func TestNilDecryptSenderJwk(t *testing.T) {
-	mockKMSProvider, err := mockkms.NewMockProvider()
-	require.NoError(t, err)
-
-	packer, err := New(mockKMSProvider, XC20P)
+	packer, err := New(newKMSProvider(t), XC20P)
 	require.NoError(t, err)
 
 	spk, err := packer.decryptSPK(nil, "!-.t.t.t.t")
@@ -48,25 +43,25 @@ func TestNilDecryptSenderJwk(t *testing.T) {
 		X: "test",
 	}}
 	someKey := new([chacha.KeySize]byte)
-	spk, err = packer.decryptJWKSharedKey([]byte(""), headersJSON, someKey[:])
+	spk, err = packer.decryptJWKSharedKey(nil, []byte(""), headersJSON)
 	require.Error(t, err)
 	require.Empty(t, spk)
 
 	headersJSON.EPK.X = "!-"
-	spk, err = packer.decryptJWKSharedKey([]byte(""), headersJSON, someKey[:])
+	spk, err = packer.decryptJWKSharedKey(nil, []byte(""), headersJSON)
 	require.Error(t, err)
 	require.Empty(t, spk)
 
 	headersJSON.EPK.X = "test"
 	headersJSON.Tag = "!-"
 
-	spk, err = packer.decryptJWKSharedKey([]byte(""), headersJSON, someKey[:])
+	spk, err = packer.decryptJWKSharedKey(nil, []byte(""), headersJSON)
 	require.Error(t, err)
 	require.Empty(t, spk)
 
 	headersJSON.Tag = "test"
 	headersJSON.IV = "!-"
-	spk, err = packer.decryptJWKSharedKey([]byte(""), headersJSON, someKey[:])
+	spk, err = packer.decryptJWKSharedKey(nil, []byte(""), headersJSON)
 	require.Error(t, err)
 	require.Empty(t, spk)
 
@@ -13,15 +13,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 	chacha "golang.org/x/crypto/chacha20poly1305"
-
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
 )
 
 func TestNilEncryptSenderJwk(t *testing.T) {
-	mockKMSProvider, err := mockkms.NewMockProvider()
-	require.NoError(t, err)
-
-	packer, err := New(mockKMSProvider, XC20P)
+	packer, err := New(newKMSProvider(t), XC20P)
 	require.NoError(t, err)
 
 	spk, err := packer.generateSPK(nil, nil)
@@ -56,7 +51,7 @@ func TestNilEncryptSenderJwk(t *testing.T) {
 	require.Error(t, err)
 	require.Empty(t, spk)
 
-	r, err := packer.encodeRecipient(someKey, someKey, someKey)
+	r, err := packer.encodeRecipient(nil, someKey, someKey, someKey[:], someKey)
 	require.Error(t, err)
 	require.Empty(t, r)
 
@@ -18,15 +18,16 @@ import (
 	chacha "golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/poly1305"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 // Pack will JWE encode the payload argument for the sender and recipients
 // Using (X)Chacha20 encryption algorithm and Poly1305 authenticator
-// It will encrypt by fetching the sender's encryption key corresponding to senderVerKey and converting the list
-// of recipientsVerKeys into a list of encryption keys
-func (p *Packer) Pack(payload, senderVerKey []byte, recipientsVerKeys [][]byte) ([]byte, error) { //nolint:funlen
+// It will encrypt with the KMS key of senderVerKey and the list of recipientsVerKeys, all converted to encryption keys
+func (p *Packer) Pack(payload, senderVerKey []byte, recipientsVerKeys [][]byte) ([]byte, error) {
 	senderPubKey, err := p.getSenderPubEncKey(senderVerKey)
 	if err != nil {
 		return nil, err
@@ -38,6 +39,37 @@ func (p *Packer) Pack(payload, senderVerKey []byte, recipientsVerKeys [][]byte)
 		Enc: string(p.alg),
 	}
 
+	return p.pack(payload, headers, recipientsVerKeys,
+		func(cek *[chacha.KeySize]byte, chachaRecipients []*[chacha.KeySize]byte) ([]*jose.Recipient, error) {
+			return p.encodeRecipients(cek, chachaRecipients, recipientsVerKeys, senderVerKey, senderPubKey)
+		})
+}
+
+// PackAnoncrypt will JWE encode the payload argument for the recipients without a sender, as jose.JWEEncrypt does:
+// the cek is wrapped for each recipient with ECDH-ES, using a new ephemeral key, and AES key wrap.
+// The envelope is unpacked by Unpack, without a sender key, and decrypted by jose.JWEDecrypt with the X25519 key of
+// a recipient.
+func (p *Packer) PackAnoncrypt(payload []byte, recipientsVerKeys [][]byte) ([]byte, error) {
+	// jose computes the authenticated data from the protected headers marshalled as a map, the keys are sorted
+	headers := map[string]string{
+		"typ": encodingType,
+		"alg": crypto.ECDHESA256KWAlg,
+		"enc": string(p.alg),
+	}
+
+	return p.pack(payload, headers, recipientsVerKeys,
+		func(cek *[chacha.KeySize]byte, chachaRecipients []*[chacha.KeySize]byte) ([]*jose.Recipient, error) {
+			return p.encodeAnoncryptRecipients(cek, chachaRecipients, recipientsVerKeys)
+		})
+}
+
+// encodeRecipientsFunc encrypts the cek for each of the recipients encryption keys
+type encodeRecipientsFunc func(cek *[chacha.KeySize]byte, recipients []*[chacha.KeySize]byte) ([]*jose.Recipient, error)
+
+// pack encrypts payload with a new cek, encrypted for the recipients by encodeRecipients, and builds the JWE
+// with the protected headers.
+func (p *Packer) pack(payload []byte, headers interface{}, recipientsVerKeys [][]byte,
+	encodeRecipients encodeRecipientsFunc) ([]byte, error) { //nolint:funlen
 	if len(recipientsVerKeys) == 0 {
 		return nil, fmt.Errorf("failed to pack message: empty recipients")
 	}
@@ -90,7 +122,7 @@ func (p *Packer) Pack(payload, senderVerKey []byte, recipientsVerKeys [][]byte)
 	cipherTextEncoded := extractCipherText(symOutput)
 
 	// now build, encode recipients and include the encrypted cek (with a recipient's ephemeral key)
-	encRec, err := p.encodeRecipients(cek, chachaRecipients, senderPubKey)
+	encRec, err := encodeRecipients(cek, chachaRecipients)
 	if err != nil {
 		return nil, err
 	}
@@ -108,7 +140,7 @@ func (p *Packer) getSenderPubEncKey(senderVerKey []byte) (*[chacha.KeySize]byte,
 		return nil, fmt.Errorf("failed to pack message: empty sender key")
 	}
 
-	senderKey, err := p.legacyKMS.GetEncryptionKey(senderVerKey)
+	senderKey, err := cryptoutil.PublicEd25519toCurve25519(senderVerKey)
 	if err != nil {
 		return nil, err
 	}
@@ -206,13 +238,19 @@ func hashAAD(keys []string) []byte {
 // encodeRecipients is a utility function that will encrypt the cek (content encryption key) for each recipient
 // and return a list of encoded recipient keys in a JWE compliant format ([]Recipient)
 func (p *Packer) encodeRecipients(cek *[chacha.KeySize]byte, recipients []*[chacha.KeySize]byte,
-	senderPubKey *[chacha.KeySize]byte) ([]*jose.Recipient, error) {
+	recipientsVerKeys [][]byte, senderVerKey []byte, senderPubKey *[chacha.KeySize]byte) ([]*jose.Recipient, error) {
 	var encodedRecipients []*jose.Recipient
 
-	for _, e := range recipients {
-		rec, err := p.encodeRecipient(cek, e, senderPubKey)
-		if err != nil {
-			return nil, err
+	// the sender private key is the KMS key of senderVerKey
+	senderKH, err := p.kms.Get(kms.PublicKeyID(senderVerKey))
+	if err != nil {
+		return nil, fmt.Errorf("get sender key: %w", err)
+	}
+
+	for i, e := range recipients {
+		rec, er := p.encodeRecipient(senderKH, cek, e, recipientsVerKeys[i], senderPubKey)
+		if er != nil {
+			return nil, er
 		}
 
 		encodedRecipients = append(encodedRecipients, rec)
@@ -224,7 +262,8 @@ func (p *Packer) encodeRecipients(cek *[chacha.KeySize]byte, recipients []*[chac
 // encodeRecipient will encrypt the cek (content encryption key) with a recipientKey
 // by generating a new ephemeral key to be used by the recipient to later decrypt it
 // it returns a JWE compliant Recipient
-func (p *Packer) encodeRecipient(cek, recipientPubKey, senderPubKey *[chacha.KeySize]byte) (*jose.Recipient, error) {
+func (p *Packer) encodeRecipient(senderKH interface{}, cek, recipientPubKey *[chacha.KeySize]byte,
+	recipientVerKey []byte, senderPubKey *[chacha.KeySize]byte) (*jose.Recipient, error) {
 	// generate a random APU value (Agreement PartyUInfo: https://tools.ietf.org/html/rfc7518#section-4.6.1.2)
 	apu := make([]byte, 64)
 
@@ -234,7 +273,7 @@ func (p *Packer) encodeRecipient(cek, recipientPubKey, senderPubKey *[chacha.Key
 	}
 
 	// derive an ephemeral key for the sender and recipient
-	kek, err := p.legacyKMS.DeriveKEK([]byte(p.alg), apu, senderPubKey[:], recipientPubKey[:])
+	kek, err := p.crypto.DeriveKEK([]byte(p.alg), apu, recipientPubKey[:], senderKH)
 	if err != nil {
 		return nil, err
 	}
@@ -249,16 +288,51 @@ func (p *Packer) encodeRecipient(cek, recipientPubKey, senderPubKey *[chacha.Key
 		return nil, err
 	}
 
-	return p.buildRecipient(sharedKeyCipher, apu, spk, nonce, tag, recipientPubKey)
+	return p.buildRecipient(sharedKeyCipher, apu, spk, nonce, tag, recipientVerKey)
+}
+
+// encodeAnoncryptRecipients will wrap the cek for each of the recipients with ECDH-ES, the recipient KID being the
+// recipient verification key in b58 encoding as for the authcrypt recipients
+func (p *Packer) encodeAnoncryptRecipients(cek *[chacha.KeySize]byte, recipients []*[chacha.KeySize]byte,
+	recipientsVerKeys [][]byte) ([]*jose.Recipient, error) {
+	var encodedRecipients []*jose.Recipient
+
+	for i, e := range recipients {
+		wk, err := p.crypto.WrapKey(cek[:], nil, nil, &crypto.PublicKey{X: e[:], Curve: crypto.X25519Curve, Type: "OKP"})
+		if err != nil {
+			return nil, fmt.Errorf("wrap key: %w", err)
+		}
+
+		epk, err := json.Marshal(jwk{
+			Kty: "OKP",
+			Crv: crypto.X25519Curve,
+			X:   base64.RawURLEncoding.EncodeToString(wk.EPK.X),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encodedRecipients = append(encodedRecipients, &jose.Recipient{
+			EncryptedKey: base64.RawURLEncoding.EncodeToString(wk.EncryptedCEK),
+			Header: &jose.RecipientHeaders{
+				Alg: wk.Alg,
+				KID: base58.Encode(recipientsVerKeys[i]),
+				EPK: epk,
+			},
+		})
+	}
+
+	return encodedRecipients, nil
 }
 
 // buildRecipient will build a proper JSON formatted and JWE compliant Recipient
-func (p *Packer) buildRecipient(key string, apu []byte, spkEncoded, nonceEncoded, tagEncoded string, recipientKey *[chacha.KeySize]byte) (*jose.Recipient, error) { //nolint:lll
+// The recipient KID is the recipientVerKey in b58 encoding, the recipient finds its key in its KMS from it.
+func (p *Packer) buildRecipient(key string, apu []byte, spkEncoded, nonceEncoded, tagEncoded string, recipientVerKey []byte) (*jose.Recipient, error) { //nolint:lll
 	recipientHeaders := jose.RecipientHeaders{
 		APU: base64.RawURLEncoding.EncodeToString(apu),
 		IV:  nonceEncoded,
 		Tag: tagEncoded,
-		KID: base58.Encode(recipientKey[:]),
+		KID: base58.Encode(recipientVerKey),
 		SPK: []byte(`"` + spkEncoded + `"`),
 	}
 
@@ -15,8 +15,11 @@ import (
 	"github.com/btcsuite/btcutil/base58"
 	chacha "golang.org/x/crypto/chacha20poly1305"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 // Unpack will JWE decode the envelope argument for the recipientPrivKey and validates
@@ -25,6 +28,8 @@ import (
 // encrypted CEK.
 // The current recipient is the one with the sender's encrypted key that successfully
 // decrypts with recipientKeyPair.Priv Key.
+// The envelopes packed by PackAnoncrypt, or encrypted by jose.JWEEncrypt with the recipient KIDs set, are unpacked
+// without a sender key.
 func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 	jwe := &Envelope{}
 
@@ -33,12 +38,23 @@ func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 		return nil, fmt.Errorf("unpack json: %w", err)
 	}
 
-	recipientPubKey, recipient, err := p.findRecipient(jwe.Recipients)
+	recipientVerKey, recipient, err := p.findRecipient(jwe.Recipients)
 	if err != nil {
 		return nil, fmt.Errorf("unpack: %w", err)
 	}
 
-	senderKey, err := p.decryptSPK(recipientPubKey, string(recipient.Header.SPK))
+	recipientKH, err := p.kms.Get(kms.PublicKeyID(recipientVerKey))
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	// the cek of the envelopes packed by PackAnoncrypt, or encrypted by jose.JWEEncrypt, is wrapped with ECDH-ES
+	// and an ephemeral key of the sender, they don't have a sender key
+	if len(recipient.Header.EPK) > 0 {
+		return p.unpackAnoncrypt(recipientKH, recipientVerKey, recipient, jwe)
+	}
+
+	senderKey, err := p.decryptSPK(recipientKH, string(recipient.Header.SPK))
 	if err != nil {
 		return nil, fmt.Errorf("unpack: sender key: %w", err)
 	}
@@ -48,7 +64,7 @@ func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 		senderPubKey := new([chacha.KeySize]byte)
 		copy(senderPubKey[:], senderKey)
 
-		sharedKey, er := p.decryptCEK(recipientPubKey, senderPubKey, recipient)
+		sharedKey, er := p.decryptCEK(recipientKH, senderPubKey, recipient)
 		if er != nil {
 			return nil, fmt.Errorf("unpack: decrypt shared key: %w", er)
 		}
@@ -61,20 +77,69 @@ func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 		return &transport.Envelope{
 			Message:    symOutput,
 			FromVerKey: senderKey,
-			ToVerKey:   recipientPubKey[:],
+			ToVerKey:   recipientVerKey,
 		}, nil
 	}
 
 	return nil, errors.New("unpack: invalid sender key in envelope")
 }
 
+// unpackAnoncrypt will unwrap the cek of the ECDH-ES recipient with the private key of recipientKH and decrypt the
+// payload of jwe with it. The envelope has no sender key.
+func (p *Packer) unpackAnoncrypt(recipientKH interface{}, recipientVerKey []byte, recipient *jose.Recipient,
+	jwe *Envelope) (*transport.Envelope, error) {
+	epk := &jwk{}
+
+	err := json.Unmarshal(recipient.Header.EPK, epk)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: epk: %w", err)
+	}
+
+	if epk.Crv != crypto.X25519Curve {
+		return nil, fmt.Errorf("unpack: epk curve '%s' isn't supported", epk.Crv)
+	}
+
+	epkX, err := base64.RawURLEncoding.DecodeString(epk.X)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: epk: %w", err)
+	}
+
+	encryptedCEK, err := base64.RawURLEncoding.DecodeString(recipient.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: encrypted key: %w", err)
+	}
+
+	cek, err := p.crypto.UnwrapKey(&crypto.RecipientWrappedKey{
+		EncryptedCEK: encryptedCEK,
+		EPK:          crypto.PublicKey{X: epkX, Curve: crypto.X25519Curve, Type: epk.Kty},
+		Alg:          recipient.Header.Alg,
+	}, recipientKH)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: unwrap key: %w", err)
+	}
+
+	symOutput, err := p.decryptPayload(cek, jwe)
+	if err != nil {
+		return nil, fmt.Errorf("unpack: %w", err)
+	}
+
+	return &transport.Envelope{
+		Message:  symOutput,
+		ToVerKey: recipientVerKey,
+	}, nil
+}
+
 func (p *Packer) decryptPayload(cek []byte, jwe *Envelope) ([]byte, error) {
 	cipher, err := createCipher(p.nonceSize, cek)
 	if err != nil {
 		return nil, err
 	}
 
-	pldAAD := jwe.Protected + "." + jwe.AAD
+	// as computed by jose, the authenticated data has no AAD part when the JWE has no AAD
+	pldAAD := jwe.Protected
+	if jwe.AAD != "" {
+		pldAAD += "." + jwe.AAD
+	}
 
 	payload, err := base64.RawURLEncoding.DecodeString(jwe.CipherText)
 	if err != nil {
@@ -96,26 +161,24 @@ func (p *Packer) decryptPayload(cek []byte, jwe *Envelope) ([]byte, error) {
 	return cipher.Open(nil, nonce, payload, []byte(pldAAD))
 }
 
-// findRecipient will loop through jweRecipients and returns the first matching key from the legacyKMS
-func (p *Packer) findRecipient(jweRecipients []*jose.Recipient) (*[chacha.KeySize]byte, *jose.Recipient, error) {
-	var recipientsKeys []string
+// findRecipient will loop through jweRecipients and returns the verification key of the first recipient whose key
+// is found in the KMS
+func (p *Packer) findRecipient(jweRecipients []*jose.Recipient) ([]byte, *jose.Recipient, error) {
 	for _, recipient := range jweRecipients {
-		recipientsKeys = append(recipientsKeys, recipient.Header.KID)
-	}
+		verKey := base58.Decode(recipient.Header.KID)
 
-	i, err := p.legacyKMS.FindVerKey(recipientsKeys)
-	if err != nil {
-		return nil, nil, err
+		// Currently chooses the first usable key, but could use different logic (eg, priorities)
+		if _, err := p.kms.Get(kms.PublicKeyID(verKey)); err == nil {
+			return verKey, recipient, nil
+		}
 	}
 
-	pubK := new([chacha.KeySize]byte)
-	copy(pubK[:], base58.Decode(recipientsKeys[i]))
-
-	return pubK, jweRecipients[i], nil
+	return nil, nil, cryptoutil.ErrKeyNotFound
 }
 
-// decryptCEK will decrypt the CEK found in recipient using recipientKp's private key and senderPubKey
-func (p *Packer) decryptCEK(recipientPubKey, senderPubKey *[chacha.KeySize]byte, recipient *jose.Recipient) ([]byte, error) { //nolint:lll
+// decryptCEK will decrypt the CEK found in recipient using the private key of recipientKH and senderPubKey
+func (p *Packer) decryptCEK(recipientKH interface{}, senderPubKey *[chacha.KeySize]byte,
+	recipient *jose.Recipient) ([]byte, error) {
 	apu, err := base64.RawURLEncoding.DecodeString(recipient.Header.APU)
 	if err != nil {
 		return nil, err
@@ -141,7 +204,7 @@ func (p *Packer) decryptCEK(recipientPubKey, senderPubKey *[chacha.KeySize]byte,
 	}
 
 	// derive an ephemeral key for the recipient
-	kek, err := p.legacyKMS.DeriveKEK([]byte(p.alg), apu, recipientPubKey[:], senderPubKey[:])
+	kek, err := p.crypto.DeriveKEK([]byte(p.alg), apu, senderPubKey[:], recipientKH)
 	if err != nil {
 		return nil, err
 	}
@@ -10,14 +10,16 @@ import (
 	"crypto/rand"
 	"io"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 // Packer represents an Authcrypt Pack/Unpacker that outputs/reads legacy Aries envelopes
 type Packer struct {
 	randSource io.Reader
-	legacyKMS  legacykms.KeyManager
+	kms        kms.KeyManager
+	crypto     crypto.Crypto
 }
 
 // encodingType is the `typ` string identifier in a message that identifies the format as being legacy
@@ -26,11 +28,12 @@ const encodingType string = "JWM/1.0"
 // New will create a Packer that encrypts messages using the legacy Aries format
 // Note: legacy Packer does not support XChacha20Poly1035 (XC20P), only Chacha20Poly1035 (C20P)
 func New(ctx packer.Provider) *Packer {
-	k := ctx.LegacyKMS()
+	k := ctx.KMS()
 
 	return &Packer{
 		randSource: rand.Reader,
-		legacyKMS:  k,
+		kms:        k,
+		crypto:     ctx.Crypto(),
 	}
 }
 
@@ -9,7 +9,6 @@ package authcrypt
 import (
 	"crypto/rand"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -20,11 +19,14 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/ed25519"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockStorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
-	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
 )
 
 // failReader wraps a Reader, used for testing different failure checks for encryption tests.
@@ -55,123 +57,62 @@ func (fw *failReader) Read(out []byte) (int, error) {
 }
 
 type provider struct {
-	storeProvider storage.Provider
-	crypto        legacykms.KeyManager
+	kms    kms.KeyManager
+	crypto crypto.Crypto
 }
 
-func (p *provider) StorageProvider() storage.Provider {
-	return p.storeProvider
-}
-
-func newKMS(t *testing.T) (*legacykms.BaseKMS, storage.Store) {
-	msp := mockStorage.NewMockStoreProvider()
-	p := provider{storeProvider: msp}
-
-	store, err := p.StorageProvider().OpenStore("test-legacyKMS")
-	require.NoError(t, err)
-
-	ret, err := legacykms.New(&p)
+func newKMS(t *testing.T) *localkms.LocalKMS {
+	k, err := localkms.New("local-lock://test/key/uri",
+		mockkms.NewProvider(mockStorage.NewMockStoreProvider(), &noop.NoLock{}))
 	require.NoError(t, err)
 
-	return ret, store
+	return k
 }
 
-func persistKey(pub, priv string, store storage.Store) error {
-	kp := cryptoutil.KeyPair{
-		Priv: base58.Decode(priv),
-		Pub:  base58.Decode(pub),
-	}
-
-	sk, err := cryptoutil.SecretEd25519toCurve25519(kp.Priv)
-	if err != nil {
-		return err
-	}
-
-	pk, err := cryptoutil.PublicEd25519toCurve25519(kp.Pub)
-	if err != nil {
-		return err
-	}
-
-	enc := cryptoutil.KeyPair{
-		Priv: sk,
-		Pub:  pk,
-	}
+func persistKey(priv string, k kms.KeyManager) error {
+	_, _, err := k.ImportPrivateKey(ed25519.PrivateKey(base58.Decode(priv)), kms.ED25519Type)
 
-	kpCombo := &cryptoutil.MessagingKeys{
-		EncKeyPair: &cryptoutil.EncKeyPair{
-			KeyPair: enc,
-			Alg:     cryptoutil.Curve25519,
-		},
-		SigKeyPair: &cryptoutil.SigKeyPair{
-			KeyPair: kp,
-			Alg:     cryptoutil.EdDSA,
-		},
-	}
+	return err
+}
 
-	data, err := json.Marshal(kpCombo)
-	if err != nil {
-		return fmt.Errorf("failed to marshal key: %w", err)
-	}
+func createKey(k kms.KeyManager) (string, error) {
+	_, pub, err := k.CreateAndExportPubKeyBytes(kms.ED25519Type)
 
-	err = store.Put(base58.Encode(pk), data)
-	if err != nil {
-		return err
-	}
+	return base58.Encode(pub), err
+}
 
-	return store.Put(pub, data)
+func (p *provider) KMS() kms.KeyManager {
+	return p.kms
 }
 
-func (p *provider) LegacyKMS() legacykms.KeyManager {
+func (p *provider) Crypto() crypto.Crypto {
 	return p.crypto
 }
 
-func newWithKMS(k legacykms.KeyManager) *Packer {
+func newWithKMS(t *testing.T, k kms.KeyManager) *Packer {
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
 	return New(&provider{
-		crypto: k,
+		kms:    k,
+		crypto: c,
 	})
 }
 
 func TestEncodingType(t *testing.T) {
-	// create temporary signing keys for tests
-	sigPubKey, sigPrivKey, err := ed25519.GenerateKey(rand.Reader)
-	require.NoError(t, err)
-
-	// convert signing keys to encryption keys
-	encPubKey, err := cryptoutil.PublicEd25519toCurve25519(sigPubKey)
-	require.NoError(t, err)
-
-	encPrivKey, err := cryptoutil.SecretEd25519toCurve25519(sigPrivKey)
-	require.NoError(t, err)
-
-	require.NoError(t, err)
-
-	kp := &cryptoutil.MessagingKeys{
-		SigKeyPair: &cryptoutil.SigKeyPair{
-			KeyPair: cryptoutil.KeyPair{Pub: sigPubKey, Priv: sigPrivKey},
-			Alg:     cryptoutil.EdDSA,
-		},
-		EncKeyPair: &cryptoutil.EncKeyPair{
-			KeyPair: cryptoutil.KeyPair{Pub: encPubKey, Priv: encPrivKey},
-			Alg:     cryptoutil.Curve25519,
-		},
-	}
-
-	kmsProvider, err := mockkms.NewMockProvider(kp)
-	require.NoError(t, err)
-
-	packer := New(kmsProvider)
+	packer := newWithKMS(t, newKMS(t))
 	require.NotEmpty(t, packer)
 
 	require.Equal(t, encodingType, packer.EncodingType())
 }
 
 func TestEncrypt(t *testing.T) {
-	testingKMS, _ := newKMS(t)
-	_, senderKey, e := testingKMS.CreateKeySet()
+	testingKMS := newKMS(t)
+	senderKey, e := createKey(testingKMS)
 	require.NoError(t, e)
 
 	t.Run("Failure: pack without any recipients", func(t *testing.T) {
-		packer := newWithKMS(testingKMS)
+		packer := newWithKMS(t, testingKMS)
 		require.NotEmpty(t, packer)
 
 		_, err := packer.Pack([]byte("Test Message"), base58.Decode(senderKey), [][]byte{})
@@ -179,7 +120,7 @@ func TestEncrypt(t *testing.T) {
 	})
 
 	t.Run("Failure: pack with an invalid recipient key", func(t *testing.T) {
-		packer := newWithKMS(testingKMS)
+		packer := newWithKMS(t, testingKMS)
 		require.NotEmpty(t, packer)
 
 		badKey := "6ZAQ7QpmR9EqhJdwx1jQsjq6nnpehwVqUbhVxiEiYEV7"
@@ -188,19 +129,20 @@ func TestEncrypt(t *testing.T) {
 		require.EqualError(t, err, "error converting public key")
 	})
 
-	_, recipientKey, e := testingKMS.CreateKeySet()
+	recipientKey, e := createKey(testingKMS)
 	require.NoError(t, e)
 
 	t.Run("Failure: pack with an invalid-size sender key", func(t *testing.T) {
-		packer := newWithKMS(testingKMS)
+		packer := newWithKMS(t, testingKMS)
 		require.NotEmpty(t, packer)
 
 		_, err := packer.Pack([]byte("Test Message"), []byte{1, 2, 3}, [][]byte{base58.Decode(recipientKey)})
-		require.EqualError(t, err, "3-byte key size is invalid")
+		require.Error(t, err)
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
 	})
 
 	t.Run("Success test case: given keys, generate envelope", func(t *testing.T) {
-		packer := newWithKMS(testingKMS)
+		packer := newWithKMS(t, testingKMS)
 		require.NotEmpty(t, packer)
 
 		enc, e := packer.Pack([]byte("Pack my box with five dozen liquor jugs!"),
@@ -210,15 +152,15 @@ func TestEncrypt(t *testing.T) {
 	})
 
 	t.Run("Generate testcase with multiple recipients", func(t *testing.T) {
-		_, senderKey, err := testingKMS.CreateKeySet()
+		senderKey, err := createKey(testingKMS)
 		require.NoError(t, err)
-		_, rec1Key, err := testingKMS.CreateKeySet()
+		rec1Key, err := createKey(testingKMS)
 		require.NoError(t, err)
-		_, rec2Key, err := testingKMS.CreateKeySet()
+		rec2Key, err := createKey(testingKMS)
 		require.NoError(t, err)
-		_, rec3Key, err := testingKMS.CreateKeySet()
+		rec3Key, err := createKey(testingKMS)
 		require.NoError(t, err)
-		_, rec4Key, err := testingKMS.CreateKeySet()
+		rec4Key, err := createKey(testingKMS)
 		require.NoError(t, err)
 
 		recipientKeys := [][]byte{
@@ -227,7 +169,7 @@ func TestEncrypt(t *testing.T) {
 			base58.Decode(rec3Key),
 			base58.Decode(rec4Key),
 		}
-		packer := newWithKMS(testingKMS)
+		packer := newWithKMS(t, testingKMS)
 
 		require.NoError(t, err)
 		require.NotEmpty(t, packer)
@@ -245,16 +187,16 @@ func TestEncrypt(t *testing.T) {
 		recipientPub := "CP1eVoFxCguQe1ttDbS3L35ZiJckZ8PZykX1SCDNgEYZ"
 		recipientPriv := "5aFcdEMws6ZUL7tWYrJ6DsZvY2GHZYui1jLcYquGr8uHfmyHCs96QU3nRUarH1gVYnMU2i4uUPV5STh2mX7EHpNu"
 
-		kms2, store := newKMS(t)
-		err := persistKey(senderPub, senderPriv, store)
+		kms2 := newKMS(t)
+		err := persistKey(senderPriv, kms2)
 		require.NoError(t, err)
-		err = persistKey(recipientPub, recipientPriv, store)
+		err = persistKey(recipientPriv, kms2)
 		require.NoError(t, err)
 
 		source := insecurerand.NewSource(5937493) // constant fixed to ensure constant output
 		constRand := insecurerand.New(source)
 
-		packer := newWithKMS(kms2)
+		packer := newWithKMS(t, kms2)
 		require.NotEmpty(t, packer)
 		packer.randSource = constRand
 		enc, err := packer.Pack(nil, base58.Decode(senderPub), [][]byte{base58.Decode(recipientPub)})
@@ -268,8 +210,8 @@ func TestEncrypt(t *testing.T) {
 	t.Run("Pack payload using deterministic random source for multiple recipients, verify result", func(t *testing.T) {
 		senderPub := "9NKZ9pHL9YVS7BzqJsz3e9uVvk44rJodKfLKbq4hmeUw"
 		senderPriv := "2VZLugb22G3iovUvGrecKj3VHFUNeCetkApeB4Fn4zkgBqYaMSFTW2nvF395voJ76vHkfnUXH2qvJoJnFydRoQBR"
-		senderKMS, senderStore := newKMS(t)
-		err := persistKey(senderPub, senderPriv, senderStore)
+		senderKMS := newKMS(t)
+		err := persistKey(senderPriv, senderKMS)
 		require.NoError(t, err)
 
 		rec1Pub := base58.Decode("DDk4ac2ZA19P8qXjk8XaCY9Fx7WwAmCtELkxeDNqS6Vs")
@@ -280,7 +222,7 @@ func TestEncrypt(t *testing.T) {
 		source := insecurerand.NewSource(6572692) // constant fixed to ensure constant output
 		constRand := insecurerand.New(source)
 
-		packer := newWithKMS(senderKMS)
+		packer := newWithKMS(t, senderKMS)
 		require.NotEmpty(t, packer)
 		packer.randSource = constRand
 		enc, err := packer.Pack(
@@ -300,11 +242,11 @@ func TestEncryptComponents(t *testing.T) {
 	senderPriv := "2VZLugb22G3iovUvGrecKj3VHFUNeCetkApeB4Fn4zkgBqYaMSFTW2nvF395voJ76vHkfnUXH2qvJoJnFydRoQBR"
 	rec1Pub := "DDk4ac2ZA19P8qXjk8XaCY9Fx7WwAmCtELkxeDNqS6Vs"
 
-	testKMS, store := newKMS(t)
-	e := persistKey(senderPub, senderPriv, store)
+	testKMS := newKMS(t)
+	e := persistKey(senderPriv, testKMS)
 	require.NoError(t, e)
 
-	packer := newWithKMS(testKMS)
+	packer := newWithKMS(t, testKMS)
 
 	t.Run("Failure: content encryption nonce generation fails", func(t *testing.T) {
 		failRand := newFailReader(0, rand.Reader)
@@ -356,28 +298,32 @@ func TestEncryptComponents(t *testing.T) {
 		require.NoError(t, err)
 	})
 
-	packer2 := newWithKMS(testKMS)
+	packer2 := newWithKMS(t, testKMS)
 
 	t.Run("Failure: generate recipient header with bad sender key", func(t *testing.T) {
-		_, err := packer2.buildRecipient(&[32]byte{}, []byte(""), base58.Decode(rec1Pub))
-		require.EqualError(t, err, "key is nil")
+		_, err := packer2.buildRecipients(&[32]byte{}, []byte(""), [][]byte{base58.Decode(rec1Pub)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get sender key")
 	})
 
 	t.Run("Failure: generate recipient header with bad recipient key", func(t *testing.T) {
-		_, err := packer2.buildRecipient(&[32]byte{}, base58.Decode(senderPub), base58.Decode("AAAA"))
+		senderKH, err := testKMS.Get(kms.PublicKeyID(base58.Decode(senderPub)))
+		require.NoError(t, err)
+
+		_, err = packer2.buildRecipient(&[32]byte{}, senderKH, base58.Decode(senderPub), base58.Decode("AAAA"))
 		require.EqualError(t, err, "3-byte key size is invalid")
 	})
 }
 
 func TestDecrypt(t *testing.T) {
-	testingKMS, _ := newKMS(t)
-	_, senderKey, err := testingKMS.CreateKeySet()
+	testingKMS := newKMS(t)
+	senderKey, err := createKey(testingKMS)
 	require.NoError(t, err)
-	_, recKey, err := testingKMS.CreateKeySet()
+	recKey, err := createKey(testingKMS)
 	require.NoError(t, err)
 
 	t.Run("Success: pack then unpack, same packer", func(t *testing.T) {
-		packer := newWithKMS(testingKMS)
+		packer := newWithKMS(t, testingKMS)
 		require.NoError(t, err)
 
 		msgIn := []byte("Junky qoph-flags vext crwd zimb.")
@@ -392,22 +338,48 @@ func TestDecrypt(t *testing.T) {
 		require.Equal(t, recKey, base58.Encode(env.ToVerKey))
 	})
 
+	t.Run("Failure: pack and unpack with keys whose policy denies the crypto box operations", func(t *testing.T) {
+		policyKMS := newKMS(t)
+		packer := newWithKMS(t, policyKMS)
+
+		signOnly := kms.WithPolicy(&kms.KeyPolicy{Operations: []kms.KeyOperation{kms.SignOp, kms.VerifyOp}})
+
+		policySenderKey, err := createKey(policyKMS)
+		require.NoError(t, err)
+		require.NoError(t, policyKMS.UpdateMetadata(kms.PublicKeyID(base58.Decode(policySenderKey)), signOnly))
+
+		_, err = packer.Pack([]byte("msg"), base58.Decode(policySenderKey), [][]byte{base58.Decode(recKey)})
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+
+		policyRecKey, err := createKey(policyKMS)
+		require.NoError(t, err)
+
+		enc, err := newWithKMS(t, testingKMS).Pack([]byte("msg"), base58.Decode(senderKey),
+			[][]byte{base58.Decode(policyRecKey)})
+		require.NoError(t, err)
+
+		require.NoError(t, policyKMS.UpdateMetadata(kms.PublicKeyID(base58.Decode(policyRecKey)), signOnly))
+
+		_, err = packer.Unpack(enc)
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+	})
+
 	t.Run("Success: pack and unpack, different packers, including fail recipient who wasn't sent the message", func(t *testing.T) { // nolint: lll
-		rec1KMS, _ := newKMS(t)
-		_, rec1Key, err := rec1KMS.CreateKeySet()
+		rec1KMS := newKMS(t)
+		rec1Key, err := createKey(rec1KMS)
 		require.NoError(t, err)
 
-		rec2KMS, _ := newKMS(t)
-		_, rec2Key, err := rec2KMS.CreateKeySet()
+		rec2KMS := newKMS(t)
+		rec2Key, err := createKey(rec2KMS)
 		require.NoError(t, err)
 
-		rec3KMS, _ := newKMS(t)
-		_, rec3Key, err := rec3KMS.CreateKeySet()
+		rec3KMS := newKMS(t)
+		rec3Key, err := createKey(rec3KMS)
 
 		require.NoError(t, err)
 
-		sendPacker := newWithKMS(testingKMS)
-		rec2Packer := newWithKMS(rec2KMS)
+		sendPacker := newWithKMS(t, testingKMS)
+		rec2Packer := newWithKMS(t, rec2KMS)
 
 		msgIn := []byte("Junky qoph-flags vext crwd zimb.")
 
@@ -420,8 +392,8 @@ func TestDecrypt(t *testing.T) {
 		require.Equal(t, senderKey, base58.Encode(env.FromVerKey))
 		require.Equal(t, rec2Key, base58.Encode(env.ToVerKey))
 
-		emptyKMS, _ := newKMS(t)
-		rec4Packer := newWithKMS(emptyKMS)
+		emptyKMS := newKMS(t)
+		rec4Packer := newWithKMS(t, emptyKMS)
 
 		_, err = rec4Packer.Unpack(enc)
 		require.NotNil(t, err)
@@ -436,11 +408,11 @@ func TestDecrypt(t *testing.T) {
 		recPub := "F7mNtF2frLuRu2cMEjXBnWdYcTZAXNP9jEkprXxiaZi1"
 		recPriv := "2nYsWTQ1ZguQ7G2HYfMWjMNqWagBQfaKB9GLbsFk7Z7tKVBEr2arwpVKDwgLUbaxguUzQuf7o67aWKzgtHmKaypM"
 
-		recKMS, store := newKMS(t)
-		err := persistKey(recPub, recPriv, store)
+		recKMS := newKMS(t)
+		err := persistKey(recPriv, recKMS)
 		require.NoError(t, err)
 
-		recPacker := newWithKMS(recKMS)
+		recPacker := newWithKMS(t, recKMS)
 
 		envOut, err := recPacker.Unpack([]byte(env))
 		require.NoError(t, err)
@@ -459,11 +431,11 @@ func TestDecrypt(t *testing.T) {
 		recPub := "AQ9nHtLnmuG81py64YG5geF2vd5hQCKHi5MrqQ1LYCXE"
 		recPriv := "2YbSVZzSVaim41bWDdsBzamrhXrPFKKEpzXZRmgDuoFJco5VQELRSj1oWFR9aRdaufsdUyw8sozTtZuX8Mzsqboz"
 
-		recKMS, store := newKMS(t)
-		err := persistKey(recPub, recPriv, store)
+		recKMS := newKMS(t)
+		err := persistKey(recPriv, recKMS)
 		require.NoError(t, err)
 
-		recPacker := newWithKMS(recKMS)
+		recPacker := newWithKMS(t, recKMS)
 
 		envOut, err := recPacker.Unpack([]byte(env))
 		require.NoError(t, err)
@@ -476,14 +448,13 @@ func TestDecrypt(t *testing.T) {
 	t.Run("Test unpacking python envelope with invalid recipient", func(t *testing.T) {
 		env := `{"protected": "eyJlbmMiOiAieGNoYWNoYTIwcG9seTEzMDVfaWV0ZiIsICJ0eXAiOiAiSldNLzEuMCIsICJhbGciOiAiQXV0aGNyeXB0IiwgInJlY2lwaWVudHMiOiBbeyJlbmNyeXB0ZWRfa2V5IjogIjdzN0ZTRXR6Sy1vTzdSWmdISklsSTlzX1lVU2xkMUpnRldPeUNhYUdGY1Y0aHBSTWxQbG0wNDBFcUJXRWVwY3oiLCAiaGVhZGVyIjogeyJraWQiOiAiN0RLbk56TWJHRWNYODYxOGp2WWtiNlhQTFR6eXU2YnhSbTh3RnhZb0d3SHEiLCAic2VuZGVyIjogInFLYTRDeXV1OXZOcmJzX1RCLXhQWXI2aFg2cXJZLTM4Vjd4VXdOQjFyd0J1TjVNTUVJYmRERDFvRElhV2o0QUpSYUZDTEVhSzMtakFSZHBsR1UtM2d4TWY2dkpRZWhiZkZhZHNwemdxRE9iWFZDWUJONGxrVXZLZWhvND0iLCAiaXYiOiAiSWFqeVdudFdSMENxS1BYUWJpWWptbWJRWFNNTEp2X1UifX0sIHsiZW5jcnlwdGVkX2tleSI6ICJZa05vVGh2ZUlIcC13NGlrRW1kQU51VHdxTEx1ZjBocVlVbXRJc2c5WlJMd1BKaUZHWVZuTXl1ZktKZWRvcmthIiwgImhlYWRlciI6IHsia2lkIjogIjdDRURlZUpZTnlRUzhyQjdNVHpvUHhWYXFIWm9ZZkQxNUVIVzhaVVN3VnVhIiwgInNlbmRlciI6ICJ3ZEhjc1hDemdTSjhucDRFU0pDcmJ5OWNrNjJaUEFFVjhJRjYwQmotaUhhbXJLRnBKOTJpZVNTaE1JcTdwdTNmQWZQLWo5S3J6ajAwMEV0SXB5cm05SmNrM0QwSnRBcmtYV2VsSzBoUF9ZeDR4Vlc5dW43MWlfdFBXNWM9IiwgIml2IjogIkRlbUlJbHRKaXd5TU1faGhIS29kcTZpQkx4Q1J5Z2Z3In19XX0=", "iv": "BKWHs6z0UHxGddwg", "ciphertext": "YC2eQQPYVjPHj3wIxUXxBj0yXFLuRN5Lc-9WM8hY6TXoekh-ca9-UWbHasikbcxyukTT3e-QiteOilG-6X7e9x4wiQmWn_NFLOLrqoFe669JIbkgvjHYwuQEQkIVfbD-2woSxsMUl9yln5RS-NssI5cEIVH_C1w=", "tag": "M8GPexbguDoZk5L51AvLjA=="}` // nolint: lll
 
-		recPub := "A3KnccxQu27yWQrSLwA2YFbfoSs4CHo3q6LjvhmpKz9h"
 		recPriv := "49Y63zwonNoj2jEhMYE22TDwQCn7RLKMqNeSkSoBBucbAWceJuXXNCACXfpbXD7PHKM13SWaySyDukEakPVn5sWs"
 
-		recKMS, store := newKMS(t)
-		err := persistKey(recPub, recPriv, store)
+		recKMS := newKMS(t)
+		err := persistKey(recPriv, recKMS)
 		require.NoError(t, err)
 
-		recPacker := newWithKMS(recKMS)
+		recPacker := newWithKMS(t, recKMS)
 
 		_, err = recPacker.Unpack([]byte(env))
 		require.NotNil(t, err)
@@ -499,15 +470,15 @@ func unpackComponentFailureTest(
 	errString string) {
 	fullMessage := `{"protected": "` + base64.URLEncoding.EncodeToString([]byte(protectedHeader)) + "\", " + msg
 
-	w, s := newKMS(t)
+	w := newKMS(t)
 
-	err := persistKey(base58.Encode(recKey.Pub), base58.Encode(recKey.Priv), s)
+	err := persistKey(base58.Encode(recKey.Priv), w)
 	if err != nil {
 		require.Contains(t, err.Error(), errString)
 		return
 	}
 
-	recPacker := newWithKMS(w)
+	recPacker := newWithKMS(t, w)
 	_, err = recPacker.Unpack([]byte(fullMessage))
 	require.NotNil(t, err)
 	require.Contains(t, err.Error(), errString)
@@ -520,11 +491,11 @@ func TestUnpackComponents(t *testing.T) {
 	t.Run("Fail: non-JSON envelope", func(t *testing.T) {
 		msg := `ed": "eyJlbmMiOiAieGNoYWNoYTIwcG9seTEzMDVfaWV0ZiIsICJ0eXAiOiAiSldNLzEu"}`
 
-		w, s := newKMS(t)
-		err := persistKey(base58.Encode(recKey.Pub), base58.Encode(recKey.Priv), s)
+		w := newKMS(t)
+		err := persistKey(base58.Encode(recKey.Priv), w)
 		require.NoError(t, err)
 
-		recPacker := newWithKMS(w)
+		recPacker := newWithKMS(t, w)
 
 		_, err = recPacker.Unpack([]byte(msg))
 		require.EqualError(t, err, "invalid character 'e' looking for beginning of value")
@@ -533,11 +504,11 @@ func TestUnpackComponents(t *testing.T) {
 	t.Run("Fail: non-base64 protected header", func(t *testing.T) {
 		msg := `{"protected": "&**^(&^%", "iv": "oDZpVO648Po3UcoW", "ciphertext": "pLrFQ6dND0aB4saHjSklcNTDAvpFPmIvebCis7S6UupzhhPOHwhp6o97_EphsWbwqqHl0HTiT7W9kUqrvd8jcWgx5EATtkx5o3PSyHfsfm9jl0tmKsqu6VG0RML_OokZiFv76ZUZuGMrHKxkCHGytILhlpSwajg=", "tag": "6GigdWnW59aC9Y8jhy76rA=="}` // nolint: lll
 
-		w, s := newKMS(t)
-		err := persistKey(base58.Encode(recKey.Pub), base58.Encode(recKey.Priv), s)
+		w := newKMS(t)
+		err := persistKey(base58.Encode(recKey.Priv), w)
 		require.NoError(t, err)
 
-		recPacker := newWithKMS(w)
+		recPacker := newWithKMS(t, w)
 
 		_, err = recPacker.Unpack([]byte(msg))
 		require.EqualError(t, err, "illegal base64 data at input byte 0")
@@ -576,25 +547,25 @@ func TestUnpackComponents(t *testing.T) {
 	})
 
 	t.Run("Fail: invalid public key", func(t *testing.T) {
-		rec := getB58Key("6ZAQ7QpmR9EqhJdwx1jQsjq6nnpehwVqUbhVxiEiYEV7", // invalid key, won't convert
+		rec := getB58Key("6ZAQ7QpmR9EqhJdwx1jQsjq6nnpehwVqUbhVxiEiYEV7", // invalid key, not in the KMS
 			"5pG8rLcp9WqPXQLSyQetPiyTEnLuanjS2TGd7h4DqutY6gNbLD6pnvT3H8nC5K9vEjy1UJdTtwaejf1xqDyhCrzr")
 
 		unpackComponentFailureTest(t,
 			`{"enc": "xchacha20poly1305_ietf", "typ": "JWM/1.0", "alg": "Authcrypt", "recipients": [{"encrypted_key": "DaZGim_WCyntSdziFgnQanpQlR_tVHzHznGbW-yhTYDVgGuc5nr6J5svu7dQbBg3", "header": {"kid": "6ZAQ7QpmR9EqhJdwx1jQsjq6nnpehwVqUbhVxiEiYEV7", "sender": "wZ4cC42eDMeLApmJvJC4INbuKINzdZZECGHpWDgsrmBURPJN_bWOkUV3E6oORN4ILAf_xEuWefS4b_goRycCogkZvTyS1HgvBtx2YO1A2q-a7tp__08Ky4qtSiY=", "iv": "A818WMvddPrZ8mmYqp2iuu8gqoZZC2Hx"}}]}`, // nolint: lll
 			`"iv": "oDZpVO648Po3UcoW", "ciphertext": "pLrFQ6dND0aB4saHjSklcNTDAvpFPmIvebCis7S6UupzhhPOHwhp6o97_EphsWbwqqHl0HTiT7W9kUqrvd8jcWgx5EATtkx5o3PSyHfsfm9jl0tmKsqu6VG0RML_OokZiFv76ZUZuGMrHKxkCHGytILhlpSwajg=", "tag": "6GigdWnW59aC9Y8jhy76rA=="}`,                                                                                                                                                                                        // nolint: lll
 			rec,
-			"error converting public key")
+			"no key accessible")
 	})
 
 	t.Run("Fail: invalid public key", func(t *testing.T) {
-		rec := getB58Key("57N4aoQKaxUGNeEn3ETnTKgeD1L5Wm3U3Vb8qi3hupLn", // mismatched keypair, won't decrypt
+		rec := getB58Key("57N4aoQKaxUGNeEn3ETnTKgeD1L5Wm3U3Vb8qi3hupLn", // mismatched keypair, not in the KMS
 			"5pG8rLcp9WqPXQLSyQetPiyTEnLuanjS2TGd7h4DqutY6gNbLD6pnvT3H8nC5K9vEjy1UJdTtwaejf1xqDyhCrzr")
 
 		unpackComponentFailureTest(t,
 			`{"enc": "xchacha20poly1305_ietf", "typ": "JWM/1.0", "alg": "Authcrypt", "recipients": [{"encrypted_key": "DaZGim_WCyntSdziFgnQanpQlR_tVHzHznGbW-yhTYDVgGuc5nr6J5svu7dQbBg3", "header": {"kid": "57N4aoQKaxUGNeEn3ETnTKgeD1L5Wm3U3Vb8qi3hupLn", "sender": "wZ4cC42eDMeLApmJvJC4INbuKINzdZZECGHpWDgsrmBURPJN_bWOkUV3E6oORN4ILAf_xEuWefS4b_goRycCogkZvTyS1HgvBtx2YO1A2q-a7tp__08Ky4qtSiY=", "iv": "A818WMvddPrZ8mmYqp2iuu8gqoZZC2Hx"}}]}`, // nolint: lll
 			`"iv": "oDZpVO648Po3UcoW", "ciphertext": "pLrFQ6dND0aB4saHjSklcNTDAvpFPmIvebCis7S6UupzhhPOHwhp6o97_EphsWbwqqHl0HTiT7W9kUqrvd8jcWgx5EATtkx5o3PSyHfsfm9jl0tmKsqu6VG0RML_OokZiFv76ZUZuGMrHKxkCHGytILhlpSwajg=", "tag": "6GigdWnW59aC9Y8jhy76rA=="}`,                                                                                                                                                                                        // nolint: lll
 			rec,
-			"failed to unpack")
+			"no key accessible")
 	})
 
 	t.Run("Sender is invalid base64 data", func(t *testing.T) {
@@ -681,14 +652,13 @@ func TestUnpackComponents(t *testing.T) {
 			prot,
 			`"iv": "oDZpVO648Po3UcoW", "ciphertext": "pLrFQ6dND0aB4saHjSklcNTDAvpFPmIvebCis7S6UupzhhPOHwhp6o97_EphsWbwqqHl0HTiT7W9kUqrvd8jcWgx5EATtkx5o3PSyHfsfm9jl0tmKsqu6VG0RML_OokZiFv76ZUZuGMrHKxkCHGytILhlpSwajg=", "tag": "6GigdWnW59aC9Y8jhy76rA=="}`, // nolint: lll
 			&badKey,
-			"error converting public key")
+			"no key accessible")
 	})
 }
 
 func Test_getCEK(t *testing.T) {
-	k := mockkms.CloseableKMS{
-		FindVerKeyValue:  0,
-		EncryptionKeyErr: fmt.Errorf("mock error"),
+	k := mockkms.KeyManager{
+		GetKeyErr: fmt.Errorf("mock error"),
 	}
 
 	recs := []recipient{
@@ -700,8 +670,8 @@ func Test_getCEK(t *testing.T) {
 		},
 	}
 
-	_, err := getCEK(recs, &k)
-	require.EqualError(t, err, "mock error")
+	_, err := newWithKMS(t, &k).getCEK(recs)
+	require.EqualError(t, err, "no key accessible key not found")
 }
 
 func getB58Key(pub, priv string) *cryptoutil.KeyPair {
@@ -10,13 +10,16 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 
 	"github.com/btcsuite/btcutil/base58"
 	chacha "golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/nacl/box"
 	"golang.org/x/crypto/poly1305"
 
 	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 // Pack will encode the payload argument
@@ -105,10 +108,16 @@ func (p *Packer) buildEnvelope(nonce, payload, cek []byte, header *protected) ([
 func (p *Packer) buildRecipients(cek *[chacha.KeySize]byte, senderKey []byte, recPubKeys [][]byte) ([]recipient, error) { // nolint: lll
 	var encodedRecipients = make([]recipient, len(recPubKeys))
 
+	// the sender private key is the KMS key of senderKey
+	senderKH, err := p.kms.Get(kms.PublicKeyID(senderKey))
+	if err != nil {
+		return nil, fmt.Errorf("get sender key: %w", err)
+	}
+
 	for i, recKey := range recPubKeys {
-		recipient, err := p.buildRecipient(cek, senderKey, recKey)
-		if err != nil {
-			return nil, err
+		recipient, e := p.buildRecipient(cek, senderKH, senderKey, recKey)
+		if e != nil {
+			return nil, e
 		}
 
 		encodedRecipients[i] = *recipient
@@ -119,7 +128,7 @@ func (p *Packer) buildRecipients(cek *[chacha.KeySize]byte, senderKey []byte, re
 
 // buildRecipient encodes the necessary data for the recipient to decrypt the message
 // 	encrypting the CEK and sender Pub key
-func (p *Packer) buildRecipient(cek *[chacha.KeySize]byte, senderKey, recKey []byte) (*recipient, error) { // nolint: lll
+func (p *Packer) buildRecipient(cek *[chacha.KeySize]byte, senderKH interface{}, senderKey, recKey []byte) (*recipient, error) { // nolint: lll
 	var nonce [24]byte
 
 	_, err := p.randSource.Read(nonce[:])
@@ -127,29 +136,18 @@ func (p *Packer) buildRecipient(cek *[chacha.KeySize]byte, senderKey, recKey []b
 		return nil, err
 	}
 
-	// We need the private key to be converted and keypair to be persisted
-	senderEncKey, err := p.legacyKMS.ConvertToEncryptionKey(senderKey)
-	if err != nil {
-		return nil, err
-	}
-
 	recEncKey, err := cryptoutil.PublicEd25519toCurve25519(recKey)
 	if err != nil {
 		return nil, err
 	}
 
-	box, err := legacykms.NewCryptoBox(p.legacyKMS)
-	if err != nil {
-		return nil, err
-	}
-
-	encCEK, err := box.Easy(cek[:], nonce[:], recEncKey, senderEncKey)
+	encCEK, err := p.crypto.Easy(cek[:], nonce[:], recEncKey, senderKH)
 	if err != nil {
 		return nil, err
 	}
 
 	// assumption: senderKey is ed25519
-	encSender, err := box.Seal([]byte(base58.Encode(senderKey)), recEncKey, p.randSource)
+	encSender, err := seal([]byte(base58.Encode(senderKey)), recEncKey, p.randSource)
 	if err != nil {
 		return nil, err
 	}
@@ -163,3 +161,27 @@ func (p *Packer) buildRecipient(cek *[chacha.KeySize]byte, senderKey, recKey []b
 		},
 	}, nil
 }
+
+// seal seals a payload for the Curve25519 public key theirEncPub using the equivalent of libsodium box_seal.
+//
+// Generates an ephemeral keypair to use for the sender, and includes
+// the ephemeral sender public key in the message.
+func seal(payload, theirEncPub []byte, randSource io.Reader) ([]byte, error) {
+	// generate ephemeral curve25519 asymmetric keys
+	epk, esk, err := box.GenerateKey(randSource)
+	if err != nil {
+		return nil, err
+	}
+
+	var recPubBytes [cryptoutil.Curve25519KeySize]byte
+
+	copy(recPubBytes[:], theirEncPub)
+
+	nonce, err := cryptoutil.Nonce(epk[:], theirEncPub)
+	if err != nil {
+		return nil, err
+	}
+
+	// now seal the msg with the ephemeral key, nonce and recPub (which is recipient's publicKey)
+	return box.Seal(epk[:], payload, nonce, &recPubBytes, esk), nil
+}
@@ -16,7 +16,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 // Unpack will decode the envelope using the legacy format
@@ -50,7 +50,7 @@ func (p *Packer) Unpack(envelope []byte) (*transport.Envelope, error) {
 		return nil, fmt.Errorf("message format %s not supported", protectedData.Alg)
 	}
 
-	keys, err := getCEK(protectedData.Recipients, p.legacyKMS)
+	keys, err := p.getCEK(protectedData.Recipients)
 	if err != nil {
 		return nil, err
 	}
@@ -72,27 +72,15 @@ type keys struct {
 	myKey    []byte
 }
 
-func getCEK(recipients []recipient, km legacykms.KeyManager) (*keys, error) {
-	var candidateKeys []string
-
-	for _, candidate := range recipients {
-		candidateKeys = append(candidateKeys, candidate.Header.KID)
-	}
-
-	recKeyIdx, err := km.FindVerKey(candidateKeys)
+func (p *Packer) getCEK(recipients []recipient) (*keys, error) {
+	recip, recKH, err := findRecipient(recipients, p.kms)
 	if err != nil {
 		return nil, fmt.Errorf("no key accessible %w", err)
 	}
 
-	recip := recipients[recKeyIdx]
 	recKey := base58.Decode(recip.Header.KID)
 
-	recCurvePub, err := km.ConvertToEncryptionKey(recKey)
-	if err != nil {
-		return nil, err
-	}
-
-	senderPub, senderPubCurve, err := decodeSender(recip.Header.Sender, recCurvePub, km)
+	senderPub, senderPubCurve, err := p.decodeSender(recip.Header.Sender, recKH)
 	if err != nil {
 		return nil, err
 	}
@@ -107,12 +95,7 @@ func getCEK(recipients []recipient, km legacykms.KeyManager) (*keys, error) {
 		return nil, err
 	}
 
-	b, err := legacykms.NewCryptoBox(km)
-	if err != nil {
-		return nil, err
-	}
-
-	cekSlice, err := b.EasyOpen(encCEK, nonceSlice, senderPubCurve, recCurvePub)
+	cekSlice, err := p.crypto.EasyOpen(encCEK, nonceSlice, senderPubCurve, recKH)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt CEK: %s", err)
 	}
@@ -128,18 +111,26 @@ func getCEK(recipients []recipient, km legacykms.KeyManager) (*keys, error) {
 	}, nil
 }
 
-func decodeSender(b64Sender string, pk []byte, km legacykms.KeyManager) ([]byte, []byte, error) {
-	encSender, err := base64.URLEncoding.DecodeString(b64Sender)
-	if err != nil {
-		return nil, nil, err
+// findRecipient returns the first recipient whose key (the Ed25519 recipient pk in b58 encoding) is found in km,
+// along with the key handle of its key
+func findRecipient(recipients []recipient, km kms.KeyManager) (*recipient, interface{}, error) {
+	for i := range recipients {
+		// Currently chooses the first usable key, but could use different logic (eg, priorities)
+		if kh, err := km.Get(kms.PublicKeyID(base58.Decode(recipients[i].Header.KID))); err == nil {
+			return &recipients[i], kh, nil
+		}
 	}
 
-	b, err := legacykms.NewCryptoBox(km)
+	return nil, nil, cryptoutil.ErrKeyNotFound
+}
+
+func (p *Packer) decodeSender(b64Sender string, recKH interface{}) ([]byte, []byte, error) {
+	encSender, err := base64.URLEncoding.DecodeString(b64Sender)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	senderPub, err := b.SealOpen(encSender, pk)
+	senderPub, err := p.crypto.SealOpen(encSender, recKH)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package decorator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+)
+
+// EncryptContent encrypts the content read from r into w with the streaming AEAD key handle kh of c, eg a
+// kms.AES256GCMHKDF1MBType key, without holding the content in memory. The encrypted content is meant to be
+// referenced by the Links of the attachment Data. The attachment ID is authenticated so that the encrypted content
+// of another attachment is rejected, ByteCount is set to the size of the encrypted content.
+func (a *Attachment) EncryptContent(c crypto.Crypto, kh interface{}, r io.Reader, w io.Writer) error {
+	cw := &countingWriter{w: w}
+
+	ew, err := c.EncryptStream(cw, []byte(a.ID), kh)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt attachment contents : %w", err)
+	}
+
+	if _, err = io.Copy(ew, r); err != nil {
+		return fmt.Errorf("failed to encrypt attachment contents : %w", err)
+	}
+
+	if err = ew.Close(); err != nil {
+		return fmt.Errorf("failed to encrypt attachment contents : %w", err)
+	}
+
+	a.ByteCount = cw.n
+
+	return nil
+}
+
+// DecryptContent decrypts the encrypted content of the attachment read from r into w with the streaming AEAD key
+// handle kh of c. The content written to w before an error is returned must be discarded, it failed authentication.
+func (a *Attachment) DecryptContent(c crypto.Crypto, kh interface{}, r io.Reader, w io.Writer) error {
+	dr, err := c.DecryptStream(r, []byte(a.ID), kh)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt attachment contents : %w", err)
+	}
+
+	if _, err = io.Copy(w, dr); err != nil {
+		return fmt.Errorf("failed to decrypt attachment contents : %w", err)
+	}
+
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,63 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package decorator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/streamingaead"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+)
+
+func TestAttachment_EncryptDecryptContent(t *testing.T) {
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	kh, err := keyset.NewHandle(streamingaead.AES128GCMHKDF4KBKeyTemplate())
+	require.NoError(t, err)
+
+	content := bytes.Repeat([]byte("attachment content"), 1000)
+
+	attachment := &Attachment{ID: "attachment-1", MimeType: "application/octet-stream"}
+	encrypted := new(bytes.Buffer)
+
+	require.NoError(t, attachment.EncryptContent(c, kh, bytes.NewReader(content), encrypted))
+	require.Equal(t, int64(encrypted.Len()), attachment.ByteCount)
+	require.NotContains(t, encrypted.String(), "attachment content")
+
+	t.Run("decrypt content", func(t *testing.T) {
+		decrypted := new(bytes.Buffer)
+
+		require.NoError(t, attachment.DecryptContent(c, kh, bytes.NewReader(encrypted.Bytes()), decrypted))
+		require.Equal(t, content, decrypted.Bytes())
+	})
+
+	t.Run("content of another attachment - should fail", func(t *testing.T) {
+		other := &Attachment{ID: "attachment-2"}
+
+		err := other.DecryptContent(c, kh, bytes.NewReader(encrypted.Bytes()), new(bytes.Buffer))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decrypt attachment contents")
+	})
+
+	t.Run("crypto errors", func(t *testing.T) {
+		errStream := errors.New("stream error")
+		mc := &mockcrypto.Crypto{EncryptStreamErr: errStream, DecryptStreamErr: errStream}
+
+		err := attachment.EncryptContent(mc, kh, bytes.NewReader(content), new(bytes.Buffer))
+		require.True(t, errors.Is(err, errStream))
+
+		err = attachment.DecryptContent(mc, kh, bytes.NewReader(encrypted.Bytes()), new(bytes.Buffer))
+		require.True(t, errors.Is(err, errStream))
+	})
+}
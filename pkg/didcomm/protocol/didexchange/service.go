@@ -20,7 +20,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/route"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
@@ -62,7 +62,7 @@ type provider interface {
 	OutboundDispatcher() dispatcher.Outbound
 	StorageProvider() storage.Provider
 	TransientStorageProvider() storage.Provider
-	Signer() legacykms.Signer
+	Signer() kms.Signer
 	VDRIRegistry() vdriapi.Registry
 	Service(id string) (interface{}, error)
 }
@@ -85,7 +85,7 @@ type Service struct {
 
 type context struct {
 	outboundDispatcher dispatcher.Outbound
-	signer             legacykms.Signer
+	signer             kms.Signer
 	connectionStore    *connectionStore
 	vdriRegistry       vdriapi.Registry
 	routeSvc           route.ProtocolService
@@ -579,7 +579,7 @@ func TestService_Update(t *testing.T) {
 	s := &requested{}
 	data := make(map[string][]byte)
 	connRec := &connection.Record{ThreadID: "123", ConnectionID: "123456", State: s.Name(),
-		Namespace: findNamespace(RequestMsgType)}
+		Namespace: findNamespace(RequestMsgType), Created: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)}
 	bytes, err := json.Marshal(connRec)
 	require.NoError(t, err)
 
@@ -614,7 +614,7 @@ type mockStore struct {
 }
 
 // Put stores the key and the record
-func (m *mockStore) Put(k string, v []byte) error {
+func (m *mockStore) Put(k string, v []byte, tags ...storage.Tag) error {
 	return m.put(k, v)
 }
 
@@ -623,6 +623,15 @@ func (m *mockStore) Get(k string) ([]byte, error) {
 	return m.get(k)
 }
 
+// GetTags fetches the tags of the record based on key
+func (m *mockStore) GetTags(k string) ([]storage.Tag, error) {
+	if _, err := m.get(k); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 // Delete the record based on key
 func (m *mockStore) Delete(k string) error {
 	return m.delete(k)
@@ -633,6 +642,30 @@ func (m *mockStore) Iterator(start, limit string) storage.StoreIterator {
 	return nil
 }
 
+// Query returns storage iterator
+func (m *mockStore) Query(expression string) (storage.StoreIterator, error) {
+	return nil, nil
+}
+
+// Batch performs the operations one by one
+func (m *mockStore) Batch(operations []storage.Operation) error {
+	for _, op := range operations {
+		var err error
+
+		if op.Value == nil {
+			err = m.delete(op.Key)
+		} else {
+			err = m.put(op.Key, op.Value)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func randomString() string {
 	u := uuid.New()
 	return u.String()
@@ -0,0 +1,16 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didupdate
+
+import "github.com/hyperledger/aries-framework-go/pkg/doc/did"
+
+// Update DID update message, it sends the updated DID document of the sender to the other side of a connection.
+type Update struct {
+	Type   string   `json:"@type,omitempty"`
+	ID     string   `json:"@id,omitempty"`
+	DIDDoc *did.Doc `json:"did_doc,omitempty"`
+}
@@ -0,0 +1,205 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didupdate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/google/uuid"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	didstore "github.com/hyperledger/aries-framework-go/pkg/store/did"
+)
+
+var logger = log.New("aries-framework/didupdate/service")
+
+// constants for the DID update protocol
+const (
+	// DIDUpdate DID update protocol.
+	DIDUpdate = "didupdate"
+
+	// DIDUpdateSpec defines the DID update spec. The protocol is specific to the framework, refer docs/didupdate.md,
+	// its message types aren't in the didcomm.org namespace of the Aries RFCs.
+	DIDUpdateSpec = "https://github.com/hyperledger/aries-framework-go/didupdate/1.0/"
+
+	// UpdateMsgType defines the DID update message type.
+	UpdateMsgType = DIDUpdateSpec + "update"
+)
+
+// provider contains dependencies for the DID update protocol and is typically created by using aries.Context()
+type provider interface {
+	OutboundDispatcher() dispatcher.Outbound
+	StorageProvider() storage.Provider
+	TransientStorageProvider() storage.Provider
+	VDRIRegistry() vdri.Registry
+}
+
+// Service for the DID update protocol, it sends the DID document of an updated DID, eg after the rotation of its
+// keys, to the other side of the connections of the DID, which stores it.
+type Service struct {
+	outbound         dispatcher.Outbound
+	vdriRegistry     vdri.Registry
+	connectionLookup *connection.Lookup
+	didConnStore     *didstore.ConnectionStore
+}
+
+// New returns the DID update service.
+func New(prov provider) (*Service, error) {
+	connectionLookup, err := connection.NewLookup(prov)
+	if err != nil {
+		return nil, err
+	}
+
+	didConnStore, err := didstore.NewConnectionStore(prov)
+	if err != nil {
+		return nil, fmt.Errorf("open did connection store : %w", err)
+	}
+
+	return &Service{
+		outbound:         prov.OutboundDispatcher(),
+		vdriRegistry:     prov.VDRIRegistry(),
+		connectionLookup: connectionLookup,
+		didConnStore:     didConnStore,
+	}, nil
+}
+
+// HandleInbound stores the DID document of a DID update message, the document must be the one of the DID on the
+// other side of a connection and the message must be sent from a key of the document it replaces.
+func (s *Service) HandleInbound(msg service.DIDCommMsg, myDID, theirDID string) (string, error) {
+	if msg.Type() != UpdateMsgType {
+		return "", fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	update := &Update{}
+
+	err := msg.Decode(update)
+	if err != nil {
+		return "", fmt.Errorf("decode DID update : %w", err)
+	}
+
+	if update.DIDDoc == nil || update.DIDDoc.ID != theirDID {
+		return "", errors.New("DID update doesn't have the DID document of the sender")
+	}
+
+	_, err = s.connectionLookup.GetConnectionIDByDIDs(myDID, theirDID)
+	if err != nil {
+		return "", fmt.Errorf("connection lookup using DIDs : %w", err)
+	}
+
+	// the keys of the DID which were rotated out of its document can't update it
+	err = s.checkSender(msg, theirDID)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.vdriRegistry.Store(update.DIDDoc)
+	if err != nil {
+		return "", fmt.Errorf("store DID document : %w", err)
+	}
+
+	err = s.didConnStore.SaveDIDFromDoc(update.DIDDoc)
+	if err != nil {
+		return "", fmt.Errorf("save DID keys : %w", err)
+	}
+
+	logger.Debugf("updated DID document of %s", theirDID)
+
+	return msg.ID(), nil
+}
+
+// checkSender checks that msg was packed with a key of the current DID document of theirDID.
+func (s *Service) checkSender(msg service.DIDCommMsg, theirDID string) error {
+	senderVerKey, ok := msg.Metadata()[service.SenderVerKeyMetadata].(string)
+	if !ok || senderVerKey == "" {
+		return errors.New("DID update doesn't have the key of its sender")
+	}
+
+	doc, err := s.vdriRegistry.Resolve(theirDID)
+	if err != nil {
+		return fmt.Errorf("resolve DID document : %w", err)
+	}
+
+	for i := range doc.PublicKey {
+		if base58.Encode(doc.PublicKey[i].Value) == senderVerKey {
+			return nil
+		}
+	}
+
+	return errors.New("DID update isn't sent from a key of the DID document it replaces")
+}
+
+// HandleOutbound sends a DID update message.
+func (s *Service) HandleOutbound(msg service.DIDCommMsg, myDID, theirDID string) error {
+	if !s.Accept(msg.Type()) {
+		return fmt.Errorf("unsupported message type %s", msg.Type())
+	}
+
+	return s.outbound.SendToDID(msg, myDID, theirDID)
+}
+
+// Accept checks whether the service can handle the message type.
+func (s *Service) Accept(msgType string) bool {
+	return msgType == UpdateMsgType
+}
+
+// Name of the service
+func (s *Service) Name() string {
+	return DIDUpdate
+}
+
+// NotifyConnections sends the updated DID document doc to the other side of each completed connection of its DID.
+// The messages are packed with the sender key senderVerKey, the key known on the other side before the update.
+// The keys of doc are saved first so that the replies sent to its new keys are matched with the DID. All the
+// connections are notified even if sending to some of them fails, the first error being returned.
+func (s *Service) NotifyConnections(doc *did.Doc, senderVerKey string) error {
+	err := s.didConnStore.SaveDIDFromDoc(doc)
+	if err != nil {
+		return fmt.Errorf("save DID keys : %w", err)
+	}
+
+	records, err := s.connectionLookup.QueryConnectionRecordsByState(didexchange.StateIDCompleted)
+	if err != nil {
+		return fmt.Errorf("query connections : %w", err)
+	}
+
+	var firstErr error
+
+	for _, record := range records {
+		if record.MyDID != doc.ID {
+			continue
+		}
+
+		err = s.notifyConnection(record, doc, senderVerKey)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("notify connection %s : %w", record.ConnectionID, err)
+		}
+	}
+
+	return firstErr
+}
+
+func (s *Service) notifyConnection(record *connection.Record, doc *did.Doc, senderVerKey string) error {
+	dest, err := service.GetDestination(record.TheirDID, s.vdriRegistry)
+	if err != nil {
+		return fmt.Errorf("get destination : %w", err)
+	}
+
+	return s.outbound.Send(&Update{
+		ID:     uuid.New().String(),
+		Type:   UpdateMsgType,
+		DIDDoc: doc,
+	}, senderVerKey, dest)
+}
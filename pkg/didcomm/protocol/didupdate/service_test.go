@@ -0,0 +1,314 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package didupdate
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
+	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol"
+	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	didstore "github.com/hyperledger/aries-framework-go/pkg/store/did"
+)
+
+const (
+	myDID    = "did:peer:alice"
+	theirDID = "did:peer:bob"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("returns the service", func(t *testing.T) {
+		s, err := New(&protocol.MockProvider{})
+		require.NoError(t, err)
+		require.Equal(t, DIDUpdate, s.Name())
+		require.True(t, s.Accept(UpdateMsgType))
+		require.False(t, s.Accept("unknown"))
+	})
+
+	t.Run("fails if the stores can't be opened", func(t *testing.T) {
+		expected := errors.New("test")
+
+		_, err := New(&protocol.MockProvider{
+			StoreProvider: &mockstore.MockStoreProvider{ErrOpenStoreHandle: expected},
+		})
+		require.True(t, errors.Is(err, expected))
+	})
+}
+
+func TestService_HandleInbound(t *testing.T) {
+	t.Run("stores the DID document of the other side of a connection", func(t *testing.T) {
+		prov := newProvider(t)
+
+		s, err := New(prov)
+		require.NoError(t, err)
+
+		doc := newDIDDoc(theirDID)
+
+		id, err := s.HandleInbound(newUpdateMsg(t, doc), myDID, theirDID)
+		require.NoError(t, err)
+		require.Equal(t, "update-1", id)
+		require.Equal(t, doc, prov.CustomVDRI.(*mockvdri.MockVDRIRegistry).MemStore[theirDID])
+
+		didConnStore, err := didstore.NewConnectionStore(prov)
+		require.NoError(t, err)
+
+		storedDID, err := didConnStore.GetDID(base58.Encode(doc.PublicKey[0].Value))
+		require.NoError(t, err)
+		require.Equal(t, theirDID, storedDID)
+	})
+
+	t.Run("fails for the DID document of another DID", func(t *testing.T) {
+		prov := newProvider(t)
+
+		s, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = s.HandleInbound(newUpdateMsg(t, newDIDDoc("did:peer:mallory")), myDID, theirDID)
+		require.EqualError(t, err, "DID update doesn't have the DID document of the sender")
+
+		_, err = s.HandleInbound(newUpdateMsg(t, nil), myDID, theirDID)
+		require.EqualError(t, err, "DID update doesn't have the DID document of the sender")
+		require.Empty(t, prov.CustomVDRI.(*mockvdri.MockVDRIRegistry).MemStore)
+	})
+
+	t.Run("fails without connection", func(t *testing.T) {
+		prov := newProvider(t)
+
+		s, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = s.HandleInbound(newUpdateMsg(t, newDIDDoc("did:peer:carol")), myDID, "did:peer:carol")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection lookup using DIDs")
+		require.Empty(t, prov.CustomVDRI.(*mockvdri.MockVDRIRegistry).MemStore)
+	})
+
+	t.Run("fails if the sender key isn't in the DID document being replaced", func(t *testing.T) {
+		prov := newProvider(t)
+
+		s, err := New(prov)
+		require.NoError(t, err)
+
+		// eg a key rotated out of the document
+		msg := newUpdateMsg(t, newDIDDoc(theirDID))
+		msg.Metadata()[service.SenderVerKeyMetadata] = "oldVerKey"
+
+		_, err = s.HandleInbound(msg, myDID, theirDID)
+		require.EqualError(t, err, "DID update isn't sent from a key of the DID document it replaces")
+
+		// eg an anoncrypted message
+		delete(msg.Metadata(), service.SenderVerKeyMetadata)
+
+		_, err = s.HandleInbound(msg, myDID, theirDID)
+		require.EqualError(t, err, "DID update doesn't have the key of its sender")
+		require.Empty(t, prov.CustomVDRI.(*mockvdri.MockVDRIRegistry).MemStore)
+	})
+
+	t.Run("fails if the DID document being replaced can't be resolved", func(t *testing.T) {
+		prov := newProvider(t)
+		prov.CustomVDRI.(*mockvdri.MockVDRIRegistry).ResolveFunc = func(string, ...vdriapi.ResolveOpts) (*did.Doc, error) {
+			return nil, errors.New("resolve error")
+		}
+
+		s, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = s.HandleInbound(newUpdateMsg(t, newDIDDoc(theirDID)), myDID, theirDID)
+		require.EqualError(t, err, "resolve DID document : resolve error")
+		require.Empty(t, prov.CustomVDRI.(*mockvdri.MockVDRIRegistry).MemStore)
+	})
+
+	t.Run("fails if the DID document can't be stored", func(t *testing.T) {
+		prov := newProvider(t)
+		prov.CustomVDRI.(*mockvdri.MockVDRIRegistry).PutErr = errors.New("put error")
+
+		s, err := New(prov)
+		require.NoError(t, err)
+
+		_, err = s.HandleInbound(newUpdateMsg(t, newDIDDoc(theirDID)), myDID, theirDID)
+		require.EqualError(t, err, "store DID document : put error")
+	})
+
+	t.Run("fails for other message types", func(t *testing.T) {
+		s, err := New(newProvider(t))
+		require.NoError(t, err)
+
+		_, err = s.HandleInbound(service.NewDIDCommMsgMap(&Update{Type: "unknown"}), myDID, theirDID)
+		require.EqualError(t, err, "unsupported message type unknown")
+	})
+}
+
+func TestService_HandleOutbound(t *testing.T) {
+	prov := newProvider(t)
+
+	var sent bool
+
+	prov.CustomOutbound = &mockdispatcher.MockOutbound{
+		ValidateSendToDID: func(msg interface{}, my, their string) error {
+			sent = true
+
+			require.Equal(t, myDID, my)
+			require.Equal(t, theirDID, their)
+
+			return nil
+		},
+	}
+
+	s, err := New(prov)
+	require.NoError(t, err)
+
+	require.NoError(t, s.HandleOutbound(newUpdateMsg(t, newDIDDoc(myDID)), myDID, theirDID))
+	require.True(t, sent)
+
+	err = s.HandleOutbound(service.NewDIDCommMsgMap(&Update{Type: "unknown"}), myDID, theirDID)
+	require.EqualError(t, err, "unsupported message type unknown")
+}
+
+func TestService_NotifyConnections(t *testing.T) {
+	t.Run("sends the DID document to the connections of its DID", func(t *testing.T) {
+		prov := newProvider(t)
+		saveConnection(t, prov, "conn2", "did:peer:carol", "did:peer:dave")
+
+		var sent []*Update
+
+		prov.CustomOutbound = &mockdispatcher.MockOutbound{
+			ValidateSend: func(msg interface{}, senderVerKey string, dest *service.Destination) error {
+				require.Equal(t, "prevVerKey", senderVerKey)
+				require.Equal(t, "https://localhost:8090", dest.ServiceEndpoint)
+
+				sent = append(sent, msg.(*Update))
+
+				return nil
+			},
+		}
+
+		s, err := New(prov)
+		require.NoError(t, err)
+
+		doc := newDIDDoc(myDID)
+
+		require.NoError(t, s.NotifyConnections(doc, "prevVerKey"))
+		require.Len(t, sent, 1)
+		require.Equal(t, UpdateMsgType, sent[0].Type)
+		require.NotEmpty(t, sent[0].ID)
+		require.Equal(t, doc, sent[0].DIDDoc)
+
+		didConnStore, err := didstore.NewConnectionStore(prov)
+		require.NoError(t, err)
+
+		storedDID, err := didConnStore.GetDID(base58.Encode(doc.PublicKey[0].Value))
+		require.NoError(t, err)
+		require.Equal(t, myDID, storedDID)
+	})
+
+	t.Run("notifies all the connections when sending fails", func(t *testing.T) {
+		prov := newProvider(t)
+		saveConnection(t, prov, "conn2", myDID, "did:peer:carol")
+
+		var sent int
+
+		prov.CustomOutbound = &mockdispatcher.MockOutbound{
+			ValidateSend: func(msg interface{}, senderVerKey string, dest *service.Destination) error {
+				sent++
+
+				return errors.New("send error")
+			},
+		}
+
+		s, err := New(prov)
+		require.NoError(t, err)
+
+		err = s.NotifyConnections(newDIDDoc(myDID), "prevVerKey")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "send error")
+		require.Equal(t, 2, sent)
+	})
+
+	t.Run("fails if the destination can't be resolved", func(t *testing.T) {
+		prov := newProvider(t)
+		prov.CustomVDRI.(*mockvdri.MockVDRIRegistry).ResolveFunc = nil
+
+		s, err := New(prov)
+		require.NoError(t, err)
+
+		err = s.NotifyConnections(newDIDDoc(myDID), "prevVerKey")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get destination")
+	})
+}
+
+// newProvider returns a provider with a completed connection between myDID and theirDID.
+func newProvider(t *testing.T) *protocol.MockProvider {
+	t.Helper()
+
+	prov := &protocol.MockProvider{
+		StoreProvider:          mockstore.NewMockStoreProvider(),
+		TransientStoreProvider: mockstore.NewMockStoreProvider(),
+		CustomOutbound:         &mockdispatcher.MockOutbound{},
+		CustomVDRI: &mockvdri.MockVDRIRegistry{
+			ResolveFunc: func(didID string, _ ...vdriapi.ResolveOpts) (*did.Doc, error) {
+				return newDIDDoc(didID), nil
+			},
+		},
+	}
+
+	saveConnection(t, prov, "conn1", myDID, theirDID)
+
+	return prov
+}
+
+func saveConnection(t *testing.T, prov *protocol.MockProvider, connectionID, my, their string) {
+	t.Helper()
+
+	recorder, err := connection.NewRecorder(prov)
+	require.NoError(t, err)
+
+	require.NoError(t, recorder.SaveConnectionRecord(&connection.Record{
+		ConnectionID: connectionID,
+		State:        didexchange.StateIDCompleted,
+		MyDID:        my,
+		TheirDID:     their,
+	}))
+}
+
+func newDIDDoc(id string) *did.Doc {
+	doc := mockdiddoc.GetMockDIDDoc()
+	doc.ID = id
+
+	return doc
+}
+
+// newUpdateMsg returns a DID update message of doc sent from the key of the current DID document of theirDID.
+func newUpdateMsg(t *testing.T, doc *did.Doc) service.DIDCommMsgMap {
+	t.Helper()
+
+	payload, err := json.Marshal(&Update{
+		ID:     "update-1",
+		Type:   UpdateMsgType,
+		DIDDoc: doc,
+	})
+	require.NoError(t, err)
+
+	msg, err := service.ParseDIDCommMsgMap(payload)
+	require.NoError(t, err)
+
+	msg.Metadata()[service.SenderVerKeyMetadata] = base58.Encode(newDIDDoc(theirDID).PublicKey[0].Value)
+
+	return msg
+}
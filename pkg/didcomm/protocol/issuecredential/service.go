@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
 	storeverifiable "github.com/hyperledger/aries-framework-go/pkg/store/verifiable"
 )
 
@@ -137,6 +138,12 @@ type Service struct {
 	verifiable *storeverifiable.Store
 }
 
+// Migrations returns the schema migrations of the issuecredential store, refer migration.Run().
+// Version 1 is the schema of the protocol states and transitional payloads above.
+func Migrations() []migration.Migration {
+	return []migration.Migration{{Store: Name, Version: 1, Description: "baseline of the protocol state"}}
+}
+
 // New returns the issuecredential service
 func New(p Provider) (*Service, error) {
 	store, err := p.StorageProvider().OpenStore(Name)
@@ -419,7 +426,11 @@ func (s *Service) saveTransitionalPayload(id string, data transitionalPayload) e
 		return fmt.Errorf("marshal transitional payload: %w", err)
 	}
 
-	return s.store.Put(fmt.Sprintf(transitionalPayloadKey, id), src)
+	return s.store.Batch([]storage.Operation{{
+		Key:   fmt.Sprintf(transitionalPayloadKey, id),
+		Value: src,
+		TTL:   service.ThreadDataTTL,
+	}})
 }
 
 // canTriggerActionEvents checks if the incoming message can trigger an action event
@@ -18,6 +18,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/model"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
@@ -341,18 +342,15 @@ func TestHandleDIDEvent(t *testing.T) {
 		expected := errors.New("test")
 		const connID = "123"
 		provider := testProvider()
-		provider.TransientStoreProvider = &mockstore.MockStoreProvider{
-			Store: &mockstore.MockStore{
-				Store:  make(map[string][]byte),
-				ErrGet: expected,
-			},
-		}
+		transientStore := &mockstore.MockStore{Store: make(map[string][]byte)}
+		provider.TransientStoreProvider = &mockstore.MockStoreProvider{Store: transientStore}
 		r, err := connection.NewRecorder(provider)
 		require.NoError(t, err)
 		err = r.SaveConnectionRecord(&connection.Record{
 			ConnectionID: connID,
 		})
 		require.NoError(t, err)
+		transientStore.ErrGet = expected
 		s := newAutoService(t, provider)
 		err = s.handleDIDEvent(service.StateMsg{
 			ProtocolName: didexchange.DIDExchange,
@@ -439,7 +437,7 @@ func TestHandleDIDEvent(t *testing.T) {
 		connID := uuid.New().String()
 
 		provider := testProvider()
-		provider.InboundMsgHandler = func([]byte, string, string) error {
+		provider.InboundMsgHandler = func(*transport.Envelope) error {
 			return nil
 		}
 
@@ -1103,7 +1101,7 @@ type stubStore struct {
 	putFunc func(k string, v []byte) error
 }
 
-func (s *stubStore) Put(k string, v []byte) error {
+func (s *stubStore) Put(k string, v []byte, tags ...storage.Tag) error {
 	if s.putFunc != nil {
 		return s.putFunc(k, v)
 	}
@@ -1115,6 +1113,10 @@ func (s *stubStore) Get(k string) ([]byte, error) {
 	panic("implement me")
 }
 
+func (s *stubStore) GetTags(k string) ([]storage.Tag, error) {
+	panic("implement me")
+}
+
 func (s *stubStore) Iterator(start, limit string) storage.StoreIterator {
 	panic("implement me")
 }
@@ -1123,6 +1125,14 @@ func (s *stubStore) Delete(k string) error {
 	panic("implement me")
 }
 
+func (s *stubStore) Query(expression string) (storage.StoreIterator, error) {
+	panic("implement me")
+}
+
+func (s *stubStore) Batch(operations []storage.Operation) error {
+	panic("implement me")
+}
+
 type outboundMsgHandlerStub struct {
 	handleFunc func(service.DIDCommMsg, string, string) error
 }
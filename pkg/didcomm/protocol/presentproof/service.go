@@ -372,7 +372,11 @@ func (s *Service) saveTransitionalPayload(id string, data transitionalPayload) e
 		return fmt.Errorf("marshal transitional payload: %w", err)
 	}
 
-	return s.store.Put(fmt.Sprintf(transitionalPayloadKey, id), src)
+	return s.store.Batch([]storage.Operation{{
+		Key:   fmt.Sprintf(transitionalPayloadKey, id),
+		Value: src,
+		TTL:   service.ThreadDataTTL,
+	}})
 }
 
 // canTriggerActionEvents checks if the incoming message can trigger an action event
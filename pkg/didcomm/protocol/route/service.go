@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
@@ -21,7 +22,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/logutil"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
 )
@@ -89,7 +90,7 @@ type provider interface {
 	StorageProvider() storage.Provider
 	TransientStorageProvider() storage.Provider
 	RouterEndpoint() string
-	LegacyKMS() legacykms.KeyManager
+	KMS() kms.KeyManager
 	VDRIRegistry() vdri.Registry
 }
 
@@ -116,7 +117,7 @@ type Service struct {
 	connectionLookup         *connection.Lookup
 	outbound                 dispatcher.Outbound
 	endpoint                 string
-	kms                      legacykms.KeyManager
+	kms                      kms.KeyManager
 	vdRegistry               vdri.Registry
 	routeRegistrationMap     map[string]chan Grant
 	routeRegistrationMapLock sync.RWMutex
@@ -141,7 +142,7 @@ func New(prov provider) (*Service, error) {
 		routeStore:           store,
 		outbound:             prov.OutboundDispatcher(),
 		endpoint:             prov.RouterEndpoint(),
-		kms:                  prov.LegacyKMS(),
+		kms:                  prov.KMS(),
 		vdRegistry:           prov.VDRIRegistry(),
 		connectionLookup:     connectionLookup,
 		routeRegistrationMap: make(map[string]chan Grant),
@@ -309,8 +310,12 @@ func (s *Service) handleInboundRequest(c *callback) error {
 		c.options,
 		s.endpoint,
 		func() (string, error) {
-			_, key, er := s.kms.CreateKeySet()
-			return key, er
+			_, key, er := s.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
+			if er != nil {
+				return "", er
+			}
+
+			return base58.Encode(key), nil
 		},
 	)
 	if err != nil {
@@ -23,7 +23,7 @@ import (
 	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
 	mockdiddoc "github.com/hyperledger/aries-framework-go/pkg/mock/diddoc"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
@@ -95,7 +95,7 @@ func TestServiceHandleOutbound(t *testing.T) {
 		provider := &mockprovider.Provider{
 			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
 					require.Equal(t, myDID, MYDID)
@@ -154,7 +154,7 @@ func TestServiceRequestMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -172,7 +172,7 @@ func TestServiceRequestMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -192,7 +192,7 @@ func TestServiceRequestMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			ServiceEndpointValue:          endpoint,
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSend: func(msg interface{}, senderVerKey string, des *service.Destination) error {
@@ -228,8 +228,8 @@ func TestServiceRequestMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue: &mockkms.CloseableKMS{
-				CreateKeyErr: expected,
+			KMSValue: &mockkms.KeyManager{
+				CrAndExportPubKeyErr: expected,
 			},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
@@ -251,7 +251,7 @@ func TestEvents(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -280,7 +280,7 @@ func TestEvents(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
 					dispatched <- struct{}{}
@@ -316,7 +316,7 @@ func TestEvents(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
 					dispatched <- struct{}{}
@@ -351,7 +351,7 @@ func TestEvents(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -366,7 +366,7 @@ func TestEvents(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			ServiceEndpointValue:          "http://other.com",
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
@@ -406,7 +406,7 @@ func TestServiceGrantMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -421,7 +421,7 @@ func TestServiceGrantMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -438,7 +438,7 @@ func TestServiceUpdateKeyListMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -456,7 +456,7 @@ func TestServiceUpdateKeyListMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -476,7 +476,7 @@ func TestServiceUpdateKeyListMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSend: func(msg interface{}, senderVerKey string, des *service.Destination) error {
 					res, err := json.Marshal(msg)
@@ -519,7 +519,7 @@ func TestServiceKeylistUpdateResponseMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -538,7 +538,7 @@ func TestServiceKeylistUpdateResponseMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -556,7 +556,7 @@ func TestServiceForwardMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -574,7 +574,7 @@ func TestServiceForwardMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -592,7 +592,7 @@ func TestServiceForwardMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -619,7 +619,7 @@ func TestServiceForwardMsg(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateForward: func(msg interface{}, des *service.Destination) error {
 					require.Equal(t, content, msg)
@@ -661,7 +661,7 @@ func TestRegister(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
 					require.Equal(t, myDID, MYDID)
@@ -703,7 +703,7 @@ func TestRegister(t *testing.T) {
 				Store: &mockstore.MockStore{Store: s, ErrPut: errors.New("save error")},
 			},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
 					request, ok := msg.(*Request)
@@ -735,7 +735,7 @@ func TestRegister(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -754,7 +754,7 @@ func TestRegister(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          mockstore.NewMockStoreProvider(),
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
 					return fmt.Errorf("error send")
@@ -771,7 +771,7 @@ func TestRegister(t *testing.T) {
 			StorageProviderValue: &mockstore.MockStoreProvider{
 				Store: &mockstore.MockStore{ErrGet: fmt.Errorf("get error")}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
 					return fmt.Errorf("error send")
@@ -845,7 +845,7 @@ func TestKeylistUpdate(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
 					require.Equal(t, myDID, MYDID)
@@ -895,7 +895,7 @@ func TestKeylistUpdate(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue: &mockdispatcher.MockOutbound{
 				ValidateSendToDID: func(msg interface{}, myDID, theirDID string) error {
 					require.Equal(t, myDID, MYDID)
@@ -953,7 +953,7 @@ func TestKeylistUpdate(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -976,7 +976,7 @@ func TestKeylistUpdate(t *testing.T) {
 				Store: &mockstore.MockStore{Store: s, ErrGet: errors.New("get error")},
 			},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -994,7 +994,7 @@ func TestConfig(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -1015,7 +1015,7 @@ func TestConfig(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -1030,7 +1030,7 @@ func TestConfig(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -1047,7 +1047,7 @@ func TestConfig(t *testing.T) {
 		svc, err := New(&mockprovider.Provider{
 			StorageProviderValue:          &mockstore.MockStoreProvider{Store: &mockstore.MockStore{Store: s}},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
@@ -1067,7 +1067,7 @@ func TestConfig(t *testing.T) {
 				Store: &mockstore.MockStore{Store: s, ErrGet: errors.New("get error")},
 			},
 			TransientStorageProviderValue: mockstore.NewMockStoreProvider(),
-			LegacyKMSValue:                &mockkms.CloseableKMS{},
+			KMSValue:                      &mockkms.KeyManager{},
 			OutboundDispatcherValue:       &mockdispatcher.MockOutbound{}})
 		require.NoError(t, err)
 
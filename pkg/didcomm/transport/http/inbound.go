@@ -69,7 +69,7 @@ func processPOSTRequest(w http.ResponseWriter, r *http.Request, prov transport.P
 
 	messageHandler := prov.InboundMessageHandler()
 
-	err = messageHandler(unpackMsg.Message, unpackMsg.ToDID, unpackMsg.FromDID)
+	err = messageHandler(unpackMsg)
 	if err != nil {
 		// TODO https://github.com/hyperledger/aries-framework-go/issues/271 HTTP Response Codes based on errors
 		//  from service
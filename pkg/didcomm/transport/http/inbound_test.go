@@ -30,8 +30,8 @@ type mockProvider struct {
 }
 
 func (p *mockProvider) InboundMessageHandler() transport.InboundMessageHandler {
-	return func(message []byte, myDID, theirDID string) error {
-		logger.Debugf("message received is %s", message)
+	return func(envelope *commontransport.Envelope) error {
+		logger.Debugf("message received is %s", envelope.Message)
 		return nil
 	}
 }
@@ -29,8 +29,8 @@ type OutboundTransport interface {
 }
 
 // InboundMessageHandler handles the inbound requests. The transport will unpack the payload prior to the
-// message handle invocation.
-type InboundMessageHandler func(message []byte, myDID, theirDID string) error
+// message handle invocation, the envelope holds the message along with its sender and recipient.
+type InboundMessageHandler func(envelope *transport.Envelope) error
 
 // Provider contains dependencies for starting the inbound/outbound transports.
 // It is typically created by using aries.Context().
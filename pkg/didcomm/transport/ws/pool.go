@@ -107,7 +107,7 @@ func (d *connPool) listener(conn *websocket.Conn, outbound bool) {
 
 		messageHandler := d.msgHandler
 
-		err = messageHandler(unpackMsg.Message, unpackMsg.ToDID, unpackMsg.FromDID)
+		err = messageHandler(unpackMsg)
 		if err != nil {
 			logger.Errorf("incoming msg processing failed: %v", err)
 		}
@@ -48,7 +48,7 @@ func TestConnectionStore(t *testing.T) {
 		transportProvider := &mockTransportProvider{
 			packagerValue: mockPackager,
 			frameworkID:   uuid.New().String(),
-			executeInbound: func(message []byte, myDID, theirDID string) error {
+			executeInbound: func(envelope *commontransport.Envelope) error {
 				resp, outboundErr := outbound.Send([]byte(response),
 					prepareDestinationWithTransport("ws://doesnt-matter", "", []string{verKey}))
 				require.NoError(t, outboundErr)
@@ -101,9 +101,9 @@ func TestConnectionStore(t *testing.T) {
 		transportProvider := &mockTransportProvider{
 			packagerValue: &mockPackager{verKey: verKey},
 			frameworkID:   uuid.New().String(),
-			executeInbound: func(message []byte, myDID, theirDID string) error {
+			executeInbound: func(envelope *commontransport.Envelope) error {
 				// validate the echo server response with the outbound sent message
-				require.Equal(t, request, message)
+				require.Equal(t, request, envelope.Message)
 				done <- struct{}{}
 				return nil
 			},
@@ -33,10 +33,10 @@ type mockProvider struct {
 }
 
 func (p *mockProvider) InboundMessageHandler() transport.InboundMessageHandler {
-	return func(message []byte, myDID, theirDID string) error {
-		logger.Infof("message received is %s", string(message))
+	return func(envelope *commontransport.Envelope) error {
+		logger.Infof("message received is %s", string(envelope.Message))
 
-		if string(message) == "invalid-data" {
+		if string(envelope.Message) == "invalid-data" {
 			return errors.New("error")
 		}
 
@@ -154,7 +154,7 @@ func (m *mockPackager) UnpackMessage(encMessage []byte) (*commontransport.Envelo
 
 type mockTransportProvider struct {
 	packagerValue  commontransport.Packager
-	executeInbound func(message []byte, myDID, theirDID string) error
+	executeInbound func(envelope *commontransport.Envelope) error
 	frameworkID    string
 }
 
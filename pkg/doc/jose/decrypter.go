@@ -57,9 +57,10 @@ func (jd *JWEDecrypt) Decrypt(jwe *JSONWebEncryption) ([]byte, error) {
 		return nil, fmt.Errorf("jwedecrypt: jwe is missing alg header")
 	}
 
-	// TODO add support for Chacha content encryption, issue #1684
+	// the content is decrypted with the algorithm of the enc header, whatever the content encryption of the
+	// template used to create the recipient key
 	switch encAlg {
-	case string(A256GCM):
+	case string(A256GCM), string(XC20P), string(C20P):
 	default:
 		return nil, fmt.Errorf("jwedecrypt: encryption algorithm '%s' not supported", encAlg)
 	}
@@ -123,6 +124,8 @@ func convertMarshalledJWKToRecKey(marshalledJWK []byte) (*subtle.RecipientWrappe
 	case *ecdsa.PublicKey:
 		epk.X = key.X.Bytes()
 		epk.Y = key.Y.Bytes()
+	case []byte:
+		epk.X = key
 	default:
 		return nil, fmt.Errorf("unsupported recipient key type")
 	}
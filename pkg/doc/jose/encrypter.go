@@ -30,6 +30,10 @@ type EncAlg string
 const (
 	// A256GCM for AES256GCM content encryption
 	A256GCM = EncAlg(subtle.A256GCM)
+	// XC20P for XChacha20Poly1305 content encryption, as used by the jwe/authcrypt packer
+	XC20P = EncAlg(subtle.XC20P)
+	// C20P for Chacha20Poly1305 content encryption
+	C20P = EncAlg(subtle.C20P)
 )
 
 // Encrypter interface to Encrypt/Decrypt JWE messages
@@ -48,7 +52,8 @@ type JWEEncrypt struct {
 	encAlg       EncAlg
 }
 
-// NewJWEEncrypt creates a new JWEEncrypt instance to build JWE with recipientsPubKeys
+// NewJWEEncrypt creates a new JWEEncrypt instance to build JWE with recipientsPubKeys and the content encryption
+// encAlg: A256GCM, XC20P or C20P
 func NewJWEEncrypt(encAlg EncAlg, recipientsPubKeys []subtle.ECPublicKey) (*JWEEncrypt, error) {
 	if len(recipientsPubKeys) == 0 {
 		return nil, fmt.Errorf("empty recipientsPubKeys list")
@@ -59,17 +64,21 @@ func NewJWEEncrypt(encAlg EncAlg, recipientsPubKeys []subtle.ECPublicKey) (*JWEE
 		err error
 	)
 
-	// TODO add support for Chacha content encryption, issue #1684
 	switch encAlg {
 	case A256GCM:
 		kt, err = ecdhes.ECDHES256KWAES256GCMKeyTemplateWithRecipients(recipientsPubKeys)
-		if err != nil {
-			return nil, err
-		}
+	case XC20P:
+		kt, err = ecdhes.ECDHES256KWXChaCha20Poly1305KeyTemplateWithRecipients(recipientsPubKeys)
+	case C20P:
+		kt, err = ecdhes.ECDHES256KWChaCha20Poly1305KeyTemplateWithRecipients(recipientsPubKeys)
 	default:
 		return nil, fmt.Errorf("encryption algorithm '%s' not supported", encAlg)
 	}
 
+	if err != nil {
+		return nil, err
+	}
+
 	senderKH, err := keyset.NewHandle(kt)
 	if err != nil {
 		return nil, err
@@ -155,6 +164,19 @@ func (je *JWEEncrypt) Encrypt(plaintext, aad []byte) (*JSONWebEncryption, error)
 }
 
 func convertRecKeyToMarshalledJWK(rec *subtle.RecipientWrappedKey) ([]byte, error) {
+	if rec.EPK.Curve == subtle.X25519Curve {
+		recJWK := JWK{
+			JSONWebKey: jose.JSONWebKey{
+				Use: HeaderEncryption,
+				Key: rec.EPK.X,
+			},
+			Kty: x25519Kty,
+			Crv: x25519Crv,
+		}
+
+		return recJWK.MarshalJSON()
+	}
+
 	var c elliptic.Curve
 
 	c, err := hybrid.GetCurve(rec.EPK.Curve)
@@ -171,7 +193,7 @@ func convertRecKeyToMarshalledJWK(rec *subtle.RecipientWrappedKey) ([]byte, erro
 				Y:     new(big.Int).SetBytes(rec.EPK.Y),
 			},
 		},
-		Kty: "EC", // TODO add support for X25519 content encryption, issue #1684
+		Kty: "EC",
 		Crv: rec.EPK.Curve,
 	}
 
@@ -18,6 +18,7 @@ import (
 
 	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/keyset"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/google/tink/go/subtle"
 	"github.com/square/go-jose/v3"
 	"github.com/stretchr/testify/require"
@@ -32,7 +33,7 @@ func TestJWEEncryptRoundTrip(t *testing.T) {
 	require.EqualError(t, err, "empty recipientsPubKeys list",
 		"NewJWEEncrypt should fail with empty recipientPubKeys")
 
-	recECKeys, recKHs := createRecipients(t, 20)
+	recECKeys, recKHs := createRecipients(t, 20, ecdhes.ECDHES256KWAES256GCMKeyTemplate())
 
 	_, err = NewJWEEncrypt("", recECKeys)
 	require.EqualError(t, err, "encryption algorithm '' not supported",
@@ -162,8 +163,58 @@ func TestJWEEncryptRoundTrip(t *testing.T) {
 	}
 }
 
+func TestJWEEncryptDecryptChaCha(t *testing.T) {
+	tests := []struct {
+		name   string
+		encAlg EncAlg
+		recKT  *tinkpb.KeyTemplate
+	}{
+		{name: "XC20P", encAlg: XC20P, recKT: ecdhes.ECDHES256KWXChaCha20Poly1305KeyTemplate()},
+		{name: "C20P", encAlg: C20P, recKT: ecdhes.ECDHES256KWChaCha20Poly1305KeyTemplate()},
+		{name: "XC20P with X25519 keys", encAlg: XC20P, recKT: ecdhes.ECDHESX25519KWXChaCha20Poly1305KeyTemplate()},
+		{name: "XC20P with A256GCM keys", encAlg: XC20P, recKT: ecdhes.ECDHES256KWAES256GCMKeyTemplate()},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			recECKeys, recKHs := createRecipients(t, 3, tc.recKT)
+
+			jweEncrypter, err := NewJWEEncrypt(tc.encAlg, recECKeys)
+			require.NoError(t, err)
+
+			pt := []byte("some msg")
+			jwe, err := jweEncrypter.Encrypt(pt, []byte("aad value"))
+			require.NoError(t, err)
+
+			serializedJWE, err := jwe.Serialize(json.Marshal)
+			require.NoError(t, err)
+
+			localJWE, err := Deserialize(serializedJWE)
+			require.NoError(t, err)
+
+			encAlg, ok := localJWE.ProtectedHeaders.Encryption()
+			require.True(t, ok)
+			require.Equal(t, string(tc.encAlg), encAlg)
+
+			for _, recKH := range recKHs {
+				msg, err := NewJWEDecrypt(recKH).Decrypt(localJWE)
+				require.NoError(t, err)
+				require.EqualValues(t, pt, msg)
+			}
+
+			// a key which is not a recipient can't decrypt
+			_, otherRecKHs := createRecipients(t, 1, tc.recKT)
+
+			_, err = NewJWEDecrypt(otherRecKHs[0]).Decrypt(localJWE)
+			require.EqualError(t, err, "ecdhes_factory: decryption failed")
+		})
+	}
+}
+
 func TestInteropWithGoJoseEncryptAndLocalJoseDecrypt(t *testing.T) {
-	recECKeys, recKHs := createRecipients(t, 3)
+	recECKeys, recKHs := createRecipients(t, 3, ecdhes.ECDHES256KWAES256GCMKeyTemplate())
 	gjRecipients := convertToGoJoseRecipients(t, recECKeys)
 
 	gjEncrypter, err := jose.NewMultiEncrypter(jose.A256GCM, gjRecipients, nil)
@@ -200,7 +251,7 @@ func TestInteropWithGoJoseEncryptAndLocalJoseDecrypt(t *testing.T) {
 
 func TestInteropWithLocalJoseEncryptAndGoJoseDecrypt(t *testing.T) {
 	// get two generated recipient Tink keys
-	recECKeys, _ := createRecipients(t, 2)
+	recECKeys, _ := createRecipients(t, 2, ecdhes.ECDHES256KWAES256GCMKeyTemplate())
 	// create a normal recipient key (not using Tink)
 	rec3PrivKey, err := ecdsa.GenerateKey(subtle.GetCurve(recECKeys[0].Curve), rand.Reader)
 	require.NoError(t, err)
@@ -260,7 +311,8 @@ func convertToGoJoseRecipients(t *testing.T, keys []ecdhessubtle.ECPublicKey) []
 }
 
 // createRecipients and return their public key and keyset.Handle
-func createRecipients(t *testing.T, numberOfRecipients int) ([]ecdhessubtle.ECPublicKey, []*keyset.Handle) {
+func createRecipients(t *testing.T, numberOfRecipients int,
+	kt *tinkpb.KeyTemplate) ([]ecdhessubtle.ECPublicKey, []*keyset.Handle) {
 	t.Helper()
 
 	var (
@@ -269,7 +321,7 @@ func createRecipients(t *testing.T, numberOfRecipients int) ([]ecdhessubtle.ECPu
 	)
 
 	for i := 0; i < numberOfRecipients; i++ {
-		mrKey, kh := createAndMarshalRecipient(t)
+		mrKey, kh := createAndMarshalRecipient(t, kt)
 		ecPubKey := new(ecdhessubtle.ECPublicKey)
 		err := json.Unmarshal(mrKey, ecPubKey)
 		require.NoError(t, err)
@@ -283,10 +335,10 @@ func createRecipients(t *testing.T, numberOfRecipients int) ([]ecdhessubtle.ECPu
 
 // createAndMarshalRecipient creates a new recipient keyset.Handle, extracts public key, marshals it and returns
 // both marshalled public key and original recipient keyset.Handle
-func createAndMarshalRecipient(t *testing.T) ([]byte, *keyset.Handle) {
+func createAndMarshalRecipient(t *testing.T, kt *tinkpb.KeyTemplate) ([]byte, *keyset.Handle) {
 	t.Helper()
 
-	kh, err := keyset.NewHandle(ecdhes.ECDHES256KWAES256GCMKeyTemplate())
+	kh, err := keyset.NewHandle(kt)
 	require.NoError(t, err)
 
 	pubKH, err := kh.Public()
@@ -29,9 +29,13 @@ const (
 	secp256k1Kty  = "EC"
 	secp256k1Size = 32
 	bitsPerByte   = 8
+	x25519Kty     = "OKP"
+	x25519Crv     = "X25519"
+	x25519Size    = 32
 )
 
-// JWK (JSON Web Key) is a JSON data structure that represents a cryptographic key.
+// JWK (JSON Web Key) is a JSON data structure that represents a cryptographic key. The Key of X25519 public keys is
+// their raw 32 bytes value.
 type JWK struct {
 	jose.JSONWebKey
 
@@ -58,6 +62,15 @@ func (j *JWK) PublicKeyBytes() ([]byte, error) {
 		return pubKey.SerializeCompressed(), nil
 	}
 
+	if isX25519(j.Kty, j.Crv) {
+		pubKey, ok := j.Key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unsupported public key type in kid '%s'", j.KeyID)
+		}
+
+		return pubKey, nil
+	}
+
 	switch pubKey := j.Public().Key.(type) {
 	case ed25519.PublicKey:
 		return pubKey, nil
@@ -79,14 +92,22 @@ func (j *JWK) UnmarshalJSON(jwkBytes []byte) error {
 		return fmt.Errorf("unable to read JWK: %w", marshalErr)
 	}
 
-	if isSecp256k1(key.Alg, key.Kty, key.Crv) {
+	switch {
+	case isSecp256k1(key.Alg, key.Kty, key.Crv):
 		jwk, err := unmarshalSecp256k1(&key)
 		if err != nil {
 			return fmt.Errorf("unable to read JWK: %w", err)
 		}
 
 		*j = *jwk
-	} else {
+	case isX25519(key.Kty, key.Crv):
+		jwk, err := unmarshalX25519(&key)
+		if err != nil {
+			return fmt.Errorf("unable to read JWK: %w", err)
+		}
+
+		*j = *jwk
+	default:
 		var joseJWK jose.JSONWebKey
 
 		err := json.Unmarshal(jwkBytes, &joseJWK)
@@ -109,6 +130,10 @@ func (j *JWK) MarshalJSON() ([]byte, error) {
 		return marshalSecp256k1(j)
 	}
 
+	if isX25519(j.Kty, j.Crv) {
+		return marshalX25519(j)
+	}
+
 	return (&j.JSONWebKey).MarshalJSON()
 }
 
@@ -202,6 +227,42 @@ func marshalSecp256k1(jwk *JWK) ([]byte, error) {
 	return json.Marshal(raw)
 }
 
+func isX25519(kty, crv string) bool {
+	return strings.EqualFold(kty, x25519Kty) && strings.EqualFold(crv, x25519Crv)
+}
+
+func unmarshalX25519(jwk *jsonWebKey) (*JWK, error) {
+	if jwk.X == nil || len(jwk.X.data) != x25519Size {
+		return nil, ErrInvalidKey
+	}
+
+	if jwk.D != nil {
+		return nil, errors.New("X25519 private keys are not supported")
+	}
+
+	return &JWK{
+		JSONWebKey: jose.JSONWebKey{
+			Key: jwk.X.data, KeyID: jwk.Kid, Algorithm: jwk.Alg, Use: jwk.Use,
+		},
+	}, nil
+}
+
+func marshalX25519(jwk *JWK) ([]byte, error) {
+	pubKey, ok := jwk.Key.([]byte)
+	if !ok || len(pubKey) != x25519Size {
+		return nil, ErrInvalidKey
+	}
+
+	return json.Marshal(jsonWebKey{
+		Kty: x25519Kty,
+		Crv: x25519Crv,
+		X:   newFixedSizeBuffer(pubKey, x25519Size),
+		Kid: jwk.KeyID,
+		Alg: jwk.Algorithm,
+		Use: jwk.Use,
+	})
+}
+
 // JWK gets JWK from JOSE headers.
 func (h Headers) JWK() (*JWK, bool) {
 	jwkRaw, ok := h[HeaderJSONWebKey]
@@ -264,6 +325,10 @@ type byteBuffer struct {
 	data []byte
 }
 
+func (b *byteBuffer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b.data))
+}
+
 func (b *byteBuffer) UnmarshalJSON(data []byte) error {
 	var encoded string
 
@@ -76,6 +76,16 @@ func TestDecodePublicKey(t *testing.T) {
 							"alg": "EdDSA"
 						}`,
 			},
+			{
+				name: "get public key bytes X25519 JWK",
+				jwkJSON: `{
+							"kty": "OKP",
+							"use": "enc",
+							"crv": "X25519",
+							"kid": "sample@sample.id",
+							"x": "sEHL6KXs8bUz9Ss2qSWWjhhRMHVjrog0lzFENM132R8"
+						}`,
+			},
 			{
 				name: "get public key bytes RSA JWK",
 				jwkJSON: `{
@@ -161,6 +171,16 @@ func TestDecodePublicKey(t *testing.T) {
 				jwkJSON: `}`,
 				err:     "invalid character",
 			},
+			{
+				name: "attempt public key bytes from invalid X25519 key",
+				jwkJSON: `{
+							"kty": "OKP",
+							"use": "enc",
+							"crv": "X25519",
+							"x": "sEHL6KXs8bUz9Ss2qSWWjhhRMHVjrog0lzFENM13"
+						}`,
+				err: "unable to read JWK: invalid JWK",
+			},
 			{
 				name: "attempt public key bytes from invalid curve",
 				jwkJSON: `{
@@ -293,6 +313,43 @@ func TestDecodePublicKey(t *testing.T) {
 	})
 }
 
+func TestJWK_X25519(t *testing.T) {
+	pubKey := []byte("abcdefghijklmnopqrstuvwxyz012345")
+
+	jwk := &JWK{
+		JSONWebKey: jose.JSONWebKey{
+			Key:   pubKey,
+			KeyID: "pubkey#123",
+		},
+		Kty: "OKP",
+		Crv: "X25519",
+	}
+
+	jwkBytes, err := json.Marshal(jwk)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"kty":"OKP","crv":"X25519","kid":"pubkey#123",`+
+		`"x":"YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXowMTIzNDU"}`, string(jwkBytes))
+
+	parsedJWK := &JWK{}
+	require.NoError(t, json.Unmarshal(jwkBytes, parsedJWK))
+
+	pkBytes, err := parsedJWK.PublicKeyBytes()
+	require.NoError(t, err)
+	require.Equal(t, pubKey, pkBytes)
+
+	jwk.Key = "key of invalid type"
+
+	_, err = json.Marshal(jwk)
+	require.Error(t, err)
+
+	_, err = jwk.PublicKeyBytes()
+	require.EqualError(t, err, "unsupported public key type in kid 'pubkey#123'")
+
+	err = json.Unmarshal([]byte(`{"kty":"OKP","crv":"X25519","x":"YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXowMTIzNDU",`+
+		`"d":"YWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXowMTIzNDU"}`), parsedJWK)
+	require.EqualError(t, err, "unable to read JWK: X25519 private keys are not supported")
+}
+
 func TestByteBufferUnmarshalFailure(t *testing.T) {
 	bb := &byteBuffer{}
 	err := bb.UnmarshalJSON([]byte("{"))
@@ -23,6 +23,8 @@ const (
 )
 
 // JSONWebSignature defines JSON Web Signature (https://tools.ietf.org/html/rfc7515)
+// The headers and the signature are the ones of the first signature, or of the first verified signature of a parsed
+// JWS JSON serialization which may have several signatures (see Signatures()).
 type JSONWebSignature struct {
 	ProtectedHeaders   Headers
 	UnprotectedHeaders Headers
@@ -30,6 +32,19 @@ type JSONWebSignature struct {
 
 	signature   []byte
 	joseHeaders Headers
+	signatures  []*JWSSignature
+}
+
+// JWSSignature is a signature of the payload of a JWS with its own protected and unprotected headers.
+type JWSSignature struct {
+	ProtectedHeaders   Headers
+	UnprotectedHeaders Headers
+	Signature          []byte
+	// VerificationError is the error of the verification of the signature when the JWS is parsed, nil if the
+	// signature is verified.
+	VerificationError error
+
+	encodedProtectedHeaders string
 }
 
 // SignatureVerifier makes verification of JSON Web Signature.
@@ -96,9 +111,18 @@ type Signer interface {
 	Headers() Headers
 }
 
-// NewJWS creates JSON Web Signature.
+// NewJWS creates JSON Web Signature. A JWS with an unencoded payload (b64 header set to false) has its b64 header
+// listed in the crit header (https://tools.ietf.org/html/rfc7797#section-6).
 func NewJWS(protectedHeaders, unprotectedHeaders Headers, payload []byte, signer Signer) (*JSONWebSignature, error) {
-	headers := mergeHeaders(protectedHeaders, signer.Headers())
+	headers, err := withCriticalB64(mergeHeaders(protectedHeaders, signer.Headers()))
+	if err != nil {
+		return nil, fmt.Errorf("sign JWS: %w", err)
+	}
+
+	if err = checkProtectedOnlyHeaders(unprotectedHeaders); err != nil {
+		return nil, fmt.Errorf("sign JWS: %w", err)
+	}
+
 	jws := &JSONWebSignature{
 		ProtectedHeaders:   headers,
 		UnprotectedHeaders: unprotectedHeaders,
@@ -106,17 +130,25 @@ func NewJWS(protectedHeaders, unprotectedHeaders Headers, payload []byte, signer
 		joseHeaders:        headers,
 	}
 
-	signature, err := sign(jws.joseHeaders, payload, signer)
+	encodedHeaders, signature, err := sign(jws.joseHeaders, payload, signer)
 	if err != nil {
 		return nil, fmt.Errorf("sign JWS: %w", err)
 	}
 
 	jws.signature = signature
+	jws.signatures = []*JWSSignature{{
+		ProtectedHeaders:        headers,
+		UnprotectedHeaders:      unprotectedHeaders,
+		Signature:               signature,
+		encodedProtectedHeaders: encodedHeaders,
+	}}
 
 	return jws, nil
 }
 
 // SerializeCompact makes JWS Compact Serialization (https://tools.ietf.org/html/rfc7515#section-7.1)
+// An unencoded payload (b64 header set to false) is put as is, it must not contain a period
+// (https://tools.ietf.org/html/rfc7797#section-5.2).
 func (s JSONWebSignature) SerializeCompact(detached bool) (string, error) {
 	byteHeaders, err := json.Marshal(s.joseHeaders)
 	if err != nil {
@@ -125,19 +157,40 @@ func (s JSONWebSignature) SerializeCompact(detached bool) (string, error) {
 
 	b64Headers := base64.RawURLEncoding.EncodeToString(byteHeaders)
 
-	b64Payload := ""
-	if !detached {
-		b64Payload = base64.RawURLEncoding.EncodeToString(s.Payload)
+	compactPayload, err := s.compactPayload(detached)
+	if err != nil {
+		return "", err
 	}
 
 	b64Signature := base64.RawURLEncoding.EncodeToString(s.signature)
 
 	return fmt.Sprintf("%s.%s.%s",
 		b64Headers,
-		b64Payload,
+		compactPayload,
 		b64Signature), nil
 }
 
+func (s JSONWebSignature) compactPayload(detached bool) (string, error) {
+	if detached {
+		return "", nil
+	}
+
+	b64, err := isB64Payload(s.joseHeaders)
+	if err != nil {
+		return "", err
+	}
+
+	if b64 {
+		return base64.RawURLEncoding.EncodeToString(s.Payload), nil
+	}
+
+	if strings.Contains(string(s.Payload), ".") {
+		return "", errors.New("unencoded JWS payload with a period must be detached in compact serialization")
+	}
+
+	return string(s.Payload), nil
+}
+
 // Signature returns a copy of JWS signature.
 func (s JSONWebSignature) Signature() []byte {
 	if s.signature == nil {
@@ -164,23 +217,29 @@ func mergeHeaders(h1, h2 Headers) Headers {
 	return h
 }
 
-func sign(joseHeaders Headers, payload []byte, signer Signer) ([]byte, error) { //nolint:interfacer
+// sign signs payload with signer, it returns the encoded joseHeaders of the signing input and the signature.
+func sign(joseHeaders Headers, payload []byte, signer Signer) (string, []byte, error) { //nolint:interfacer
 	err := checkJWSHeaders(joseHeaders)
 	if err != nil {
-		return nil, fmt.Errorf("check JOSE headers: %w", err)
+		return "", nil, fmt.Errorf("check JOSE headers: %w", err)
 	}
 
-	sigInput, err := signingInput(joseHeaders, payload)
+	encodedHeaders, err := encodeHeaders(joseHeaders)
 	if err != nil {
-		return nil, fmt.Errorf("prepare JWS verification data: %w", err)
+		return "", nil, fmt.Errorf("prepare JWS verification data: %w", err)
+	}
+
+	sigInput, err := encodedSigningInput(encodedHeaders, joseHeaders, payload)
+	if err != nil {
+		return "", nil, fmt.Errorf("prepare JWS verification data: %w", err)
 	}
 
 	signature, err := signer.Sign(sigInput)
 	if err != nil {
-		return nil, fmt.Errorf("sign JWS verification data: %w", err)
+		return "", nil, fmt.Errorf("sign JWS verification data: %w", err)
 	}
 
-	return signature, nil
+	return encodedHeaders, signature, nil
 }
 
 // jwsParseOpts holds options for the JWS Parsing.
@@ -198,7 +257,9 @@ func WithJWSDetachedPayload(payload []byte) JWSParseOpt {
 	}
 }
 
-// ParseJWS parses serialized JWS. Currently only JWS Compact Serialization parsing is supported.
+// ParseJWS parses serialized JWS in Compact Serialization or in general or flattened JSON Serialization, and verifies
+// its signatures with verifier. A JWS in JSON Serialization is parsed if at least one of its signatures is verified,
+// the verification of each signature is reported by Signatures().
 func ParseJWS(jws string, verifier SignatureVerifier, opts ...JWSParseOpt) (*JSONWebSignature, error) {
 	pOpts := &jwsParseOpts{}
 
@@ -207,9 +268,7 @@ func ParseJWS(jws string, verifier SignatureVerifier, opts ...JWSParseOpt) (*JSO
 	}
 
 	if strings.HasPrefix(jws, "{") {
-		// TODO support JWS JSON serialization format
-		//  https://github.com/hyperledger/aries-framework-go/issues/1331
-		return nil, errors.New("JWS JSON serialization is not supported")
+		return parseJSON(jws, verifier, pOpts)
 	}
 
 	return parseCompacted(jws, verifier, pOpts)
@@ -233,12 +292,12 @@ func parseCompacted(jwsCompact string, verifier SignatureVerifier, opts *jwsPars
 		return nil, err
 	}
 
-	payload, err := parseCompactedPayload(parts[jwsPayloadPart], opts)
+	payload, err := parseCompactedPayload(parts[jwsPayloadPart], joseHeaders, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	sInput, err := signingInput(joseHeaders, payload)
+	sInput, err := encodedSigningInput(parts[jwsHeaderPart], joseHeaders, payload)
 	if err != nil {
 		return nil, fmt.Errorf("build signing input: %w", err)
 	}
@@ -258,14 +317,28 @@ func parseCompacted(jwsCompact string, verifier SignatureVerifier, opts *jwsPars
 		Payload:          payload,
 		signature:        signature,
 		joseHeaders:      joseHeaders,
+		signatures: []*JWSSignature{{
+			ProtectedHeaders:        joseHeaders,
+			Signature:               signature,
+			encodedProtectedHeaders: parts[jwsHeaderPart],
+		}},
 	}, nil
 }
 
-func parseCompactedPayload(jwsPayload string, opts *jwsParseOpts) ([]byte, error) {
+func parseCompactedPayload(jwsPayload string, joseHeaders Headers, opts *jwsParseOpts) ([]byte, error) {
 	if len(opts.detachedPayload) > 0 {
 		return opts.detachedPayload, nil
 	}
 
+	b64, err := isB64Payload(joseHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	if !b64 {
+		return []byte(jwsPayload), nil
+	}
+
 	payload, err := base64.RawURLEncoding.DecodeString(jwsPayload)
 	if err != nil {
 		return nil, fmt.Errorf("decode base64 payload: %w", err)
@@ -295,22 +368,13 @@ func parseCompactedHeaders(parts []string) (Headers, error) {
 	return joseHeaders, nil
 }
 
-func signingInput(headers Headers, payload []byte) ([]byte, error) {
-	headersBytes, err := json.Marshal(headers)
+// encodedSigningInput builds the signing input of payload with the protected headers as they were encoded.
+func encodedSigningInput(encodedHeaders string, headers Headers, payload []byte) ([]byte, error) {
+	hBase64, err := isB64Payload(headers)
 	if err != nil {
-		return nil, fmt.Errorf("serialize JWS headers: %w", err)
-	}
-
-	hBase64 := true
-
-	if b64, ok := headers[HeaderB64Payload]; ok {
-		if hBase64, ok = b64.(bool); !ok {
-			return nil, errors.New("invalid b64 header")
-		}
+		return nil, err
 	}
 
-	headersStr := base64.RawURLEncoding.EncodeToString(headersBytes)
-
 	var payloadStr string
 
 	if hBase64 {
@@ -319,7 +383,31 @@ func signingInput(headers Headers, payload []byte) ([]byte, error) {
 		payloadStr = string(payload)
 	}
 
-	return []byte(fmt.Sprintf("%s.%s", headersStr, payloadStr)), nil
+	return []byte(fmt.Sprintf("%s.%s", encodedHeaders, payloadStr)), nil
+}
+
+func encodeHeaders(headers Headers) (string, error) {
+	headersBytes, err := json.Marshal(headers)
+	if err != nil {
+		return "", fmt.Errorf("serialize JWS headers: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(headersBytes), nil
+}
+
+// isB64Payload tells whether the payload is base64URL encoded in the signing input, as set by the b64 header.
+func isB64Payload(headers Headers) (bool, error) {
+	b64, ok := headers[HeaderB64Payload]
+	if !ok {
+		return true, nil
+	}
+
+	hBase64, ok := b64.(bool)
+	if !ok {
+		return false, errors.New("invalid b64 header")
+	}
+
+	return hBase64, nil
 }
 
 func checkJWSHeaders(headers Headers) error {
@@ -327,6 +415,107 @@ func checkJWSHeaders(headers Headers) error {
 		return fmt.Errorf("%s JWS header is not defined", HeaderAlgorithm)
 	}
 
+	return checkCriticalHeaders(headers)
+}
+
+// criticalJWSHeaders are the JWS header extensions understood by the JWS implementation.
+var criticalJWSHeaders = map[string]bool{ //nolint:gochecknoglobals
+	HeaderB64Payload: true,
+}
+
+// checkCriticalHeaders checks that the critical headers (https://tools.ietf.org/html/rfc7515#section-4.1.11) are
+// understood and defined, and that the b64 header is critical (https://tools.ietf.org/html/rfc7797#section-6).
+func checkCriticalHeaders(headers Headers) error {
+	crit, err := criticalHeaders(headers)
+	if err != nil {
+		return err
+	}
+
+	if crit != nil && len(crit) == 0 {
+		return fmt.Errorf("%s JWS header is empty", HeaderCritical)
+	}
+
+	isCritical := make(map[string]bool, len(crit))
+
+	for _, name := range crit {
+		if !criticalJWSHeaders[name] {
+			return fmt.Errorf("critical JWS header %s is not supported", name)
+		}
+
+		if _, ok := headers[name]; !ok {
+			return fmt.Errorf("critical JWS header %s is not defined", name)
+		}
+
+		isCritical[name] = true
+	}
+
+	if _, ok := headers[HeaderB64Payload]; ok && !isCritical[HeaderB64Payload] {
+		return fmt.Errorf("%s JWS header is not critical", HeaderB64Payload)
+	}
+
+	return nil
+}
+
+// criticalHeaders returns the names listed in the crit header, nil if there is no crit header.
+func criticalHeaders(headers Headers) ([]string, error) {
+	crit, ok := headers[HeaderCritical]
+	if !ok {
+		return nil, nil
+	}
+
+	switch names := crit.(type) {
+	case []string:
+		return append([]string{}, names...), nil
+	case []interface{}:
+		critNames := make([]string, len(names))
+
+		for i, name := range names {
+			critName, ok := name.(string)
+			if !ok {
+				return nil, errors.New("invalid crit header")
+			}
+
+			critNames[i] = critName
+		}
+
+		return critNames, nil
+	default:
+		return nil, errors.New("invalid crit header")
+	}
+}
+
+// withCriticalB64 lists the b64 header in the crit header if it is not yet listed, headers are updated.
+func withCriticalB64(headers Headers) (Headers, error) {
+	if _, ok := headers[HeaderB64Payload]; !ok {
+		return headers, nil
+	}
+
+	crit, err := criticalHeaders(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range crit {
+		if name == HeaderB64Payload {
+			return headers, nil
+		}
+	}
+
+	headers[HeaderCritical] = append(crit, HeaderB64Payload)
+
+	return headers, nil
+}
+
+// checkProtectedOnlyHeaders checks that the unprotected headers have neither the crit header
+// (https://tools.ietf.org/html/rfc7515#section-4.1.11) nor the b64 header
+// (https://tools.ietf.org/html/rfc7797#section-3).
+func checkProtectedOnlyHeaders(unprotectedHeaders Headers) error {
+	for _, name := range []string{HeaderCritical, HeaderB64Payload} {
+		if _, ok := unprotectedHeaders[name]; ok {
+			return fmt.Errorf("%s JWS header must be protected", name)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,351 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/square/go-jose/v3/json"
+)
+
+// jwsJSONSignature is a signature of the JWS JSON Serialization (https://tools.ietf.org/html/rfc7515#section-7.2.1)
+type jwsJSONSignature struct {
+	Protected string  `json:"protected,omitempty"`
+	Header    Headers `json:"header,omitempty"`
+	Signature string  `json:"signature"`
+}
+
+// generalJWSJSON is the general JWS JSON Serialization (https://tools.ietf.org/html/rfc7515#section-7.2.1)
+type generalJWSJSON struct {
+	Payload    *string            `json:"payload,omitempty"`
+	Signatures []jwsJSONSignature `json:"signatures"`
+}
+
+// flattenedJWSJSON is the flattened JWS JSON Serialization (https://tools.ietf.org/html/rfc7515#section-7.2.2)
+type flattenedJWSJSON struct {
+	Payload *string `json:"payload,omitempty"`
+	jwsJSONSignature
+}
+
+// rawJWSJSON holds the members of both the general and the flattened JWS JSON Serializations.
+type rawJWSJSON struct {
+	Payload    *string            `json:"payload,omitempty"`
+	Signatures []jwsJSONSignature `json:"signatures,omitempty"`
+	Protected  string             `json:"protected,omitempty"`
+	Header     Headers            `json:"header,omitempty"`
+	Signature  *string            `json:"signature,omitempty"`
+}
+
+// AddSignature co-signs the payload of the JWS with signer, the new signature has its own protectedHeaders (merged
+// with the signer headers) and unprotectedHeaders. A JWS with several signatures is serialized with
+// SerializeGeneralJSON.
+func (s *JSONWebSignature) AddSignature(protectedHeaders, unprotectedHeaders Headers, signer Signer) error {
+	headers, err := withCriticalB64(mergeHeaders(protectedHeaders, signer.Headers()))
+	if err != nil {
+		return fmt.Errorf("add JWS signature: %w", err)
+	}
+
+	if err = checkProtectedOnlyHeaders(unprotectedHeaders); err != nil {
+		return fmt.Errorf("add JWS signature: %w", err)
+	}
+
+	if err = checkDisjointHeaders(headers, unprotectedHeaders); err != nil {
+		return fmt.Errorf("add JWS signature: %w", err)
+	}
+
+	if err = checkSameB64Payload(s.signatures, headers); err != nil {
+		return fmt.Errorf("add JWS signature: %w", err)
+	}
+
+	encodedHeaders, signature, err := sign(headers, s.Payload, signer)
+	if err != nil {
+		return fmt.Errorf("add JWS signature: %w", err)
+	}
+
+	s.signatures = append(s.signatures, &JWSSignature{
+		ProtectedHeaders:        headers,
+		UnprotectedHeaders:      unprotectedHeaders,
+		Signature:               signature,
+		encodedProtectedHeaders: encodedHeaders,
+	})
+
+	return nil
+}
+
+// Signatures returns a copy of the signatures of the JWS, a parsed JWS reports the verification of each one of them.
+func (s JSONWebSignature) Signatures() []JWSSignature {
+	signatures := make([]JWSSignature, len(s.signatures))
+
+	for i, sig := range s.signatures {
+		signatures[i] = *sig
+		signatures[i].Signature = append([]byte(nil), sig.Signature...)
+	}
+
+	return signatures
+}
+
+// SerializeGeneralJSON makes JWS general JSON Serialization (https://tools.ietf.org/html/rfc7515#section-7.2.1)
+// with all the signatures of the JWS.
+func (s JSONWebSignature) SerializeGeneralJSON(detached bool) (string, error) {
+	payload, err := s.jsonPayload(detached)
+	if err != nil {
+		return "", err
+	}
+
+	jws := generalJWSJSON{Payload: payload}
+
+	for _, sig := range s.signatures {
+		jws.Signatures = append(jws.Signatures, sig.jsonSignature())
+	}
+
+	return marshalJWSJSON(jws)
+}
+
+// SerializeFlattenedJSON makes JWS flattened JSON Serialization (https://tools.ietf.org/html/rfc7515#section-7.2.2)
+// of a JWS with a single signature.
+func (s JSONWebSignature) SerializeFlattenedJSON(detached bool) (string, error) {
+	if len(s.signatures) != 1 {
+		return "", fmt.Errorf("flattened JWS JSON serialization requires a single signature, the JWS has %d",
+			len(s.signatures))
+	}
+
+	payload, err := s.jsonPayload(detached)
+	if err != nil {
+		return "", err
+	}
+
+	return marshalJWSJSON(flattenedJWSJSON{
+		Payload:          payload,
+		jwsJSONSignature: s.signatures[0].jsonSignature(),
+	})
+}
+
+func (s JSONWebSignature) jsonPayload(detached bool) (*string, error) {
+	if len(s.signatures) == 0 {
+		return nil, errors.New("JWS has no signature")
+	}
+
+	if detached {
+		return nil, nil
+	}
+
+	b64, err := isB64Payload(s.signatures[0].ProtectedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := string(s.Payload)
+	if b64 {
+		payload = base64.RawURLEncoding.EncodeToString(s.Payload)
+	}
+
+	return &payload, nil
+}
+
+func (sig *JWSSignature) jsonSignature() jwsJSONSignature {
+	return jwsJSONSignature{
+		Protected: sig.encodedProtectedHeaders,
+		Header:    sig.UnprotectedHeaders,
+		Signature: base64.RawURLEncoding.EncodeToString(sig.Signature),
+	}
+}
+
+func marshalJWSJSON(jws interface{}) (string, error) {
+	jwsBytes, err := json.Marshal(jws)
+	if err != nil {
+		return "", fmt.Errorf("marshal JWS JSON: %w", err)
+	}
+
+	return string(jwsBytes), nil
+}
+
+func parseJSON(jwsJSON string, verifier SignatureVerifier, opts *jwsParseOpts) (*JSONWebSignature, error) {
+	var raw rawJWSJSON
+
+	if err := json.Unmarshal([]byte(jwsJSON), &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal JWS JSON: %w", err)
+	}
+
+	jsonSignatures := raw.Signatures
+
+	switch {
+	case raw.Signature != nil && jsonSignatures != nil:
+		return nil, errors.New("invalid JWS JSON format: both flattened and general serializations")
+	case raw.Signature != nil:
+		jsonSignatures = []jwsJSONSignature{{Protected: raw.Protected, Header: raw.Header, Signature: *raw.Signature}}
+	case len(jsonSignatures) == 0:
+		return nil, errors.New("invalid JWS JSON format: no signatures")
+	}
+
+	jws := &JSONWebSignature{}
+
+	for i, jsonSig := range jsonSignatures {
+		sig, err := parseJSONSignature(jsonSig)
+		if err != nil {
+			return nil, fmt.Errorf("JWS signature %d: %w", i, err)
+		}
+
+		if err = checkSameB64Payload(jws.signatures, sig.ProtectedHeaders); err != nil {
+			return nil, err
+		}
+
+		jws.signatures = append(jws.signatures, sig)
+	}
+
+	payload, err := parseJSONPayload(raw.Payload, jws.signatures[0].ProtectedHeaders, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	jws.Payload = payload
+
+	if err = jws.verifySignatures(verifier); err != nil {
+		return nil, err
+	}
+
+	return jws, nil
+}
+
+func parseJSONSignature(jsonSig jwsJSONSignature) (*JWSSignature, error) {
+	protectedHeaders := Headers{}
+
+	if jsonSig.Protected != "" {
+		headersBytes, err := base64.RawURLEncoding.DecodeString(jsonSig.Protected)
+		if err != nil {
+			return nil, fmt.Errorf("decode base64 header: %w", err)
+		}
+
+		if err = json.Unmarshal(headersBytes, &protectedHeaders); err != nil {
+			return nil, fmt.Errorf("unmarshal JSON headers: %w", err)
+		}
+	}
+
+	if err := checkProtectedOnlyHeaders(jsonSig.Header); err != nil {
+		return nil, err
+	}
+
+	if err := checkDisjointHeaders(protectedHeaders, jsonSig.Header); err != nil {
+		return nil, err
+	}
+
+	if err := checkJWSHeaders(mergeHeaders(protectedHeaders, jsonSig.Header)); err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(jsonSig.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64 signature: %w", err)
+	}
+
+	return &JWSSignature{
+		ProtectedHeaders:        protectedHeaders,
+		UnprotectedHeaders:      jsonSig.Header,
+		Signature:               signature,
+		encodedProtectedHeaders: jsonSig.Protected,
+	}, nil
+}
+
+func parseJSONPayload(jsonPayload *string, protectedHeaders Headers, opts *jwsParseOpts) ([]byte, error) {
+	if len(opts.detachedPayload) > 0 {
+		return opts.detachedPayload, nil
+	}
+
+	if jsonPayload == nil {
+		return nil, errors.New("JWS payload is missing")
+	}
+
+	b64, err := isB64Payload(protectedHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	if !b64 {
+		return []byte(*jsonPayload), nil
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(*jsonPayload)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64 payload: %w", err)
+	}
+
+	return payload, nil
+}
+
+// verifySignatures verifies every signature of the JWS and reports its verification error, it fails if none of
+// them is verified. The headers and the signature of the JWS are the ones of the first verified signature.
+func (s *JSONWebSignature) verifySignatures(verifier SignatureVerifier) error {
+	var firstErr error
+
+	for _, sig := range s.signatures {
+		sInput, err := encodedSigningInput(sig.encodedProtectedHeaders, sig.ProtectedHeaders, s.Payload)
+		if err != nil {
+			return fmt.Errorf("build signing input: %w", err)
+		}
+
+		joseHeaders := mergeHeaders(sig.ProtectedHeaders, sig.UnprotectedHeaders)
+
+		sig.VerificationError = verifier.Verify(joseHeaders, s.Payload, sInput, sig.Signature)
+		if sig.VerificationError != nil {
+			if firstErr == nil {
+				firstErr = sig.VerificationError
+			}
+
+			continue
+		}
+
+		if s.signature == nil {
+			s.ProtectedHeaders = sig.ProtectedHeaders
+			s.UnprotectedHeaders = sig.UnprotectedHeaders
+			s.signature = sig.Signature
+			s.joseHeaders = sig.ProtectedHeaders
+		}
+	}
+
+	if s.signature == nil {
+		return fmt.Errorf("no JWS signature verified: %w", firstErr)
+	}
+
+	return nil
+}
+
+// checkDisjointHeaders checks that a header is not both protected and unprotected
+// (https://tools.ietf.org/html/rfc7515#section-7.2.1).
+func checkDisjointHeaders(protectedHeaders, unprotectedHeaders Headers) error {
+	for k := range unprotectedHeaders {
+		if _, ok := protectedHeaders[k]; ok {
+			return fmt.Errorf("%s JWS header is both protected and unprotected", k)
+		}
+	}
+
+	return nil
+}
+
+// checkSameB64Payload checks that the b64 header of protectedHeaders is the one of the other signatures, the payload
+// is represented the same way for all of them (https://tools.ietf.org/html/rfc7797#section-3).
+func checkSameB64Payload(signatures []*JWSSignature, protectedHeaders Headers) error {
+	if len(signatures) == 0 {
+		return nil
+	}
+
+	b64, err := isB64Payload(protectedHeaders)
+	if err != nil {
+		return err
+	}
+
+	otherB64, err := isB64Payload(signatures[0].ProtectedHeaders)
+	if err != nil {
+		return err
+	}
+
+	if b64 != otherB64 {
+		return errors.New("b64 JWS header differs between the signatures")
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jose
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/square/go-jose/v3/json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONWebSignature_SerializeGeneralJSON(t *testing.T) {
+	payload := []byte("payload")
+	signer1, signer2 := newMACSigner("key-1"), newMACSigner("key-2")
+
+	jws, err := NewJWS(Headers{"typ": "JWT"}, Headers{"kid": "key-1"}, payload, signer1)
+	require.NoError(t, err)
+
+	require.NoError(t, jws.AddSignature(nil, Headers{"kid": "key-2"}, signer2))
+
+	t.Run("parse co-signed JWS", func(t *testing.T) {
+		jwsJSON, err := jws.SerializeGeneralJSON(false)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(jwsJSON, "{"))
+
+		parsedJWS, err := ParseJWS(jwsJSON, macVerifier{"key-1": signer1, "key-2": signer2})
+		require.NoError(t, err)
+		require.Equal(t, payload, parsedJWS.Payload)
+		require.Equal(t, Headers{"kid": "key-1"}, parsedJWS.UnprotectedHeaders)
+
+		signatures := parsedJWS.Signatures()
+		require.Len(t, signatures, 2)
+		require.Equal(t, Headers{"kid": "key-2"}, signatures[1].UnprotectedHeaders)
+		require.Equal(t, Headers{"alg": "HS256"}, signatures[1].ProtectedHeaders)
+
+		for _, sig := range signatures {
+			require.NoError(t, sig.VerificationError)
+		}
+
+		require.Equal(t, jws.Signatures(), signatures)
+	})
+
+	t.Run("report the signatures which failed verification", func(t *testing.T) {
+		jwsJSON, err := jws.SerializeGeneralJSON(false)
+		require.NoError(t, err)
+
+		parsedJWS, err := ParseJWS(jwsJSON, macVerifier{"key-2": signer2})
+		require.NoError(t, err)
+
+		signatures := parsedJWS.Signatures()
+		require.EqualError(t, signatures[0].VerificationError, "unknown key key-1")
+		require.NoError(t, signatures[1].VerificationError)
+
+		// the JWS headers are the ones of the verified signature
+		require.Equal(t, Headers{"kid": "key-2"}, parsedJWS.UnprotectedHeaders)
+		require.Equal(t, signatures[1].Signature, parsedJWS.Signature())
+
+		_, err = ParseJWS(jwsJSON, macVerifier{})
+		require.EqualError(t, err, "no JWS signature verified: unknown key key-1")
+	})
+
+	t.Run("detached payload", func(t *testing.T) {
+		jwsJSON, err := jws.SerializeGeneralJSON(true)
+		require.NoError(t, err)
+		require.NotContains(t, jwsJSON, "payload")
+
+		_, err = ParseJWS(jwsJSON, macVerifier{"key-1": signer1})
+		require.EqualError(t, err, "JWS payload is missing")
+
+		parsedJWS, err := ParseJWS(jwsJSON, macVerifier{"key-1": signer1}, WithJWSDetachedPayload(payload))
+		require.NoError(t, err)
+		require.Equal(t, payload, parsedJWS.Payload)
+	})
+
+	t.Run("flattened serialization requires a single signature", func(t *testing.T) {
+		_, err := jws.SerializeFlattenedJSON(false)
+		require.EqualError(t, err, "flattened JWS JSON serialization requires a single signature, the JWS has 2")
+
+		_, err = (&JSONWebSignature{}).SerializeGeneralJSON(false)
+		require.EqualError(t, err, "JWS has no signature")
+	})
+
+	t.Run("add signature errors", func(t *testing.T) {
+		err := jws.AddSignature(Headers{"kid": "key-3"}, Headers{"kid": "key-3"}, signer2)
+		require.EqualError(t, err, "add JWS signature: kid JWS header is both protected and unprotected")
+
+		err = jws.AddSignature(Headers{"b64": false}, nil, signer2)
+		require.EqualError(t, err, "add JWS signature: b64 JWS header differs between the signatures")
+
+		err = jws.AddSignature(nil, nil, &testSigner{headers: Headers{"alg": "dummy"}, err: errors.New("signer error")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "signer error")
+	})
+}
+
+func TestJSONWebSignature_SerializeFlattenedJSON(t *testing.T) {
+	signer := newMACSigner("key-1")
+
+	t.Run("flattened round trip", func(t *testing.T) {
+		jws, err := NewJWS(nil, Headers{"kid": "key-1"}, []byte("payload"), signer)
+		require.NoError(t, err)
+
+		jwsJSON, err := jws.SerializeFlattenedJSON(false)
+		require.NoError(t, err)
+
+		raw := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(jwsJSON), &raw))
+		require.NotContains(t, raw, "signatures")
+		require.Equal(t, base64.RawURLEncoding.EncodeToString([]byte("payload")), raw["payload"])
+
+		parsedJWS, err := ParseJWS(jwsJSON, macVerifier{"key-1": signer})
+		require.NoError(t, err)
+		require.Equal(t, []byte("payload"), parsedJWS.Payload)
+		require.Equal(t, jws.Signature(), parsedJWS.Signature())
+	})
+
+	t.Run("unencoded payload", func(t *testing.T) {
+		jws, err := NewJWS(Headers{"b64": false, "crit": []string{"b64"}}, Headers{"kid": "key-1"},
+			[]byte(`{"unencoded": "payload"}`), signer)
+		require.NoError(t, err)
+
+		jwsJSON, err := jws.SerializeFlattenedJSON(false)
+		require.NoError(t, err)
+
+		raw := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(jwsJSON), &raw))
+		require.Equal(t, `{"unencoded": "payload"}`, raw["payload"])
+
+		parsedJWS, err := ParseJWS(jwsJSON, macVerifier{"key-1": signer})
+		require.NoError(t, err)
+		require.Equal(t, []byte(`{"unencoded": "payload"}`), parsedJWS.Payload)
+	})
+
+	t.Run("the protected headers are verified as encoded by the signer", func(t *testing.T) {
+		protected := base64.RawURLEncoding.EncodeToString([]byte(`{ "alg" : "HS256" }`))
+		payload := base64.RawURLEncoding.EncodeToString([]byte("payload"))
+
+		signature, err := signer.Sign([]byte(protected + "." + payload))
+		require.NoError(t, err)
+
+		jwsJSON := fmt.Sprintf(`{"payload":%q,"protected":%q,"header":{"kid":"key-1"},"signature":%q}`,
+			payload, protected, base64.RawURLEncoding.EncodeToString(signature))
+
+		parsedJWS, err := ParseJWS(jwsJSON, macVerifier{"key-1": signer})
+		require.NoError(t, err)
+		require.Equal(t, Headers{"alg": "HS256"}, parsedJWS.ProtectedHeaders)
+
+		reserialized, err := parsedJWS.SerializeFlattenedJSON(false)
+		require.NoError(t, err)
+		require.Contains(t, reserialized, protected)
+	})
+}
+
+func TestParseJWS_JSONFailures(t *testing.T) {
+	protected := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte("payload"))
+
+	tests := []struct {
+		name string
+		jws  string
+		err  string
+	}{
+		{
+			name: "invalid JSON",
+			jws:  `{"payload":`,
+			err:  "unmarshal JWS JSON",
+		},
+		{
+			name: "both serializations",
+			jws:  `{"signature":"c2ln","signatures":[{"signature":"c2ln"}]}`,
+			err:  "invalid JWS JSON format: both flattened and general serializations",
+		},
+		{
+			name: "invalid protected headers",
+			jws:  `{"payload":"cGF5bG9hZA","protected":"XXXXXaGVsbG8=","signature":"c2ln"}`,
+			err:  "JWS signature 0: decode base64 header",
+		},
+		{
+			name: "protected headers not a JSON object",
+			jws:  fmt.Sprintf(`{"signatures":[{"protected":%q,"signature":"c2ln"}]}`, payload),
+			err:  "JWS signature 0: unmarshal JSON headers",
+		},
+		{
+			name: "headers not disjoint",
+			jws:  fmt.Sprintf(`{"protected":%q,"header":{"alg":"HS256"},"signature":"c2ln"}`, protected),
+			err:  "JWS signature 0: alg JWS header is both protected and unprotected",
+		},
+		{
+			name: "no alg header",
+			jws:  `{"header":{"kid":"key-1"},"signature":"c2ln"}`,
+			err:  "JWS signature 0: alg JWS header is not defined",
+		},
+		{
+			name: "invalid signature",
+			jws:  fmt.Sprintf(`{"protected":%q,"signature":"XXXXXaGVsbG8="}`, protected),
+			err:  "JWS signature 0: decode base64 signature",
+		},
+		{
+			name: "invalid payload",
+			jws:  fmt.Sprintf(`{"payload":"XXXXXaGVsbG8=","protected":%q,"signature":"c2ln"}`, protected),
+			err:  "decode base64 payload",
+		},
+		{
+			name: "b64 headers differ",
+			jws: fmt.Sprintf(`{"payload":%q,"signatures":[{"protected":%q,"signature":"c2ln"},`+
+				`{"protected":%q,"signature":"c2ln"}]}`, payload, protected,
+				base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","b64":false,"crit":["b64"]}`))),
+			err: "b64 JWS header differs between the signatures",
+		},
+		{
+			name: "unprotected crit header",
+			jws:  fmt.Sprintf(`{"protected":%q,"header":{"crit":["b64"]},"signature":"c2ln"}`, protected),
+			err:  "JWS signature 0: crit JWS header must be protected",
+		},
+		{
+			name: "unprotected b64 header",
+			jws:  fmt.Sprintf(`{"protected":%q,"header":{"b64":false},"signature":"c2ln"}`, protected),
+			err:  "JWS signature 0: b64 JWS header must be protected",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			jws, err := ParseJWS(tc.jws, &testVerifier{})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+			require.Nil(t, jws)
+		})
+	}
+}
+
+// macSigner signs with HMAC-SHA256, the JWS signatures of the tests can be verified.
+type macSigner struct {
+	key []byte
+}
+
+func newMACSigner(key string) *macSigner {
+	return &macSigner{key: []byte(key)}
+}
+
+func (s *macSigner) Sign(data []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(data) //nolint:errcheck
+
+	return mac.Sum(nil), nil
+}
+
+func (s *macSigner) Headers() Headers {
+	return Headers{"alg": "HS256"}
+}
+
+// macVerifier verifies the signatures of the macSigner of the kid header.
+type macVerifier map[string]*macSigner
+
+func (v macVerifier) Verify(joseHeaders Headers, _, signingInput, signature []byte) error {
+	kid, _ := joseHeaders.KeyID()
+
+	signer, ok := v[kid]
+	if !ok {
+		return fmt.Errorf("unknown key %s", kid)
+	}
+
+	expected, err := signer.Sign(signingInput)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal(expected, signature) {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
@@ -176,10 +176,10 @@ func TestParseJWS(t *testing.T) {
 	require.NotNil(t, parsedJWS)
 	require.Equal(t, jws, parsedJWS)
 
-	// Parse not compact JWS format
+	// Parse invalid JWS JSON format
 	parsedJWS, err = ParseJWS(`{"some": "JSON"}`, &testVerifier{})
 	require.Error(t, err)
-	require.EqualError(t, err, "JWS JSON serialization is not supported")
+	require.EqualError(t, err, "invalid JWS JSON format: no signatures")
 	require.Nil(t, parsedJWS)
 
 	// Parse invalid compact JWS format
@@ -230,6 +230,113 @@ func TestParseJWS(t *testing.T) {
 	require.Nil(t, parsedJWS)
 }
 
+func TestJSONWebSignature_UnencodedPayload(t *testing.T) {
+	payload := []byte(`{"unencoded":"payload"}`)
+	signer := newMACSigner("key-1")
+
+	jws, err := NewJWS(Headers{"b64": false, "kid": "key-1"}, nil, payload, signer)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b64"}, jws.ProtectedHeaders[HeaderCritical])
+
+	t.Run("compact serialization", func(t *testing.T) {
+		jwsCompact, err := jws.SerializeCompact(false)
+		require.NoError(t, err)
+
+		parts := strings.Split(jwsCompact, ".")
+		require.Equal(t, string(payload), parts[jwsPayloadPart])
+
+		signature, err := signer.Sign([]byte(parts[jwsHeaderPart] + "." + string(payload)))
+		require.NoError(t, err)
+		require.Equal(t, signature, jws.Signature())
+
+		parsedJWS, err := ParseJWS(jwsCompact, macVerifier{"key-1": signer})
+		require.NoError(t, err)
+		require.Equal(t, payload, parsedJWS.Payload)
+		require.Equal(t, []interface{}{"b64"}, parsedJWS.ProtectedHeaders[HeaderCritical])
+	})
+
+	t.Run("detached compact serialization", func(t *testing.T) {
+		periodJWS, err := NewJWS(Headers{"b64": false, "kid": "key-1"}, nil, []byte("pay.load"), signer)
+		require.NoError(t, err)
+
+		_, err = periodJWS.SerializeCompact(false)
+		require.EqualError(t, err, "unencoded JWS payload with a period must be detached in compact serialization")
+
+		jwsCompact, err := periodJWS.SerializeCompact(true)
+		require.NoError(t, err)
+
+		parsedJWS, err := ParseJWS(jwsCompact, macVerifier{"key-1": signer}, WithJWSDetachedPayload([]byte("pay.load")))
+		require.NoError(t, err)
+		require.Equal(t, []byte("pay.load"), parsedJWS.Payload)
+	})
+
+	t.Run("JSON serialization", func(t *testing.T) {
+		jwsJSON, err := jws.SerializeFlattenedJSON(false)
+		require.NoError(t, err)
+
+		parsedJWS, err := ParseJWS(jwsJSON, macVerifier{"key-1": signer})
+		require.NoError(t, err)
+		require.Equal(t, payload, parsedJWS.Payload)
+	})
+
+	t.Run("b64 and crit headers must be protected", func(t *testing.T) {
+		_, err := NewJWS(nil, Headers{"b64": false}, payload, signer)
+		require.EqualError(t, err, "sign JWS: b64 JWS header must be protected")
+
+		err = jws.AddSignature(nil, Headers{"crit": []string{"b64"}}, signer)
+		require.EqualError(t, err, "add JWS signature: crit JWS header must be protected")
+
+		_, err = NewJWS(Headers{"b64": false, "crit": "b64"}, nil, payload, signer)
+		require.EqualError(t, err, "sign JWS: invalid crit header")
+	})
+}
+
+func TestParseJWS_CriticalHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers string
+		err     string
+	}{
+		{
+			name:    "unsupported critical header",
+			headers: `{"alg":"HS256","exp":1363284000,"crit":["exp"]}`,
+			err:     "critical JWS header exp is not supported",
+		},
+		{
+			name:    "critical header is not defined",
+			headers: `{"alg":"HS256","crit":["b64"]}`,
+			err:     "critical JWS header b64 is not defined",
+		},
+		{
+			name:    "empty crit header",
+			headers: `{"alg":"HS256","crit":[]}`,
+			err:     "crit JWS header is empty",
+		},
+		{
+			name:    "invalid crit header",
+			headers: `{"alg":"HS256","crit":["b64",1],"b64":false}`,
+			err:     "invalid crit header",
+		},
+		{
+			name:    "b64 header is not critical",
+			headers: `{"alg":"HS256","b64":false}`,
+			err:     "b64 JWS header is not critical",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			jwsCompact := base64.RawURLEncoding.EncodeToString([]byte(tc.headers)) + ".payload.c2ln"
+
+			jws, err := ParseJWS(jwsCompact, &testVerifier{})
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, jws)
+		})
+	}
+}
+
 func TestIsCompactJWS(t *testing.T) {
 	require.True(t, IsCompactJWS("a.b.c"))
 	require.False(t, IsCompactJWS("a.b"))
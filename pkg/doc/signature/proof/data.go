@@ -41,7 +41,7 @@ const (
 // CreateVerifyData creates data that is used to generate or verify a digital signature.
 // It depends on the signature value holder type.
 // In case of "proofValue", the standard Create Verify Hash algorithm is used.
-// In case of "jws", verify data is built as JSON Web Signature (JWS) with detached payload.
+// In case of "jws", verify data is the detached and unencoded payload of JSON Web Signature (JWS).
 func CreateVerifyData(suite signatureSuite, jsonldDoc map[string]interface{}, proof *Proof,
 	opts ...jsonld.ProcessorOpts) ([]byte, error) {
 	switch proof.SignatureRepresentation {
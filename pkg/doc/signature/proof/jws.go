@@ -6,24 +6,17 @@ SPDX-License-Identifier: Apache-2.0
 package proof
 
 import (
-	"encoding/base64"
 	"encoding/json"
-	"errors"
-	"strings"
 
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
 )
 
 const securityContext = "https://w3id.org/security/v2"
 
-const (
-	jwtPartsNumber   = 3
-	jwtHeaderPart    = 0
-	jwtSignaturePart = 2
-)
-
-// CreateDetachedJWTHeader creates detached JWT header.
-func CreateDetachedJWTHeader(p *Proof) string {
+// CreateDetachedJWTHeader creates the protected headers of a JWS with unencoded payload
+// (https://tools.ietf.org/html/rfc7797), jose.NewJWS lists the b64 header as critical.
+func CreateDetachedJWTHeader(p *Proof) jose.Headers {
 	var jwsAlg string
 
 	// TODO this is a hacky workaround, to be improved
@@ -37,41 +30,15 @@ func CreateDetachedJWTHeader(p *Proof) string {
 		jwsAlg = p.Type
 	}
 
-	jwtHeaderMap := map[string]interface{}{
-		"alg":  jwsAlg,
-		"b64":  false,
-		"crit": []string{"b64"},
-	}
-
-	jwtHeaderBytes, err := json.Marshal(jwtHeaderMap)
-	if err != nil {
-		panic(err)
-	}
-
-	return base64.RawURLEncoding.EncodeToString(jwtHeaderBytes)
-}
-
-// GetJWTSignature returns signature part of JWT
-func GetJWTSignature(jwt string) ([]byte, error) {
-	jwtParts := strings.Split(jwt, ".")
-	if len(jwtParts) != jwtPartsNumber || jwtParts[jwtSignaturePart] == "" {
-		return nil, errors.New("invalid JWT")
+	return jose.Headers{
+		jose.HeaderAlgorithm:  jwsAlg,
+		jose.HeaderB64Payload: false,
 	}
-
-	return base64.RawURLEncoding.DecodeString(jwtParts[jwtSignaturePart])
-}
-
-func getJWTHeader(jwt string) (string, error) {
-	jwtParts := strings.Split(jwt, ".")
-	if len(jwtParts) != jwtPartsNumber { // nolint:gomnd
-		return "", errors.New("invalid JWT")
-	}
-
-	return jwtParts[jwtHeaderPart], nil
 }
 
 // createVerifyJWS creates a data to be used to create/verify a digital signature in the
 // form of JSON Web Signature (JWS) with detached content (https://tools.ietf.org/html/rfc7797).
+// The data is the unencoded JWS payload, jose builds the JWS signing input from it.
 // The algorithm of building the payload is similar to conventional  Create Verify Hash algorithm.
 // It differs by using https://w3id.org/security/v2 as context for JSON-LD canonization of both
 // JSON and Signature documents and by preliminary JSON-LD compacting of JSON document.
@@ -94,14 +61,7 @@ func createVerifyJWS(suite signatureSuite, jsonldDoc map[string]interface{}, p *
 
 	docDigest := suite.GetDigest(canonicalDoc)
 
-	verifyData := append(proofOptionsDigest, docDigest...)
-
-	jwtHeader, err := getJWTHeader(p.JWS)
-	if err != nil {
-		return nil, err
-	}
-
-	return append([]byte(jwtHeader+"."), verifyData...), nil
+	return append(proofOptionsDigest, docDigest...), nil
 }
 
 func prepareJWSProof(suite signatureSuite, proofOptions map[string]interface{},
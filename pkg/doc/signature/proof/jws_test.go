@@ -6,24 +6,14 @@ SPDX-License-Identifier: Apache-2.0
 package proof
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
-)
-
-func Test_getJWTHeader(t *testing.T) {
-	jwtHeader, err := getJWTHeader("eyJ0eXAiOiJK..gFWFOEjXk")
-	require.NoError(t, err)
-	require.Equal(t, "eyJ0eXAiOiJK", jwtHeader)
 
-	jwtHeader, err = getJWTHeader("invalid jwt")
-	require.Error(t, err)
-	require.EqualError(t, err, "invalid JWT")
-	require.Empty(t, jwtHeader)
-}
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
+)
 
 func Test_createVerifyJWS(t *testing.T) {
 	created, err := time.Parse(time.RFC3339, "2018-03-15T00:00:00Z")
@@ -32,7 +22,6 @@ func Test_createVerifyJWS(t *testing.T) {
 	p := &Proof{
 		Type:         "Ed25519Signature2018",
 		Created:      &created,
-		JWS:          "eyJ0eXAiOiJK..gFWFOEjXk",
 		ProofPurpose: "assertionMethod",
 	}
 
@@ -56,78 +45,22 @@ func Test_createVerifyJWS(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "invalid type value")
 	require.Empty(t, proofVerifyData)
-
-	// invalid JWT passed (we need to read a header from it to prepare verify data)
-	doc["type"] = "Ed25519Signature2018"
-	p.JWS = "invalid jws"
-	proofVerifyData, err = createVerifyJWS(&mockSignatureSuite{}, doc, p)
-	require.Error(t, err)
-	require.Contains(t, err.Error(), "invalid JWT")
-	require.Empty(t, proofVerifyData)
 }
 
 func TestCreateDetachedJWTHeader(t *testing.T) {
-	getJwtHeaderMap := func(jwtHeaderB64 string) map[string]interface{} {
-		jwtHeaderBytes, err := base64.RawURLEncoding.DecodeString(jwtHeaderB64)
-		require.NoError(t, err)
-
-		var jwtHeaderMap map[string]interface{}
-		err = json.Unmarshal(jwtHeaderBytes, &jwtHeaderMap)
-		require.NoError(t, err)
-
-		return jwtHeaderMap
-	}
-
 	jwtHeader := CreateDetachedJWTHeader(&Proof{
 		Type: "Ed25519Signature2018",
 	})
-	require.NotEmpty(t, jwtHeader)
-
-	jwtHeaderMap := getJwtHeaderMap(jwtHeader)
-	require.Equal(t, "EdDSA", jwtHeaderMap["alg"])
-	require.Equal(t, false, jwtHeaderMap["b64"])
-	require.Equal(t, []interface{}{"b64"}, jwtHeaderMap["crit"])
+	require.Equal(t, jose.Headers{"alg": "EdDSA", "b64": false}, jwtHeader)
 
 	jwtHeader = CreateDetachedJWTHeader(&Proof{
 		Type: "EcdsaSecp256k1Signature2019",
 	})
-	require.NotEmpty(t, jwtHeader)
-
-	jwtHeaderMap = getJwtHeaderMap(jwtHeader)
-	require.Equal(t, "ES256K", jwtHeaderMap["alg"])
-	require.Equal(t, false, jwtHeaderMap["b64"])
-	require.Equal(t, []interface{}{"b64"}, jwtHeaderMap["crit"])
+	require.Equal(t, jose.Headers{"alg": "ES256K", "b64": false}, jwtHeader)
 
 	jwtHeader = CreateDetachedJWTHeader(&Proof{
 		Type: "JsonWebSignature2020",
 	})
-	require.NotEmpty(t, jwtHeader)
-
-	jwtHeaderMap = getJwtHeaderMap(jwtHeader)
 	// TODO this should be improved (https://github.com/hyperledger/aries-framework-go/issues/1589)
-	require.Equal(t, "JsonWebSignature2020", jwtHeaderMap["alg"])
-	require.Equal(t, false, jwtHeaderMap["b64"])
-	require.Equal(t, []interface{}{"b64"}, jwtHeaderMap["crit"])
-}
-
-func TestGetJWTSignature(t *testing.T) {
-	jwtSignature := base64.RawURLEncoding.EncodeToString([]byte("test signature"))
-	jws := "header.payload." + jwtSignature
-
-	// happy path
-	signature, err := GetJWTSignature(jws)
-	require.NoError(t, err)
-	require.Equal(t, []byte("test signature"), signature)
-
-	// not JWS
-	signature, err = GetJWTSignature("incorrect JWS structure")
-	require.Error(t, err)
-	require.EqualError(t, err, "invalid JWT")
-	require.Empty(t, signature)
-
-	// empty signature (unsecured JWT)
-	signature, err = GetJWTSignature("header.payload.")
-	require.Error(t, err)
-	require.EqualError(t, err, "invalid JWT")
-	require.Empty(t, signature)
+	require.Equal(t, jose.Headers{"alg": "JsonWebSignature2020", "b64": false}, jwtHeader)
 }
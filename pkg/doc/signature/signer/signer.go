@@ -6,7 +6,6 @@ SPDX-License-Identifier: Apache-2.0
 package signer
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,6 +13,7 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/jsonld"
 
+	"github.com/hyperledger/aries-framework-go/pkg/doc/jose"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/proof"
 )
 
@@ -117,32 +117,56 @@ func (signer *DocumentSigner) signObject(context *Context, jsonLdObject map[stri
 		p.ProofPurpose = defaultProofPurpose
 	}
 
-	if context.SignatureRepresentation == proof.SignatureJWS {
-		p.JWS = proof.CreateDetachedJWTHeader(p) + ".."
-	}
-
 	message, err := proof.CreateVerifyData(suite, jsonLdObject, p, jsonld.WithRemoveAllInvalidRDF())
 	if err != nil {
 		return err
 	}
 
-	s, err := suite.Sign(message)
+	err = signer.applySignatureValue(context, p, suite, message)
 	if err != nil {
 		return err
 	}
 
-	signer.applySignatureValue(context, p, s)
-
 	return proof.AddProof(jsonLdObject, p)
 }
 
-func (signer *DocumentSigner) applySignatureValue(context *Context, p *proof.Proof, s []byte) {
+func (signer *DocumentSigner) applySignatureValue(context *Context, p *proof.Proof, suite SignatureSuite,
+	message []byte) error {
 	switch context.SignatureRepresentation {
 	case proof.SignatureProofValue:
+		s, err := suite.Sign(message)
+		if err != nil {
+			return err
+		}
+
 		p.ProofValue = s
 	case proof.SignatureJWS:
-		p.JWS += base64.RawURLEncoding.EncodeToString(s)
+		jws, err := jose.NewJWS(nil, nil, message, &jwsSigner{suite: suite, headers: proof.CreateDetachedJWTHeader(p)})
+		if err != nil {
+			return err
+		}
+
+		p.JWS, err = jws.SerializeCompact(true)
+		if err != nil {
+			return err
+		}
 	}
+
+	return nil
+}
+
+// jwsSigner signs the JWS of a proof with the signature suite.
+type jwsSigner struct {
+	suite   SignatureSuite
+	headers jose.Headers
+}
+
+func (s *jwsSigner) Sign(data []byte) ([]byte, error) {
+	return s.suite.Sign(data)
+}
+
+func (s *jwsSigner) Headers() jose.Headers {
+	return s.headers
 }
 
 // getSignatureSuite returns signature suite based on signature type
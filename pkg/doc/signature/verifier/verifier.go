@@ -106,12 +106,7 @@ func (dv *DocumentVerifier) verifyObject(jsonLdObject map[string]interface{}, op
 			return err
 		}
 
-		signature, err := getProofVerifyValue(p)
-		if err != nil {
-			return err
-		}
-
-		err = suite.Verify(publicKey, message, signature)
+		err = verifyProofValue(suite, publicKey, p, message)
 		if err != nil {
 			return err
 		}
@@ -131,13 +126,19 @@ func (dv *DocumentVerifier) getSignatureSuite(signatureType string) (SignatureSu
 	return nil, fmt.Errorf("signature type %s not supported", signatureType)
 }
 
-func getProofVerifyValue(p *proof.Proof) ([]byte, error) {
+func verifyProofValue(suite SignatureSuite, publicKey *PublicKey, p *proof.Proof, message []byte) error {
 	switch p.SignatureRepresentation {
 	case proof.SignatureProofValue:
-		return p.ProofValue, nil
+		return suite.Verify(publicKey, message, p.ProofValue)
 	case proof.SignatureJWS:
-		return proof.GetJWTSignature(p.JWS)
+		jwsVerifier := jose.SignatureVerifierFunc(func(_ jose.Headers, _, signingInput, signature []byte) error {
+			return suite.Verify(publicKey, signingInput, signature)
+		})
+
+		_, err := jose.ParseJWS(p.JWS, jwsVerifier, jose.WithJWSDetachedPayload(message))
+
+		return err
 	}
 
-	return nil, fmt.Errorf("unsupported signature representation: %v", p.SignatureRepresentation)
+	return fmt.Errorf("unsupported signature representation: %v", p.SignatureRepresentation)
 }
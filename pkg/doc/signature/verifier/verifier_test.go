@@ -107,31 +107,44 @@ func TestVerify(t *testing.T) {
 	require.Nil(t, v)
 }
 
-func Test_getProofVerifyValue(t *testing.T) {
+func Test_verifyProofValue(t *testing.T) {
+	suite := &testSignatureSuite{accept: true}
+	publicKey := &PublicKey{Type: kms.ED25519, Value: []byte("public key")}
+
+	jwsHeaders := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"EdDSA","b64":false,"crit":["b64"]}`))
 	jwsSignature := base64.RawURLEncoding.EncodeToString([]byte("signature"))
 
 	// signatureValue
 	p := &proof.Proof{
 		SignatureRepresentation: proof.SignatureProofValue,
 		ProofValue:              []byte("proof value"),
-		JWS:                     "j.w." + jwsSignature,
+		JWS:                     jwsHeaders + ".." + jwsSignature,
 	}
-	proofVerifyValue, err := getProofVerifyValue(p)
+	err := verifyProofValue(suite, publicKey, p, []byte("message"))
 	require.NoError(t, err)
-	require.Equal(t, []byte("proof value"), proofVerifyValue)
 
 	// JWS
 	p.SignatureRepresentation = proof.SignatureJWS
-	proofVerifyValue, err = getProofVerifyValue(p)
+	err = verifyProofValue(suite, publicKey, p, []byte("message"))
 	require.NoError(t, err)
-	require.Equal(t, []byte("signature"), proofVerifyValue)
+
+	// JWS with unsupported critical header
+	p.JWS = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"EdDSA","crit":["exp"],"exp":1}`)) +
+		".." + jwsSignature
+	err = verifyProofValue(suite, publicKey, p, []byte("message"))
+	require.EqualError(t, err, "critical JWS header exp is not supported")
+
+	// JWS verification error
+	p.JWS = jwsHeaders + ".." + jwsSignature
+	err = verifyProofValue(&testSignatureSuite{verifyError: errors.New("verify data error")}, publicKey, p,
+		[]byte("message"))
+	require.EqualError(t, err, "verify data error")
 
 	// unsupported signature holding
 	p.SignatureRepresentation = proof.SignatureRepresentation(-1)
-	proofVerifyValue, err = getProofVerifyValue(p)
+	err = verifyProofValue(suite, publicKey, p, []byte("message"))
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "unsupported signature representation")
-	require.Nil(t, proofVerifyValue)
 }
 
 type testKeyResolver struct {
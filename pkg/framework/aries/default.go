@@ -18,6 +18,7 @@ import (
 	jwe "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/jwe/authcrypt"
 	legacy "github.com/hyperledger/aries-framework-go/pkg/didcomm/packer/legacy/authcrypt"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didupdate"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/introduce"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/issuecredential"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/outofband"
@@ -26,9 +27,12 @@ import (
 	arieshttp "github.com/hyperledger/aries-framework-go/pkg/didcomm/transport/http"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
+	"github.com/hyperledger/aries-framework-go/pkg/store/verifiable"
+	"github.com/hyperledger/aries-framework-go/pkg/vdri/peer"
 )
 
 // defFrameworkOpts provides default framework options
@@ -58,9 +62,11 @@ func defFrameworkOpts(frameworkOpts *Aries) error {
 	// - Introduce depends on OutOfBand
 	frameworkOpts.protocolSvcCreators = append(frameworkOpts.protocolSvcCreators,
 		newRouteSvc(), newExchangeSvc(), newOutOfBandSvc(), newIntroduceSvc(),
-		newIssueCredentialSvc(), newPresentProofSvc(),
+		newIssueCredentialSvc(), newPresentProofSvc(), newDIDUpdateSvc(),
 	)
 
+	frameworkOpts.migrations = append(frameworkOpts.migrations, defaultMigrations()...)
+
 	if frameworkOpts.secretLock == nil && frameworkOpts.kmsCreator == nil {
 		err := createDefSecretLock(frameworkOpts)
 		if err != nil {
@@ -77,6 +83,18 @@ func defFrameworkOpts(frameworkOpts *Aries) error {
 	return setAdditionalDefaultOpts(frameworkOpts)
 }
 
+// defaultMigrations returns the schema migrations of the framework stores.
+func defaultMigrations() []migration.Migration {
+	var migrations []migration.Migration
+
+	migrations = append(migrations, connection.Migrations()...)
+	migrations = append(migrations, verifiable.Migrations()...)
+	migrations = append(migrations, issuecredential.Migrations()...)
+	migrations = append(migrations, peer.Migrations()...)
+
+	return migrations
+}
+
 func newExchangeSvc() api.ProtocolSvcCreator {
 	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
 		return didexchange.New(prv)
@@ -113,13 +131,13 @@ func newOutOfBandSvc() api.ProtocolSvcCreator {
 	}
 }
 
-func setAdditionalDefaultOpts(frameworkOpts *Aries) error {
-	if frameworkOpts.legacyKMSCreator == nil {
-		frameworkOpts.legacyKMSCreator = func(provider api.Provider) (api.CloseableKMS, error) {
-			return legacykms.New(provider)
-		}
+func newDIDUpdateSvc() api.ProtocolSvcCreator {
+	return func(prv api.Provider) (dispatcher.ProtocolService, error) {
+		return didupdate.New(prv)
 	}
+}
 
+func setAdditionalDefaultOpts(frameworkOpts *Aries) error {
 	if frameworkOpts.crypto == nil {
 		// create default tink crypto if not passed in frameworkOpts
 		cr, err := tinkcrypto.New()
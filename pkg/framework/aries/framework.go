@@ -9,6 +9,7 @@ package aries
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/messenger"
 
@@ -21,13 +22,17 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packager"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didupdate"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/context"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/rotation"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
 	"github.com/hyperledger/aries-framework-go/pkg/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/vdri/peer"
 )
@@ -51,8 +56,6 @@ type Aries struct {
 	messenger              service.MessengerHandler
 	outboundTransports     []transport.OutboundTransport
 	inboundTransports      []transport.InboundTransport
-	legacyKMSCreator       api.KMSCreator
-	legacyKMS              api.CloseableKMS
 	kms                    kms.KeyManager
 	kmsCreator             kms.Creator
 	secretLock             secretlock.Service
@@ -65,9 +68,14 @@ type Aries struct {
 	packers                []packer.Packer
 	vdriRegistry           vdriapi.Registry
 	vdri                   []vdriapi.VDRI
+	peerVDRI               *peer.VDRI
+	keyRotationMaxAge      time.Duration
+	keyRotationOpts        []rotation.Option
+	keyRotation            *rotation.Scheduler
 	defaultServiceEndpoint string
-	defaultRouterEndpoint string
+	defaultRouterEndpoint  string
 	transportReturnRoute   string
+	migrations             []migration.Migration
 	id                     string
 }
 
@@ -108,21 +116,28 @@ func New(opts ...Option) (*Aries, error) {
 
 func initializeServices(frameworkOpts *Aries) (*Aries, error) {
 	// Order of initializing service is important
-	// Create legacyKMS
-	if e := createLegacyKMS(frameworkOpts); e != nil {
-		return nil, e
+	// Upgrade persisted records before anything reads them
+	if err := migration.Run(frameworkOpts.storeProvider, frameworkOpts.transientStoreProvider,
+		frameworkOpts.migrations...); err != nil {
+		return nil, fmt.Errorf("store migration failed: %w", err)
 	}
 
 	if e := createKMS(frameworkOpts); e != nil {
 		return nil, e
 	}
 
+	// Import the keys of the legacy keystore, so that the DIDs created with them keep working
+	if err := migration.Run(frameworkOpts.storeProvider, nil,
+		legacykms.Migrations(frameworkOpts.kms)...); err != nil {
+		return nil, fmt.Errorf("legacy keystore migration failed: %w", err)
+	}
+
 	// Create vdri
 	if e := createVDRI(frameworkOpts); e != nil {
 		return nil, e
 	}
 
-	// create packers and packager (must be done after LegacyKMS and connection store)
+	// create packers and packager (must be done after KMS and connection store)
 	if err := createPackersAndPackager(frameworkOpts); err != nil {
 		return nil, err
 	}
@@ -147,6 +162,11 @@ func initializeServices(frameworkOpts *Aries) (*Aries, error) {
 		return nil, err
 	}
 
+	// Start the key rotation (must be done after the services which notify the connections of the rotated keys)
+	if err := startKeyRotation(frameworkOpts); err != nil {
+		return nil, err
+	}
+
 	return frameworkOpts, nil
 }
 
@@ -231,10 +251,12 @@ func WithProtocols(protocolSvcCreator ...api.ProtocolSvcCreator) Option {
 	}
 }
 
-// WithLegacyKMS injects a LegacyKMS service to the Aries framework.
-func WithLegacyKMS(k api.KMSCreator) Option {
+// WithMigrations injects schema migrations of stores to the Aries framework, typically the ones of the stores of
+// the protocols injected with WithProtocols. Migrations run when the framework starts, along with the migrations of
+// the framework stores. Refer migration.Run().
+func WithMigrations(migrations ...migration.Migration) Option {
 	return func(opts *Aries) error {
-		opts.legacyKMSCreator = k
+		opts.migrations = append(opts.migrations, migrations...)
 		return nil
 	}
 }
@@ -293,6 +315,18 @@ func WithPacker(primary packer.Creator, additionalPackers ...packer.Creator) Opt
 	}
 }
 
+// WithKeyRotation rotates the keys of the KMS older than maxAge, the KMS must support the rotation (refer
+// rotation.KeyManager). The peer DID documents of the rotated keys are updated and sent to the connections of their
+// DIDs. The rotation is configured with rotationOpts, eg the grace period of the previous versions of the rotated keys.
+func WithKeyRotation(maxAge time.Duration, rotationOpts ...rotation.Option) Option {
+	return func(opts *Aries) error {
+		opts.keyRotationMaxAge = maxAge
+		opts.keyRotationOpts = rotationOpts
+
+		return nil
+	}
+}
+
 // Context provides a handle to the framework context.
 func (a *Aries) Context() (*context.Provider, error) {
 	return context.New(
@@ -300,7 +334,6 @@ func (a *Aries) Context() (*context.Provider, error) {
 		context.WithMessengerHandler(a.messenger),
 		context.WithOutboundTransports(a.outboundTransports...),
 		context.WithProtocolServices(a.services...),
-		context.WithLegacyKMS(a.legacyKMS),
 		context.WithKMS(a.kms),
 		context.WithSecretLock(a.secretLock),
 		context.WithCrypto(a.crypto),
@@ -325,11 +358,8 @@ func (a *Aries) Messenger() service.Messenger {
 
 // Close frees resources being maintained by the framework.
 func (a *Aries) Close() error {
-	if a.legacyKMS != nil {
-		err := a.legacyKMS.Close()
-		if err != nil {
-			return fmt.Errorf("failed to close the legacyKMS: %w", err)
-		}
+	if a.keyRotation != nil {
+		a.keyRotation.Stop()
 	}
 
 	if a.storeProvider != nil {
@@ -365,22 +395,6 @@ func (a *Aries) closeVDRI() error {
 	return nil
 }
 
-func createLegacyKMS(frameworkOpts *Aries) error {
-	ctx, err := context.New(
-		context.WithStorageProvider(frameworkOpts.storeProvider),
-	)
-	if err != nil {
-		return fmt.Errorf("create context failed: %w", err)
-	}
-
-	frameworkOpts.legacyKMS, err = frameworkOpts.legacyKMSCreator(ctx)
-	if err != nil {
-		return fmt.Errorf("create legacyKMS failed: %w", err)
-	}
-
-	return nil
-}
-
 func createKMS(frameworkOpts *Aries) error {
 	ctx, err := context.New(
 		context.WithStorageProvider(frameworkOpts.storeProvider),
@@ -400,7 +414,7 @@ func createKMS(frameworkOpts *Aries) error {
 
 func createVDRI(frameworkOpts *Aries) error {
 	ctx, err := context.New(
-		context.WithLegacyKMS(frameworkOpts.legacyKMS),
+		context.WithKMS(frameworkOpts.kms),
 		context.WithCrypto(frameworkOpts.crypto),
 		context.WithStorageProvider(frameworkOpts.storeProvider),
 		context.WithServiceEndpoint(serviceEndpoint(frameworkOpts)),
@@ -419,6 +433,8 @@ func createVDRI(frameworkOpts *Aries) error {
 		return fmt.Errorf("create new vdri peer failed: %w", err)
 	}
 
+	frameworkOpts.peerVDRI = p
+
 	opts = append(opts,
 		vdri.WithVDRI(p),
 		vdri.WithDefaultServiceType(vdriapi.DIDCommServiceType),
@@ -450,7 +466,7 @@ func createMessengerHandler(frameworkOpts *Aries) error {
 
 func createOutboundDispatcher(frameworkOpts *Aries) error {
 	ctx, err := context.New(
-		context.WithLegacyKMS(frameworkOpts.legacyKMS),
+		context.WithKMS(frameworkOpts.kms),
 		context.WithCrypto(frameworkOpts.crypto),
 		context.WithOutboundTransports(frameworkOpts.outboundTransports...),
 		context.WithPackager(frameworkOpts.packager),
@@ -468,7 +484,7 @@ func createOutboundDispatcher(frameworkOpts *Aries) error {
 
 func startTransports(frameworkOpts *Aries) error {
 	ctx, err := context.New(
-		context.WithLegacyKMS(frameworkOpts.legacyKMS),
+		context.WithKMS(frameworkOpts.kms),
 		context.WithCrypto(frameworkOpts.crypto),
 		context.WithPackager(frameworkOpts.packager),
 		context.WithProtocolServices(frameworkOpts.services...),
@@ -503,7 +519,7 @@ func loadServices(frameworkOpts *Aries) error {
 		context.WithMessengerHandler(frameworkOpts.messenger),
 		context.WithStorageProvider(frameworkOpts.storeProvider),
 		context.WithTransientStorageProvider(frameworkOpts.transientStoreProvider),
-		context.WithLegacyKMS(frameworkOpts.legacyKMS),
+		context.WithKMS(frameworkOpts.kms),
 		context.WithCrypto(frameworkOpts.crypto),
 		context.WithPackager(frameworkOpts.packager),
 		context.WithServiceEndpoint(serviceEndpoint(frameworkOpts)),
@@ -532,9 +548,40 @@ func loadServices(frameworkOpts *Aries) error {
 	return nil
 }
 
+func startKeyRotation(frameworkOpts *Aries) error {
+	if frameworkOpts.keyRotationMaxAge == 0 {
+		return nil
+	}
+
+	km, ok := frameworkOpts.kms.(rotation.KeyManager)
+	if !ok {
+		return fmt.Errorf("key rotation: KMS %T doesn't support the rotation of keys", frameworkOpts.kms)
+	}
+
+	opts := []rotation.Option{rotation.WithDIDStore(frameworkOpts.peerVDRI)}
+
+	for _, svc := range frameworkOpts.services {
+		if updateSvc, ok := svc.(*didupdate.Service); ok {
+			opts = append(opts, rotation.WithNotifier(updateSvc.NotifyConnections))
+		}
+	}
+
+	scheduler, err := rotation.New(km, frameworkOpts.keyRotationMaxAge,
+		append(opts, frameworkOpts.keyRotationOpts...)...)
+	if err != nil {
+		return fmt.Errorf("key rotation: %w", err)
+	}
+
+	scheduler.Start()
+
+	frameworkOpts.keyRotation = scheduler
+
+	return nil
+}
+
 func createPackersAndPackager(frameworkOpts *Aries) error {
 	ctx, err := context.New(
-		context.WithLegacyKMS(frameworkOpts.legacyKMS),
+		context.WithKMS(frameworkOpts.kms),
 		context.WithCrypto(frameworkOpts.crypto),
 	)
 	if err != nil {
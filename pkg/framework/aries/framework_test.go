@@ -9,6 +9,8 @@ SPDX-License-Identifier: Apache-2.0
 package aries
 
 import (
+	"bytes"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -17,18 +19,25 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/remotecrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
+	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/dispatcher"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didupdate"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/transport"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
@@ -38,17 +47,27 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/msghandler"
 	mockdidexchange "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/didexchange"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol/generic"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/remotekms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/remotekms/keyserver"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/rotation"
 	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
 	locallock "github.com/hyperledger/aries-framework-go/pkg/secretlock/local"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/hkdf"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	ariesstorage "github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/encrypted"
 	"github.com/hyperledger/aries-framework-go/pkg/storage/leveldb"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/store/connection"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
 	"github.com/hyperledger/aries-framework-go/pkg/vdri/peer"
 )
 
@@ -103,9 +122,6 @@ func TestFramework(t *testing.T) {
 
 		aries, err := New(
 			WithInboundTransport(&mockInboundTransport{}),
-			WithLegacyKMS(func(ctx api.Provider) (api.CloseableKMS, error) {
-				return &mockkms.CloseableKMS{SignMessageValue: []byte("mockValue")}, nil
-			}),
 			WithPacker(func(ctx packer.Provider) (packer.Packer, error) {
 				return &didcomm.MockAuthCrypt{
 					EncryptValue: func(payload, senderPubKey []byte, recipients [][]byte) (bytes []byte, e error) {
@@ -150,7 +166,10 @@ func TestFramework(t *testing.T) {
 
 	t.Run("test error create vdri", func(t *testing.T) {
 		_, err := New(
-			WithStoreProvider(&storage.MockStoreProvider{FailNamespace: peer.StoreNamespace}),
+			WithStoreProvider(&storage.MockStoreProvider{
+				Store:         &storage.MockStore{Store: make(map[string][]byte)},
+				FailNamespace: peer.StoreNamespace,
+			}),
 			WithInboundTransport(&mockInboundTransport{}))
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "create new vdri peer failed")
@@ -301,16 +320,17 @@ func TestFramework(t *testing.T) {
 		require.Contains(t, err.Error(), "inbound transport close failed")
 	})
 
-	t.Run("test legacyKMS svc - with user provided instance", func(t *testing.T) {
+	t.Run("test signer - with user provided kms and crypto", func(t *testing.T) {
 		path, cleanup := generateTempDir(t)
 		defer cleanup()
 		dbPath = path
 
-		// with custom legacyKMS
+		// with custom kms and crypto
 		aries, err := New(WithInboundTransport(&mockInboundTransport{}),
-			WithLegacyKMS(func(ctx api.Provider) (api.CloseableKMS, error) {
-				return &mockkms.CloseableKMS{SignMessageValue: []byte("mockValue")}, nil
-			}))
+			WithKMS(func(ctx kms.Provider) (kms.KeyManager, error) {
+				return &mockkms.KeyManager{}, nil
+			}),
+			WithCrypto(&mockcrypto.Crypto{SignValue: []byte("mockValue")}))
 		require.NoError(t, err)
 		require.NotEmpty(t, aries)
 
@@ -324,6 +344,124 @@ func TestFramework(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("test signer - with remote kms and crypto", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
+		// the key server keeps the keys in its own local kms
+		p, err := context.New(
+			context.WithSecretLock(&noop.NoLock{}),
+			context.WithStorageProvider(mem.NewProvider()),
+		)
+		require.NoError(t, err)
+
+		serverKMS, err := localkms.New("local-lock://key/server/master/key/", p)
+		require.NoError(t, err)
+
+		serverCrypto, err := tinkcrypto.New()
+		require.NoError(t, err)
+
+		srv := httptest.NewServer(keyserver.New(serverKMS, serverCrypto))
+		defer srv.Close()
+
+		transport, err := remotekms.NewHTTPTransport(srv.URL)
+		require.NoError(t, err)
+
+		aries, err := New(WithInboundTransport(&mockInboundTransport{}),
+			WithKMS(remotekms.Creator(transport)),
+			WithCrypto(remotecrypto.New(transport)))
+		require.NoError(t, err)
+		require.NotEmpty(t, aries)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		_, pubKey, err := ctx.KMS().CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		msg := []byte("lorem ipsum")
+
+		sig, err := ctx.Signer().SignMessage(msg, base58.Encode(pubKey))
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pubKey, msg, sig))
+
+		// the key is held by the key server only
+		_, err = serverKMS.Get(kms.PublicKeyID(pubKey))
+		require.NoError(t, err)
+
+		err = aries.Close()
+		require.NoError(t, err)
+	})
+
+	t.Run("test packers - with remote kms and crypto", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
+		p, err := context.New(
+			context.WithSecretLock(&noop.NoLock{}),
+			context.WithStorageProvider(mem.NewProvider()),
+		)
+		require.NoError(t, err)
+
+		serverKMS, err := localkms.New("local-lock://key/server/master/key/", p)
+		require.NoError(t, err)
+
+		serverCrypto, err := tinkcrypto.New()
+		require.NoError(t, err)
+
+		srv := httptest.NewServer(keyserver.New(serverKMS, serverCrypto))
+		defer srv.Close()
+
+		transport, err := remotekms.NewHTTPTransport(srv.URL)
+		require.NoError(t, err)
+
+		aries, err := New(WithInboundTransport(&mockInboundTransport{}),
+			WithKMS(remotekms.Creator(transport)),
+			WithCrypto(remotecrypto.New(transport)))
+		require.NoError(t, err)
+		require.NotEmpty(t, aries)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		_, senderKey, err := ctx.KMS().CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		_, recKey, err := ctx.KMS().CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		msg := []byte("lorem ipsum")
+
+		// the legacy primary packer through the packager, then each packer
+		enc, err := ctx.Packager().PackMessage(&commontransport.Envelope{
+			Message:    msg,
+			FromVerKey: senderKey,
+			ToVerKeys:  []string{base58.Encode(recKey)},
+		})
+		require.NoError(t, err)
+
+		env, err := ctx.Packager().UnpackMessage(enc)
+		require.NoError(t, err)
+		require.Equal(t, msg, env.Message)
+		require.Equal(t, senderKey, env.FromVerKey)
+		require.Equal(t, recKey, env.ToVerKey)
+
+		for _, pckr := range ctx.Packers() {
+			enc, err = pckr.Pack(msg, senderKey, [][]byte{recKey})
+			require.NoError(t, err, pckr.EncodingType())
+
+			env, err = pckr.Unpack(enc)
+			require.NoError(t, err, pckr.EncodingType())
+			require.Equal(t, msg, env.Message)
+			require.Equal(t, recKey, env.ToVerKey)
+		}
+
+		err = aries.Close()
+		require.NoError(t, err)
+	})
+
 	t.Run("test crypto svc - with user provided crypto - Encrypt success", func(t *testing.T) {
 		// with custom crypto
 		aries, err := New(WithCrypto(&mockcrypto.Crypto{
@@ -391,17 +529,52 @@ func TestFramework(t *testing.T) {
 		require.NoError(t, err)
 	})
 
-	t.Run("test error from legacy kms svc", func(t *testing.T) {
-		// with custom legacy kms
-		_, err := New(WithInboundTransport(&mockInboundTransport{}),
-			WithLegacyKMS(func(ctx api.Provider) (api.CloseableKMS, error) {
-				return nil, fmt.Errorf("error from legacyKMS")
-			}))
+	t.Run("test legacy keystore - keys are imported into the kms", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
+		s := mem.NewProvider()
+
+		legacyKMS, err := legacykms.New(&mockprovider.Provider{StorageProviderValue: s})
+		require.NoError(t, err)
+
+		_, verKey, err := legacyKMS.CreateKeySet()
+		require.NoError(t, err)
+
+		aries, err := New(WithInboundTransport(&mockInboundTransport{}), WithStoreProvider(s))
+		require.NoError(t, err)
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		msg := []byte("test message")
+
+		sig, err := ctx.Signer().SignMessage(msg, verKey)
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(base58.Decode(verKey), msg, sig))
+
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test legacy keystore - migration error", func(t *testing.T) {
+		s := mem.NewProvider()
+
+		store, err := s.OpenStore(legacykms.KeyStoreNamespace)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put("key", []byte("{")))
+
+		_, err = New(WithInboundTransport(&mockInboundTransport{}), WithStoreProvider(s))
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "error from legacyKMS")
+		require.Contains(t, err.Error(), "legacy keystore migration failed")
 	})
 
 	t.Run("test new with explicitly passing noop secret lock svc as an option", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
 		// create noop secret lock service
 		s := &noop.NoLock{}
 
@@ -415,7 +588,30 @@ func TestFramework(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("test new with encrypted store provider", func(t *testing.T) {
+		masterKeyContent := make([]byte, sha256.Size)
+		_, err := rand.Read(masterKeyContent)
+		require.NoError(t, err)
+
+		s, err := locallock.NewService(strings.NewReader(base64.URLEncoding.EncodeToString(masterKeyContent)), nil)
+		require.NoError(t, err)
+
+		storeProvider, err := encrypted.NewProvider(mem.NewProvider(), s)
+		require.NoError(t, err)
+
+		a, err := New(WithStoreProvider(storeProvider), WithSecretLock(s))
+		require.NoError(t, err)
+		require.Equal(t, storeProvider, a.storeProvider)
+
+		err = a.Close()
+		require.NoError(t, err)
+	})
+
 	t.Run("test new with custom (unprotected master key) secret lock svc and with custom KMS", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
 		masterKeyFilePath := "masterKey_aries.txt"
 		tmpfile, err := ioutil.TempFile("", masterKeyFilePath)
 		require.NoError(t, err)
@@ -459,6 +655,10 @@ func TestFramework(t *testing.T) {
 	})
 
 	t.Run("test new with custom (protected) secret lock svc and with custom KMS", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
 		// pre steps (preparation), create a protected master key and store it in a local file
 		masterKeyFilePath := "masterKey_aries.txt"
 		tmpfile, err := ioutil.TempFile("", masterKeyFilePath)
@@ -555,6 +755,43 @@ func TestFramework(t *testing.T) {
 		require.Equal(t, s, aries.transientStoreProvider)
 	})
 
+	t.Run("test migrations - with default and user provided migrations", func(t *testing.T) {
+		path, cleanup := generateTempDir(t)
+		defer cleanup()
+		dbPath = path
+
+		s := mem.NewProvider()
+
+		var migrated bool
+
+		aries, err := New(WithInboundTransport(&mockInboundTransport{}), WithStoreProvider(s),
+			WithMigrations(migration.Migration{Store: "custom", Version: 1, Upgrade: func(ariesstorage.Store) error {
+				migrated = true
+				return nil
+			}}))
+		require.NoError(t, err)
+		require.True(t, migrated)
+
+		for store, expected := range map[string]int{"custom": 1, connection.Namespace: 2, peer.StoreNamespace: 1} {
+			version, err := migration.Version(s, store)
+			require.NoError(t, err)
+			require.Equal(t, expected, version)
+		}
+
+		require.NoError(t, aries.Close())
+	})
+
+	t.Run("test migrations - error", func(t *testing.T) {
+		_, err := New(WithInboundTransport(&mockInboundTransport{}),
+			WithStoreProvider(storage.NewMockStoreProvider()),
+			WithMigrations(migration.Migration{Store: "custom", Version: 1, Upgrade: func(ariesstorage.Store) error {
+				return fmt.Errorf("upgrade error")
+			}}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "store migration failed")
+		require.Contains(t, err.Error(), "upgrade error")
+	})
+
 	t.Run("test new with outbound transport service", func(t *testing.T) {
 		path, cleanup := generateTempDir(t)
 		defer cleanup()
@@ -611,6 +848,47 @@ func TestFramework(t *testing.T) {
 		require.Contains(t, err.Error(), "invalid transport return route option : "+transportReturnRoute)
 	})
 
+	t.Run("test key rotation - the peer DID documents are updated with the rotated keys", func(t *testing.T) {
+		aries, err := New(WithInboundTransport(&mockInboundTransport{}), WithStoreProvider(mem.NewProvider()),
+			WithServiceEndpoint("http://example.com"),
+			WithKeyRotation(100*time.Millisecond, rotation.WithInterval(10*time.Millisecond)))
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, aries.Close())
+		}()
+
+		ctx, err := aries.Context()
+		require.NoError(t, err)
+
+		_, err = ctx.Service(didupdate.DIDUpdate)
+		require.NoError(t, err)
+
+		doc, err := ctx.VDRIRegistry().Create("peer")
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			updated, e := ctx.VDRIRegistry().Resolve(doc.ID)
+			require.NoError(t, e)
+
+			return !bytes.Equal(doc.PublicKey[0].Value, updated.PublicKey[0].Value)
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("test key rotation - errors", func(t *testing.T) {
+		_, err := New(WithInboundTransport(&mockInboundTransport{}), WithStoreProvider(mem.NewProvider()),
+			WithKeyRotation(time.Hour), WithKMS(func(ctx kms.Provider) (kms.KeyManager, error) {
+				return &mockkms.KeyManager{}, nil
+			}))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "doesn't support the rotation of keys")
+
+		_, err = New(WithInboundTransport(&mockInboundTransport{}), WithStoreProvider(mem.NewProvider()),
+			WithKeyRotation(-time.Hour))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "key rotation max age must be positive")
+	})
+
 	t.Run("test message service provider option", func(t *testing.T) {
 		path, cleanup := generateTempDir(t)
 		defer cleanup()
@@ -9,6 +9,8 @@ package context
 import (
 	"fmt"
 
+	"github.com/btcsuite/btcutil/base58"
+
 	"github.com/hyperledger/aries-framework-go/pkg/crypto"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	commontransport "github.com/hyperledger/aries-framework-go/pkg/didcomm/common/transport"
@@ -18,7 +20,6 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/framework/aries/api"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
@@ -29,7 +30,6 @@ type Provider struct {
 	msgSvcProvider         api.MessageServiceProvider
 	storeProvider          storage.Provider
 	transientStoreProvider storage.Provider
-	legacyKMS              legacykms.KMS
 	kms                    kms.KeyManager
 	secretLock             secretlock.Service
 	crypto                 crypto.Crypto
@@ -95,11 +95,6 @@ func (p *Provider) Service(id string) (interface{}, error) {
 	return nil, api.ErrSvcNotFound
 }
 
-// LegacyKMS returns a legacyKMS service.
-func (p *Provider) LegacyKMS() legacykms.KeyManager {
-	return p.legacyKMS
-}
-
 // KMS returns a Key Management Service
 func (p *Provider) KMS() kms.KeyManager {
 	return p.kms
@@ -135,9 +130,9 @@ func (p *Provider) PrimaryPacker() packer.Packer {
 	return p.primaryPacker
 }
 
-// Signer returns a legacyKMS signing service.
-func (p *Provider) Signer() legacykms.Signer {
-	return p.legacyKMS
+// Signer returns a signing service, signing with the keys of the KMS.
+func (p *Provider) Signer() kms.Signer {
+	return kms.NewSigner(p.kms, p.crypto)
 }
 
 // ServiceEndpoint returns an service endpoint. This endpoint is used in Out-Of-Band messages,
@@ -154,20 +149,26 @@ func (p *Provider) RouterEndpoint() string {
 	return p.routerEndpoint
 }
 
-func (p *Provider) tryToHandle(svc service.InboundHandler, msg service.DIDCommMsgMap, myDID, theirDID string) error {
-	if err := p.messenger.HandleInbound(msg, myDID, theirDID); err != nil {
+func (p *Provider) tryToHandle(svc service.InboundHandler, msg service.DIDCommMsgMap,
+	envelope *commontransport.Envelope) error {
+	if err := p.messenger.HandleInbound(msg, envelope.ToDID, envelope.FromDID); err != nil {
 		return fmt.Errorf("messenger HandleInbound: %w", err)
 	}
 
-	_, err := svc.HandleInbound(msg, myDID, theirDID)
+	// the sender key is known only from the envelope, the metadata of the thread doesn't hold it
+	if len(envelope.FromVerKey) > 0 && msg.Metadata() != nil {
+		msg.Metadata()[service.SenderVerKeyMetadata] = base58.Encode(envelope.FromVerKey)
+	}
+
+	_, err := svc.HandleInbound(msg, envelope.ToDID, envelope.FromDID)
 
 	return err
 }
 
 // InboundMessageHandler return an inbound message handler.
 func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
-	return func(message []byte, myDID, theirDID string) error {
-		msg, err := service.ParseDIDCommMsgMap(message)
+	return func(envelope *commontransport.Envelope) error {
+		msg, err := service.ParseDIDCommMsgMap(envelope.Message)
 		if err != nil {
 			return err
 		}
@@ -175,7 +176,7 @@ func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
 		// find the service which accepts the message type
 		for _, svc := range p.services {
 			if svc.Accept(msg.Type()) {
-				return p.tryToHandle(svc, msg, myDID, theirDID)
+				return p.tryToHandle(svc, msg, envelope)
 			}
 		}
 
@@ -192,7 +193,7 @@ func (p *Provider) InboundMessageHandler() transport.InboundMessageHandler {
 			}
 
 			if svc.Accept(msg.Type(), h.Purpose) {
-				return p.tryToHandle(svc, msg, myDID, theirDID)
+				return p.tryToHandle(svc, msg, envelope)
 			}
 		}
 
@@ -276,14 +277,6 @@ func WithProtocolServices(services ...dispatcher.ProtocolService) ProviderOption
 	}
 }
 
-// WithLegacyKMS injects a legacy KMS service into the context.
-func WithLegacyKMS(w legacykms.KMS) ProviderOption {
-	return func(opts *Provider) error {
-		opts.legacyKMS = w
-		return nil
-	}
-}
-
 // WithKMS injects a kms service into the context
 func WithKMS(k kms.KeyManager) ProviderOption {
 	return func(opts *Provider) error {
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -29,7 +30,6 @@ import (
 	mocklock "github.com/hyperledger/aries-framework-go/pkg/internal/mock/secretlock"
 	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
 	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
-	mocklegacykms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
 	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
 )
@@ -107,42 +107,75 @@ func TestNewProvider(t *testing.T) {
 		inboundHandler := ctx.InboundMessageHandler()
 
 		// valid json and message type
-		err = inboundHandler([]byte(`
+		err = inboundHandler(&transport.Envelope{Message: []byte(`
 		{
 			"@frameworkID": "5678876542345",
 			"@type": "valid-message-type"
-		}`), "", "")
+		}`)})
 		require.NoError(t, err)
 
 		// invalid json
-		err = inboundHandler([]byte("invalid json"), "", "")
+		err = inboundHandler(&transport.Envelope{Message: []byte("invalid json")})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "invalid payload data format")
 
 		// invalid json
-		err = inboundHandler([]byte("invalid json"), "", "")
+		err = inboundHandler(&transport.Envelope{Message: []byte("invalid json")})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "invalid payload data format")
 
 		// no handlers
-		err = inboundHandler([]byte(`
+		err = inboundHandler(&transport.Envelope{Message: []byte(`
 		{
 			"@type": "invalid-message-type",
 			"label": "Bob"
-		}`), "", "")
+		}`)})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no message handlers found for the message type: invalid-message-type")
 
 		// valid json, message type but service handlers returns error
-		err = inboundHandler([]byte(`
+		err = inboundHandler(&transport.Envelope{Message: []byte(`
 		{
 			"label": "Carol",
 			"@type": "valid-message-type"
-		}`), "", "")
+		}`)})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "error handling the message")
 	})
 
+	t.Run("inbound message handler - sender key is in the message metadata", func(t *testing.T) {
+		messengerHandler := serviceMocks.NewMockMessengerHandler(ctrl)
+		messengerHandler.EXPECT().HandleInbound(gomock.Any(), "did1", "did2").Return(nil).Times(2)
+
+		var senderKeys []interface{}
+
+		ctx, err := New(WithProtocolServices(&mockdidexchange.MockDIDExchangeSvc{
+			AcceptFunc: func(msgType string) bool {
+				return msgType == "valid-message-type"
+			},
+			HandleFunc: func(msg service.DIDCommMsg) (string, error) {
+				senderKeys = append(senderKeys, msg.(service.DIDCommMsgMap).Metadata()[service.SenderVerKeyMetadata])
+
+				return uuid.New().String(), nil
+			},
+		}), WithMessengerHandler(messengerHandler))
+		require.NoError(t, err)
+
+		message := []byte(`{"@id": "1", "@type": "valid-message-type", "_internal_metadata": {"sender_verkey": "x"}}`)
+
+		err = ctx.InboundMessageHandler()(&transport.Envelope{
+			Message: message, FromVerKey: base58.Decode("FkYjEJ3xNQqP5rpDfCVDCpiJTXNWvTwqvP3LzCvZfXzs"),
+			ToDID: "did1", FromDID: "did2",
+		})
+		require.NoError(t, err)
+
+		// the metadata of the message received isn't trusted
+		err = ctx.InboundMessageHandler()(&transport.Envelope{Message: message, ToDID: "did1", FromDID: "did2"})
+		require.NoError(t, err)
+
+		require.Equal(t, []interface{}{"FkYjEJ3xNQqP5rpDfCVDCpiJTXNWvTwqvP3LzCvZfXzs", nil}, senderKeys)
+	})
+
 	t.Run("Messenger handle inbound error", func(t *testing.T) {
 		errTest := errors.New("test")
 
@@ -164,11 +197,11 @@ func TestNewProvider(t *testing.T) {
 		inboundHandler := ctx.InboundMessageHandler()
 
 		// valid json and message type
-		err = inboundHandler([]byte(`
+		err = inboundHandler(&transport.Envelope{Message: []byte(`
 		{
 			"@frameworkID": "5678876542345",
 			"@type": "valid-message-type"
-		}`), "", "")
+		}`)})
 
 		require.EqualError(t, errors.Unwrap(err), errTest.Error())
 	})
@@ -245,11 +278,11 @@ func TestNewProvider(t *testing.T) {
 
 		inboundHandler := prov.InboundMessageHandler()
 
-		err = inboundHandler([]byte(fmt.Sprintf(`
+		err = inboundHandler(&transport.Envelope{Message: []byte(fmt.Sprintf(`
 		{
 			"@frameworkID": "5678876542345",
 			"@type": "%s"
-		}`, sampleMsgType)), "did1", "did2")
+		}`, sampleMsgType)), ToDID: "did1", FromDID: "did2"})
 		require.NoError(t, err)
 
 		select {
@@ -260,9 +293,10 @@ func TestNewProvider(t *testing.T) {
 		}
 	})
 
-	t.Run("test new with legacyKMS and packager service", func(t *testing.T) {
+	t.Run("test new with kms, crypto and packager service", func(t *testing.T) {
 		prov, err := New(
-			WithLegacyKMS(&mocklegacykms.CloseableKMS{SignMessageValue: []byte("mockValue")}),
+			WithKMS(&mockkms.KeyManager{}),
+			WithCrypto(&mockcrypto.Crypto{SignValue: []byte("mockValue")}),
 			WithPackager(&mockpackager.Packager{PackValue: []byte("data")}),
 			WithPacker(
 				&mockdidcomm.MockAuthCrypt{
@@ -282,9 +316,6 @@ func TestNewProvider(t *testing.T) {
 		v, err := prov.Signer().SignMessage(nil, "")
 		require.NoError(t, err)
 		require.Equal(t, []byte("mockValue"), v)
-		index, err := prov.LegacyKMS().FindVerKey([]string{"non-existent"})
-		require.NoError(t, err)
-		require.Equal(t, 0, index)
 		v, err = prov.Packager().PackMessage(&transport.Envelope{})
 		require.NoError(t, err)
 		require.Equal(t, []byte("data"), v)
@@ -17,8 +17,9 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/didexchange"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
@@ -188,8 +189,8 @@ func (p *MockProvider) TransientStorageProvider() storage.Provider {
 }
 
 // Signer is mock signer for DID exchange service
-func (p *MockProvider) Signer() legacykms.Signer {
-	return &mockkms.CloseableKMS{}
+func (p *MockProvider) Signer() kms.Signer {
+	return kms.NewSigner(&mockkms.KeyManager{}, &mockcrypto.Crypto{})
 }
 
 // VDRIRegistry is mock vdri registry
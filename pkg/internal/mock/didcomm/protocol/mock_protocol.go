@@ -13,8 +13,9 @@ import (
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	mockdispatcher "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/dispatcher"
 	mockservice "github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/service"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
@@ -27,7 +28,7 @@ type MockProvider struct {
 	CustomVDRI             vdriapi.Registry
 	CustomOutbound         *mockdispatcher.MockOutbound
 	CustomMessenger        *mockservice.MockMessenger
-	CustomKMS              *mockkms.CloseableKMS
+	CustomKMS              *mockkms.KeyManager
 	ServiceErr             error
 	ServiceMap             map[string]interface{}
 	InboundMsgHandler      transport.InboundMessageHandler
@@ -62,8 +63,8 @@ func (p *MockProvider) TransientStorageProvider() storage.Provider {
 }
 
 // Signer is mock signer for DID exchange service
-func (p *MockProvider) Signer() legacykms.Signer {
-	return &mockkms.CloseableKMS{}
+func (p *MockProvider) Signer() kms.Signer {
+	return kms.NewSigner(&mockkms.KeyManager{}, &mockcrypto.Crypto{})
 }
 
 // VDRIRegistry is mock vdri registry
@@ -75,13 +76,13 @@ func (p *MockProvider) VDRIRegistry() vdriapi.Registry {
 	return &mockvdri.MockVDRIRegistry{}
 }
 
-// LegacyKMS returns mock LegacyKMS
-func (p *MockProvider) LegacyKMS() legacykms.KeyManager {
+// KMS returns mock KMS
+func (p *MockProvider) KMS() kms.KeyManager {
 	if p.CustomKMS != nil {
 		return p.CustomKMS
 	}
 
-	return &mockkms.CloseableKMS{}
+	return &mockkms.KeyManager{}
 }
 
 // Service return service
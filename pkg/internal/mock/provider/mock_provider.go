@@ -11,7 +11,6 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/packer"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
@@ -21,7 +20,7 @@ type Provider struct {
 	ServiceErr                    error
 	ServiceMap                    map[string]interface{}
 	KMSValue                      kms.KeyManager
-	LegacyKMSValue                legacykms.KMS
+	SignerValue                   kms.Signer
 	ServiceEndpointValue          string
 	StorageProviderValue          storage.Provider
 	TransientStorageProviderValue storage.Provider
@@ -45,11 +44,6 @@ func (p *Provider) Service(id string) (interface{}, error) {
 	return p.ServiceValue, nil
 }
 
-// LegacyKMS returns a LegacyKMS instance
-func (p *Provider) LegacyKMS() legacykms.KeyManager {
-	return p.LegacyKMSValue
-}
-
 // KMS returns a kms instance
 func (p *Provider) KMS() kms.KeyManager {
 	return p.KMSValue
@@ -100,7 +94,7 @@ func (p *Provider) VDRIRegistry() vdriapi.Registry {
 	return p.VDRIRegistryValue
 }
 
-// Signer returns a legacyKMS signing service.
-func (p *Provider) Signer() legacykms.Signer {
-	return p.LegacyKMSValue
+// Signer returns a signing service.
+func (p *Provider) Signer() kms.Signer {
+	return p.SignerValue
 }
@@ -7,10 +7,18 @@
 package kms
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
+// publicKeyIDLen is the number of bytes of the public key hash used as keyID
+const publicKeyIDLen = 10
+
 // KeyManager manages keys and their storage for the aries framework
 type KeyManager interface {
 	// Create a new key/keyset/key handle for the type kt
@@ -20,6 +28,104 @@ type KeyManager interface {
 	// Rotate a key referenced by keyID and return a new handle of a keyset including old key and
 	// new key with type kt. It also returns the updated keyID as the first return value
 	Rotate(kt KeyType, keyID string) (string, interface{}, error)
+	// List returns the metadata of the keys managed by the KeyManager
+	List() ([]*KeyMetadata, error)
+	// GetMetadata returns the metadata of the key referenced by keyID
+	GetMetadata(keyID string) (*KeyMetadata, error)
+	// UpdateMetadata records what the key referenced by keyID is used for
+	UpdateMetadata(keyID string, opts ...MetadataOpts) error
+	// Delete the key referenced by keyID, e.g. when it is compromised
+	Delete(keyID string) error
+	// ExportPubKeyBytes returns the raw bytes of the public key of the asymmetric key referenced by keyID
+	ExportPubKeyBytes(keyID string) ([]byte, error)
+	// CreateAndExportPubKeyBytes creates a new asymmetric key of type kt and returns its keyID along with the raw
+	// bytes of its public key
+	CreateAndExportPubKeyBytes(kt KeyType) (string, []byte, error)
+	// ImportPrivateKey stores privKey as a key of type kt and returns its keyID and key handle
+	ImportPrivateKey(privKey interface{}, kt KeyType, opts ...PrivateKeyOpts) (string, interface{}, error)
+}
+
+// ErrKeyNotFound is returned when a key referenced by its keyID doesn't exist.
+var ErrKeyNotFound = errors.New("key not found")
+
+// KeyMetadata describes a key managed by a KeyManager.
+type KeyMetadata struct {
+	ID      string    `json:"id"`
+	Type    KeyType   `json:"type,omitempty"`
+	Created time.Time `json:"created"`
+	// Purpose tells what the key is used for, e.g. "authentication" or "assertionMethod"
+	Purpose string `json:"purpose,omitempty"`
+	// DID and VerificationMethod reference the DID document verification method of the key
+	DID                string `json:"did,omitempty"`
+	VerificationMethod string `json:"verificationMethod,omitempty"`
+	// PreviousKeyIDs are the IDs the key had before it was rotated, oldest first
+	PreviousKeyIDs []string `json:"previousKeyIDs,omitempty"`
+	// PreviousVersions is the number of versions the rotated key keeps to decrypt and verify the data of its last
+	// PreviousKeyIDs
+	PreviousVersions int `json:"previousVersions,omitempty"`
+	// PendingDIDUpdate is the public key (base58) the key had before its last rotation, as long as the DID document
+	// referencing the key isn't updated with its new public key
+	PendingDIDUpdate string `json:"pendingDIDUpdate,omitempty"`
+	// Policy restricts the usage of the key, UsageCount is the number of operations it was used for if the policy
+	// limits its usage
+	Policy     *KeyPolicy `json:"policy,omitempty"`
+	UsageCount int        `json:"usageCount,omitempty"`
+}
+
+// MetadataOpts are the options of KeyManager.UpdateMetadata.
+type MetadataOpts func(md *KeyMetadata)
+
+// WithPurpose option sets what a key is used for.
+func WithPurpose(purpose string) MetadataOpts {
+	return func(md *KeyMetadata) {
+		md.Purpose = purpose
+	}
+}
+
+// WithDID option associates a key with the verification method of a DID document.
+func WithDID(did, verificationMethod string) MetadataOpts {
+	return func(md *KeyMetadata) {
+		md.DID = did
+		md.VerificationMethod = verificationMethod
+	}
+}
+
+// WithPendingDIDUpdate option records that the DID document of a key still references its previous public key
+// prevPubKey (base58), an empty prevPubKey records that the DID document is up to date.
+func WithPendingDIDUpdate(prevPubKey string) MetadataOpts {
+	return func(md *KeyMetadata) {
+		md.PendingDIDUpdate = prevPubKey
+	}
+}
+
+// WithPolicy option sets the policy restricting the usage of a key, a nil policy removes it.
+func WithPolicy(policy *KeyPolicy) MetadataOpts {
+	return func(md *KeyMetadata) {
+		md.Policy = policy
+	}
+}
+
+// PrivateKeyOptions holds the options of KeyManager.ImportPrivateKey.
+type PrivateKeyOptions struct {
+	KeyID string
+}
+
+// PrivateKeyOpts are the import private key options.
+type PrivateKeyOpts func(opts *PrivateKeyOptions)
+
+// WithKeyID option is for importing a private key with a specified KeyID.
+func WithKeyID(keyID string) PrivateKeyOpts {
+	return func(opts *PrivateKeyOptions) {
+		opts.KeyID = keyID
+	}
+}
+
+// PublicKeyID returns the keyID a KeyManager gives to the asymmetric key with the public key pubKey, so that keys
+// referenced by their public key only (eg DID verification keys) can be found in the KeyManager.
+func PublicKeyID(pubKey []byte) string {
+	h := sha256.Sum256(pubKey)
+
+	return hex.EncodeToString(h[:publicKeyIDLen])
 }
 
 // Provider for KeyManager builder/constructor
@@ -42,6 +148,12 @@ const (
 	ChaCha20Poly1305 = "ChaCha20Poly1305"
 	// XChaCha20Poly1305 key type value
 	XChaCha20Poly1305 = "XChaCha20Poly1305"
+	// AES128GCMHKDF4KB streaming AEAD key type value
+	AES128GCMHKDF4KB = "AES128GCMHKDF4KB"
+	// AES256GCMHKDF4KB streaming AEAD key type value
+	AES256GCMHKDF4KB = "AES256GCMHKDF4KB"
+	// AES256GCMHKDF1MB streaming AEAD key type value
+	AES256GCMHKDF1MB = "AES256GCMHKDF1MB"
 	// ECDSAP256DER key type value
 	ECDSAP256DER = "ECDSAP256DER"
 	// ECDSAP384DER key type value
@@ -62,6 +174,18 @@ const (
 	HMACSHA256Tag256 = "HMACSHA256Tag256"
 	// ECDHES256AES256GCM key type value
 	ECDHES256AES256GCM = "ECDHES256AES256GCM"
+	// ECDHES256XChaCha20Poly1305 key type value
+	ECDHES256XChaCha20Poly1305 = "ECDHES256XChaCha20Poly1305"
+	// ECDHES256ChaCha20Poly1305 key type value
+	ECDHES256ChaCha20Poly1305 = "ECDHES256ChaCha20Poly1305"
+	// ECDSASecp256k1DER key type value
+	ECDSASecp256k1DER = "ECDSASecp256k1DER"
+	// ECDSASecp256k1IEEEP1363 key type value
+	ECDSASecp256k1IEEEP1363 = "ECDSASecp256k1IEEEP1363"
+	// X25519 key type value
+	X25519 = "X25519"
+	// BLS12381G2 key type value
+	BLS12381G2 = "BLS12381G2"
 )
 
 // KeyType represents a key type supported by the KMS
@@ -78,6 +202,12 @@ const (
 	ChaCha20Poly1305Type = KeyType(ChaCha20Poly1305)
 	// XChaCha20Poly1305Type key type value
 	XChaCha20Poly1305Type = KeyType(XChaCha20Poly1305)
+	// AES128GCMHKDF4KBType streaming AEAD key type value, AES-128-GCM segments of 4KB with keys derived by HKDF
+	AES128GCMHKDF4KBType = KeyType(AES128GCMHKDF4KB)
+	// AES256GCMHKDF4KBType streaming AEAD key type value, AES-256-GCM segments of 4KB with keys derived by HKDF
+	AES256GCMHKDF4KBType = KeyType(AES256GCMHKDF4KB)
+	// AES256GCMHKDF1MBType streaming AEAD key type value, AES-256-GCM segments of 1MB with keys derived by HKDF
+	AES256GCMHKDF1MBType = KeyType(AES256GCMHKDF1MB)
 	// ECDSAP256TypeDER key type value
 	ECDSAP256TypeDER = KeyType(ECDSAP256DER)
 	// ECDSAP384TypeDER key type value
@@ -98,4 +228,16 @@ const (
 	HMACSHA256Tag256Type = KeyType(HMACSHA256Tag256)
 	// ECDHES256AES256GCMType key type value
 	ECDHES256AES256GCMType = KeyType(ECDHES256AES256GCM)
+	// ECDHES256XChaCha20Poly1305Type key type value
+	ECDHES256XChaCha20Poly1305Type = KeyType(ECDHES256XChaCha20Poly1305)
+	// ECDHES256ChaCha20Poly1305Type key type value
+	ECDHES256ChaCha20Poly1305Type = KeyType(ECDHES256ChaCha20Poly1305)
+	// ECDSASecp256k1TypeDER key type value
+	ECDSASecp256k1TypeDER = KeyType(ECDSASecp256k1DER)
+	// ECDSASecp256k1TypeIEEEP1363 key type value
+	ECDSASecp256k1TypeIEEEP1363 = KeyType(ECDSASecp256k1IEEEP1363)
+	// X25519Type key type value
+	X25519Type = KeyType(X25519)
+	// BLS12381G2Type BBS+ key type value
+	BLS12381G2Type = KeyType(BLS12381G2)
 )
@@ -15,7 +15,7 @@ type KMS interface {
 	Signer
 }
 
-// KeyManager interface provides key management operations (create, find, get, derive, etc.)
+// KeyManager interface provides key management operations (create, find, get, etc.)
 type KeyManager interface {
 	KeyConverter
 
@@ -27,16 +27,6 @@ type KeyManager interface {
 	// error: error
 	CreateKeySet() (string, string, error)
 
-	// DeriveKEK will derive an ephemeral symmetric key (kek) using a private from key fetched from
-	// from the LegacyKMS corresponding to fromPubKey and derived with toPubKey.
-	//
-	// This function assumes both fromPubKey and toPubKey to be on curve25519.
-	//
-	// returns:
-	// 		kek []byte the key encryption key used to decrypt a cek (a shared key)
-	//		error in case of errors
-	DeriveKEK(alg, apu, fromPubKey, toPubKey []byte) ([]byte, error)
-
 	// FindVerKey will search the LegacyKMS to find stored keys that match any of candidateKeys and
 	// 		return the index of the first match
 	// returns:
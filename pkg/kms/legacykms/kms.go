@@ -14,7 +14,6 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/btcutil/base58"
-	chacha "golang.org/x/crypto/chacha20poly1305"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/signature/suite/ed25519signature2018"
@@ -199,31 +198,6 @@ func (w *BaseKMS) getKeyPairSet(verKey string) (*cryptoutil.MessagingKeys, error
 	return &key, nil
 }
 
-// DeriveKEK will derive an ephemeral symmetric key (kek) using a private key fetched from
-// the LegacyKMS corresponding to fromPubKey and derived with toPubKey
-// This implementation is for curve 25519 only
-func (w *BaseKMS) DeriveKEK(alg, apu, fromPubKey, toPubKey []byte) ([]byte, error) { // nolint:lll
-	if fromPubKey == nil || toPubKey == nil {
-		return nil, cryptoutil.ErrInvalidKey
-	}
-
-	fromPrivKey := new([chacha.KeySize]byte)
-	copy(fromPrivKey[:], fromPubKey)
-
-	// get key pairs combo from LegacyKMS store
-	kpc, err := w.getKeyPairSet(base58.Encode(fromPubKey))
-	if err != nil {
-		return nil, fmt.Errorf("failed from getKeyPairSet: %w", err)
-	}
-
-	copy(fromPrivKey[:], kpc.EncKeyPair.Priv)
-
-	toKey := new([chacha.KeySize]byte)
-	copy(toKey[:], toPubKey)
-
-	return cryptoutil.Derive25519KEK(alg, apu, fromPrivKey, toKey)
-}
-
 // FindVerKey selects a signing key which is present in candidateKeys that is present in the LegacyKMS
 func (w *BaseKMS) FindVerKey(candidateKeys []string) (int, error) {
 	for i, key := range candidateKeys {
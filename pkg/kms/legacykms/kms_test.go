@@ -202,74 +202,6 @@ func TestBaseKMS_ConvertToEncryptionKey(t *testing.T) {
 	})
 }
 
-func TestBaseKMS_DeriveKEK(t *testing.T) {
-	pk32, sk32, err := box.GenerateKey(rand.Reader)
-	require.NoError(t, err)
-
-	kp := cryptoutil.KeyPair{Pub: pk32[:], Priv: sk32[:]}
-
-	kpCombo := &cryptoutil.MessagingKeys{
-		EncKeyPair: &cryptoutil.EncKeyPair{
-			KeyPair: kp,
-			Alg:     cryptoutil.Curve25519,
-		},
-		SigKeyPair: nil,
-	}
-	kpm, err := json.Marshal(kpCombo)
-	require.NoError(t, err)
-
-	pk32a, _, err := box.GenerateKey(rand.Reader)
-	k, err := New(newMockKMSProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
-		Store: map[string][]byte{
-			base58.Encode(pk32[:]): kpm,
-		},
-	}}))
-
-	t.Run("test success", func(t *testing.T) {
-		// test DeriveKEK from LegacyKMS where fromKey is a public key (private fromKey will be fetched from the LegacyKMS)
-		require.NoError(t, err)
-		kek, e := k.DeriveKEK(nil, nil, pk32[:], pk32a[:])
-		require.NoError(t, e)
-		require.NotEmpty(t, kek)
-
-		// test Derive25519KEK from the util function where fromKey is a private key
-		kek, e = cryptoutil.Derive25519KEK(nil, nil, sk32, pk32a)
-		require.NoError(t, e)
-		require.NotEmpty(t, kek)
-	})
-
-	t.Run("test failure fromKey empty and toKey not empty", func(t *testing.T) {
-		// test DeriveKEK from LegacyKMS where fromKey is a public key (private fromKey will be fetched from the LegacyKMS)
-		kek, e := k.DeriveKEK(nil, nil, nil, pk32a[:])
-		require.EqualError(t, e, cryptoutil.ErrInvalidKey.Error())
-		require.Empty(t, kek)
-
-		// test Derive25519KEK from the util function where fromKey is a private key
-		kek, e = cryptoutil.Derive25519KEK(nil, nil, nil, pk32a)
-		require.EqualError(t, e, cryptoutil.ErrInvalidKey.Error())
-		require.Empty(t, kek)
-	})
-
-	t.Run("test failure fromKey not empty and toKey empty", func(t *testing.T) {
-		// test DeriveKEK from LegacyKMS where fromKey is a public key (private fromKey will be fetched from the LegacyKMS)
-		kek, e := k.DeriveKEK(nil, nil, pk32[:], nil)
-		require.EqualError(t, e, cryptoutil.ErrInvalidKey.Error())
-		require.Empty(t, kek)
-
-		// test Derive25519KEK from the util function where fromKey is a private key
-		kek, e = cryptoutil.Derive25519KEK(nil, nil, sk32, nil)
-		require.EqualError(t, e, cryptoutil.ErrInvalidKey.Error())
-		require.Empty(t, kek)
-	})
-
-	t.Run("test failure fromPubKey not found in LegacyKMS", func(t *testing.T) {
-		// test DeriveKEK from LegacyKMS where fromKey is a public key (private fromKey will be fetched from the LegacyKMS)
-		kek, e := k.DeriveKEK(nil, nil, pk32a[:], pk32[:])
-		require.EqualError(t, e, "failed from getKeyPairSet: "+cryptoutil.ErrKeyNotFound.Error())
-		require.Empty(t, kek)
-	})
-}
-
 func TestBaseKMS_FindVerKey(t *testing.T) {
 	pk1, sk1, err := box.GenerateKey(rand.Reader)
 	require.NoError(t, err)
@@ -0,0 +1,71 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/internal/cryptoutil"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
+)
+
+// Migrations returns the schema migrations of the legacy keystore, refer migration.Run(). They import the
+// legacy signature keypairs into km, so that the DIDs created with them can still be used.
+func Migrations(km kms.KeyManager) []migration.Migration {
+	return []migration.Migration{{
+		Store:       KeyStoreNamespace,
+		Version:     1,
+		Description: "import legacy keypairs into the KMS",
+		Upgrade: func(store storage.Store) error {
+			return importKeyPairs(store, km)
+		},
+	}}
+}
+
+// importKeyPairs imports the ED25519 keypairs of store into km, with the kms.PublicKeyID of their public key as
+// keyID. Keypairs already in km are skipped since every keypair is stored twice in the legacy keystore.
+func importKeyPairs(store storage.Store, km kms.KeyManager) error {
+	itr := store.Iterator(" ", "~"+storage.EndKeySuffix)
+	defer itr.Release()
+
+	for itr.Next() {
+		var kpCombo cryptoutil.MessagingKeys
+
+		if err := json.Unmarshal(itr.Value(), &kpCombo); err != nil {
+			return fmt.Errorf("unmarshal legacy keypair: %w", err)
+		}
+
+		if kpCombo.SigKeyPair == nil {
+			continue
+		}
+
+		_, err := km.Get(kms.PublicKeyID(kpCombo.SigKeyPair.Pub))
+		if err == nil {
+			continue
+		}
+
+		if len(kpCombo.SigKeyPair.Priv) != ed25519.PrivateKeySize {
+			return errors.New("invalid legacy ED25519 private key")
+		}
+
+		_, _, err = km.ImportPrivateKey(ed25519.PrivateKey(kpCombo.SigKeyPair.Priv), kms.ED25519Type)
+		if err != nil {
+			return fmt.Errorf("import legacy keypair: %w", err)
+		}
+	}
+
+	if err := itr.Error(); err != nil {
+		return fmt.Errorf("read legacy keypairs: %w", err)
+	}
+
+	return nil
+}
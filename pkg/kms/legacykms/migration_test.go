@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package legacykms
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
+)
+
+func TestMigrations(t *testing.T) {
+	newLocalKMS := func(t *testing.T) *localkms.LocalKMS {
+		km, err := localkms.New("local-lock://test/key/uri",
+			mockkms.NewProvider(mockstorage.NewMockStoreProvider(), &noop.NoLock{}))
+		require.NoError(t, err)
+
+		return km
+	}
+
+	t.Run("test legacy keypairs are imported", func(t *testing.T) {
+		storeProvider := mem.NewProvider()
+
+		legacyKMS, err := New(&mockprovider.Provider{StorageProviderValue: storeProvider})
+		require.NoError(t, err)
+
+		_, verKey1, err := legacyKMS.CreateKeySet()
+		require.NoError(t, err)
+
+		_, verKey2, err := legacyKMS.CreateKeySet()
+		require.NoError(t, err)
+
+		km := newLocalKMS(t)
+
+		require.NoError(t, migration.Run(storeProvider, nil, Migrations(km)...))
+
+		keys, err := km.List()
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+
+		c, err := tinkcrypto.New()
+		require.NoError(t, err)
+
+		for _, verKey := range []string{verKey1, verKey2} {
+			msg := []byte("test message")
+
+			sig, e := kms.NewSigner(km, c).SignMessage(msg, verKey)
+			require.NoError(t, e)
+			require.True(t, ed25519.Verify(base58.Decode(verKey), msg, sig))
+		}
+
+		// the keystore is migrated only once
+		_, _, err = legacyKMS.CreateKeySet()
+		require.NoError(t, err)
+
+		require.NoError(t, migration.Run(storeProvider, nil, Migrations(km)...))
+
+		keys, err = km.List()
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+	})
+
+	t.Run("test invalid legacy keypair", func(t *testing.T) {
+		store, err := mem.NewProvider().OpenStore(KeyStoreNamespace)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put("key", []byte("{")))
+
+		err = importKeyPairs(store, newLocalKMS(t))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal legacy keypair")
+
+		require.NoError(t, store.Put("key", []byte(`{"sigkeypair":{"keypair":{"pub":"AQI=","priv":"AQI="}}}`)))
+
+		err = importKeyPairs(store, newLocalKMS(t))
+		require.EqualError(t, err, "invalid legacy ED25519 private key")
+
+		require.NoError(t, store.Put("key", []byte(`{}`)))
+		require.NoError(t, importKeyPairs(store, newLocalKMS(t)))
+	})
+
+	t.Run("test keystore errors", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte), ErrItr: fmt.Errorf("iterator error")}
+
+		err := importKeyPairs(store, newLocalKMS(t))
+		require.EqualError(t, err, "read legacy keypairs: iterator error")
+
+		legacyKMS, err := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+		require.NoError(t, err)
+
+		_, _, err = legacyKMS.CreateKeySet()
+		require.NoError(t, err)
+
+		err = importKeyPairs(legacyKMS.keystore, &mockkms.KeyManager{
+			GetKeyErr:           fmt.Errorf("get error"),
+			ImportPrivateKeyErr: fmt.Errorf("import error"),
+		})
+		require.EqualError(t, err, "import legacy keypair: import error")
+	})
+}
@@ -10,18 +10,27 @@ import (
 	"bytes"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"errors"
 	"fmt"
+	"sync"
 
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/insecurecleartextkeyset"
 	"github.com/google/tink/go/keyset"
 	"github.com/google/tink/go/mac"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
 	"github.com/google/tink/go/signature"
+	"github.com/google/tink/go/streamingaead"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs"
 	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/composite/ecdhes"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/secp256k1"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/x25519"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms/internal/keywrapper"
 	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
@@ -35,6 +44,8 @@ const (
 	ecdsaPrivateKeyTypeURL = "type.googleapis.com/google.crypto.tink.EcdsaPrivateKey"
 )
 
+var logger = log.New("aries-framework/kms/localkms")
+
 // LocalKMS implements kms.KeyManager to provide key management capabilities using a local db.
 // It uses an underlying secret lock service (default local secretLock) to wrap (encrypt) keys
 // prior to storing them.
@@ -42,34 +53,55 @@ type LocalKMS struct {
 	secretLock       secretlock.Service
 	masterKeyURI     string
 	store            storage.Store
+	metadataStore    storage.Store
 	masterKeyEnvAEAD *aead.KMSEnvelopeAEAD
+	policyMutex      sync.Mutex
+	// masterKeyMutex is locked for writing while the master key is rotated, and for reading while keysets are
+	// decrypted or encrypted and stored with the master key.
+	masterKeyMutex sync.RWMutex
 }
 
 // New will create a new (local) KMS service
 func New(masterKeyURI string, p kms.Provider) (*LocalKMS, error) {
-	store, err := p.StorageProvider().OpenStore(Namespace)
+	storageProvider := p.StorageProvider()
+
+	store, err := storageProvider.OpenStore(Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ceate local kms: %w", err)
+	}
+
+	metadataStore, err := storageProvider.OpenStore(MetadataNamespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to ceate local kms: %w", err)
 	}
 
 	secretLock := p.SecretLock()
 
-	kw, err := keywrapper.New(secretLock, masterKeyURI)
+	masterKeyEnvAEAD, err := newMasterKeyEnvAEAD(secretLock, masterKeyURI)
 	if err != nil {
 		return nil, err
 	}
 
-	// create a KMSEnvelopeAEAD instance to wrap/unwrap keys managed by LocalKMS
-	masterKeyEnvAEAD := aead.NewKMSEnvelopeAEAD(*aead.AES256GCMKeyTemplate(), kw)
-
 	return &LocalKMS{
 			store:            store,
+			metadataStore:    metadataStore,
 			secretLock:       secretLock,
 			masterKeyURI:     masterKeyURI,
 			masterKeyEnvAEAD: masterKeyEnvAEAD},
 		nil
 }
 
+// newMasterKeyEnvAEAD creates a KMSEnvelopeAEAD instance to wrap/unwrap keys managed by LocalKMS with the master key
+// masterKeyURI of secretLock.
+func newMasterKeyEnvAEAD(secretLock secretlock.Service, masterKeyURI string) (*aead.KMSEnvelopeAEAD, error) {
+	kw, err := keywrapper.New(secretLock, masterKeyURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.NewKMSEnvelopeAEAD(*aead.AES256GCMKeyTemplate(), kw), nil
+}
+
 // Create a new key/keyset for key type kt, store it and return its stored ID and key handle
 func (l *LocalKMS) Create(kt kms.KeyType) (string, interface{}, error) {
 	if kt == "" {
@@ -91,21 +123,64 @@ func (l *LocalKMS) Create(kt kms.KeyType) (string, interface{}, error) {
 		return "", nil, err
 	}
 
+	err = l.recordNewKey(kID, kt, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
 	return kID, kh, nil
 }
 
-// Get key handle for the given keyID
+// Get key handle for the given keyID. The key handle of a key with a policy is a kms.PolicyKeyHandle, the crypto
+// operations must be authorized by the key policy.
+// The previous IDs of a rotated key get its updated handle until its previous versions are removed, the primary key
+// of the handle being their version so that they keep encrypting and signing with it.
 func (l *LocalKMS) Get(keyID string) (interface{}, error) {
-	return l.getKeySet(keyID)
+	kh, err := l.getKeySet(keyID)
+	if errors.Is(err, kms.ErrKeyNotFound) {
+		rotatedID, e := l.rotatedKeyID(keyID)
+		if e != nil {
+			return nil, err
+		}
+
+		kh, err = l.getKeySet(rotatedID)
+		if err == nil {
+			kh, err = previousVersionHandle(kh, keyID)
+		}
+
+		keyID = rotatedID
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := l.GetMetadata(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if md.Policy != nil {
+		return &policyKeyHandle{kh: kh, keyID: keyID, kms: l}, nil
+	}
+
+	return kh, nil
 }
 
-// Rotate a key referenced by keyID and return its updated handle
+// Rotate a key referenced by keyID and return its updated handle. The metadata of the rotated key keeps its usage,
+// its policy and the IDs it had before. The updated keyset keeps the previous versions of the key to decrypt and
+// verify the data they encrypted and signed, until they are removed with RemovePreviousVersions.
 func (l *LocalKMS) Rotate(kt kms.KeyType, keyID string) (string, interface{}, error) {
 	kh, err := l.getKeySet(keyID)
 	if err != nil {
 		return "", nil, err
 	}
 
+	md, err := l.GetMetadata(keyID)
+	if err != nil {
+		return "", nil, err
+	}
+
 	keyTemplate, err := getKeyTemplate(kt)
 	if err != nil {
 		return "", nil, err
@@ -124,19 +199,113 @@ func (l *LocalKMS) Rotate(kt kms.KeyType, keyID string) (string, interface{}, er
 		return "", nil, err
 	}
 
-	err = l.store.Delete(keyID)
+	// the rotated key is recorded before the key it replaces is removed, so that a failed rotation keeps the key
+	newID, err := l.storeKeySet(updatedKH)
 	if err != nil {
 		return "", nil, err
 	}
 
-	newID, err := l.storeKeySet(updatedKH)
+	err = l.recordNewKey(newID, kt, md)
 	if err != nil {
 		return "", nil, err
 	}
 
+	l.removeReplacedKey(keyID, newID)
+
+	if md.Policy != nil {
+		return newID, &policyKeyHandle{kh: updatedKH, keyID: newID, kms: l}, nil
+	}
+
 	return newID, updatedKH, nil
 }
 
+// RemovePreviousVersions removes the previous versions kept by the rotated key referenced by keyID, the data they
+// encrypted and signed can't be decrypted and verified with the key anymore.
+func (l *LocalKMS) RemovePreviousVersions(keyID string) error {
+	md, err := l.GetMetadata(keyID)
+	if err != nil {
+		return err
+	}
+
+	if md.PreviousVersions == 0 {
+		return nil
+	}
+
+	kh, err := l.getKeySet(keyID)
+	if err != nil {
+		return err
+	}
+
+	primaryKH, err := primaryKeyHandle(kh)
+	if err != nil {
+		return fmt.Errorf("failed to remove previous key versions: %w", err)
+	}
+
+	err = l.replaceKeySet(keyID, primaryKH)
+	if err != nil {
+		return fmt.Errorf("failed to remove previous key versions: %w", err)
+	}
+
+	md.PreviousVersions = 0
+
+	return l.saveMetadata(md)
+}
+
+// primaryKeyHandle returns the handle of a keyset holding the primary key of kh only.
+func primaryKeyHandle(kh *keyset.Handle) (*keyset.Handle, error) {
+	rw := &keyset.MemReaderWriter{}
+
+	err := insecurecleartextkeyset.Write(kh, rw)
+	if err != nil {
+		return nil, err
+	}
+
+	ks := rw.Keyset
+
+	for _, key := range ks.Key {
+		if key.KeyId == ks.PrimaryKeyId {
+			ks.Key = []*tinkpb.Keyset_Key{key}
+
+			break
+		}
+	}
+
+	return insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks})
+}
+
+// previousVersionHandle returns kh with the version whose public key has the ID keyID as primary key. kh is returned
+// as is when no version has this ID, eg for symmetric keys.
+func previousVersionHandle(kh *keyset.Handle, keyID string) (*keyset.Handle, error) {
+	rw := &keyset.MemReaderWriter{}
+
+	err := insecurecleartextkeyset.Write(kh, rw)
+	if err != nil {
+		return nil, err
+	}
+
+	ks := rw.Keyset
+
+	for _, key := range ks.Key {
+		versionKH, e := insecurecleartextkeyset.Read(&keyset.MemReaderWriter{
+			Keyset: &tinkpb.Keyset{PrimaryKeyId: key.KeyId, Key: []*tinkpb.Keyset_Key{key}},
+		})
+		if e != nil {
+			return nil, e
+		}
+
+		pubKey, e := exportPubKeyBytes(versionKH)
+		if e != nil || kms.PublicKeyID(pubKey) != keyID {
+			continue
+		}
+
+		ks.PrimaryKeyId = key.KeyId
+
+		return insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks})
+	}
+
+	return kh, nil
+}
+
 // nolint:gocyclo
 func getKeyTemplate(keyType kms.KeyType) (*tinkpb.KeyTemplate, error) {
 	switch keyType {
@@ -151,6 +320,12 @@ func getKeyTemplate(keyType kms.KeyType) (*tinkpb.KeyTemplate, error) {
 		return aead.ChaCha20Poly1305KeyTemplate(), nil
 	case kms.XChaCha20Poly1305Type:
 		return aead.XChaCha20Poly1305KeyTemplate(), nil
+	case kms.AES128GCMHKDF4KBType:
+		return streamingaead.AES128GCMHKDF4KBKeyTemplate(), nil
+	case kms.AES256GCMHKDF4KBType:
+		return streamingaead.AES256GCMHKDF4KBKeyTemplate(), nil
+	case kms.AES256GCMHKDF1MBType:
+		return streamingaead.AES256GCMHKDF1MBKeyTemplate(), nil
 	case kms.ECDSAP256TypeDER:
 		return signature.ECDSAP256KeyWithoutPrefixTemplate(), nil
 	case kms.ECDSAP384TypeDER:
@@ -170,6 +345,18 @@ func getKeyTemplate(keyType kms.KeyType) (*tinkpb.KeyTemplate, error) {
 		return mac.HMACSHA256Tag256KeyTemplate(), nil
 	case kms.ECDHES256AES256GCMType:
 		return ecdhes.ECDHES256KWAES256GCMKeyTemplate(), nil
+	case kms.ECDHES256XChaCha20Poly1305Type:
+		return ecdhes.ECDHES256KWXChaCha20Poly1305KeyTemplate(), nil
+	case kms.ECDHES256ChaCha20Poly1305Type:
+		return ecdhes.ECDHES256KWChaCha20Poly1305KeyTemplate(), nil
+	case kms.ECDSASecp256k1TypeDER:
+		return secp256k1.DERKeyTemplate(), nil
+	case kms.ECDSASecp256k1TypeIEEEP1363:
+		return secp256k1.IEEEP1363KeyTemplate(), nil
+	case kms.X25519Type:
+		return x25519.KeyTemplate(), nil
+	case kms.BLS12381G2Type:
+		return bbs.BLS12381G2KeyTemplate(), nil
 	default:
 		return nil, fmt.Errorf("key type unrecognized")
 	}
@@ -192,6 +379,9 @@ func createECDSAIEEE1363KeyTemplate(hashType commonpb.HashType, curve commonpb.E
 }
 
 func (l *LocalKMS) storeKeySet(kh *keyset.Handle) (string, error) {
+	l.masterKeyMutex.RLock()
+	defer l.masterKeyMutex.RUnlock()
+
 	buf := new(bytes.Buffer)
 	jsonKeysetWriter := keyset.NewJSONWriter(buf)
 
@@ -200,10 +390,37 @@ func (l *LocalKMS) storeKeySet(kh *keyset.Handle) (string, error) {
 		return "", err
 	}
 
-	return writeToStore(l.store, buf)
+	return writeToStore(l.store, buf, publicKeyIDOpts(kh)...)
 }
 
-func writeToStore(store storage.Store, buf *bytes.Buffer, opts ...PrivateKeyOpts) (string, error) {
+// publicKeyIDOpts returns the option storing kh under the keyID given by kms.PublicKeyID for its primary public key,
+// so that asymmetric keys can be found from their public key. It returns no option for keys whose public key can't
+// be exported (eg symmetric keys), they are stored under a random keyID.
+func publicKeyIDOpts(kh *keyset.Handle) []kms.PrivateKeyOpts {
+	pubKey, err := exportPubKeyBytes(kh)
+	if err != nil {
+		return nil
+	}
+
+	return []kms.PrivateKeyOpts{kms.WithKeyID(kms.PublicKeyID(pubKey))}
+}
+
+// replaceKeySet stores kh in place of the keyset keyID.
+func (l *LocalKMS) replaceKeySet(keyID string, kh *keyset.Handle) error {
+	l.masterKeyMutex.RLock()
+	defer l.masterKeyMutex.RUnlock()
+
+	buf := new(bytes.Buffer)
+
+	err := kh.Write(keyset.NewJSONWriter(buf), l.masterKeyEnvAEAD)
+	if err != nil {
+		return err
+	}
+
+	return l.store.Put(keyID, buf.Bytes())
+}
+
+func writeToStore(store storage.Store, buf *bytes.Buffer, opts ...kms.PrivateKeyOpts) (string, error) {
 	w := newWriter(store, opts...)
 
 	// write buffer to localstorage
@@ -216,12 +433,19 @@ func writeToStore(store storage.Store, buf *bytes.Buffer, opts ...PrivateKeyOpts
 }
 
 func (l *LocalKMS) getKeySet(id string) (*keyset.Handle, error) {
+	l.masterKeyMutex.RLock()
+	defer l.masterKeyMutex.RUnlock()
+
 	localDBReader := newReader(l.store, id)
 	jsonKeysetReader := keyset.NewJSONReader(localDBReader)
 
 	// Read reads the encrypted keyset handle back from the io.reader implementation
 	// and decrypts it using masterKeyEnvAEAD.
 	kh, err := keyset.Read(jsonKeysetReader, l.masterKeyEnvAEAD)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("key %s: %w", id, kms.ErrKeyNotFound)
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -239,6 +463,42 @@ func (l *LocalKMS) ExportPubKeyBytes(id string) ([]byte, error) {
 		return nil, err
 	}
 
+	return exportPubKeyBytes(kh)
+}
+
+// CreateAndExportPubKeyBytes will create a key of type kt and export its public key in raw bytes and returns it.
+// The key type must be an asymmetric key type, a symmetric key is not created.
+// It returns the keyID of the new key along with its public key.
+func (l *LocalKMS) CreateAndExportPubKeyBytes(kt kms.KeyType) (string, []byte, error) {
+	keyTemplate, err := getKeyTemplate(kt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	kh, err := keyset.NewHandle(keyTemplate)
+	if err != nil {
+		return "", nil, err
+	}
+
+	pubKeyBytes, err := exportPubKeyBytes(kh)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to export new public key bytes: %w", err)
+	}
+
+	kID, err := l.storeKeySet(kh)
+	if err != nil {
+		return "", nil, err
+	}
+
+	err = l.recordNewKey(kID, kt, nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return kID, pubKeyBytes, nil
+}
+
+func exportPubKeyBytes(kh *keyset.Handle) ([]byte, error) {
 	// kh must be a private asymmetric key in order to extract its public key
 	pubKH, err := kh.Public()
 	if err != nil {
@@ -266,35 +526,43 @@ func (l *LocalKMS) PubKeyBytesToHandle(pubKey []byte, kt kms.KeyType) (*keyset.H
 
 // ImportPrivateKey will import privKey into the KMS storage for the given keyType then returns the new key id and the
 // newly stored keyset.Handle
-// privKey possible types are: *ecdsa.PrivateKey and ed25519.PrivateKey
-// kt possible types are signing key types only (ECDSA keys or Ed25519)
-// opts allows setting the keysetID of the imported key using WithKeyID() option. If the ID is already used,
-// then an error is returned.
+// privKey possible types are: *ecdsa.PrivateKey, *btcec.PrivateKey, ed25519.PrivateKey and []byte for the raw 32 bytes
+// of an X25519 private key
+// kt possible types are signing key types (ECDSA, ECDSA secp256k1 or Ed25519 keys) and X25519Type
+// opts allows setting the keysetID of the imported key using kms.WithKeyID() option, the keysetID defaults to the one
+// given by kms.PublicKeyID for the public key of privKey. If the ID is already used, then an error is returned.
 //
 // It returns an error if importing the key fails (key empty, invalid, doesn't match keyType or storing key failed)
 func (l *LocalKMS) ImportPrivateKey(privKey interface{}, kt kms.KeyType,
-	opts ...PrivateKeyOpts) (string, *keyset.Handle, error) {
+	opts ...kms.PrivateKeyOpts) (string, interface{}, error) {
+	var (
+		keyID string
+		kh    *keyset.Handle
+		err   error
+	)
+
 	switch pk := privKey.(type) {
 	case *ecdsa.PrivateKey:
-		return l.importECDSAKey(pk, kt, opts...)
+		keyID, kh, err = l.importECDSAKey(pk, kt, opts...)
+	case *btcec.PrivateKey:
+		keyID, kh, err = l.importECDSAKey(pk.ToECDSA(), kt, opts...)
 	case ed25519.PrivateKey:
-		return l.importEd25519Key(pk, kt, opts...)
+		keyID, kh, err = l.importEd25519Key(pk, kt, opts...)
+	case []byte:
+		keyID, kh, err = l.importX25519Key(pk, kt, opts...)
 	default:
 		return "", nil, fmt.Errorf("import private key does not support this key type or key is public")
 	}
-}
 
-// privateKeyOpts holds options for ImportPrivateKey.
-type privateKeyOpts struct {
-	ksID string
-}
-
-// PrivateKeyOpts are the import private key option.
-type PrivateKeyOpts func(opts *privateKeyOpts)
+	if err != nil {
+		return "", nil, err
+	}
 
-// WithKeyID option is for importing a private key with a specified KeyID.
-func WithKeyID(keyID string) PrivateKeyOpts {
-	return func(opts *privateKeyOpts) {
-		opts.ksID = keyID
+	err = l.recordNewKey(keyID, kt, nil)
+	if err != nil {
+		return "", nil, err
 	}
+
+	return keyID, kh, nil
 }
+
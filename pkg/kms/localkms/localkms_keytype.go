@@ -0,0 +1,137 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
+	"github.com/google/tink/go/keyset"
+	aesgcmpb "github.com/google/tink/go/proto/aes_gcm_go_proto"
+	streamingpb "github.com/google/tink/go/proto/aes_gcm_hkdf_streaming_go_proto"
+	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
+	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+
+	ecdhespb "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/proto/ecdhes_aead_go_proto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const (
+	aesGCMTypeURL              = "type.googleapis.com/google.crypto.tink.AesGcmKey"
+	aesGCMHKDFStreamingTypeURL = "type.googleapis.com/google.crypto.tink.AesGcmHkdfStreamingKey"
+	secp256k1PrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.Secp256k1PrivateKey"
+	ecdhesPrivateKeyTypeURL    = "type.hyperledger.org/hyperledger.aries.crypto.tink.EcdhesAeadPrivateKey"
+)
+
+// keyTypes are the key types created by the KMS.
+var keyTypes = []kms.KeyType{ //nolint:gochecknoglobals
+	kms.AES128GCMType, kms.AES256GCMNoPrefixType, kms.AES256GCMType, kms.ChaCha20Poly1305Type,
+	kms.XChaCha20Poly1305Type, kms.AES128GCMHKDF4KBType, kms.AES256GCMHKDF4KBType, kms.AES256GCMHKDF1MBType,
+	kms.ECDSAP256TypeDER, kms.ECDSAP384TypeDER, kms.ECDSAP521TypeDER, kms.ECDSAP256TypeIEEEP1363,
+	kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363, kms.ED25519Type, kms.HMACSHA256Tag256Type,
+	kms.ECDHES256AES256GCMType, kms.ECDHES256XChaCha20Poly1305Type, kms.ECDHES256ChaCha20Poly1305Type,
+	kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363, kms.X25519Type, kms.BLS12381G2Type,
+}
+
+// keyTypeOf returns the type of the primary key of kh, that is the type whose template creates keys having the same
+// type URL, output prefix and parameters. It returns an empty type when the key isn't of a type created by the KMS.
+func keyTypeOf(kh *keyset.Handle) (kms.KeyType, error) {
+	rw := &keyset.MemReaderWriter{}
+
+	err := insecurecleartextkeyset.Write(kh, rw)
+	if err != nil {
+		return "", err
+	}
+
+	var primary *tinkpb.Keyset_Key
+
+	for _, key := range rw.Keyset.Key {
+		if key.KeyId == rw.Keyset.PrimaryKeyId {
+			primary = key
+		}
+	}
+
+	if primary == nil || primary.KeyData == nil {
+		return "", nil
+	}
+
+	for _, kt := range keyTypes {
+		template, err := getKeyTemplate(kt)
+		if err != nil {
+			return "", err
+		}
+
+		if template.TypeUrl != primary.KeyData.TypeUrl || outputPrefix(template) != primary.OutputPrefixType {
+			continue
+		}
+
+		ok, err := matchesKeyFormat(primary.KeyData, template.Value)
+		if err != nil {
+			return "", err
+		}
+
+		if ok {
+			return kt, nil
+		}
+	}
+
+	return "", nil
+}
+
+// outputPrefix returns the output prefix of the keys created with a template, keys of templates not setting it have
+// the Tink prefix.
+func outputPrefix(template *tinkpb.KeyTemplate) tinkpb.OutputPrefixType {
+	if template.OutputPrefixType == tinkpb.OutputPrefixType_UNKNOWN_PREFIX {
+		return tinkpb.OutputPrefixType_TINK
+	}
+
+	return template.OutputPrefixType
+}
+
+// matchesKeyFormat tells whether a key has the parameters of the serialized key format of its type. Keys of the type
+// URLs used by a single key type always match.
+func matchesKeyFormat(key *tinkpb.KeyData, format []byte) (bool, error) {
+	switch key.TypeUrl {
+	case aesGCMTypeURL:
+		k, f := &aesgcmpb.AesGcmKey{}, &aesgcmpb.AesGcmKeyFormat{}
+		if err := unmarshalKeyAndFormat(key.Value, k, format, f); err != nil {
+			return false, err
+		}
+
+		return len(k.KeyValue) == int(f.KeySize), nil
+	case aesGCMHKDFStreamingTypeURL:
+		k, f := &streamingpb.AesGcmHkdfStreamingKey{}, &streamingpb.AesGcmHkdfStreamingKeyFormat{}
+		if err := unmarshalKeyAndFormat(key.Value, k, format, f); err != nil {
+			return false, err
+		}
+
+		return proto.Equal(k.Params, f.Params), nil
+	case ecdsaPrivateKeyTypeURL, secp256k1PrivateKeyTypeURL:
+		k, f := &ecdsapb.EcdsaPrivateKey{}, &ecdsapb.EcdsaKeyFormat{}
+		if err := unmarshalKeyAndFormat(key.Value, k, format, f); err != nil {
+			return false, err
+		}
+
+		return proto.Equal(k.GetPublicKey().GetParams(), f.Params), nil
+	case ecdhesPrivateKeyTypeURL:
+		k, f := &ecdhespb.EcdhesAeadPrivateKey{}, &ecdhespb.EcdhesAeadKeyFormat{}
+		if err := unmarshalKeyAndFormat(key.Value, k, format, f); err != nil {
+			return false, err
+		}
+
+		return proto.Equal(k.GetPublicKey().GetParams(), f.Params), nil
+	default:
+		return true, nil
+	}
+}
+
+func unmarshalKeyAndFormat(key []byte, k proto.Message, format []byte, f proto.Message) error {
+	if err := proto.Unmarshal(key, k); err != nil {
+		return err
+	}
+
+	return proto.Unmarshal(format, f)
+}
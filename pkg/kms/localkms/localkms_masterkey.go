@@ -0,0 +1,153 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/tink/go/aead"
+	"github.com/google/tink/go/keyset"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// masterKeyRotationKey is the metadata store key of the record of a master key rotation in progress.
+const masterKeyRotationKey = "masterkeyrotation"
+
+type masterKeyRotation struct {
+	MasterKeyURI string    `json:"masterKeyURI"`
+	Started      time.Time `json:"started"`
+}
+
+// RotateMasterKey re-encrypts the keysets of the KMS with the master key newMasterKeyURI of newSecretLock, then uses
+// the new master key for the next keysets. The keys used while the master key is rotated wait for the end of the
+// rotation.
+//
+// The rotation is resumable: if it is interrupted, the keysets are encrypted either with the previous master key or
+// with the new one, and calling RotateMasterKey again from the KMS of the previous master key with the same new master
+// key re-encrypts the keysets still encrypted with the previous master key. PendingMasterKeyRotation tells whether a
+// rotation was interrupted.
+func (l *LocalKMS) RotateMasterKey(newMasterKeyURI string, newSecretLock secretlock.Service) error {
+	newEnvAEAD, err := newMasterKeyEnvAEAD(newSecretLock, newMasterKeyURI)
+	if err != nil {
+		return fmt.Errorf("rotate master key: %w", err)
+	}
+
+	l.masterKeyMutex.Lock()
+	defer l.masterKeyMutex.Unlock()
+
+	pending, err := l.PendingMasterKeyRotation()
+	if err != nil {
+		return fmt.Errorf("rotate master key: %w", err)
+	}
+
+	if pending != "" && pending != newMasterKeyURI {
+		return fmt.Errorf("rotate master key: the rotation to master key %s must be resumed first", pending)
+	}
+
+	if pending == "" {
+		if err = l.saveMasterKeyRotation(newMasterKeyURI); err != nil {
+			return fmt.Errorf("rotate master key: %w", err)
+		}
+	}
+
+	if err = l.reencryptKeySets(newEnvAEAD); err != nil {
+		return fmt.Errorf("rotate master key: %w", err)
+	}
+
+	if err = l.metadataStore.Delete(masterKeyRotationKey); err != nil {
+		return fmt.Errorf("rotate master key: failed to delete master key rotation record: %w", err)
+	}
+
+	l.secretLock = newSecretLock
+	l.masterKeyURI = newMasterKeyURI
+	l.masterKeyEnvAEAD = newEnvAEAD
+
+	return nil
+}
+
+// PendingMasterKeyRotation returns the URI of the new master key of an interrupted master key rotation, or an empty
+// string if no rotation was interrupted.
+func (l *LocalKMS) PendingMasterKeyRotation() (string, error) {
+	data, err := l.metadataStore.Get(masterKeyRotationKey)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get master key rotation record: %w", err)
+	}
+
+	rotation := &masterKeyRotation{}
+
+	if err = json.Unmarshal(data, rotation); err != nil {
+		return "", fmt.Errorf("failed to unmarshal master key rotation record: %w", err)
+	}
+
+	return rotation.MasterKeyURI, nil
+}
+
+func (l *LocalKMS) saveMasterKeyRotation(newMasterKeyURI string) error {
+	data, err := json.Marshal(&masterKeyRotation{MasterKeyURI: newMasterKeyURI, Started: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal master key rotation record: %w", err)
+	}
+
+	if err = l.metadataStore.Put(masterKeyRotationKey, data); err != nil {
+		return fmt.Errorf("failed to save master key rotation record: %w", err)
+	}
+
+	return nil
+}
+
+// reencryptKeySets re-encrypts the keysets encrypted with the master key of the KMS with newEnvAEAD, each keyset is
+// replaced at once so that an interruption leaves it encrypted with either master key.
+func (l *LocalKMS) reencryptKeySets(newEnvAEAD *aead.KMSEnvelopeAEAD) error {
+	itr := l.store.Iterator(" ", "~"+storage.EndKeySuffix)
+	defer itr.Release()
+
+	rotated := 0
+
+	for itr.Next() {
+		keyID := string(itr.Key())
+
+		kh, err := keyset.Read(keyset.NewJSONReader(bytes.NewReader(itr.Value())), l.masterKeyEnvAEAD)
+		if err != nil {
+			// the keyset was re-encrypted before the rotation was interrupted
+			if _, e := keyset.Read(keyset.NewJSONReader(bytes.NewReader(itr.Value())), newEnvAEAD); e == nil {
+				continue
+			}
+
+			return fmt.Errorf("failed to decrypt keyset %s: %w", keyID, err)
+		}
+
+		buf := new(bytes.Buffer)
+
+		if err = kh.Write(keyset.NewJSONWriter(buf), newEnvAEAD); err != nil {
+			return fmt.Errorf("failed to encrypt keyset %s: %w", keyID, err)
+		}
+
+		if err = l.store.Put(keyID, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to save keyset %s: %w", keyID, err)
+		}
+
+		rotated++
+	}
+
+	if err := itr.Error(); err != nil {
+		return fmt.Errorf("failed to list keysets: %w", err)
+	}
+
+	logger.Infof("re-encrypted %d keysets with master key", rotated)
+
+	return nil
+}
@@ -0,0 +1,255 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+const newMasterKeyURI = "local-lock://test/new/key/uri"
+
+func TestLocalKMS_RotateMasterKey(t *testing.T) {
+	oldLock, newLock := newLocalSecretLock(t), newLocalSecretLock(t)
+
+	t.Run("rotate master key", func(t *testing.T) {
+		storeProvider := mem.NewProvider()
+
+		kmsService, err := New(testMasterKeyURI, &lockProvider{storage: storeProvider, secretLock: oldLock})
+		require.NoError(t, err)
+
+		keyIDs := createKeys(t, kmsService)
+
+		require.NoError(t, kmsService.RotateMasterKey(newMasterKeyURI, newLock))
+
+		// the KMS uses the new master key
+		for _, keyID := range keyIDs {
+			_, err = kmsService.Get(keyID)
+			require.NoError(t, err)
+		}
+
+		keyID, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		newKMS, err := New(newMasterKeyURI, &lockProvider{storage: storeProvider, secretLock: newLock})
+		require.NoError(t, err)
+
+		for _, id := range append(keyIDs, keyID) {
+			_, err = newKMS.Get(id)
+			require.NoError(t, err)
+		}
+
+		oldKMS, err := New(testMasterKeyURI, &lockProvider{storage: storeProvider, secretLock: oldLock})
+		require.NoError(t, err)
+
+		_, err = oldKMS.Get(keyIDs[0])
+		require.Error(t, err)
+	})
+
+	t.Run("resume interrupted rotation", func(t *testing.T) {
+		kmsStore := &failingPutStore{}
+		storeProvider := &kmsStoreProvider{Provider: mem.NewProvider(), kmsStore: kmsStore}
+
+		kmsService, err := New(testMasterKeyURI, &lockProvider{storage: storeProvider, secretLock: oldLock})
+		require.NoError(t, err)
+
+		keyIDs := createKeys(t, kmsService)
+
+		// interrupt the rotation after a keyset is re-encrypted
+		kmsStore.putsLeft = 1
+		errPut := errors.New("interrupted")
+		kmsStore.err = errPut
+
+		err = kmsService.RotateMasterKey(newMasterKeyURI, newLock)
+		require.True(t, errors.Is(err, errPut))
+
+		pending, err := kmsService.PendingMasterKeyRotation()
+		require.NoError(t, err)
+		require.Equal(t, newMasterKeyURI, pending)
+
+		err = kmsService.RotateMasterKey("local-lock://other/key/uri", newLock)
+		require.EqualError(t, err, "rotate master key: the rotation to master key "+newMasterKeyURI+
+			" must be resumed first")
+
+		kmsStore.err = nil
+
+		require.NoError(t, kmsService.RotateMasterKey(newMasterKeyURI, newLock))
+
+		pending, err = kmsService.PendingMasterKeyRotation()
+		require.NoError(t, err)
+		require.Empty(t, pending)
+
+		newKMS, err := New(newMasterKeyURI, &lockProvider{storage: storeProvider, secretLock: newLock})
+		require.NoError(t, err)
+
+		for _, keyID := range keyIDs {
+			_, err = newKMS.Get(keyID)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("keys created during the rotation", func(t *testing.T) {
+		storeProvider := mem.NewProvider()
+
+		kmsService, err := New(testMasterKeyURI, &lockProvider{storage: storeProvider, secretLock: oldLock})
+		require.NoError(t, err)
+
+		createKeys(t, kmsService)
+
+		const creators = 10
+
+		type created struct {
+			keyID string
+			err   error
+		}
+
+		results := make(chan created, creators)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < creators; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				keyID, _, e := kmsService.Create(kms.ED25519Type)
+				if e == nil {
+					_, e = kmsService.Get(keyID)
+				}
+
+				results <- created{keyID: keyID, err: e}
+			}()
+		}
+
+		require.NoError(t, kmsService.RotateMasterKey(newMasterKeyURI, newLock))
+
+		wg.Wait()
+		close(results)
+
+		// the keys created before or after the master key is swapped are all encrypted with the new master key
+		newKMS, err := New(newMasterKeyURI, &lockProvider{storage: storeProvider, secretLock: newLock})
+		require.NoError(t, err)
+
+		for result := range results {
+			require.NoError(t, result.err)
+
+			_, err = newKMS.Get(result.keyID)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("rotation failures", func(t *testing.T) {
+		storeProvider := mem.NewProvider()
+
+		kmsService, err := New(testMasterKeyURI, &lockProvider{storage: storeProvider, secretLock: oldLock})
+		require.NoError(t, err)
+
+		createKeys(t, kmsService)
+
+		err = kmsService.RotateMasterKey("bad-uri", newLock)
+		require.EqualError(t, err, "rotate master key: keyURI must start with local-lock://")
+
+		// a KMS with another master key can't decrypt the keysets
+		otherKMS, err := New(testMasterKeyURI, &lockProvider{
+			storage:    storeProvider,
+			secretLock: newLocalSecretLock(t),
+		})
+		require.NoError(t, err)
+
+		err = otherKMS.RotateMasterKey(newMasterKeyURI, newLock)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rotate master key: failed to decrypt keyset")
+	})
+}
+
+func createKeys(t *testing.T, kmsService *LocalKMS) []string {
+	t.Helper()
+
+	var keyIDs []string
+
+	for _, kt := range []kms.KeyType{kms.ED25519Type, kms.AES256GCMType, kms.X25519Type} {
+		keyID, _, err := kmsService.Create(kt)
+		require.NoError(t, err)
+
+		keyIDs = append(keyIDs, keyID)
+	}
+
+	return keyIDs
+}
+
+func newLocalSecretLock(t *testing.T) secretlock.Service {
+	t.Helper()
+
+	masterKey := base64.URLEncoding.EncodeToString(random.GetRandomBytes(32))
+
+	sl, err := local.NewService(bytes.NewReader([]byte(masterKey)), nil)
+	require.NoError(t, err)
+
+	return sl
+}
+
+type lockProvider struct {
+	storage    storage.Provider
+	secretLock secretlock.Service
+}
+
+func (p *lockProvider) StorageProvider() storage.Provider {
+	return p.storage
+}
+
+func (p *lockProvider) SecretLock() secretlock.Service {
+	return p.secretLock
+}
+
+// kmsStoreProvider opens kmsStore as the KMS keystore.
+type kmsStoreProvider struct {
+	storage.Provider
+	kmsStore *failingPutStore
+}
+
+func (p *kmsStoreProvider) OpenStore(name string) (storage.Store, error) {
+	store, err := p.Provider.OpenStore(name)
+	if err != nil || name != Namespace {
+		return store, err
+	}
+
+	p.kmsStore.Store = store
+
+	return p.kmsStore, nil
+}
+
+// failingPutStore fails the Puts after putsLeft Puts while err is set.
+type failingPutStore struct {
+	storage.Store
+	putsLeft int
+	err      error
+}
+
+func (s *failingPutStore) Put(k string, v []byte, tags ...storage.Tag) error {
+	if s.err != nil {
+		if s.putsLeft == 0 {
+			return s.err
+		}
+
+		s.putsLeft--
+	}
+
+	return s.Store.Put(k, v, tags...)
+}
@@ -0,0 +1,297 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// MetadataNamespace is the DB storage namespace of the metadata of the keys of the keystore
+	MetadataNamespace = "kmsmetadata"
+
+	metadataKeyPrefix = "md_"
+)
+
+// List returns the metadata of the keys of the KMS, sorted by creation time.
+// Keys created before their metadata was recorded get their metadata when first listed, with that time as their
+// creation time so that they aren't all due for rotation at once.
+func (l *LocalKMS) List() ([]*kms.KeyMetadata, error) {
+	itr := l.metadataStore.Iterator(metadataKeyPrefix, metadataKeyPrefix+storage.EndKeySuffix)
+	defer itr.Release()
+
+	var keys []*kms.KeyMetadata
+
+	// IDs of the keys having metadata, and of the keys they replaced
+	known := make(map[string]struct{})
+
+	for itr.Next() {
+		md := &kms.KeyMetadata{}
+
+		if err := json.Unmarshal(itr.Value(), md); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal key metadata: %w", err)
+		}
+
+		keys = append(keys, md)
+		known[md.ID] = struct{}{}
+
+		for _, id := range md.PreviousKeyIDs {
+			known[id] = struct{}{}
+		}
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	recorded, err := l.recordMissingMetadata(known)
+	if err != nil {
+		return nil, err
+	}
+
+	keys = append(keys, recorded...)
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		if !keys[i].Created.Equal(keys[j].Created) {
+			return keys[i].Created.Before(keys[j].Created)
+		}
+
+		return keys[i].ID < keys[j].ID
+	})
+
+	return keys, nil
+}
+
+// recordMissingMetadata saves the metadata of the keys of the keystore which aren't known, ie created before their
+// metadata was recorded, and returns it.
+func (l *LocalKMS) recordMissingMetadata(known map[string]struct{}) ([]*kms.KeyMetadata, error) {
+	itr := l.store.Iterator(" ", "~"+storage.EndKeySuffix)
+	defer itr.Release()
+
+	var missing []string
+
+	for itr.Next() {
+		if id := string(itr.Key()); !isKnown(known, id) {
+			missing = append(missing, id)
+		}
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	recorded := make([]*kms.KeyMetadata, 0, len(missing))
+
+	for _, id := range missing {
+		md, err := l.initialMetadata(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = l.saveMetadata(md); err != nil {
+			return nil, err
+		}
+
+		recorded = append(recorded, md)
+	}
+
+	return recorded, nil
+}
+
+func isKnown(known map[string]struct{}, id string) bool {
+	_, ok := known[id]
+
+	return ok
+}
+
+// initialMetadata returns the metadata of the key referenced by keyID which was created before its metadata was
+// recorded. Its type is the one of its primary key, it's empty when the key isn't of a type created by the KMS.
+func (l *LocalKMS) initialMetadata(keyID string) (*kms.KeyMetadata, error) {
+	kh, err := l.getKeySet(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	kt, err := keyTypeOf(kh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get type of key %s: %w", keyID, err)
+	}
+
+	return &kms.KeyMetadata{ID: keyID, Type: kt, Created: time.Now().UTC()}, nil
+}
+
+// GetMetadata returns the metadata of the key referenced by keyID. The metadata of a key created before its metadata
+// was recorded is saved on the first call, like List does.
+func (l *LocalKMS) GetMetadata(keyID string) (*kms.KeyMetadata, error) {
+	data, err := l.metadataStore.Get(metadataKeyPrefix + keyID)
+	if err == nil {
+		md := &kms.KeyMetadata{}
+
+		if err = json.Unmarshal(data, md); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal key metadata: %w", err)
+		}
+
+		return md, nil
+	}
+
+	if !errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("failed to get key metadata: %w", err)
+	}
+
+	md, err := l.initialMetadata(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = l.saveMetadata(md); err != nil {
+		return nil, err
+	}
+
+	return md, nil
+}
+
+// UpdateMetadata records what the key referenced by keyID is used for.
+func (l *LocalKMS) UpdateMetadata(keyID string, opts ...kms.MetadataOpts) error {
+	md, err := l.GetMetadata(keyID)
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		opt(md)
+	}
+
+	md.ID = keyID
+
+	return l.saveMetadata(md)
+}
+
+// Delete the key referenced by keyID along with its metadata.
+func (l *LocalKMS) Delete(keyID string) error {
+	if err := l.keyExists(keyID); err != nil {
+		return err
+	}
+
+	if err := l.store.Delete(keyID); err != nil {
+		return fmt.Errorf("failed to delete key: %w", err)
+	}
+
+	if err := l.metadataStore.Delete(metadataKeyPrefix + keyID); err != nil {
+		return fmt.Errorf("failed to delete key metadata: %w", err)
+	}
+
+	return nil
+}
+
+// recordNewKey saves the metadata of a newly stored key. The new key is removed if its metadata can't be saved, the
+// key it replaces (previous) is left untouched.
+func (l *LocalKMS) recordNewKey(keyID string, kt kms.KeyType, previous *kms.KeyMetadata) error {
+	md := &kms.KeyMetadata{
+		ID:      keyID,
+		Type:    kt,
+		Created: time.Now().UTC(),
+	}
+
+	// a rotated key keeps its usage, its policy and its pending DID document update along with the IDs it had before
+	if previous != nil {
+		md.Policy = previous.Policy
+		md.Purpose = previous.Purpose
+		md.DID = previous.DID
+		md.VerificationMethod = previous.VerificationMethod
+		md.PendingDIDUpdate = previous.PendingDIDUpdate
+		md.PreviousKeyIDs = append(append([]string(nil), previous.PreviousKeyIDs...), previous.ID)
+		md.PreviousVersions = previous.PreviousVersions + 1
+	}
+
+	if err := l.saveMetadata(md); err != nil {
+		if e := l.store.Delete(keyID); e != nil {
+			logger.Warnf("failed to remove key %s without metadata: %s", keyID, e)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// removeReplacedKey removes the key referenced by keyID once its rotated key newID is recorded. The rotation is done
+// by then, so failures are only logged. The metadata goes first: a key left behind without it isn't listed since it's
+// a previous key of the rotated key.
+func (l *LocalKMS) removeReplacedKey(keyID, newID string) {
+	if err := l.metadataStore.Delete(metadataKeyPrefix + keyID); err != nil {
+		logger.Warnf("failed to remove metadata of key %s replaced by %s: %s", keyID, newID, err)
+
+		return
+	}
+
+	if err := l.store.Delete(keyID); err != nil {
+		logger.Warnf("failed to remove key %s replaced by %s: %s", keyID, newID, err)
+	}
+}
+
+// rotatedKeyID returns the ID of the rotated key keeping the version which had the ID keyID.
+func (l *LocalKMS) rotatedKeyID(keyID string) (string, error) {
+	itr := l.metadataStore.Iterator(metadataKeyPrefix, metadataKeyPrefix+storage.EndKeySuffix)
+	defer itr.Release()
+
+	for itr.Next() {
+		md := &kms.KeyMetadata{}
+
+		if err := json.Unmarshal(itr.Value(), md); err != nil {
+			return "", fmt.Errorf("failed to unmarshal key metadata: %w", err)
+		}
+
+		if md.PreviousVersions == 0 || md.PreviousVersions > len(md.PreviousKeyIDs) {
+			continue
+		}
+
+		for _, id := range md.PreviousKeyIDs[len(md.PreviousKeyIDs)-md.PreviousVersions:] {
+			if id == keyID {
+				return md.ID, nil
+			}
+		}
+	}
+
+	if err := itr.Error(); err != nil {
+		return "", fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	return "", fmt.Errorf("key %s: %w", keyID, kms.ErrKeyNotFound)
+}
+
+func (l *LocalKMS) saveMetadata(md *kms.KeyMetadata) error {
+	data, err := json.Marshal(md)
+	if err != nil {
+		return fmt.Errorf("failed to marshal key metadata: %w", err)
+	}
+
+	if err = l.metadataStore.Put(metadataKeyPrefix+md.ID, data); err != nil {
+		return fmt.Errorf("failed to save key metadata: %w", err)
+	}
+
+	return nil
+}
+
+func (l *LocalKMS) keyExists(keyID string) error {
+	_, err := l.store.Get(keyID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("key %s: %w", keyID, kms.ErrKeyNotFound)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get key: %w", err)
+	}
+
+	return nil
+}
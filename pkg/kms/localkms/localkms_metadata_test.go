@@ -0,0 +1,303 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestLocalKMS_Metadata(t *testing.T) {
+	sl := createMasterKeyAndSecretLock(t)
+
+	newKMS := func(t *testing.T, store *mockstorage.MockStore) *LocalKMS {
+		kmsService, err := New(testMasterKeyURI, &mockProvider{
+			storage:    mockstorage.NewCustomMockStoreProvider(store),
+			secretLock: sl,
+		})
+		require.NoError(t, err)
+
+		return kmsService
+	}
+
+	t.Run("test New() fail due to error opening metadata store", func(t *testing.T) {
+		_, err := New(testMasterKeyURI, &mockProvider{
+			storage:    &mockstorage.MockStoreProvider{FailNamespace: MetadataNamespace},
+			secretLock: sl,
+		})
+		require.EqualError(t, err, "failed to ceate local kms: failed to open store for name space kmsmetadata")
+	})
+
+	t.Run("test create, list, update and delete keys", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		kmsService := newKMS(t, store)
+
+		keys, err := kmsService.List()
+		require.NoError(t, err)
+		require.Empty(t, keys)
+
+		id1, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		id2, _, err := kmsService.Create(kms.AES256GCMType)
+		require.NoError(t, err)
+
+		_, pk, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		id3, _, err := kmsService.ImportPrivateKey(pk, kms.ED25519Type, kms.WithKeyID("imported"))
+		require.NoError(t, err)
+		require.Equal(t, "imported", id3)
+
+		keys, err = kmsService.List()
+		require.NoError(t, err)
+		require.Len(t, keys, 3)
+
+		types := make(map[string]kms.KeyType)
+
+		for _, k := range keys {
+			require.False(t, k.Created.IsZero())
+			types[k.ID] = k.Type
+		}
+
+		require.Equal(t, map[string]kms.KeyType{
+			id1: kms.ED25519Type, id2: kms.AES256GCMType, id3: kms.ED25519Type,
+		}, types)
+
+		err = kmsService.UpdateMetadata(id1, kms.WithPurpose("authentication"),
+			kms.WithDID("did:example:123", "did:example:123#key-1"))
+		require.NoError(t, err)
+
+		md, err := kmsService.GetMetadata(id1)
+		require.NoError(t, err)
+		require.Equal(t, id1, md.ID)
+		require.Equal(t, kms.ED25519Type, md.Type)
+		require.Equal(t, "authentication", md.Purpose)
+		require.Equal(t, "did:example:123", md.DID)
+		require.Equal(t, "did:example:123#key-1", md.VerificationMethod)
+
+		require.NoError(t, kmsService.Delete(id2))
+
+		_, err = kmsService.Get(id2)
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+
+		_, err = kmsService.GetMetadata(id2)
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+
+		err = kmsService.Delete(id2)
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+
+		keys, err = kmsService.List()
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+	})
+
+	t.Run("test rotate keeps key usage and lineage", func(t *testing.T) {
+		kmsService := newKMS(t, &mockstorage.MockStore{Store: make(map[string][]byte)})
+
+		id, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		require.NoError(t, kmsService.UpdateMetadata(id, kms.WithPurpose("assertionMethod")))
+
+		rotatedID, _, err := kmsService.Rotate(kms.ED25519Type, id)
+		require.NoError(t, err)
+
+		rotatedAgainID, _, err := kmsService.Rotate(kms.ECDSAP256TypeIEEEP1363, rotatedID)
+		require.NoError(t, err)
+
+		md, err := kmsService.GetMetadata(rotatedAgainID)
+		require.NoError(t, err)
+		require.Equal(t, kms.ECDSAP256TypeIEEEP1363, md.Type)
+		require.Equal(t, "assertionMethod", md.Purpose)
+		require.Equal(t, []string{id, rotatedID}, md.PreviousKeyIDs)
+
+		keys, err := kmsService.List()
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, rotatedAgainID, keys[0].ID)
+	})
+
+	t.Run("test keys without metadata", func(t *testing.T) {
+		kmsService, err := New(testMasterKeyURI, &lockProvider{storage: mem.NewProvider(), secretLock: sl})
+		require.NoError(t, err)
+
+		types := make(map[string]kms.KeyType)
+
+		for _, kt := range keyTypes {
+			id, _, e := kmsService.Create(kt)
+			require.NoError(t, e)
+
+			types[id] = kt
+		}
+
+		_, pk, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		imported, _, err := kmsService.ImportPrivateKey(pk, kms.ED25519Type)
+		require.NoError(t, err)
+
+		types[imported] = kms.ED25519Type
+
+		// keys created before their metadata was recorded
+		for id := range types {
+			require.NoError(t, kmsService.metadataStore.Delete(metadataKeyPrefix+id))
+		}
+
+		// a key left behind by a rotation
+		replaced, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		keyset, err := kmsService.store.Get(replaced)
+		require.NoError(t, err)
+
+		rotatedID, _, err := kmsService.Rotate(kms.ED25519Type, replaced)
+		require.NoError(t, err)
+
+		require.NoError(t, kmsService.store.Put(replaced, keyset))
+
+		md, err := kmsService.GetMetadata(imported)
+		require.NoError(t, err)
+		require.Equal(t, imported, md.ID)
+		require.Equal(t, kms.ED25519Type, md.Type)
+		require.False(t, md.Created.IsZero())
+
+		keys, err := kmsService.List()
+		require.NoError(t, err)
+		require.Len(t, keys, len(types)+1)
+
+		for _, k := range keys {
+			require.NotEqual(t, replaced, k.ID)
+
+			if k.ID == rotatedID {
+				continue
+			}
+
+			require.Equal(t, types[k.ID], k.Type, k.ID)
+			require.False(t, k.Created.IsZero())
+
+			if k.ID == imported {
+				require.Equal(t, md, k)
+			}
+		}
+
+		created := md.Created
+
+		require.NoError(t, kmsService.UpdateMetadata(imported, kms.WithPurpose("keyAgreement")))
+
+		md, err = kmsService.GetMetadata(imported)
+		require.NoError(t, err)
+		require.Equal(t, "keyAgreement", md.Purpose)
+		require.Equal(t, created, md.Created)
+
+		_, err = kmsService.GetMetadata("unknown")
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+
+		err = kmsService.UpdateMetadata("unknown", kms.WithPurpose("keyAgreement"))
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+	})
+
+	t.Run("test storage errors", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		kmsService := newKMS(t, store)
+
+		id, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		store.ErrItr = fmt.Errorf("iterator error")
+		_, err = kmsService.List()
+		require.EqualError(t, err, "failed to list keys: iterator error")
+
+		store.ErrItr = nil
+		store.Store[metadataKeyPrefix+"invalid"] = []byte("{")
+		_, err = kmsService.List()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal key metadata")
+
+		_, err = kmsService.GetMetadata("invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal key metadata")
+
+		store.ErrGet = fmt.Errorf("get error")
+		_, err = kmsService.GetMetadata(id)
+		require.EqualError(t, err, "failed to get key metadata: get error")
+
+		err = kmsService.Delete(id)
+		require.EqualError(t, err, "failed to get key: get error")
+
+		store.ErrGet = nil
+		store.ErrDelete = fmt.Errorf("delete error")
+		err = kmsService.Delete(id)
+		require.EqualError(t, err, "failed to delete key: delete error")
+	})
+
+	t.Run("test create removes key when its metadata can't be saved", func(t *testing.T) {
+		keysets := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		kmsService := newKMS(t, keysets)
+		kmsService.metadataStore = &mockstorage.MockStore{
+			Store:  make(map[string][]byte),
+			ErrPut: fmt.Errorf("put error"),
+		}
+
+		_, _, err := kmsService.Create(kms.ED25519Type)
+		require.EqualError(t, err, "failed to save key metadata: put error")
+		require.Empty(t, keysets.Store)
+	})
+
+	t.Run("test rotate keeps the key when the rotated key's metadata can't be saved", func(t *testing.T) {
+		keysets := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		metadata := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		kmsService := newKMS(t, keysets)
+		kmsService.metadataStore = metadata
+
+		id, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		require.NoError(t, kmsService.UpdateMetadata(id, kms.WithPurpose("authentication")))
+
+		metadata.ErrPut = fmt.Errorf("put error")
+
+		_, _, err = kmsService.Rotate(kms.ED25519Type, id)
+		require.EqualError(t, err, "failed to save key metadata: put error")
+		require.Len(t, keysets.Store, 1)
+
+		_, err = kmsService.Get(id)
+		require.NoError(t, err)
+
+		md, err := kmsService.GetMetadata(id)
+		require.NoError(t, err)
+		require.Equal(t, "authentication", md.Purpose)
+	})
+
+	t.Run("test rotate succeeds when the replaced key can't be removed", func(t *testing.T) {
+		keysets := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		kmsService := newKMS(t, keysets)
+		kmsService.metadataStore = &mockstorage.MockStore{Store: make(map[string][]byte)}
+
+		id, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		keysets.ErrDelete = fmt.Errorf("delete error")
+
+		rotatedID, _, err := kmsService.Rotate(kms.ED25519Type, id)
+		require.NoError(t, err)
+
+		keys, err := kmsService.List()
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, rotatedID, keys[0].ID)
+	})
+}
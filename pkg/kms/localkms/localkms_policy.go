@@ -0,0 +1,163 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/tink/go/keyset"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const auditRecordPrefix = "audit_"
+
+// AuditRecord records a crypto operation denied by the policy of a key.
+type AuditRecord struct {
+	KeyID     string           `json:"keyID"`
+	Operation kms.KeyOperation `json:"operation"`
+	Algorithm string           `json:"algorithm,omitempty"`
+	Reason    string           `json:"reason"`
+	Time      time.Time        `json:"time"`
+}
+
+// policyKeyHandle is the key handle of the keys with a policy, it implements kms.PolicyKeyHandle.
+type policyKeyHandle struct {
+	kh    *keyset.Handle
+	keyID string
+	kms   *LocalKMS
+}
+
+// Authorize checks that the policy of the key allows the operation op with the algorithm alg and returns the keyset
+// handle of the key.
+func (h *policyKeyHandle) Authorize(op kms.KeyOperation, alg string) (interface{}, error) {
+	if err := h.kms.authorize(h.keyID, op, alg); err != nil {
+		return nil, err
+	}
+
+	return h.kh, nil
+}
+
+// AuditRecords returns the records of the operations denied by the key policies, oldest first.
+func (l *LocalKMS) AuditRecords() ([]*AuditRecord, error) {
+	itr := l.metadataStore.Iterator(auditRecordPrefix, auditRecordPrefix+storage.EndKeySuffix)
+	defer itr.Release()
+
+	var records []*AuditRecord
+
+	for itr.Next() {
+		record := &AuditRecord{}
+
+		if err := json.Unmarshal(itr.Value(), record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit record: %w", err)
+		}
+
+		records = append(records, record)
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("failed to list audit records: %w", err)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+
+	return records, nil
+}
+
+// authorize enforces the policy of the key keyID for the operation op with the algorithm alg, denied operations are
+// audited. The policy is read at each operation so that its updates apply to the key handles already in use.
+func (l *LocalKMS) authorize(keyID string, op kms.KeyOperation, alg string) error {
+	l.policyMutex.Lock()
+	defer l.policyMutex.Unlock()
+
+	md, err := l.GetMetadata(keyID)
+	if err != nil {
+		return err
+	}
+
+	if md.Policy == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+
+	if reason := deniedReason(md, op, alg, now); reason != "" {
+		logger.Warnf("%s of key %s denied: %s", op, keyID, reason)
+
+		l.audit(&AuditRecord{KeyID: keyID, Operation: op, Algorithm: alg, Reason: reason, Time: now})
+
+		return fmt.Errorf("%w: %s of key %s denied: %s", kms.ErrKeyPolicyViolation, op, keyID, reason)
+	}
+
+	if md.Policy.MaxUsage == 0 {
+		return nil
+	}
+
+	md.UsageCount++
+
+	return l.saveMetadata(md)
+}
+
+// deniedReason returns why the policy of the key md denies the operation op with alg, or an empty string if the
+// operation is allowed.
+func deniedReason(md *kms.KeyMetadata, op kms.KeyOperation, alg string, now time.Time) string {
+	policy := md.Policy
+
+	switch {
+	case len(policy.Operations) > 0 && !containsOperation(policy.Operations, op):
+		return "operation not allowed"
+	case len(policy.Algorithms) > 0 && alg != "" && !containsString(policy.Algorithms, alg):
+		return fmt.Sprintf("algorithm %s not allowed", alg)
+	case policy.Expiry != nil && now.After(*policy.Expiry):
+		return "key expired"
+	case policy.MaxUsage > 0 && md.UsageCount >= policy.MaxUsage:
+		return "usage limit reached"
+	default:
+		return ""
+	}
+}
+
+func (l *LocalKMS) audit(record *AuditRecord) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		logger.Errorf("failed to marshal audit record: %s", err)
+		return
+	}
+
+	// the zero padded timestamp keeps the records sorted by time
+	key := fmt.Sprintf("%s%020d_%s", auditRecordPrefix, record.Time.UnixNano(), record.KeyID)
+
+	if err = l.metadataStore.Put(key, data); err != nil {
+		logger.Errorf("failed to save audit record: %s", err)
+	}
+}
+
+func containsOperation(operations []kms.KeyOperation, op kms.KeyOperation) bool {
+	for _, o := range operations {
+		if o == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,176 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package localkms
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+)
+
+func TestLocalKMS_Policy(t *testing.T) {
+	kmsService, err := New(testMasterKeyURI, &mockProvider{
+		storage:    mockstorage.NewMockStoreProvider(),
+		secretLock: createMasterKeyAndSecretLock(t),
+	})
+	require.NoError(t, err)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	msg := []byte("test message")
+
+	t.Run("key without policy", func(t *testing.T) {
+		keyID, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		kh, err := kmsService.Get(keyID)
+		require.NoError(t, err)
+		require.IsType(t, &keyset.Handle{}, kh)
+	})
+
+	t.Run("sign-only key with a usage limit", func(t *testing.T) {
+		keyID, _, err := kmsService.Create(kms.ED25519Type)
+		require.NoError(t, err)
+
+		err = kmsService.UpdateMetadata(keyID, kms.WithPolicy(&kms.KeyPolicy{
+			Operations: []kms.KeyOperation{kms.SignOp},
+			MaxUsage:   2,
+		}))
+		require.NoError(t, err)
+
+		kh, err := kmsService.Get(keyID)
+		require.NoError(t, err)
+		require.Implements(t, (*kms.PolicyKeyHandle)(nil), kh)
+
+		sig, err := c.Sign(msg, kh)
+		require.NoError(t, err)
+
+		err = c.Verify(sig, msg, kh)
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+		require.EqualError(t, err, "key policy violation: verify of key "+keyID+" denied: operation not allowed")
+
+		_, err = c.Sign(msg, kh)
+		require.NoError(t, err)
+
+		md, err := kmsService.GetMetadata(keyID)
+		require.NoError(t, err)
+		require.Equal(t, 2, md.UsageCount)
+
+		_, err = c.Sign(msg, kh)
+		require.EqualError(t, err, "key policy violation: sign of key "+keyID+" denied: usage limit reached")
+
+		records, err := kmsService.AuditRecords()
+		require.NoError(t, err)
+		require.True(t, len(records) >= 2)
+
+		var denied []kms.KeyOperation
+
+		for _, record := range records {
+			if record.KeyID == keyID {
+				denied = append(denied, record.Operation)
+			}
+		}
+
+		require.Equal(t, []kms.KeyOperation{kms.VerifyOp, kms.SignOp}, denied)
+	})
+
+	t.Run("expired key", func(t *testing.T) {
+		keyID, _, err := kmsService.Create(kms.AES256GCMType)
+		require.NoError(t, err)
+
+		expiry := time.Now().Add(-time.Minute)
+
+		err = kmsService.UpdateMetadata(keyID, kms.WithPolicy(&kms.KeyPolicy{Expiry: &expiry}))
+		require.NoError(t, err)
+
+		kh, err := kmsService.Get(keyID)
+		require.NoError(t, err)
+
+		_, _, err = c.Encrypt(msg, nil, kh)
+		require.EqualError(t, err, "key policy violation: encrypt of key "+keyID+" denied: key expired")
+
+		// removing the policy of the key allows it again
+		require.NoError(t, kmsService.UpdateMetadata(keyID, kms.WithPolicy(nil)))
+
+		kh, err = kmsService.Get(keyID)
+		require.NoError(t, err)
+
+		_, _, err = c.Encrypt(msg, nil, kh)
+		require.NoError(t, err)
+	})
+
+	t.Run("key wrapping algorithms", func(t *testing.T) {
+		keyID, pubKey, err := kmsService.CreateAndExportPubKeyBytes(kms.X25519Type)
+		require.NoError(t, err)
+
+		err = kmsService.UpdateMetadata(keyID, kms.WithPolicy(&kms.KeyPolicy{
+			Operations: []kms.KeyOperation{kms.UnwrapKeyOp},
+			Algorithms: []string{crypto.ECDH1PUA256KWAlg},
+		}))
+		require.NoError(t, err)
+
+		recKH, err := kmsService.Get(keyID)
+		require.NoError(t, err)
+
+		recPubKey := &crypto.PublicKey{KID: keyID, X: pubKey, Curve: crypto.X25519Curve, Type: "OKP"}
+		cek := make([]byte, 32)
+
+		_, err = rand.Read(cek)
+		require.NoError(t, err)
+
+		wk, err := c.WrapKey(cek, nil, nil, recPubKey)
+		require.NoError(t, err)
+
+		_, err = c.UnwrapKey(wk, recKH)
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+		require.Contains(t, err.Error(), "algorithm "+crypto.ECDHESA256KWAlg+" not allowed")
+
+		senderKeyID, senderPubKey, err := kmsService.CreateAndExportPubKeyBytes(kms.X25519Type)
+		require.NoError(t, err)
+
+		senderKH, err := kmsService.Get(senderKeyID)
+		require.NoError(t, err)
+
+		wk, err = c.WrapKey(cek, nil, nil, recPubKey, crypto.WithSender(senderKH))
+		require.NoError(t, err)
+
+		key, err := c.UnwrapKey(wk, recKH, crypto.WithSender(&crypto.PublicKey{
+			KID: senderKeyID, X: senderPubKey, Curve: crypto.X25519Curve, Type: "OKP",
+		}))
+		require.NoError(t, err)
+		require.Equal(t, cek, key)
+	})
+
+	t.Run("rotated key keeps its policy", func(t *testing.T) {
+		keyID, _, err := kmsService.Create(kms.HMACSHA256Tag256Type)
+		require.NoError(t, err)
+
+		policy := &kms.KeyPolicy{Operations: []kms.KeyOperation{kms.ComputeMACOp}}
+
+		require.NoError(t, kmsService.UpdateMetadata(keyID, kms.WithPolicy(policy)))
+
+		newKeyID, kh, err := kmsService.Rotate(kms.HMACSHA256Tag256Type, keyID)
+		require.NoError(t, err)
+
+		md, err := kmsService.GetMetadata(newKeyID)
+		require.NoError(t, err)
+		require.Equal(t, policy, md.Policy)
+
+		err = c.VerifyMAC(nil, msg, kh)
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+	})
+}
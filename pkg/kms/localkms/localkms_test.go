@@ -14,16 +14,23 @@ import (
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/google/tink/go/aead"
 	"github.com/google/tink/go/keyset"
+	"github.com/google/tink/go/signature"
 	"github.com/google/tink/go/subtle/random"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/curve25519"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs"
+	bbssubtle "github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs/subtle"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms/internal/keywrapper"
 	mocksecretlock "github.com/hyperledger/aries-framework-go/pkg/mock/secretlock"
@@ -204,6 +211,9 @@ func TestLocalKMS_Success(t *testing.T) {
 		kms.AES256GCMType,
 		kms.ChaCha20Poly1305Type,
 		kms.XChaCha20Poly1305Type,
+		kms.AES128GCMHKDF4KBType,
+		kms.AES256GCMHKDF4KBType,
+		kms.AES256GCMHKDF1MBType,
 		kms.ECDSAP256TypeDER,
 		kms.ECDSAP384TypeDER,
 		kms.ECDSAP521TypeDER,
@@ -212,6 +222,11 @@ func TestLocalKMS_Success(t *testing.T) {
 		kms.ECDSAP521TypeIEEEP1363,
 		kms.ED25519Type,
 		kms.ECDHES256AES256GCMType,
+		kms.ECDHES256XChaCha20Poly1305Type,
+		kms.ECDHES256ChaCha20Poly1305Type,
+		kms.ECDSASecp256k1TypeDER,
+		kms.ECDSASecp256k1TypeIEEEP1363,
+		kms.BLS12381G2Type,
 	}
 
 	for _, v := range keyTemplates {
@@ -259,7 +274,7 @@ func TestLocalKMS_Success(t *testing.T) {
 		require.Equal(t, len(newKHPrimitives.Entries), len(rotatedKHPrimitives.Entries))
 		require.Equal(t, len(readKHPrimitives.Entries), len(rotatedKHPrimitives.Entries))
 
-		if strings.Contains(string(v), "ECDSA") || v == kms.ED25519Type {
+		if strings.Contains(string(v), "ECDSA") || v == kms.ED25519Type || v == kms.BLS12381G2Type {
 			pubKeyBytes, e := kmsService.ExportPubKeyBytes(keyID)
 			require.Errorf(t, e, "KeyID has been rotated. An error must be returned")
 			require.Empty(t, pubKeyBytes)
@@ -336,6 +351,20 @@ func TestLocalKMS_ImportPrivateKey(t *testing.T) {
 			tcName:  "import private key using ED25519Type type",
 			keyType: kms.ED25519Type,
 		},
+		{
+			tcName:  "import private key using ECDSASecp256k1TypeDER type",
+			keyType: kms.ECDSASecp256k1TypeDER,
+			curve:   btcec.S256(),
+		},
+		{
+			tcName:  "import private key using ECDSASecp256k1TypeIEEEP1363 type",
+			keyType: kms.ECDSASecp256k1TypeIEEEP1363,
+			curve:   btcec.S256(),
+		},
+		{
+			tcName:  "import private key using X25519Type type",
+			keyType: kms.X25519Type,
+		},
 		{
 			tcName:  "import private key using ECDSAP256DER type and a set empty KeyID",
 			keyType: kms.ECDSAP256TypeDER,
@@ -377,6 +406,20 @@ func TestLocalKMS_ImportPrivateKey(t *testing.T) {
 				return
 			}
 
+			if tt.keyType == kms.X25519Type {
+				privKey := random.GetRandomBytes(curve25519.ScalarSize)
+				pubKey, err := curve25519.X25519(privKey, curve25519.Basepoint)
+				require.NoError(t, err)
+
+				ksID, _, err := kmsService.ImportPrivateKey(privKey, tt.keyType)
+				require.NoError(t, err)
+
+				pubKeyBytes, err := kmsService.ExportPubKeyBytes(ksID)
+				require.NoError(t, err)
+				require.EqualValues(t, pubKey, pubKeyBytes)
+				return
+			}
+
 			privKey, err := ecdsa.GenerateKey(tt.curve, rand.Reader)
 			require.NoError(t, err)
 
@@ -385,7 +428,7 @@ func TestLocalKMS_ImportPrivateKey(t *testing.T) {
 			// test ImportPrivateKey
 			if tt.setID {
 				// with set keyset ID
-				ksID, _, err = kmsService.ImportPrivateKey(privKey, tt.keyType, WithKeyID(tt.ksID))
+				ksID, _, err = kmsService.ImportPrivateKey(privKey, tt.keyType, kms.WithKeyID(tt.ksID))
 				if strings.Contains(tt.tcName, "larger than maxKeyIDLen") {
 					require.Contains(t, err.Error(),
 						fmt.Sprintf("is longer than max allowed length of %d", maxKeyIDLen))
@@ -393,7 +436,7 @@ func TestLocalKMS_ImportPrivateKey(t *testing.T) {
 				}
 
 				require.NoError(t, err)
-				// calling ImportPrivatekeyt and WithKeyID("") will ignore the set KeyID and generate a new one
+				// calling ImportPrivatekeyt and kms.WithKeyID("") will ignore the set KeyID and generate a new one
 				if tt.ksID != "" {
 					require.Equal(t, tt.ksID, ksID)
 				}
@@ -412,7 +455,8 @@ func TestLocalKMS_ImportPrivateKey(t *testing.T) {
 				pubKey, err := x509.MarshalPKIXPublicKey(privKey.Public())
 				require.NoError(t, err)
 				require.EqualValues(t, pubKey, pubKeyBytes)
-			case kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363:
+			case kms.ECDSAP256TypeIEEEP1363, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeIEEEP1363,
+				kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363:
 				pubKey := elliptic.Marshal(tt.curve, privKey.X, privKey.Y)
 				require.EqualValues(t, pubKey, pubKeyBytes)
 			}
@@ -420,6 +464,164 @@ func TestLocalKMS_ImportPrivateKey(t *testing.T) {
 	}
 }
 
+func TestLocalKMS_X25519(t *testing.T) {
+	kmsService, err := New(testMasterKeyURI, &mockProvider{
+		storage:    mockstorage.NewMockStoreProvider(),
+		secretLock: createMasterKeyAndSecretLock(t),
+	})
+	require.NoError(t, err)
+
+	t.Run("create and export X25519 key", func(t *testing.T) {
+		keyID, pubKeyBytes, err := kmsService.CreateAndExportPubKeyBytes(kms.X25519Type)
+		require.NoError(t, err)
+		require.Len(t, pubKeyBytes, curve25519.PointSize)
+		require.Equal(t, kms.PublicKeyID(pubKeyBytes), keyID)
+
+		exported, err := kmsService.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Equal(t, pubKeyBytes, exported)
+
+		md, err := kmsService.GetMetadata(keyID)
+		require.NoError(t, err)
+		require.Equal(t, kms.X25519Type, md.Type)
+	})
+
+	t.Run("import secp256k1 key from a btcec private key", func(t *testing.T) {
+		privKey, err := btcec.NewPrivateKey(btcec.S256())
+		require.NoError(t, err)
+
+		keyID, _, err := kmsService.ImportPrivateKey(privKey, kms.ECDSASecp256k1TypeIEEEP1363)
+		require.NoError(t, err)
+
+		pubKeyBytes, err := kmsService.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Equal(t, privKey.PubKey().SerializeUncompressed(), pubKeyBytes)
+	})
+}
+
+func TestLocalKMS_BLS12381G2(t *testing.T) {
+	kmsService, err := New(testMasterKeyURI, &mockProvider{
+		storage:    mockstorage.NewMockStoreProvider(),
+		secretLock: createMasterKeyAndSecretLock(t),
+	})
+	require.NoError(t, err)
+
+	keyID, pubKeyBytes, err := kmsService.CreateAndExportPubKeyBytes(kms.BLS12381G2Type)
+	require.NoError(t, err)
+	require.Len(t, pubKeyBytes, bbssubtle.PublicKeySize)
+
+	kh, err := kmsService.Get(keyID)
+	require.NoError(t, err)
+
+	signer, err := bbs.NewSigner(kh.(*keyset.Handle))
+	require.NoError(t, err)
+
+	messages := [][]byte{[]byte("message 1"), []byte("message 2")}
+
+	sig, err := signer.Sign(messages)
+	require.NoError(t, err)
+
+	// the exported public key verifies the signatures of the key
+	pubKH, err := kmsService.PubKeyBytesToHandle(pubKeyBytes, kms.BLS12381G2Type)
+	require.NoError(t, err)
+
+	verifier, err := bbs.NewVerifier(pubKH)
+	require.NoError(t, err)
+	require.NoError(t, verifier.Verify(messages, sig))
+}
+
+func TestLocalKMS_RemovePreviousVersions(t *testing.T) {
+	kmsService, err := New(testMasterKeyURI, &mockProvider{
+		storage:    mockstorage.NewMockStoreProvider(),
+		secretLock: createMasterKeyAndSecretLock(t),
+	})
+	require.NoError(t, err)
+
+	keyID, kh, err := kmsService.Create(kms.AES256GCMType)
+	require.NoError(t, err)
+
+	a, err := aead.New(kh.(*keyset.Handle))
+	require.NoError(t, err)
+
+	ct, err := a.Encrypt([]byte("plaintext"), nil)
+	require.NoError(t, err)
+
+	// nothing to remove from a key which wasn't rotated
+	require.NoError(t, kmsService.RemovePreviousVersions(keyID))
+
+	newKeyID, _, err := kmsService.Rotate(kms.AES256GCMType, keyID)
+	require.NoError(t, err)
+
+	md, err := kmsService.GetMetadata(newKeyID)
+	require.NoError(t, err)
+	require.Equal(t, 1, md.PreviousVersions)
+
+	// the previous ID of the key gets the rotated key decrypting the data of its previous version
+	kh, err = kmsService.Get(keyID)
+	require.NoError(t, err)
+
+	a, err = aead.New(kh.(*keyset.Handle))
+	require.NoError(t, err)
+
+	pt, err := a.Decrypt(ct, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("plaintext"), pt)
+
+	require.NoError(t, kmsService.RemovePreviousVersions(newKeyID))
+
+	md, err = kmsService.GetMetadata(newKeyID)
+	require.NoError(t, err)
+	require.Zero(t, md.PreviousVersions)
+	require.Equal(t, []string{keyID}, md.PreviousKeyIDs)
+
+	_, err = kmsService.Get(keyID)
+	require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+
+	kh, err = kmsService.Get(newKeyID)
+	require.NoError(t, err)
+
+	a, err = aead.New(kh.(*keyset.Handle))
+	require.NoError(t, err)
+
+	_, err = a.Decrypt(ct, nil)
+	require.Error(t, err)
+
+	err = kmsService.RemovePreviousVersions("unknown")
+	require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+}
+
+func TestLocalKMS_GetPreviousVersion(t *testing.T) {
+	kmsService, err := New(testMasterKeyURI, &mockProvider{
+		storage:    mockstorage.NewMockStoreProvider(),
+		secretLock: createMasterKeyAndSecretLock(t),
+	})
+	require.NoError(t, err)
+
+	keyID, pubKey, err := kmsService.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	require.NoError(t, err)
+
+	newKeyID, _, err := kmsService.Rotate(kms.ED25519Type, keyID)
+	require.NoError(t, err)
+
+	newPubKey, err := kmsService.ExportPubKeyBytes(newKeyID)
+	require.NoError(t, err)
+
+	msg := []byte("lorem ipsum")
+
+	// the previous ID of the key signs with its version until the previous versions are removed
+	for id, pub := range map[string][]byte{keyID: pubKey, newKeyID: newPubKey} {
+		kh, e := kmsService.Get(id)
+		require.NoError(t, e)
+
+		signer, e := signature.NewSigner(kh.(*keyset.Handle))
+		require.NoError(t, e)
+
+		sig, e := signer.Sign(msg)
+		require.NoError(t, e)
+		require.True(t, ed25519.Verify(pub, msg, sig))
+	}
+}
+
 func TestLocalKMS_getKeyTemplate(t *testing.T) {
 	keyTemplate, err := getKeyTemplate(kms.HMACSHA256Tag256Type)
 	require.NoError(t, err)
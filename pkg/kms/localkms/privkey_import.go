@@ -12,23 +12,28 @@ import (
 	"crypto/ed25519"
 	"fmt"
 
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/golang/protobuf/proto"
+	"github.com/google/tink/go/insecurecleartextkeyset"
 	"github.com/google/tink/go/keyset"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
 	ed25519pb "github.com/google/tink/go/proto/ed25519_go_proto"
 	tinkpb "github.com/google/tink/go/proto/tink_go_proto"
+	"golang.org/x/crypto/curve25519"
 
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 const (
-	ecdsaSignerTypeURL   = "type.googleapis.com/google.crypto.tink.EcdsaPrivateKey"
-	ed25519SignerTypeURL = "type.googleapis.com/google.crypto.tink.Ed25519PrivateKey"
+	ecdsaSignerTypeURL      = "type.googleapis.com/google.crypto.tink.EcdsaPrivateKey"
+	ed25519SignerTypeURL    = "type.googleapis.com/google.crypto.tink.Ed25519PrivateKey"
+	secp256k1SignerTypeURL  = "type.hyperledger.org/hyperledger.aries.crypto.tink.Secp256k1PrivateKey"
+	x25519PrivateKeyTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.X25519PrivateKey"
 )
 
 func (l *LocalKMS) importECDSAKey(privKey *ecdsa.PrivateKey, kt kms.KeyType,
-	opts ...PrivateKeyOpts) (string, *keyset.Handle, error) {
+	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
 	var params *ecdsapb.EcdsaParams
 
 	err := validECPrivateKey(privKey)
@@ -36,6 +41,8 @@ func (l *LocalKMS) importECDSAKey(privKey *ecdsa.PrivateKey, kt kms.KeyType,
 		return "", nil, fmt.Errorf("import private EC key failed: %w", err)
 	}
 
+	tURL := ecdsaSignerTypeURL
+
 	switch kt {
 	case kms.ECDSAP256TypeDER:
 		params = &ecdsapb.EcdsaParams{
@@ -73,6 +80,13 @@ func (l *LocalKMS) importECDSAKey(privKey *ecdsa.PrivateKey, kt kms.KeyType,
 			Encoding: ecdsapb.EcdsaSignatureEncoding_IEEE_P1363,
 			HashType: commonpb.HashType_SHA512,
 		}
+	case kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363:
+		if privKey.Curve != btcec.S256() {
+			return "", nil, fmt.Errorf("import private EC key failed: private key is not a secp256k1 key")
+		}
+
+		tURL = secp256k1SignerTypeURL
+		params = secp256k1Params(kt)
 	default:
 		return "", nil, fmt.Errorf("import private EC key failed: invalid ECDSA key type")
 	}
@@ -82,12 +96,12 @@ func (l *LocalKMS) importECDSAKey(privKey *ecdsa.PrivateKey, kt kms.KeyType,
 		return "", nil, fmt.Errorf("import private EC key failed: %w", err)
 	}
 
-	ks := newKeySet(ecdsaSignerTypeURL, mKeyValue, tinkpb.KeyData_ASYMMETRIC_PRIVATE)
+	ks := newKeySet(tURL, mKeyValue, tinkpb.KeyData_ASYMMETRIC_PRIVATE)
 
 	return l.importKeySet(ks, opts...)
 }
 
-func (l *LocalKMS) importKeySet(ks *tinkpb.Keyset, opts ...PrivateKeyOpts) (string, *keyset.Handle, error) {
+func (l *LocalKMS) importKeySet(ks *tinkpb.Keyset, opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
 	ksID, err := l.writeImportedKey(ks, opts...)
 	if err != nil {
 		return "", nil, fmt.Errorf("import private EC key failed: %w", err)
@@ -107,7 +121,7 @@ func getMarshalledECDSAPrivateKey(privKey *ecdsa.PrivateKey, params *ecdsapb.Ecd
 }
 
 func (l *LocalKMS) importEd25519Key(privKey ed25519.PrivateKey, kt kms.KeyType,
-	opts ...PrivateKeyOpts) (string, *keyset.Handle, error) {
+	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
 	if privKey == nil {
 		return "", nil, fmt.Errorf("import private ED25519 key failed: private key is nil")
 	}
@@ -131,6 +145,51 @@ func (l *LocalKMS) importEd25519Key(privKey ed25519.PrivateKey, kt kms.KeyType,
 	return l.importKeySet(ks, opts...)
 }
 
+func (l *LocalKMS) importX25519Key(privKey []byte, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, *keyset.Handle, error) {
+	if kt != kms.X25519Type {
+		return "", nil, fmt.Errorf("import private X25519 key failed: invalid key type")
+	}
+
+	if len(privKey) != curve25519.ScalarSize {
+		return "", nil, fmt.Errorf("import private X25519 key failed: invalid key size")
+	}
+
+	pubKey, err := curve25519.X25519(privKey, curve25519.Basepoint)
+	if err != nil {
+		return "", nil, fmt.Errorf("import private X25519 key failed: %w", err)
+	}
+
+	// X25519 keys are serialized as Ed25519 protos, see the tinkcrypto x25519 primitive
+	mKeyValue, err := proto.Marshal(&ed25519pb.Ed25519PrivateKey{
+		Version:   0,
+		PublicKey: &ed25519pb.Ed25519PublicKey{Version: 0, KeyValue: pubKey},
+		KeyValue:  privKey,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("import private X25519 key failed: %w", err)
+	}
+
+	ks := newKeySet(x25519PrivateKeyTypeURL, mKeyValue, tinkpb.KeyData_ASYMMETRIC_PRIVATE)
+
+	return l.importKeySet(ks, opts...)
+}
+
+// secp256k1Params returns the EcdsaParams of secp256k1 keys of type kt. The curve is left to UNKNOWN_CURVE since Tink
+// doesn't define secp256k1, the curve is given by the key type URL instead.
+func secp256k1Params(kt kms.KeyType) *ecdsapb.EcdsaParams {
+	encoding := ecdsapb.EcdsaSignatureEncoding_DER
+	if kt == kms.ECDSASecp256k1TypeIEEEP1363 {
+		encoding = ecdsapb.EcdsaSignatureEncoding_IEEE_P1363
+	}
+
+	return &ecdsapb.EcdsaParams{
+		Curve:    commonpb.EllipticCurveType_UNKNOWN_CURVE,
+		Encoding: encoding,
+		HashType: commonpb.HashType_SHA256,
+	}
+}
+
 func validECPrivateKey(privateKey *ecdsa.PrivateKey) error {
 	if privateKey == nil {
 		return fmt.Errorf("private key is nil")
@@ -178,7 +237,10 @@ func newProtoEd25519PrivateKey(privateKey ed25519.PrivateKey) (*ed25519pb.Ed2551
 	}, nil
 }
 
-func (l *LocalKMS) writeImportedKey(ks *tinkpb.Keyset, opts ...PrivateKeyOpts) (string, error) {
+func (l *LocalKMS) writeImportedKey(ks *tinkpb.Keyset, opts ...kms.PrivateKeyOpts) (string, error) {
+	l.masterKeyMutex.RLock()
+	defer l.masterKeyMutex.RUnlock()
+
 	serializedKeyset, err := proto.Marshal(ks)
 	if err != nil {
 		return "", fmt.Errorf("invalid keyset data")
@@ -207,6 +269,12 @@ func (l *LocalKMS) writeImportedKey(ks *tinkpb.Keyset, opts ...PrivateKeyOpts) (
 		return "", fmt.Errorf("failed to write keyset as json: %w", err)
 	}
 
+	// the imported key is read in memory only to get its public key ID, opts override it
+	kh, err := insecurecleartextkeyset.Read(&keyset.MemReaderWriter{Keyset: ks})
+	if err == nil {
+		opts = append(publicKeyIDOpts(kh), opts...)
+	}
+
 	return writeToStore(l.store, buf, opts...)
 }
 
@@ -44,6 +44,27 @@ func TestImportECDSAKeyWithInvalidKey(t *testing.T) {
 		"unsupported key type")
 }
 
+func TestImportSecp256k1KeyWithInvalidKey(t *testing.T) {
+	k := createKMS(t)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, _, err = k.importECDSAKey(privKey, kms.ECDSASecp256k1TypeDER)
+	require.EqualError(t, err, "import private EC key failed: private key is not a secp256k1 key")
+}
+
+func TestImportX25519KeyWithInvalidKey(t *testing.T) {
+	k := createKMS(t)
+	errPrefix := "import private X25519 key failed: "
+
+	_, _, err := k.importX25519Key(make([]byte, 32), kms.ED25519Type)
+	require.EqualError(t, err, errPrefix+"invalid key type")
+
+	_, _, err = k.importX25519Key([]byte("bad key"), kms.X25519Type)
+	require.EqualError(t, err, errPrefix+"invalid key size")
+}
+
 func TestImportEd25519KeyWitnInvalidKey(t *testing.T) {
 	k := createKMS(t)
 	errPrefix := "import private ED25519 key failed: "
@@ -72,6 +93,7 @@ func TestImportKeySetInvalid(t *testing.T) {
 
 	storeProvider := storageGoMocks.NewMockProvider(ctrl)
 	storeProvider.EXPECT().OpenStore(Namespace).Return(store, nil).AnyTimes()
+	storeProvider.EXPECT().OpenStore(MetadataNamespace).Return(storageGoMocks.NewMockStore(ctrl), nil).AnyTimes()
 
 	var flagTests = []struct {
 		tcName        string
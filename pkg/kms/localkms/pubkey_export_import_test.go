@@ -18,6 +18,9 @@ import (
 	"github.com/google/tink/go/signature"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/bbs"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/secp256k1"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto/primitive/x25519"
 	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
@@ -70,6 +73,28 @@ func TestPubKeyExportAndRead(t *testing.T) {
 			keyTemplate: signature.ED25519KeyWithoutPrefixTemplate(),
 			doSign:      true,
 		},
+		{
+			tcName:      "export then read ECDSASecp256k1DER public key",
+			keyType:     kms.ECDSASecp256k1TypeDER,
+			keyTemplate: secp256k1.DERKeyTemplate(),
+			doSign:      true,
+		},
+		{
+			tcName:      "export then read ECDSASecp256k1IEEEP1363 public key",
+			keyType:     kms.ECDSASecp256k1TypeIEEEP1363,
+			keyTemplate: secp256k1.IEEEP1363KeyTemplate(),
+			doSign:      true,
+		},
+		{
+			tcName:      "export then read BLS12381G2 public key",
+			keyType:     kms.BLS12381G2Type,
+			keyTemplate: bbs.BLS12381G2KeyTemplate(),
+		},
+		{
+			tcName:      "export then read X25519 public key",
+			keyType:     kms.X25519Type,
+			keyTemplate: x25519.KeyTemplate(),
+		},
 	}
 
 	for _, tc := range flagTests {
@@ -12,6 +12,7 @@ import (
 	"crypto/x509"
 	"fmt"
 
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/golang/protobuf/proto"
 	"github.com/google/tink/go/insecurecleartextkeyset"
 	"github.com/google/tink/go/keyset"
@@ -140,6 +141,27 @@ func getMarshalledProtoKeyAndKeyURL(pubKey []byte, kt kms.KeyType) ([]byte, stri
 		if err != nil {
 			return nil, "", err
 		}
+	case kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363:
+		tURL = secp256k1VerifierTypeURL
+
+		keyValue, err = getMarshalledSecp256k1Key(pubKey, kt)
+		if err != nil {
+			return nil, "", err
+		}
+	case kms.X25519Type:
+		tURL = x25519PublicKeyTypeURL
+
+		keyValue, err = getMarshalledRawKey(pubKey)
+		if err != nil {
+			return nil, "", err
+		}
+	case kms.BLS12381G2Type:
+		tURL = bbsVerifierTypeURL
+
+		keyValue, err = getMarshalledRawKey(pubKey)
+		if err != nil {
+			return nil, "", err
+		}
 	case kms.ED25519Type:
 		tURL = ed25519VerifierTypeURL
 		pubKeyProto := new(ed25519pb.Ed25519PublicKey)
@@ -206,6 +228,21 @@ func getMarshalledECDSAIEEEP1363Key(marshaledPubKey []byte, curveName string, c
 	return getMarshalledECDSAKey(&ecdsa.PublicKey{X: x, Y: y, Curve: curve}, params)
 }
 
+// getMarshalledRawKey returns the serialized proto of a raw public key value. X25519 and BBS+ public keys are
+// serialized as Ed25519 protos since they hold a raw key value only.
+func getMarshalledRawKey(pubKey []byte) ([]byte, error) {
+	return proto.Marshal(&ed25519pb.Ed25519PublicKey{Version: 0, KeyValue: append([]byte{}, pubKey...)})
+}
+
+func getMarshalledSecp256k1Key(marshaledPubKey []byte, kt kms.KeyType) ([]byte, error) {
+	x, y := elliptic.Unmarshal(btcec.S256(), marshaledPubKey)
+	if x == nil || y == nil {
+		return nil, fmt.Errorf("failed to unamrshal public secp256k1 key")
+	}
+
+	return getMarshalledECDSAKey(&ecdsa.PublicKey{X: x, Y: y, Curve: btcec.S256()}, secp256k1Params(kt))
+}
+
 func getMarshalledECDSAKey(ecPubKey *ecdsa.PublicKey, params *ecdsapb.EcdsaParams) ([]byte, error) {
 	return proto.Marshal(newProtoECDSAPublicKey(ecPubKey, params))
 }
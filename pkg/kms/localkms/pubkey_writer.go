@@ -14,6 +14,7 @@ import (
 	"io"
 	"math/big"
 
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/golang/protobuf/proto"
 	commonpb "github.com/google/tink/go/proto/common_go_proto"
 	ecdsapb "github.com/google/tink/go/proto/ecdsa_go_proto"
@@ -23,13 +24,17 @@ import (
 )
 
 const (
-	ecdsaVerifierTypeURL   = "type.googleapis.com/google.crypto.tink.EcdsaPublicKey"
-	ed25519VerifierTypeURL = "type.googleapis.com/google.crypto.tink.Ed25519PublicKey"
+	ecdsaVerifierTypeURL     = "type.googleapis.com/google.crypto.tink.EcdsaPublicKey"
+	ed25519VerifierTypeURL   = "type.googleapis.com/google.crypto.tink.Ed25519PublicKey"
+	secp256k1VerifierTypeURL = "type.hyperledger.org/hyperledger.aries.crypto.tink.Secp256k1PublicKey"
+	x25519PublicKeyTypeURL   = "type.hyperledger.org/hyperledger.aries.crypto.tink.X25519PublicKey"
+	bbsVerifierTypeURL       = "type.hyperledger.org/hyperledger.aries.crypto.tink.BBSPublicKey"
 )
 
 // PubKeyWriter will write the raw bytes of a Tink KeySet's primary public key
 // The keyset must be one of the keyURLs defined above
-// Note: Only signing public keys can be exported through this PubKeyWriter.
+// Note: Only signing public keys (including BBS+ keys as compressed BLS12-381 G2 points) and X25519 public keys can be
+// exported through this PubKeyWriter.
 // ECHDES has its own Writer to export its public keys due to cyclic dependency.
 type PubKeyWriter struct {
 	w io.Writer
@@ -62,7 +67,8 @@ func write(w io.Writer, msg *tinkpb.Keyset) error {
 	for _, key := range ks {
 		if key.KeyId == primaryKID && key.Status == tinkpb.KeyStatusType_ENABLED {
 			switch key.KeyData.TypeUrl {
-			case ecdsaVerifierTypeURL, ed25519VerifierTypeURL:
+			case ecdsaVerifierTypeURL, ed25519VerifierTypeURL, secp256k1VerifierTypeURL, x25519PublicKeyTypeURL,
+				bbsVerifierTypeURL:
 				created, err = writePubKey(w, key)
 				if err != nil {
 					return err
@@ -98,7 +104,18 @@ func writePubKey(w io.Writer, key *tinkpb.Keyset_Key) (bool, error) {
 		if err != nil {
 			return false, err
 		}
-	case ed25519VerifierTypeURL:
+	case secp256k1VerifierTypeURL:
+		pubKeyProto := new(ecdsapb.EcdsaPublicKey)
+
+		err := proto.Unmarshal(key.KeyData.Value, pubKeyProto)
+		if err != nil {
+			return false, err
+		}
+
+		// secp256k1 public keys are always exported uncompressed, whatever their signature encoding
+		marshaledRawPubKey = elliptic.Marshal(btcec.S256(),
+			new(big.Int).SetBytes(pubKeyProto.X), new(big.Int).SetBytes(pubKeyProto.Y))
+	case ed25519VerifierTypeURL, x25519PublicKeyTypeURL, bbsVerifierTypeURL:
 		pubKeyProto := new(ed25519pb.Ed25519PublicKey)
 
 		err := proto.Unmarshal(key.KeyData.Value, pubKeyProto)
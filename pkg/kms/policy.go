@@ -0,0 +1,63 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"errors"
+	"time"
+)
+
+// KeyOperation is a crypto operation using a key.
+type KeyOperation string
+
+const (
+	// SignOp signs messages (crypto.Sign and crypto.SignMulti).
+	SignOp = KeyOperation("sign")
+	// VerifyOp verifies signatures and proofs (crypto.Verify, crypto.VerifyMulti and crypto.VerifyProof).
+	VerifyOp = KeyOperation("verify")
+	// DeriveProofOp derives BBS+ proofs (crypto.DeriveProof).
+	DeriveProofOp = KeyOperation("deriveProof")
+	// EncryptOp encrypts messages (crypto.Encrypt).
+	EncryptOp = KeyOperation("encrypt")
+	// DecryptOp decrypts messages (crypto.Decrypt).
+	DecryptOp = KeyOperation("decrypt")
+	// ComputeMACOp computes MACs (crypto.ComputeMAC).
+	ComputeMACOp = KeyOperation("computeMAC")
+	// VerifyMACOp verifies MACs (crypto.VerifyMAC).
+	VerifyMACOp = KeyOperation("verifyMAC")
+	// WrapKeyOp wraps keys as the sender of ECDH-1PU key wrapping (crypto.WrapKey) and seals boxes (crypto.Easy).
+	WrapKeyOp = KeyOperation("wrapKey")
+	// UnwrapKeyOp unwraps keys (crypto.UnwrapKey) and opens boxes (crypto.EasyOpen and crypto.SealOpen).
+	UnwrapKeyOp = KeyOperation("unwrapKey")
+	// DeriveKEKOp derives key encryption keys (crypto.DeriveKEK).
+	DeriveKEKOp = KeyOperation("deriveKEK")
+)
+
+// ErrKeyPolicyViolation is returned when a crypto operation is denied by the policy of its key.
+var ErrKeyPolicyViolation = errors.New("key policy violation")
+
+// KeyPolicy restricts the usage of a key, its unset fields don't restrict anything.
+type KeyPolicy struct {
+	// Operations the key can be used for, eg only SignOp and VerifyOp for a sign-only key.
+	Operations []KeyOperation `json:"operations,omitempty"`
+	// Algorithms the key can be used with, they restrict the operations choosing an algorithm: the key wrapping
+	// algorithms (crypto.ECDHESA256KWAlg or crypto.ECDH1PUA256KWAlg) of WrapKeyOp and UnwrapKeyOp.
+	Algorithms []string `json:"algorithms,omitempty"`
+	// Expiry is the time after which the key can't be used anymore.
+	Expiry *time.Time `json:"expiry,omitempty"`
+	// MaxUsage is the number of operations the key can be used for.
+	MaxUsage int `json:"maxUsage,omitempty"`
+}
+
+// PolicyKeyHandle is the key handle of a key with a KeyPolicy. Its underlying key handle is only returned by the
+// authorization of an operation, so that Crypto implementations can't execute an operation the policy denies.
+type PolicyKeyHandle interface {
+	// Authorize checks that the key policy allows the operation op with the algorithm alg (empty for the operations
+	// without algorithm choice) and records the key usage. It returns the underlying key handle to execute the
+	// operation with, or an error wrapping ErrKeyPolicyViolation if the operation is denied.
+	Authorize(op KeyOperation, alg string) (interface{}, error)
+}
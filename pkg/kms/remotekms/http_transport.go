@@ -0,0 +1,130 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotekms
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const contentType = "application/json"
+
+// HTTPTransport sends the key server requests as JSON documents POSTed to the key server URL. The key server
+// answers with a JSON Response and a 200 status, or with a JSON ErrorResponse and a 404 status if the key of the
+// request doesn't exist, a 403 status if the key policy denies the operation, or another error status otherwise.
+type HTTPTransport struct {
+	url    string
+	client *http.Client
+	header http.Header
+}
+
+// HTTPOption configures the HTTP transport.
+type HTTPOption func(t *HTTPTransport)
+
+// WithHTTPClient option sets the HTTP client used to send the requests, eg to set up TLS or timeouts.
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(t *HTTPTransport) {
+		t.client = client
+	}
+}
+
+// WithHeader option adds a header to the requests, eg to authorize the agent with the key server.
+func WithHeader(name, value string) HTTPOption {
+	return func(t *HTTPTransport) {
+		t.header.Add(name, value)
+	}
+}
+
+// NewHTTPTransport creates a transport sending the key server requests to keyServerURL.
+func NewHTTPTransport(keyServerURL string, opts ...HTTPOption) (*HTTPTransport, error) {
+	_, err := url.ParseRequestURI(keyServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key server URL: %w", err)
+	}
+
+	t := &HTTPTransport{url: keyServerURL, client: &http.Client{}, header: http.Header{}}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t, nil
+}
+
+// Send sends req to the key server.
+func (t *HTTPTransport) Send(req *Request) (*Response, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal key server request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, t.url, bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key server request: %w", err)
+	}
+
+	for name, values := range t.header {
+		httpReq.Header[name] = values
+	}
+
+	httpReq.Header.Set("Content-Type", contentType)
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send key server request: %w", err)
+	}
+
+	defer closeResponseBody(resp.Body)
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key server response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp.StatusCode, respBytes)
+	}
+
+	res := &Response{}
+
+	err = json.Unmarshal(respBytes, res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal key server response: %w", err)
+	}
+
+	return res, nil
+}
+
+func responseError(status int, body []byte) error {
+	errResp := &ErrorResponse{}
+
+	if err := json.Unmarshal(body, errResp); err != nil || errResp.Error == "" {
+		return fmt.Errorf("key server: unexpected response status '%d' body %s", status, body)
+	}
+
+	switch status {
+	case http.StatusNotFound:
+		return fmt.Errorf("key server: %s: %w", errResp.Error, kms.ErrKeyNotFound)
+	case http.StatusForbidden:
+		return fmt.Errorf("key server: %s: %w", errResp.Error, kms.ErrKeyPolicyViolation)
+	}
+
+	return fmt.Errorf("key server: %s", errResp.Error)
+}
+
+func closeResponseBody(respBody io.Closer) {
+	if err := respBody.Close(); err != nil {
+		logger.Errorf("failed to close response body: %s", err)
+	}
+}
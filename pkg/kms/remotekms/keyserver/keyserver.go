@@ -0,0 +1,492 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package keyserver provides a reference key server serving the remotekms protocol with a kms.KeyManager and a
+// crypto.Crypto, typically localkms and tinkcrypto. The Server is an http.Handler to serve the HTTP transport of
+// the protocol, and a remotekms.Transport itself to be used in-process (eg in tests).
+package keyserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/google/tink/go/keyset"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/remotekms"
+)
+
+var logger = log.New("aries-framework/kms/remotekms/keyserver")
+
+var errUnknownOp = errors.New("unknown operation")
+
+// Server is a key server managing its keys with a kms.KeyManager and executing the crypto operations with a
+// crypto.Crypto.
+type Server struct {
+	km         kms.KeyManager
+	crypto     crypto.Crypto
+	operations map[string]func(req *remotekms.Request) (*remotekms.Response, error)
+}
+
+// New creates a key server for the keys of km.
+func New(km kms.KeyManager, c crypto.Crypto) *Server {
+	s := &Server{km: km, crypto: c}
+
+	s.operations = map[string]func(req *remotekms.Request) (*remotekms.Response, error){
+		remotekms.CreateKeyOp:          s.createKey,
+		remotekms.CreateAndExportKeyOp: s.createAndExportKey,
+		remotekms.RotateKeyOp:          s.rotateKey,
+		remotekms.ExportKeyOp:          s.exportKey,
+		remotekms.ImportKeyOp:          s.importKey,
+		remotekms.DeleteKeyOp:          s.deleteKey,
+		remotekms.ListKeysOp:           s.listKeys,
+		remotekms.GetMetadataOp:        s.getMetadata,
+		remotekms.UpdateMetadataOp:     s.updateMetadata,
+		remotekms.EncryptOp:            s.encrypt,
+		remotekms.DecryptOp:            s.decrypt,
+		remotekms.SignOp:               s.sign,
+		remotekms.VerifyOp:             s.verify,
+		remotekms.ComputeMACOp:         s.computeMAC,
+		remotekms.VerifyMACOp:          s.verifyMAC,
+		remotekms.WrapKeyOp:            s.wrapKey,
+		remotekms.UnwrapKeyOp:          s.unwrapKey,
+		remotekms.EasyOp:               s.easy,
+		remotekms.EasyOpenOp:           s.easyOpen,
+		remotekms.SealOpenOp:           s.sealOpen,
+		remotekms.DeriveKEKOp:          s.deriveKEK,
+		remotekms.SignMultiOp:          s.signMulti,
+		remotekms.VerifyMultiOp:        s.verifyMulti,
+		remotekms.DeriveProofOp:        s.deriveProof,
+		remotekms.VerifyProofOp:        s.verifyProof,
+	}
+
+	return s
+}
+
+// Send executes req, it implements remotekms.Transport.
+func (s *Server) Send(req *remotekms.Request) (*remotekms.Response, error) {
+	op, ok := s.operations[req.Op]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownOp, req.Op)
+	}
+
+	return op(req)
+}
+
+// ServeHTTP serves the requests of remotekms.HTTPTransport.
+func (s *Server) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(rw, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, fmt.Errorf("failed to read request: %w", err))
+		return
+	}
+
+	req := &remotekms.Request{}
+
+	err = json.Unmarshal(body, req)
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, fmt.Errorf("failed to unmarshal request: %w", err))
+		return
+	}
+
+	resp, err := s.Send(req)
+
+	switch {
+	case errors.Is(err, kms.ErrKeyNotFound):
+		writeError(rw, http.StatusNotFound, err)
+	case errors.Is(err, kms.ErrKeyPolicyViolation):
+		writeError(rw, http.StatusForbidden, err)
+	case errors.Is(err, errUnknownOp):
+		writeError(rw, http.StatusBadRequest, err)
+	case err != nil:
+		writeError(rw, http.StatusInternalServerError, err)
+	default:
+		writeResponse(rw, http.StatusOK, resp)
+	}
+}
+
+func writeError(rw http.ResponseWriter, status int, err error) {
+	writeResponse(rw, status, &remotekms.ErrorResponse{Error: err.Error()})
+}
+
+func writeResponse(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	if err := json.NewEncoder(rw).Encode(v); err != nil {
+		logger.Errorf("failed to write key server response: %s", err)
+	}
+}
+
+func (s *Server) createKey(req *remotekms.Request) (*remotekms.Response, error) {
+	keyID, _, err := s.km.Create(req.KeyType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{KeyID: keyID}, nil
+}
+
+func (s *Server) createAndExportKey(req *remotekms.Request) (*remotekms.Response, error) {
+	keyID, pubKey, err := s.km.CreateAndExportPubKeyBytes(req.KeyType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{KeyID: keyID, PublicKey: pubKey}, nil
+}
+
+func (s *Server) rotateKey(req *remotekms.Request) (*remotekms.Response, error) {
+	keyID, _, err := s.km.Rotate(req.KeyType, req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{KeyID: keyID}, nil
+}
+
+func (s *Server) exportKey(req *remotekms.Request) (*remotekms.Response, error) {
+	pubKey, err := s.km.ExportPubKeyBytes(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{PublicKey: pubKey}, nil
+}
+
+func (s *Server) importKey(req *remotekms.Request) (*remotekms.Response, error) {
+	privKey, err := unmarshalPrivateKey(req.PrivateKey, req.KeyType)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []kms.PrivateKeyOpts
+
+	if req.KeyID != "" {
+		opts = append(opts, kms.WithKeyID(req.KeyID))
+	}
+
+	keyID, _, err := s.km.ImportPrivateKey(privKey, req.KeyType, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{KeyID: keyID}, nil
+}
+
+func (s *Server) deleteKey(req *remotekms.Request) (*remotekms.Response, error) {
+	return &remotekms.Response{}, s.km.Delete(req.KeyID)
+}
+
+func (s *Server) listKeys(*remotekms.Request) (*remotekms.Response, error) {
+	keys, err := s.km.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Keys: keys}, nil
+}
+
+func (s *Server) getMetadata(req *remotekms.Request) (*remotekms.Response, error) {
+	md, err := s.km.GetMetadata(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Metadata: md}, nil
+}
+
+func (s *Server) updateMetadata(req *remotekms.Request) (*remotekms.Response, error) {
+	if req.Metadata == nil {
+		return nil, errors.New("missing key metadata")
+	}
+
+	err := s.km.UpdateMetadata(req.KeyID,
+		kms.WithPurpose(req.Metadata.Purpose),
+		kms.WithDID(req.Metadata.DID, req.Metadata.VerificationMethod),
+		kms.WithPolicy(req.Metadata.Policy),
+		kms.WithPendingDIDUpdate(req.Metadata.PendingDIDUpdate))
+
+	return &remotekms.Response{}, err
+}
+
+func (s *Server) encrypt(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ct, nonce, err := s.crypto.Encrypt(req.Message, req.AAD, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: ct, Nonce: nonce}, nil
+}
+
+func (s *Server) decrypt(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	pt, err := s.crypto.Decrypt(req.Ciphertext, req.Nonce, req.AAD, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: pt}, nil
+}
+
+func (s *Server) sign(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := s.crypto.Sign(req.Message, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: sig}, nil
+}
+
+func (s *Server) verify(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.publicKeyHandle(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{}, s.crypto.Verify(req.Signature, req.Message, kh)
+}
+
+func (s *Server) computeMAC(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	mac, err := s.crypto.ComputeMAC(req.Message, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: mac}, nil
+}
+
+func (s *Server) verifyMAC(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{}, s.crypto.VerifyMAC(req.MAC, req.Message, kh)
+}
+
+func (s *Server) wrapKey(req *remotekms.Request) (*remotekms.Response, error) {
+	var opts []crypto.WrapKeyOpts
+
+	if req.SenderKeyID != "" {
+		senderKH, err := s.km.Get(req.SenderKeyID)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, crypto.WithSender(senderKH))
+	}
+
+	wk, err := s.crypto.WrapKey(req.CEK, req.APU, req.APV, req.RecipientPubKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{WrappedKey: wk}, nil
+}
+
+func (s *Server) unwrapKey(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []crypto.WrapKeyOpts
+
+	if req.SenderPubKey != nil {
+		opts = append(opts, crypto.WithSender(req.SenderPubKey))
+	}
+
+	key, err := s.crypto.UnwrapKey(req.WrappedKey, kh, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: key}, nil
+}
+
+func (s *Server) easy(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := s.crypto.Easy(req.Message, req.Nonce, req.TheirPubKey, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: sealed}, nil
+}
+
+func (s *Server) easyOpen(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := s.crypto.EasyOpen(req.Ciphertext, req.Nonce, req.TheirPubKey, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: payload}, nil
+}
+
+func (s *Server) sealOpen(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := s.crypto.SealOpen(req.Ciphertext, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: payload}, nil
+}
+
+func (s *Server) deriveKEK(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	kek, err := s.crypto.DeriveKEK(req.Alg, req.APU, req.TheirPubKey, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: kek}, nil
+}
+
+func (s *Server) signMulti(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.km.Get(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := s.crypto.SignMulti(req.Messages, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: sig}, nil
+}
+
+func (s *Server) verifyMulti(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.publicKeyHandle(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{}, s.crypto.VerifyMulti(req.Messages, req.Signature, kh)
+}
+
+func (s *Server) deriveProof(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.publicKeyHandle(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := s.crypto.DeriveProof(req.Messages, req.Signature, req.Nonce, req.RevealedIndexes, kh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{Data: proof}, nil
+}
+
+func (s *Server) verifyProof(req *remotekms.Request) (*remotekms.Response, error) {
+	kh, err := s.publicKeyHandle(req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &remotekms.Response{}, s.crypto.VerifyProof(req.Messages, req.Proof, req.Nonce, kh)
+}
+
+// publicKeyHandle returns the public key handle the verifications are done with, for the tink keysets of localkms.
+func (s *Server) publicKeyHandle(keyID string) (interface{}, error) {
+	kh, err := s.km.Get(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if h, ok := kh.(*keyset.Handle); ok {
+		return h.Public()
+	}
+
+	return kh, nil
+}
+
+// unmarshalPrivateKey parses the raw private key bytes sent by remotekms.RemoteKMS for the key type kt.
+func unmarshalPrivateKey(privKey []byte, kt kms.KeyType) (interface{}, error) {
+	switch kt {
+	case kms.ECDSAP256TypeDER, kms.ECDSAP256TypeIEEEP1363:
+		return ecdsaPrivateKey(elliptic.P256(), privKey), nil
+	case kms.ECDSAP384TypeDER, kms.ECDSAP384TypeIEEEP1363:
+		return ecdsaPrivateKey(elliptic.P384(), privKey), nil
+	case kms.ECDSAP521TypeDER, kms.ECDSAP521TypeIEEEP1363:
+		return ecdsaPrivateKey(elliptic.P521(), privKey), nil
+	case kms.ECDSASecp256k1TypeDER, kms.ECDSASecp256k1TypeIEEEP1363:
+		pk, _ := btcec.PrivKeyFromBytes(btcec.S256(), privKey)
+		return pk, nil
+	case kms.ED25519Type:
+		if len(privKey) != ed25519.PrivateKeySize {
+			return nil, errors.New("invalid ed25519 private key size")
+		}
+
+		return ed25519.PrivateKey(privKey), nil
+	case kms.X25519Type:
+		return privKey, nil
+	default:
+		return nil, fmt.Errorf("import private key does not support key type %s", kt)
+	}
+}
+
+func ecdsaPrivateKey(curve elliptic.Curve, d []byte) *ecdsa.PrivateKey {
+	privKey := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	privKey.Curve = curve
+	privKey.X, privKey.Y = curve.ScalarBaseMult(d)
+
+	return privKey
+}
@@ -0,0 +1,127 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/remotekms"
+	mockcrypto "github.com/hyperledger/aries-framework-go/pkg/mock/crypto"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+)
+
+func TestServer_Send(t *testing.T) {
+	errKMS := errors.New("kms error")
+	s := New(&mockkms.KeyManager{GetKeyErr: errKMS}, &mockcrypto.Crypto{})
+
+	_, err := s.Send(&remotekms.Request{Op: "unknown"})
+	require.EqualError(t, err, "unknown operation: unknown")
+
+	for _, op := range []string{
+		remotekms.EncryptOp, remotekms.DecryptOp, remotekms.SignOp, remotekms.VerifyOp, remotekms.ComputeMACOp,
+		remotekms.VerifyMACOp, remotekms.UnwrapKeyOp, remotekms.SignMultiOp, remotekms.VerifyMultiOp,
+		remotekms.DeriveProofOp, remotekms.VerifyProofOp,
+	} {
+		_, err = s.Send(&remotekms.Request{Op: op, KeyID: "keyID"})
+		require.True(t, errors.Is(err, errKMS), op)
+	}
+
+	_, err = s.Send(&remotekms.Request{Op: remotekms.WrapKeyOp, SenderKeyID: "keyID"})
+	require.True(t, errors.Is(err, errKMS))
+
+	_, err = s.Send(&remotekms.Request{Op: remotekms.UpdateMetadataOp, KeyID: "keyID"})
+	require.EqualError(t, err, "missing key metadata")
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	srv := httptest.NewServer(New(&mockkms.KeyManager{
+		GetKeyErr:    kms.ErrKeyNotFound,
+		CreateKeyErr: errors.New("create key error"),
+	}, &mockcrypto.Crypto{}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+		error  string
+	}{
+		{"method not allowed", http.MethodGet, "", http.StatusMethodNotAllowed, "method GET not allowed"},
+		{"invalid request", http.MethodPost, "not json", http.StatusBadRequest, "failed to unmarshal request"},
+		{"unknown operation", http.MethodPost, `{"op":"unknown"}`, http.StatusBadRequest, "unknown operation"},
+		{"key not found", http.MethodPost, `{"op":"sign","keyID":"123"}`, http.StatusNotFound, "key not found"},
+		{"operation error", http.MethodPost, `{"op":"createKey"}`, http.StatusInternalServerError, "create key error"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, srv.URL, strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			resp, err := srv.Client().Do(req)
+			require.NoError(t, err)
+
+			defer func() {
+				require.NoError(t, resp.Body.Close())
+			}()
+
+			require.Equal(t, tc.status, resp.StatusCode)
+			require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+			errResp := &remotekms.ErrorResponse{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(errResp))
+			require.Contains(t, errResp.Error, tc.error)
+		})
+	}
+}
+
+func TestUnmarshalPrivateKey(t *testing.T) {
+	d := []byte{
+		1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+		17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32,
+	}
+
+	for _, kt := range []kms.KeyType{kms.ECDSAP256TypeDER, kms.ECDSAP384TypeIEEEP1363, kms.ECDSAP521TypeDER} {
+		privKey, err := unmarshalPrivateKey(d, kt)
+		require.NoError(t, err)
+
+		ecPrivKey, ok := privKey.(*ecdsa.PrivateKey)
+		require.True(t, ok)
+		require.True(t, ecPrivKey.Curve.IsOnCurve(ecPrivKey.X, ecPrivKey.Y))
+	}
+
+	privKey, err := unmarshalPrivateKey(d, kms.ECDSASecp256k1TypeDER)
+	require.NoError(t, err)
+	require.IsType(t, &btcec.PrivateKey{}, privKey)
+
+	privKey, err = unmarshalPrivateKey(d, kms.X25519Type)
+	require.NoError(t, err)
+	require.Equal(t, d, privKey)
+
+	privKey, err = unmarshalPrivateKey(append(d, d...), kms.ED25519Type)
+	require.NoError(t, err)
+	require.IsType(t, ed25519.PrivateKey{}, privKey)
+
+	_, err = unmarshalPrivateKey(d, kms.ED25519Type)
+	require.EqualError(t, err, "invalid ed25519 private key size")
+
+	_, err = unmarshalPrivateKey(d, kms.AES256GCMType)
+	require.EqualError(t, err, "import private key does not support key type AES256GCM")
+}
@@ -0,0 +1,128 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotekms
+
+import (
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+// Operations of the key server protocol.
+const (
+	// CreateKeyOp creates a key of KeyType, the response holds its KeyID.
+	CreateKeyOp = "createKey"
+	// CreateAndExportKeyOp creates an asymmetric key of KeyType, the response holds its KeyID and PublicKey.
+	CreateAndExportKeyOp = "createAndExportKey"
+	// RotateKeyOp rotates the key KeyID with a new key of KeyType, the response holds the updated KeyID.
+	RotateKeyOp = "rotateKey"
+	// ExportKeyOp exports the public key of the asymmetric key KeyID, the response holds its PublicKey.
+	ExportKeyOp = "exportKey"
+	// ImportKeyOp imports PrivateKey as a key of KeyType with the optional KeyID, the response holds its KeyID.
+	ImportKeyOp = "importKey"
+	// DeleteKeyOp deletes the key KeyID.
+	DeleteKeyOp = "deleteKey"
+	// ListKeysOp lists the keys of the key server, the response holds their Keys metadata.
+	ListKeysOp = "listKeys"
+	// GetMetadataOp gets the metadata of the key KeyID, the response holds its Metadata.
+	GetMetadataOp = "getMetadata"
+	// UpdateMetadataOp updates the purpose, the DID, the verification method and the policy of the key KeyID with
+	// Metadata.
+	UpdateMetadataOp = "updateMetadata"
+
+	// EncryptOp encrypts Message and AAD with the key KeyID, the response holds the ciphertext in Data and its Nonce.
+	EncryptOp = "encrypt"
+	// DecryptOp decrypts Ciphertext with AAD and Nonce with the key KeyID, the response holds the plaintext in Data.
+	DecryptOp = "decrypt"
+	// SignOp signs Message with the key KeyID, the response holds the signature in Data.
+	SignOp = "sign"
+	// VerifyOp verifies the Signature of Message with the key KeyID.
+	VerifyOp = "verify"
+	// ComputeMACOp computes the MAC of Message with the key KeyID, the response holds the MAC in Data.
+	ComputeMACOp = "computeMAC"
+	// VerifyMACOp verifies the MAC of Message with the key KeyID.
+	VerifyMACOp = "verifyMAC"
+	// WrapKeyOp wraps CEK for RecipientPubKey with APU and APV, with ECDH-1PU if the sender key SenderKeyID is set.
+	// The response holds the WrappedKey.
+	WrapKeyOp = "wrapKey"
+	// UnwrapKeyOp unwraps WrappedKey with the key KeyID, ECDH-1PU wrapped keys require SenderPubKey. The response
+	// holds the unwrapped key in Data.
+	UnwrapKeyOp = "unwrapKey"
+	// EasyOp seals Message with Nonce for TheirPubKey with the key KeyID, the response holds the sealed box in Data.
+	EasyOp = "easy"
+	// EasyOpenOp opens the box Ciphertext sealed with Nonce by the owner of TheirPubKey with the key KeyID, the
+	// response holds the payload in Data.
+	EasyOpenOp = "easyOpen"
+	// SealOpenOp opens the box Ciphertext anonymously sealed for the key KeyID, the response holds the payload in Data.
+	SealOpenOp = "sealOpen"
+	// DeriveKEKOp derives a key encryption key from the key agreement of the key KeyID with TheirPubKey, with Alg and
+	// APU as KDF info. The response holds the key encryption key in Data.
+	DeriveKEKOp = "deriveKEK"
+	// SignMultiOp creates a BBS+ signature of Messages with the key KeyID, the response holds the signature in Data.
+	SignMultiOp = "signMulti"
+	// VerifyMultiOp verifies the BBS+ Signature of Messages with the key KeyID.
+	VerifyMultiOp = "verifyMulti"
+	// DeriveProofOp derives a proof of the BBS+ Signature of Messages revealing the messages at RevealedIndexes,
+	// with the key KeyID and Nonce. The response holds the proof in Data.
+	DeriveProofOp = "deriveProof"
+	// VerifyProofOp verifies the Proof of the revealed Messages with the key KeyID and Nonce.
+	VerifyProofOp = "verifyProof"
+)
+
+// Transport sends the requests of the key server protocol to a key server. Plugging in another Transport than the
+// HTTP one lets RemoteKMS and RemoteCrypto talk to any key server able to serve the protocol operations.
+// Send must return an error wrapping kms.ErrKeyNotFound when the key of the request doesn't exist.
+type Transport interface {
+	Send(req *Request) (*Response, error)
+}
+
+// Request is a request of the key server protocol, the fields used depend on the operation Op.
+type Request struct {
+	Op              string                      `json:"op"`
+	KeyID           string                      `json:"keyID,omitempty"`
+	KeyType         kms.KeyType                 `json:"keyType,omitempty"`
+	PrivateKey      []byte                      `json:"privateKey,omitempty"`
+	Metadata        *kms.KeyMetadata            `json:"metadata,omitempty"`
+	Message         []byte                      `json:"message,omitempty"`
+	Messages        [][]byte                    `json:"messages,omitempty"`
+	Ciphertext      []byte                      `json:"ciphertext,omitempty"`
+	AAD             []byte                      `json:"aad,omitempty"`
+	Nonce           []byte                      `json:"nonce,omitempty"`
+	Signature       []byte                      `json:"signature,omitempty"`
+	MAC             []byte                      `json:"mac,omitempty"`
+	Proof           []byte                      `json:"proof,omitempty"`
+	RevealedIndexes []int                       `json:"revealedIndexes,omitempty"`
+	CEK             []byte                      `json:"cek,omitempty"`
+	APU             []byte                      `json:"apu,omitempty"`
+	APV             []byte                      `json:"apv,omitempty"`
+	RecipientPubKey *crypto.PublicKey           `json:"recipientPubKey,omitempty"`
+	SenderKeyID     string                      `json:"senderKeyID,omitempty"`
+	SenderPubKey    *crypto.PublicKey           `json:"senderPubKey,omitempty"`
+	WrappedKey      *crypto.RecipientWrappedKey `json:"wrappedKey,omitempty"`
+	TheirPubKey     []byte                      `json:"theirPubKey,omitempty"`
+	Alg             []byte                      `json:"alg,omitempty"`
+}
+
+// Response is the response of the key server to a Request, Data holds the output of the crypto operations.
+type Response struct {
+	KeyID      string                      `json:"keyID,omitempty"`
+	PublicKey  []byte                      `json:"publicKey,omitempty"`
+	Keys       []*kms.KeyMetadata          `json:"keys,omitempty"`
+	Metadata   *kms.KeyMetadata            `json:"metadata,omitempty"`
+	Data       []byte                      `json:"data,omitempty"`
+	Nonce      []byte                      `json:"nonce,omitempty"`
+	WrappedKey *crypto.RecipientWrappedKey `json:"wrappedKey,omitempty"`
+}
+
+// ErrorResponse is the body of the HTTP responses of failed requests.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// KeyHandle is the key handle of the keys of RemoteKMS, it references a key of the key server.
+type KeyHandle struct {
+	KeyID string
+}
@@ -0,0 +1,210 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package remotekms provides a kms.KeyManager delegating key management to a remote key server, so that the private
+// keys of the agents are held by a central key server rather than in their local stores. The key server is reached
+// through a pluggable Transport (HTTPTransport by default), the keyserver package provides a reference key server.
+//
+// The key handles of RemoteKMS are *KeyHandle references to the keys of the key server, the crypto operations
+// using them are executed by the key server with pkg/crypto/remotecrypto.
+package remotekms
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+var logger = log.New("aries-framework/kms/remotekms")
+
+// RemoteKMS implements kms.KeyManager by sending the key management requests to a key server.
+type RemoteKMS struct {
+	transport Transport
+}
+
+// New creates a KMS managing the keys of the key server reached through transport.
+func New(transport Transport) *RemoteKMS {
+	return &RemoteKMS{transport: transport}
+}
+
+// Creator returns the kms.Creator of a RemoteKMS to set up the framework with aries.WithKMS().
+func Creator(transport Transport) kms.Creator {
+	return func(kms.Provider) (kms.KeyManager, error) {
+		return New(transport), nil
+	}
+}
+
+// Create a new key of type kt in the key server and return its keyID and key handle.
+func (r *RemoteKMS) Create(kt kms.KeyType) (string, interface{}, error) {
+	if kt == "" {
+		return "", nil, fmt.Errorf("failed to create new key, missing key type")
+	}
+
+	resp, err := r.transport.Send(&Request{Op: CreateKeyOp, KeyType: kt})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create new key: %w", err)
+	}
+
+	return resp.KeyID, &KeyHandle{KeyID: resp.KeyID}, nil
+}
+
+// Get the key handle of the key keyID, it returns an error wrapping kms.ErrKeyNotFound if the key server doesn't
+// have the key.
+func (r *RemoteKMS) Get(keyID string) (interface{}, error) {
+	_, err := r.GetMetadata(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyHandle{KeyID: keyID}, nil
+}
+
+// Rotate the key keyID with a new key of type kt and return its updated keyID and key handle.
+func (r *RemoteKMS) Rotate(kt kms.KeyType, keyID string) (string, interface{}, error) {
+	resp, err := r.transport.Send(&Request{Op: RotateKeyOp, KeyID: keyID, KeyType: kt})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to rotate key: %w", err)
+	}
+
+	return resp.KeyID, &KeyHandle{KeyID: resp.KeyID}, nil
+}
+
+// List returns the metadata of the keys of the key server.
+func (r *RemoteKMS) List() ([]*kms.KeyMetadata, error) {
+	resp, err := r.transport.Send(&Request{Op: ListKeysOp})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	return resp.Keys, nil
+}
+
+// GetMetadata returns the metadata of the key keyID.
+func (r *RemoteKMS) GetMetadata(keyID string) (*kms.KeyMetadata, error) {
+	resp, err := r.transport.Send(&Request{Op: GetMetadataOp, KeyID: keyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key metadata: %w", err)
+	}
+
+	if resp.Metadata == nil {
+		return nil, errors.New("failed to get key metadata: missing metadata in key server response")
+	}
+
+	return resp.Metadata, nil
+}
+
+// UpdateMetadata records what the key keyID is used for.
+func (r *RemoteKMS) UpdateMetadata(keyID string, opts ...kms.MetadataOpts) error {
+	md, err := r.GetMetadata(keyID)
+	if err != nil {
+		return err
+	}
+
+	for _, opt := range opts {
+		opt(md)
+	}
+
+	_, err = r.transport.Send(&Request{Op: UpdateMetadataOp, KeyID: keyID, Metadata: md})
+	if err != nil {
+		return fmt.Errorf("failed to update key metadata: %w", err)
+	}
+
+	return nil
+}
+
+// Delete the key keyID from the key server.
+func (r *RemoteKMS) Delete(keyID string) error {
+	_, err := r.transport.Send(&Request{Op: DeleteKeyOp, KeyID: keyID})
+	if err != nil {
+		return fmt.Errorf("failed to delete key: %w", err)
+	}
+
+	return nil
+}
+
+// ExportPubKeyBytes returns the raw bytes of the public key of the asymmetric key keyID.
+func (r *RemoteKMS) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	resp, err := r.transport.Send(&Request{Op: ExportKeyOp, KeyID: keyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to export public key bytes: %w", err)
+	}
+
+	return resp.PublicKey, nil
+}
+
+// CreateAndExportPubKeyBytes creates an asymmetric key of type kt and returns its keyID along with the raw bytes of
+// its public key.
+func (r *RemoteKMS) CreateAndExportPubKeyBytes(kt kms.KeyType) (string, []byte, error) {
+	resp, err := r.transport.Send(&Request{Op: CreateAndExportKeyOp, KeyType: kt})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create and export public key bytes: %w", err)
+	}
+
+	return resp.KeyID, resp.PublicKey, nil
+}
+
+// ImportPrivateKey sends privKey to the key server to be stored as a key of type kt, and returns its keyID and key
+// handle. privKey possible types are the ones of localkms: *ecdsa.PrivateKey, *btcec.PrivateKey, ed25519.PrivateKey
+// and []byte for the raw 32 bytes of an X25519 private key. The keyID can be set with the kms.WithKeyID() option.
+func (r *RemoteKMS) ImportPrivateKey(privKey interface{}, kt kms.KeyType,
+	opts ...kms.PrivateKeyOpts) (string, interface{}, error) {
+	privKeyBytes, err := marshalPrivateKey(privKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	pOpts := &kms.PrivateKeyOptions{}
+
+	for _, opt := range opts {
+		opt(pOpts)
+	}
+
+	resp, err := r.transport.Send(&Request{
+		Op:         ImportKeyOp,
+		KeyID:      pOpts.KeyID,
+		KeyType:    kt,
+		PrivateKey: privKeyBytes,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to import private key: %w", err)
+	}
+
+	return resp.KeyID, &KeyHandle{KeyID: resp.KeyID}, nil
+}
+
+// marshalPrivateKey serializes privKey as its raw bytes, the big endian scalar of ECDSA keys is padded to the size
+// of the curve.
+func marshalPrivateKey(privKey interface{}) ([]byte, error) {
+	switch pk := privKey.(type) {
+	case *ecdsa.PrivateKey:
+		return ecdsaPrivateKeyBytes(pk), nil
+	case *btcec.PrivateKey:
+		return ecdsaPrivateKeyBytes(pk.ToECDSA()), nil
+	case ed25519.PrivateKey:
+		return pk, nil
+	case []byte:
+		return pk, nil
+	default:
+		return nil, fmt.Errorf("import private key does not support this key type or key is public")
+	}
+}
+
+func ecdsaPrivateKeyBytes(privKey *ecdsa.PrivateKey) []byte {
+	d := privKey.D.Bytes()
+	size := (privKey.Curve.Params().BitSize + 7) / 8 //nolint:gomnd
+
+	if len(d) >= size {
+		return d
+	}
+
+	return append(make([]byte, size-len(d)), d...)
+}
@@ -0,0 +1,359 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package remotekms_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/remotekms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/remotekms/keyserver"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestRemoteKMS(t *testing.T) {
+	srv := httptest.NewServer(newKeyServer(t))
+	defer srv.Close()
+
+	transport, err := remotekms.NewHTTPTransport(srv.URL)
+	require.NoError(t, err)
+
+	km := remotekms.New(transport)
+
+	t.Run("create and get key", func(t *testing.T) {
+		keyID, kh, err := km.Create(kms.ED25519Type)
+		require.NoError(t, err)
+		require.Equal(t, &remotekms.KeyHandle{KeyID: keyID}, kh)
+
+		kh, err = km.Get(keyID)
+		require.NoError(t, err)
+		require.Equal(t, &remotekms.KeyHandle{KeyID: keyID}, kh)
+
+		_, _, err = km.Create("")
+		require.EqualError(t, err, "failed to create new key, missing key type")
+
+		_, _, err = km.Create("unknown")
+		require.EqualError(t, err, "failed to create new key: key server: key type unrecognized")
+	})
+
+	t.Run("create and export public key", func(t *testing.T) {
+		keyID, pubKey, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+		require.Len(t, pubKey, ed25519.PublicKeySize)
+		require.Equal(t, kms.PublicKeyID(pubKey), keyID)
+
+		exported, err := km.ExportPubKeyBytes(keyID)
+		require.NoError(t, err)
+		require.Equal(t, pubKey, exported)
+	})
+
+	t.Run("rotate key", func(t *testing.T) {
+		keyID, _, err := km.Create(kms.AES256GCMType)
+		require.NoError(t, err)
+
+		newKeyID, kh, err := km.Rotate(kms.AES256GCMType, keyID)
+		require.NoError(t, err)
+		require.NotEqual(t, keyID, newKeyID)
+		require.Equal(t, &remotekms.KeyHandle{KeyID: newKeyID}, kh)
+
+		md, err := km.GetMetadata(newKeyID)
+		require.NoError(t, err)
+		require.Equal(t, []string{keyID}, md.PreviousKeyIDs)
+
+		_, _, err = km.Rotate(kms.AES256GCMType, "unknown")
+		require.Error(t, err)
+	})
+
+	t.Run("list keys and update metadata", func(t *testing.T) {
+		keyID, _, err := km.Create(kms.ECDSAP256TypeIEEEP1363)
+		require.NoError(t, err)
+
+		require.NoError(t, km.UpdateMetadata(keyID, kms.WithPurpose("authentication")))
+		require.NoError(t, km.UpdateMetadata(keyID, kms.WithDID("did:example:123", "did:example:123#key-1")))
+
+		md, err := km.GetMetadata(keyID)
+		require.NoError(t, err)
+		require.Equal(t, kms.ECDSAP256TypeIEEEP1363, md.Type)
+		require.Equal(t, "authentication", md.Purpose)
+		require.Equal(t, "did:example:123", md.DID)
+		require.Equal(t, "did:example:123#key-1", md.VerificationMethod)
+
+		keys, err := km.List()
+		require.NoError(t, err)
+		require.Contains(t, keys, md)
+
+		err = km.UpdateMetadata("unknown", kms.WithPurpose("authentication"))
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+	})
+
+	t.Run("delete key", func(t *testing.T) {
+		keyID, _, err := km.Create(kms.HMACSHA256Tag256Type)
+		require.NoError(t, err)
+
+		require.NoError(t, km.Delete(keyID))
+
+		_, err = km.Get(keyID)
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+
+		err = km.Delete(keyID)
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+	})
+
+	t.Run("import private keys", func(t *testing.T) {
+		_, edPrivKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		ecPrivKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		secp256k1PrivKey, err := btcec.NewPrivateKey(btcec.S256())
+		require.NoError(t, err)
+
+		tests := []struct {
+			name     string
+			privKey  interface{}
+			keyType  kms.KeyType
+			expected []byte
+		}{
+			{"ed25519", edPrivKey, kms.ED25519Type, edPrivKey.Public().(ed25519.PublicKey)},
+			{"ecdsa", ecPrivKey, kms.ECDSAP384TypeIEEEP1363, nil},
+			{"secp256k1", secp256k1PrivKey, kms.ECDSASecp256k1TypeIEEEP1363, secp256k1PrivKey.PubKey().SerializeUncompressed()},
+			{"x25519", randomBytes(t, 32), kms.X25519Type, nil},
+		}
+
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				keyID, kh, err := km.ImportPrivateKey(tc.privKey, tc.keyType)
+				require.NoError(t, err)
+				require.Equal(t, &remotekms.KeyHandle{KeyID: keyID}, kh)
+
+				pubKey, err := km.ExportPubKeyBytes(keyID)
+				require.NoError(t, err)
+				require.Equal(t, kms.PublicKeyID(pubKey), keyID)
+
+				if tc.expected != nil {
+					require.Equal(t, tc.expected, pubKey)
+				}
+			})
+		}
+
+		keyID, _, err := km.ImportPrivateKey(randomBytes(t, 32), kms.X25519Type, kms.WithKeyID("imported"))
+		require.NoError(t, err)
+		require.Equal(t, "imported", keyID)
+
+		_, _, err = km.ImportPrivateKey(&ecPrivKey.PublicKey, kms.ECDSAP384TypeIEEEP1363)
+		require.EqualError(t, err, "import private key does not support this key type or key is public")
+
+		_, _, err = km.ImportPrivateKey(edPrivKey, kms.AES256GCMType)
+		require.EqualError(t, err, "failed to import private key: key server: "+
+			"import private key does not support key type AES256GCM")
+	})
+}
+
+func TestRemoteKMS_Creator(t *testing.T) {
+	km, err := remotekms.Creator(newKeyServer(t))(nil)
+	require.NoError(t, err)
+
+	keyID, _, err := km.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	_, err = km.Get(keyID)
+	require.NoError(t, err)
+}
+
+func TestRemoteKMS_TransportError(t *testing.T) {
+	errTransport := errors.New("transport error")
+
+	km := remotekms.New(transportFunc(func(*remotekms.Request) (*remotekms.Response, error) {
+		return nil, errTransport
+	}))
+
+	_, _, err := km.Create(kms.ED25519Type)
+	require.True(t, errors.Is(err, errTransport))
+
+	_, err = km.Get("keyID")
+	require.True(t, errors.Is(err, errTransport))
+
+	_, _, err = km.Rotate(kms.ED25519Type, "keyID")
+	require.True(t, errors.Is(err, errTransport))
+
+	_, err = km.List()
+	require.True(t, errors.Is(err, errTransport))
+
+	err = km.UpdateMetadata("keyID")
+	require.True(t, errors.Is(err, errTransport))
+
+	err = km.Delete("keyID")
+	require.True(t, errors.Is(err, errTransport))
+
+	_, err = km.ExportPubKeyBytes("keyID")
+	require.True(t, errors.Is(err, errTransport))
+
+	_, _, err = km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	require.True(t, errors.Is(err, errTransport))
+
+	_, _, err = km.ImportPrivateKey(randomBytes(t, 32), kms.X25519Type)
+	require.True(t, errors.Is(err, errTransport))
+
+	t.Run("update metadata error", func(t *testing.T) {
+		km := remotekms.New(transportFunc(func(req *remotekms.Request) (*remotekms.Response, error) {
+			if req.Op == remotekms.GetMetadataOp {
+				return &remotekms.Response{Metadata: &kms.KeyMetadata{ID: req.KeyID}}, nil
+			}
+
+			return nil, errTransport
+		}))
+
+		err := km.UpdateMetadata("keyID", kms.WithPurpose("authentication"))
+		require.True(t, errors.Is(err, errTransport))
+	})
+
+	t.Run("missing metadata", func(t *testing.T) {
+		km := remotekms.New(transportFunc(func(*remotekms.Request) (*remotekms.Response, error) {
+			return &remotekms.Response{}, nil
+		}))
+
+		_, err := km.GetMetadata("keyID")
+		require.EqualError(t, err, "failed to get key metadata: missing metadata in key server response")
+	})
+}
+
+func TestHTTPTransport(t *testing.T) {
+	t.Run("invalid key server URL", func(t *testing.T) {
+		_, err := remotekms.NewHTTPTransport("invalid")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid key server URL")
+	})
+
+	t.Run("send request with options", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+			_, err := rw.Write([]byte(`{"keyID":"123"}`))
+			require.NoError(t, err)
+		}))
+		defer srv.Close()
+
+		transport, err := remotekms.NewHTTPTransport(srv.URL,
+			remotekms.WithHTTPClient(srv.Client()), remotekms.WithHeader("Authorization", "Bearer token"))
+		require.NoError(t, err)
+
+		resp, err := transport.Send(&remotekms.Request{Op: remotekms.CreateKeyOp})
+		require.NoError(t, err)
+		require.Equal(t, "123", resp.KeyID)
+	})
+
+	t.Run("unexpected responses", func(t *testing.T) {
+		var (
+			status int
+			body   string
+		)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(status)
+
+			_, err := rw.Write([]byte(body))
+			require.NoError(t, err)
+		}))
+		defer srv.Close()
+
+		transport, err := remotekms.NewHTTPTransport(srv.URL)
+		require.NoError(t, err)
+
+		status, body = http.StatusOK, "not json"
+		_, err = transport.Send(&remotekms.Request{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal key server response")
+
+		status, body = http.StatusBadGateway, "bad gateway"
+		_, err = transport.Send(&remotekms.Request{})
+		require.EqualError(t, err, "key server: unexpected response status '502' body bad gateway")
+
+		status, body = http.StatusNotFound, `{"error":"key 123 not found"}`
+		_, err = transport.Send(&remotekms.Request{})
+		require.EqualError(t, err, "key server: key 123 not found: key not found")
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+
+		status, body = http.StatusForbidden, `{"error":"sign of key 123 denied"}`
+		_, err = transport.Send(&remotekms.Request{})
+		require.True(t, errors.Is(err, kms.ErrKeyPolicyViolation))
+	})
+
+	t.Run("key server unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+
+		transport, err := remotekms.NewHTTPTransport(srv.URL)
+		require.NoError(t, err)
+
+		_, err = transport.Send(&remotekms.Request{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to send key server request")
+	})
+}
+
+type transportFunc func(req *remotekms.Request) (*remotekms.Response, error)
+
+func (f transportFunc) Send(req *remotekms.Request) (*remotekms.Response, error) {
+	return f(req)
+}
+
+func newKeyServer(t *testing.T) *keyserver.Server {
+	t.Helper()
+
+	km, err := localkms.New("local-lock://test/key/uri", &kmsProvider{
+		storage:    mem.NewProvider(),
+		secretLock: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	return keyserver.New(km, c)
+}
+
+func randomBytes(t *testing.T, size int) []byte {
+	t.Helper()
+
+	b := make([]byte, size)
+
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+
+	return b
+}
+
+type kmsProvider struct {
+	storage    storage.Provider
+	secretLock secretlock.Service
+}
+
+func (p *kmsProvider) StorageProvider() storage.Provider {
+	return p.storage
+}
+
+func (p *kmsProvider) SecretLock() secretlock.Service {
+	return p.secretLock
+}
@@ -0,0 +1,370 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package rotation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+)
+
+const (
+	peerDIDPrefix = "did:peer:"
+
+	defaultGracePeriod = 24 * time.Hour
+	defaultInterval    = time.Hour
+)
+
+var logger = log.New("aries-framework/kms/rotation")
+
+// KeyManager is the key manager of the keys rotated by the Scheduler, eg localkms.LocalKMS.
+type KeyManager interface {
+	kms.KeyManager
+	// RemovePreviousVersions removes the previous versions kept by the rotated key referenced by keyID.
+	RemovePreviousVersions(keyID string) error
+}
+
+// DIDStore stores the peer DID documents updated with the rotated keys, eg the peer VDRI.
+type DIDStore interface {
+	Get(id string) (*did.Doc, error)
+	Store(doc *did.Doc, by *[]vdriapi.ModifiedBy) error
+}
+
+// Notifier sends the updated peer DID documents to the connections of their DIDs, eg didupdate.Service. The
+// updates are sent with the base58 public key prevVerKey of the previous version of the rotated key, the key known by
+// the connections before the update.
+type Notifier func(doc *did.Doc, prevVerKey string) error
+
+// Scheduler rotates the keys older than a maximum age. The rotated keys keep their previous versions to decrypt and
+// verify the data of their previous versions for a grace period, and the peer DID documents referencing the rotated
+// keys in their metadata are updated with the new keys.
+type Scheduler struct {
+	km          KeyManager
+	maxAge      time.Duration
+	gracePeriod time.Duration
+	interval    time.Duration
+	didStore    DIDStore
+	notify      Notifier
+	filter      func(md *kms.KeyMetadata) bool
+	now         func() time.Time
+	stop        chan struct{}
+	lock        sync.Mutex
+}
+
+// Option configures the Scheduler.
+type Option func(s *Scheduler)
+
+// WithGracePeriod sets how long the rotated keys keep their previous versions, one day by default.
+func WithGracePeriod(gracePeriod time.Duration) Option {
+	return func(s *Scheduler) {
+		s.gracePeriod = gracePeriod
+	}
+}
+
+// WithInterval sets how often the started Scheduler checks the keys, every hour by default.
+func WithInterval(interval time.Duration) Option {
+	return func(s *Scheduler) {
+		s.interval = interval
+	}
+}
+
+// WithDIDStore sets the store of the peer DID documents updated with the rotated keys.
+func WithDIDStore(didStore DIDStore) Option {
+	return func(s *Scheduler) {
+		s.didStore = didStore
+	}
+}
+
+// WithNotifier sets the Notifier sending the updated peer DID documents to their connections, the documents are only
+// updated in the DID store by default.
+func WithNotifier(notify Notifier) Option {
+	return func(s *Scheduler) {
+		s.notify = notify
+	}
+}
+
+// WithKeyFilter restricts the rotation to the keys whose metadata is accepted by filter, all keys are rotated by
+// default.
+func WithKeyFilter(filter func(md *kms.KeyMetadata) bool) Option {
+	return func(s *Scheduler) {
+		s.filter = filter
+	}
+}
+
+// New returns a Scheduler rotating the keys of km older than maxAge.
+func New(km KeyManager, maxAge time.Duration, opts ...Option) (*Scheduler, error) {
+	if maxAge <= 0 {
+		return nil, errors.New("key rotation max age must be positive")
+	}
+
+	s := &Scheduler{
+		km:          km,
+		maxAge:      maxAge,
+		gracePeriod: defaultGracePeriod,
+		interval:    defaultInterval,
+		filter:      func(*kms.KeyMetadata) bool { return true },
+		now:         time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.interval <= 0 {
+		return nil, errors.New("key rotation interval must be positive")
+	}
+
+	return s, nil
+}
+
+// Start runs the Scheduler every interval until it is stopped.
+func (s *Scheduler) Start() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+
+	s.stop = make(chan struct{})
+
+	go s.loop(s.stop)
+}
+
+// Stop stops the started Scheduler.
+func (s *Scheduler) Stop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+func (s *Scheduler) loop(stop chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Run(); err != nil {
+				logger.Errorf("%s", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Run rotates the keys older than the max age and removes the previous versions of the keys rotated before the
+// grace period. The DID documents of the keys whose rotation couldn't update them are updated first, the previous
+// versions of these keys are kept until then. The keys failing to rotate don't stop the rotation of the other keys,
+// the first failure is returned.
+func (s *Scheduler) Run() error {
+	keys, err := s.km.List()
+	if err != nil {
+		return fmt.Errorf("key rotation: %w", err)
+	}
+
+	now := s.now()
+
+	var firstErr error
+
+	for _, md := range keys {
+		switch {
+		case md.PendingDIDUpdate != "":
+			err = s.completeDIDUpdate(md)
+		case md.Type != "" && now.Sub(md.Created) >= s.maxAge && s.filter(md):
+			err = s.rotate(md)
+		case md.PreviousVersions > 0 && now.Sub(md.Created) >= s.gracePeriod:
+			err = s.km.RemovePreviousVersions(md.ID)
+		default:
+			continue
+		}
+
+		if err != nil {
+			logger.Warnf("failed to rotate key %s: %s", md.ID, err)
+
+			if firstErr == nil {
+				firstErr = fmt.Errorf("key rotation of key %s: %w", md.ID, err)
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// rotate rotates the key md. The previous public key of a key referenced by a peer DID document is recorded in the
+// metadata of the key before the rotation, so that the document update is retried by the next runs until it is done.
+func (s *Scheduler) rotate(md *kms.KeyMetadata) error {
+	if !s.updatesDIDDoc(md) {
+		newKeyID, _, err := s.km.Rotate(md.Type, md.ID)
+		if err != nil {
+			return err
+		}
+
+		logger.Infof("rotated key %s, its new ID is %s", md.ID, newKeyID)
+
+		return nil
+	}
+
+	pubKey, err := s.km.ExportPubKeyBytes(md.ID)
+	if err != nil {
+		return fmt.Errorf("failed to export public key: %w", err)
+	}
+
+	err = s.km.UpdateMetadata(md.ID, kms.WithPendingDIDUpdate(base58.Encode(pubKey)))
+	if err != nil {
+		return fmt.Errorf("failed to record pending DID document update: %w", err)
+	}
+
+	newKeyID, _, err := s.km.Rotate(md.Type, md.ID)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("rotated key %s, its new ID is %s", md.ID, newKeyID)
+
+	newMD, err := s.km.GetMetadata(newKeyID)
+	if err != nil {
+		return err
+	}
+
+	return s.completeDIDUpdate(newMD)
+}
+
+// completeDIDUpdate updates the DID document of the rotated key md with its new public key, then records that the
+// update is done. Nothing is updated if the key wasn't rotated.
+func (s *Scheduler) completeDIDUpdate(md *kms.KeyMetadata) error {
+	if s.updatesDIDDoc(md) {
+		pubKey, err := s.km.ExportPubKeyBytes(md.ID)
+		if err != nil {
+			return fmt.Errorf("failed to export rotated public key: %w", err)
+		}
+
+		if base58.Encode(pubKey) != md.PendingDIDUpdate {
+			if err = s.updateDIDDoc(md, base58.Decode(md.PendingDIDUpdate), pubKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	err := s.km.UpdateMetadata(md.ID, kms.WithPendingDIDUpdate(""))
+	if err != nil {
+		return fmt.Errorf("failed to record DID document update: %w", err)
+	}
+
+	return nil
+}
+
+// updatesDIDDoc tells whether the rotation of the key md updates a peer DID document.
+func (s *Scheduler) updatesDIDDoc(md *kms.KeyMetadata) bool {
+	return s.didStore != nil && strings.HasPrefix(md.DID, peerDIDPrefix) && md.VerificationMethod != ""
+}
+
+// updateDIDDoc replaces the public key of the verification method of the rotated key in its peer DID document and
+// in the recipient keys of its services, stores the updated document as a new delta and sends it to the connections
+// of the DID. The document isn't stored again when it already has the new public key.
+func (s *Scheduler) updateDIDDoc(md *kms.KeyMetadata, oldPubKey, newPubKey []byte) error {
+	doc, err := s.didStore.Get(md.DID)
+	if err != nil {
+		return fmt.Errorf("failed to get DID document: %w", err)
+	}
+
+	before, err := doc.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("failed to marshal DID document: %w", err)
+	}
+
+	found := false
+
+	for i := range doc.PublicKey {
+		if updatePublicKey(doc, &doc.PublicKey[i], md.VerificationMethod, newPubKey) {
+			found = true
+		}
+	}
+
+	for _, methods := range [][]did.VerificationMethod{doc.Authentication, doc.AssertionMethod,
+		doc.CapabilityDelegation, doc.CapabilityInvocation, doc.KeyAgreement} {
+		for i := range methods {
+			if updatePublicKey(doc, &methods[i].PublicKey, md.VerificationMethod, newPubKey) {
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("verification method %s not found in DID document %s", md.VerificationMethod, md.DID)
+	}
+
+	oldRecKey, newRecKey := base58.Encode(oldPubKey), base58.Encode(newPubKey)
+
+	for i := range doc.Service {
+		for j, recKey := range doc.Service[i].RecipientKeys {
+			if recKey == oldRecKey {
+				doc.Service[i].RecipientKeys[j] = newRecKey
+			}
+		}
+	}
+
+	after, err := doc.JSONBytes()
+	if err != nil {
+		return fmt.Errorf("failed to marshal DID document: %w", err)
+	}
+
+	if !bytes.Equal(before, after) {
+		updated := s.now()
+		doc.Updated = &updated
+
+		if err = s.didStore.Store(doc, nil); err != nil {
+			return fmt.Errorf("failed to store DID document: %w", err)
+		}
+	}
+
+	if s.notify != nil {
+		if err = s.notify(doc, oldRecKey); err != nil {
+			return fmt.Errorf("failed to send DID document update: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// updatePublicKey sets the value of pubKey to value if it is the verification method vm of doc.
+func updatePublicKey(doc *did.Doc, pubKey *did.PublicKey, vm string, value []byte) bool {
+	if absoluteID(doc, pubKey.ID) != absoluteID(doc, vm) {
+		return false
+	}
+
+	*pubKey = *did.NewPublicKeyFromBytes(pubKey.ID, pubKey.Type, pubKey.Controller, value)
+
+	return true
+}
+
+// absoluteID returns the absolute ID of the relative verification method IDs of doc, eg "#key-1" or "key-1".
+func absoluteID(doc *did.Doc, id string) string {
+	switch {
+	case strings.HasPrefix(id, "#"):
+		return doc.ID + id
+	case !strings.HasPrefix(id, "did:"):
+		return doc.ID + "#" + id
+	default:
+		return id
+	}
+}
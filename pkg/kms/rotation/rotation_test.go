@@ -0,0 +1,373 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package rotation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/google/tink/go/keyset"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/vdri/peer"
+)
+
+func TestNew(t *testing.T) {
+	km := newKMS(t)
+
+	s, err := New(km, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, defaultGracePeriod, s.gracePeriod)
+	require.Equal(t, defaultInterval, s.interval)
+
+	_, err = New(km, 0)
+	require.EqualError(t, err, "key rotation max age must be positive")
+
+	_, err = New(km, time.Hour, WithInterval(0))
+	require.EqualError(t, err, "key rotation interval must be positive")
+}
+
+func TestScheduler_Run(t *testing.T) {
+	km := newKMS(t)
+
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	v, err := peer.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	keyID, pubKey, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	require.NoError(t, err)
+
+	doc, err := v.Build(&vdriapi.PubKey{Value: base58.Encode(pubKey), Type: "Ed25519VerificationKey2018"},
+		vdriapi.WithServiceType(vdriapi.DIDCommServiceType), vdriapi.WithServiceEndpoint("http://example.com"))
+	require.NoError(t, err)
+	require.NoError(t, v.Store(doc, nil))
+
+	require.NoError(t, km.UpdateMetadata(keyID, kms.WithDID(doc.ID, doc.PublicKey[0].ID)))
+
+	// a symmetric key which isn't rotated
+	symKeyID, _, err := km.Create(kms.AES256GCMType)
+	require.NoError(t, err)
+
+	kh, err := km.Get(keyID)
+	require.NoError(t, err)
+
+	msg := []byte("test message")
+
+	sig, err := c.Sign(msg, kh)
+	require.NoError(t, err)
+
+	s, err := New(km, time.Hour, WithDIDStore(v), WithKeyFilter(func(md *kms.KeyMetadata) bool {
+		return md.Type == kms.ED25519Type
+	}))
+	require.NoError(t, err)
+
+	t.Run("keys younger than the max age aren't rotated", func(t *testing.T) {
+		require.NoError(t, s.Run())
+
+		_, err = km.GetMetadata(keyID)
+		require.NoError(t, err)
+	})
+
+	var newKeyID string
+
+	t.Run("rotate keys older than the max age", func(t *testing.T) {
+		s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+		require.NoError(t, s.Run())
+
+		keys, err := km.List()
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+
+		md := keys[1]
+		require.Equal(t, []string{keyID}, md.PreviousKeyIDs)
+		require.Equal(t, 1, md.PreviousVersions)
+		require.Equal(t, doc.ID, md.DID)
+		require.Equal(t, symKeyID, keys[0].ID)
+
+		newKeyID = md.ID
+
+		newPubKey, err := km.ExportPubKeyBytes(newKeyID)
+		require.NoError(t, err)
+		require.Equal(t, kms.PublicKeyID(newPubKey), newKeyID)
+
+		// the updated DID document is stored as a delta
+		updatedDoc, err := v.Get(doc.ID)
+		require.NoError(t, err)
+		require.Equal(t, newPubKey, updatedDoc.PublicKey[0].Value)
+		require.Equal(t, []string{base58.Encode(newPubKey)}, updatedDoc.Service[0].RecipientKeys)
+		require.True(t, updatedDoc.Updated.After(*doc.Updated))
+
+		// the previous version verifies its signatures during the grace period
+		kh, err := km.Get(keyID)
+		require.NoError(t, err)
+
+		pubKH, err := kh.(*keyset.Handle).Public()
+		require.NoError(t, err)
+		require.NoError(t, c.Verify(sig, msg, pubKH))
+	})
+
+	t.Run("remove the previous versions after the grace period", func(t *testing.T) {
+		s.maxAge = 48 * time.Hour
+		s.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+
+		require.NoError(t, s.Run())
+
+		md, err := km.GetMetadata(newKeyID)
+		require.NoError(t, err)
+		require.Zero(t, md.PreviousVersions)
+
+		_, err = km.Get(keyID)
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+
+		kh, err := km.Get(newKeyID)
+		require.NoError(t, err)
+
+		pubKH, err := kh.(*keyset.Handle).Public()
+		require.NoError(t, err)
+		require.Error(t, c.Verify(sig, msg, pubKH))
+	})
+}
+
+func TestScheduler_RunFailure(t *testing.T) {
+	t.Run("list keys error", func(t *testing.T) {
+		errList := errors.New("list error")
+
+		s, err := New(&failingKMS{KeyManager: newKMS(t), err: errList}, time.Hour)
+		require.NoError(t, err)
+
+		err = s.Run()
+		require.True(t, errors.Is(err, errList))
+	})
+
+	t.Run("verification method not in the DID document", func(t *testing.T) {
+		km := newKMS(t)
+
+		v, err := peer.New(mem.NewProvider())
+		require.NoError(t, err)
+
+		keyID, _, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		otherKeyID, _, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		doc := &did.Doc{Context: []string{did.Context}, ID: "did:peer:123"}
+		require.NoError(t, v.Store(doc, nil))
+
+		require.NoError(t, km.UpdateMetadata(keyID, kms.WithDID(doc.ID, "#key-1")))
+
+		s, err := New(km, time.Hour, WithDIDStore(v))
+		require.NoError(t, err)
+
+		s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+		err = s.Run()
+		require.EqualError(t, err, "key rotation of key "+keyID+
+			": verification method #key-1 not found in DID document did:peer:123")
+
+		// the failure doesn't stop the rotation of the other keys
+		_, err = km.GetMetadata(otherKeyID)
+		require.True(t, errors.Is(err, kms.ErrKeyNotFound))
+	})
+}
+
+func TestScheduler_PendingDIDUpdate(t *testing.T) {
+	km := newKMS(t)
+
+	v, err := peer.New(mem.NewProvider())
+	require.NoError(t, err)
+
+	keyID, pubKey, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+	require.NoError(t, err)
+
+	doc, err := v.Build(&vdriapi.PubKey{Value: base58.Encode(pubKey), Type: "Ed25519VerificationKey2018"},
+		vdriapi.WithServiceType(vdriapi.DIDCommServiceType), vdriapi.WithServiceEndpoint("http://example.com"))
+	require.NoError(t, err)
+	require.NoError(t, v.Store(doc, nil))
+
+	require.NoError(t, km.UpdateMetadata(keyID, kms.WithDID(doc.ID, doc.PublicKey[0].ID)))
+
+	didStore := &failingDIDStore{DIDStore: v, err: errors.New("store error")}
+
+	var notified []*did.Doc
+
+	errNotify := errors.New("notify error")
+
+	s, err := New(km, time.Hour, WithDIDStore(didStore), WithNotifier(func(doc *did.Doc, prevVerKey string) error {
+		require.Equal(t, base58.Encode(pubKey), prevVerKey)
+
+		notified = append(notified, doc)
+
+		return errNotify
+	}))
+	require.NoError(t, err)
+
+	s.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	var newKeyID string
+
+	t.Run("a failed DID document update is recorded", func(t *testing.T) {
+		err = s.Run()
+		require.True(t, errors.Is(err, didStore.err))
+
+		keys, err := km.List()
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.Equal(t, base58.Encode(pubKey), keys[0].PendingDIDUpdate)
+		require.Empty(t, notified)
+
+		newKeyID = keys[0].ID
+	})
+
+	t.Run("the DID document update is retried and the connections are notified", func(t *testing.T) {
+		didStore.err = nil
+		s.maxAge = 48 * time.Hour
+		s.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+
+		err = s.Run()
+		require.True(t, errors.Is(err, errNotify))
+
+		newPubKey, err := km.ExportPubKeyBytes(newKeyID)
+		require.NoError(t, err)
+
+		updatedDoc, err := v.Get(doc.ID)
+		require.NoError(t, err)
+		require.Equal(t, newPubKey, updatedDoc.PublicKey[0].Value)
+
+		require.Len(t, notified, 1)
+		require.Equal(t, doc.ID, notified[0].ID)
+
+		// the previous versions are kept until the update is done
+		md, err := km.GetMetadata(newKeyID)
+		require.NoError(t, err)
+		require.Equal(t, base58.Encode(pubKey), md.PendingDIDUpdate)
+		require.Equal(t, 1, md.PreviousVersions)
+	})
+
+	t.Run("the DID document isn't stored again when the notification is retried", func(t *testing.T) {
+		errNotify = nil
+
+		require.NoError(t, s.Run())
+		require.Len(t, notified, 2)
+		require.Equal(t, 1, didStore.stored)
+
+		md, err := km.GetMetadata(newKeyID)
+		require.NoError(t, err)
+		require.Empty(t, md.PendingDIDUpdate)
+		require.Equal(t, 1, md.PreviousVersions)
+
+		require.NoError(t, s.Run())
+
+		md, err = km.GetMetadata(newKeyID)
+		require.NoError(t, err)
+		require.Zero(t, md.PreviousVersions)
+	})
+
+	t.Run("nothing is updated for a key whose rotation failed", func(t *testing.T) {
+		newPubKey, err := km.ExportPubKeyBytes(newKeyID)
+		require.NoError(t, err)
+
+		// the pending update recorded before a rotation which didn't happen
+		require.NoError(t, km.UpdateMetadata(newKeyID, kms.WithPendingDIDUpdate(base58.Encode(newPubKey))))
+
+		require.NoError(t, s.Run())
+		require.Len(t, notified, 2)
+		require.Equal(t, 1, didStore.stored)
+
+		md, err := km.GetMetadata(newKeyID)
+		require.NoError(t, err)
+		require.Empty(t, md.PendingDIDUpdate)
+	})
+}
+
+func TestScheduler_Start(t *testing.T) {
+	km := newKMS(t)
+
+	keyID, _, err := km.Create(kms.ED25519Type)
+	require.NoError(t, err)
+
+	s, err := New(km, time.Nanosecond, WithInterval(time.Millisecond), WithKeyFilter(func(md *kms.KeyMetadata) bool {
+		return md.ID == keyID
+	}))
+	require.NoError(t, err)
+
+	s.Start()
+	s.Start()
+
+	require.Eventually(t, func() bool {
+		_, err := km.GetMetadata(keyID)
+
+		return errors.Is(err, kms.ErrKeyNotFound)
+	}, time.Second, time.Millisecond)
+
+	s.Stop()
+	s.Stop()
+}
+
+func newKMS(t *testing.T) *localkms.LocalKMS {
+	t.Helper()
+
+	km, err := localkms.New("local-lock://test/key/uri", &kmsProvider{
+		storage:    mem.NewProvider(),
+		secretLock: &noop.NoLock{},
+	})
+	require.NoError(t, err)
+
+	return km
+}
+
+type kmsProvider struct {
+	storage    storage.Provider
+	secretLock secretlock.Service
+}
+
+func (p *kmsProvider) StorageProvider() storage.Provider {
+	return p.storage
+}
+
+func (p *kmsProvider) SecretLock() secretlock.Service {
+	return p.secretLock
+}
+
+type failingKMS struct {
+	KeyManager
+	err error
+}
+
+func (k *failingKMS) List() ([]*kms.KeyMetadata, error) {
+	return nil, k.err
+}
+
+type failingDIDStore struct {
+	DIDStore
+	err    error
+	stored int
+}
+
+func (s *failingDIDStore) Store(doc *did.Doc, by *[]vdriapi.ModifiedBy) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.stored++
+
+	return s.DIDStore.Store(doc, by)
+}
@@ -0,0 +1,42 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto"
+)
+
+// Signer signs messages with the private key of a verification key.
+type Signer interface {
+	// SignMessage signs message using the private key associated with fromVerKey, the base58 encoded
+	// verification (public) key.
+	SignMessage(message []byte, fromVerKey string) ([]byte, error)
+}
+
+// NewSigner returns a Signer signing with the keys of km using c. The private key of a verification key is the
+// key of km with the keyID PublicKeyID gives for the verification key.
+func NewSigner(km KeyManager, c crypto.Crypto) Signer {
+	return &signer{km: km, crypto: c}
+}
+
+type signer struct {
+	km     KeyManager
+	crypto crypto.Crypto
+}
+
+func (s *signer) SignMessage(message []byte, fromVerKey string) ([]byte, error) {
+	kh, err := s.km.Get(PublicKeyID(base58.Decode(fromVerKey)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key: %w", err)
+	}
+
+	return s.crypto.Sign(message, kh)
+}
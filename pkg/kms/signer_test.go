@@ -0,0 +1,50 @@
+/*
+ Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms_test
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/crypto/tinkcrypto"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms/localkms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/noop"
+)
+
+func TestSigner_SignMessage(t *testing.T) {
+	c, err := tinkcrypto.New()
+	require.NoError(t, err)
+
+	t.Run("test sign with the key of a verification key", func(t *testing.T) {
+		km, err := localkms.New("local-lock://test/key/uri",
+			mockkms.NewProvider(mockstorage.NewMockStoreProvider(), &noop.NoLock{}))
+		require.NoError(t, err)
+
+		_, pubKey, err := km.CreateAndExportPubKeyBytes(kms.ED25519Type)
+		require.NoError(t, err)
+
+		msg := []byte("test message")
+
+		sig, err := kms.NewSigner(km, c).SignMessage(msg, base58.Encode(pubKey))
+		require.NoError(t, err)
+		require.True(t, ed25519.Verify(pubKey, msg, sig))
+	})
+
+	t.Run("test sign with an unknown verification key", func(t *testing.T) {
+		km := &mockkms.KeyManager{GetKeyErr: errors.New("get error")}
+
+		_, err := kms.NewSigner(km, c).SignMessage([]byte("test message"), "unknown")
+		require.EqualError(t, err, "failed to get key: get error")
+	})
+}
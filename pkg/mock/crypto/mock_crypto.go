@@ -6,6 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 
 package crypto
 
+import (
+	"io"
+
+	cryptoapi "github.com/hyperledger/aries-framework-go/pkg/crypto"
+)
+
 // Crypto mock
 type Crypto struct {
 	EncryptValue      []byte
@@ -13,12 +19,32 @@ type Crypto struct {
 	EncryptErr        error
 	DecryptValue      []byte
 	DecryptErr        error
+	EncryptStreamErr  error
+	DecryptStreamErr  error
 	SignValue         []byte
 	SignErr           error
 	VerifyErr         error
 	ComputeMACValue   []byte
 	ComputeMACErr     error
 	VerifyMACErr      error
+	WrapValue         *cryptoapi.RecipientWrappedKey
+	WrapError         error
+	UnwrapValue       []byte
+	UnwrapError       error
+	EasyValue         []byte
+	EasyErr           error
+	EasyOpenValue     []byte
+	EasyOpenErr       error
+	SealOpenValue     []byte
+	SealOpenErr       error
+	DeriveKEKValue    []byte
+	DeriveKEKErr      error
+	BBSSignValue      []byte
+	BBSSignErr        error
+	BBSVerifyErr      error
+	DeriveProofValue  []byte
+	DeriveProofError  error
+	VerifyProofErr    error
 }
 
 // Encrypt returns mocked values and a mocked error
@@ -31,6 +57,32 @@ func (c *Crypto) Decrypt(cipher, aad, nonce []byte, kh interface{}) ([]byte, err
 	return c.DecryptValue, c.DecryptErr
 }
 
+// EncryptStream returns a writer writing the data to w as is, or a mocked error
+func (c *Crypto) EncryptStream(w io.Writer, aad []byte, kh interface{}) (io.WriteCloser, error) {
+	if c.EncryptStreamErr != nil {
+		return nil, c.EncryptStreamErr
+	}
+
+	return &nopWriteCloser{Writer: w}, nil
+}
+
+// DecryptStream returns r as is, or a mocked error
+func (c *Crypto) DecryptStream(r io.Reader, aad []byte, kh interface{}) (io.Reader, error) {
+	if c.DecryptStreamErr != nil {
+		return nil, c.DecryptStreamErr
+	}
+
+	return r, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (w *nopWriteCloser) Close() error {
+	return nil
+}
+
 // Sign returns a mocked value and a mocked error
 func (c *Crypto) Sign(msg []byte, kh interface{}) ([]byte, error) {
 	return c.SignValue, c.SignErr
@@ -50,3 +102,56 @@ func (c *Crypto) ComputeMAC(data []byte, kh interface{}) ([]byte, error) {
 func (c *Crypto) VerifyMAC(mac, data []byte, kh interface{}) error {
 	return c.VerifyMACErr
 }
+
+// WrapKey returns a mocked value and a mocked error
+func (c *Crypto) WrapKey(cek, apu, apv []byte, recPubKey *cryptoapi.PublicKey,
+	opts ...cryptoapi.WrapKeyOpts) (*cryptoapi.RecipientWrappedKey, error) {
+	return c.WrapValue, c.WrapError
+}
+
+// UnwrapKey returns a mocked value and a mocked error
+func (c *Crypto) UnwrapKey(recWK *cryptoapi.RecipientWrappedKey, kh interface{},
+	opts ...cryptoapi.WrapKeyOpts) ([]byte, error) {
+	return c.UnwrapValue, c.UnwrapError
+}
+
+// Easy returns a mocked value and a mocked error
+func (c *Crypto) Easy(payload, nonce, theirPub []byte, kh interface{}) ([]byte, error) {
+	return c.EasyValue, c.EasyErr
+}
+
+// EasyOpen returns a mocked value and a mocked error
+func (c *Crypto) EasyOpen(cipherText, nonce, theirPub []byte, kh interface{}) ([]byte, error) {
+	return c.EasyOpenValue, c.EasyOpenErr
+}
+
+// SealOpen returns a mocked value and a mocked error
+func (c *Crypto) SealOpen(cipherText []byte, kh interface{}) ([]byte, error) {
+	return c.SealOpenValue, c.SealOpenErr
+}
+
+// DeriveKEK returns a mocked value and a mocked error
+func (c *Crypto) DeriveKEK(alg, apu, theirPub []byte, kh interface{}) ([]byte, error) {
+	return c.DeriveKEKValue, c.DeriveKEKErr
+}
+
+// SignMulti returns a mocked value and a mocked error
+func (c *Crypto) SignMulti(messages [][]byte, kh interface{}) ([]byte, error) {
+	return c.BBSSignValue, c.BBSSignErr
+}
+
+// VerifyMulti returns a mocked value
+func (c *Crypto) VerifyMulti(messages [][]byte, signature []byte, kh interface{}) error {
+	return c.BBSVerifyErr
+}
+
+// DeriveProof returns a mocked value and a mocked error
+func (c *Crypto) DeriveProof(messages [][]byte, bbsSignature, nonce []byte, revealedIndexes []int,
+	kh interface{}) ([]byte, error) {
+	return c.DeriveProofValue, c.DeriveProofError
+}
+
+// VerifyProof returns a mocked value
+func (c *Crypto) VerifyProof(revealedMessages [][]byte, proof, nonce []byte, kh interface{}) error {
+	return c.VerifyProofErr
+}
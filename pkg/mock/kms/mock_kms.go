@@ -21,14 +21,28 @@ import (
 
 // KeyManager mocks a local Key Management Service
 type KeyManager struct {
-	CreateKeyID    string
-	CreateKeyValue *keyset.Handle
-	CreateKeyErr   error
-	GetKeyValue    *keyset.Handle
-	GetKeyErr      error
-	RotateKeyID    string
-	RotateKeyValue *keyset.Handle
-	RotateKeyErr   error
+	CreateKeyID            string
+	CreateKeyValue         *keyset.Handle
+	CreateKeyErr           error
+	GetKeyValue            *keyset.Handle
+	GetKeyErr              error
+	RotateKeyID            string
+	RotateKeyValue         *keyset.Handle
+	RotateKeyErr           error
+	ListValue              []*kmsservice.KeyMetadata
+	ListErr                error
+	MetadataValue          *kmsservice.KeyMetadata
+	MetadataErr            error
+	UpdateErr              error
+	DeleteErr              error
+	PubKeyBytes            []byte
+	ExportPubKeyErr        error
+	CrAndExportPubKeyValue []byte
+	CrAndExportPubKeyID    string
+	CrAndExportPubKeyErr   error
+	ImportPrivateKeyID     string
+	ImportPrivateKeyValue  *keyset.Handle
+	ImportPrivateKeyErr    error
 }
 
 // Create a new mock ey/keyset/key handle for the type kt
@@ -58,6 +72,62 @@ func (k *KeyManager) Rotate(kt kmsservice.KeyType, keyID string) (string, interf
 	return k.RotateKeyID, k.RotateKeyValue, nil
 }
 
+// List returns the mocked metadata of the keys
+func (k *KeyManager) List() ([]*kmsservice.KeyMetadata, error) {
+	if k.ListErr != nil {
+		return nil, k.ListErr
+	}
+
+	return k.ListValue, nil
+}
+
+// GetMetadata returns the mocked metadata of a key
+func (k *KeyManager) GetMetadata(keyID string) (*kmsservice.KeyMetadata, error) {
+	if k.MetadataErr != nil {
+		return nil, k.MetadataErr
+	}
+
+	return k.MetadataValue, nil
+}
+
+// UpdateMetadata returns the mocked error of a key metadata update
+func (k *KeyManager) UpdateMetadata(keyID string, opts ...kmsservice.MetadataOpts) error {
+	return k.UpdateErr
+}
+
+// Delete returns the mocked error of a key deletion
+func (k *KeyManager) Delete(keyID string) error {
+	return k.DeleteErr
+}
+
+// ExportPubKeyBytes returns the mocked public key bytes
+func (k *KeyManager) ExportPubKeyBytes(keyID string) ([]byte, error) {
+	if k.ExportPubKeyErr != nil {
+		return nil, k.ExportPubKeyErr
+	}
+
+	return k.PubKeyBytes, nil
+}
+
+// CreateAndExportPubKeyBytes returns the mocked ID and public key bytes of a new key
+func (k *KeyManager) CreateAndExportPubKeyBytes(kt kmsservice.KeyType) (string, []byte, error) {
+	if k.CrAndExportPubKeyErr != nil {
+		return "", nil, k.CrAndExportPubKeyErr
+	}
+
+	return k.CrAndExportPubKeyID, k.CrAndExportPubKeyValue, nil
+}
+
+// ImportPrivateKey returns the mocked ID and key handle of an imported private key
+func (k *KeyManager) ImportPrivateKey(privKey interface{}, kt kmsservice.KeyType,
+	opts ...kmsservice.PrivateKeyOpts) (string, interface{}, error) {
+	if k.ImportPrivateKeyErr != nil {
+		return "", nil, k.ImportPrivateKeyErr
+	}
+
+	return k.ImportPrivateKeyID, k.ImportPrivateKeyValue, nil
+}
+
 // CreateMockKeyHandle is a utility function that returns a mock key (for tests only. ie: not registered in Tink)
 func CreateMockKeyHandle() (*keyset.Handle, error) {
 	ks := testutil.NewTestAESGCMKeyset(tinkpb.OutputPrefixType_TINK)
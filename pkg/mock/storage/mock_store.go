@@ -8,8 +8,10 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
@@ -61,15 +63,19 @@ func (s *MockStoreProvider) CloseStore(name string) error {
 // MockStore mock store.
 type MockStore struct {
 	Store     map[string][]byte
+	Tags      map[string][]storage.Tag
+	TTLs      map[string]time.Duration
 	lock      sync.RWMutex
 	ErrPut    error
 	ErrGet    error
 	ErrItr    error
 	ErrDelete error
+	ErrQuery  error
+	ErrBatch  error
 }
 
-// Put stores the key and the record
-func (s *MockStore) Put(k string, v []byte) error {
+// Put stores the key and the record along with optional tags
+func (s *MockStore) Put(k string, v []byte, tags ...storage.Tag) error {
 	if k == "" {
 		return errors.New("key is mandatory")
 	}
@@ -80,6 +86,13 @@ func (s *MockStore) Put(k string, v []byte) error {
 
 	s.lock.Lock()
 	s.Store[k] = v
+
+	if s.Tags == nil {
+		s.Tags = make(map[string][]storage.Tag)
+	}
+
+	s.Tags[k] = tags
+	delete(s.TTLs, k)
 	s.lock.Unlock()
 
 	return s.ErrPut
@@ -102,6 +115,22 @@ func (s *MockStore) Get(k string) ([]byte, error) {
 	return val, s.ErrGet
 }
 
+// GetTags fetches the tags stored along with the record of key k
+func (s *MockStore) GetTags(k string) ([]storage.Tag, error) {
+	if s.ErrGet != nil {
+		return nil, s.ErrGet
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.Store[k]; !ok {
+		return nil, storage.ErrDataNotFound
+	}
+
+	return s.Tags[k], nil
+}
+
 // Iterator returns an iterator for the underlying mockstore
 func (s *MockStore) Iterator(start, limit string) storage.StoreIterator {
 	if s.ErrItr != nil {
@@ -126,11 +155,99 @@ func (s *MockStore) Iterator(start, limit string) storage.StoreIterator {
 func (s *MockStore) Delete(k string) error {
 	s.lock.Lock()
 	delete(s.Store, k)
+	delete(s.Tags, k)
+	delete(s.TTLs, k)
 	s.lock.Unlock()
 
 	return s.ErrDelete
 }
 
+// Batch performs the given operations, failing as a whole with ErrBatch or with
+// ErrPut/ErrDelete when the batch contains a put/delete operation
+func (s *MockStore) Batch(operations []storage.Operation) error {
+	if s.ErrBatch != nil {
+		return s.ErrBatch
+	}
+
+	for _, op := range operations {
+		if op.Key == "" {
+			return errors.New("key is mandatory")
+		}
+
+		if op.Value == nil && s.ErrDelete != nil {
+			return s.ErrDelete
+		}
+
+		if op.Value != nil && s.ErrPut != nil {
+			return s.ErrPut
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.Tags == nil {
+		s.Tags = make(map[string][]storage.Tag)
+	}
+
+	if s.TTLs == nil {
+		s.TTLs = make(map[string]time.Duration)
+	}
+
+	for _, op := range operations {
+		delete(s.TTLs, op.Key)
+
+		if op.Value == nil {
+			delete(s.Store, op.Key)
+			delete(s.Tags, op.Key)
+
+			continue
+		}
+
+		s.Store[op.Key] = op.Value
+		s.Tags[op.Key] = op.Tags
+
+		if op.TTL > 0 {
+			s.TTLs[op.Key] = op.TTL
+		}
+	}
+
+	return nil
+}
+
+// Query returns an iterator over the records whose tags match the given expression
+func (s *MockStore) Query(expression string) (storage.StoreIterator, error) {
+	if s.ErrQuery != nil {
+		return nil, s.ErrQuery
+	}
+
+	terms, err := storage.ParseQuery(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var keys []string
+
+	for k, tags := range s.Tags {
+		if storage.MatchTags(terms, tags) {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	var batch [][]string
+
+	for _, k := range keys {
+		batch = append(batch, []string{k, string(s.Store[k])})
+	}
+
+	return NewMockIterator(batch), nil
+}
+
 // NewMockIterator returns new mock iterator for given batch
 func NewMockIterator(batch [][]string) *MockIterator {
 	if len(batch) == 0 {
@@ -0,0 +1,158 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// MaxShares is the maximum number of shares of a secret, the share indexes are the non zero elements of GF(2^8).
+const MaxShares = 255
+
+// Split splits secret into n shares, any threshold of them reconstruct secret with Combine. Each share holds the
+// evaluations of a random polynomial of degree threshold-1 per secret byte followed by its (non zero) index.
+func Split(secret []byte, n, threshold int) ([][]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret is empty")
+	}
+
+	if threshold < 2 || threshold > n || n > MaxShares {
+		return nil, fmt.Errorf("invalid threshold %d of %d shares", threshold, n)
+	}
+
+	shares := make([][]byte, n)
+
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = byte(i + 1)
+	}
+
+	coefficients := make([]byte, threshold)
+
+	for j, s := range secret {
+		// random coefficients of the polynomial, its constant term is the secret byte
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, err
+		}
+
+		coefficients[0] = s
+
+		for _, share := range shares {
+			share[j] = evaluate(coefficients, share[len(secret)])
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine reconstructs the secret from shares created by Split, their number must reach the threshold of Split.
+// Combining fewer shares returns a wrong secret.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("at least two shares are required")
+	}
+
+	size := len(shares[0])
+	if size < 2 {
+		return nil, errors.New("invalid share")
+	}
+
+	xs := make([]byte, len(shares))
+
+	for i, share := range shares {
+		if len(share) != size {
+			return nil, errors.New("shares have different sizes")
+		}
+
+		xs[i] = share[size-1]
+
+		if xs[i] == 0 {
+			return nil, errors.New("invalid share index")
+		}
+
+		for _, x := range xs[:i] {
+			if x == xs[i] {
+				return nil, errors.New("duplicate share")
+			}
+		}
+	}
+
+	secret := make([]byte, size-1)
+	ys := make([]byte, len(shares))
+
+	for j := range secret {
+		for i, share := range shares {
+			ys[i] = share[j]
+		}
+
+		secret[j] = interpolateAtZero(xs, ys)
+	}
+
+	return secret, nil
+}
+
+// evaluate evaluates the polynomial of coefficients at x with Horner's method.
+func evaluate(coefficients []byte, x byte) byte {
+	var y byte
+
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coefficients[i]
+	}
+
+	return y
+}
+
+// interpolateAtZero returns the value at 0 of the polynomial through the points (xs, ys) with Lagrange interpolation.
+func interpolateAtZero(xs, ys []byte) byte {
+	var y byte
+
+	for i := range xs {
+		basis := byte(1)
+
+		for j := range xs {
+			if i != j {
+				// in GF(2^8) subtraction is addition: (0 - xj) / (xi - xj) = xj / (xi ^ xj)
+				basis = mul(basis, div(xs[j], xs[i]^xs[j]))
+			}
+		}
+
+		y ^= mul(ys[i], basis)
+	}
+
+	return y
+}
+
+// mul multiplies a and b in GF(2^8) with the AES reduction polynomial x^8 + x^4 + x^3 + x + 1, without branching on
+// their values.
+func mul(a, b byte) byte {
+	var p byte
+
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		b >>= 1
+
+		carry := -(a >> 7)
+		a = (a << 1) ^ (0x1b & carry)
+	}
+
+	return p
+}
+
+// div divides a by b (non zero) in GF(2^8), multiplying a by the inverse b^254 of b.
+func div(a, b byte) byte {
+	// b^254 = b^(2+4+8+16+32+64+128)
+	b2 := mul(b, b)
+	inv := b2
+
+	for i := 0; i < 6; i++ {
+		b2 = mul(b2, b2)
+		inv = mul(inv, b2)
+	}
+
+	return mul(a, inv)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shamir
+
+import (
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := random.GetRandomBytes(32)
+
+	shares, err := Split(secret, 5, 3)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	t.Run("any threshold of shares combine the secret", func(t *testing.T) {
+		for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+			var parts [][]byte
+
+			for _, i := range subset {
+				parts = append(parts, shares[i])
+			}
+
+			combined, err := Combine(parts)
+			require.NoError(t, err)
+			require.Equal(t, secret, combined)
+		}
+	})
+
+	t.Run("fewer shares than the threshold don't combine the secret", func(t *testing.T) {
+		combined, err := Combine(shares[:2])
+		require.NoError(t, err)
+		require.NotEqual(t, secret, combined)
+	})
+
+	t.Run("invalid shares", func(t *testing.T) {
+		_, err := Combine(shares[:1])
+		require.EqualError(t, err, "at least two shares are required")
+
+		_, err = Combine([][]byte{shares[0], shares[0]})
+		require.EqualError(t, err, "duplicate share")
+
+		_, err = Combine([][]byte{shares[0], shares[1][1:]})
+		require.EqualError(t, err, "shares have different sizes")
+
+		_, err = Combine([][]byte{{1}, {2}})
+		require.EqualError(t, err, "invalid share")
+
+		_, err = Combine([][]byte{{1, 0}, {2, 1}})
+		require.EqualError(t, err, "invalid share index")
+	})
+
+	t.Run("invalid split", func(t *testing.T) {
+		_, err := Split(nil, 5, 3)
+		require.EqualError(t, err, "secret is empty")
+
+		_, err = Split(secret, 5, 1)
+		require.EqualError(t, err, "invalid threshold 1 of 5 shares")
+
+		_, err = Split(secret, 2, 3)
+		require.EqualError(t, err, "invalid threshold 3 of 2 shares")
+
+		_, err = Split(secret, MaxShares+1, 3)
+		require.EqualError(t, err, "invalid threshold 3 of 256 shares")
+	})
+}
+
+func TestField(t *testing.T) {
+	for a := 0; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			require.Equal(t, byte(a), mul(div(byte(a), byte(b)), byte(b)))
+		}
+	}
+
+	// the AES field multiplication example of FIPS 197
+	require.Equal(t, byte(0xc1), mul(0x57, 0x83))
+}
@@ -22,7 +22,9 @@ import (
 // in a local file or an environment variable prior to using this service.
 //
 // The user has the option to encrypt the master key using hkdf.NewMasterLock(passphrase, hash func(), salt)
-// found in the sub package masterlock/hkdf.
+// found in the sub package masterlock/hkdf, or preferably using the memory-hard argon2.NewMasterLock(passphrase) or
+// scrypt.NewMasterLock(passphrase) found in the sub packages masterlock/argon2 and masterlock/scrypt. A master key
+// encrypted with hkdf can be re-encrypted with them using masterlock.Rewrap().
 //
 // The master key must be stored (encrypted with a MasterLock or not encrypted) either in a file or in
 // an environment variable.
@@ -43,6 +45,14 @@ import (
 // and secLock which is the masterKey lock used to encrypt/decrypt the master key. If secLock is nil
 // then the masterKey content in reader will be used as-is without being decrypted. The keys however are always
 // encrypted using the read masterKey.
+//
+// To recover the master key if it is lost, split it beforehand with:
+//		SplitMasterKey(reader, secLock, n, threshold)
+// into n printable shares to be kept apart, eg by different custodians. Any threshold of the shares recover the master
+// key with MasterKeyFromShares(shares), the returned reader creates the lock service with NewService(reader, nil).
+//
+// To rotate the master key, create the lock service of the new master key and call RotateMasterKey() of the KMS, eg
+// localkms.LocalKMS.RotateMasterKey(), to re-encrypt the keys with the new master key.
 
 var logger = log.New("aries-framework/lock")
 
@@ -57,6 +67,22 @@ type Lock struct {
 // If the masterKey is not protected (secLock=nil) this function will attempt to base64 URL Decode the
 // content of masterKeyReader and if it fails, then will attempt to create a secret lock cipher with the raw key as is.
 func NewService(masterKeyReader io.Reader, secLock secretlock.Service) (secretlock.Service, error) {
+	masterKey, err := readMasterKey(masterKeyReader, secLock)
+	if err != nil {
+		return nil, err
+	}
+
+	// finally create the cipher to be used by the lock service
+	aead, err := cipherutil.CreateAESCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Lock{aead: aead}, nil
+}
+
+// readMasterKey reads the master key in masterKeyReader, decrypted with secLock if it is not nil.
+func readMasterKey(masterKeyReader io.Reader, secLock secretlock.Service) ([]byte, error) {
 	masterKeyData := make([]byte, masterKeyLen)
 
 	if masterKeyReader == nil {
@@ -74,8 +100,6 @@ func NewService(masterKeyReader io.Reader, secLock secretlock.Service) (secretlo
 		return nil, fmt.Errorf("masterKeyReader is empty")
 	}
 
-	var masterKey []byte
-
 	// if secLock not empty, then masterKeyData is encrypted (protected), let's decrypt it first.
 	if secLock != nil {
 		decResponse, e := secLock.Decrypt("", &secretlock.DecryptRequest{
@@ -84,28 +108,22 @@ func NewService(masterKeyReader io.Reader, secLock secretlock.Service) (secretlo
 			return nil, e
 		}
 
-		masterKey = []byte(decResponse.Plaintext)
-	} else {
-		// masterKeyData is not encrypted, base64URL decode it
-		masterKey, err = base64.URLEncoding.DecodeString(string(masterKeyData[:n]))
-		if err != nil {
-			logger.Warnf("base64URL.Decode of unprotected master key failed. " +
-				"Will attempt to create a service using the key content from reader as is.")
-
-			masterKey = make([]byte, n)
-
-			// copy masterKey read from reader directly
-			copy(masterKey, masterKeyData)
-		}
+		return []byte(decResponse.Plaintext), nil
 	}
 
-	// finally create the cipher to be used by the lock service
-	aead, err := cipherutil.CreateAESCipher(masterKey)
+	// masterKeyData is not encrypted, base64URL decode it
+	masterKey, err := base64.URLEncoding.DecodeString(string(masterKeyData[:n]))
 	if err != nil {
-		return nil, err
+		logger.Warnf("base64URL.Decode of unprotected master key failed. " +
+			"Will attempt to create a service using the key content from reader as is.")
+
+		masterKey = make([]byte, n)
+
+		// copy masterKey read from reader directly
+		copy(masterKey, masterKeyData)
 	}
 
-	return &Lock{aead: aead}, nil
+	return masterKey, nil
 }
 
 // Encrypt a key in req using master key in the local secret lock service
@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package local
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/subtle/random"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/internal/shamir"
+)
+
+const (
+	shareVersion = 1
+	splitIDSize  = 4
+	checksumSize = 4
+	// version, split ID and threshold
+	shareHeaderSize = 1 + splitIDSize + 1
+)
+
+// SplitMasterKey splits the master key in masterKeyReader into n shares with Shamir's secret sharing, any threshold of
+// them recover the master key with MasterKeyFromShares() while fewer reveal nothing about it. masterKeyReader and
+// secLock are read as in NewService(): if secLock is not nil, the master key is decrypted with secLock before it is
+// split.
+//
+// The shares are base64URL encoded strings which can be printed or written down, each one embeds the threshold, an
+// identifier of the split and a checksum detecting typos.
+func SplitMasterKey(masterKeyReader io.Reader, secLock secretlock.Service, n, threshold int) ([]string, error) {
+	masterKey, err := readMasterKey(masterKeyReader, secLock)
+	if err != nil {
+		return nil, fmt.Errorf("split master key: %w", err)
+	}
+
+	parts, err := shamir.Split(masterKey, n, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("split master key: %w", err)
+	}
+
+	splitID := random.GetRandomBytes(splitIDSize)
+	shares := make([]string, len(parts))
+
+	for i, part := range parts {
+		share := make([]byte, 0, shareHeaderSize+len(part)+checksumSize)
+		share = append(share, shareVersion)
+		share = append(share, splitID...)
+		share = append(share, byte(threshold))
+		share = append(share, part...)
+		share = append(share, checksum(share)...)
+
+		shares[i] = base64.URLEncoding.EncodeToString(share)
+	}
+
+	return shares, nil
+}
+
+// MasterKeyFromShares creates a new instance of a local secret lock Reader to read the master key recovered from
+// shares created by SplitMasterKey(), their number must reach the threshold of the split. The master key in the
+// reader is not encrypted, the lock service is created with NewService(reader, nil).
+func MasterKeyFromShares(shares []string) (io.Reader, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no master key shares")
+	}
+
+	var (
+		splitID   []byte
+		threshold int
+	)
+
+	parts := make([][]byte, len(shares))
+
+	for i, s := range shares {
+		share, err := base64.URLEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("master key share %d: %w", i, err)
+		}
+
+		if len(share) < shareHeaderSize+checksumSize ||
+			!bytes.Equal(checksum(share[:len(share)-checksumSize]), share[len(share)-checksumSize:]) {
+			return nil, fmt.Errorf("master key share %d is corrupted", i)
+		}
+
+		if share[0] != shareVersion {
+			return nil, fmt.Errorf("master key share %d: unsupported version %d", i, share[0])
+		}
+
+		if i == 0 {
+			splitID, threshold = share[1:1+splitIDSize], int(share[1+splitIDSize])
+		} else if !bytes.Equal(splitID, share[1:1+splitIDSize]) {
+			return nil, errors.New("master key shares come from different splits")
+		}
+
+		parts[i] = share[shareHeaderSize : len(share)-checksumSize]
+	}
+
+	if len(shares) < threshold {
+		return nil, fmt.Errorf("%d master key shares of %d required", len(shares), threshold)
+	}
+
+	masterKey, err := shamir.Combine(parts)
+	if err != nil {
+		return nil, fmt.Errorf("combine master key shares: %w", err)
+	}
+
+	return bytes.NewReader([]byte(base64.URLEncoding.EncodeToString(masterKey))), nil
+}
+
+func checksum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+
+	return sum[:checksumSize]
+}
@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package local
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	mocksecretlock "github.com/hyperledger/aries-framework-go/pkg/mock/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/argon2"
+)
+
+func TestSplitMasterKey(t *testing.T) {
+	masterKey := base64.URLEncoding.EncodeToString(random.GetRandomBytes(uint32(32)))
+
+	s, err := NewService(bytes.NewReader([]byte(masterKey)), nil)
+	require.NoError(t, err)
+
+	encResponse, err := s.Encrypt(testKeyURI, &secretlock.EncryptRequest{Plaintext: "test key"})
+	require.NoError(t, err)
+
+	shares, err := SplitMasterKey(bytes.NewReader([]byte(masterKey)), nil, 5, 3)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	t.Run("recover the lock service from a threshold of shares", func(t *testing.T) {
+		r, err := MasterKeyFromShares([]string{shares[4], shares[1], shares[2]})
+		require.NoError(t, err)
+
+		recovered, err := NewService(r, nil)
+		require.NoError(t, err)
+
+		decResponse, err := recovered.Decrypt(testKeyURI, &secretlock.DecryptRequest{
+			Ciphertext: encResponse.Ciphertext})
+		require.NoError(t, err)
+		require.Equal(t, "test key", decResponse.Plaintext)
+	})
+
+	t.Run("split a master key encrypted with a master lock", func(t *testing.T) {
+		masterLock, err := argon2.NewMasterLock("passphrase", argon2.WithMemory(64), argon2.WithThreads(1))
+		require.NoError(t, err)
+
+		lockedMasterKey, err := masterLock.Encrypt("", &secretlock.EncryptRequest{
+			Plaintext: string(random.GetRandomBytes(uint32(32)))})
+		require.NoError(t, err)
+
+		lockedShares, err := SplitMasterKey(bytes.NewReader([]byte(lockedMasterKey.Ciphertext)), masterLock, 3, 2)
+		require.NoError(t, err)
+
+		lockedService, err := NewService(bytes.NewReader([]byte(lockedMasterKey.Ciphertext)), masterLock)
+		require.NoError(t, err)
+
+		enc, err := lockedService.Encrypt(testKeyURI, &secretlock.EncryptRequest{Plaintext: "test key"})
+		require.NoError(t, err)
+
+		r, err := MasterKeyFromShares(lockedShares[1:])
+		require.NoError(t, err)
+
+		recovered, err := NewService(r, nil)
+		require.NoError(t, err)
+
+		dec, err := recovered.Decrypt(testKeyURI, &secretlock.DecryptRequest{Ciphertext: enc.Ciphertext})
+		require.NoError(t, err)
+		require.Equal(t, "test key", dec.Plaintext)
+	})
+
+	t.Run("invalid shares", func(t *testing.T) {
+		_, err := MasterKeyFromShares(nil)
+		require.EqualError(t, err, "no master key shares")
+
+		_, err = MasterKeyFromShares(shares[:2])
+		require.EqualError(t, err, "2 master key shares of 3 required")
+
+		_, err = MasterKeyFromShares([]string{shares[0], "!"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "master key share 1: ")
+
+		share, err := base64.URLEncoding.DecodeString(shares[1])
+		require.NoError(t, err)
+
+		share[shareHeaderSize] ^= 1
+
+		_, err = MasterKeyFromShares([]string{shares[0], base64.URLEncoding.EncodeToString(share), shares[2]})
+		require.EqualError(t, err, "master key share 1 is corrupted")
+
+		otherShares, err := SplitMasterKey(bytes.NewReader([]byte(masterKey)), nil, 5, 3)
+		require.NoError(t, err)
+
+		_, err = MasterKeyFromShares([]string{shares[0], shares[1], otherShares[2]})
+		require.EqualError(t, err, "master key shares come from different splits")
+
+		_, err = MasterKeyFromShares([]string{shares[0], shares[0], shares[1]})
+		require.EqualError(t, err, "combine master key shares: duplicate share")
+	})
+
+	t.Run("split failures", func(t *testing.T) {
+		_, err := SplitMasterKey(bytes.NewReader([]byte(masterKey)), nil, 2, 3)
+		require.EqualError(t, err, "split master key: invalid threshold 3 of 2 shares")
+
+		_, err = SplitMasterKey(nil, nil, 5, 3)
+		require.EqualError(t, err, "split master key: masterKeyReader is nil")
+
+		errDecrypt := errors.New("decrypt error")
+
+		_, err = SplitMasterKey(bytes.NewReader([]byte(masterKey)), &mocksecretlock.MockSecretLock{
+			ErrDecrypt: errDecrypt,
+		}, 5, 3)
+		require.True(t, errors.Is(err, errDecrypt))
+	})
+}
@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package argon2
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/internal/kdflock"
+)
+
+// Default costs of the Argon2id key derivation, as recommended by RFC 9106 for memory constrained environments.
+const (
+	DefaultTime    = 3
+	DefaultMemory  = 64 * 1024
+	DefaultThreads = 4
+)
+
+// Maximum costs of the Argon2id key derivation. The costs of an encrypted master key are read from its header before
+// it can be authenticated, they are bounded so that a corrupted or tampered header can't exhaust the agent's memory.
+const (
+	MaxTime   = 64
+	MaxMemory = 1024 * 1024
+)
+
+type params struct {
+	time    uint32
+	memory  uint32
+	threads uint32
+}
+
+// Option sets a cost of the Argon2id key derivation.
+type Option func(p *params)
+
+// WithTime sets the number of passes over the memory.
+func WithTime(time uint32) Option {
+	return func(p *params) {
+		p.time = time
+	}
+}
+
+// WithMemory sets the size of the memory in KiB.
+func WithMemory(memory uint32) Option {
+	return func(p *params) {
+		p.memory = memory
+	}
+}
+
+// WithThreads sets the number of threads.
+func WithThreads(threads uint8) Option {
+	return func(p *params) {
+		p.threads = uint32(threads)
+	}
+}
+
+// NewMasterLock is responsible for encrypting/decrypting a master key with a key derived from `passphrase` using
+// Argon2id, a memory-hard key derivation resisting brute-force attacks on the passphrase if the encrypted master key
+// leaks. The costs of the key derivation default to DefaultTime, DefaultMemory and DefaultThreads.
+// The size of a master key passed to Encrypt() must be 32 bytes. The encrypted master key starts with a header
+// describing the key derivation, its costs and its random salt, so that master keys encrypted with other costs can
+// still be decrypted.
+// This implementation must not be used directly in Aries framework. It should be passed in
+// as the second argument to local secret lock service constructor:
+// `local.NewService(masterKeyReader io.Reader, secLock secretlock.Service)`
+func NewMasterLock(passphrase string, opts ...Option) (secretlock.Service, error) {
+	p := &params{time: DefaultTime, memory: DefaultMemory, threads: DefaultThreads}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if err := validate(p.time, p.memory, p.threads); err != nil {
+		return nil, err
+	}
+
+	return kdflock.New(passphrase, kdflock.Argon2id, [3]uint32{p.time, p.memory, p.threads}, deriveKey)
+}
+
+func deriveKey(passphrase, salt []byte, params [3]uint32) ([]byte, error) {
+	if err := validate(params[0], params[1], params[2]); err != nil {
+		return nil, err
+	}
+
+	return argon2.IDKey(passphrase, salt, params[0], params[1], uint8(params[2]), kdflock.KeySize), nil
+}
+
+func validate(time, memory, threads uint32) error {
+	if time == 0 || threads == 0 || threads > math.MaxUint8 {
+		return errors.New("invalid argon2id costs")
+	}
+
+	// Argon2 requires at least 8 KiB of memory per thread
+	if memory < 8*threads {
+		return errors.New("argon2id memory must be at least 8 KiB per thread")
+	}
+
+	if time > MaxTime || memory > MaxMemory {
+		return errors.New("argon2id costs exceed the maximum")
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package argon2
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+)
+
+func TestMasterLock(t *testing.T) {
+	testKey := random.GetRandomBytes(32)
+	goodPassphrase := "somepassphrase"
+
+	mkLock, err := NewMasterLock(goodPassphrase, WithTime(1), WithMemory(64), WithThreads(2))
+	require.NoError(t, err)
+
+	encryptedMk, err := mkLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: string(testKey)})
+	require.NoError(t, err)
+	require.NotEmpty(t, encryptedMk)
+
+	decryptedMk, err := mkLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encryptedMk.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, testKey, []byte(decryptedMk.Plaintext))
+
+	// a lock with other costs decrypts the master key with the costs of its header
+	mkLock2, err := NewMasterLock(goodPassphrase, WithTime(2), WithMemory(128))
+	require.NoError(t, err)
+
+	decryptedMk2, err := mkLock2.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encryptedMk.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, testKey, []byte(decryptedMk2.Plaintext))
+
+	// the same master key encrypted twice uses different salts
+	encryptedMk2, err := mkLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: string(testKey)})
+	require.NoError(t, err)
+	require.NotEqual(t, encryptedMk.Ciphertext, encryptedMk2.Ciphertext)
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		badLock, err := NewMasterLock("otherpassphrase", WithTime(1), WithMemory(64))
+		require.NoError(t, err)
+
+		_, err = badLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encryptedMk.Ciphertext})
+		require.Error(t, err)
+	})
+
+	t.Run("tampered header", func(t *testing.T) {
+		ct, err := base64.URLEncoding.DecodeString(encryptedMk.Ciphertext)
+		require.NoError(t, err)
+
+		// increase the time cost of the header
+		ct[9]++
+
+		_, err = mkLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: base64.URLEncoding.EncodeToString(ct)})
+		require.EqualError(t, err, "cipher: message authentication failed")
+
+		// a memory cost of 4 TiB is rejected before deriving the key
+		copy(ct[10:14], []byte{0xff, 0xff, 0xff, 0xff})
+
+		_, err = mkLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: base64.URLEncoding.EncodeToString(ct)})
+		require.EqualError(t, err, "argon2id costs exceed the maximum")
+
+		// unsupported version
+		ct[4] = 2
+
+		_, err = mkLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: base64.URLEncoding.EncodeToString(ct)})
+		require.EqualError(t, err, "unsupported key derivation header version 2")
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		_, err := mkLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: "BadKey"})
+		require.EqualError(t, err, "invalid key size")
+
+		_, err = mkLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: "bad{}base64URLstring[]"})
+		require.Error(t, err)
+
+		_, err = mkLock.Decrypt("", &secretlock.DecryptRequest{
+			Ciphertext: base64.URLEncoding.EncodeToString(random.GetRandomBytes(64)),
+		})
+		require.EqualError(t, err, "master key has no key derivation header")
+	})
+
+	t.Run("invalid costs", func(t *testing.T) {
+		_, err := NewMasterLock("")
+		require.EqualError(t, err, "passphrase is empty")
+
+		_, err = NewMasterLock(goodPassphrase, WithTime(0))
+		require.EqualError(t, err, "invalid argon2id costs")
+
+		_, err = NewMasterLock(goodPassphrase, WithThreads(0))
+		require.EqualError(t, err, "invalid argon2id costs")
+
+		_, err = NewMasterLock(goodPassphrase, WithMemory(8), WithThreads(2))
+		require.EqualError(t, err, "argon2id memory must be at least 8 KiB per thread")
+
+		_, err = NewMasterLock(goodPassphrase, WithTime(MaxTime+1))
+		require.EqualError(t, err, "argon2id costs exceed the maximum")
+
+		_, err = NewMasterLock(goodPassphrase, WithMemory(MaxMemory+1))
+		require.EqualError(t, err, "argon2id costs exceed the maximum")
+	})
+}
@@ -0,0 +1,193 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kdflock
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/google/tink/go/subtle/random"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	cipherutil "github.com/hyperledger/aries-framework-go/pkg/secretlock/local/internal/cipher"
+)
+
+// KDF identifies the key derivation function of the header.
+type KDF byte
+
+const (
+	// Argon2id derives keys with Argon2id, its params are the time, memory (in KiB) and threads costs.
+	Argon2id = KDF(1)
+	// Scrypt derives keys with scrypt, its params are the N, r and p costs.
+	Scrypt = KDF(2)
+)
+
+const (
+	// KeySize is the size of the derived keys, AES-256 keys.
+	KeySize = 32
+	// SaltSize is the size of the random salt of the keys derived by Encrypt.
+	SaltSize = 16
+
+	magic         = "AMKL"
+	headerVersion = 1
+	paramsCount   = 3
+	// magic, version, KDF, params and salt size
+	headerSize = len(magic) + 3 + paramsCount*4
+)
+
+// DeriveKeyFunc derives a KeySize key from passphrase with salt and the params of the KDF.
+type DeriveKeyFunc func(passphrase, salt []byte, params [3]uint32) ([]byte, error)
+
+// Lock encrypts master keys with a key derived from a passphrase, the encrypted master keys start with a header
+// describing how to derive the key: the KDF, its params and its salt.
+type Lock struct {
+	passphrase []byte
+	kdf        KDF
+	params     [3]uint32
+	deriveKey  DeriveKeyFunc
+}
+
+// New returns a Lock encrypting master keys with a key derived from passphrase by kdf with params.
+func New(passphrase string, kdf KDF, params [3]uint32, deriveKey DeriveKeyFunc) (*Lock, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+
+	return &Lock{passphrase: []byte(passphrase), kdf: kdf, params: params, deriveKey: deriveKey}, nil
+}
+
+// Encrypt a master key in req with a key derived with a new random salt.
+// (keyURI is used for remote locks, it is ignored by this implementation)
+func (l *Lock) Encrypt(keyURI string, req *secretlock.EncryptRequest) (*secretlock.EncryptResponse, error) {
+	if len(req.Plaintext) != KeySize {
+		return nil, fmt.Errorf("invalid key size")
+	}
+
+	salt := random.GetRandomBytes(SaltSize)
+
+	header := l.header(salt)
+
+	key, err := l.deriveKey(l.passphrase, salt, l.params)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipherutil.CreateAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// the header is authenticated so that its params can't be tampered with
+	nonce := random.GetRandomBytes(uint32(aead.NonceSize()))
+	ct := aead.Seal(nil, nonce, []byte(req.Plaintext), authenticatedData(header, req.AdditionalAuthenticatedData))
+
+	ct = append(append(header, nonce...), ct...)
+
+	return &secretlock.EncryptResponse{
+		Ciphertext: base64.URLEncoding.EncodeToString(ct),
+	}, nil
+}
+
+// Decrypt a master key in req with the key derived as described by its header, the params of the header may differ
+// from the params of the lock.
+// (keyURI is used for remote locks, it is ignored by this implementation)
+func (l *Lock) Decrypt(keyURI string, req *secretlock.DecryptRequest) (*secretlock.DecryptResponse, error) {
+	ct, err := base64.URLEncoding.DecodeString(req.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	kdf, params, salt, err := ParseHeader(ct)
+	if err != nil {
+		return nil, err
+	}
+
+	if kdf != l.kdf {
+		return nil, fmt.Errorf("master key locked with another key derivation function")
+	}
+
+	header := ct[:headerSize+len(salt)]
+	ct = ct[len(header):]
+
+	key, err := l.deriveKey(l.passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipherutil.CreateAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := aead.NonceSize()
+
+	// ensure ciphertext contains more than nonce+ciphertext (result from Encrypt())
+	if len(ct) <= nonceSize {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	aad := authenticatedData(header, req.AdditionalAuthenticatedData)
+
+	pt, err := aead.Open(nil, ct[:nonceSize], ct[nonceSize:], aad)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secretlock.DecryptResponse{Plaintext: string(pt)}, nil
+}
+
+func (l *Lock) header(salt []byte) []byte {
+	header := make([]byte, 0, headerSize+len(salt))
+
+	header = append(header, magic...)
+	header = append(header, headerVersion, byte(l.kdf))
+
+	for _, p := range l.params {
+		header = append(header, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(header[len(header)-4:], p)
+	}
+
+	header = append(header, byte(len(salt)))
+
+	return append(header, salt...)
+}
+
+func authenticatedData(header []byte, aad string) []byte {
+	return append(append([]byte(nil), header...), aad...)
+}
+
+// ParseHeader parses the header of an encrypted master key, it returns its KDF, its params and its salt.
+func ParseHeader(data []byte) (KDF, [3]uint32, []byte, error) {
+	var params [3]uint32
+
+	if len(data) < headerSize || string(data[:len(magic)]) != magic {
+		return 0, params, nil, errors.New("master key has no key derivation header")
+	}
+
+	data = data[len(magic):]
+
+	if data[0] != headerVersion {
+		return 0, params, nil, fmt.Errorf("unsupported key derivation header version %d", data[0])
+	}
+
+	kdf := KDF(data[1])
+	data = data[2:]
+
+	for i := range params {
+		params[i] = binary.BigEndian.Uint32(data[i*4:])
+	}
+
+	data = data[paramsCount*4:]
+
+	saltSize := int(data[0])
+	if saltSize == 0 || len(data) < 1+saltSize {
+		return 0, params, nil, errors.New("invalid key derivation header salt")
+	}
+
+	return kdf, params, data[1 : 1+saltSize], nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package masterlock
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/internal/kdflock"
+)
+
+// Key derivations of the master locks with a self-describing header.
+const (
+	// Argon2id is the key derivation of argon2.NewMasterLock().
+	Argon2id = "argon2id"
+	// Scrypt is the key derivation of scrypt.NewMasterLock().
+	Scrypt = "scrypt"
+)
+
+// KeyDerivation returns the key derivation described by the header of the encrypted master key lockedMasterKey, or
+// an empty string if it has no header, eg if it is encrypted with hkdf.NewMasterLock().
+func KeyDerivation(lockedMasterKey string) string {
+	data, err := base64.URLEncoding.DecodeString(lockedMasterKey)
+	if err != nil {
+		return ""
+	}
+
+	kdf, _, _, err := kdflock.ParseHeader(data)
+	if err != nil {
+		return ""
+	}
+
+	switch kdf {
+	case kdflock.Argon2id:
+		return Argon2id
+	case kdflock.Scrypt:
+		return Scrypt
+	default:
+		return ""
+	}
+}
+
+// Rewrap decrypts the master key lockedMasterKey with oldLock and encrypts it with newLock, eg to move a master key
+// encrypted with hkdf.NewMasterLock() to argon2.NewMasterLock() or scrypt.NewMasterLock(). The master key itself
+// doesn't change, the keys it protects don't need to be re-encrypted.
+func Rewrap(lockedMasterKey string, oldLock, newLock secretlock.Service) (string, error) {
+	dec, err := oldLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: lockedMasterKey})
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt master key: %w", err)
+	}
+
+	enc, err := newLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: dec.Plaintext})
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt master key: %w", err)
+	}
+
+	return enc.Ciphertext, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package masterlock_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	mocksecretlock "github.com/hyperledger/aries-framework-go/pkg/mock/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/argon2"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/hkdf"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/scrypt"
+)
+
+func TestRewrap(t *testing.T) {
+	passphrase := "somepassphrase"
+	masterKey := random.GetRandomBytes(32)
+
+	hkdfLock, err := hkdf.NewMasterLock(passphrase, sha256.New, nil)
+	require.NoError(t, err)
+
+	hkdfLocked, err := hkdfLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: string(masterKey)})
+	require.NoError(t, err)
+	require.Empty(t, masterlock.KeyDerivation(hkdfLocked.Ciphertext))
+
+	argon2Lock, err := argon2.NewMasterLock(passphrase, argon2.WithTime(1), argon2.WithMemory(64))
+	require.NoError(t, err)
+
+	argon2Locked, err := masterlock.Rewrap(hkdfLocked.Ciphertext, hkdfLock, argon2Lock)
+	require.NoError(t, err)
+	require.Equal(t, masterlock.Argon2id, masterlock.KeyDerivation(argon2Locked))
+
+	scryptLock, err := scrypt.NewMasterLock(passphrase, scrypt.WithN(1024))
+	require.NoError(t, err)
+
+	scryptLocked, err := masterlock.Rewrap(argon2Locked, argon2Lock, scryptLock)
+	require.NoError(t, err)
+	require.Equal(t, masterlock.Scrypt, masterlock.KeyDerivation(scryptLocked))
+
+	// the secret lock services of the rewrapped master keys encrypt and decrypt the same keys
+	hkdfService, err := local.NewService(bytes.NewReader([]byte(hkdfLocked.Ciphertext)), hkdfLock)
+	require.NoError(t, err)
+
+	scryptService, err := local.NewService(bytes.NewReader([]byte(scryptLocked)), scryptLock)
+	require.NoError(t, err)
+
+	enc, err := hkdfService.Encrypt("", &secretlock.EncryptRequest{Plaintext: "key"})
+	require.NoError(t, err)
+
+	dec, err := scryptService.Decrypt("", &secretlock.DecryptRequest{Ciphertext: enc.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, "key", dec.Plaintext)
+
+	t.Run("rewrap failures", func(t *testing.T) {
+		_, err := masterlock.Rewrap(hkdfLocked.Ciphertext, argon2Lock, scryptLock)
+		require.EqualError(t, err, "failed to decrypt master key: master key has no key derivation header")
+
+		_, err = masterlock.Rewrap(argon2Locked, argon2Lock, &mocksecretlock.MockSecretLock{
+			ErrEncrypt: errors.New("encrypt error"),
+		})
+		require.EqualError(t, err, "failed to encrypt master key: encrypt error")
+
+		require.Empty(t, masterlock.KeyDerivation("bad{}base64URLstring[]"))
+	})
+}
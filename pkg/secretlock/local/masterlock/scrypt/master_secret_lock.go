@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scrypt
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/internal/kdflock"
+)
+
+// Default costs of the scrypt key derivation, as recommended by the scrypt package for interactive logins.
+const (
+	DefaultN = 32768
+	DefaultR = 8
+	DefaultP = 1
+)
+
+// Maximum costs of the scrypt key derivation, which uses 128*N*r bytes of memory. The costs of an encrypted master key
+// are read from its header before it can be authenticated, they are bounded so that a corrupted or tampered header
+// can't exhaust the agent's memory or CPU.
+const (
+	MaxMemory = 1 << 30
+	MaxP      = 16
+)
+
+type params struct {
+	n uint32
+	r uint32
+	p uint32
+}
+
+// Option sets a cost of the scrypt key derivation.
+type Option func(p *params)
+
+// WithN sets the CPU/memory cost, a power of two greater than 1.
+func WithN(n uint32) Option {
+	return func(p *params) {
+		p.n = n
+	}
+}
+
+// WithR sets the block size.
+func WithR(r uint32) Option {
+	return func(p *params) {
+		p.r = r
+	}
+}
+
+// WithP sets the parallelization.
+func WithP(parallelization uint32) Option {
+	return func(p *params) {
+		p.p = parallelization
+	}
+}
+
+// NewMasterLock is responsible for encrypting/decrypting a master key with a key derived from `passphrase` using
+// scrypt, a memory-hard key derivation resisting brute-force attacks on the passphrase if the encrypted master key
+// leaks. The costs of the key derivation default to DefaultN, DefaultR and DefaultP.
+// The size of a master key passed to Encrypt() must be 32 bytes. The encrypted master key starts with a header
+// describing the key derivation, its costs and its random salt, so that master keys encrypted with other costs can
+// still be decrypted.
+// This implementation must not be used directly in Aries framework. It should be passed in
+// as the second argument to local secret lock service constructor:
+// `local.NewService(masterKeyReader io.Reader, secLock secretlock.Service)`
+func NewMasterLock(passphrase string, opts ...Option) (secretlock.Service, error) {
+	p := &params{n: DefaultN, r: DefaultR, p: DefaultP}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if err := validate(p.n, p.r, p.p); err != nil {
+		return nil, err
+	}
+
+	return kdflock.New(passphrase, kdflock.Scrypt, [3]uint32{p.n, p.r, p.p}, deriveKey)
+}
+
+func deriveKey(passphrase, salt []byte, params [3]uint32) ([]byte, error) {
+	if err := validate(params[0], params[1], params[2]); err != nil {
+		return nil, err
+	}
+
+	return scrypt.Key(passphrase, salt, int(params[0]), int(params[1]), int(params[2]), kdflock.KeySize)
+}
+
+func validate(n, r, p uint32) error {
+	if n <= 1 || n&(n-1) != 0 {
+		return errors.New("scrypt N must be a power of two greater than 1")
+	}
+
+	if r == 0 || p == 0 || uint64(r)*uint64(p) >= 1<<30 {
+		return errors.New("invalid scrypt costs")
+	}
+
+	const blockSize = 128
+
+	if blockSize*uint64(n)*uint64(r) > MaxMemory || p > MaxP {
+		return errors.New("scrypt costs exceed the maximum")
+	}
+
+	return nil
+}
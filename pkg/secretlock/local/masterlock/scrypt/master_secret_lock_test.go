@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package scrypt
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/argon2"
+)
+
+func TestMasterLock(t *testing.T) {
+	testKey := random.GetRandomBytes(32)
+	goodPassphrase := "somepassphrase"
+
+	mkLock, err := NewMasterLock(goodPassphrase, WithN(1024), WithR(8), WithP(1))
+	require.NoError(t, err)
+
+	encryptedMk, err := mkLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: string(testKey)})
+	require.NoError(t, err)
+
+	decryptedMk, err := mkLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encryptedMk.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, testKey, []byte(decryptedMk.Plaintext))
+
+	// a lock with other costs decrypts the master key with the costs of its header
+	mkLock2, err := NewMasterLock(goodPassphrase, WithN(2048))
+	require.NoError(t, err)
+
+	decryptedMk2, err := mkLock2.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encryptedMk.Ciphertext})
+	require.NoError(t, err)
+	require.Equal(t, testKey, []byte(decryptedMk2.Plaintext))
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		badLock, err := NewMasterLock("otherpassphrase", WithN(1024))
+		require.NoError(t, err)
+
+		_, err = badLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encryptedMk.Ciphertext})
+		require.Error(t, err)
+	})
+
+	t.Run("master key locked with argon2id", func(t *testing.T) {
+		argon2Lock, err := argon2.NewMasterLock(goodPassphrase, argon2.WithTime(1), argon2.WithMemory(64))
+		require.NoError(t, err)
+
+		encrypted, err := argon2Lock.Encrypt("", &secretlock.EncryptRequest{Plaintext: string(testKey)})
+		require.NoError(t, err)
+
+		_, err = mkLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: encrypted.Ciphertext})
+		require.EqualError(t, err, "master key locked with another key derivation function")
+	})
+
+	t.Run("invalid costs", func(t *testing.T) {
+		_, err := NewMasterLock(goodPassphrase, WithN(1000))
+		require.EqualError(t, err, "scrypt N must be a power of two greater than 1")
+
+		_, err = NewMasterLock(goodPassphrase, WithR(0))
+		require.EqualError(t, err, "invalid scrypt costs")
+
+		_, err = NewMasterLock(goodPassphrase, WithR(1<<15), WithP(1<<15))
+		require.EqualError(t, err, "invalid scrypt costs")
+
+		_, err = NewMasterLock(goodPassphrase, WithN(1<<21))
+		require.EqualError(t, err, "scrypt costs exceed the maximum")
+
+		_, err = NewMasterLock(goodPassphrase, WithP(MaxP+1))
+		require.EqualError(t, err, "scrypt costs exceed the maximum")
+	})
+
+	t.Run("header costs exceeding the maximum", func(t *testing.T) {
+		ct, err := base64.URLEncoding.DecodeString(encryptedMk.Ciphertext)
+		require.NoError(t, err)
+
+		// N = 2^31 would need 2 TiB of memory with r = 8
+		copy(ct[6:10], []byte{0x80, 0, 0, 0})
+
+		_, err = mkLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: base64.URLEncoding.EncodeToString(ct)})
+		require.EqualError(t, err, "scrypt costs exceed the maximum")
+	})
+}
@@ -0,0 +1,283 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package backup moves the content of stores between storage providers through a single encrypted archive.
+//
+// Export streams the records of the given stores, along with their tags, into an archive protected by a passphrase:
+//
+//		err := backup.Export(w, provider, passphrase, "didexchange", "verifiable")
+//
+// Import restores every store of the archive into any provider, overwriting records of the same keys:
+//
+//		err := backup.Import(r, otherProvider, passphrase)
+//
+// The archive starts with a header holding a random data key wrapped by an Argon2id secretlock master lock derived
+// from the passphrase, the costs and salt of the key derivation are part of the wrapped key. The records follow,
+// encrypted with the data key by a streaming AEAD, so that neither Export nor Import holds a whole store in memory.
+//
+// Records are archived as they are stored: the keysets of the local KMS stay wrapped by the agent's secret lock,
+// so an archive restored on another machine is only usable along with the same secret lock, e.g. the same
+// master key file and its passphrase, which have to be moved separately.
+//
+// Keys are expected to be printable ASCII, like every key written by the framework.
+package backup
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/tink/go/streamingaead/subtle"
+	"github.com/google/tink/go/subtle/random"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local/masterlock/argon2"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// archiveVersion is the version of the archive format written by Export.
+	archiveVersion = 2
+
+	// range covering every printable key
+	firstKey = " "
+	lastKey  = "~" + storage.EndKeySuffix
+
+	dataKeySize = 32
+	segmentSize = 4096
+
+	// binds the encrypted records to the archive format
+	dataAAD = "aries-framework-go/backup/v2"
+
+	// importBatchSize bounds the number of records written to a store at once by Import
+	importBatchSize = 100
+)
+
+// header is the first line of an archive, Key is the data key wrapped with the passphrase.
+type header struct {
+	Version int    `json:"version"`
+	Key     string `json:"key"`
+}
+
+// entry is either the start of a store, when Store is set, or a record of the last started store.
+type entry struct {
+	Store string        `json:"store,omitempty"`
+	Key   string        `json:"key,omitempty"`
+	Value []byte        `json:"value"`
+	Tags  []storage.Tag `json:"tags,omitempty"`
+}
+
+// Export writes the records of the stores of provider with the given names, along with their tags,
+// to w as a single archive encrypted with passphrase. Records are streamed to w as they're read.
+func Export(w io.Writer, provider storage.Provider, passphrase string, storeNames ...string) error {
+	passphraseLock, err := argon2.NewMasterLock(passphrase)
+	if err != nil {
+		return fmt.Errorf("create passphrase lock: %w", err)
+	}
+
+	dataKey := random.GetRandomBytes(dataKeySize)
+
+	wrapped, err := passphraseLock.Encrypt("", &secretlock.EncryptRequest{Plaintext: string(dataKey)})
+	if err != nil {
+		return fmt.Errorf("wrap data key: %w", err)
+	}
+
+	err = json.NewEncoder(w).Encode(&header{Version: archiveVersion, Key: wrapped.Ciphertext})
+	if err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+
+	dataCipher, err := newDataCipher(dataKey)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := dataCipher.NewEncryptingWriter(w, []byte(dataAAD))
+	if err != nil {
+		return fmt.Errorf("seal stores: %w", err)
+	}
+
+	// the encrypting writer seals every write, the buffer groups the entries into segments
+	buffered := bufio.NewWriterSize(sealed, segmentSize)
+	encoder := json.NewEncoder(buffered)
+
+	for _, name := range storeNames {
+		if err = exportStore(encoder, provider, name); err != nil {
+			return err
+		}
+	}
+
+	if err = buffered.Flush(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+
+	if err = sealed.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+
+	return nil
+}
+
+// Import restores every store of an archive written by Export into provider, replacing the records of the same
+// keys and keeping the others. Records are written as they're read, in batches of up to 100 records, so an archive
+// failing part way leaves the records restored until then.
+func Import(r io.Reader, provider storage.Provider, passphrase string) error {
+	decoder := json.NewDecoder(r)
+
+	var h header
+
+	if err := decoder.Decode(&h); err != nil {
+		return fmt.Errorf("read archive: %w", err)
+	}
+
+	if h.Version != archiveVersion {
+		return fmt.Errorf("archive version %d not supported", h.Version)
+	}
+
+	passphraseLock, err := argon2.NewMasterLock(passphrase)
+	if err != nil {
+		return fmt.Errorf("create passphrase lock: %w", err)
+	}
+
+	dataKey, err := passphraseLock.Decrypt("", &secretlock.DecryptRequest{Ciphertext: h.Key})
+	if err != nil {
+		// a wrong passphrase only shows up as an authentication failure
+		return fmt.Errorf("unwrap data key (wrong passphrase?): %w", err)
+	}
+
+	dataCipher, err := newDataCipher([]byte(dataKey.Plaintext))
+	if err != nil {
+		return err
+	}
+
+	// the header decoder may have read ahead into the records, up to which the header line ends
+	sealed := bufio.NewReader(io.MultiReader(decoder.Buffered(), r))
+
+	if b, e := sealed.ReadByte(); e != nil || b != '\n' {
+		return errors.New("read archive: header isn't terminated by a new line")
+	}
+
+	data, err := dataCipher.NewDecryptingReader(sealed, []byte(dataAAD))
+	if err != nil {
+		return fmt.Errorf("open stores: %w", err)
+	}
+
+	return importStores(json.NewDecoder(data), provider)
+}
+
+func exportStore(encoder *json.Encoder, provider storage.Provider, name string) error {
+	s, err := provider.OpenStore(name)
+	if err != nil {
+		return fmt.Errorf("open store %s: %w", name, err)
+	}
+
+	if err = encoder.Encode(&entry{Store: name}); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+
+	itr := s.Iterator(firstKey, lastKey)
+	defer itr.Release()
+
+	for itr.Next() {
+		rec := entry{Key: string(itr.Key()), Value: itr.Value()}
+
+		rec.Tags, err = s.GetTags(rec.Key)
+		if err != nil {
+			return fmt.Errorf("get tags of %s in store %s: %w", rec.Key, name, err)
+		}
+
+		if err = encoder.Encode(&rec); err != nil {
+			return fmt.Errorf("write archive: %w", err)
+		}
+	}
+
+	if err = itr.Error(); err != nil {
+		return fmt.Errorf("read store %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// importStores writes the entries read by decoder to their stores.
+func importStores(decoder *json.Decoder, provider storage.Provider) error {
+	var (
+		store storage.Store
+		name  string
+		ops   []storage.Operation
+	)
+
+	for {
+		var e entry
+
+		err := decoder.Decode(&e)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("open stores: %w", err)
+		}
+
+		if e.Store != "" {
+			if err = writeRecords(store, name, ops); err != nil {
+				return err
+			}
+
+			name, ops = e.Store, nil
+
+			store, err = provider.OpenStore(name)
+			if err != nil {
+				return fmt.Errorf("open store %s: %w", name, err)
+			}
+
+			continue
+		}
+
+		if store == nil {
+			return errors.New("store name is mandatory")
+		}
+
+		if e.Value == nil {
+			e.Value = []byte{}
+		}
+
+		ops = append(ops, storage.Operation{Key: e.Key, Value: e.Value, Tags: e.Tags})
+
+		if len(ops) == importBatchSize {
+			if err = writeRecords(store, name, ops); err != nil {
+				return err
+			}
+
+			ops = nil
+		}
+	}
+
+	return writeRecords(store, name, ops)
+}
+
+func writeRecords(store storage.Store, name string, ops []storage.Operation) error {
+	if len(ops) == 0 {
+		return nil
+	}
+
+	if err := store.Batch(ops); err != nil {
+		return fmt.Errorf("write store %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// newDataCipher returns the streaming AEAD sealing the records with dataKey.
+func newDataCipher(dataKey []byte) (*subtle.AESGCMHKDF, error) {
+	dataCipher, err := subtle.NewAESGCMHKDF(dataKey, "SHA256", dataKeySize, segmentSize, 0)
+	if err != nil {
+		return nil, fmt.Errorf("create data cipher: %w", err)
+	}
+
+	return dataCipher, nil
+}
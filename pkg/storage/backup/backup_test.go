@@ -0,0 +1,165 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+const passphrase = "correct horse battery staple"
+
+func TestExportImport(t *testing.T) {
+	source := mem.NewProvider()
+
+	connections, err := source.OpenStore("didexchange")
+	require.NoError(t, err)
+	require.NoError(t, connections.Put("conn_1", []byte(`{"state":"completed"}`),
+		storage.Tag{Name: "state", Value: "completed"}))
+	require.NoError(t, connections.Put("conn_2", []byte{}))
+
+	credentials, err := source.OpenStore("verifiable")
+	require.NoError(t, err)
+	require.NoError(t, credentials.Put("credential_1", []byte("vc")))
+
+	var archive bytes.Buffer
+
+	require.NoError(t, Export(&archive, source, passphrase, "didexchange", "verifiable", "empty"))
+	require.NotContains(t, archive.String(), "completed")
+	require.NotContains(t, archive.String(), "didexchange")
+
+	t.Run("test import - records and tags are restored", func(t *testing.T) {
+		target := mem.NewProvider()
+
+		require.NoError(t, Import(bytes.NewReader(archive.Bytes()), target, passphrase))
+
+		connections, err := target.OpenStore("didexchange")
+		require.NoError(t, err)
+
+		v, err := connections.Get("conn_1")
+		require.NoError(t, err)
+		require.Equal(t, []byte(`{"state":"completed"}`), v)
+
+		v, err = connections.Get("conn_2")
+		require.NoError(t, err)
+		require.Empty(t, v)
+
+		itr, err := connections.Query("state:completed")
+		require.NoError(t, err)
+		require.True(t, itr.Next())
+		require.Equal(t, "conn_1", string(itr.Key()))
+		itr.Release()
+
+		credentials, err := target.OpenStore("verifiable")
+		require.NoError(t, err)
+
+		v, err = credentials.Get("credential_1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("vc"), v)
+	})
+
+	t.Run("test import - wrong passphrase", func(t *testing.T) {
+		err := Import(bytes.NewReader(archive.Bytes()), mem.NewProvider(), "wrong")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unwrap data key (wrong passphrase?)")
+	})
+
+	t.Run("test import - invalid archive", func(t *testing.T) {
+		err := Import(strings.NewReader("{"), mem.NewProvider(), passphrase)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "read archive")
+
+		err = Import(strings.NewReader(`{"version":1}`), mem.NewProvider(), passphrase)
+		require.EqualError(t, err, "archive version 1 not supported")
+
+		tampered := append([]byte(nil), archive.Bytes()...)
+		tampered[len(tampered)-1] ^= 1
+
+		err = Import(bytes.NewReader(tampered), mem.NewProvider(), passphrase)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open stores")
+
+		truncated := archive.Bytes()[:bytes.IndexByte(archive.Bytes(), '\n')+40]
+
+		err = Import(bytes.NewReader(truncated), mem.NewProvider(), passphrase)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "open stores")
+	})
+
+	t.Run("test import - storage errors", func(t *testing.T) {
+		err := Import(bytes.NewReader(archive.Bytes()),
+			&mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New("open")}, passphrase)
+		require.EqualError(t, err, "open store didexchange: open")
+
+		err = Import(bytes.NewReader(archive.Bytes()), &mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: map[string][]byte{}, ErrBatch: errors.New("batch"),
+		}}, passphrase)
+		require.EqualError(t, err, "write store didexchange: batch")
+	})
+}
+
+func TestExportImport_ManyRecords(t *testing.T) {
+	source := mem.NewProvider()
+
+	s, err := source.OpenStore("verifiable")
+	require.NoError(t, err)
+
+	const count = 2*importBatchSize + 1
+
+	value := bytes.Repeat([]byte("v"), segmentSize)
+
+	for i := 0; i < count; i++ {
+		require.NoError(t, s.Put(fmt.Sprintf("credential_%03d", i), value))
+	}
+
+	var archive bytes.Buffer
+
+	require.NoError(t, Export(&archive, source, passphrase, "verifiable"))
+
+	target := mem.NewProvider()
+
+	require.NoError(t, Import(&archive, target, passphrase))
+
+	restored, err := target.OpenStore("verifiable")
+	require.NoError(t, err)
+
+	itr := restored.Iterator("credential_", "credential_"+storage.EndKeySuffix)
+	defer itr.Release()
+
+	n := 0
+
+	for itr.Next() {
+		require.Equal(t, value, itr.Value())
+
+		n++
+	}
+
+	require.Equal(t, count, n)
+}
+
+func TestExport_Errors(t *testing.T) {
+	err := Export(&bytes.Buffer{}, mem.NewProvider(), "", "didexchange")
+	require.EqualError(t, err, "create passphrase lock: passphrase is empty")
+
+	err = Export(&bytes.Buffer{}, &mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New("open")},
+		passphrase, "didexchange")
+	require.EqualError(t, err, "open store didexchange: open")
+
+	err = Export(&bytes.Buffer{}, &mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+		Store: map[string][]byte{}, ErrItr: errors.New("iterator"),
+	}}, passphrase, "didexchange")
+	require.EqualError(t, err, "read store didexchange: iterator")
+}
@@ -14,22 +14,29 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	_ "github.com/go-kivik/couchdb" // The CouchDB driver
 	"github.com/go-kivik/kivik"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
+var logger = log.New("aries-framework/storage/couchdb")
+
 // Provider represents an CouchDB implementation of the storage.Provider interface
 type Provider struct {
-	hostURL       string
-	couchDBClient *kivik.Client
-	dbs           map[string]*CouchDBStore
-	dbPrefix      string
+	hostURL        string
+	couchDBClient  *kivik.Client
+	dbs            map[string]*CouchDBStore
+	dbPrefix       string
+	expiryInterval time.Duration
 	sync.RWMutex
 }
 
@@ -37,6 +44,18 @@ const (
 	blankHostErrMsg           = "hostURL for new CouchDB provider can't be blank"
 	failToCloseProviderErrMsg = "failed to close provider"
 	couchDBNotFoundErr        = "Not Found:"
+
+	// The tags of the records of a store are kept in a companion database, so that the stored JSON documents
+	// are the values as they were put. A tags document has the ID of its record and a tagsFieldName field
+	// holding the tags as a map of tag name to values. The tags document of a record written with a TTL
+	// also holds its expiry, in unix milliseconds, in an expiryFieldName field.
+	tagsDBSuffix    = "$tags"
+	tagsFieldName   = "tags"
+	expiryFieldName = "expiry"
+	tagsIndexDoc    = "aries_tags"
+
+	// defaultExpiryInterval is how often records written with a TTL are checked for expiry.
+	defaultExpiryInterval = time.Minute
 )
 
 // Option configures the couchdb provider
@@ -49,6 +68,16 @@ func WithDBPrefix(dbPrefix string) Option {
 	}
 }
 
+// WithExpiryInterval sets how often the stores remove their expired records, one minute by default.
+// A zero or negative interval keeps the default.
+func WithExpiryInterval(interval time.Duration) Option {
+	return func(opts *Provider) {
+		if interval > 0 {
+			opts.expiryInterval = interval
+		}
+	}
+}
+
 // NewProvider instantiates Provider
 func NewProvider(hostURL string, opts ...Option) (*Provider, error) {
 	if hostURL == "" {
@@ -60,7 +89,12 @@ func NewProvider(hostURL string, opts ...Option) (*Provider, error) {
 		return nil, err
 	}
 
-	p := &Provider{hostURL: hostURL, couchDBClient: client, dbs: map[string]*CouchDBStore{}}
+	p := &Provider{
+		hostURL:        hostURL,
+		couchDBClient:  client,
+		dbs:            map[string]*CouchDBStore{},
+		expiryInterval: defaultExpiryInterval,
+	}
 
 	for _, opt := range opts {
 		opt(p)
@@ -84,6 +118,40 @@ func (p *Provider) OpenStore(name string) (storage.Store, error) {
 		return cachedStore, nil
 	}
 
+	db, err := p.createDB(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tagsDB, err := p.createDB(name + tagsDBSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &CouchDBStore{
+		db:             db,
+		tagsDB:         tagsDB,
+		indexedFields:  make(map[string]struct{}),
+		expiryInterval: p.expiryInterval,
+	}
+
+	// records written with a TTL before the store was last closed still have to expire
+	expiring, err := store.findIDs(map[string]interface{}{expiryFieldName: map[string]interface{}{"$exists": true}}, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(expiring) > 0 {
+		store.startExpiry()
+	}
+
+	p.dbs[name] = store
+
+	return store, nil
+}
+
+// createDB creates the db of the given name if it doesn't exist yet.
+func (p *Provider) createDB(name string) (*kivik.DB, error) {
 	err := p.couchDBClient.CreateDB(context.Background(), name)
 	if err != nil {
 		if err.Error() != "Precondition Failed: The database could not be created, the file already exists." {
@@ -97,11 +165,7 @@ func (p *Provider) OpenStore(name string) (storage.Store, error) {
 		return nil, db.Err()
 	}
 
-	store := &CouchDBStore{db: db}
-
-	p.dbs[name] = store
-
-	return store, nil
+	return db, nil
 }
 
 // CloseStore closes a previously opened store.
@@ -120,7 +184,7 @@ func (p *Provider) CloseStore(name string) error {
 
 	delete(p.dbs, name)
 
-	return store.db.Close(context.Background())
+	return store.close()
 }
 
 // Close closes the provider.
@@ -129,7 +193,7 @@ func (p *Provider) Close() error {
 	defer p.Unlock()
 
 	for _, store := range p.dbs {
-		err := store.db.Close(context.Background())
+		err := store.close()
 		if err != nil {
 			return fmt.Errorf(failToCloseProviderErrMsg+": %w", err)
 		}
@@ -144,17 +208,63 @@ func (p *Provider) Close() error {
 	return nil
 }
 
-// CouchDBStore represents a CouchDB-backed database.
+// CouchDBStore represents a CouchDB-backed database. Records written with a TTL are skipped once expired
+// and removed every expiry interval.
 type CouchDBStore struct {
-	db *kivik.DB
+	db            *kivik.DB
+	tagsDB        *kivik.DB
+	indexedFields map[string]struct{}
+	lock          sync.Mutex
+	// the expiry loop is started along with the first record written with a TTL
+	expiryInterval time.Duration
+	stopExpiry     chan struct{}
+	expiryDone     chan struct{}
+}
+
+// tagsDocument is the content of a document of the tags db.
+type tagsDocument struct {
+	Tags   map[string][]string `json:"tags"`
+	Expiry int64               `json:"expiry,omitempty"`
+}
+
+// expired tells whether the record of the document expired by the given time.
+func (d *tagsDocument) expired(now time.Time) bool {
+	return d.Expiry != 0 && d.Expiry <= unixMillis(now)
+}
+
+func unixMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
 }
 
-// Put stores the given key-value pair in the store.
-func (c *CouchDBStore) Put(k string, v []byte) error {
+// close stops the expiry loop, waiting for any ongoing expiry to complete, then closes the dbs.
+func (c *CouchDBStore) close() error {
+	c.lock.Lock()
+	stop, done := c.stopExpiry, c.expiryDone
+	c.stopExpiry = nil
+	c.lock.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	if err := c.db.Close(context.Background()); err != nil {
+		return err
+	}
+
+	return c.tagsDB.Close(context.Background())
+}
+
+// Put stores the given key-value pair in the store along with optional tags.
+func (c *CouchDBStore) Put(k string, v []byte, tags ...storage.Tag) error {
 	if k == "" || v == nil {
 		return errors.New("key and value are mandatory")
 	}
 
+	if err := storage.ValidateTags(tags); err != nil {
+		return err
+	}
+
 	var valueToPut []byte
 	if isJSON(v) {
 		valueToPut = v
@@ -162,13 +272,13 @@ func (c *CouchDBStore) Put(k string, v []byte) error {
 		valueToPut = wrapTextAsCouchDBAttachment(v)
 	}
 
-	revID, err := c.getRevID(k)
+	revID, err := getRevID(c.db, k)
 	if err != nil {
 		return err
 	}
 
 	if revID != "" {
-		valueToPut, err = c.addRevID(valueToPut, revID)
+		valueToPut, err = addFields(valueToPut, "", revID)
 		if err != nil {
 			return err
 		}
@@ -179,14 +289,113 @@ func (c *CouchDBStore) Put(k string, v []byte) error {
 		return fmt.Errorf("failed to store data: %w", err)
 	}
 
+	tagsDoc, err := c.tagsDoc(k, tags, 0)
+	if err != nil {
+		return err
+	}
+
+	if tagsDoc == nil {
+		return nil
+	}
+
+	_, err = c.tagsDB.Put(context.Background(), k, tagsDoc)
+	if err != nil {
+		return fmt.Errorf("failed to store tags: %w", err)
+	}
+
 	return nil
 }
 
+// tagsDoc returns the document which replaces the tags of key k in the tags db: it holds the given tags and
+// expiry (if not zero), or it deletes the current tags document when there are neither. It returns nil if there
+// is nothing to write.
+func (c *CouchDBStore) tagsDoc(k string, tags []storage.Tag, expiry int64) (map[string]interface{}, error) {
+	revID, err := getRevID(c.tagsDB, k)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tags) == 0 && expiry == 0 {
+		if revID == "" {
+			return nil, nil
+		}
+
+		return map[string]interface{}{"_id": k, "_rev": revID, "_deleted": true}, nil
+	}
+
+	tagsMap := make(map[string][]string, len(tags))
+
+	for _, tag := range tags {
+		tagsMap[tag.Name] = append(tagsMap[tag.Name], tag.Value)
+	}
+
+	doc := map[string]interface{}{"_id": k, tagsFieldName: tagsMap}
+	if revID != "" {
+		doc["_rev"] = revID
+	}
+
+	if expiry != 0 {
+		doc[expiryFieldName] = expiry
+	}
+
+	return doc, nil
+}
+
 func isJSON(textToCheck []byte) bool {
 	var js map[string]interface{}
 	return json.Unmarshal(textToCheck, &js) == nil
 }
 
+// GetTags fetches the tags stored along with the record of key k.
+func (c *CouchDBStore) GetTags(k string) ([]storage.Tag, error) {
+	if k == "" {
+		return nil, errors.New("key is mandatory")
+	}
+
+	var doc tagsDocument
+
+	err := c.tagsDB.Get(context.Background(), k).ScanDoc(&doc)
+	if err != nil {
+		if !strings.Contains(err.Error(), couchDBNotFoundErr) {
+			return nil, err
+		}
+
+		// no tags document, the record may have no tags
+		revID, revErr := getRevID(c.db, k)
+		if revErr != nil {
+			return nil, revErr
+		}
+
+		if revID == "" {
+			return nil, storage.ErrDataNotFound
+		}
+
+		return nil, nil
+	}
+
+	if doc.expired(time.Now()) {
+		return nil, storage.ErrDataNotFound
+	}
+
+	names := make([]string, 0, len(doc.Tags))
+	for name := range doc.Tags {
+		names = append(names, name)
+	}
+
+	// tags are kept in a map, sort them for a stable result
+	sort.Strings(names)
+
+	var tags []storage.Tag
+
+	for _, name := range names {
+		for _, value := range doc.Tags[name] {
+			tags = append(tags, storage.Tag{Name: name, Value: value})
+		}
+	}
+
+	return tags, nil
+}
+
 // Kivik has a PutAttachment method, but it requires creating a document first and then adding an attachment after.
 // We want to do it all in one step, hence this manual stuff below.
 func wrapTextAsCouchDBAttachment(textToWrap []byte) []byte {
@@ -213,16 +422,48 @@ func (c *CouchDBStore) Get(k string) ([]byte, error) {
 		return nil, err
 	}
 
+	expired, err := c.expired(k)
+	if err != nil {
+		return nil, err
+	}
+
+	if expired {
+		return nil, storage.ErrDataNotFound
+	}
+
 	return c.getStoredValueFromRawDoc(rawDoc, k)
 }
 
-func (c *CouchDBStore) addRevID(valueToPut []byte, revID string) ([]byte, error) {
+// expired tells whether the record of key k expired but wasn't removed yet.
+func (c *CouchDBStore) expired(k string) (bool, error) {
+	var doc tagsDocument
+
+	err := c.tagsDB.Get(context.Background(), k).ScanDoc(&doc)
+	if err != nil {
+		if strings.Contains(err.Error(), couchDBNotFoundErr) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return doc.expired(time.Now()), nil
+}
+
+// addFields adds the document ID (if given) and rev ID (if given) to the value to put.
+func addFields(valueToPut []byte, id, revID string) ([]byte, error) {
 	var m map[string]interface{}
 	if err := json.Unmarshal(valueToPut, &m); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal put value: %w", err)
 	}
 
-	m["_rev"] = revID
+	if id != "" {
+		m["_id"] = id
+	}
+
+	if revID != "" {
+		m["_rev"] = revID
+	}
 
 	newValue, err := json.Marshal(m)
 	if err != nil {
@@ -232,11 +473,11 @@ func (c *CouchDBStore) addRevID(valueToPut []byte, revID string) ([]byte, error)
 	return newValue, nil
 }
 
-// get rev ID
-func (c *CouchDBStore) getRevID(k string) (string, error) {
+// getRevID returns the rev ID of the document k of db, empty if there is no such document.
+func getRevID(db *kivik.DB, k string) (string, error) {
 	rawDoc := make(map[string]interface{})
 
-	row := c.db.Get(context.Background(), k)
+	row := db.Get(context.Background(), k)
 
 	err := row.ScanDoc(&rawDoc)
 	if err != nil {
@@ -256,21 +497,173 @@ func (c *CouchDBStore) Delete(k string) error {
 		return errors.New("key is mandatory")
 	}
 
-	revID, err := c.getRevID(k)
+	for _, db := range []*kivik.DB{c.db, c.tagsDB} {
+		revID, err := getRevID(db, k)
+		if err != nil {
+			return err
+		}
+
+		// nothing to delete
+		if revID == "" {
+			continue
+		}
+
+		_, err = db.Delete(context.TODO(), k, revID)
+		if err != nil {
+			return fmt.Errorf("failed to delete doc: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Batch writes the given operations through the bulk docs API. CouchDB has no transactions so, unlike
+// other providers, the batch is best-effort: documents which fail to be written are reported in the
+// returned error while the others are kept.
+func (c *CouchDBStore) Batch(operations []storage.Operation) error {
+	if err := storage.ValidateOperations(operations); err != nil {
+		return err
+	}
+
+	// a document can be written only once per bulk request, the last operation on a key wins
+	var keys []string
+
+	last := make(map[string]storage.Operation)
+
+	for _, op := range operations {
+		if _, ok := last[op.Key]; !ok {
+			keys = append(keys, op.Key)
+		}
+
+		last[op.Key] = op
+	}
+
+	var docs, tagsDocs []interface{}
+
+	now := time.Now()
+	withTTL := false
+
+	for _, k := range keys {
+		op := last[k]
+
+		doc, err := c.bulkDoc(op)
+		if err != nil {
+			return err
+		}
+
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+
+		var expiry int64
+
+		if op.Value != nil && op.TTL > 0 {
+			expiry = unixMillis(now.Add(op.TTL))
+			withTTL = true
+		}
+
+		tagsDoc, err := c.tagsDoc(k, op.Tags, expiry)
+		if err != nil {
+			return err
+		}
+
+		if tagsDoc != nil {
+			tagsDocs = append(tagsDocs, tagsDoc)
+		}
+	}
+
+	failed, err := bulkWrite(c.db, docs)
 	if err != nil {
 		return err
 	}
 
-	_, err = c.db.Delete(context.TODO(), k, revID)
+	failedTags, err := bulkWrite(c.tagsDB, tagsDocs)
 	if err != nil {
-		return fmt.Errorf("failed to delete doc: %w", err)
+		return err
+	}
+
+	if withTTL {
+		c.lock.Lock()
+		if c.stopExpiry == nil {
+			c.startExpiry()
+		}
+		c.lock.Unlock()
+	}
+
+	if failed = append(failed, failedTags...); len(failed) > 0 {
+		return fmt.Errorf("failed to write batch documents: %s", strings.Join(failed, ", "))
 	}
 
 	return nil
 }
 
+// bulkWrite writes docs to db through bulk docs, it returns the documents which failed to be written.
+func bulkWrite(db *kivik.DB, docs []interface{}) ([]string, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	results, err := db.BulkDocs(context.Background(), docs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write batch: %w", err)
+	}
+
+	var failed []string
+
+	for results.Next() {
+		if updateErr := results.UpdateErr(); updateErr != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", results.ID(), updateErr))
+		}
+	}
+
+	if results.Err() != nil {
+		return nil, fmt.Errorf("failed to read batch results: %w", results.Err())
+	}
+
+	return failed, nil
+}
+
+// bulkDoc returns the document to send through bulk docs for the given operation,
+// or nil if there is nothing to write.
+func (c *CouchDBStore) bulkDoc(op storage.Operation) (interface{}, error) {
+	revID, err := getRevID(c.db, op.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if op.Value == nil {
+		if revID == "" {
+			return nil, nil
+		}
+
+		return map[string]interface{}{"_id": op.Key, "_rev": revID, "_deleted": true}, nil
+	}
+
+	valueToPut := op.Value
+	if !isJSON(valueToPut) {
+		valueToPut = wrapTextAsCouchDBAttachment(valueToPut)
+	}
+
+	doc, err := addFields(valueToPut, op.Key, revID)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(doc), nil
+}
+
 // Iterator returns iterator for the latest snapshot of the underlying db.
 func (c *CouchDBStore) Iterator(startKey, endKey string) storage.StoreIterator {
+	if startKey == "" || endKey == "" {
+		return &couchDBResultsIterator{store: c, err: errors.New("start or end key is mandatory")}
+	}
+
+	// the records which expired but weren't removed yet are skipped
+	expired, err := c.expiredKeys(time.Now())
+	if err != nil {
+		return &couchDBResultsIterator{store: c, err: err}
+	}
+
 	resultRows, err := c.db.AllDocs(context.TODO(), kivik.Options{
 		"startkey":      startKey,
 		"endkey":        strings.ReplaceAll(endKey, storage.EndKeySuffix, kivik.EndKeySuffix),
@@ -278,31 +671,226 @@ func (c *CouchDBStore) Iterator(startKey, endKey string) storage.StoreIterator {
 		"include_docs":  "true",
 	})
 	if err != nil {
-		return &couchDBResultsIterator{store: c, resultRows: &kivik.Rows{},
-			err: fmt.Errorf("failed to query docs: %w", err)}
+		return &couchDBResultsIterator{store: c, err: fmt.Errorf("failed to query docs: %w", err)}
 	}
 
-	return &couchDBResultsIterator{store: c, resultRows: resultRows}
+	skip := make(map[string]struct{}, len(expired))
+	for _, k := range expired {
+		skip[k] = struct{}{}
+	}
+
+	return &couchDBResultsIterator{store: c, resultRows: resultRows, skip: skip}
+}
+
+// Query returns iterator over the records whose tags match the given expression,
+// using a Mango query of the tags db backed by a json index on each queried tag.
+func (c *CouchDBStore) Query(expression string) (storage.StoreIterator, error) {
+	terms, err := storage.ParseQuery(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.ensureIndex(expiryFieldName); err != nil {
+		return nil, err
+	}
+
+	// the records which expired but weren't removed yet are skipped
+	conditions := []interface{}{map[string]interface{}{"$or": []interface{}{
+		map[string]interface{}{expiryFieldName: map[string]interface{}{"$exists": false}},
+		map[string]interface{}{expiryFieldName: map[string]interface{}{"$gt": unixMillis(time.Now())}},
+	}}}
+
+	for _, term := range terms {
+		field := tagField(term.Name)
+
+		if indexErr := c.ensureIndex(field); indexErr != nil {
+			return nil, indexErr
+		}
+
+		if term.Value == "" {
+			conditions = append(conditions, map[string]interface{}{field: map[string]interface{}{"$exists": true}})
+		} else {
+			conditions = append(conditions, map[string]interface{}{field: map[string]interface{}{"$all": []string{term.Value}}})
+		}
+	}
+
+	selector := map[string]interface{}{"$and": conditions}
+
+	// Mango queries return 25 documents unless told otherwise
+	resultRows, err := c.tagsDB.Find(context.Background(), map[string]interface{}{
+		"selector": selector,
+		"limit":    math.MaxInt32,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query docs: %w", err)
+	}
+
+	return &couchDBResultsIterator{store: c, resultRows: resultRows, tagsQuery: true}, nil
+}
+
+// ensureIndex creates the json index of the given field of the tags db the first time the field is queried.
+func (c *CouchDBStore) ensureIndex(field string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.indexedFields[field]; ok {
+		return nil
+	}
+
+	err := c.tagsDB.CreateIndex(context.Background(), tagsIndexDoc, field,
+		map[string]interface{}{"fields": []string{field}})
+	if err != nil {
+		return fmt.Errorf("failed to create tag index: %w", err)
+	}
+
+	c.indexedFields[field] = struct{}{}
+
+	return nil
+}
+
+// expiredKeys returns the keys of the records which expired by the given time.
+func (c *CouchDBStore) expiredKeys(now time.Time) ([]string, error) {
+	if err := c.ensureIndex(expiryFieldName); err != nil {
+		return nil, err
+	}
+
+	return c.findIDs(map[string]interface{}{expiryFieldName: map[string]interface{}{"$lte": unixMillis(now)}},
+		math.MaxInt32)
+}
+
+// findIDs returns the IDs of the tags documents matching selector, at most limit of them.
+func (c *CouchDBStore) findIDs(selector map[string]interface{}, limit int) ([]string, error) {
+	rows, err := c.tagsDB.Find(context.Background(), map[string]interface{}{
+		"selector": selector,
+		"fields":   []string{"_id"},
+		"limit":    limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expiries: %w", err)
+	}
+
+	defer func() {
+		// the rows are fully read already
+		_ = rows.Close() // nolint: errcheck
+	}()
+
+	var ids []string
+
+	for rows.Next() {
+		var doc struct {
+			ID string `json:"_id"`
+		}
+
+		if err = rows.ScanDoc(&doc); err != nil {
+			return nil, fmt.Errorf("failed to read expiries: %w", err)
+		}
+
+		ids = append(ids, doc.ID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read expiries: %w", err)
+	}
+
+	return ids, nil
+}
+
+// startExpiry starts the expiry loop, the caller either holds the store lock or has the store to itself.
+func (c *CouchDBStore) startExpiry() {
+	c.stopExpiry = make(chan struct{})
+	c.expiryDone = make(chan struct{})
+
+	go c.expireLoop(c.stopExpiry, c.expiryDone)
+}
+
+// expireLoop removes the expired records every expiry interval, until stop is closed.
+func (c *CouchDBStore) expireLoop(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(c.expiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			if err := c.expire(now); err != nil {
+				logger.Warnf("failed to remove expired records: %s", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// expire removes the records which expired by the given time, along with their tags and expiry.
+func (c *CouchDBStore) expire(now time.Time) error {
+	keys, err := c.expiredKeys(now)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		// the record may have been put again since the expired keys were read
+		expired, err := c.expired(k)
+		if err != nil {
+			return err
+		}
+
+		if !expired {
+			continue
+		}
+
+		if err = c.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tagField returns the Mango field path of the given tag, escaping dots in the tag name.
+func tagField(name string) string {
+	return tagsFieldName + "." + strings.ReplaceAll(name, ".", "\\.")
 }
 
 type couchDBResultsIterator struct {
-	store      *CouchDBStore
+	store *CouchDBStore
+	// nil when the query failed
 	resultRows *kivik.Rows
 	err        error
+	// Mango query results are the tags documents: the key has to be read from the document ID
+	// and the value from the record
+	tagsQuery bool
+	// keys of the expired records which weren't removed yet
+	skip map[string]struct{}
 }
 
 func (i *couchDBResultsIterator) Next() bool {
-	return i.resultRows.Next()
+	if i.resultRows == nil {
+		return false
+	}
+
+	for i.resultRows.Next() {
+		if _, ok := i.skip[string(i.Key())]; !ok {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (i *couchDBResultsIterator) Release() {
+	if i.resultRows == nil {
+		return
+	}
+
 	if err := i.resultRows.Close(); err != nil {
 		i.err = err
 	}
 }
 
 func (i *couchDBResultsIterator) Error() error {
-	if i.err != nil {
+	if i.err != nil || i.resultRows == nil {
 		return i.err
 	}
 
@@ -311,6 +899,10 @@ func (i *couchDBResultsIterator) Error() error {
 
 // Key returns the key of the current key-value pair.
 func (i *couchDBResultsIterator) Key() []byte {
+	if i.tagsQuery {
+		return i.docID()
+	}
+
 	key := i.resultRows.Key()
 	if key != "" {
 		// The returned key is a raw JSON string. It needs to be unescaped:
@@ -328,6 +920,17 @@ func (i *couchDBResultsIterator) Key() []byte {
 
 // Value returns the value of the current key-value pair.
 func (i *couchDBResultsIterator) Value() []byte {
+	if i.tagsQuery {
+		v, err := i.store.Get(string(i.Key()))
+		if err != nil {
+			i.err = err
+
+			return nil
+		}
+
+		return v
+	}
+
 	rawDoc := make(map[string]interface{})
 
 	err := i.resultRows.ScanDoc(&rawDoc)
@@ -349,6 +952,19 @@ func (i *couchDBResultsIterator) Value() []byte {
 	return v
 }
 
+func (i *couchDBResultsIterator) docID() []byte {
+	var doc struct {
+		ID string `json:"_id"`
+	}
+
+	if err := i.resultRows.ScanDoc(&doc); err != nil {
+		i.err = err
+		return nil
+	}
+
+	return []byte(doc.ID)
+}
+
 func (c *CouchDBStore) getStoredValueFromRawDoc(rawDoc map[string]interface{}, k string) ([]byte, error) {
 	_, containsAttachment := rawDoc["_attachments"]
 	if containsAttachment {
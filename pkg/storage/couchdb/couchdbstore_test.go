@@ -10,6 +10,7 @@ package couchdbstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -20,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	storagetest "github.com/hyperledger/aries-framework-go/pkg/storage/test"
 )
 
 const (
@@ -342,3 +344,213 @@ func TestCouchDBStoreDelete(t *testing.T) {
 	require.EqualError(t, err, storage.ErrDataNotFound.Error())
 	require.Empty(t, doc)
 }
+
+func TestCouchDBStoreQuery(t *testing.T) {
+	prov, err := NewProvider(couchDBURL)
+	require.NoError(t, err)
+
+	store, err := prov.OpenStore("querytest")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "completed"}))
+	require.NoError(t, store.Put("key2", []byte(`{"field":"value2"}`),
+		storage.Tag{Name: "state", Value: "invited"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key3", []byte("value3"),
+		storage.Tag{Name: "state", Value: "completed"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key4", []byte("value4")))
+
+	t.Run("Test couchdb store query", func(t *testing.T) {
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key1": "value1", "key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("type")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": `{"field":"value2"}`, "key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("state:completed && type:a")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key3": "value3"}, readAll(t, itr))
+	})
+
+	t.Run("Test couchdb store query - tags are not part of the value", func(t *testing.T) {
+		doc, err := store.Get("key2")
+		require.NoError(t, err)
+		require.Equal(t, `{"field":"value2"}`, string(doc))
+	})
+
+	t.Run("Test couchdb store query - tags replaced and deleted", func(t *testing.T) {
+		require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "abandoned"}))
+		require.NoError(t, store.Delete("key3"))
+
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Empty(t, readAll(t, itr))
+
+		itr, err = store.Query("state:abandoned")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key1": "value1"}, readAll(t, itr))
+	})
+
+	t.Run("Test couchdb store query - invalid expression", func(t *testing.T) {
+		_, err := store.Query("")
+		require.Error(t, err)
+	})
+}
+
+func readAll(t *testing.T, itr storage.StoreIterator) map[string]string {
+	defer itr.Release()
+
+	values := make(map[string]string)
+
+	for itr.Next() {
+		values[string(itr.Key())] = string(itr.Value())
+	}
+
+	require.NoError(t, itr.Error())
+
+	return values
+}
+
+func TestCouchDBStoreBatch(t *testing.T) {
+	prov, err := NewProvider(couchDBURL)
+	require.NoError(t, err)
+
+	store, err := prov.OpenStore("batchtest")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1")))
+
+	t.Run("Test couchdb store batch", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{
+			{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state", Value: "completed"}}},
+			{Key: "key1"},
+			{Key: "key3", Value: []byte("value3")},
+			{Key: "key3", Value: []byte("value3.1")},
+			{Key: "missing"},
+		})
+		require.NoError(t, err)
+
+		_, err = store.Get("key1")
+		require.EqualError(t, err, storage.ErrDataNotFound.Error())
+
+		v, err := store.Get("key3")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value3.1"), v)
+
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, itr))
+	})
+
+	t.Run("Test couchdb store batch - invalid operation", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{{Value: []byte("value")}})
+		require.EqualError(t, err, "key is mandatory")
+	})
+}
+
+func TestWithExpiryInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		prov, err := NewProvider(couchDBURL, WithExpiryInterval(interval))
+		require.NoError(t, err)
+		require.Equal(t, defaultExpiryInterval, prov.expiryInterval)
+	}
+
+	prov, err := NewProvider(couchDBURL, WithExpiryInterval(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, time.Second, prov.expiryInterval)
+}
+
+func TestCouchDBStoreTTL(t *testing.T) {
+	prov, err := NewProvider(couchDBURL, WithExpiryInterval(50*time.Millisecond))
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, prov.Close()) }()
+
+	store, err := prov.OpenStore("ttltest")
+	require.NoError(t, err)
+
+	t.Run("Test couchdb store ttl - expired records are removed along with their tags", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key1", Value: []byte("value1"), Tags: []storage.Tag{{Name: "state"}}, TTL: 10 * time.Millisecond},
+			{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Hour},
+			{Key: "key3", Value: []byte("value3"), Tags: []storage.Tag{{Name: "state"}}},
+		}))
+
+		// expired records aren't read before they're removed, so wait for their tags document to be removed
+		require.Eventually(t, func() bool {
+			revID, err := getRevID(store.(*CouchDBStore).tagsDB, "key1")
+			require.NoError(t, err)
+
+			return revID == ""
+		}, 5*time.Second, 50*time.Millisecond)
+
+		revID, err := getRevID(store.(*CouchDBStore).db, "key1")
+		require.NoError(t, err)
+		require.Empty(t, revID)
+
+		itr, err := store.Query("state")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2", "key3": "value3"}, readAll(t, itr))
+	})
+
+	t.Run("Test couchdb store ttl - putting a record again clears its ttl", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{{Key: "key4", Value: []byte("value4"), TTL: time.Millisecond}}))
+		require.NoError(t, store.Put("key4", []byte("value4")))
+
+		time.Sleep(100 * time.Millisecond)
+
+		v, err := store.Get("key4")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value4"), v)
+	})
+
+	t.Run("Test couchdb store ttl - expired records aren't read before they're removed", func(t *testing.T) {
+		prov, err := NewProvider(couchDBURL, WithExpiryInterval(time.Hour))
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, prov.Close()) }()
+
+		store, err := prov.OpenStore("ttlreadtest")
+		require.NoError(t, err)
+
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key6", Value: []byte("value6"), Tags: []storage.Tag{{Name: "state"}}, TTL: 10 * time.Millisecond},
+			{Key: "key7", Value: []byte("value7"), Tags: []storage.Tag{{Name: "state"}}},
+		}))
+
+		time.Sleep(50 * time.Millisecond)
+
+		_, err = store.Get("key6")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = store.GetTags("key6")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		itr, err := store.Query("state")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key7": "value7"}, readAll(t, itr))
+		require.Equal(t, map[string]string{"key7": "value7"}, readAll(t, store.Iterator("key", "key"+storage.EndKeySuffix)))
+	})
+
+	t.Run("Test couchdb store ttl - records expire after the store is reopened", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{{Key: "key5", Value: []byte("value5"), TTL: time.Second}}))
+		require.NoError(t, prov.CloseStore("ttltest"))
+
+		store, err = prov.OpenStore("ttltest")
+		require.NoError(t, err)
+		require.NotNil(t, store.(*CouchDBStore).stopExpiry)
+
+		require.Eventually(t, func() bool {
+			_, err := store.Get("key5")
+			return errors.Is(err, storage.ErrDataNotFound)
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
+
+func TestCouchDBStoreConformance(t *testing.T) {
+	prov, err := NewProvider(couchDBURL)
+	require.NoError(t, err)
+
+	storagetest.TestAll(t, prov)
+}
@@ -0,0 +1,583 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package encrypted provides a storage.Provider decorator which encrypts the records of an underlying provider
+// at rest. Values are sealed with AES-256-GCM using a data key which is generated on first use, protected by
+// the given secretlock.Service and saved (wrapped) in the underlying provider itself.
+//
+// Tag values are always stored as deterministic HMAC-SHA256 digests: the underlying provider can match them in
+// queries but can't read them, it only sees which records share the same tag value. Keys and tag names are stored
+// in plaintext by default so that range iteration behaves exactly as with the underlying provider. WithHashedKeys
+// replaces them with HMAC-SHA256 digests as well: Get, Put, Delete, Batch and Query are unaffected, while Iterator
+// falls back to scanning (and decrypting) the whole store.
+//
+// To use it with the Aries framework, wrap the provider passed to aries.WithStoreProvider:
+//
+//		prov, err := encrypted.NewProvider(leveldb.NewProvider(dbPath), secLock)
+//		...
+//		framework, err := aries.New(aries.WithStoreProvider(prov), aries.WithSecretLock(secLock))
+package encrypted
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/tink/go/subtle/random"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// KeyStoreName is the name of the store, in the underlying provider, holding the wrapped data key.
+	KeyStoreName = "encryptedstorekey"
+
+	dataKeyID  = "datakey"
+	dataKeyLen = 32
+
+	encryptionKeyInfo = "aries-framework-go/storage/encrypted/value"
+	hashKeyInfo       = "aries-framework-go/storage/encrypted/key"
+
+	// hashed keys are hex encoded: scanning the key range of each hex digit covers all of them, whether
+	// the underlying provider treats ranges as prefixes or not.
+	hexDigits = "0123456789abcdef"
+)
+
+// dataKeyLock serializes the creation of data keys, so that providers created concurrently on the same underlying
+// provider don't each save their own data key. Processes sharing an underlying provider must not be started
+// concurrently against an empty store.
+var dataKeyLock sync.Mutex //nolint:gochecknoglobals
+
+// Option configures the encrypted storage provider.
+type Option func(p *Provider)
+
+// WithKeyURI sets the keyURI passed to the secretlock.Service when wrapping and unwrapping the data key.
+func WithKeyURI(keyURI string) Option {
+	return func(p *Provider) {
+		p.keyURI = keyURI
+	}
+}
+
+// WithHashedKeys makes the provider store keys and tag names as deterministic HMAC digests rather than in plaintext.
+func WithHashedKeys() Option {
+	return func(p *Provider) {
+		p.hashKeys = true
+	}
+}
+
+// Provider is a storage.Provider which encrypts the records of another storage.Provider.
+type Provider struct {
+	provider storage.Provider
+	secLock  secretlock.Service
+	keyURI   string
+	hashKeys bool
+	aead     cipher.AEAD
+	hashKey  []byte
+}
+
+// NewProvider creates a Provider encrypting the records of provider with a data key protected by secLock.
+func NewProvider(provider storage.Provider, secLock secretlock.Service, opts ...Option) (*Provider, error) {
+	if provider == nil {
+		return nil, errors.New("storage provider is mandatory")
+	}
+
+	if secLock == nil {
+		return nil, errors.New("secret lock service is mandatory")
+	}
+
+	p := &Provider{provider: provider, secLock: secLock}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	dataKey, err := p.dataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	encryptionKey, err := deriveKey(dataKey, encryptionKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("create data key cipher: %w", err)
+	}
+
+	p.aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create data key cipher: %w", err)
+	}
+
+	p.hashKey, err = deriveKey(dataKey, hashKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// dataKey reads and unwraps the data key from the underlying provider, creating it on first use.
+func (p *Provider) dataKey() ([]byte, error) {
+	dataKeyLock.Lock()
+	defer dataKeyLock.Unlock()
+
+	store, err := p.provider.OpenStore(KeyStoreName)
+	if err != nil {
+		return nil, fmt.Errorf("open data key store: %w", err)
+	}
+
+	wrapped, err := store.Get(dataKeyID)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return p.newDataKey(store)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get data key: %w", err)
+	}
+
+	resp, err := p.secLock.Decrypt(p.keyURI, &secretlock.DecryptRequest{Ciphertext: string(wrapped)})
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+
+	dataKey, err := base64.RawURLEncoding.DecodeString(resp.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("decode data key: %w", err)
+	}
+
+	return dataKey, nil
+}
+
+func (p *Provider) newDataKey(store storage.Store) ([]byte, error) {
+	dataKey := random.GetRandomBytes(dataKeyLen)
+
+	resp, err := p.secLock.Encrypt(p.keyURI, &secretlock.EncryptRequest{
+		Plaintext: base64.RawURLEncoding.EncodeToString(dataKey),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key: %w", err)
+	}
+
+	err = store.Put(dataKeyID, []byte(resp.Ciphertext))
+	if err != nil {
+		return nil, fmt.Errorf("save data key: %w", err)
+	}
+
+	return dataKey, nil
+}
+
+func deriveKey(dataKey []byte, info string) ([]byte, error) {
+	key := make([]byte, dataKeyLen)
+
+	_, err := io.ReadFull(hkdf.New(sha256.New, dataKey, nil, []byte(info)), key)
+	if err != nil {
+		return nil, fmt.Errorf("derive key: %w", err)
+	}
+
+	return key, nil
+}
+
+// OpenStore opens the store of given name space in the underlying provider and returns an encrypting handle to it.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	store, err := p.provider.OpenStore(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedStore{store: store, provider: p}, nil
+}
+
+// CloseStore closes store of given name space in the underlying provider.
+func (p *Provider) CloseStore(name string) error {
+	return p.provider.CloseStore(name)
+}
+
+// Close closes all stores of the underlying provider.
+func (p *Provider) Close() error {
+	return p.provider.Close()
+}
+
+// RegisterChangeEvent registers a channel receiving the changes of every store of the underlying provider.
+// Changes are only reported with plaintext keys, storage.ErrChangeFeedNotSupported is returned when keys
+// are hashed or when the underlying provider doesn't support change feed.
+func (p *Provider) RegisterChangeEvent(ch chan<- storage.Change) error {
+	feed, ok := p.provider.(storage.ChangeFeed)
+	if !ok || p.hashKeys {
+		return storage.ErrChangeFeedNotSupported
+	}
+
+	return feed.RegisterChangeEvent(ch)
+}
+
+// UnregisterChangeEvent stops sending changes to a channel.
+func (p *Provider) UnregisterChangeEvent(ch chan<- storage.Change) error {
+	feed, ok := p.provider.(storage.ChangeFeed)
+	if !ok || p.hashKeys {
+		return storage.ErrChangeFeedNotSupported
+	}
+
+	return feed.UnregisterChangeEvent(ch)
+}
+
+type encryptedStore struct {
+	store    storage.Store
+	provider *Provider
+}
+
+// record is the plaintext sealed into the underlying store. Key is only set when keys are hashed, so that the
+// original key can be given back. Tags are kept since their values are hashed in the underlying store.
+type record struct {
+	Key   string        `json:"key,omitempty"`
+	Value []byte        `json:"value"`
+	Tags  []storage.Tag `json:"tags,omitempty"`
+}
+
+// Put encrypts and stores the record along with optional tags.
+func (s *encryptedStore) Put(k string, v []byte, tags ...storage.Tag) error {
+	if k == "" || v == nil {
+		return errors.New("key and value are mandatory")
+	}
+
+	return s.Batch([]storage.Operation{{Key: k, Value: v, Tags: tags}})
+}
+
+// Get fetches and decrypts the record based on key.
+func (s *encryptedStore) Get(k string) ([]byte, error) {
+	storedKey := s.storedKey(k)
+
+	sealed, err := s.store.Get(storedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := s.open(storedKey, sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	return rec.Value, nil
+}
+
+// GetTags fetches the tags stored along with the record of key k.
+func (s *encryptedStore) GetTags(k string) ([]storage.Tag, error) {
+	storedKey := s.storedKey(k)
+
+	sealed, err := s.store.Get(storedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := s.open(storedKey, sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	return rec.Tags, nil
+}
+
+// Iterator returns an iterator decrypting the records of the underlying store.
+// With hashed keys, the whole store is scanned and records are filtered and sorted by their original key.
+func (s *encryptedStore) Iterator(start, limit string) storage.StoreIterator {
+	if !s.provider.hashKeys {
+		return &iterator{store: s, itr: s.store.Iterator(start, limit)}
+	}
+
+	if start == "" || limit == "" {
+		return &sliceIterator{err: errors.New("start or limit key is mandatory")}
+	}
+
+	limit = strings.ReplaceAll(limit, storage.EndKeySuffix, "~")
+
+	var records []*record
+
+	for _, digit := range hexDigits {
+		recs, err := s.scan(string(digit), func(rec *record) bool { return rec.Key >= start && rec.Key < limit })
+		if err != nil {
+			return &sliceIterator{err: err}
+		}
+
+		records = append(records, recs...)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Key < records[j].Key })
+
+	return &sliceIterator{records: records}
+}
+
+// scan reads and decrypts the records stored under the given key prefix, keeping those matching filter.
+func (s *encryptedStore) scan(prefix string, filter func(rec *record) bool) ([]*record, error) {
+	itr := s.store.Iterator(prefix, prefix+storage.EndKeySuffix)
+	defer itr.Release()
+
+	var records []*record
+
+	for itr.Next() {
+		rec, err := s.open(string(itr.Key()), itr.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		if filter(rec) {
+			records = append(records, rec)
+		}
+	}
+
+	return records, itr.Error()
+}
+
+// Delete will delete record with k key.
+func (s *encryptedStore) Delete(k string) error {
+	if k == "" {
+		return errors.New("key is mandatory")
+	}
+
+	return s.Batch([]storage.Operation{{Key: k}})
+}
+
+// Batch encrypts the values of operations and performs them as a single write of the underlying store,
+// which takes care of their TTL.
+func (s *encryptedStore) Batch(operations []storage.Operation) error {
+	if err := storage.ValidateOperations(operations); err != nil {
+		return err
+	}
+
+	sealedOps := make([]storage.Operation, len(operations))
+
+	for i, op := range operations {
+		sealedOps[i] = storage.Operation{Key: s.storedKey(op.Key), Tags: s.storedTags(op.Tags), TTL: op.TTL}
+
+		if op.Value == nil {
+			continue
+		}
+
+		sealed, err := s.seal(op.Key, op.Value, op.Tags)
+		if err != nil {
+			return err
+		}
+
+		sealedOps[i].Value = sealed
+	}
+
+	return s.store.Batch(sealedOps)
+}
+
+// Query returns an iterator decrypting the records whose tags match the given expression.
+func (s *encryptedStore) Query(expression string) (storage.StoreIterator, error) {
+	terms, err := storage.ParseQuery(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	storedTerms := make([]string, len(terms))
+
+	for i, term := range terms {
+		storedTerms[i] = s.storedTagName(term.Name)
+		if term.Value != "" {
+			storedTerms[i] += storage.TagNameValueSeparator + s.hash(term.Name, term.Value)
+		}
+	}
+
+	itr, err := s.store.Query(strings.Join(storedTerms, storage.TagQueryOperatorAnd))
+	if err != nil {
+		return nil, err
+	}
+
+	return &iterator{store: s, itr: itr}, nil
+}
+
+func (s *encryptedStore) storedKey(k string) string {
+	if !s.provider.hashKeys {
+		return k
+	}
+
+	return s.hash(k)
+}
+
+func (s *encryptedStore) storedTags(tags []storage.Tag) []storage.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	storedTags := make([]storage.Tag, len(tags))
+
+	for i, tag := range tags {
+		storedTags[i] = storage.Tag{Name: s.storedTagName(tag.Name), Value: s.hash(tag.Name, tag.Value)}
+	}
+
+	return storedTags
+}
+
+func (s *encryptedStore) storedTagName(name string) string {
+	if !s.provider.hashKeys {
+		return name
+	}
+
+	return s.hash(name)
+}
+
+// hash returns the hex encoded HMAC of parts, each part being length prefixed so that parts can't be shifted.
+func (s *encryptedStore) hash(parts ...string) string {
+	mac := hmac.New(sha256.New, s.provider.hashKey)
+
+	for _, part := range parts {
+		fmt.Fprintf(mac, "%d:%s", len(part), part)
+	}
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// seal encrypts the value v of key k along with its tags, authenticating it against
+// the key it is stored under so that records can't be swapped.
+func (s *encryptedStore) seal(k string, v []byte, tags []storage.Tag) ([]byte, error) {
+	rec := record{Value: v, Tags: tags}
+	if s.provider.hashKeys {
+		rec.Key = k
+	}
+
+	pt, err := json.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("marshal record: %w", err)
+	}
+
+	aead := s.provider.aead
+	nonce := random.GetRandomBytes(uint32(aead.NonceSize()))
+
+	return aead.Seal(nonce, nonce, pt, []byte(s.storedKey(k))), nil
+}
+
+// open decrypts a record stored under storedKey in the underlying store.
+func (s *encryptedStore) open(storedKey string, sealed []byte) (*record, error) {
+	aead := s.provider.aead
+	nonceSize := aead.NonceSize()
+
+	if len(sealed) <= nonceSize {
+		return nil, errors.New("decrypt record: invalid ciphertext")
+	}
+
+	pt, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(storedKey))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt record: %w", err)
+	}
+
+	rec := &record{}
+
+	err = json.Unmarshal(pt, rec)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal record: %w", err)
+	}
+
+	if !s.provider.hashKeys {
+		rec.Key = storedKey
+	}
+
+	return rec, nil
+}
+
+// iterator decrypts the records of an underlying store iterator as it moves.
+type iterator struct {
+	store *encryptedStore
+	itr   storage.StoreIterator
+	rec   *record
+	err   error
+}
+
+func (i *iterator) Next() bool {
+	i.rec = nil
+
+	if i.err != nil || !i.itr.Next() {
+		return false
+	}
+
+	i.rec, i.err = i.store.open(string(i.itr.Key()), i.itr.Value())
+
+	return i.err == nil
+}
+
+func (i *iterator) Release() {
+	i.itr.Release()
+}
+
+func (i *iterator) Error() error {
+	if i.err != nil {
+		return i.err
+	}
+
+	return i.itr.Error()
+}
+
+func (i *iterator) Key() []byte {
+	if i.rec == nil {
+		return nil
+	}
+
+	return []byte(i.rec.Key)
+}
+
+func (i *iterator) Value() []byte {
+	if i.rec == nil {
+		return nil
+	}
+
+	return i.rec.Value
+}
+
+// sliceIterator iterates over records which have already been read and decrypted.
+type sliceIterator struct {
+	records []*record
+	index   int
+	err     error
+}
+
+func (i *sliceIterator) Next() bool {
+	if i.index >= len(i.records) {
+		i.records = nil
+
+		return false
+	}
+
+	i.index++
+
+	return true
+}
+
+func (i *sliceIterator) Release() {
+	i.records = nil
+}
+
+func (i *sliceIterator) Error() error {
+	return i.err
+}
+
+func (i *sliceIterator) Key() []byte {
+	if i.index == 0 || i.index > len(i.records) {
+		return nil
+	}
+
+	return []byte(i.records[i.index-1].Key)
+}
+
+func (i *sliceIterator) Value() []byte {
+	if i.index == 0 || i.index > len(i.records) {
+		return nil
+	}
+
+	return i.records[i.index-1].Value
+}
@@ -0,0 +1,344 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encrypted
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/tink/go/subtle/random"
+	"github.com/stretchr/testify/require"
+
+	mocksecretlock "github.com/hyperledger/aries-framework-go/pkg/mock/secretlock"
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock"
+	"github.com/hyperledger/aries-framework-go/pkg/secretlock/local"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	storagetest "github.com/hyperledger/aries-framework-go/pkg/storage/test"
+)
+
+func TestNewProvider(t *testing.T) {
+	secLock := newSecretLock(t)
+
+	t.Run("test new provider - data key is created then reused", func(t *testing.T) {
+		memProvider := mem.NewProvider()
+
+		prov, err := NewProvider(memProvider, secLock)
+		require.NoError(t, err)
+
+		keyStore, err := memProvider.OpenStore(KeyStoreName)
+		require.NoError(t, err)
+
+		wrapped, err := keyStore.Get(dataKeyID)
+		require.NoError(t, err)
+		require.NotEmpty(t, wrapped)
+
+		store, err := prov.OpenStore("test")
+		require.NoError(t, err)
+		require.NoError(t, store.Put("key1", []byte("value1")))
+
+		prov, err = NewProvider(memProvider, secLock)
+		require.NoError(t, err)
+
+		store, err = prov.OpenStore("test")
+		require.NoError(t, err)
+
+		v, err := store.Get("key1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value1"), v)
+	})
+
+	t.Run("test new provider - concurrent first use creates a single data key", func(t *testing.T) {
+		memProvider := mem.NewProvider()
+
+		const count = 10
+
+		providers := make(chan *Provider, count)
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				prov, err := NewProvider(memProvider, &slowSecretLock{Service: secLock})
+				require.NoError(t, err)
+
+				providers <- prov
+			}()
+		}
+
+		wg.Wait()
+		close(providers)
+
+		prov := <-providers
+
+		store, err := prov.OpenStore("test")
+		require.NoError(t, err)
+		require.NoError(t, store.Put("key1", []byte("value1")))
+
+		for other := range providers {
+			store, err = other.OpenStore("test")
+			require.NoError(t, err)
+
+			v, err := store.Get("key1")
+			require.NoError(t, err)
+			require.Equal(t, []byte("value1"), v)
+		}
+	})
+
+	t.Run("test new provider - mandatory arguments", func(t *testing.T) {
+		_, err := NewProvider(nil, secLock)
+		require.EqualError(t, err, "storage provider is mandatory")
+
+		_, err = NewProvider(mem.NewProvider(), nil)
+		require.EqualError(t, err, "secret lock service is mandatory")
+	})
+
+	t.Run("test new provider - secret lock errors", func(t *testing.T) {
+		_, err := NewProvider(mem.NewProvider(), &mocksecretlock.MockSecretLock{ErrEncrypt: errors.New("encrypt")})
+		require.EqualError(t, err, "wrap data key: encrypt")
+
+		memProvider := mem.NewProvider()
+
+		_, err = NewProvider(memProvider, secLock)
+		require.NoError(t, err)
+
+		_, err = NewProvider(memProvider, &mocksecretlock.MockSecretLock{ErrDecrypt: errors.New("decrypt")})
+		require.EqualError(t, err, "unwrap data key: decrypt")
+
+		_, err = NewProvider(memProvider, &mocksecretlock.MockSecretLock{ValDecrypt: "%"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "decode data key")
+	})
+
+	t.Run("test new provider - storage errors", func(t *testing.T) {
+		_, err := NewProvider(&mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New("open")}, secLock)
+		require.EqualError(t, err, "open data key store: open")
+
+		_, err = NewProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: map[string][]byte{}, ErrGet: errors.New("get"),
+		}}, secLock)
+		require.EqualError(t, err, "get data key: get")
+
+		_, err = NewProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+			Store: map[string][]byte{}, ErrPut: errors.New("put"),
+		}}, secLock)
+		require.EqualError(t, err, "save data key: put")
+	})
+}
+
+func TestEncryptedStore(t *testing.T) {
+	for _, hashKeys := range []bool{false, true} {
+		var opts []Option
+		if hashKeys {
+			opts = append(opts, WithHashedKeys())
+		}
+
+		memProvider := mem.NewProvider()
+
+		prov, err := NewProvider(memProvider, newSecretLock(t), opts...)
+		require.NoError(t, err)
+
+		store, err := prov.OpenStore("test")
+		require.NoError(t, err)
+
+		underlying, err := memProvider.OpenStore("test")
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put("abc1", []byte("value1"), storage.Tag{Name: "state", Value: "completed"}))
+		require.NoError(t, store.Put("abc2", []byte("value2"), storage.Tag{Name: "state", Value: "invited"}))
+		require.NoError(t, store.Put("xyz1", []byte("value3"), storage.Tag{Name: "state", Value: "completed"}))
+
+		t.Run("test encrypted store - values are encrypted", func(t *testing.T) {
+			itr := underlying.Iterator(" ", "~")
+			defer itr.Release()
+
+			count := 0
+
+			for itr.Next() {
+				count++
+
+				require.False(t, bytes.Contains(itr.Value(), []byte("value")))
+				require.Equal(t, hashKeys, !bytes.HasPrefix(itr.Key(), []byte("abc")) &&
+					!bytes.HasPrefix(itr.Key(), []byte("xyz")))
+
+				tags, err := underlying.GetTags(string(itr.Key()))
+				require.NoError(t, err)
+				require.Len(t, tags, 1)
+				require.Equal(t, hashKeys, tags[0].Name != "state")
+				require.NotContains(t, []string{"completed", "invited"}, tags[0].Value)
+			}
+
+			require.Equal(t, 3, count)
+
+			tags, err := store.GetTags("abc2")
+			require.NoError(t, err)
+			require.Equal(t, []storage.Tag{{Name: "state", Value: "invited"}}, tags)
+		})
+
+		t.Run("test encrypted store - get", func(t *testing.T) {
+			v, err := store.Get("abc1")
+			require.NoError(t, err)
+			require.Equal(t, []byte("value1"), v)
+
+			_, err = store.Get("missing")
+			require.True(t, errors.Is(err, storage.ErrDataNotFound))
+		})
+
+		t.Run("test encrypted store - iterator", func(t *testing.T) {
+			itr := store.Iterator("abc", "abc"+storage.EndKeySuffix)
+			require.Equal(t, map[string]string{"abc1": "value1", "abc2": "value2"}, readAll(t, itr))
+
+			itr = store.Iterator("xyz", "xyz"+storage.EndKeySuffix)
+			require.Equal(t, map[string]string{"xyz1": "value3"}, readAll(t, itr))
+		})
+
+		t.Run("test encrypted store - query", func(t *testing.T) {
+			itr, err := store.Query("state:completed")
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"abc1": "value1", "xyz1": "value3"}, readAll(t, itr))
+
+			itr, err = store.Query("state")
+			require.NoError(t, err)
+			require.Len(t, readAll(t, itr), 3)
+
+			_, err = store.Query("")
+			require.Error(t, err)
+		})
+
+		t.Run("test encrypted store - batch and delete", func(t *testing.T) {
+			require.NoError(t, store.Batch([]storage.Operation{
+				{Key: "abc3", Value: []byte("value4")},
+				{Key: "abc1"},
+			}))
+
+			_, err := store.Get("abc1")
+			require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+			require.NoError(t, store.Delete("abc3"))
+
+			_, err = store.Get("abc3")
+			require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+			require.EqualError(t, store.Delete(""), "key is mandatory")
+			require.EqualError(t, store.Put("", []byte("v")), "key and value are mandatory")
+			require.EqualError(t, store.Batch([]storage.Operation{{Value: []byte("v")}}), "key is mandatory")
+		})
+
+		t.Run("test encrypted store - records can't be swapped", func(t *testing.T) {
+			require.NoError(t, store.Put("k1", []byte("v1")))
+			require.NoError(t, store.Put("k2", []byte("v2")))
+
+			k1, k2 := "k1", "k2"
+			if hashKeys {
+				k1, k2 = store.(*encryptedStore).hash(k1), store.(*encryptedStore).hash(k2)
+			}
+
+			sealed, err := underlying.Get(k1)
+			require.NoError(t, err)
+			require.NoError(t, underlying.Put(k2, sealed))
+
+			_, err = store.Get("k2")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "decrypt record")
+		})
+	}
+}
+
+func TestEncryptedStore_Errors(t *testing.T) {
+	prov, err := NewProvider(&mockstorage.MockStoreProvider{Store: &mockstorage.MockStore{
+		Store:    map[string][]byte{},
+		ErrQuery: errors.New("query"),
+	}}, newSecretLock(t))
+	require.NoError(t, err)
+
+	store, err := prov.OpenStore("test")
+	require.NoError(t, err)
+
+	_, err = store.Query("state")
+	require.EqualError(t, err, "query")
+
+	require.NoError(t, store.(*encryptedStore).store.Put("key1", []byte("short")))
+
+	_, err = store.Get("key1")
+	require.EqualError(t, err, "decrypt record: invalid ciphertext")
+
+	prov.provider = &mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New("open")}
+
+	_, err = prov.OpenStore("test")
+	require.EqualError(t, err, "open")
+}
+
+func TestEncryptedStoreTTL(t *testing.T) {
+	prov, err := NewProvider(mem.NewProvider(mem.WithExpiryInterval(10*time.Millisecond)), newSecretLock(t),
+		WithHashedKeys())
+	require.NoError(t, err)
+
+	store, err := prov.OpenStore("test")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Batch([]storage.Operation{{Key: "key1", Value: []byte("value1"), TTL: time.Millisecond}}))
+
+	require.Eventually(t, func() bool {
+		_, err := store.Get("key1")
+		return errors.Is(err, storage.ErrDataNotFound)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestEncryptedStoreConformance(t *testing.T) {
+	prov, err := NewProvider(mem.NewProvider(), newSecretLock(t))
+	require.NoError(t, err)
+
+	storagetest.TestAll(t, prov)
+
+	prov, err = NewProvider(mem.NewProvider(), newSecretLock(t), WithHashedKeys())
+	require.NoError(t, err)
+
+	storagetest.TestAll(t, prov)
+}
+
+func newSecretLock(t *testing.T) secretlock.Service {
+	masterKey := base64.URLEncoding.EncodeToString(random.GetRandomBytes(32))
+
+	secLock, err := local.NewService(bytes.NewReader([]byte(masterKey)), nil)
+	require.NoError(t, err)
+
+	return secLock
+}
+
+func readAll(t *testing.T, itr storage.StoreIterator) map[string]string {
+	defer itr.Release()
+
+	records := make(map[string]string)
+
+	for itr.Next() {
+		records[string(itr.Key())] = string(itr.Value())
+	}
+
+	require.NoError(t, itr.Error())
+
+	return records
+}
+
+// slowSecretLock delays the wrapping of keys, so that concurrent data key creations overlap.
+type slowSecretLock struct {
+	secretlock.Service
+}
+
+func (s *slowSecretLock) Encrypt(keyURI string, req *secretlock.EncryptRequest) (*secretlock.EncryptResponse, error) {
+	time.Sleep(10 * time.Millisecond)
+
+	return s.Service.Encrypt(keyURI, req)
+}
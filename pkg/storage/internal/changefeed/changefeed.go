@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package changefeed implements storage.ChangeFeed for the storage providers.
+package changefeed
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// QueueSize is the number of changes queued for a registered channel which isn't drained,
+// further changes are dropped until the queue has room again.
+const QueueSize = 1024
+
+var logger = log.New("aries-framework/storage/changefeed")
+
+// ErrNilChannel is returned when a nil channel is registered.
+var ErrNilChannel = errors.New("channel is mandatory")
+
+// Feed is a thread-safe register of change channels, meant to be embedded in storage providers.
+// The changes of each channel are queued and sent by a goroutine of their own, so that writers never wait
+// on the channels.
+type Feed struct {
+	mu          sync.RWMutex
+	subscribers []*subscriber
+}
+
+type subscriber struct {
+	ch     chan<- storage.Change
+	queue  chan storage.Change
+	done   chan struct{}
+	exited chan struct{}
+}
+
+// RegisterChangeEvent registers a channel receiving the changes sent with Notify.
+func (f *Feed) RegisterChangeEvent(ch chan<- storage.Change) error {
+	if ch == nil {
+		return ErrNilChannel
+	}
+
+	sub := &subscriber{
+		ch:     ch,
+		queue:  make(chan storage.Change, QueueSize),
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+
+	f.mu.Lock()
+	f.subscribers = append(f.subscribers, sub)
+	f.mu.Unlock()
+
+	go sub.forward()
+
+	return nil
+}
+
+// UnregisterChangeEvent stops sending changes to a channel. No change is sent to it once this returns,
+// the changes still queued for it are dropped.
+func (f *Feed) UnregisterChangeEvent(ch chan<- storage.Change) error {
+	var removed []*subscriber
+
+	f.mu.Lock()
+
+	// Notify reads the slice without holding the lock, so it is replaced rather than shifted in place
+	subscribers := make([]*subscriber, 0, len(f.subscribers))
+
+	for _, sub := range f.subscribers {
+		if sub.ch == ch {
+			removed = append(removed, sub)
+		} else {
+			subscribers = append(subscribers, sub)
+		}
+	}
+
+	f.subscribers = subscribers
+	f.mu.Unlock()
+
+	for _, sub := range removed {
+		close(sub.done)
+		<-sub.exited
+	}
+
+	return nil
+}
+
+// Notify queues a change for each of the given operations applied to store. It must be called once
+// the operations are applied, without holding any lock of the store. It never blocks: the changes
+// are dropped, with a warning, for the channels whose queue is full.
+func (f *Feed) Notify(store string, operations []storage.Operation) {
+	f.mu.RLock()
+	subscribers := f.subscribers
+	f.mu.RUnlock()
+
+	if len(subscribers) == 0 {
+		return
+	}
+
+	for _, op := range operations {
+		change := storage.Change{Store: store, Key: op.Key, Operation: storage.ChangePut}
+		if op.Value == nil {
+			change.Operation = storage.ChangeDelete
+		}
+
+		for _, sub := range subscribers {
+			select {
+			case sub.queue <- change:
+			default:
+				logger.Warnf("change feed queue full, dropped %s of key %s in store %s",
+					change.Operation, change.Key, change.Store)
+			}
+		}
+	}
+}
+
+// NotifyDelete queues a delete change for each of the given keys of store, e.g. once they expired.
+func (f *Feed) NotifyDelete(store string, keys []string) {
+	operations := make([]storage.Operation, len(keys))
+
+	for i, k := range keys {
+		operations[i] = storage.Operation{Key: k}
+	}
+
+	f.Notify(store, operations)
+}
+
+// forward sends the queued changes to the channel of the subscriber until it's unregistered.
+func (s *subscriber) forward() {
+	defer close(s.exited)
+
+	for {
+		select {
+		case change := <-s.queue:
+			select {
+			case s.ch <- change:
+			case <-s.done:
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package changefeed
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+func TestFeed(t *testing.T) {
+	t.Run("test notify - every registered channel receives the changes", func(t *testing.T) {
+		feed := &Feed{}
+
+		ch1 := make(chan storage.Change, 2)
+		ch2 := make(chan storage.Change, 2)
+
+		require.NoError(t, feed.RegisterChangeEvent(ch1))
+		require.NoError(t, feed.RegisterChangeEvent(ch2))
+
+		feed.Notify("store", []storage.Operation{{Key: "key1", Value: []byte("value1")}})
+		feed.NotifyDelete("store", []string{"key2"})
+
+		for _, ch := range []chan storage.Change{ch1, ch2} {
+			require.Equal(t, storage.Change{Store: "store", Key: "key1", Operation: storage.ChangePut}, <-ch)
+			require.Equal(t, storage.Change{Store: "store", Key: "key2", Operation: storage.ChangeDelete}, <-ch)
+		}
+	})
+
+	t.Run("test notify - unregistered channels don't receive changes", func(t *testing.T) {
+		feed := &Feed{}
+
+		ch := make(chan storage.Change)
+
+		require.NoError(t, feed.RegisterChangeEvent(ch))
+		require.NoError(t, feed.UnregisterChangeEvent(ch))
+
+		feed.Notify("store", []storage.Operation{{Key: "key1"}})
+
+		select {
+		case change := <-ch:
+			require.Fail(t, "unexpected change", change)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("test notify - undrained channels don't block writers", func(t *testing.T) {
+		feed := &Feed{}
+
+		ch := make(chan storage.Change)
+
+		require.NoError(t, feed.RegisterChangeEvent(ch))
+
+		operations := make([]storage.Operation, QueueSize+10)
+		for i := range operations {
+			operations[i] = storage.Operation{Key: fmt.Sprintf("key%d", i)}
+		}
+
+		// changes beyond the queue are dropped
+		feed.Notify("store", operations)
+
+		received := 0
+
+	drain:
+		for {
+			select {
+			case <-ch:
+				received++
+			case <-time.After(50 * time.Millisecond):
+				break drain
+			}
+		}
+
+		require.True(t, received >= QueueSize && received < len(operations))
+		require.NoError(t, feed.UnregisterChangeEvent(ch))
+	})
+
+	t.Run("test unregister - queued changes aren't sent", func(t *testing.T) {
+		feed := &Feed{}
+
+		ch := make(chan storage.Change)
+
+		require.NoError(t, feed.RegisterChangeEvent(ch))
+
+		feed.Notify("store", []storage.Operation{{Key: "key1"}, {Key: "key2"}})
+
+		require.NoError(t, feed.UnregisterChangeEvent(ch))
+
+		select {
+		case change := <-ch:
+			require.Fail(t, "unexpected change", change)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("test register - nil channel", func(t *testing.T) {
+		require.Equal(t, ErrNilChannel, (&Feed{}).RegisterChangeEvent(nil))
+	})
+}
@@ -11,6 +11,7 @@ package jsindexeddb
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"syscall/js"
 	"time"
@@ -32,9 +33,16 @@ import (
 const (
 	dbName    = "aries-%s"
 	defDbName = "aries"
+	// tagsIndex is a multi entry index over the tags field, which holds one "name" and one "name:value"
+	// entry per tag so that queries can match either form.
+	tagsIndex = "tags"
+	tagsField = "tags"
+	// expiryIndex indexes the expiry, in unix milliseconds, of the records written with a TTL.
+	expiryIndex = "expiry"
+	expiryField = "expiry"
 )
 
-var dbVersion = 1 //nolint:gochecknoglobals
+var dbVersion = 3 //nolint:gochecknoglobals
 
 // Provider jsindexeddb implementation of storage.Provider interface
 type Provider struct {
@@ -78,6 +86,11 @@ func (p *Provider) OpenStore(name string) (storage.Store, error) {
 	p.Lock()
 	defer p.Unlock()
 
+	// the store may have been opened while waiting for the lock
+	if db, ok = p.stores[name]; ok {
+		return &store{name: name, db: db}, nil
+	}
+
 	// create new if not found in list of object stores (not the predefined ones)
 	err := p.openDB(fmt.Sprintf(dbName, name), name)
 	if err != nil {
@@ -93,8 +106,21 @@ func (p *Provider) openDB(db string, names ...string) error {
 		m := make(map[string]interface{})
 		m["keyPath"] = "key"
 		for _, name := range names {
-			fmt.Printf("indexedDB create object store %s\n", name)
-			this.Get("result").Call("createObjectStore", name, m)
+			var objectStore js.Value
+			if this.Get("result").Get("objectStoreNames").Call("contains", name).Bool() {
+				objectStore = this.Get("transaction").Call("objectStore", name)
+			} else {
+				fmt.Printf("indexedDB create object store %s\n", name)
+				objectStore = this.Get("result").Call("createObjectStore", name, m)
+			}
+
+			if !objectStore.Get("indexNames").Call("contains", tagsIndex).Bool() {
+				objectStore.Call("createIndex", tagsIndex, tagsField, map[string]interface{}{"multiEntry": true})
+			}
+
+			if !objectStore.Get("indexNames").Call("contains", expiryIndex).Bool() {
+				objectStore.Call("createIndex", expiryIndex, expiryField)
+			}
 		}
 		return nil
 	}))
@@ -121,17 +147,17 @@ type store struct {
 	db   *js.Value
 }
 
-// Put stores the key and the record
-func (s *store) Put(k string, v []byte) error {
+// Put stores the key and the record along with optional tags
+func (s *store) Put(k string, v []byte, tags ...storage.Tag) error {
 	if k == "" || v == nil {
 		return errors.New("key and value are mandatory")
 	}
 
-	m := make(map[string]interface{})
-	m["key"] = k
-	m["value"] = string(v)
+	if err := storage.ValidateTags(tags); err != nil {
+		return err
+	}
 
-	req := s.db.Call("transaction", s.name, "readwrite").Call("objectStore", s.name).Call("put", m)
+	req := s.db.Call("transaction", s.name, "readwrite").Call("objectStore", s.name).Call("put", newRecord(k, v, tags, 0))
 
 	_, err := getResult(req)
 	if err != nil {
@@ -141,6 +167,86 @@ func (s *store) Put(k string, v []byte) error {
 	return nil
 }
 
+// Batch performs the given operations in a single readwrite transaction, which IndexedDB commits atomically.
+// IndexedDB has no expiry, so the records written with a TTL are skipped by reads once expired and are
+// removed by the next batch writing a TTL.
+func (s *store) Batch(operations []storage.Operation) error {
+	if err := storage.ValidateOperations(operations); err != nil {
+		return err
+	}
+
+	tx := s.db.Call("transaction", s.name, "readwrite")
+	objectStore := tx.Call("objectStore", s.name)
+
+	hasTTL := false
+
+	for _, op := range operations {
+		if op.Value == nil {
+			objectStore.Call("delete", op.Key)
+			continue
+		}
+
+		objectStore.Call("put", newRecord(op.Key, op.Value, op.Tags, op.TTL))
+
+		hasTTL = hasTTL || op.TTL > 0
+	}
+
+	if err := waitForTransaction(tx); err != nil {
+		return fmt.Errorf("failed to write batch: %w", err)
+	}
+
+	if hasTTL {
+		if err := s.expire(); err != nil {
+			return fmt.Errorf("failed to remove expired records: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// expire removes the records which expired by now.
+func (s *store) expire() error {
+	tx := s.db.Call("transaction", s.name, "readwrite")
+	keyRange := js.Global().Get("IDBKeyRange").Call("upperBound", nowMillis())
+	req := tx.Call("objectStore", s.name).Call("index", expiryIndex).Call("openCursor", keyRange)
+
+	// the cursor has to be advanced from its callback for the transaction to stay active
+	req.Set("onsuccess", js.FuncOf(func(this js.Value, inputs []js.Value) interface{} {
+		cursor := this.Get("result")
+		if cursor.Truthy() {
+			cursor.Call("delete")
+			cursor.Call("continue")
+		}
+
+		return nil
+	}))
+
+	return waitForTransaction(tx)
+}
+
+// newRecord returns the object store record of the given key, value, tags and TTL
+func newRecord(k string, v []byte, tags []storage.Tag, ttl time.Duration) map[string]interface{} {
+	m := make(map[string]interface{})
+	m["key"] = k
+	m["value"] = string(v)
+
+	if ttl > 0 {
+		m[expiryField] = nowMillis() + float64(ttl.Milliseconds())
+	}
+
+	if len(tags) > 0 {
+		var entries []interface{}
+
+		for _, tag := range tags {
+			entries = append(entries, tag.Name, tag.Name+storage.TagNameValueSeparator+tag.Value)
+		}
+
+		m[tagsField] = entries
+	}
+
+	return m
+}
+
 // Get fetches the record based on key
 func (s *store) Get(k string) ([]byte, error) {
 	if k == "" {
@@ -154,25 +260,64 @@ func (s *store) Get(k string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to get data: %w", err)
 	}
 
-	if !data.Truthy() {
+	if !data.Truthy() || expired(*data, nowMillis()) {
 		return nil, storage.ErrDataNotFound
 	}
 
 	return []byte(data.Get("value").String()), nil
 }
 
+// GetTags fetches the tags stored along with the record of key k
+func (s *store) GetTags(k string) ([]storage.Tag, error) {
+	if k == "" {
+		return nil, errors.New("key is mandatory")
+	}
+
+	req := s.db.Call("transaction", s.name).Call("objectStore", s.name).Call("get", k)
+
+	data, err := getResult(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	if !data.Truthy() || expired(*data, nowMillis()) {
+		return nil, storage.ErrDataNotFound
+	}
+
+	return tagsFromEntries(data.Get(tagsField)), nil
+}
+
 // Iterator returns iterator for the latest snapshot of the underlying db.
 func (s *store) Iterator(start, limit string) storage.StoreIterator {
-	// TODO Change Store Iterator https://github.com/hyperledger/aries-framework-go/issues/852
-	if start == "" {
-		return newIterator(nil, fmt.Errorf("start key is mandatory"))
+	if start == "" || limit == "" {
+		return newIterator(nil, fmt.Errorf("start or limit key is mandatory"))
+	}
+
+	// same range semantics as leveldb
+	limit = strings.ReplaceAll(limit, storage.EndKeySuffix, "~")
+
+	// IDBKeyRange.bound throws on an empty range
+	if limit <= start {
+		return newIterator(nil, nil)
 	}
 
-	keyRange := js.Global().Get("IDBKeyRange").Call("bound", start, start+"\uffff")
+	keyRange := js.Global().Get("IDBKeyRange").Call("bound", start, limit, false, true)
 	openCursor := s.db.Call("transaction", s.name).Call("objectStore", s.name).Call("getAll", keyRange)
 	batch, err := getResult(openCursor)
+	if err != nil {
+		return newIterator(nil, err)
+	}
+
+	items := js.Global().Get("Array").New()
+	now := nowMillis()
 
-	return newIterator(batch, err)
+	for i := 0; i < batch.Length(); i++ {
+		if item := batch.Index(i); !expired(item, now) {
+			items.Call("push", item)
+		}
+	}
+
+	return newIterator(&items, nil)
 }
 
 // Delete will delete record with k key
@@ -191,6 +336,68 @@ func (s *store) Delete(k string) error {
 	return nil
 }
 
+// Query returns iterator over the records whose tags match the given expression.
+// The tags index is looked up for the first term and the remaining terms are checked against each candidate.
+func (s *store) Query(expression string) (storage.StoreIterator, error) {
+	terms, err := storage.ParseQuery(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := terms[0].Name
+	if terms[0].Value != "" {
+		entry += storage.TagNameValueSeparator + terms[0].Value
+	}
+
+	req := s.db.Call("transaction", s.name).Call("objectStore", s.name).Call("index", tagsIndex).Call("getAll", entry)
+
+	batch, err := getResult(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query data: %w", err)
+	}
+
+	matches := js.Global().Get("Array").New()
+	now := nowMillis()
+
+	for i := 0; i < batch.Length(); i++ {
+		item := batch.Index(i)
+		if !expired(item, now) && storage.MatchTags(terms[1:], tagsFromEntries(item.Get(tagsField))) {
+			matches.Call("push", item)
+		}
+	}
+
+	return newIterator(&matches, nil), nil
+}
+
+// expired tells whether the given record expired by now, in unix milliseconds.
+func expired(record js.Value, now float64) bool {
+	expiry := record.Get(expiryField)
+
+	return expiry.Truthy() && expiry.Float() <= now
+}
+
+func nowMillis() float64 {
+	return float64(time.Now().UnixNano()) / float64(time.Millisecond)
+}
+
+// tagsFromEntries rebuilds the tags of a record from its tags index entries.
+func tagsFromEntries(entries js.Value) []storage.Tag {
+	if !entries.Truthy() {
+		return nil
+	}
+
+	var tags []storage.Tag
+
+	for i := 0; i < entries.Length(); i++ {
+		parts := strings.SplitN(entries.Index(i).String(), storage.TagNameValueSeparator, 2)
+		if len(parts) == 2 {
+			tags = append(tags, storage.Tag{Name: parts[0], Value: parts[1]})
+		}
+	}
+
+	return tags
+}
+
 type iterator struct {
 	batch *js.Value
 	err   error
@@ -266,6 +473,35 @@ func getResult(req js.Value) (*js.Value, error) {
 	}
 }
 
+func waitForTransaction(tx js.Value) error {
+	oncomplete := make(chan struct{})
+	onerror := make(chan js.Value)
+
+	const timeout = 10
+
+	tx.Set("oncomplete", js.FuncOf(func(this js.Value, inputs []js.Value) interface{} {
+		oncomplete <- struct{}{}
+		return nil
+	}))
+	// errors of the transaction requests bubble up to the transaction and abort it
+	tx.Set("onabort", js.FuncOf(func(this js.Value, inputs []js.Value) interface{} {
+		onerror <- this.Get("error")
+		return nil
+	}))
+	select {
+	case <-oncomplete:
+		return nil
+	case value := <-onerror:
+		if !value.Truthy() {
+			return errors.New("transaction aborted")
+		}
+
+		return fmt.Errorf("%s %s", value.Get("name").String(), value.Get("message").String())
+	case <-time.After(timeout * time.Second):
+		return errors.New("timeout waiting for transaction")
+	}
+}
+
 // since jsindexdb doesn't support adding object stores on fly, using predefined object store names to
 //  create object store in advance instead of creating a database per store.
 // TODO pass store names from higher level packages during initialization [Issue #1347]
@@ -279,6 +515,7 @@ func getStoreNames() []string {
 		peer.StoreNamespace,
 		did.StoreName,
 		localkms.Namespace,
+		localkms.MetadataNamespace,
 		verifiable.NameSpace,
 		issuecredential.Name,
 		presentproof.Name,
@@ -12,10 +12,12 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	storagetest "github.com/hyperledger/aries-framework-go/pkg/storage/test"
 )
 
 const sampleDBName = "testdb"
@@ -105,7 +107,7 @@ func TestStore(t *testing.T) {
 			err = store.Put(key, []byte(fmt.Sprintf(valPrefix, key)))
 			require.NoError(t, err)
 		}
-		itr := store.Iterator("abc_", "")
+		itr := store.Iterator("abc_", "abc_"+storage.EndKeySuffix)
 		require.NoError(t, itr.Error())
 		verifyItr(t, itr, 4, "abc_")
 
@@ -113,11 +115,19 @@ func TestStore(t *testing.T) {
 		require.Error(t, itr.Error())
 		verifyItr(t, itr, 0, "")
 
-		itr = store.Iterator("jkl_123", "")
+		itr = store.Iterator("jkl_123", "jkl_123"+storage.EndKeySuffix)
 		require.NoError(t, itr.Error())
 		verifyItr(t, itr, 1, "jkl_")
 
-		itr = store.Iterator("123", "")
+		itr = store.Iterator("123", "123"+storage.EndKeySuffix)
+		require.NoError(t, itr.Error())
+		verifyItr(t, itr, 0, "")
+
+		itr = store.Iterator("abc_", "mno_123")
+		require.NoError(t, itr.Error())
+		verifyItr(t, itr, 5, "")
+
+		itr = store.Iterator("mno_", "abc_")
 		require.NoError(t, itr.Error())
 		verifyItr(t, itr, 0, "")
 	})
@@ -175,3 +185,127 @@ func TestIndexDBStoreDelete(t *testing.T) {
 	require.EqualError(t, err, storage.ErrDataNotFound.Error())
 	require.Empty(t, doc)
 }
+
+func TestStoreQuery(t *testing.T) {
+	prov, err := NewProvider(sampleDBName)
+	require.NoError(t, err)
+
+	store, err := prov.OpenStore("querytest")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "completed"}))
+	require.NoError(t, store.Put("key2", []byte("value2"),
+		storage.Tag{Name: "state", Value: "invited"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key3", []byte("value3"),
+		storage.Tag{Name: "state", Value: "completed"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key4", []byte("value4")))
+
+	t.Run("Test store query", func(t *testing.T) {
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key1": "value1", "key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("type")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2", "key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("state:completed && type:a")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key3": "value3"}, readAll(t, itr))
+	})
+
+	t.Run("Test store query - tags replaced and deleted", func(t *testing.T) {
+		require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "abandoned"}))
+		require.NoError(t, store.Delete("key3"))
+
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Empty(t, readAll(t, itr))
+	})
+
+	t.Run("Test store query - invalid expression", func(t *testing.T) {
+		_, err := store.Query("")
+		require.Error(t, err)
+	})
+}
+
+func readAll(t *testing.T, itr storage.StoreIterator) map[string]string {
+	defer itr.Release()
+
+	values := make(map[string]string)
+
+	for itr.Next() {
+		values[string(itr.Key())] = string(itr.Value())
+	}
+
+	require.NoError(t, itr.Error())
+
+	return values
+}
+
+func TestStoreBatch(t *testing.T) {
+	prov, err := NewProvider(sampleDBName)
+	require.NoError(t, err)
+
+	store, err := prov.OpenStore("batchtest")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1")))
+
+	t.Run("Test store batch", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{
+			{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state", Value: "completed"}}},
+			{Key: "key1"},
+			{Key: "key3", Value: []byte("value3")},
+		})
+		require.NoError(t, err)
+
+		_, err = store.Get("key1")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), storage.ErrDataNotFound.Error())
+
+		v, err := store.Get("key3")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value3"), v)
+
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, itr))
+	})
+
+	t.Run("Test store batch - invalid operation", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{{Value: []byte("value")}})
+		require.EqualError(t, err, "key is mandatory")
+	})
+
+	t.Run("Test store batch - expired records aren't read and are removed by the next ttl", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key4", Value: []byte("value4"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Millisecond},
+			{Key: "key5", Value: []byte("value5"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Hour},
+		}))
+
+		time.Sleep(10 * time.Millisecond)
+
+		_, err := store.Get("key4")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), storage.ErrDataNotFound.Error())
+
+		itr, err := store.Query("state")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key5": "value5"}, readAll(t, itr))
+
+		require.NoError(t, store.Batch([]storage.Operation{{Key: "key6", Value: []byte("value6"), TTL: time.Hour}}))
+
+		req := prov.stores["batchtest"].Call("transaction", "batchtest").Call("objectStore", "batchtest").Call("get", "key4")
+		data, err := getResult(req)
+		require.NoError(t, err)
+		require.False(t, data.Truthy())
+	})
+}
+
+func TestStoreConformance(t *testing.T) {
+	prov, err := NewProvider(sampleDBName)
+	require.NoError(t, err)
+
+	storagetest.TestAll(t, prov)
+}
@@ -9,30 +9,80 @@ SPDX-License-Identifier: Apache-2.0
 package leveldb
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/util"
 
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/internal/changefeed"
 )
 
-const pathPattern = "%s-%s"
+var logger = log.New("aries-framework/storage/leveldb")
+
+const (
+	pathPattern = "%s-%s"
+
+	// tag index records live in the same db as the data so that they are written atomically with it,
+	// under a prefix which sorts before any printable key so they never show up in key ranges.
+	tagsKeyPrefix     = "\x00tags\x00"
+	tagIndexKeyPrefix = "\x00tagidx\x00"
+	tagIndexSeparator = "\x00"
+
+	// the expiry of a record written with a TTL is kept under its key, and indexed by expiry time
+	// (big endian unix nanoseconds) so that the expired records are found with a single range scan.
+	expiryKeyPrefix      = "\x00exp\x00"
+	expiryIndexKeyPrefix = "\x00expidx\x00"
+	expiryTimeLength     = 8
+
+	// defaultExpiryInterval is how often records written with a TTL are checked for expiry.
+	defaultExpiryInterval = time.Minute
+)
 
 // Provider leveldb implementation of storage.Provider interface
 type Provider struct {
-	dbPath string
-	dbs    map[string]*leveldbStore
-	lock   sync.RWMutex
+	dbPath         string
+	dbs            map[string]*leveldbStore
+	expiryInterval time.Duration
+	feed           *changefeed.Feed
+	lock           sync.RWMutex
+}
+
+// Option configures the leveldb provider.
+type Option func(p *Provider)
+
+// WithExpiryInterval sets how often the stores remove their expired records, one minute by default.
+// A zero or negative interval keeps the default.
+func WithExpiryInterval(interval time.Duration) Option {
+	return func(p *Provider) {
+		if interval > 0 {
+			p.expiryInterval = interval
+		}
+	}
 }
 
 // NewProvider instantiates Provider
-func NewProvider(dbPath string) *Provider {
-	return &Provider{dbs: make(map[string]*leveldbStore), dbPath: dbPath}
+func NewProvider(dbPath string, opts ...Option) *Provider {
+	p := &Provider{
+		dbs:            make(map[string]*leveldbStore),
+		dbPath:         dbPath,
+		expiryInterval: defaultExpiryInterval,
+		feed:           &changefeed.Feed{},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
 // OpenStore opens and returns a store for given name space.
@@ -60,12 +110,26 @@ func (p *Provider) newLeveldbStore(name string) (*leveldbStore, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
+	// the store may have been opened while waiting for the lock, the db files can't be opened twice
+	if store, ok := p.dbs[strings.ToLower(name)]; ok {
+		return store, nil
+	}
+
 	db, err := leveldb.OpenFile(fmt.Sprintf(pathPattern, p.dbPath, name), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	store := &leveldbStore{db}
+	store := &leveldbStore{db: db, name: strings.ToLower(name), feed: p.feed, expiryInterval: p.expiryInterval}
+
+	// records written with a TTL before the store was last closed still have to expire
+	itr := db.NewIterator(util.BytesPrefix([]byte(expiryIndexKeyPrefix)), nil)
+	if itr.First() {
+		store.startExpiry()
+	}
+
+	itr.Release()
+
 	p.dbs[strings.ToLower(name)] = store
 
 	return store, nil
@@ -79,7 +143,7 @@ func (p *Provider) Close() error {
 	var errs []error
 
 	for _, v := range p.dbs {
-		e := v.db.Close()
+		e := v.close()
 		if e != nil && e != leveldb.ErrClosed {
 			errs = append(errs, e)
 		}
@@ -104,23 +168,150 @@ func (p *Provider) CloseStore(name string) error {
 	store, ok := p.dbs[k]
 	if ok {
 		delete(p.dbs, k)
-		return store.db.Close()
+		return store.close()
 	}
 
 	return nil
 }
 
+// RegisterChangeEvent registers a channel receiving the changes of every store of the provider.
+func (p *Provider) RegisterChangeEvent(ch chan<- storage.Change) error {
+	return p.feed.RegisterChangeEvent(ch)
+}
+
+// UnregisterChangeEvent stops sending changes to a channel.
+func (p *Provider) UnregisterChangeEvent(ch chan<- storage.Change) error {
+	return p.feed.UnregisterChangeEvent(ch)
+}
+
 type leveldbStore struct {
-	db *leveldb.DB
+	db   *leveldb.DB
+	name string
+	feed *changefeed.Feed
+	// serializes batches and expiry, which read the previous tags of their keys to update the index
+	lock sync.Mutex
+	// the expiry loop is started along with the first record written with a TTL
+	expiryInterval time.Duration
+	stopExpiry     chan struct{}
+	expiryDone     chan struct{}
+}
+
+// close stops the expiry loop, waiting for any ongoing expiry to complete, then closes the db.
+func (s *leveldbStore) close() error {
+	s.lock.Lock()
+	stop, done := s.stopExpiry, s.expiryDone
+	s.stopExpiry = nil
+	s.lock.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	return s.db.Close()
 }
 
-// Put stores the key and the record
-func (s *leveldbStore) Put(k string, v []byte) error {
+// Put stores the key and the record along with optional tags
+func (s *leveldbStore) Put(k string, v []byte, tags ...storage.Tag) error {
 	if k == "" || v == nil {
 		return errors.New("key and value are mandatory")
 	}
 
-	return s.db.Put([]byte(k), v, nil)
+	return s.Batch([]storage.Operation{{Key: k, Value: v, Tags: tags}})
+}
+
+// Batch writes the given operations, along with the matching tag index updates, in a single leveldb batch.
+func (s *leveldbStore) Batch(operations []storage.Operation) error {
+	if err := storage.ValidateOperations(operations); err != nil {
+		return err
+	}
+
+	if err := s.write(operations); err != nil {
+		return err
+	}
+
+	s.feed.Notify(s.name, operations)
+
+	return nil
+}
+
+func (s *leveldbStore) write(operations []storage.Operation) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batch := new(leveldb.Batch)
+	// tags and expiries of the keys already written by this batch, which aren't in the db yet
+	pending := make(map[string][]storage.Tag)
+	pendingExpiry := make(map[string][]byte)
+	hasTTL := false
+
+	for _, op := range operations {
+		if err := s.clear(batch, op.Key, pending, pendingExpiry); err != nil {
+			return err
+		}
+
+		if op.Value == nil {
+			batch.Delete([]byte(op.Key))
+			pending[op.Key] = nil
+			pendingExpiry[op.Key] = nil
+
+			continue
+		}
+
+		if err := putTags(batch, op.Key, op.Tags); err != nil {
+			return err
+		}
+
+		batch.Put([]byte(op.Key), op.Value)
+		pending[op.Key] = op.Tags
+		pendingExpiry[op.Key] = nil
+
+		if op.TTL > 0 {
+			pendingExpiry[op.Key] = putExpiry(batch, op.Key, time.Now().Add(op.TTL))
+			hasTTL = true
+		}
+	}
+
+	if err := s.db.Write(batch, nil); err != nil {
+		return err
+	}
+
+	if hasTTL && s.stopExpiry == nil {
+		s.startExpiry()
+	}
+
+	return nil
+}
+
+// clear adds to batch the removal of the tags and expiry previously stored with key k,
+// looking up the ones written earlier in the same batch first.
+func (s *leveldbStore) clear(batch *leveldb.Batch, k string, pending map[string][]storage.Tag,
+	pendingExpiry map[string][]byte) error {
+	tags, ok := pending[k]
+	if !ok {
+		var err error
+
+		tags, err = s.getTags(k)
+		if err != nil {
+			return err
+		}
+	}
+
+	deleteTags(batch, k, tags)
+
+	expiry, ok := pendingExpiry[k]
+	if !ok {
+		var err error
+
+		expiry, err = s.getExpiry(k)
+		if err != nil {
+			return err
+		}
+	}
+
+	deleteExpiry(batch, k, expiry)
+
+	return nil
 }
 
 // Get fetches the record based on key
@@ -138,17 +329,39 @@ func (s *leveldbStore) Get(k string) ([]byte, error) {
 		return nil, err
 	}
 
+	expired, err := s.expired(k, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if expired {
+		return nil, storage.ErrDataNotFound
+	}
+
 	return data, nil
 }
 
+// GetTags fetches the tags stored along with the record of key k
+func (s *leveldbStore) GetTags(k string) ([]storage.Tag, error) {
+	if _, err := s.Get(k); err != nil {
+		return nil, err
+	}
+
+	return s.getTags(k)
+}
+
 // Iterator returns iterator for the latest snapshot of the underlying db.
 func (s *leveldbStore) Iterator(start, limit string) storage.StoreIterator {
 	if start == "" || limit == "" {
-		iterator.NewEmptyIterator(errors.New("start or limit key is mandatory"))
+		return iterator.NewEmptyIterator(errors.New("start or limit key is mandatory"))
 	}
 
-	return s.db.NewIterator(&util.Range{Start: []byte(start),
-		Limit: []byte(strings.ReplaceAll(limit, storage.EndKeySuffix, "~"))}, nil)
+	return &unexpiredIterator{
+		Iterator: s.db.NewIterator(&util.Range{Start: []byte(start),
+			Limit: []byte(strings.ReplaceAll(limit, storage.EndKeySuffix, "~"))}, nil),
+		store: s,
+		now:   time.Now(),
+	}
 }
 
 // Delete will delete record with k key
@@ -157,5 +370,344 @@ func (s *leveldbStore) Delete(k string) error {
 		return errors.New("key is mandatory")
 	}
 
-	return s.db.Delete([]byte(k), nil)
+	return s.Batch([]storage.Operation{{Key: k}})
+}
+
+// Query returns iterator over the records whose tags match the given expression.
+// The index of the first term is scanned and the remaining terms are checked against each candidate's tags.
+func (s *leveldbStore) Query(expression string) (storage.StoreIterator, error) {
+	terms, err := storage.ParseQuery(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := tagIndexKeyPrefix + terms[0].Name + tagIndexSeparator
+	if terms[0].Value != "" {
+		prefix += terms[0].Value + tagIndexSeparator
+	}
+
+	return &queryIterator{
+		store:  s,
+		index:  s.db.NewIterator(util.BytesPrefix([]byte(prefix)), nil),
+		prefix: prefix,
+		terms:  terms,
+		byName: terms[0].Value == "",
+		seen:   make(map[string]struct{}),
+		now:    time.Now(),
+	}, nil
+}
+
+// deleteTags adds to batch the removal of the given tags previously stored with key k.
+func deleteTags(batch *leveldb.Batch, k string, tags []storage.Tag) {
+	if len(tags) == 0 {
+		return
+	}
+
+	batch.Delete([]byte(tagsKeyPrefix + k))
+
+	for _, tag := range tags {
+		batch.Delete([]byte(tagIndexKey(tag.Name, tag.Value, k)))
+	}
+}
+
+// putTags adds to batch the given tags of key k along with their index entries.
+func putTags(batch *leveldb.Batch, k string, tags []storage.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	tagsBytes, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tags: %w", err)
+	}
+
+	batch.Put([]byte(tagsKeyPrefix+k), tagsBytes)
+
+	for _, tag := range tags {
+		batch.Put([]byte(tagIndexKey(tag.Name, tag.Value, k)), []byte{})
+	}
+
+	return nil
+}
+
+func (s *leveldbStore) getTags(k string) ([]storage.Tag, error) {
+	tagsBytes, err := s.db.Get([]byte(tagsKeyPrefix+k), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	var tags []storage.Tag
+
+	if err := json.Unmarshal(tagsBytes, &tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// putExpiry adds to batch the expiry of key k along with its index entry, and returns the encoded expiry.
+func putExpiry(batch *leveldb.Batch, k string, expiry time.Time) []byte {
+	encoded := make([]byte, expiryTimeLength)
+	binary.BigEndian.PutUint64(encoded, uint64(expiry.UnixNano()))
+
+	batch.Put([]byte(expiryKeyPrefix+k), encoded)
+	batch.Put(expiryIndexKey(encoded, k), []byte{})
+
+	return encoded
+}
+
+// deleteExpiry adds to batch the removal of the encoded expiry previously stored with key k.
+func deleteExpiry(batch *leveldb.Batch, k string, expiry []byte) {
+	if expiry == nil {
+		return
+	}
+
+	batch.Delete([]byte(expiryKeyPrefix + k))
+	batch.Delete(expiryIndexKey(expiry, k))
+}
+
+func (s *leveldbStore) getExpiry(k string) ([]byte, error) {
+	expiry, err := s.db.Get([]byte(expiryKeyPrefix+k), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get expiry: %w", err)
+	}
+
+	return expiry, nil
+}
+
+// expired tells whether the record of key k expired by the given time but wasn't removed yet.
+func (s *leveldbStore) expired(k string, now time.Time) (bool, error) {
+	expiry, err := s.getExpiry(k)
+	if err != nil || expiry == nil {
+		return false, err
+	}
+
+	return binary.BigEndian.Uint64(expiry) <= uint64(now.UnixNano()), nil
+}
+
+func expiryIndexKey(expiry []byte, k string) []byte {
+	return append(append([]byte(expiryIndexKeyPrefix), expiry...), k...)
+}
+
+// startExpiry starts the expiry loop, the caller either holds the store lock or has the store to itself.
+func (s *leveldbStore) startExpiry() {
+	s.stopExpiry = make(chan struct{})
+	s.expiryDone = make(chan struct{})
+
+	go s.expireLoop(s.stopExpiry, s.expiryDone)
+}
+
+// expireLoop removes the expired records every expiry interval, until stop is closed.
+func (s *leveldbStore) expireLoop(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.expiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			expired, err := s.expire(now)
+			if err != nil {
+				logger.Warnf("failed to remove expired records: %s", err)
+			}
+
+			s.feed.NotifyDelete(s.name, expired)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// expire removes the records which expired by the given time, along with their tags and expiry,
+// and returns their keys.
+func (s *leveldbStore) expire(now time.Time) ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	limit := make([]byte, expiryTimeLength)
+	binary.BigEndian.PutUint64(limit, uint64(now.UnixNano())+1)
+
+	itr := s.db.NewIterator(&util.Range{
+		Start: []byte(expiryIndexKeyPrefix),
+		Limit: append([]byte(expiryIndexKeyPrefix), limit...),
+	}, nil)
+	defer itr.Release()
+
+	batch := new(leveldb.Batch)
+
+	var expired []string
+
+	for itr.Next() {
+		indexKey := itr.Key()[len(expiryIndexKeyPrefix):]
+		k := string(indexKey[expiryTimeLength:])
+
+		tags, err := s.getTags(k)
+		if err != nil {
+			return nil, err
+		}
+
+		deleteTags(batch, k, tags)
+		deleteExpiry(batch, k, indexKey[:expiryTimeLength])
+		batch.Delete([]byte(k))
+
+		expired = append(expired, k)
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read expiry index: %w", err)
+	}
+
+	if len(expired) == 0 {
+		return nil, nil
+	}
+
+	if err := s.db.Write(batch, nil); err != nil {
+		return nil, err
+	}
+
+	return expired, nil
+}
+
+func tagIndexKey(name, value, k string) string {
+	return tagIndexKeyPrefix + name + tagIndexSeparator + value + tagIndexSeparator + k
+}
+
+// queryIterator walks the tag index and resolves the matching records.
+type queryIterator struct {
+	store  *leveldbStore
+	index  iterator.Iterator
+	prefix string
+	terms  []storage.Tag
+	byName bool
+	now    time.Time
+	key    []byte
+	value  []byte
+	err    error
+	// keys already returned by a query by tag name, which are indexed once per value of the tag
+	seen map[string]struct{}
+}
+
+// Next moves the iterator to the next matching record.
+func (i *queryIterator) Next() bool {
+	i.key, i.value = nil, nil
+
+	for i.err == nil && i.index.Next() {
+		k := strings.TrimPrefix(string(i.index.Key()), i.prefix)
+		if i.byName {
+			// index key still holds the tag value
+			k = k[strings.Index(k, tagIndexSeparator)+len(tagIndexSeparator):]
+
+			if _, ok := i.seen[k]; ok {
+				continue
+			}
+
+			i.seen[k] = struct{}{}
+		}
+
+		if len(i.terms) > 1 {
+			tags, err := i.store.getTags(k)
+			if err != nil {
+				i.err = err
+				return false
+			}
+
+			if !storage.MatchTags(i.terms[1:], tags) {
+				continue
+			}
+		}
+
+		v, err := i.store.db.Get([]byte(k), nil)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				continue
+			}
+
+			i.err = err
+
+			return false
+		}
+
+		expired, err := i.store.expired(k, i.now)
+		if err != nil {
+			i.err = err
+			return false
+		}
+
+		if expired {
+			continue
+		}
+
+		i.key, i.value = []byte(k), v
+
+		return true
+	}
+
+	return false
+}
+
+// Release releases the underlying index iterator.
+func (i *queryIterator) Release() {
+	i.index.Release()
+}
+
+// Error returns any accumulated error.
+func (i *queryIterator) Error() error {
+	if i.err != nil {
+		return i.err
+	}
+
+	return i.index.Error()
+}
+
+// Key returns the key of the current record.
+func (i *queryIterator) Key() []byte {
+	return i.key
+}
+
+// Value returns the value of the current record.
+func (i *queryIterator) Value() []byte {
+	return i.value
+}
+
+// unexpiredIterator skips the records which expired but weren't removed yet.
+type unexpiredIterator struct {
+	iterator.Iterator
+	store *leveldbStore
+	now   time.Time
+	err   error
+}
+
+// Next moves the iterator to the next record which hasn't expired.
+func (i *unexpiredIterator) Next() bool {
+	for i.err == nil && i.Iterator.Next() {
+		expired, err := i.store.expired(string(i.Iterator.Key()), i.now)
+		if err != nil {
+			i.err = err
+			return false
+		}
+
+		if !expired {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Error returns any accumulated error.
+func (i *unexpiredIterator) Error() error {
+	if i.err != nil {
+		return i.err
+	}
+
+	return i.Iterator.Error()
 }
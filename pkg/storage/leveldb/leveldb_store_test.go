@@ -9,15 +9,18 @@ SPDX-License-Identifier: Apache-2.0
 package leveldb
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	storagetest "github.com/hyperledger/aries-framework-go/pkg/storage/test"
 )
 
 func setupLevelDB(t testing.TB) (string, func()) {
@@ -294,3 +297,272 @@ func TestLevelDBStoreDelete(t *testing.T) {
 	require.EqualError(t, err, storage.ErrDataNotFound.Error())
 	require.Empty(t, doc)
 }
+
+func TestLevelDBStoreQuery(t *testing.T) {
+	path, cleanup := setupLevelDB(t)
+	defer cleanup()
+
+	prov := NewProvider(path)
+	store, err := prov.OpenStore("test")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "completed"}))
+	require.NoError(t, store.Put("key2", []byte("value2"),
+		storage.Tag{Name: "state", Value: "invited"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key3", []byte("value3"),
+		storage.Tag{Name: "state", Value: "completed"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key4", []byte("value4")))
+
+	t.Run("Test leveldb store query", func(t *testing.T) {
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key1": "value1", "key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("type")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2", "key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("state:completed && type:a")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("label")
+		require.NoError(t, err)
+		require.Empty(t, readAll(t, itr))
+	})
+
+	t.Run("Test leveldb store query - index is not visible through key ranges", func(t *testing.T) {
+		itr := store.Iterator("key", "key"+storage.EndKeySuffix)
+		require.Len(t, readAll(t, itr), 4)
+
+		itr = store.Iterator(" ", "~")
+		require.Len(t, readAll(t, itr), 4)
+	})
+
+	t.Run("Test leveldb store query - tags replaced and deleted", func(t *testing.T) {
+		require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "abandoned"}))
+		require.NoError(t, store.Delete("key3"))
+
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Empty(t, readAll(t, itr))
+
+		itr, err = store.Query("state:abandoned")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key1": "value1"}, readAll(t, itr))
+
+		require.NoError(t, store.Put("key1", []byte("value1")))
+
+		itr, err = store.Query("state")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, itr))
+	})
+
+	t.Run("Test leveldb store query - invalid expression and tags", func(t *testing.T) {
+		_, err := store.Query("")
+		require.Error(t, err)
+
+		err = store.Put("key5", []byte("value5"), storage.Tag{Name: "a:b"})
+		require.Error(t, err)
+	})
+
+	t.Run("Test leveldb store query - tags survive reopening the store", func(t *testing.T) {
+		require.NoError(t, prov.CloseStore("test"))
+
+		store, err := prov.OpenStore("test")
+		require.NoError(t, err)
+
+		itr, err := store.Query("type:a")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, itr))
+	})
+}
+
+func readAll(t *testing.T, itr storage.StoreIterator) map[string]string {
+	defer itr.Release()
+
+	values := make(map[string]string)
+
+	for itr.Next() {
+		values[string(itr.Key())] = string(itr.Value())
+	}
+
+	require.NoError(t, itr.Error())
+
+	return values
+}
+
+func TestLevelDBStoreBatch(t *testing.T) {
+	path, cleanup := setupLevelDB(t)
+	defer cleanup()
+
+	prov := NewProvider(path)
+	store, err := prov.OpenStore("test")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "invited"}))
+
+	t.Run("Test leveldb store batch", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{
+			{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state", Value: "invited"}}},
+			{Key: "key1"},
+			{Key: "key3", Value: []byte("value3")},
+		})
+		require.NoError(t, err)
+
+		_, err = store.Get("key1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		v, err := store.Get("key3")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value3"), v)
+
+		itr, err := store.Query("state:invited")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, itr))
+	})
+
+	t.Run("Test leveldb store batch - same key written twice", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{
+			{Key: "key4", Value: []byte("value4"), Tags: []storage.Tag{{Name: "state", Value: "invited"}}},
+			{Key: "key4", Value: []byte("value4.1"), Tags: []storage.Tag{{Name: "state", Value: "completed"}}},
+		})
+		require.NoError(t, err)
+
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key4": "value4.1"}, readAll(t, itr))
+
+		itr, err = store.Query("state:invited")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, itr))
+	})
+
+	t.Run("Test leveldb store batch - invalid operation fails the whole batch", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{
+			{Key: "key5", Value: []byte("value5")},
+			{Value: []byte("value6")},
+		})
+		require.EqualError(t, err, "key is mandatory")
+
+		_, err = store.Get("key5")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+}
+
+func TestWithExpiryInterval(t *testing.T) {
+	path, cleanup := setupLevelDB(t)
+	defer cleanup()
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		prov := NewProvider(path, WithExpiryInterval(interval))
+		require.Equal(t, defaultExpiryInterval, prov.expiryInterval)
+
+		store, err := prov.OpenStore("test-interval")
+		require.NoError(t, err)
+		require.NoError(t, store.Batch([]storage.Operation{{Key: "key1", Value: []byte("value1"), TTL: time.Hour}}))
+		require.NoError(t, prov.Close())
+	}
+
+	require.Equal(t, time.Second, NewProvider(path, WithExpiryInterval(time.Second)).expiryInterval)
+}
+
+func TestLevelDBStoreTTL(t *testing.T) {
+	path, cleanup := setupLevelDB(t)
+	defer cleanup()
+
+	prov := NewProvider(path, WithExpiryInterval(10*time.Millisecond))
+	defer func() { require.NoError(t, prov.Close()) }()
+
+	store, err := prov.OpenStore("test-ttl")
+	require.NoError(t, err)
+
+	t.Run("Test leveldb store ttl - expired records are removed along with their tags", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key1", Value: []byte("value1"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Millisecond},
+			{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Hour},
+			{Key: "key3", Value: []byte("value3"), Tags: []storage.Tag{{Name: "state"}}},
+		}))
+
+		// expired records aren't read before they're removed, so wait for their expiry entry to be removed
+		require.Eventually(t, func() bool {
+			_, err := store.(*leveldbStore).db.Get([]byte(expiryKeyPrefix+"key1"), nil)
+			return err != nil
+		}, time.Second, 10*time.Millisecond)
+
+		_, err := store.Get("key1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		itr, err := store.Query("state")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2", "key3": "value3"}, readAll(t, itr))
+	})
+
+	t.Run("Test leveldb store ttl - putting a record again clears its ttl", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key4", Value: []byte("value4"), TTL: time.Millisecond},
+			{Key: "key4", Value: []byte("value4")},
+		}))
+
+		time.Sleep(50 * time.Millisecond)
+
+		v, err := store.Get("key4")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value4"), v)
+	})
+
+	t.Run("Test leveldb store ttl - expired records aren't read before they're removed", func(t *testing.T) {
+		path, cleanup := setupLevelDB(t)
+		defer cleanup()
+
+		prov := NewProvider(path, WithExpiryInterval(time.Hour))
+		defer func() { require.NoError(t, prov.Close()) }()
+
+		store, err := prov.OpenStore("test-ttl")
+		require.NoError(t, err)
+
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key6", Value: []byte("value6"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Millisecond},
+			{Key: "key7", Value: []byte("value7"), Tags: []storage.Tag{{Name: "state"}}},
+		}))
+
+		time.Sleep(10 * time.Millisecond)
+
+		_, err = store.Get("key6")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = store.GetTags("key6")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		itr, err := store.Query("state")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key7": "value7"}, readAll(t, itr))
+		require.Equal(t, map[string]string{"key7": "value7"}, readAll(t, store.Iterator("key", "key~")))
+	})
+
+	t.Run("Test leveldb store ttl - records expire after the store is reopened", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key5", Value: []byte("value5"), TTL: 50 * time.Millisecond},
+		}))
+		require.NoError(t, prov.CloseStore("test-ttl"))
+
+		store, err = prov.OpenStore("test-ttl")
+		require.NoError(t, err)
+		require.NotNil(t, store.(*leveldbStore).stopExpiry)
+
+		require.Eventually(t, func() bool {
+			_, err := store.Get("key5")
+			return errors.Is(err, storage.ErrDataNotFound)
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestLevelDBStoreConformance(t *testing.T) {
+	path, cleanup := setupLevelDB(t)
+	defer cleanup()
+
+	prov := NewProvider(path)
+	defer func() { require.NoError(t, prov.Close()) }()
+
+	storagetest.TestAll(t, prov)
+}
@@ -8,24 +8,52 @@ package mem
 
 import (
 	"errors"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/internal/changefeed"
 )
 
-// TODO https://github.com/hyperledger/aries-framework-go/issues/750 - we will need to consider
-//  automatic eviction based on TTL.
+// defaultExpiryInterval is how often records written with a TTL are checked for expiry.
+const defaultExpiryInterval = time.Minute
 
 // Provider leveldb implementation of storage.Provider interface
 type Provider struct {
-	dbs  map[string]*memStore
-	lock sync.RWMutex
+	dbs            map[string]*memStore
+	expiryInterval time.Duration
+	feed           *changefeed.Feed
+	lock           sync.RWMutex
+}
+
+// Option configures the mem provider.
+type Option func(p *Provider)
+
+// WithExpiryInterval sets how often the stores remove their expired records, one minute by default.
+// A zero or negative interval keeps the default.
+func WithExpiryInterval(interval time.Duration) Option {
+	return func(p *Provider) {
+		if interval > 0 {
+			p.expiryInterval = interval
+		}
+	}
 }
 
 // NewProvider instantiates Provider
-func NewProvider() *Provider {
-	return &Provider{dbs: make(map[string]*memStore)}
+func NewProvider(opts ...Option) *Provider {
+	p := &Provider{
+		dbs:            make(map[string]*memStore),
+		expiryInterval: defaultExpiryInterval,
+		feed:           &changefeed.Feed{},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
 // OpenStore opens and returns a store for given name space.
@@ -53,7 +81,19 @@ func (p *Provider) newMemStore(name string) *memStore {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
-	store := &memStore{db: make(map[string][]byte)}
+	// the store may have been created while waiting for the lock
+	if store, ok := p.dbs[strings.ToLower(name)]; ok {
+		return store
+	}
+
+	store := &memStore{
+		name:           strings.ToLower(name),
+		feed:           p.feed,
+		db:             make(map[string][]byte),
+		tags:           make(map[string][]storage.Tag),
+		expiry:         make(map[string]time.Time),
+		expiryInterval: p.expiryInterval,
+	}
 	p.dbs[strings.ToLower(name)] = store
 
 	return store
@@ -65,7 +105,7 @@ func (p *Provider) Close() error {
 	defer p.lock.Unlock()
 
 	for _, memStore := range p.dbs {
-		memStore.db = make(map[string][]byte)
+		memStore.reset()
 	}
 
 	p.dbs = make(map[string]*memStore)
@@ -84,30 +124,148 @@ func (p *Provider) CloseStore(name string) error {
 	if ok {
 		delete(p.dbs, k)
 
-		memStore.db = make(map[string][]byte)
+		memStore.reset()
 	}
 
 	return nil
 }
 
+// RegisterChangeEvent registers a channel receiving the changes of every store of the provider.
+func (p *Provider) RegisterChangeEvent(ch chan<- storage.Change) error {
+	return p.feed.RegisterChangeEvent(ch)
+}
+
+// UnregisterChangeEvent stops sending changes to a channel.
+func (p *Provider) UnregisterChangeEvent(ch chan<- storage.Change) error {
+	return p.feed.UnregisterChangeEvent(ch)
+}
+
 type memStore struct {
-	db map[string][]byte
+	name   string
+	feed   *changefeed.Feed
+	db     map[string][]byte
+	tags   map[string][]storage.Tag
+	expiry map[string]time.Time
+	// the expiry loop is started along with the first record written with a TTL
+	expiryInterval time.Duration
+	stopExpiry     chan struct{}
 	sync.RWMutex
 }
 
-// Put stores the key and the record
-func (s *memStore) Put(k string, v []byte) error {
+func (s *memStore) reset() {
+	s.Lock()
+	s.db = make(map[string][]byte)
+	s.tags = make(map[string][]storage.Tag)
+	s.expiry = make(map[string]time.Time)
+
+	if s.stopExpiry != nil {
+		close(s.stopExpiry)
+		s.stopExpiry = nil
+	}
+
+	s.Unlock()
+}
+
+// Put stores the key and the record along with optional tags
+func (s *memStore) Put(k string, v []byte, tags ...storage.Tag) error {
 	if k == "" || v == nil {
 		return errors.New("key and value are mandatory")
 	}
 
-	s.Lock()
-	s.db[k] = v
-	s.Unlock()
+	return s.Batch([]storage.Operation{{Key: k, Value: v, Tags: tags}})
+}
+
+// Batch performs the given operations under a single lock, so they're applied all at once
+func (s *memStore) Batch(operations []storage.Operation) error {
+	if err := storage.ValidateOperations(operations); err != nil {
+		return err
+	}
+
+	s.apply(operations)
+	s.feed.Notify(s.name, operations)
 
 	return nil
 }
 
+func (s *memStore) apply(operations []storage.Operation) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := time.Now()
+
+	for _, op := range operations {
+		delete(s.expiry, op.Key)
+
+		if op.Value == nil {
+			delete(s.db, op.Key)
+			delete(s.tags, op.Key)
+
+			continue
+		}
+
+		s.db[op.Key] = op.Value
+
+		if len(op.Tags) > 0 {
+			s.tags[op.Key] = append([]storage.Tag(nil), op.Tags...)
+		} else {
+			delete(s.tags, op.Key)
+		}
+
+		if op.TTL > 0 {
+			s.expiry[op.Key] = now.Add(op.TTL)
+		}
+	}
+
+	if len(s.expiry) > 0 && s.stopExpiry == nil {
+		s.stopExpiry = make(chan struct{})
+
+		go s.expireLoop(s.stopExpiry)
+	}
+}
+
+// expireLoop removes the expired records every expiry interval, until stop is closed.
+func (s *memStore) expireLoop(stop chan struct{}) {
+	ticker := time.NewTicker(s.expiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			s.feed.NotifyDelete(s.name, s.expire(now))
+		case <-stop:
+			return
+		}
+	}
+}
+
+// expire removes the records which expired by the given time and returns their keys.
+func (s *memStore) expire(now time.Time) []string {
+	s.Lock()
+	defer s.Unlock()
+
+	var expired []string
+
+	for k, expiry := range s.expiry {
+		if !expiry.After(now) {
+			delete(s.db, k)
+			delete(s.tags, k)
+			delete(s.expiry, k)
+
+			expired = append(expired, k)
+		}
+	}
+
+	return expired
+}
+
+// expired tells whether the record of key k expired by the given time but wasn't removed yet,
+// the caller holds the store lock.
+func (s *memStore) expired(k string, now time.Time) bool {
+	expiry, ok := s.expiry[k]
+
+	return ok && !expiry.After(now)
+}
+
 // Get fetches the record based on key
 func (s *memStore) Get(k string) ([]byte, error) {
 	if k == "" {
@@ -116,30 +274,62 @@ func (s *memStore) Get(k string) ([]byte, error) {
 
 	s.RLock()
 	data, ok := s.db[k]
+	expired := s.expired(k, time.Now())
 	s.RUnlock()
 
-	if !ok {
+	if !ok || expired {
 		return nil, storage.ErrDataNotFound
 	}
 
 	return data, nil
 }
 
+// GetTags fetches the tags stored along with the record of key k
+func (s *memStore) GetTags(k string) ([]storage.Tag, error) {
+	if k == "" {
+		return nil, errors.New("key is mandatory")
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	if _, ok := s.db[k]; !ok || s.expired(k, time.Now()) {
+		return nil, storage.ErrDataNotFound
+	}
+
+	return append([]storage.Tag(nil), s.tags[k]...), nil
+}
+
 // Iterator returns iterator for the latest snapshot of the underlying db.
 func (s *memStore) Iterator(start, limit string) storage.StoreIterator {
-	// TODO Change Store Iterator https://github.com/hyperledger/aries-framework-go/issues/852
+	if start == "" || limit == "" {
+		return &memIterator{err: errors.New("start or limit key is mandatory")}
+	}
+
+	// same range semantics as leveldb
+	limit = strings.ReplaceAll(limit, storage.EndKeySuffix, "~")
+
 	s.RLock()
-	data := s.db
 	defer s.RUnlock()
 
-	var batch [][]string
+	var keys []string
 
-	for k, v := range data {
-		if strings.HasPrefix(k, start) {
-			batch = append(batch, []string{k, string(v)})
+	now := time.Now()
+
+	for k := range s.db {
+		if k >= start && k < limit && !s.expired(k, now) {
+			keys = append(keys, k)
 		}
 	}
 
+	sort.Strings(keys)
+
+	batch := make([][]string, len(keys))
+
+	for i, k := range keys {
+		batch[i] = []string{k, string(s.db[k])}
+	}
+
 	return newMemIterator(batch)
 }
 
@@ -149,11 +339,38 @@ func (s *memStore) Delete(k string) error {
 		return errors.New("key is mandatory")
 	}
 
-	s.Lock()
-	delete(s.db, k)
-	s.Unlock()
+	return s.Batch([]storage.Operation{{Key: k}})
+}
 
-	return nil
+// Query returns iterator over the records whose tags match the given expression.
+func (s *memStore) Query(expression string) (storage.StoreIterator, error) {
+	terms, err := storage.ParseQuery(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	var keys []string
+
+	now := time.Now()
+
+	for k, tags := range s.tags {
+		if storage.MatchTags(terms, tags) && !s.expired(k, now) {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	batch := make([][]string, len(keys))
+
+	for i, k := range keys {
+		batch[i] = []string{k, string(s.db[k])}
+	}
+
+	return newMemIterator(batch), nil
 }
 
 type memIterator struct {
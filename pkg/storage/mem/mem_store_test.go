@@ -7,11 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package mem
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	storagetest "github.com/hyperledger/aries-framework-go/pkg/storage/test"
 )
 
 func TestMemStore(t *testing.T) {
@@ -186,7 +189,7 @@ func TestMemStoreIterator(t *testing.T) {
 			require.NoError(t, err)
 		}
 
-		itr := store.Iterator("", "")
+		itr := store.Iterator("key", "key"+storage.EndKeySuffix)
 		defer itr.Release()
 
 		count := 0
@@ -204,7 +207,7 @@ func TestMemStoreIterator(t *testing.T) {
 		store, err := prov.OpenStore("test2")
 		require.NoError(t, err)
 
-		itr := store.Iterator("", "")
+		itr := store.Iterator("key", "key"+storage.EndKeySuffix)
 		defer itr.Release()
 
 		require.False(t, itr.Next())
@@ -212,6 +215,20 @@ func TestMemStoreIterator(t *testing.T) {
 		require.Nil(t, itr.Value())
 		require.NoError(t, itr.Error())
 	})
+
+	t.Run("Test mem store iterator - start and limit keys are mandatory", func(t *testing.T) {
+		prov := NewProvider()
+		store, err := prov.OpenStore("test3")
+		require.NoError(t, err)
+
+		require.NoError(t, store.Put("key1", []byte("value1")))
+
+		itr := store.Iterator("", "")
+		defer itr.Release()
+
+		require.False(t, itr.Next())
+		require.EqualError(t, itr.Error(), "start or limit key is mandatory")
+	})
 }
 
 func TestMemStoreDelete(t *testing.T) {
@@ -245,3 +262,206 @@ func TestMemStoreDelete(t *testing.T) {
 	require.EqualError(t, err, storage.ErrDataNotFound.Error())
 	require.Empty(t, doc)
 }
+
+func TestMemStoreQuery(t *testing.T) {
+	prov := NewProvider()
+	store, err := prov.OpenStore("test")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "completed"}))
+	require.NoError(t, store.Put("key2", []byte("value2"),
+		storage.Tag{Name: "state", Value: "invited"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key3", []byte("value3"),
+		storage.Tag{Name: "state", Value: "completed"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key4", []byte("value4")))
+
+	t.Run("Test mem store query", func(t *testing.T) {
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key1": "value1", "key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("type")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2", "key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("state:completed && type:a")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("label")
+		require.NoError(t, err)
+		require.Empty(t, readAll(t, itr))
+	})
+
+	t.Run("Test mem store query - tags replaced and deleted", func(t *testing.T) {
+		require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "abandoned"}))
+		require.NoError(t, store.Delete("key3"))
+
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Empty(t, readAll(t, itr))
+
+		itr, err = store.Query("state:abandoned")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key1": "value1"}, readAll(t, itr))
+	})
+
+	t.Run("Test mem store query - invalid expression and tags", func(t *testing.T) {
+		_, err := store.Query("")
+		require.Error(t, err)
+
+		err = store.Put("key5", []byte("value5"), storage.Tag{Name: "a:b"})
+		require.Error(t, err)
+	})
+}
+
+func readAll(t *testing.T, itr storage.StoreIterator) map[string]string {
+	defer itr.Release()
+
+	values := make(map[string]string)
+
+	for itr.Next() {
+		values[string(itr.Key())] = string(itr.Value())
+	}
+
+	require.NoError(t, itr.Error())
+
+	return values
+}
+
+func TestMemStoreBatch(t *testing.T) {
+	prov := NewProvider()
+	store, err := prov.OpenStore("test")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1")))
+
+	t.Run("Test mem store batch", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{
+			{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state", Value: "completed"}}},
+			{Key: "key1"},
+			{Key: "key3", Value: []byte("value3")},
+		})
+		require.NoError(t, err)
+
+		_, err = store.Get("key1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		v, err := store.Get("key2")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value2"), v)
+
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, itr))
+	})
+
+	t.Run("Test mem store batch - invalid operation fails the whole batch", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{
+			{Key: "key4", Value: []byte("value4")},
+			{Value: []byte("value5")},
+		})
+		require.EqualError(t, err, "key is mandatory")
+
+		_, err = store.Get("key4")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+}
+
+func TestWithExpiryInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		prov := NewProvider(WithExpiryInterval(interval))
+		require.Equal(t, defaultExpiryInterval, prov.expiryInterval)
+
+		store, err := prov.OpenStore("test-interval")
+		require.NoError(t, err)
+		require.NoError(t, store.Batch([]storage.Operation{{Key: "key1", Value: []byte("value1"), TTL: time.Hour}}))
+		require.NoError(t, prov.Close())
+	}
+
+	require.Equal(t, time.Second, NewProvider(WithExpiryInterval(time.Second)).expiryInterval)
+}
+
+func TestMemStoreTTL(t *testing.T) {
+	prov := NewProvider(WithExpiryInterval(10 * time.Millisecond))
+
+	store, err := prov.OpenStore("test-ttl")
+	require.NoError(t, err)
+
+	t.Run("Test mem store ttl - expired records are removed along with their tags", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key1", Value: []byte("value1"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Millisecond},
+			{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Hour},
+			{Key: "key3", Value: []byte("value3"), Tags: []storage.Tag{{Name: "state"}}},
+		}))
+
+		require.Eventually(t, func() bool {
+			_, err := store.Get("key1")
+			return errors.Is(err, storage.ErrDataNotFound)
+		}, time.Second, 10*time.Millisecond)
+
+		itr, err := store.Query("state")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2", "key3": "value3"}, readAll(t, itr))
+	})
+
+	t.Run("Test mem store ttl - putting a record again clears its ttl", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{{Key: "key4", Value: []byte("value4"), TTL: time.Millisecond}}))
+		require.NoError(t, store.Put("key4", []byte("value4")))
+
+		time.Sleep(50 * time.Millisecond)
+
+		v, err := store.Get("key4")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value4"), v)
+	})
+
+	t.Run("Test mem store ttl - expired records are reported as deleted", func(t *testing.T) {
+		changes := make(chan storage.Change, 1)
+		require.NoError(t, prov.RegisterChangeEvent(changes))
+		require.NoError(t, store.Batch([]storage.Operation{{Key: "key5", Value: []byte("value5"), TTL: time.Millisecond}}))
+		require.Equal(t, storage.Change{Store: "test-ttl", Key: "key5", Operation: storage.ChangePut}, <-changes)
+
+		select {
+		case change := <-changes:
+			require.Equal(t, storage.Change{Store: "test-ttl", Key: "key5", Operation: storage.ChangeDelete}, change)
+		case <-time.After(time.Second):
+			require.Fail(t, "expiry of key5 wasn't reported")
+		}
+
+		require.NoError(t, prov.UnregisterChangeEvent(changes))
+	})
+
+	t.Run("Test mem store ttl - expired records aren't read before they're removed", func(t *testing.T) {
+		store, err := NewProvider(WithExpiryInterval(time.Hour)).OpenStore("test-ttl")
+		require.NoError(t, err)
+
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key6", Value: []byte("value6"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Millisecond},
+			{Key: "key7", Value: []byte("value7"), Tags: []storage.Tag{{Name: "state"}}},
+		}))
+
+		time.Sleep(10 * time.Millisecond)
+
+		_, err = store.Get("key6")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = store.GetTags("key6")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		itr, err := store.Query("state")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key7": "value7"}, readAll(t, itr))
+		require.Equal(t, map[string]string{"key7": "value7"}, readAll(t, store.Iterator("key", "key~")))
+	})
+
+	t.Run("Test mem store ttl - closing the store stops the expiry", func(t *testing.T) {
+		require.NotNil(t, store.(*memStore).stopExpiry)
+		require.NoError(t, prov.CloseStore("test-ttl"))
+		require.Nil(t, store.(*memStore).stopExpiry)
+	})
+}
+
+func TestMemStoreConformance(t *testing.T) {
+	storagetest.TestAll(t, NewProvider())
+}
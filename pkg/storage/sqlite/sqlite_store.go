@@ -0,0 +1,648 @@
+// +build !js,!wasm
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	// registers the sqlite3 database/sql driver.
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/internal/changefeed"
+)
+
+var logger = log.New("aries-framework/storage/sqlite")
+
+const (
+	driverName = "sqlite3"
+
+	// WAL journaling lets readers proceed while a batch is being written, busy timeout makes concurrent writers
+	// wait for each other instead of failing and immediate transactions take the write lock upfront.
+	dsnPattern = "file:%s?_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+
+	// all stores share the same tables, keys are compared with the default BINARY collation so that records
+	// are ordered byte-wise like in leveldb.
+	createRecordsTable = `CREATE TABLE IF NOT EXISTS records (
+		store TEXT NOT NULL,
+		key TEXT NOT NULL,
+		value BLOB NOT NULL,
+		PRIMARY KEY (store, key)
+	) WITHOUT ROWID`
+	createTagsTable = `CREATE TABLE IF NOT EXISTS tags (
+		store TEXT NOT NULL,
+		key TEXT NOT NULL,
+		name TEXT NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY (store, key, name, value)
+	) WITHOUT ROWID`
+	createTagsIndex = `CREATE INDEX IF NOT EXISTS tags_by_name ON tags (store, name, value)`
+	// the expiry (unix nanoseconds) of the records written with a TTL, in a table of its own so that
+	// databases created before expiry was supported get it without altering their records.
+	createExpiriesTable = `CREATE TABLE IF NOT EXISTS expiries (
+		store TEXT NOT NULL,
+		key TEXT NOT NULL,
+		expiry INTEGER NOT NULL,
+		PRIMARY KEY (store, key)
+	) WITHOUT ROWID`
+	createExpiriesIndex = `CREATE INDEX IF NOT EXISTS expiries_by_time ON expiries (expiry)`
+
+	// expired records are skipped until the expiry loop removes them.
+	unexpired = ` AND NOT EXISTS (SELECT 1 FROM expiries e WHERE e.store = r.store AND e.key = r.key` +
+		` AND e.expiry <= ?)`
+
+	getRecord      = `SELECT value FROM records r WHERE store = ? AND key = ?` + unexpired
+	putRecord      = `INSERT OR REPLACE INTO records (store, key, value) VALUES (?, ?, ?)`
+	deleteRecord   = `DELETE FROM records WHERE store = ? AND key = ?`
+	putTag         = `INSERT OR IGNORE INTO tags (store, key, name, value) VALUES (?, ?, ?, ?)`
+	deleteTags     = `DELETE FROM tags WHERE store = ? AND key = ?`
+	getTags        = `SELECT name, value FROM tags WHERE store = ? AND key = ? ORDER BY name, value`
+	putExpiry      = `INSERT OR REPLACE INTO expiries (store, key, expiry) VALUES (?, ?, ?)`
+	deleteExpiry   = `DELETE FROM expiries WHERE store = ? AND key = ?`
+	anyExpiry      = `SELECT 1 FROM expiries LIMIT 1`
+	expiredRecords = `SELECT store, key FROM expiries WHERE expiry <= ? ORDER BY store, key`
+	rangeRecords   = `SELECT key, value FROM records r WHERE store = ? AND key >= ? AND key < ?` + unexpired +
+		` ORDER BY key`
+	queryRecords = `SELECT key, value FROM records r WHERE store = ?` + unexpired
+	tagCondition = ` AND EXISTS (SELECT 1 FROM tags t WHERE t.store = r.store AND t.key = r.key AND t.name = ?`
+
+	// defaultExpiryInterval is how often records written with a TTL are checked for expiry.
+	defaultExpiryInterval = time.Minute
+)
+
+// Provider SQLite implementation of storage.Provider interface. All its stores live in a single database file.
+// Records written with a TTL are skipped once expired and removed from the database every expiry interval.
+// Its change feed only reports the writes made through the provider, not those of other database connections.
+type Provider struct {
+	dbPath         string
+	db             *sql.DB
+	expiry         *expiryLoop
+	expiryInterval time.Duration
+	dbs            map[string]*sqliteStore
+	feed           *changefeed.Feed
+	lock           sync.RWMutex
+}
+
+// Option configures the sqlite provider.
+type Option func(p *Provider)
+
+// WithExpiryInterval sets how often the expired records are removed from the database, one minute by default.
+// A zero or negative interval keeps the default.
+func WithExpiryInterval(interval time.Duration) Option {
+	return func(p *Provider) {
+		if interval > 0 {
+			p.expiryInterval = interval
+		}
+	}
+}
+
+// NewProvider instantiates Provider, the database file at dbPath is opened (and created if needed)
+// along with the first store.
+func NewProvider(dbPath string, opts ...Option) *Provider {
+	p := &Provider{
+		dbs:            make(map[string]*sqliteStore),
+		dbPath:         dbPath,
+		feed:           &changefeed.Feed{},
+		expiryInterval: defaultExpiryInterval,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// OpenStore opens and returns a store for given name space.
+func (p *Provider) OpenStore(name string) (storage.Store, error) {
+	store := p.getSQLiteStore(name)
+	if store == nil {
+		return p.newSQLiteStore(name)
+	}
+
+	return store, nil
+}
+
+// getSQLiteStore finds sqlite store with given name
+// returns nil if not found
+func (p *Provider) getSQLiteStore(name string) *sqliteStore {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.dbs[strings.ToLower(name)]
+}
+
+// newSQLiteStore creates sqlite store for given name space, opening the database if needed.
+func (p *Provider) newSQLiteStore(name string) (*sqliteStore, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.db == nil {
+		db, err := openDB(p.dbPath)
+		if err != nil {
+			return nil, err
+		}
+
+		p.db = db
+		p.expiry = &expiryLoop{db: db, feed: p.feed, interval: p.expiryInterval}
+
+		// records written with a TTL before the database was last closed still have to expire
+		var found int
+
+		err = db.QueryRow(anyExpiry).Scan(&found)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to read expiries: %w", err)
+		}
+
+		if err == nil {
+			p.expiry.start()
+		}
+	}
+
+	store := &sqliteStore{db: p.db, name: strings.ToLower(name), feed: p.feed, expiry: p.expiry}
+	p.dbs[store.name] = store
+
+	return store, nil
+}
+
+func openDB(dbPath string) (*sql.DB, error) {
+	if dbPath == "" {
+		return nil, errors.New("database path is mandatory")
+	}
+
+	db, err := sql.Open(driverName, fmt.Sprintf(dsnPattern, dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	for _, stmt := range []string{
+		createRecordsTable, createTagsTable, createTagsIndex, createExpiriesTable, createExpiriesIndex,
+	} {
+		if _, err = db.Exec(stmt); err != nil {
+			// the database is of no use without its schema
+			_ = db.Close() // nolint: errcheck
+
+			return nil, fmt.Errorf("failed to create database schema: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+// Close closes all stores created under this store provider, along with the database.
+func (p *Provider) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.dbs = make(map[string]*sqliteStore)
+
+	if p.db == nil {
+		return nil
+	}
+
+	// the expiry loop may be removing records, it has to be done before the database is closed
+	p.expiry.stop()
+
+	db := p.db
+	p.db = nil
+
+	if err := db.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %w", err)
+	}
+
+	return nil
+}
+
+// CloseStore closes sqlite store of given name. The database stays open for the other stores.
+func (p *Provider) CloseStore(name string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.dbs, strings.ToLower(name))
+
+	return nil
+}
+
+// RegisterChangeEvent registers a channel receiving the changes of every store of the provider.
+func (p *Provider) RegisterChangeEvent(ch chan<- storage.Change) error {
+	return p.feed.RegisterChangeEvent(ch)
+}
+
+// UnregisterChangeEvent stops sending changes to a channel.
+func (p *Provider) UnregisterChangeEvent(ch chan<- storage.Change) error {
+	return p.feed.UnregisterChangeEvent(ch)
+}
+
+type sqliteStore struct {
+	db     *sql.DB
+	name   string
+	feed   *changefeed.Feed
+	expiry *expiryLoop
+}
+
+// Put stores the key and the record along with optional tags
+func (s *sqliteStore) Put(k string, v []byte, tags ...storage.Tag) error {
+	if k == "" || v == nil {
+		return errors.New("key and value are mandatory")
+	}
+
+	return s.Batch([]storage.Operation{{Key: k, Value: v, Tags: tags}})
+}
+
+// Batch writes the given operations, along with their tags, in a single transaction.
+func (s *sqliteStore) Batch(operations []storage.Operation) error {
+	if err := storage.ValidateOperations(operations); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	now := time.Now()
+	withTTL := false
+
+	for _, op := range operations {
+		withTTL = withTTL || op.Value != nil && op.TTL > 0
+
+		if err = s.write(tx, op, now); err != nil {
+			// the write error is the one worth reporting
+			_ = tx.Rollback() // nolint: errcheck
+
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if withTTL {
+		s.expiry.start()
+	}
+
+	s.feed.Notify(s.name, operations)
+
+	return nil
+}
+
+func (s *sqliteStore) write(tx *sql.Tx, op storage.Operation, now time.Time) error {
+	if _, err := tx.Exec(deleteTags, s.name, op.Key); err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+
+	if _, err := tx.Exec(deleteExpiry, s.name, op.Key); err != nil {
+		return fmt.Errorf("failed to delete expiry: %w", err)
+	}
+
+	if op.Value == nil {
+		if _, err := tx.Exec(deleteRecord, s.name, op.Key); err != nil {
+			return fmt.Errorf("failed to delete data: %w", err)
+		}
+
+		return nil
+	}
+
+	if _, err := tx.Exec(putRecord, s.name, op.Key, op.Value); err != nil {
+		return fmt.Errorf("failed to store data: %w", err)
+	}
+
+	for _, tag := range op.Tags {
+		if _, err := tx.Exec(putTag, s.name, op.Key, tag.Name, tag.Value); err != nil {
+			return fmt.Errorf("failed to store tags: %w", err)
+		}
+	}
+
+	if op.TTL > 0 {
+		if _, err := tx.Exec(putExpiry, s.name, op.Key, now.Add(op.TTL).UnixNano()); err != nil {
+			return fmt.Errorf("failed to store expiry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Get fetches the record based on key
+func (s *sqliteStore) Get(k string) ([]byte, error) {
+	if k == "" {
+		return nil, errors.New("key is mandatory")
+	}
+
+	var data []byte
+
+	err := s.db.QueryRow(getRecord, s.name, k, time.Now().UnixNano()).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, storage.ErrDataNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data: %w", err)
+	}
+
+	return data, nil
+}
+
+// GetTags fetches the tags stored along with the record of key k
+func (s *sqliteStore) GetTags(k string) ([]storage.Tag, error) {
+	if _, err := s.Get(k); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(getTags, s.name, k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	defer func() {
+		// the rows are fully read already
+		_ = rows.Close() // nolint: errcheck
+	}()
+
+	var tags []storage.Tag
+
+	for rows.Next() {
+		var tag storage.Tag
+
+		if err = rows.Scan(&tag.Name, &tag.Value); err != nil {
+			return nil, fmt.Errorf("failed to read tags: %w", err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// Iterator returns iterator over the records with keys in range [start, limit), ordered by key.
+// As with leveldb, a limit ending with storage.EndKeySuffix covers every key starting with the rest of it.
+func (s *sqliteStore) Iterator(start, limit string) storage.StoreIterator {
+	if start == "" || limit == "" {
+		return &sqliteIterator{err: errors.New("start or limit key is mandatory")}
+	}
+
+	return s.query(rangeRecords, s.name, start, strings.ReplaceAll(limit, storage.EndKeySuffix, "~"),
+		time.Now().UnixNano())
+}
+
+// Delete will delete record with k key
+func (s *sqliteStore) Delete(k string) error {
+	if k == "" {
+		return errors.New("key is mandatory")
+	}
+
+	return s.Batch([]storage.Operation{{Key: k}})
+}
+
+// Query returns iterator over the records whose tags match the given expression, ordered by key.
+func (s *sqliteStore) Query(expression string) (storage.StoreIterator, error) {
+	terms, err := storage.ParseQuery(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := queryRecords
+	args := []interface{}{s.name, time.Now().UnixNano()}
+
+	for _, term := range terms {
+		stmt += tagCondition
+		args = append(args, term.Name)
+
+		if term.Value != "" {
+			stmt += " AND t.value = ?"
+			args = append(args, term.Value)
+		}
+
+		stmt += ")"
+	}
+
+	itr := s.query(stmt+" ORDER BY key", args...)
+	if itr.err != nil {
+		return nil, itr.err
+	}
+
+	return itr, nil
+}
+
+// expiryLoop removes the expired records of every store of a database, it's started along with the first record
+// written with a TTL.
+type expiryLoop struct {
+	db       *sql.DB
+	feed     *changefeed.Feed
+	interval time.Duration
+	lock     sync.Mutex
+	stopped  chan struct{}
+	done     chan struct{}
+}
+
+// start starts the loop unless it's running already.
+func (e *expiryLoop) start() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.stopped != nil {
+		return
+	}
+
+	e.stopped = make(chan struct{})
+	e.done = make(chan struct{})
+
+	go e.run(e.stopped, e.done)
+}
+
+// stop stops the loop, waiting for any ongoing expiry to complete. The loop isn't started again after.
+func (e *expiryLoop) stop() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.stopped == nil {
+		e.stopped = make(chan struct{})
+		close(e.stopped)
+
+		return
+	}
+
+	select {
+	case <-e.stopped:
+	default:
+		close(e.stopped)
+		<-e.done
+	}
+}
+
+// run removes the expired records every expiry interval, until stop is closed.
+func (e *expiryLoop) run(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			expired, err := e.expire(now)
+			if err != nil {
+				logger.Warnf("failed to remove expired records: %s", err)
+			}
+
+			for store, keys := range expired {
+				e.feed.NotifyDelete(store, keys)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// expire removes the records which expired by the given time, along with their tags and expiry,
+// and returns their keys by store.
+func (e *expiryLoop) expire(now time.Time) (map[string][]string, error) {
+	tx, err := e.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	expired, err := expiredKeys(tx, now)
+	if err == nil {
+		err = deleteExpired(tx, expired)
+	}
+
+	if err != nil {
+		// the read or delete error is the one worth reporting
+		_ = tx.Rollback() // nolint: errcheck
+
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return expired, nil
+}
+
+func expiredKeys(tx *sql.Tx, now time.Time) (map[string][]string, error) {
+	rows, err := tx.Query(expiredRecords, now.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expiries: %w", err)
+	}
+
+	defer func() {
+		// the rows are fully read already
+		_ = rows.Close() // nolint: errcheck
+	}()
+
+	expired := make(map[string][]string)
+
+	for rows.Next() {
+		var store, key string
+
+		if err = rows.Scan(&store, &key); err != nil {
+			return nil, fmt.Errorf("failed to read expiries: %w", err)
+		}
+
+		expired[store] = append(expired[store], key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read expiries: %w", err)
+	}
+
+	return expired, nil
+}
+
+func deleteExpired(tx *sql.Tx, expired map[string][]string) error {
+	for store, keys := range expired {
+		for _, key := range keys {
+			for _, stmt := range []string{deleteTags, deleteExpiry, deleteRecord} {
+				if _, err := tx.Exec(stmt, store, key); err != nil {
+					return fmt.Errorf("failed to delete expired data: %w", err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *sqliteStore) query(stmt string, args ...interface{}) *sqliteIterator {
+	rows, err := s.db.Query(stmt, args...)
+	if err != nil {
+		return &sqliteIterator{err: fmt.Errorf("failed to query data: %w", err)}
+	}
+
+	return &sqliteIterator{rows: rows}
+}
+
+// sqliteIterator reads the rows of a query as it moves, the read transaction keeps
+// a consistent snapshot of the database until the iterator is exhausted or released.
+type sqliteIterator struct {
+	rows  *sql.Rows
+	key   []byte
+	value []byte
+	err   error
+}
+
+// Next moves the iterator to the next record.
+func (i *sqliteIterator) Next() bool {
+	i.key, i.value = nil, nil
+
+	if i.rows == nil || i.err != nil {
+		return false
+	}
+
+	if !i.rows.Next() {
+		i.err = i.rows.Err()
+		i.Release()
+
+		return false
+	}
+
+	if err := i.rows.Scan(&i.key, &i.value); err != nil {
+		i.err = fmt.Errorf("failed to read data: %w", err)
+		i.Release()
+
+		return false
+	}
+
+	return true
+}
+
+// Release releases the underlying rows.
+func (i *sqliteIterator) Release() {
+	if i.rows != nil {
+		// closing rows which are already closed is a no-op
+		_ = i.rows.Close() // nolint: errcheck
+	}
+}
+
+// Error returns any accumulated error.
+func (i *sqliteIterator) Error() error {
+	return i.err
+}
+
+// Key returns the key of the current record, or nil if done.
+func (i *sqliteIterator) Key() []byte {
+	return i.key
+}
+
+// Value returns the value of the current record, or nil if done.
+func (i *sqliteIterator) Value() []byte {
+	return i.value
+}
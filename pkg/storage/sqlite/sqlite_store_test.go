@@ -0,0 +1,531 @@
+// +build !js,!wasm
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	storagetest "github.com/hyperledger/aries-framework-go/pkg/storage/test"
+)
+
+func setupSQLite(t testing.TB) (string, func()) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatalf("Failed to create sqlite directory: %s", err)
+	}
+
+	return filepath.Join(dir, "aries.db"), func() {
+		err := os.RemoveAll(dir)
+		if err != nil {
+			t.Fatalf("Failed to clear sqlite directory: %s", err)
+		}
+	}
+}
+
+func TestSQLiteStore(t *testing.T) {
+	path, cleanup := setupSQLite(t)
+	defer cleanup()
+
+	t.Run("Test sqlite store put and get", func(t *testing.T) {
+		prov := NewProvider(path)
+		store, err := prov.OpenStore("test")
+		require.NoError(t, err)
+
+		const key = "did:example:123"
+		data := []byte("value")
+
+		err = store.Put(key, data)
+		require.NoError(t, err)
+
+		doc, err := store.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, data, doc)
+
+		// overwrite
+		err = store.Put(key, []byte("value2"))
+		require.NoError(t, err)
+
+		doc, err = store.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, []byte("value2"), doc)
+
+		_, err = store.Get("did:example:789")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		// nil key
+		_, err = store.Get("")
+		require.Error(t, err)
+
+		// nil value
+		err = store.Put(key, nil)
+		require.Error(t, err)
+
+		// nil key
+		err = store.Put("", data)
+		require.Error(t, err)
+
+		err = prov.Close()
+		require.NoError(t, err)
+
+		// try to get after provider is closed
+		_, err = store.Get(key)
+		require.Error(t, err)
+
+		// data is still there once the database is opened again
+		store, err = prov.OpenStore("test")
+		require.NoError(t, err)
+
+		doc, err = store.Get(key)
+		require.NoError(t, err)
+		require.Equal(t, []byte("value2"), doc)
+
+		require.NoError(t, prov.Close())
+		require.NoError(t, prov.Close())
+	})
+
+	t.Run("Test sqlite multi store put and get", func(t *testing.T) {
+		prov := NewProvider(path)
+		defer func() { require.NoError(t, prov.Close()) }()
+
+		const commonKey = "did:example:1"
+		data := []byte("value1")
+
+		store1, err := prov.OpenStore("store1")
+		require.NoError(t, err)
+
+		store2, err := prov.OpenStore("store2")
+		require.NoError(t, err)
+
+		err = store1.Put(commonKey, data)
+		require.NoError(t, err)
+
+		doc, err := store1.Get(commonKey)
+		require.NoError(t, err)
+		require.Equal(t, data, doc)
+
+		// get in store 2 - not found
+		_, err = store2.Get(commonKey)
+		require.Equal(t, storage.ErrDataNotFound, err)
+
+		err = store2.Put(commonKey, data)
+		require.NoError(t, err)
+
+		// create new store 3 with same name as store1
+		store3, err := prov.OpenStore("store1")
+		require.NoError(t, err)
+
+		doc, err = store3.Get(commonKey)
+		require.NoError(t, err)
+		require.Equal(t, data, doc)
+
+		require.Len(t, prov.dbs, 2)
+
+		require.NoError(t, prov.CloseStore("store1"))
+		require.NoError(t, prov.CloseStore("store3"))
+		require.Len(t, prov.dbs, 1)
+	})
+
+	t.Run("Test sqlite store failures", func(t *testing.T) {
+		prov := NewProvider("")
+
+		_, err := prov.OpenStore("test")
+		require.EqualError(t, err, "database path is mandatory")
+
+		prov = NewProvider(filepath.Join(path+"-missing", "aries.db"))
+
+		_, err = prov.OpenStore("test")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to create database schema")
+	})
+
+	t.Run("Test sqlite store iterator", func(t *testing.T) {
+		prov := NewProvider(path)
+		defer func() { require.NoError(t, prov.Close()) }()
+
+		store, err := prov.OpenStore("iterator")
+		require.NoError(t, err)
+
+		const valPrefix = "val-for-%s"
+		keys := []string{"abc_123", "abc_124", "abc_125", "abc_126", "jkl_123", "mno_123", "dab_123"}
+
+		for _, key := range keys {
+			err = store.Put(key, []byte(fmt.Sprintf(valPrefix, key)))
+			require.NoError(t, err)
+		}
+
+		itr := store.Iterator("abc_", "abc_"+storage.EndKeySuffix)
+		verifyItr(t, itr, 4, "abc_")
+
+		itr = store.Iterator("", "")
+		verifyItr(t, itr, 0, "")
+		require.Error(t, itr.Error())
+
+		itr = store.Iterator("abc_", "mno_"+storage.EndKeySuffix)
+		verifyItr(t, itr, 7, "")
+
+		itr = store.Iterator("abc_", "mno_123")
+		verifyItr(t, itr, 6, "")
+
+		// records are ordered by key
+		itr = store.Iterator("a", "z")
+
+		var got []string
+
+		for itr.Next() {
+			got = append(got, string(itr.Key()))
+		}
+
+		require.NoError(t, itr.Error())
+		require.Equal(t, []string{"abc_123", "abc_124", "abc_125", "abc_126", "dab_123", "jkl_123", "mno_123"}, got)
+		require.Nil(t, itr.Key())
+		require.Nil(t, itr.Value())
+
+		itr.Release()
+		itr.Release()
+	})
+}
+
+func verifyItr(t *testing.T, itr storage.StoreIterator, count int, prefix string) {
+	defer itr.Release()
+
+	var vals []string
+
+	for itr.Next() {
+		if prefix != "" {
+			require.Contains(t, string(itr.Key()), prefix)
+		}
+
+		vals = append(vals, string(itr.Value()))
+	}
+
+	require.Len(t, vals, count)
+}
+
+func TestSQLiteStoreDelete(t *testing.T) {
+	path, cleanup := setupSQLite(t)
+	defer cleanup()
+
+	prov := NewProvider(path)
+	defer func() { require.NoError(t, prov.Close()) }()
+
+	store, err := prov.OpenStore("test")
+	require.NoError(t, err)
+
+	const commonKey = "did:example:1234"
+
+	require.NoError(t, store.Put(commonKey, []byte("value1"), storage.Tag{Name: "state", Value: "completed"}))
+
+	require.NoError(t, store.Delete(commonKey))
+
+	_, err = store.Get(commonKey)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+	itr, err := store.Query("state")
+	require.NoError(t, err)
+	require.Empty(t, readAll(t, itr))
+
+	// deleting a missing key is not an error
+	require.NoError(t, store.Delete(commonKey))
+
+	require.EqualError(t, store.Delete(""), "key is mandatory")
+}
+
+func TestSQLiteStoreQuery(t *testing.T) {
+	path, cleanup := setupSQLite(t)
+	defer cleanup()
+
+	prov := NewProvider(path)
+	defer func() { require.NoError(t, prov.Close()) }()
+
+	store, err := prov.OpenStore("test")
+	require.NoError(t, err)
+
+	other, err := prov.OpenStore("other")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "completed"}))
+	require.NoError(t, store.Put("key2", []byte("value2"),
+		storage.Tag{Name: "state", Value: "invited"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key3", []byte("value3"),
+		storage.Tag{Name: "state", Value: "completed"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key4", []byte("value4")))
+	require.NoError(t, other.Put("key5", []byte("value5"), storage.Tag{Name: "state", Value: "completed"}))
+
+	t.Run("Test sqlite store query", func(t *testing.T) {
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key1": "value1", "key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("type")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2", "key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("state:completed && type:a")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("state:unknown")
+		require.NoError(t, err)
+		require.Empty(t, readAll(t, itr))
+	})
+
+	t.Run("Test sqlite store query - tags are replaced on put", func(t *testing.T) {
+		require.NoError(t, store.Put("key1", []byte("value1.1"), storage.Tag{Name: "state", Value: "abandoned"}))
+
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key3": "value3"}, readAll(t, itr))
+
+		itr, err = store.Query("state:abandoned")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key1": "value1.1"}, readAll(t, itr))
+	})
+
+	t.Run("Test sqlite store query - invalid expression", func(t *testing.T) {
+		_, err := store.Query("")
+		require.Error(t, err)
+
+		_, err = store.Query("state:completed && ")
+		require.Error(t, err)
+	})
+}
+
+func readAll(t *testing.T, itr storage.StoreIterator) map[string]string {
+	defer itr.Release()
+
+	records := make(map[string]string)
+
+	for itr.Next() {
+		records[string(itr.Key())] = string(itr.Value())
+	}
+
+	require.NoError(t, itr.Error())
+
+	return records
+}
+
+func TestSQLiteStoreBatch(t *testing.T) {
+	path, cleanup := setupSQLite(t)
+	defer cleanup()
+
+	prov := NewProvider(path)
+	defer func() { require.NoError(t, prov.Close()) }()
+
+	store, err := prov.OpenStore("test")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "invited"}))
+
+	t.Run("Test sqlite store batch", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{
+			{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state", Value: "invited"}}},
+			{Key: "key1"},
+			{Key: "key3", Value: []byte("value3")},
+		})
+		require.NoError(t, err)
+
+		_, err = store.Get("key1")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		v, err := store.Get("key3")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value3"), v)
+
+		itr, err := store.Query("state:invited")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, itr))
+	})
+
+	t.Run("Test sqlite store batch - same key written twice", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{
+			{Key: "key4", Value: []byte("value4"), Tags: []storage.Tag{{Name: "state", Value: "invited"}}},
+			{Key: "key4", Value: []byte("value4.1"), Tags: []storage.Tag{{Name: "state", Value: "completed"}}},
+		})
+		require.NoError(t, err)
+
+		itr, err := store.Query("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key4": "value4.1"}, readAll(t, itr))
+	})
+
+	t.Run("Test sqlite store batch - invalid operation fails the whole batch", func(t *testing.T) {
+		err := store.Batch([]storage.Operation{
+			{Key: "key5", Value: []byte("value5")},
+			{Value: []byte("value6")},
+		})
+		require.EqualError(t, err, "key is mandatory")
+
+		_, err = store.Get("key5")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+
+	t.Run("Test sqlite store batch - concurrent writers", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				require.NoError(t, store.Put(fmt.Sprintf("concurrent_%d", i), []byte("value")))
+			}(i)
+		}
+
+		wg.Wait()
+
+		itr := store.Iterator("concurrent_", "concurrent_"+storage.EndKeySuffix)
+		verifyItr(t, itr, 10, "concurrent_")
+	})
+}
+
+func TestWithExpiryInterval(t *testing.T) {
+	path, cleanup := setupSQLite(t)
+	defer cleanup()
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		prov := NewProvider(path, WithExpiryInterval(interval))
+		require.Equal(t, defaultExpiryInterval, prov.expiryInterval)
+
+		store, err := prov.OpenStore("test-interval")
+		require.NoError(t, err)
+		require.NoError(t, store.Batch([]storage.Operation{{Key: "key1", Value: []byte("value1"), TTL: time.Hour}}))
+		require.NoError(t, prov.Close())
+	}
+
+	require.Equal(t, time.Second, NewProvider(path, WithExpiryInterval(time.Second)).expiryInterval)
+}
+
+func TestSQLiteStoreTTL(t *testing.T) {
+	path, cleanup := setupSQLite(t)
+	defer cleanup()
+
+	prov := NewProvider(path, WithExpiryInterval(10*time.Millisecond))
+	defer func() { require.NoError(t, prov.Close()) }()
+
+	store, err := prov.OpenStore("test-ttl")
+	require.NoError(t, err)
+
+	t.Run("Test sqlite store ttl - expired records are removed along with their tags", func(t *testing.T) {
+		changes := make(chan storage.Change, 10)
+		require.NoError(t, prov.RegisterChangeEvent(changes))
+
+		defer func() { require.NoError(t, prov.UnregisterChangeEvent(changes)) }()
+
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key1", Value: []byte("value1"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Millisecond},
+			{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Hour},
+			{Key: "key3", Value: []byte("value3"), Tags: []storage.Tag{{Name: "state"}}},
+		}))
+
+		// expired records aren't read before they're removed, so wait for their removal to be reported
+		require.Eventually(t, func() bool {
+			for {
+				select {
+				case change := <-changes:
+					if change.Operation == storage.ChangeDelete && change.Key == "key1" {
+						return true
+					}
+				default:
+					return false
+				}
+			}
+		}, time.Second, 10*time.Millisecond)
+
+		var count int
+
+		require.NoError(t, prov.db.QueryRow(`SELECT COUNT(*) FROM tags WHERE key = 'key1'`).Scan(&count))
+		require.Zero(t, count)
+
+		itr, err := store.Query("state")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key2": "value2", "key3": "value3"}, readAll(t, itr))
+	})
+
+	t.Run("Test sqlite store ttl - putting a record again clears its ttl", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key4", Value: []byte("value4"), TTL: time.Millisecond},
+			{Key: "key4", Value: []byte("value4")},
+		}))
+
+		time.Sleep(50 * time.Millisecond)
+
+		v, err := store.Get("key4")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value4"), v)
+	})
+
+	t.Run("Test sqlite store ttl - expired records aren't read before they're removed", func(t *testing.T) {
+		path, cleanup := setupSQLite(t)
+		defer cleanup()
+
+		prov := NewProvider(path, WithExpiryInterval(time.Hour))
+		defer func() { require.NoError(t, prov.Close()) }()
+
+		store, err := prov.OpenStore("test-ttl")
+		require.NoError(t, err)
+
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key6", Value: []byte("value6"), Tags: []storage.Tag{{Name: "state"}}, TTL: time.Millisecond},
+			{Key: "key7", Value: []byte("value7"), Tags: []storage.Tag{{Name: "state"}}},
+		}))
+
+		time.Sleep(10 * time.Millisecond)
+
+		_, err = store.Get("key6")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = store.GetTags("key6")
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		itr, err := store.Query("state")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key7": "value7"}, readAll(t, itr))
+		require.Equal(t, map[string]string{"key7": "value7"}, readAll(t, store.Iterator("key", "key~")))
+	})
+
+	t.Run("Test sqlite store ttl - records expire after the database is reopened", func(t *testing.T) {
+		require.NoError(t, store.Batch([]storage.Operation{
+			{Key: "key5", Value: []byte("value5"), TTL: 50 * time.Millisecond},
+		}))
+		require.NoError(t, prov.Close())
+
+		store, err = prov.OpenStore("test-ttl")
+		require.NoError(t, err)
+		require.NotNil(t, prov.expiry.stopped)
+
+		require.Eventually(t, func() bool {
+			var count int
+
+			require.NoError(t, prov.db.QueryRow(`SELECT COUNT(*) FROM records WHERE key = 'key5'`).Scan(&count))
+
+			return count == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestSQLiteStoreConformance(t *testing.T) {
+	path, cleanup := setupSQLite(t)
+	defer cleanup()
+
+	prov := NewProvider(path)
+	defer func() { require.NoError(t, prov.Close()) }()
+
+	storagetest.TestAll(t, prov)
+}
@@ -6,14 +6,55 @@ SPDX-License-Identifier: Apache-2.0
 
 package storage
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
-// EndKeySuffix end key suffix
-const EndKeySuffix = "!!"
+const (
+	// EndKeySuffix end key suffix
+	EndKeySuffix = "!!"
+
+	// TagNameValueSeparator separates a tag name from its value in a query expression
+	TagNameValueSeparator = ":"
+
+	// TagQueryOperatorAnd joins the terms of a query expression, all of which must match
+	TagQueryOperatorAnd = "&&"
+
+	// tagReservedCharacter can't be used in tag names and values, providers use it to separate them in their indexes
+	tagReservedCharacter = "\x00"
+)
 
 // ErrDataNotFound is returned when data not found
 var ErrDataNotFound = errors.New("data not found")
 
+// ErrChangeFeedNotSupported is returned when changes are requested from a provider which doesn't report them.
+var ErrChangeFeedNotSupported = errors.New("storage provider doesn't support change feed")
+
+// ErrTTLNotSupported is returned when an operation with a TTL is applied to a store which can't expire records.
+var ErrTTLNotSupported = errors.New("storage provider doesn't support record expiry")
+
+// Tag is a name/value pair attached to a stored record which can be used to find the record through Store.Query.
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// Operation is a single write of a batch: it puts Value along with Tags under Key,
+// or deletes Key when Value is nil.
+//
+// A positive TTL makes the record expire once that duration has elapsed, zero keeps it until deleted.
+// Expired records are removed in the background and are no longer read once they expire.
+// Providers without expiry support reject such operations with ErrTTLNotSupported.
+type Operation struct {
+	Key   string
+	Value []byte
+	Tags  []Tag
+	TTL   time.Duration
+}
+
 // Provider storage provider interface
 type Provider interface {
 	// OpenStore opens a store with given name space and returns the handle
@@ -26,29 +67,81 @@ type Provider interface {
 	Close() error
 }
 
+// ChangeOperation is the kind of write reported by a change feed.
+type ChangeOperation string
+
+const (
+	// ChangePut is reported when a record is put.
+	ChangePut ChangeOperation = "put"
+
+	// ChangeDelete is reported when a record is deleted or expires. Deleting a missing key is reported as well.
+	ChangeDelete ChangeOperation = "delete"
+)
+
+// Change is a write applied to a record of a store.
+type Change struct {
+	// Store is the name of the store, in lower case
+	Store     string
+	Key       string
+	Operation ChangeOperation
+}
+
+// ChangeFeed is optionally implemented by providers reporting the writes applied to the records of their stores.
+//
+// Changes are sent once the write is applied, so the changes of concurrent writes may be received in any order.
+// Writers never wait on the channels: the changes of each channel are queued, and dropped with a warning
+// while its queue is full, so registered channels must be drained promptly.
+type ChangeFeed interface {
+	// RegisterChangeEvent registers a channel receiving the changes of every store of the provider.
+	RegisterChangeEvent(ch chan<- Change) error
+
+	// UnregisterChangeEvent stops sending changes to a channel. Refer RegisterChangeEvent().
+	UnregisterChangeEvent(ch chan<- Change) error
+}
+
 // Store is the storage interface
 type Store interface {
-	// Put stores the key and the record
-	Put(k string, v []byte) error
+	// Put stores the key and the record along with optional tags.
+	// Putting an existing key replaces both the record and any tags previously stored with it.
+	Put(k string, v []byte, tags ...Tag) error
 
 	// Get fetches the record based on key
 	Get(k string) ([]byte, error)
 
+	// GetTags fetches the tags stored along with the record of key k, ErrDataNotFound is returned
+	// when there is no such record.
+	GetTags(k string) ([]Tag, error)
+
 	// Iterator returns an iterator for the latest snapshot of the
 	// underlying store
 	//
 	// Args:
 	//
 	// startKey: Start of the key range, include in the range.
-	// endKey: End of the key range, not include in the range.
+	// endKey: End of the key range, not include in the range. An endKey ending with
+	// EndKeySuffix covers every key starting with the rest of it.
+	//
+	// Both keys are mandatory, the iterator reports an error otherwise.
 	//
 	// Returns:
 	//
-	// StoreIterator: iterator for result range
+	// StoreIterator: iterator for result range, ordered by key
 	Iterator(startKey, endKey string) StoreIterator
 
 	// Delete will delete a record with k key
 	Delete(k string) error
+
+	// Batch performs the given put and delete operations, in order, as a single write.
+	// Unless stated otherwise by the provider the batch is atomic: either every operation
+	// is applied or none is.
+	Batch(operations []Operation) error
+
+	// Query returns an iterator over the records whose tags match the given expression.
+	//
+	// Expression is one or more terms joined by "&&". A term is either "name:value",
+	// matching records having tag name with the given value, or "name", matching
+	// records having tag name regardless of its value.
+	Query(expression string) (StoreIterator, error)
 }
 
 // StoreIterator is the iterator for the latest snapshot of the underlying store.
@@ -75,3 +168,95 @@ type StoreIterator interface {
 	// its contents may change on the next call to any 'seeks method'.
 	Value() []byte
 }
+
+// ParseQuery parses a tag query expression into its terms. Terms without a value
+// (tag name only) are returned with an empty Value.
+func ParseQuery(expression string) ([]Tag, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, errors.New("query expression is mandatory")
+	}
+
+	var terms []Tag
+
+	for _, term := range strings.Split(expression, TagQueryOperatorAnd) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return nil, fmt.Errorf("invalid query expression '%s': empty term", expression)
+		}
+
+		parts := strings.SplitN(term, TagNameValueSeparator, 2)
+
+		tag := Tag{Name: parts[0]}
+		if len(parts) == 2 {
+			tag.Value = parts[1]
+		}
+
+		if tag.Name == "" {
+			return nil, fmt.Errorf("invalid query expression '%s': tag name is mandatory", expression)
+		}
+
+		terms = append(terms, tag)
+	}
+
+	return terms, nil
+}
+
+// MatchTags reports whether the given record tags satisfy every term of a parsed query.
+func MatchTags(terms, tags []Tag) bool {
+	for _, term := range terms {
+		if !matchTerm(term, tags) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchTerm(term Tag, tags []Tag) bool {
+	for _, tag := range tags {
+		if tag.Name == term.Name && (term.Value == "" || tag.Value == term.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateTags checks that the given tags can be matched by a query expression and indexed by the providers.
+func ValidateTags(tags []Tag) error {
+	for _, tag := range tags {
+		if tag.Name == "" {
+			return errors.New("tag name is mandatory")
+		}
+
+		if strings.Contains(tag.Name, TagNameValueSeparator) || strings.Contains(tag.Name, TagQueryOperatorAnd) ||
+			strings.Contains(tag.Name, tagReservedCharacter) {
+			return fmt.Errorf("tag name '%s' contains a reserved character sequence", tag.Name)
+		}
+
+		if strings.Contains(tag.Value, TagQueryOperatorAnd) || strings.Contains(tag.Value, tagReservedCharacter) {
+			return fmt.Errorf("value of tag '%s' contains a reserved character sequence", tag.Name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateOperations checks that every operation of a batch has a key, valid tags and a non negative TTL.
+func ValidateOperations(operations []Operation) error {
+	for _, op := range operations {
+		if op.Key == "" {
+			return errors.New("key is mandatory")
+		}
+
+		if op.TTL < 0 {
+			return fmt.Errorf("ttl of key '%s' can't be negative", op.Key)
+		}
+
+		if err := ValidateTags(op.Tags); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
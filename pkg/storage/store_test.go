@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Run("test parse query - success", func(t *testing.T) {
+		terms, err := ParseQuery("state:completed")
+		require.NoError(t, err)
+		require.Equal(t, []Tag{{Name: "state", Value: "completed"}}, terms)
+
+		terms, err = ParseQuery("state && type:https://example.com/type:1")
+		require.NoError(t, err)
+		require.Equal(t, []Tag{{Name: "state"}, {Name: "type", Value: "https://example.com/type:1"}}, terms)
+	})
+
+	t.Run("test parse query - invalid expressions", func(t *testing.T) {
+		_, err := ParseQuery(" ")
+		require.EqualError(t, err, "query expression is mandatory")
+
+		_, err = ParseQuery("state:completed &&")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "empty term")
+
+		_, err = ParseQuery(":completed")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "tag name is mandatory")
+	})
+}
+
+func TestMatchTags(t *testing.T) {
+	tags := []Tag{{Name: "state", Value: "completed"}, {Name: "type", Value: "a"}, {Name: "type", Value: "b"}}
+
+	require.True(t, MatchTags([]Tag{{Name: "state", Value: "completed"}}, tags))
+	require.True(t, MatchTags([]Tag{{Name: "state"}}, tags))
+	require.True(t, MatchTags([]Tag{{Name: "type", Value: "a"}, {Name: "type", Value: "b"}}, tags))
+	require.False(t, MatchTags([]Tag{{Name: "state", Value: "invited"}}, tags))
+	require.False(t, MatchTags([]Tag{{Name: "type", Value: "a"}, {Name: "label"}}, tags))
+	require.False(t, MatchTags([]Tag{{Name: "state"}}, nil))
+}
+
+func TestValidateTags(t *testing.T) {
+	require.NoError(t, ValidateTags(nil))
+	require.NoError(t, ValidateTags([]Tag{{Name: "state", Value: "completed"}, {Name: "flag"}}))
+	require.EqualError(t, ValidateTags([]Tag{{Value: "completed"}}), "tag name is mandatory")
+	require.Error(t, ValidateTags([]Tag{{Name: "a:b"}}))
+	require.Error(t, ValidateTags([]Tag{{Name: "a&&b"}}))
+	require.Error(t, ValidateTags([]Tag{{Name: "a", Value: "b&&c"}}))
+	require.Error(t, ValidateTags([]Tag{{Name: "a\x00b"}}))
+	require.Error(t, ValidateTags([]Tag{{Name: "a", Value: "b\x00c"}}))
+}
+
+func TestValidateOperations(t *testing.T) {
+	require.NoError(t, ValidateOperations(nil))
+	require.NoError(t, ValidateOperations([]Operation{
+		{Key: "k1", Value: []byte("v1"), Tags: []Tag{{Name: "state", Value: "completed"}}},
+		{Key: "k2"},
+	}))
+	require.EqualError(t, ValidateOperations([]Operation{{Key: "k1"}, {Value: []byte("v2")}}), "key is mandatory")
+	require.Error(t, ValidateOperations([]Operation{{Key: "k1", Value: []byte("v1"), Tags: []Tag{{Name: "a:b"}}}}))
+	require.NoError(t, ValidateOperations([]Operation{{Key: "k1", Value: []byte("v1"), TTL: time.Minute}}))
+	require.EqualError(t, ValidateOperations([]Operation{{Key: "k1", Value: []byte("v1"), TTL: -time.Minute}}),
+		"ttl of key 'k1' can't be negative")
+}
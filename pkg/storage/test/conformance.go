@@ -0,0 +1,533 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package test provides a conformance test suite for storage.Provider implementations.
+//
+// A provider proves it behaves like every other provider by running the suite from its own tests:
+//
+//		func TestProvider(t *testing.T) {
+//			test.TestAll(t, myprovider.NewProvider())
+//		}
+//
+// Each test opens its own randomly named stores, so the suite can run against a provider holding other data.
+// Records are read back only through the provider under test, never from a previous run.
+package test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// TestAll runs the whole conformance test suite against provider.
+func TestAll(t *testing.T, provider storage.Provider) {
+	t.Run("put and get", func(t *testing.T) {
+		TestPutGet(t, provider)
+	})
+	t.Run("store isolation", func(t *testing.T) {
+		TestStoreIsolation(t, provider)
+	})
+	t.Run("iterator", func(t *testing.T) {
+		TestIterator(t, provider)
+	})
+	t.Run("delete", func(t *testing.T) {
+		TestDelete(t, provider)
+	})
+	t.Run("query", func(t *testing.T) {
+		TestQuery(t, provider)
+	})
+	t.Run("get tags", func(t *testing.T) {
+		TestGetTags(t, provider)
+	})
+	t.Run("batch", func(t *testing.T) {
+		TestBatch(t, provider)
+	})
+	t.Run("ttl", func(t *testing.T) {
+		TestTTL(t, provider)
+	})
+	t.Run("close store", func(t *testing.T) {
+		TestCloseStore(t, provider)
+	})
+	t.Run("concurrent open store", func(t *testing.T) {
+		TestConcurrentOpenStore(t, provider)
+	})
+	t.Run("change feed", func(t *testing.T) {
+		TestChangeFeed(t, provider)
+	})
+}
+
+// TestPutGet checks that records are read back as they were written, that putting an existing key replaces
+// its record and that missing keys are reported with storage.ErrDataNotFound.
+func TestPutGet(t *testing.T, provider storage.Provider) {
+	store := openStore(t, provider)
+
+	const key = "did:example:123"
+
+	require.NoError(t, store.Put(key, []byte("value1")))
+
+	value, err := store.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), value)
+
+	require.NoError(t, store.Put(key, []byte(`{"field":"value2"}`)))
+
+	value, err = store.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"field":"value2"}`), value)
+
+	_, err = store.Get("did:example:789")
+	require.True(t, errors.Is(err, storage.ErrDataNotFound), "expected ErrDataNotFound, got %v", err)
+
+	_, err = store.Get("")
+	require.Error(t, err)
+
+	require.Error(t, store.Put("", []byte("value")))
+	require.Error(t, store.Put(key, nil))
+}
+
+// TestStoreIsolation checks that stores of different names don't share records
+// while stores opened with the same name do.
+func TestStoreIsolation(t *testing.T, provider storage.Provider) {
+	name := randomStoreName()
+
+	store1, err := provider.OpenStore(name)
+	require.NoError(t, err)
+
+	store2 := openStore(t, provider)
+
+	const key = "did:example:1"
+
+	require.NoError(t, store1.Put(key, []byte("value1")))
+
+	_, err = store2.Get(key)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound), "expected ErrDataNotFound, got %v", err)
+
+	require.NoError(t, store2.Put(key, []byte("value2")))
+
+	store3, err := provider.OpenStore(name)
+	require.NoError(t, err)
+
+	value, err := store3.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), value)
+}
+
+// TestIterator checks that iterators return the records in key range [start, limit) ordered by key, a limit
+// ending with storage.EndKeySuffix covering every key which starts with the rest of it. Start and limit keys
+// are mandatory: iterators over an empty bound return no records and report an error.
+func TestIterator(t *testing.T, provider storage.Provider) {
+	store := openStore(t, provider)
+
+	keys := []string{"abc", "abc_123", "abc_124", "abc_125", "abc_126", "dab_123", "jkl_123", "mno_123"}
+	for _, key := range keys {
+		require.NoError(t, store.Put(key, []byte("value-for-"+key)))
+	}
+
+	tests := []struct {
+		start, limit string
+		expected     []string
+	}{
+		{"abc_", "abc_" + storage.EndKeySuffix, keys[1:5]},
+		{"abc", "abc" + storage.EndKeySuffix, keys[0:5]},
+		{"abc_", "mno_" + storage.EndKeySuffix, keys[1:]},
+		{"abc_124", "jkl_123", keys[2:6]},
+		{"jkl_123", "jkl_123" + storage.EndKeySuffix, keys[6:7]},
+		{"xyz_", "xyz_" + storage.EndKeySuffix, nil},
+	}
+
+	for _, tc := range tests {
+		itr := store.Iterator(tc.start, tc.limit)
+
+		var got []string
+
+		for itr.Next() {
+			got = append(got, string(itr.Key()))
+			require.Equal(t, "value-for-"+string(itr.Key()), string(itr.Value()))
+		}
+
+		require.NoError(t, itr.Error())
+		require.Equal(t, tc.expected, got, "iterator [%s, %s)", tc.start, tc.limit)
+
+		itr.Release()
+		itr.Release()
+	}
+
+	for _, bounds := range [][2]string{{"", ""}, {"abc_", ""}, {"", "abc_" + storage.EndKeySuffix}} {
+		itr := store.Iterator(bounds[0], bounds[1])
+
+		require.False(t, itr.Next(), "iterator [%s, %s)", bounds[0], bounds[1])
+		require.Error(t, itr.Error(), "iterator [%s, %s)", bounds[0], bounds[1])
+
+		itr.Release()
+	}
+}
+
+// TestDelete checks that deleted records can't be read, iterated over or queried anymore
+// and that deleting a missing key is not an error.
+func TestDelete(t *testing.T, provider storage.Provider) {
+	store := openStore(t, provider)
+
+	const key = "did:example:1234"
+
+	require.NoError(t, store.Put(key, []byte("value"), storage.Tag{Name: "state", Value: "completed"}))
+	require.NoError(t, store.Delete(key))
+
+	_, err := store.Get(key)
+	require.True(t, errors.Is(err, storage.ErrDataNotFound), "expected ErrDataNotFound, got %v", err)
+
+	require.Empty(t, readAll(t, store.Iterator(key, key+storage.EndKeySuffix)))
+
+	itr, err := store.Query("state")
+	require.NoError(t, err)
+	require.Empty(t, readAll(t, itr))
+
+	require.NoError(t, store.Delete(key))
+	require.Error(t, store.Delete(""))
+}
+
+// TestGetTags checks that the tags of a record are read back as they were written, in any order,
+// and that putting the record again replaces them.
+func TestGetTags(t *testing.T, provider storage.Provider) {
+	store := openStore(t, provider)
+
+	tags := []storage.Tag{{Name: "state", Value: "completed"}, {Name: "type", Value: "a"}, {Name: "empty"}}
+
+	require.NoError(t, store.Put("key1", []byte("value1"), tags...))
+	require.NoError(t, store.Put("key2", []byte("value2")))
+
+	stored, err := store.GetTags("key1")
+	require.NoError(t, err)
+	require.ElementsMatch(t, tags, stored)
+
+	stored, err = store.GetTags("key2")
+	require.NoError(t, err)
+	require.Empty(t, stored)
+
+	require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "invited"}))
+
+	stored, err = store.GetTags("key1")
+	require.NoError(t, err)
+	require.Equal(t, []storage.Tag{{Name: "state", Value: "invited"}}, stored)
+
+	_, err = store.GetTags("key3")
+	require.True(t, errors.Is(err, storage.ErrDataNotFound), "expected ErrDataNotFound, got %v", err)
+
+	_, err = store.GetTags("")
+	require.Error(t, err)
+}
+
+// TestQuery checks that queries return the records matching all their terms
+// and that putting a record replaces its tags.
+func TestQuery(t *testing.T, provider storage.Provider) {
+	store := openStore(t, provider)
+
+	require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "completed"}))
+	require.NoError(t, store.Put("key2", []byte("value2"),
+		storage.Tag{Name: "state", Value: "invited"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key3", []byte("value3"),
+		storage.Tag{Name: "state", Value: "completed"}, storage.Tag{Name: "type", Value: "a"}))
+	require.NoError(t, store.Put("key4", []byte("value4")))
+
+	tests := []struct {
+		expression string
+		expected   map[string]string
+	}{
+		{"state:completed", map[string]string{"key1": "value1", "key3": "value3"}},
+		{"type", map[string]string{"key2": "value2", "key3": "value3"}},
+		{"state:completed && type:a", map[string]string{"key3": "value3"}},
+		{"state:unknown", map[string]string{}},
+		{"unknown", map[string]string{}},
+	}
+
+	for _, tc := range tests {
+		itr, err := store.Query(tc.expression)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, readAll(t, itr), "query %s", tc.expression)
+	}
+
+	// a record is returned once, whatever the number of its values for the queried tag
+	require.NoError(t, store.Put("key7", []byte("value7"),
+		storage.Tag{Name: "role", Value: "a"}, storage.Tag{Name: "role", Value: "b"}))
+
+	itr, err := store.Query("role")
+	require.NoError(t, err)
+	require.Equal(t, []string{"key7"}, readKeys(t, itr))
+
+	require.NoError(t, store.Put("key1", []byte("value1.1"), storage.Tag{Name: "state", Value: "abandoned"}))
+
+	itr, err = store.Query("state:completed")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"key3": "value3"}, readAll(t, itr))
+
+	itr, err = store.Query("state:abandoned")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"key1": "value1.1"}, readAll(t, itr))
+
+	for _, expression := range []string{"", "state:completed && ", ":completed"} {
+		_, err = store.Query(expression)
+		require.Error(t, err, "query %s", expression)
+	}
+
+	require.Error(t, store.Put("key5", []byte("value5"), storage.Tag{Name: "a" + storage.TagNameValueSeparator}))
+	require.Error(t, store.Put("key5", []byte("value5"), storage.Tag{Name: "a\x00b"}))
+	require.Error(t, store.Put("key5", []byte("value5"), storage.Tag{Name: "state", Value: "a\x00b"}))
+
+	// tags are kept apart from the value, whatever its fields
+	jsonValue := `{"aries_tags":{"state":["completed"]},"tags":"value"}`
+	require.NoError(t, store.Put("key6", []byte(jsonValue), storage.Tag{Name: "state", Value: "json"}))
+
+	value, err := store.Get("key6")
+	require.NoError(t, err)
+	require.Equal(t, jsonValue, string(value))
+
+	itr, err = store.Query("state:json")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"key6": jsonValue}, readAll(t, itr))
+}
+
+// TestBatch checks that batches apply their operations in order and that a batch holding
+// an invalid operation is rejected without any of its operations being applied.
+func TestBatch(t *testing.T, provider storage.Provider) {
+	store := openStore(t, provider)
+
+	require.NoError(t, store.Put("key1", []byte("value1"), storage.Tag{Name: "state", Value: "invited"}))
+
+	require.NoError(t, store.Batch([]storage.Operation{
+		{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state", Value: "invited"}}},
+		{Key: "key1"},
+		{Key: "key3", Value: []byte("value3")},
+		{Key: "key3", Value: []byte("value3.1"), Tags: []storage.Tag{{Name: "state", Value: "completed"}}},
+		{Key: "missing"},
+	}))
+
+	_, err := store.Get("key1")
+	require.True(t, errors.Is(err, storage.ErrDataNotFound), "expected ErrDataNotFound, got %v", err)
+
+	value, err := store.Get("key3")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value3.1"), value)
+
+	itr, err := store.Query("state:invited")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, itr))
+
+	itr, err = store.Query("state:completed")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"key3": "value3.1"}, readAll(t, itr))
+
+	require.Error(t, store.Batch([]storage.Operation{
+		{Key: "key4", Value: []byte("value4")},
+		{Value: []byte("value5")},
+	}))
+
+	_, err = store.Get("key4")
+	require.True(t, errors.Is(err, storage.ErrDataNotFound), "expected ErrDataNotFound, got %v", err)
+}
+
+// TestTTL checks that a record written with a TTL is read until it expires and no longer after, by any read,
+// while a record written without one is kept. Providers rejecting TTLs with storage.ErrTTLNotSupported skip it.
+func TestTTL(t *testing.T, provider storage.Provider) {
+	store := openStore(t, provider)
+
+	err := store.Batch([]storage.Operation{
+		{Key: "key1", Value: []byte("value1"), Tags: []storage.Tag{{Name: "state"}}, TTL: 500 * time.Millisecond},
+		{Key: "key2", Value: []byte("value2"), Tags: []storage.Tag{{Name: "state"}}},
+	})
+	if errors.Is(err, storage.ErrTTLNotSupported) {
+		t.Skip("provider doesn't support record expiry")
+	}
+
+	require.NoError(t, err)
+
+	value, err := store.Get("key1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value1"), value)
+
+	require.Eventually(t, func() bool {
+		_, err := store.Get("key1")
+		return errors.Is(err, storage.ErrDataNotFound)
+	}, 5*time.Second, 20*time.Millisecond)
+
+	_, err = store.GetTags("key1")
+	require.True(t, errors.Is(err, storage.ErrDataNotFound), "expected ErrDataNotFound, got %v", err)
+
+	itr, err := store.Query("state")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, itr))
+	require.Equal(t, map[string]string{"key2": "value2"}, readAll(t, store.Iterator("key", "key"+storage.EndKeySuffix)))
+}
+
+// TestCloseStore checks that closing stores, opened or not, succeeds and that a closed store can be opened again.
+// Whether records outlive their store being closed is up to the provider.
+func TestCloseStore(t *testing.T, provider storage.Provider) {
+	name := randomStoreName()
+
+	store, err := provider.OpenStore(name)
+	require.NoError(t, err)
+	require.NoError(t, store.Put("key1", []byte("value1")))
+
+	require.NoError(t, provider.CloseStore(name))
+	require.NoError(t, provider.CloseStore(name))
+	require.NoError(t, provider.CloseStore(randomStoreName()))
+
+	store, err = provider.OpenStore(name)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key2", []byte("value2")))
+
+	value, err := store.Get("key2")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value2"), value)
+
+	require.NoError(t, provider.CloseStore(name))
+}
+
+// TestConcurrentOpenStore checks that stores opened concurrently with the same name all share their records.
+func TestConcurrentOpenStore(t *testing.T, provider storage.Provider) {
+	const count = 10
+
+	name := randomStoreName()
+	errs := make(chan error, count)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			store, err := provider.OpenStore(name)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			errs <- store.Put(fmt.Sprintf("key%d", i), []byte("value"))
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	store, err := provider.OpenStore(name)
+	require.NoError(t, err)
+
+	for i := 0; i < count; i++ {
+		_, err = store.Get(fmt.Sprintf("key%d", i))
+		require.NoError(t, err)
+	}
+}
+
+// TestChangeFeed checks that providers implementing storage.ChangeFeed report the puts and deletes applied
+// to their stores. It's skipped for the other providers.
+func TestChangeFeed(t *testing.T, provider storage.Provider) {
+	feed, ok := provider.(storage.ChangeFeed)
+	if !ok {
+		t.Skip("provider doesn't support change feed")
+	}
+
+	changes := make(chan storage.Change, 10)
+
+	err := feed.RegisterChangeEvent(changes)
+	if errors.Is(err, storage.ErrChangeFeedNotSupported) {
+		t.Skip("provider doesn't support change feed")
+	}
+
+	require.NoError(t, err)
+
+	name := randomStoreName()
+
+	store, err := provider.OpenStore(name)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put("key1", []byte("value1")))
+	require.NoError(t, store.Batch([]storage.Operation{
+		{Key: "key2", Value: []byte("value2")},
+		{Key: "key1"},
+	}))
+
+	// changes are delivered asynchronously
+	var received []storage.Change
+
+	for len(received) < 3 {
+		select {
+		case change := <-changes:
+			if change.Store == name {
+				received = append(received, change)
+			}
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timeout waiting for changes", received)
+		}
+	}
+
+	require.NoError(t, feed.UnregisterChangeEvent(changes))
+	require.NoError(t, store.Delete("key2"))
+
+	select {
+	case change := <-changes:
+		require.NotEqual(t, name, change.Store, "change received after unregistering")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.Equal(t, []storage.Change{
+		{Store: name, Key: "key1", Operation: storage.ChangePut},
+		{Store: name, Key: "key2", Operation: storage.ChangePut},
+		{Store: name, Key: "key1", Operation: storage.ChangeDelete},
+	}, received)
+}
+
+// randomStoreName returns a store name which is valid for every provider (CouchDB being the most restrictive).
+func randomStoreName() string {
+	return "store" + strings.ReplaceAll(uuid.New().String(), "-", "")
+}
+
+func openStore(t *testing.T, provider storage.Provider) storage.Store {
+	store, err := provider.OpenStore(randomStoreName())
+	require.NoError(t, err)
+
+	return store
+}
+
+func readKeys(t *testing.T, itr storage.StoreIterator) []string {
+	defer itr.Release()
+
+	var keys []string
+
+	for itr.Next() {
+		keys = append(keys, string(itr.Key()))
+	}
+
+	require.NoError(t, itr.Error())
+
+	return keys
+}
+
+func readAll(t *testing.T, itr storage.StoreIterator) map[string]string {
+	defer itr.Release()
+
+	records := make(map[string]string)
+
+	for itr.Next() {
+		records[string(itr.Key())] = string(itr.Value())
+	}
+
+	require.NoError(t, itr.Error())
+
+	return records
+}
@@ -14,8 +14,10 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/internal/storechange"
 )
 
 const (
@@ -24,6 +26,7 @@ const (
 	keyPattern          = "%s_%s"
 	connIDKeyPrefix     = "conn"
 	connStateKeyPrefix  = "connstate"
+	connIndexKeyPrefix  = "connidx"
 	invKeyPrefix        = "inv"
 	eventDataKeyprefix  = "connevent"
 	didConnMapKeyprefix = "didconn_%s,%s"
@@ -31,6 +34,8 @@ const (
 	limitPattern    = "%s" + storage.EndKeySuffix
 	keySeparator    = "_"
 	stateIDEmptyErr = "stateID can't be empty"
+	// stateTagName is the tag holding the state of connection records
+	stateTagName = "state"
 )
 
 // KeyPrefix is prefix builder for storage keys
@@ -57,28 +62,72 @@ type Record struct {
 	InvitationDID   string
 	Implicit        bool
 	Namespace       string
+	// Created is the time the record was first saved, zero for records saved by earlier versions
+	Created time.Time
+}
+
+// RecordEvent is sent when a connection record is saved or removed, in the permanent or the transient store.
+type RecordEvent struct {
+	ConnectionID string
+	Operation    storage.ChangeOperation
 }
 
 // NewLookup returns new connection lookup instance.
 // Lookup is read only connection store. It provides connection record related query features.
 func NewLookup(p provider) (*Lookup, error) {
-	store, err := p.StorageProvider().OpenStore(Namespace)
+	storageProvider, transientProvider := p.StorageProvider(), p.TransientStorageProvider()
+
+	store, err := storageProvider.OpenStore(Namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open permanent store to create new connection recorder: %w", err)
 	}
 
-	transientStore, err := p.TransientStorageProvider().OpenStore(Namespace)
+	transientStore, err := transientProvider.OpenStore(Namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open transient store to create new connection recorder: %w", err)
 	}
 
-	return &Lookup{transientStore: transientStore, store: store}, nil
+	return &Lookup{
+		transientStore: transientStore,
+		store:          store,
+		relay:          storechange.New(storageProvider, transientProvider),
+	}, nil
 }
 
 // Lookup takes care of connection related persistence features
 type Lookup struct {
 	transientStore storage.Store
 	store          storage.Store
+	relay          *storechange.Relay
+}
+
+// RegisterRecordEvent registers a channel receiving an event whenever a connection record is saved or removed.
+// storage.ErrChangeFeedNotSupported is returned when none of the storage providers report their changes.
+func (c *Lookup) RegisterRecordEvent(ch chan<- RecordEvent) error {
+	if ch == nil {
+		return errors.New("channel is mandatory")
+	}
+
+	recordKeyPrefix := getConnectionKeyPrefix()("")
+
+	return c.relay.Subscribe(ch, func(change storage.Change, done <-chan struct{}) {
+		if change.Store != Namespace || !strings.HasPrefix(change.Key, recordKeyPrefix) {
+			return
+		}
+
+		select {
+		case ch <- RecordEvent{
+			ConnectionID: strings.TrimPrefix(change.Key, recordKeyPrefix),
+			Operation:    change.Operation,
+		}:
+		case <-done:
+		}
+	})
+}
+
+// UnregisterRecordEvent stops sending connection record events to a channel. Refer RegisterRecordEvent().
+func (c *Lookup) UnregisterRecordEvent(ch chan<- RecordEvent) error {
+	return c.relay.Unsubscribe(ch)
 }
 
 // GetConnectionRecord return connection record based on the connection ID
@@ -147,6 +196,67 @@ func (c *Lookup) QueryConnectionRecords() ([]*Record, error) {
 	return records, nil
 }
 
+// QueryConnectionRecordsByState returns connection records currently at the given state,
+// found through the state tag of the records in the underlying stores.
+func (c *Lookup) QueryConnectionRecordsByState(stateID string) ([]*Record, error) {
+	if stateID == "" {
+		return nil, errors.New(stateIDEmptyErr)
+	}
+
+	query := stateTagName + storage.TagNameValueSeparator + stateID
+
+	itr, err := c.store.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connection records by state : %w", err)
+	}
+	defer itr.Release()
+
+	records, keys, err := readRecords(itr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connection records by state : %w", err)
+	}
+
+	transientItr, err := c.transientStore.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connection records by state from transient store : %w", err)
+	}
+	defer transientItr.Release()
+
+	// don't fetch data from transient store if same record is present in permanent store
+	transientRecords, _, err := readRecords(transientItr, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query connection records by state from transient store : %w", err)
+	}
+
+	return append(records, transientRecords...), nil
+}
+
+// readRecords reads the connection records of an iterator, skipping the given keys,
+// and returns them along with the keys read.
+func readRecords(itr storage.StoreIterator, skip map[string]struct{}) ([]*Record, map[string]struct{}, error) {
+	var records []*Record
+
+	keys := make(map[string]struct{})
+
+	for itr.Next() {
+		if _, ok := skip[string(itr.Key())]; ok {
+			continue
+		}
+
+		var record Record
+
+		if err := json.Unmarshal(itr.Value(), &record); err != nil {
+			return nil, nil, err
+		}
+
+		keys[string(itr.Key())] = struct{}{}
+
+		records = append(records, &record)
+	}
+
+	return records, keys, itr.Error()
+}
+
 // GetConnectionRecordAtState return connection record based on the connection ID and state.
 func (c *Lookup) GetConnectionRecordAtState(connectionID, stateID string) (*Record, error) {
 	if stateID == "" {
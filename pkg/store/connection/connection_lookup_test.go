@@ -11,12 +11,15 @@ package connection
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol"
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
 	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
@@ -344,6 +347,77 @@ func TestConnectionRecorder_QueryConnectionRecord(t *testing.T) {
 	})
 }
 
+func TestConnectionLookup_QueryConnectionRecordsByState(t *testing.T) {
+	t.Run("test query connection records by state", func(t *testing.T) {
+		provider := &mockProvider{
+			store:          &mockstorage.MockStore{Store: make(map[string][]byte)},
+			transientStore: &mockstorage.MockStore{Store: make(map[string][]byte)},
+		}
+
+		recorder, err := NewRecorder(provider)
+		require.NoError(t, err)
+
+		for i, state := range []string{"invited", "requested", stateNameCompleted, stateNameCompleted} {
+			require.NoError(t, recorder.SaveConnectionRecord(&Record{
+				ConnectionID: fmt.Sprintf("conn%d", i),
+				State:        state,
+			}))
+		}
+
+		result, err := recorder.QueryConnectionRecordsByState(stateNameCompleted)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		for _, record := range result {
+			require.Equal(t, stateNameCompleted, record.State)
+		}
+
+		result, err = recorder.QueryConnectionRecordsByState("invited")
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, "conn0", result[0].ConnectionID)
+
+		// record moves to next state
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{ConnectionID: "conn0", State: "requested"}))
+
+		result, err = recorder.QueryConnectionRecordsByState("invited")
+		require.NoError(t, err)
+		require.Empty(t, result)
+
+		result, err = recorder.QueryConnectionRecordsByState("requested")
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+	})
+
+	t.Run("test query connection records by state - failure", func(t *testing.T) {
+		recorder, err := NewLookup(&mockProvider{
+			store:          &mockstorage.MockStore{Store: make(map[string][]byte)},
+			transientStore: &mockstorage.MockStore{Store: make(map[string][]byte), ErrQuery: fmt.Errorf(sampleErrMsg)},
+		})
+		require.NoError(t, err)
+
+		result, err := recorder.QueryConnectionRecordsByState("")
+		require.EqualError(t, err, stateIDEmptyErr)
+		require.Empty(t, result)
+
+		result, err = recorder.QueryConnectionRecordsByState(stateNameCompleted)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), sampleErrMsg)
+		require.Empty(t, result)
+
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		require.NoError(t, store.Put(fmt.Sprintf("%s_abc123", connIDKeyPrefix), []byte("-----"),
+			storage.Tag{Name: stateTagName, Value: stateNameCompleted}))
+
+		recorder, err = NewLookup(&mockProvider{store: store})
+		require.NoError(t, err)
+
+		result, err = recorder.QueryConnectionRecordsByState(stateNameCompleted)
+		require.Error(t, err)
+		require.Empty(t, result)
+	})
+}
+
 func TestGetConnectionIDByDIDs(t *testing.T) {
 	myDID := "did:mydid:123"
 	theirDID := "did:theirdid:789"
@@ -380,6 +454,50 @@ func TestGetConnectionIDByDIDs(t *testing.T) {
 	})
 }
 
+func TestConnectionLookup_RecordEvents(t *testing.T) {
+	t.Run("test record events - saved and removed records are reported", func(t *testing.T) {
+		recorder, err := NewRecorder(&mockprovider.Provider{
+			StorageProviderValue:          mem.NewProvider(),
+			TransientStorageProviderValue: mem.NewProvider(),
+		})
+		require.NoError(t, err)
+
+		events := make(chan RecordEvent, 10)
+		require.NoError(t, recorder.RegisterRecordEvent(events))
+
+		connRec := &Record{ThreadID: threadIDValue, ConnectionID: sampleConnID, State: stateNameCompleted,
+			Namespace: myNSPrefix, MyDID: "did:mydid:123", TheirDID: "did:theirdid:789"}
+		require.NoError(t, recorder.SaveConnectionRecord(connRec))
+		require.NoError(t, recorder.RemoveConnection(sampleConnID))
+
+		var received []RecordEvent
+
+		for len(received) == 0 || received[len(received)-1].Operation != storage.ChangeDelete {
+			select {
+			case event := <-events:
+				received = append(received, event)
+			case <-time.After(time.Second):
+				require.Fail(t, "connection record removal wasn't reported")
+			}
+		}
+
+		require.NoError(t, recorder.UnregisterRecordEvent(events))
+		require.Equal(t, RecordEvent{ConnectionID: sampleConnID, Operation: storage.ChangePut}, received[0])
+		require.Equal(t, RecordEvent{ConnectionID: sampleConnID, Operation: storage.ChangeDelete},
+			received[len(received)-1])
+	})
+
+	t.Run("test record events - storage provider without change feed", func(t *testing.T) {
+		lookup, err := NewLookup(&mockProvider{})
+		require.NoError(t, err)
+
+		err = lookup.RegisterRecordEvent(make(chan RecordEvent))
+		require.True(t, errors.Is(err, storage.ErrChangeFeedNotSupported))
+
+		require.EqualError(t, lookup.RegisterRecordEvent(nil), "channel is mandatory")
+	})
+}
+
 // mockProvider for connection recorder
 type mockProvider struct {
 	transientStoreError error
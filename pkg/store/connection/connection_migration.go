@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
+)
+
+// Migrations returns the schema migrations of the connection stores, refer migration.Run().
+func Migrations() []migration.Migration {
+	const (
+		tagDescription   = "tag connection records with their state"
+		indexDescription = "index connection records in their sort orders"
+	)
+
+	return []migration.Migration{
+		{Store: Namespace, Version: 1, Description: tagDescription, Upgrade: tagConnectionRecords},
+		{Store: Namespace, Version: 1, Transient: true, Description: tagDescription, Upgrade: tagConnectionRecords},
+		{Store: Namespace, Version: 2, Description: indexDescription, Upgrade: indexConnectionRecords},
+		{Store: Namespace, Version: 2, Transient: true, Description: indexDescription, Upgrade: indexConnectionRecords},
+	}
+}
+
+// tagConnectionRecords adds the state tag to connection records saved before records were tagged,
+// so that QueryConnectionRecordsByState finds them.
+func tagConnectionRecords(store storage.Store) error {
+	operations, err := connectionTagOperations(store)
+	if err != nil {
+		return err
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return store.Batch(operations)
+}
+
+// indexConnectionRecords adds the index entries of connection records saved before records were indexed,
+// so that SearchConnectionRecords finds them.
+func indexConnectionRecords(store storage.Store) error {
+	searchKey := getConnectionKeyPrefix()("")
+
+	itr := store.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey))
+	defer itr.Release()
+
+	var operations []storage.Operation
+
+	for itr.Next() {
+		var record Record
+
+		if err := json.Unmarshal(itr.Value(), &record); err != nil {
+			return fmt.Errorf("unmarshal connection record : %w", err)
+		}
+
+		operations = append(operations, connectionIndex.Operations(indexEntry(&record), nil)...)
+	}
+
+	if err := itr.Error(); err != nil {
+		return fmt.Errorf("read connection records : %w", err)
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return store.Batch(operations)
+}
+
+func connectionTagOperations(store storage.Store) ([]storage.Operation, error) {
+	searchKey := getConnectionKeyPrefix()("")
+
+	itr := store.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey))
+	defer itr.Release()
+
+	var operations []storage.Operation
+
+	for itr.Next() {
+		var record Record
+
+		if err := json.Unmarshal(itr.Value(), &record); err != nil {
+			return nil, fmt.Errorf("unmarshal connection record : %w", err)
+		}
+
+		operations = append(operations, storage.Operation{
+			Key:   string(itr.Key()),
+			Value: append([]byte(nil), itr.Value()...),
+			Tags:  connectionTags(&record),
+		})
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("read connection records : %w", err)
+	}
+
+	return operations, nil
+}
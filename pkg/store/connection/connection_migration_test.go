@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
+)
+
+func TestMigrations(t *testing.T) {
+	t.Run("test migrations - untagged records found by state", func(t *testing.T) {
+		provider, transientProvider := mem.NewProvider(), mem.NewProvider()
+
+		// records saved without tags by earlier versions
+		for _, p := range []*mem.Provider{provider, transientProvider} {
+			store, err := p.OpenStore(Namespace)
+			require.NoError(t, err)
+
+			recordBytes, err := json.Marshal(&Record{ConnectionID: "conn1", State: stateNameCompleted})
+			require.NoError(t, err)
+			require.NoError(t, store.Put(getConnectionKeyPrefix()("conn1"), recordBytes))
+		}
+
+		store, err := provider.OpenStore(Namespace)
+		require.NoError(t, err)
+
+		transientStore, err := transientProvider.OpenStore(Namespace)
+		require.NoError(t, err)
+
+		lookup, err := NewLookup(&mockProvider{store: store, transientStore: transientStore})
+		require.NoError(t, err)
+
+		records, err := lookup.QueryConnectionRecordsByState(stateNameCompleted)
+		require.NoError(t, err)
+		require.Empty(t, records)
+
+		require.NoError(t, migration.Run(provider, transientProvider, Migrations()...))
+
+		records, err = lookup.QueryConnectionRecordsByState(stateNameCompleted)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, "conn1", records[0].ConnectionID)
+
+		page, err := lookup.SearchConnectionRecords(&Query{})
+		require.NoError(t, err)
+		require.Len(t, page.Records, 1)
+		require.Equal(t, "conn1", page.Records[0].ConnectionID)
+	})
+
+	t.Run("test migrations - invalid record", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: map[string][]byte{getConnectionKeyPrefix()("conn1"): []byte("--")}}
+
+		err := tagConnectionRecords(store)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal connection record")
+
+		err = indexConnectionRecords(store)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unmarshal connection record")
+	})
+
+	t.Run("test migrations - iterator error", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: map[string][]byte{}, ErrItr: errors.New("iterator error")}
+
+		err := tagConnectionRecords(store)
+		require.EqualError(t, err, "read connection records : iterator error")
+
+		err = indexConnectionRecords(store)
+		require.EqualError(t, err, "read connection records : iterator error")
+	})
+}
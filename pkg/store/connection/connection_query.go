@@ -0,0 +1,172 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/internal/pagination"
+)
+
+// SortField is a field connection records can be sorted by.
+type SortField string
+
+const (
+	// SortByCreated sorts connection records by creation time, the default.
+	SortByCreated SortField = "created"
+	// SortByLabel sorts connection records by the label of the other party.
+	SortByLabel SortField = "label"
+	// SortByState sorts connection records by state.
+	SortByState SortField = "state"
+)
+
+// ErrInvalidCursor is returned when the cursor of a query can't be used, e.g. when it was returned for
+// another sort order.
+var ErrInvalidCursor = pagination.ErrInvalidCursor
+
+// Query holds the criteria of a connection records search. Empty filters match every record.
+type Query struct {
+	State      string
+	MyDID      string
+	TheirDID   string
+	TheirLabel string
+	SortBy     SortField
+	Descending bool
+	// Cursor is the next cursor of the previous page, empty for the first page
+	Cursor string
+	// Limit is the maximum number of records of the page, zero for no limit
+	Limit int
+}
+
+// Page is a page of connection records.
+type Page struct {
+	Records []*Record
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string
+}
+
+// connectionIndex keeps connection records in the sort orders they can be searched in, refer pagination.Index.
+var connectionIndex = &pagination.Index{ //nolint:gochecknoglobals
+	Prefix: connIndexKeyPrefix,
+	Orders: []string{string(SortByCreated), string(SortByLabel), string(SortByState)},
+}
+
+// SearchConnectionRecords returns the page of connection records matching the query, in the requested sort order.
+// Records are read through the index of each store, up to the first record of the next page. Records saved without
+// their index entries, eg before the index migration ran, aren't found.
+func (c *Lookup) SearchConnectionRecords(q *Query) (*Page, error) {
+	sortBy := q.SortBy
+	if sortBy == "" {
+		sortBy = SortByCreated
+	}
+
+	if !validSortField(sortBy) {
+		return nil, fmt.Errorf("invalid sort field : %s", sortBy)
+	}
+
+	stores := []storage.Store{c.store, c.transientStore}
+
+	load := func(i int, connectionID string) (interface{}, error) {
+		key := getConnectionKeyPrefix()(connectionID)
+
+		// records of the transient store are the ones which aren't in the permanent store
+		if i > 0 {
+			_, err := c.store.Get(key)
+			if err == nil {
+				return nil, nil
+			}
+
+			if !errors.Is(err, storage.ErrDataNotFound) {
+				return nil, fmt.Errorf("get connection record : %w", err)
+			}
+		}
+
+		record := &Record{}
+
+		err := getAndUnmarshal(key, record, stores[i])
+		if errors.Is(err, storage.ErrDataNotFound) {
+			// the record was removed since its entry was read
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("get connection record : %w", err)
+		}
+
+		if !q.matches(record) {
+			return nil, nil
+		}
+
+		return record, nil
+	}
+
+	records, next, err := connectionIndex.Read(stores,
+		pagination.Order{Key: string(sortBy), Descending: q.Descending}, q.Cursor, q.Limit, load)
+	if err != nil {
+		return nil, fmt.Errorf("search connection records : %w", err)
+	}
+
+	page := &Page{Records: make([]*Record, len(records)), NextCursor: next}
+
+	for i, r := range records {
+		page.Records[i] = r.(*Record)
+	}
+
+	return page, nil
+}
+
+func (q *Query) matches(record *Record) bool {
+	return (q.State == "" || q.State == record.State) &&
+		(q.MyDID == "" || q.MyDID == record.MyDID) &&
+		(q.TheirDID == "" || q.TheirDID == record.TheirDID) &&
+		(q.TheirLabel == "" || q.TheirLabel == record.TheirLabel)
+}
+
+func validSortField(field SortField) bool {
+	switch field {
+	case SortByCreated, SortByLabel, SortByState:
+		return true
+	default:
+		return false
+	}
+}
+
+// indexEntry returns the entry of a record in the index of connection records.
+func indexEntry(record *Record) *pagination.Entry {
+	return &pagination.Entry{
+		ID: record.ConnectionID,
+		SortValues: map[string]string{
+			string(SortByCreated): pagination.TimeValue(record.Created),
+			string(SortByLabel):   record.TheirLabel,
+			string(SortByState):   record.State,
+		},
+	}
+}
+
+// indexOperations returns the operations updating the index entries of a connection record in the given store,
+// which remove them when record is nil.
+func indexOperations(store storage.Store, connectionID string, record *Record) ([]storage.Operation, error) {
+	var previous *Record
+
+	err := getAndUnmarshal(getConnectionKeyPrefix()(connectionID), &previous, store)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return nil, fmt.Errorf("get connection record : %w", err)
+	}
+
+	switch {
+	case record != nil && previous != nil:
+		return connectionIndex.Operations(indexEntry(record), indexEntry(previous)), nil
+	case record != nil:
+		return connectionIndex.Operations(indexEntry(record), nil), nil
+	case previous != nil:
+		return connectionIndex.DeleteOperations(indexEntry(previous)), nil
+	default:
+		return nil, nil
+	}
+}
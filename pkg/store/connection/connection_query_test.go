@@ -0,0 +1,215 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package connection
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestLookup_SearchConnectionRecords(t *testing.T) {
+	newRecorder := func(t *testing.T) *Recorder {
+		store, err := mem.NewProvider().OpenStore(Namespace)
+		require.NoError(t, err)
+
+		transientStore, err := mem.NewProvider().OpenStore(Namespace)
+		require.NoError(t, err)
+
+		recorder, err := NewRecorder(&mockProvider{store: store, transientStore: transientStore})
+		require.NoError(t, err)
+
+		created := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+
+		for i, label := range []string{"carol", "alice", "bob", "dave"} {
+			state := "requested"
+			if i%2 == 0 {
+				state = stateNameCompleted
+			}
+
+			require.NoError(t, recorder.SaveConnectionRecord(&Record{
+				ConnectionID: fmt.Sprintf("conn%d", i),
+				TheirLabel:   label,
+				TheirDID:     fmt.Sprintf("did:example:%s", label),
+				State:        state,
+				Created:      created.Add(time.Duration(i) * time.Minute),
+			}))
+		}
+
+		return recorder
+	}
+
+	connectionIDs := func(records []*Record) []string {
+		ids := make([]string, len(records))
+		for i, r := range records {
+			ids[i] = r.ConnectionID
+		}
+
+		return ids
+	}
+
+	t.Run("test search - sorted by creation time by default", func(t *testing.T) {
+		page, err := newRecorder(t).SearchConnectionRecords(&Query{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn0", "conn1", "conn2", "conn3"}, connectionIDs(page.Records))
+		require.Empty(t, page.NextCursor)
+	})
+
+	t.Run("test search - sorted by label, descending", func(t *testing.T) {
+		page, err := newRecorder(t).SearchConnectionRecords(&Query{SortBy: SortByLabel, Descending: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn3", "conn0", "conn2", "conn1"}, connectionIDs(page.Records))
+	})
+
+	t.Run("test search - sorted by state", func(t *testing.T) {
+		page, err := newRecorder(t).SearchConnectionRecords(&Query{SortBy: SortByState})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn0", "conn2", "conn1", "conn3"}, connectionIDs(page.Records))
+	})
+
+	t.Run("test search - filters", func(t *testing.T) {
+		recorder := newRecorder(t)
+
+		page, err := recorder.SearchConnectionRecords(&Query{State: stateNameCompleted})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn0", "conn2"}, connectionIDs(page.Records))
+
+		page, err = recorder.SearchConnectionRecords(&Query{TheirLabel: "bob"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn2"}, connectionIDs(page.Records))
+
+		page, err = recorder.SearchConnectionRecords(&Query{State: stateNameCompleted, TheirDID: "did:example:bob"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn2"}, connectionIDs(page.Records))
+
+		page, err = recorder.SearchConnectionRecords(&Query{MyDID: "did:example:unknown"})
+		require.NoError(t, err)
+		require.Empty(t, page.Records)
+	})
+
+	t.Run("test search - pages", func(t *testing.T) {
+		recorder := newRecorder(t)
+
+		page, err := recorder.SearchConnectionRecords(&Query{SortBy: SortByLabel, Limit: 3})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn1", "conn2", "conn0"}, connectionIDs(page.Records))
+		require.NotEmpty(t, page.NextCursor)
+
+		page, err = recorder.SearchConnectionRecords(&Query{SortBy: SortByLabel, Limit: 3, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn3"}, connectionIDs(page.Records))
+		require.Empty(t, page.NextCursor)
+	})
+
+	t.Run("test search - invalid query", func(t *testing.T) {
+		recorder := newRecorder(t)
+
+		_, err := recorder.SearchConnectionRecords(&Query{SortBy: "unknown"})
+		require.EqualError(t, err, "invalid sort field : unknown")
+
+		page, err := recorder.SearchConnectionRecords(&Query{Limit: 1})
+		require.NoError(t, err)
+
+		_, err = recorder.SearchConnectionRecords(&Query{SortBy: SortByLabel, Cursor: page.NextCursor})
+		require.True(t, errors.Is(err, ErrInvalidCursor))
+
+		_, err = recorder.SearchConnectionRecords(&Query{Limit: -1})
+		require.Error(t, err)
+	})
+
+	t.Run("test search - updated and removed records", func(t *testing.T) {
+		recorder := newRecorder(t)
+
+		record, err := recorder.GetConnectionRecord("conn1")
+		require.NoError(t, err)
+
+		record.TheirLabel = "erin"
+		require.NoError(t, recorder.SaveConnectionRecord(record))
+
+		record, err = recorder.GetConnectionRecord("conn2")
+		require.NoError(t, err)
+
+		record.ThreadID, record.Namespace = "thread2", myNSPrefix
+		require.NoError(t, recorder.SaveConnectionRecordWithMappings(record))
+		require.NoError(t, recorder.RemoveConnection("conn2"))
+
+		page, err := recorder.SearchConnectionRecords(&Query{SortBy: SortByLabel})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn0", "conn3", "conn1"}, connectionIDs(page.Records))
+		require.Equal(t, "erin", page.Records[2].TheirLabel)
+	})
+
+	t.Run("test search - records are read from their key", func(t *testing.T) {
+		recorder := newRecorder(t)
+
+		record, err := recorder.GetConnectionRecord("conn0")
+		require.NoError(t, err)
+
+		// records written without their index entries are read as saved, at their indexed position
+		record.State = "abandoned"
+		require.NoError(t, marshalAndSave(getConnectionKeyPrefix()("conn0"), record, recorder.store))
+		require.NoError(t, recorder.transientStore.Delete(getConnectionKeyPrefix()("conn3")))
+
+		page, err := recorder.SearchConnectionRecords(&Query{})
+		require.NoError(t, err)
+		require.Equal(t, []string{"conn0", "conn1", "conn2"}, connectionIDs(page.Records))
+		require.Equal(t, "abandoned", page.Records[0].State)
+
+		require.NoError(t, recorder.store.Put(getConnectionKeyPrefix()("conn0"), []byte("----")))
+
+		_, err = recorder.SearchConnectionRecords(&Query{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "get connection record")
+	})
+
+	t.Run("test search - record read error", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+
+		recorder, err := NewRecorder(&mockProvider{store: store, transientStore: mockstorage.NewMockStoreProvider().Store})
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{ConnectionID: "conn0", State: stateNameCompleted}))
+
+		store.ErrGet = fmt.Errorf(sampleErrMsg)
+
+		_, err = recorder.SearchConnectionRecords(&Query{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), sampleErrMsg)
+	})
+
+	t.Run("test search - store error", func(t *testing.T) {
+		lookup, err := NewLookup(&mockProvider{
+			store: &mockstorage.MockStore{Store: make(map[string][]byte), ErrItr: fmt.Errorf(sampleErrMsg)},
+		})
+		require.NoError(t, err)
+
+		_, err = lookup.SearchConnectionRecords(&Query{State: stateNameCompleted})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), sampleErrMsg)
+	})
+
+	t.Run("test creation time is kept across saves", func(t *testing.T) {
+		recorder := newRecorder(t)
+
+		record := &Record{ConnectionID: "conn4", State: "invited"}
+		require.NoError(t, recorder.SaveConnectionRecord(record))
+		require.False(t, record.Created.IsZero())
+
+		require.NoError(t, recorder.SaveConnectionRecord(&Record{ConnectionID: "conn4", State: "requested"}))
+
+		saved, err := recorder.GetConnectionRecord("conn4")
+		require.NoError(t, err)
+		require.Equal(t, "requested", saved.State)
+		require.Equal(t, record.Created, saved.Created)
+	})
+}
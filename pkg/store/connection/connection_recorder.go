@@ -14,7 +14,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
 )
 
@@ -56,35 +58,73 @@ func (c *Recorder) SaveInvitation(id string, invitation interface{}) error {
 
 // SaveConnectionRecord saves given connection records in underlying store
 func (c *Recorder) SaveConnectionRecord(record *Record) error {
-	if err := marshalAndSave(getConnectionKeyPrefix()(record.ConnectionID),
-		record, c.transientStore); err != nil {
-		return fmt.Errorf("save connection record in transient store: %w", err)
+	return c.saveConnectionRecord(record)
+}
+
+// saveConnectionRecord saves given connection record, along with the given extra operations on the transient store.
+// Records of each store are written in a single batch, along with their index entries, so that a failure can't
+// leave partial mappings behind.
+func (c *Recorder) saveConnectionRecord(record *Record, transientOps ...storage.Operation) error {
+	if record.Created.IsZero() {
+		record.Created = c.createdTime(record.ConnectionID)
+	}
+
+	recordOp, err := marshalOperation(getConnectionKeyPrefix()(record.ConnectionID), record, connectionTags(record)...)
+	if err != nil {
+		return err
 	}
 
+	ops := []storage.Operation{recordOp}
+
 	if record.State != "" {
-		err := marshalAndSave(getConnectionStateKeyPrefix()(record.ConnectionID, record.State),
-			record, c.transientStore)
-		if err != nil {
-			return fmt.Errorf("save connection record with state in transient store: %w", err)
-		}
+		ops = append(ops, storage.Operation{
+			Key:   getConnectionStateKeyPrefix()(record.ConnectionID, record.State),
+			Value: recordOp.Value,
+		})
+	}
+
+	indexOps, err := indexOperations(c.transientStore, record.ConnectionID, record)
+	if err != nil {
+		return fmt.Errorf("save connection record in transient store: %w", err)
+	}
+
+	if err = c.transientStore.Batch(append(append(ops, indexOps...), transientOps...)); err != nil {
+		return fmt.Errorf("save connection record in transient store: %w", err)
 	}
 
 	if record.State == stateNameCompleted {
-		if err := marshalAndSave(getConnectionKeyPrefix()(record.ConnectionID),
-			record, c.store); err != nil {
+		indexOps, err = indexOperations(c.store, record.ConnectionID, record)
+		if err != nil {
 			return fmt.Errorf("save connection record in permanent store: %w", err)
 		}
 
-		// create map between DIDs and ConnectionID
-		if err := c.store.Put(getDIDConnMapKeyPrefix()(record.MyDID, record.TheirDID),
-			[]byte(record.ConnectionID)); err != nil {
-			return fmt.Errorf("save did and connection map in store: %w", err)
+		// create map between DIDs and ConnectionID along with the record
+		err = c.store.Batch(append([]storage.Operation{
+			recordOp,
+			{
+				Key:   getDIDConnMapKeyPrefix()(record.MyDID, record.TheirDID),
+				Value: []byte(record.ConnectionID),
+			},
+		}, indexOps...))
+		if err != nil {
+			return fmt.Errorf("save connection record and did connection map in permanent store: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// createdTime returns the creation time of the saved record of the given connection, or the current time
+// for new connections. Times are kept to the millisecond so that they read back unchanged.
+func (c *Recorder) createdTime(connectionID string) time.Time {
+	existing, err := c.GetConnectionRecord(connectionID)
+	if err == nil && !existing.Created.IsZero() {
+		return existing.Created
+	}
+
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
 // SaveConnectionRecordWithMappings saves newly created connection record against the connection id in the store
 // and it creates mapping from namespaced ThreadID to connection ID
 func (c *Recorder) SaveConnectionRecordWithMappings(record *Record) error {
@@ -93,89 +133,126 @@ func (c *Recorder) SaveConnectionRecordWithMappings(record *Record) error {
 		return fmt.Errorf("validation failed while saving connection record with mapping: %w", err)
 	}
 
-	err = c.SaveConnectionRecord(record)
+	mappingOp, err := namespaceThreadIDOperation(record.ThreadID, record.Namespace, record.ConnectionID)
 	if err != nil {
-		return fmt.Errorf("failed to save connection record with mappings: %w", err)
+		return fmt.Errorf("failed to save connection record with namespace mappings: %w", err)
 	}
 
-	err = c.SaveNamespaceThreadID(record.ThreadID, record.Namespace, record.ConnectionID)
+	err = c.saveConnectionRecord(record, mappingOp)
 	if err != nil {
-		return fmt.Errorf("failed to save connection record with namespace mappings: %w", err)
+		return fmt.Errorf("failed to save connection record with mappings: %w", err)
 	}
 
 	return nil
 }
 
-// SaveEvent saves event related data for given connection ID
+// SaveEvent saves event related data for given connection ID, which expires after service.ThreadDataTTL
 // TODO connection event data shouldn't be transient [Issues #1029]
 func (c *Recorder) SaveEvent(connectionID string, data []byte) error {
-	return c.transientStore.Put(getEventDataKeyPrefix()(connectionID), data)
+	return c.transientStore.Batch([]storage.Operation{{
+		Key:   getEventDataKeyPrefix()(connectionID),
+		Value: data,
+		TTL:   service.ThreadDataTTL,
+	}})
 }
 
 // SaveNamespaceThreadID saves given namespace, threadID and connection ID mapping in transient store
 func (c *Recorder) SaveNamespaceThreadID(threadID, namespace, connectionID string) error {
-	if namespace != myNSPrefix && namespace != theirNSPrefix {
-		return fmt.Errorf("namespace not supported")
-	}
-
-	prefix := myNSPrefix
-	if namespace == theirNSPrefix {
-		prefix = theirNSPrefix
-	}
-
-	key, err := computeHash([]byte(threadID))
+	op, err := namespaceThreadIDOperation(threadID, namespace, connectionID)
 	if err != nil {
 		return err
 	}
 
-	return c.transientStore.Put(getNamespaceKeyPrefix(prefix)(key), []byte(connectionID))
+	return c.transientStore.Put(op.Key, op.Value)
 }
 
-// RemoveConnection removes connection record from the store for given id
+// RemoveConnection removes connection record from the store for given id, along with its state records and its
+// namespace, threadID and DID mappings.
+//
+// The transient and the permanent stores are written by separate batches, the permanent store last: if the removal
+// fails in between, the record is still found in the permanent store and the removal can be retried.
 func (c *Recorder) RemoveConnection(connectionID string) error {
 	record, err := c.GetConnectionRecord(connectionID)
 	if err != nil {
 		return fmt.Errorf("unable to get connection record: connectionid=%s err=%w", connectionID, err)
 	}
 
-	if err = c.transientStore.Delete(getConnectionKeyPrefix()(connectionID)); err != nil {
-		return fmt.Errorf("unable to delete connection record from the transient store: connectionid=%s err=%w",
-			connectionID, err)
+	// remove connection records for different states, and namespace, threadID and connection ID mapping
+	stateOps, err := removeConnectionsForStates(c, connectionID)
+	if err != nil {
+		return fmt.Errorf("remove records for different connections states error: %w", err)
 	}
 
-	// remove connection records for different states from transient store
-	err = removeConnectionsForStates(c, connectionID)
+	mappingOp, err := removeMappings(record)
 	if err != nil {
-		return fmt.Errorf("remove records for different connections states error: %w", err)
+		return fmt.Errorf("unable to delete connection record with namespace mappings: %w", err)
 	}
 
-	err = c.store.Delete(getConnectionKeyPrefix()(connectionID))
+	transientIndexOps, err := indexOperations(c.transientStore, connectionID, nil)
 	if err != nil {
-		return fmt.Errorf("unable to delete connection record from the store: connectionid=%s err=%w", connectionID, err)
+		return fmt.Errorf("unable to read connection record index from the transient store: %w", err)
 	}
 
-	err = c.store.Delete(getDIDConnMapKeyPrefix()(record.MyDID, record.TheirDID))
+	indexOps, err := indexOperations(c.store, connectionID, nil)
 	if err != nil {
-		return fmt.Errorf("unable to delete did mapping connection record from the store: connectionid=%s err=%w",
+		return fmt.Errorf("unable to read connection record index from the store: %w", err)
+	}
+
+	transientOps := append([]storage.Operation{{Key: getConnectionKeyPrefix()(connectionID)}}, stateOps...)
+	transientOps = append(transientOps, transientIndexOps...)
+
+	if err = c.transientStore.Batch(append(transientOps, mappingOp)); err != nil {
+		return fmt.Errorf("unable to delete connection record from the transient store: connectionid=%s err=%w",
 			connectionID, err)
 	}
 
-	// remove namespace, threadID and connection ID mapping from transient store
-	err = removeMappings(c, record)
+	err = c.store.Batch(append([]storage.Operation{
+		{Key: getConnectionKeyPrefix()(connectionID)},
+		{Key: getDIDConnMapKeyPrefix()(record.MyDID, record.TheirDID)},
+	}, indexOps...))
 	if err != nil {
-		return fmt.Errorf("unable to delete connection record with namespace mappings: %w", err)
+		return fmt.Errorf("unable to delete connection record and did mapping from the store: connectionid=%s err=%w",
+			connectionID, err)
 	}
 
 	return nil
 }
 
-func marshalAndSave(k string, v interface{}, store storage.Store) error {
+func marshalAndSave(k string, v interface{}, store storage.Store, tags ...storage.Tag) error {
+	op, err := marshalOperation(k, v, tags...)
+	if err != nil {
+		return err
+	}
+
+	return store.Put(op.Key, op.Value, op.Tags...)
+}
+
+func marshalOperation(k string, v interface{}, tags ...storage.Tag) (storage.Operation, error) {
 	bytes, err := json.Marshal(v)
 	if err != nil {
-		return fmt.Errorf("save connection record: %w", err)
+		return storage.Operation{}, fmt.Errorf("save connection record: %w", err)
+	}
+
+	return storage.Operation{Key: k, Value: bytes, Tags: tags}, nil
+}
+
+// namespaceThreadIDOperation returns the put operation of given namespace, threadID and connection ID mapping
+func namespaceThreadIDOperation(threadID, namespace, connectionID string) (storage.Operation, error) {
+	if namespace != myNSPrefix && namespace != theirNSPrefix {
+		return storage.Operation{}, fmt.Errorf("namespace not supported")
+	}
+
+	key, err := CreateNamespaceKey(namespace, threadID)
+	if err != nil {
+		return storage.Operation{}, err
 	}
 
-	return store.Put(k, bytes)
+	return storage.Operation{Key: key, Value: []byte(connectionID)}, nil
+}
+
+// connectionTags returns the tags connection records are indexed by
+func connectionTags(record *Record) []storage.Tag {
+	return []storage.Tag{{Name: stateTagName, Value: record.State}}
 }
 
 // isValidConnection validates connection record
@@ -200,32 +277,29 @@ func computeHash(bytes []byte) (string, error) {
 	return fmt.Sprintf("%x", hash), nil
 }
 
-func removeConnectionsForStates(c *Recorder, connectionID string) error {
+// removeConnectionsForStates returns the delete operations of the connection records for different states
+func removeConnectionsForStates(c *Recorder, connectionID string) ([]storage.Operation, error) {
 	itr := c.transientStore.Iterator(getConnectionStateKeyPrefix()(
 		connectionID),
 		getConnectionStateKeyPrefix()(connectionID)+storage.EndKeySuffix,
 	)
 	defer itr.Release()
 
-	for itr.Next() {
-		key := string(itr.Key())
-		err := c.transientStore.Delete(key)
+	var ops []storage.Operation
 
-		if err != nil {
-			return fmt.Errorf(
-				"unable to delete connection state record from the transient store: key=%s connectionid=%s err=%w",
-				key, connectionID, err)
-		}
+	for itr.Next() {
+		ops = append(ops, storage.Operation{Key: string(itr.Key())})
 	}
 
-	return itr.Error()
+	return ops, itr.Error()
 }
 
-func removeMappings(c *Recorder, record *Record) error {
-	key, err := computeHash([]byte(record.ThreadID))
+// removeMappings returns the delete operation of the namespace, threadID and connection ID mapping of the record
+func removeMappings(record *Record) (storage.Operation, error) {
+	key, err := CreateNamespaceKey(record.Namespace, record.ThreadID)
 	if err != nil {
-		return fmt.Errorf("compute hash: %w", err)
+		return storage.Operation{}, fmt.Errorf("compute hash: %w", err)
 	}
 
-	return c.store.Delete(getNamespaceKeyPrefix(record.Namespace)(key))
+	return storage.Operation{Key: key}, nil
 }
@@ -10,12 +10,14 @@
 package connection
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/aries-framework-go/pkg/didcomm/common/service"
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/internal/mock/didcomm/protocol"
 	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
@@ -73,49 +75,28 @@ func Test_ComputeHash(t *testing.T) {
 
 func Test_RemoveMappings(t *testing.T) {
 	t.Run("test success", func(t *testing.T) {
-		recorder, err := NewRecorder(&protocol.MockProvider{})
-		require.NoError(t, err)
-		require.NotNil(t, recorder)
-
 		record := &Record{
-			ThreadID: threadIDValue,
+			ThreadID:  threadIDValue,
+			Namespace: myNSPrefix,
 		}
 
-		err = removeMappings(recorder, record)
+		op, err := removeMappings(record)
 		require.NoError(t, err)
+		require.Nil(t, op.Value)
+
+		key, err := CreateNamespaceKey(myNSPrefix, threadIDValue)
+		require.NoError(t, err)
+		require.Equal(t, key, op.Key)
 	})
 	t.Run("test failed - empty bytes", func(t *testing.T) {
-		recorder, err := NewRecorder(&protocol.MockProvider{})
-		require.NoError(t, err)
-		require.NotNil(t, recorder)
-
 		record := &Record{
 			ThreadID: "",
 		}
 
-		err = removeMappings(recorder, record)
+		_, err := removeMappings(record)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "empty bytes")
 	})
-	t.Run("test failed to delete the record", func(t *testing.T) {
-		const errMsg = "get error"
-		recorder, err := NewRecorder(&protocol.MockProvider{
-			StoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
-				Store:     make(map[string][]byte),
-				ErrDelete: fmt.Errorf(errMsg),
-			}),
-		})
-		require.NoError(t, err)
-		require.NotNil(t, recorder)
-
-		record := &Record{
-			ThreadID: threadIDValue,
-		}
-
-		err = removeMappings(recorder, record)
-		require.Error(t, err)
-		require.Contains(t, err.Error(), errMsg)
-	})
 }
 
 func Test_RemoveConnectionRecordsForStates(t *testing.T) {
@@ -143,8 +124,11 @@ func Test_RemoveConnectionRecordsForStates(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, recorder)
 
-		err = removeConnectionsForStates(recorder, record.ConnectionID)
+		ops, err := removeConnectionsForStates(recorder, record.ConnectionID)
 		require.NoError(t, err)
+		require.Equal(t, []storage.Operation{
+			{Key: getConnectionStateKeyPrefix()(record.ConnectionID, record.State)},
+		}, ops)
 	})
 	t.Run("test failed to iterate connection state records", func(t *testing.T) {
 		const errMsg = "get error"
@@ -160,37 +144,7 @@ func Test_RemoveConnectionRecordsForStates(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, recorder)
 
-		err = removeConnectionsForStates(recorder, "anyID")
-		require.Error(t, err)
-		require.Contains(t, err.Error(), errMsg)
-	})
-	t.Run("test failed to delete connection state record from the store", func(t *testing.T) {
-		const errMsg = "get error"
-		record := &Record{
-			ThreadID:     threadIDValue,
-			ConnectionID: uuid.New().String(),
-			State:        stateNameCompleted,
-			Namespace:    theirNSPrefix,
-			MyDID:        "did:mydid:123",
-			TheirDID:     "did:theirdid:123",
-		}
-
-		store := &mockstorage.MockStore{
-			Store:     make(map[string][]byte),
-			ErrDelete: fmt.Errorf(errMsg),
-		}
-
-		err := marshalAndSave(getConnectionStateKeyPrefix()(record.ConnectionID, record.State),
-			record, store)
-		require.NoError(t, err)
-
-		recorder, err := NewRecorder(&protocol.MockProvider{
-			TransientStoreProvider: mockstorage.NewCustomMockStoreProvider(store),
-		})
-		require.NoError(t, err)
-		require.NotNil(t, recorder)
-
-		err = removeConnectionsForStates(recorder, record.ConnectionID)
+		_, err = removeConnectionsForStates(recorder, "anyID")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), errMsg)
 	})
@@ -297,7 +251,9 @@ func TestConnectionStore_GetInvitation(t *testing.T) {
 
 func TestConnectionStore_SaveAndGetEventData(t *testing.T) {
 	t.Run("test save and get event data - success", func(t *testing.T) {
-		recorder, err := NewRecorder(&protocol.MockProvider{})
+		transientStore := mockstorage.NewMockStoreProvider()
+
+		recorder, err := NewRecorder(&protocol.MockProvider{TransientStoreProvider: transientStore})
 		require.NoError(t, err)
 		require.NotNil(t, recorder)
 
@@ -309,6 +265,7 @@ func TestConnectionStore_SaveAndGetEventData(t *testing.T) {
 		valueFound, err := recorder.GetEvent(sampleConnID)
 		require.NoError(t, err)
 		require.Equal(t, valueStored, valueFound)
+		require.Equal(t, service.ThreadDataTTL, transientStore.Store.TTLs[getEventDataKeyPrefix()(sampleConnID)])
 	})
 
 	t.Run("test get invitation - not found scenario", func(t *testing.T) {
@@ -458,6 +415,47 @@ func TestConnectionRecorder_SaveConnectionRecord(t *testing.T) {
 		err = record.SaveConnectionRecord(connRec)
 		require.Contains(t, err.Error(), errMsg)
 	})
+
+	t.Run("save connection record - failed to read the previous record", func(t *testing.T) {
+		const errMsg = "get error"
+
+		for _, tc := range []struct {
+			name     string
+			provider *protocol.MockProvider
+			expected string
+		}{
+			{
+				name: "transient store",
+				provider: &protocol.MockProvider{
+					TransientStoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+						Store:  make(map[string][]byte),
+						ErrGet: fmt.Errorf(errMsg),
+					}),
+				},
+				expected: "save connection record in transient store: get connection record : " + errMsg,
+			},
+			{
+				name: "permanent store",
+				provider: &protocol.MockProvider{
+					StoreProvider: mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+						Store:  make(map[string][]byte),
+						ErrGet: fmt.Errorf(errMsg),
+					}),
+				},
+				expected: "save connection record in permanent store: get connection record : " + errMsg,
+			},
+		} {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				recorder, err := NewRecorder(tc.provider)
+				require.NoError(t, err)
+
+				err = recorder.SaveConnectionRecord(&Record{ConnectionID: "test", State: stateNameCompleted})
+				require.EqualError(t, err, tc.expected)
+			})
+		}
+	})
 }
 
 func TestConnectionRecorder_RemoveConnection(t *testing.T) {
@@ -629,6 +627,92 @@ func TestConnectionRecorder_RemoveConnection(t *testing.T) {
 	})
 }
 
+func TestConnectionRecorder_Batches(t *testing.T) {
+	t.Run("remove connection record with mappings", func(t *testing.T) {
+		recorder, err := NewRecorder(&protocol.MockProvider{})
+		require.NoError(t, err)
+
+		record := &Record{
+			ThreadID:     threadIDValue,
+			ConnectionID: uuid.New().String(),
+			State:        stateNameCompleted,
+			Namespace:    theirNSPrefix,
+			MyDID:        "did:mydid:123",
+			TheirDID:     "did:theirdid:123",
+		}
+		require.NoError(t, recorder.SaveConnectionRecordWithMappings(record))
+
+		nsThreadID, err := CreateNamespaceKey(theirNSPrefix, threadIDValue)
+		require.NoError(t, err)
+
+		_, err = recorder.GetConnectionRecordByNSThreadID(nsThreadID)
+		require.NoError(t, err)
+
+		require.NoError(t, recorder.RemoveConnection(record.ConnectionID))
+
+		_, err = recorder.transientStore.Get(nsThreadID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+	t.Run("remove connection record is retried after the permanent store failed", func(t *testing.T) {
+		store := &mockstorage.MockStore{Store: make(map[string][]byte)}
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			StoreProvider: mockstorage.NewCustomMockStoreProvider(store),
+		})
+		require.NoError(t, err)
+
+		record := &Record{
+			ThreadID:     threadIDValue,
+			ConnectionID: uuid.New().String(),
+			State:        stateNameCompleted,
+			Namespace:    theirNSPrefix,
+			MyDID:        "did:mydid:123",
+			TheirDID:     "did:theirdid:123",
+		}
+		require.NoError(t, recorder.SaveConnectionRecordWithMappings(record))
+
+		store.ErrBatch = errors.New("batch error")
+		require.Error(t, recorder.RemoveConnection(record.ConnectionID))
+
+		// the transient records are removed, the permanent ones are kept
+		_, err = recorder.GetConnectionRecord(record.ConnectionID)
+		require.NoError(t, err)
+
+		store.ErrBatch = nil
+		require.NoError(t, recorder.RemoveConnection(record.ConnectionID))
+
+		_, err = recorder.GetConnectionRecord(record.ConnectionID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+
+		_, err = recorder.GetConnectionIDByDIDs(record.MyDID, record.TheirDID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+	t.Run("failed batch leaves no partial records", func(t *testing.T) {
+		const errMsg = "batch error"
+
+		store := &mockstorage.MockStore{Store: make(map[string][]byte), ErrBatch: fmt.Errorf(errMsg)}
+		recorder, err := NewRecorder(&protocol.MockProvider{
+			StoreProvider: mockstorage.NewCustomMockStoreProvider(store),
+		})
+		require.NoError(t, err)
+
+		record := &Record{
+			ThreadID:     threadIDValue,
+			ConnectionID: uuid.New().String(),
+			State:        stateNameCompleted,
+			Namespace:    theirNSPrefix,
+			MyDID:        "did:mydid:123",
+			TheirDID:     "did:theirdid:123",
+		}
+		err = recorder.SaveConnectionRecord(record)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errMsg)
+		require.Empty(t, store.Store)
+
+		_, err = recorder.GetConnectionIDByDIDs(record.MyDID, record.TheirDID)
+		require.True(t, errors.Is(err, storage.ErrDataNotFound))
+	})
+}
+
 func TestConnectionRecorder_ConnectionRecordMappings(t *testing.T) {
 	t.Run("get connection record by namespace threadID in my namespace", func(t *testing.T) {
 		recorder, err := NewRecorder(&protocol.MockProvider{})
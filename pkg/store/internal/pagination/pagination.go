@@ -0,0 +1,358 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pagination pages sorted records of a store with opaque cursors.
+//
+// Records are paged through an ordered index written along with them: each record has an entry per sort order and
+// direction, whose key sorts like the record, so that a page is read by iterating the store from the cursor on.
+// Entries have an empty value, the records of a page are loaded from where they're saved.
+// A cursor holds the position of the last record of a page in the sort order, so the next page starts right after
+// it even when records were saved or removed in the meantime.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+const (
+	// timeLayout formats times with a fixed width so that they sort as strings.
+	timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+	// terminators of the values encoded in entry keys, they sort before (ascending) and after (descending) hex digits
+	// so that a value sorts before the values it's a prefix of, or after them in descending order.
+	ascendingTerminator  = "."
+	descendingTerminator = "|"
+
+	keySeparator = "_"
+)
+
+// ErrInvalidCursor is returned when a cursor can't be decoded or belongs to another sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Index is the ordered index of the records of a store.
+type Index struct {
+	// Prefix of the keys of the entries, the other keys of the store mustn't start with it
+	Prefix string
+	// Orders are the keys of the sort orders of the records
+	Orders []string
+}
+
+// Entry is an indexed record.
+type Entry struct {
+	// ID is unique among the records, it orders records having the same sort value
+	ID string
+	// SortValues holds the value the record is sorted by in each order, by order key
+	SortValues map[string]string
+}
+
+// Order is the sort order of a query.
+type Order struct {
+	// Key names the sort value, cursors of other keys are rejected
+	Key        string
+	Descending bool
+}
+
+// Load returns the record of an entry read from the i-th store of Index.Read, or nil when the record doesn't belong
+// to the page, eg when it doesn't match the query or was removed since the entry was written.
+type Load func(i int, id string) (interface{}, error)
+
+type cursor struct {
+	Key        string `json:"k"`
+	Descending bool   `json:"d,omitempty"`
+	SortValue  string `json:"v"`
+	ID         string `json:"id"`
+}
+
+// TimeValue returns t as a sort value.
+func TimeValue(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// Operations returns the operations writing the entries of a record, replacing the ones of its previous version
+// unless previous is nil. They're meant to be written in the batch of the record.
+func (x *Index) Operations(entry, previous *Entry) []storage.Operation {
+	keys := x.keys(entry)
+
+	var operations []storage.Operation
+
+	if previous != nil {
+		current := make(map[string]struct{}, len(keys))
+
+		for _, k := range keys {
+			current[k] = struct{}{}
+		}
+
+		for _, k := range x.keys(previous) {
+			if _, ok := current[k]; !ok {
+				operations = append(operations, storage.Operation{Key: k})
+			}
+		}
+	}
+
+	for _, k := range keys {
+		operations = append(operations, storage.Operation{Key: k, Value: []byte{}})
+	}
+
+	return operations
+}
+
+// DeleteOperations returns the operations removing the entries of a record.
+func (x *Index) DeleteOperations(entry *Entry) []storage.Operation {
+	keys := x.keys(entry)
+	operations := make([]storage.Operation, len(keys))
+
+	for i, k := range keys {
+		operations[i] = storage.Operation{Key: k}
+	}
+
+	return operations
+}
+
+// Read returns the records of at most limit entries following the cursor in the given order, along with the cursor
+// of the next page. The next cursor is empty when there are no more records. An empty cursor starts from the first
+// record and a zero limit returns every record.
+//
+// The entries of the given stores are read merged in order, the records returned by load make the page.
+// The stores are read up to the first record of the next page, so that a page only loads its own records.
+func (x *Index) Read(stores []storage.Store, order Order, after string, limit int, load Load) ([]interface{}, string,
+	error) {
+	if limit < 0 {
+		return nil, "", errors.New("limit can't be negative")
+	}
+
+	prefix := x.orderPrefix(order)
+	start, last := prefix, ""
+
+	if after != "" {
+		c, err := decode(after)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if c.Key != order.Key || c.Descending != order.Descending {
+			return nil, "", fmt.Errorf("%w: cursor of another sort order", ErrInvalidCursor)
+		}
+
+		last = prefix + encodeValue(c.SortValue, order.Descending) + encodeValue(c.ID, order.Descending)
+		start = last
+	}
+
+	heads := make([]*head, len(stores))
+
+	for i, store := range stores {
+		itr := store.Iterator(start, prefix+storage.EndKeySuffix)
+		defer itr.Release()
+
+		heads[i] = &head{itr: itr, skip: last}
+
+		if err := heads[i].next(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var (
+		records []interface{}
+		lastKey string
+	)
+
+	for h, i := first(heads); h != nil; h, i = first(heads) {
+		_, id, err := parseKey(h.key[len(prefix):], order.Descending)
+		if err != nil {
+			return nil, "", err
+		}
+
+		record, err := load(i, id)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if record != nil {
+			if limit > 0 && len(records) == limit {
+				next, err := nextCursor(order, lastKey[len(prefix):])
+				if err != nil {
+					return nil, "", err
+				}
+
+				return records, next, nil
+			}
+
+			records = append(records, record)
+			lastKey = h.key
+		}
+
+		if err := h.next(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return records, "", nil
+}
+
+// keys returns the keys of the entries of a record, in every order and direction.
+func (x *Index) keys(entry *Entry) []string {
+	var keys []string
+
+	for _, key := range x.Orders {
+		value := entry.SortValues[key]
+
+		for _, descending := range []bool{false, true} {
+			keys = append(keys, x.orderPrefix(Order{Key: key, Descending: descending})+
+				encodeValue(value, descending)+encodeValue(entry.ID, descending))
+		}
+	}
+
+	return keys
+}
+
+func (x *Index) orderPrefix(order Order) string {
+	direction := "a"
+	if order.Descending {
+		direction = "d"
+	}
+
+	return x.Prefix + keySeparator + order.Key + keySeparator + direction + keySeparator
+}
+
+// head is the current entry of the iterator of a store.
+type head struct {
+	itr  storage.StoreIterator
+	skip string
+	key  string
+	done bool
+}
+
+// next moves to the next entry, skipping the one of the cursor.
+func (h *head) next() error {
+	for h.itr.Next() {
+		if key := string(h.itr.Key()); key != h.skip {
+			h.key = key
+
+			return nil
+		}
+	}
+
+	h.done = true
+
+	if err := h.itr.Error(); err != nil {
+		return fmt.Errorf("read index entries: %w", err)
+	}
+
+	return nil
+}
+
+// first returns the head having the first entry in key order, along with its position, or nil once every
+// iterator is exhausted. Heads having the same entry are returned in store order.
+func first(heads []*head) (*head, int) {
+	var (
+		earliest *head
+		pos      int
+	)
+
+	for i, h := range heads {
+		if !h.done && (earliest == nil || h.key < earliest.key) {
+			earliest, pos = h, i
+		}
+	}
+
+	return earliest, pos
+}
+
+// encodeValue encodes a value so that it sorts like the value, or in reverse when descending. Values are hex encoded
+// to stay printable, bytes being inverted beforehand in descending order.
+func encodeValue(value string, descending bool) string {
+	b := []byte(value)
+
+	if !descending {
+		return hex.EncodeToString(b) + ascendingTerminator
+	}
+
+	for i := range b {
+		b[i] = ^b[i]
+	}
+
+	return hex.EncodeToString(b) + descendingTerminator
+}
+
+func decodeValue(encoded string, descending bool) (string, error) {
+	b, err := hex.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	if descending {
+		for i := range b {
+			b[i] = ^b[i]
+		}
+	}
+
+	return string(b), nil
+}
+
+// parseKey returns the sort value and the ID encoded in an entry key, without the prefix of its order.
+func parseKey(key string, descending bool) (string, string, error) {
+	terminator := ascendingTerminator
+	if descending {
+		terminator = descendingTerminator
+	}
+
+	parts := strings.Split(key, terminator)
+	if len(parts) != 3 || parts[2] != "" {
+		return "", "", fmt.Errorf("invalid index entry key '%s'", key)
+	}
+
+	sortValue, err := decodeValue(parts[0], descending)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid index entry key '%s': %w", key, err)
+	}
+
+	id, err := decodeValue(parts[1], descending)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid index entry key '%s': %w", key, err)
+	}
+
+	return sortValue, id, nil
+}
+
+func nextCursor(order Order, key string) (string, error) {
+	sortValue, id, err := parseKey(key, order.Descending)
+	if err != nil {
+		return "", err
+	}
+
+	return encode(&cursor{Key: order.Key, Descending: order.Descending, SortValue: sortValue, ID: id})
+}
+
+func encode(c *cursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("marshal cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decode(s string) (*cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+
+	var c cursor
+
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pagination
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestIndex(t *testing.T) {
+	index := &Index{Prefix: "idx", Orders: []string{"name"}}
+
+	entries := []*Entry{
+		{ID: "c", SortValues: map[string]string{"name": "2"}},
+		{ID: "a", SortValues: map[string]string{"name": "1"}},
+		{ID: "d", SortValues: map[string]string{"name": "2"}},
+		{ID: "b", SortValues: map[string]string{"name": "3"}},
+		{ID: "e", SortValues: map[string]string{"name": "22"}},
+	}
+
+	newStore := func(t *testing.T, entries ...*Entry) storage.Store {
+		store, err := mem.NewProvider().OpenStore("test")
+		require.NoError(t, err)
+
+		for _, e := range entries {
+			require.NoError(t, store.Batch(index.Operations(e, nil)))
+		}
+
+		return store
+	}
+
+	// records are their IDs
+	all := func(_ int, id string) (interface{}, error) { return id, nil }
+
+	t.Run("test read - pages follow each other", func(t *testing.T) {
+		store := newStore(t, entries...)
+		order := Order{Key: "name"}
+
+		page, next, err := index.Read([]storage.Store{store}, order, "", 3, all)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"a", "c", "d"}, page)
+		require.NotEmpty(t, next)
+
+		page, next, err = index.Read([]storage.Store{store}, order, next, 3, all)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"e", "b"}, page)
+		require.Empty(t, next)
+	})
+
+	t.Run("test read - descending order", func(t *testing.T) {
+		store := newStore(t, entries...)
+		order := Order{Key: "name", Descending: true}
+
+		page, next, err := index.Read([]storage.Store{store}, order, "", 2, all)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"b", "e"}, page)
+
+		page, next, err = index.Read([]storage.Store{store}, order, next, 0, all)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"d", "c", "a"}, page)
+		require.Empty(t, next)
+	})
+
+	t.Run("test read - removed records don't shift the next page", func(t *testing.T) {
+		store := newStore(t, entries...)
+		order := Order{Key: "name"}
+
+		_, next, err := index.Read([]storage.Store{store}, order, "", 2, all)
+		require.NoError(t, err)
+
+		require.NoError(t, store.Batch(index.DeleteOperations(entries[0])))
+
+		page, _, err := index.Read([]storage.Store{store}, order, next, 0, all)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"d", "e", "b"}, page)
+	})
+
+	t.Run("test read - entries of updated records move", func(t *testing.T) {
+		store := newStore(t, entries...)
+
+		updated := &Entry{ID: "a", SortValues: map[string]string{"name": "4"}}
+		require.NoError(t, store.Batch(index.Operations(updated, entries[1])))
+
+		page, _, err := index.Read([]storage.Store{store}, Order{Key: "name"}, "", 0, all)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"c", "d", "e", "b", "a"}, page)
+	})
+
+	t.Run("test read - stores are merged and filtered", func(t *testing.T) {
+		store1, store2 := newStore(t, entries[:2]...), newStore(t, entries[1:]...)
+
+		var read []string
+
+		// entries of the second store which are in the first one are skipped
+		filter := func(i int, id string) (interface{}, error) {
+			read = append(read, id)
+
+			if i == 1 && id == "a" {
+				return nil, nil
+			}
+
+			return id, nil
+		}
+
+		page, next, err := index.Read([]storage.Store{store1, store2}, Order{Key: "name"}, "", 3, filter)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"a", "c", "d"}, page)
+
+		// the first record of the next page is read to know whether there is one
+		require.Equal(t, []string{"a", "a", "c", "d", "e"}, read)
+
+		page, next, err = index.Read([]storage.Store{store1, store2}, Order{Key: "name"}, next, 3, filter)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"e", "b"}, page)
+		require.Empty(t, next)
+	})
+
+	t.Run("test read - entries only hold the ID of their record", func(t *testing.T) {
+		store := newStore(t, entries...)
+
+		itr := store.Iterator("idx", "idx"+storage.EndKeySuffix)
+		defer itr.Release()
+
+		var count int
+
+		for ; itr.Next(); count++ {
+			require.Empty(t, itr.Value())
+		}
+
+		require.Equal(t, 2*len(entries), count)
+	})
+
+	t.Run("test read - load error", func(t *testing.T) {
+		_, _, err := index.Read([]storage.Store{newStore(t, entries...)}, Order{Key: "name"}, "", 0,
+			func(int, string) (interface{}, error) { return nil, errors.New("load error") })
+		require.EqualError(t, err, "load error")
+	})
+
+	t.Run("test read - invalid arguments", func(t *testing.T) {
+		stores := []storage.Store{newStore(t, entries...)}
+
+		_, _, err := index.Read(stores, Order{Key: "name"}, "", -1, all)
+		require.EqualError(t, err, "limit can't be negative")
+
+		_, _, err = index.Read(stores, Order{Key: "name"}, "%%", 0, all)
+		require.True(t, errors.Is(err, ErrInvalidCursor))
+
+		_, _, err = index.Read(stores, Order{Key: "name"}, "bm90IGpzb24", 0, all)
+		require.True(t, errors.Is(err, ErrInvalidCursor))
+
+		_, next, err := index.Read(stores, Order{Key: "name"}, "", 1, all)
+		require.NoError(t, err)
+
+		_, _, err = index.Read(stores, Order{Key: "created"}, next, 0, all)
+		require.True(t, errors.Is(err, ErrInvalidCursor))
+	})
+}
+
+func TestTimeValue(t *testing.T) {
+	earlier := time.Date(2020, 5, 1, 10, 0, 0, 900000000, time.UTC)
+	later := time.Date(2020, 5, 1, 11, 0, 1, 0, time.FixedZone("CET", 3600))
+
+	require.Less(t, TimeValue(earlier), TimeValue(later))
+}
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package storechange relays the changes reported by storage providers to the event handlers of the stores.
+package storechange
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// Handler handles a change of the storage providers of a relay. It should return once done is closed,
+// rather than keep waiting on its subscriber.
+type Handler func(change storage.Change, done <-chan struct{})
+
+// Relay calls the handlers subscribed to it with the changes of its storage providers. Each subscription
+// has its own channel on the providers' feeds, drained by its own goroutine.
+type Relay struct {
+	feeds         []storage.ChangeFeed
+	mu            sync.Mutex
+	subscriptions map[interface{}]*subscription
+}
+
+type subscription struct {
+	changes chan storage.Change
+	done    chan struct{}
+	feeds   []storage.ChangeFeed
+}
+
+// New returns a relay of the changes of the given providers which implement storage.ChangeFeed.
+// A provider given more than once is only subscribed to once.
+func New(providers ...storage.Provider) *Relay {
+	r := &Relay{subscriptions: make(map[interface{}]*subscription)}
+
+	for i, p := range providers {
+		feed, ok := p.(storage.ChangeFeed)
+		if !ok || contains(providers[:i], p) {
+			continue
+		}
+
+		r.feeds = append(r.feeds, feed)
+	}
+
+	return r
+}
+
+func contains(providers []storage.Provider, p storage.Provider) bool {
+	for _, provider := range providers {
+		if provider == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Subscribe calls handle with every change of the providers until Unsubscribe is called with the same id.
+// Subscribing an id again has no effect. storage.ErrChangeFeedNotSupported is returned when none
+// of the providers report their changes.
+func (r *Relay) Subscribe(id interface{}, handle Handler) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subscriptions[id]; ok {
+		return nil
+	}
+
+	sub := &subscription{changes: make(chan storage.Change), done: make(chan struct{})}
+
+	for _, feed := range r.feeds {
+		err := feed.RegisterChangeEvent(sub.changes)
+		if errors.Is(err, storage.ErrChangeFeedNotSupported) {
+			continue
+		}
+
+		if err != nil {
+			sub.unregister()
+
+			return err
+		}
+
+		sub.feeds = append(sub.feeds, feed)
+	}
+
+	if len(sub.feeds) == 0 {
+		return storage.ErrChangeFeedNotSupported
+	}
+
+	r.subscriptions[id] = sub
+
+	go sub.relay(handle)
+
+	return nil
+}
+
+// Unsubscribe stops calling the handler subscribed with id. Refer Subscribe().
+func (r *Relay) Unsubscribe(id interface{}) error {
+	r.mu.Lock()
+	sub, ok := r.subscriptions[id]
+	delete(r.subscriptions, id)
+	r.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	// the relay keeps draining the changes, without handling them, until the feeds let go of the channel
+	close(sub.done)
+	sub.unregister()
+	close(sub.changes)
+
+	return nil
+}
+
+func (s *subscription) relay(handle Handler) {
+	for change := range s.changes {
+		select {
+		case <-s.done:
+			continue
+		default:
+		}
+
+		handle(change, s.done)
+	}
+}
+
+func (s *subscription) unregister() {
+	for _, feed := range s.feeds {
+		// the feeds of the framework providers never fail to unregister
+		_ = feed.UnregisterChangeEvent(s.changes) // nolint: errcheck
+	}
+}
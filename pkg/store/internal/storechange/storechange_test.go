@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package storechange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestRelay(t *testing.T) {
+	t.Run("test subscribe - changes of every provider are handled once", func(t *testing.T) {
+		permanent, transient := mem.NewProvider(), mem.NewProvider()
+		relay := New(permanent, transient, permanent, &mockstorage.MockStoreProvider{})
+
+		changes := make(chan storage.Change)
+
+		require.NoError(t, relay.Subscribe(changes, func(change storage.Change, done <-chan struct{}) {
+			changes <- change
+		}))
+
+		store, err := permanent.OpenStore("permanent")
+		require.NoError(t, err)
+
+		go func() {
+			require.NoError(t, store.Put("key1", []byte("value1")))
+		}()
+
+		require.Equal(t, storage.Change{Store: "permanent", Key: "key1", Operation: storage.ChangePut}, <-changes)
+
+		store, err = transient.OpenStore("transient")
+		require.NoError(t, err)
+
+		go func() {
+			require.NoError(t, store.Delete("key2"))
+		}()
+
+		require.Equal(t, storage.Change{Store: "transient", Key: "key2", Operation: storage.ChangeDelete}, <-changes)
+
+		require.NoError(t, relay.Unsubscribe(changes))
+		require.NoError(t, relay.Unsubscribe(changes))
+
+		// not handled anymore, would block otherwise
+		require.NoError(t, store.Put("key3", []byte("value3")))
+	})
+
+	t.Run("test subscribe - unsubscribe while the handler is blocked", func(t *testing.T) {
+		provider := mem.NewProvider()
+		relay := New(provider)
+
+		events := make(chan storage.Change)
+
+		require.NoError(t, relay.Subscribe(events, func(change storage.Change, done <-chan struct{}) {
+			select {
+			case events <- change:
+			case <-done:
+			}
+		}))
+
+		store, err := provider.OpenStore("store")
+		require.NoError(t, err)
+
+		// nobody reads the events: the handler blocks on the first change, and so does the next put
+		// until the subscription is gone
+		require.NoError(t, store.Put("key1", []byte("value1")))
+
+		put := make(chan error)
+
+		go func() {
+			put <- store.Put("key2", []byte("value2"))
+		}()
+
+		require.NoError(t, relay.Unsubscribe(events))
+		require.NoError(t, <-put)
+	})
+
+	t.Run("test subscribe - no provider supports change feed", func(t *testing.T) {
+		relay := New(&mockstorage.MockStoreProvider{})
+
+		err := relay.Subscribe("id", func(storage.Change, <-chan struct{}) {})
+		require.True(t, errors.Is(err, storage.ErrChangeFeedNotSupported))
+	})
+
+	t.Run("test subscribe - register error", func(t *testing.T) {
+		relay := New(mem.NewProvider(), &failingFeed{})
+
+		err := relay.Subscribe("id", func(storage.Change, <-chan struct{}) {})
+		require.EqualError(t, err, "register error")
+	})
+}
+
+type failingFeed struct {
+	mockstorage.MockStoreProvider
+}
+
+func (f *failingFeed) RegisterChangeEvent(chan<- storage.Change) error {
+	return errors.New("register error")
+}
+
+func (f *failingFeed) UnregisterChangeEvent(chan<- storage.Change) error {
+	return nil
+}
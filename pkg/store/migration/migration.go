@@ -0,0 +1,199 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package migration upgrades the records persisted by the framework when their schema changes.
+//
+// Each store has a schema version, kept in the StoreName store of its storage provider, starting at 0 for stores
+// that were never migrated. A migration upgrades the records of a store to the next version. Run applies the
+// migrations newer than the version of each store, in version order, and records the version after each of them
+// so that an interrupted run resumes at the failed migration.
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hyperledger/aries-framework-go/pkg/common/log"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
+// StoreName is the name of the store holding the schema versions of the stores of a storage provider.
+const StoreName = "schemaversion"
+
+var logger = log.New("aries-framework/store/migration")
+
+// Migration upgrades the records of a store to a schema version.
+type Migration struct {
+	// Store is the name of the migrated store
+	Store string
+	// Version is the schema version of the store once migrated, starting at 1
+	Version int
+	// Transient tells whether the store belongs to the transient storage provider instead of the permanent one
+	Transient bool
+	// Description tells what the migration changes, for logging
+	Description string
+	// Upgrade rewrites the records of the store. A nil Upgrade only records the version, e.g. to set the
+	// baseline version of a store whose records don't need any change.
+	Upgrade func(store storage.Store) error
+}
+
+// Run applies the migrations newer than the schema version of their stores, in version order.
+// The transient storage provider may be nil when there are no transient migrations.
+func Run(storageProvider, transientProvider storage.Provider, migrations ...Migration) error {
+	if err := validate(migrations); err != nil {
+		return err
+	}
+
+	var permanent, transient []Migration
+
+	for _, m := range migrations {
+		if m.Transient {
+			transient = append(transient, m)
+		} else {
+			permanent = append(permanent, m)
+		}
+	}
+
+	if err := run(storageProvider, permanent); err != nil {
+		return err
+	}
+
+	if len(transient) == 0 {
+		return nil
+	}
+
+	if transientProvider == nil {
+		return errors.New("transient storage provider is mandatory for transient migrations")
+	}
+
+	return run(transientProvider, transient)
+}
+
+// Version returns the schema version of a store of the given storage provider, 0 if it was never migrated.
+func Version(p storage.Provider, store string) (int, error) {
+	versions, err := p.OpenStore(StoreName)
+	if err != nil {
+		return 0, fmt.Errorf("open schema version store : %w", err)
+	}
+
+	return version(versions, store)
+}
+
+func validate(migrations []Migration) error {
+	type id struct {
+		store     string
+		version   int
+		transient bool
+	}
+
+	seen := make(map[id]struct{})
+
+	for _, m := range migrations {
+		if m.Store == "" {
+			return errors.New("migration store is mandatory")
+		}
+
+		if m.Version < 1 {
+			return fmt.Errorf("invalid version %d of migration of store %s", m.Version, m.Store)
+		}
+
+		key := id{store: m.Store, version: m.Version, transient: m.Transient}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate version %d of migration of store %s", m.Version, m.Store)
+		}
+
+		seen[key] = struct{}{}
+	}
+
+	return nil
+}
+
+func run(p storage.Provider, migrations []Migration) error {
+	if len(migrations) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(migrations, func(i, j int) bool {
+		if migrations[i].Store != migrations[j].Store {
+			return migrations[i].Store < migrations[j].Store
+		}
+
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	versions, err := p.OpenStore(StoreName)
+	if err != nil {
+		return fmt.Errorf("open schema version store : %w", err)
+	}
+
+	current := make(map[string]int)
+
+	for i := range migrations {
+		m := &migrations[i]
+
+		v, ok := current[m.Store]
+		if !ok {
+			v, err = version(versions, m.Store)
+			if err != nil {
+				return err
+			}
+		}
+
+		if m.Version <= v {
+			current[m.Store] = v
+			continue
+		}
+
+		if err := upgrade(p, versions, m); err != nil {
+			return err
+		}
+
+		current[m.Store] = m.Version
+	}
+
+	return nil
+}
+
+func upgrade(p storage.Provider, versions storage.Store, m *Migration) error {
+	logger.Infof("migrating store %s to version %d : %s", m.Store, m.Version, m.Description)
+
+	if m.Upgrade != nil {
+		store, err := p.OpenStore(m.Store)
+		if err != nil {
+			return fmt.Errorf("open store %s : %w", m.Store, err)
+		}
+
+		if err := m.Upgrade(store); err != nil {
+			return fmt.Errorf("migrate store %s to version %d : %w", m.Store, m.Version, err)
+		}
+	}
+
+	if err := versions.Put(m.Store, []byte(strconv.Itoa(m.Version))); err != nil {
+		return fmt.Errorf("save version %d of store %s : %w", m.Version, m.Store, err)
+	}
+
+	return nil
+}
+
+func version(versions storage.Store, store string) (int, error) {
+	b, err := versions.Get(store)
+	if errors.Is(err, storage.ErrDataNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("get version of store %s : %w", store, err)
+	}
+
+	v, err := strconv.Atoi(string(b))
+	if err != nil {
+		return 0, fmt.Errorf("invalid version of store %s : %w", store, err)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mockstorage "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("test run - migrations applied in version order once", func(t *testing.T) {
+		provider, transientProvider := mem.NewProvider(), mem.NewProvider()
+
+		var applied []string
+
+		migrations := []Migration{
+			{Store: "store1", Version: 2, Upgrade: func(store storage.Store) error {
+				applied = append(applied, "store1-v2")
+				return store.Put("key", []byte("v2"))
+			}},
+			{Store: "store1", Version: 1, Upgrade: func(store storage.Store) error {
+				applied = append(applied, "store1-v1")
+				return store.Put("key", []byte("v1"))
+			}},
+			{Store: "store2", Version: 1},
+			{Store: "store1", Version: 1, Transient: true, Upgrade: func(store storage.Store) error {
+				applied = append(applied, "transient-store1-v1")
+				return nil
+			}},
+		}
+
+		require.NoError(t, Run(provider, transientProvider, migrations...))
+		require.Equal(t, []string{"store1-v1", "store1-v2", "transient-store1-v1"}, applied)
+
+		store, err := provider.OpenStore("store1")
+		require.NoError(t, err)
+
+		v, err := store.Get("key")
+		require.NoError(t, err)
+		require.Equal(t, []byte("v2"), v)
+
+		version, err := Version(provider, "store1")
+		require.NoError(t, err)
+		require.Equal(t, 2, version)
+
+		version, err = Version(provider, "store2")
+		require.NoError(t, err)
+		require.Equal(t, 1, version)
+
+		version, err = Version(transientProvider, "store1")
+		require.NoError(t, err)
+		require.Equal(t, 1, version)
+
+		version, err = Version(provider, "store3")
+		require.NoError(t, err)
+		require.Equal(t, 0, version)
+
+		// already migrated
+		applied = nil
+
+		require.NoError(t, Run(provider, transientProvider, migrations...))
+		require.Empty(t, applied)
+	})
+
+	t.Run("test run - failed migration resumed", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		var applied []int
+
+		fail := true
+
+		migrations := []Migration{
+			{Store: "store", Version: 1, Upgrade: func(storage.Store) error {
+				applied = append(applied, 1)
+				return nil
+			}},
+			{Store: "store", Version: 2, Upgrade: func(storage.Store) error {
+				if fail {
+					return errors.New("upgrade error")
+				}
+
+				applied = append(applied, 2)
+
+				return nil
+			}},
+		}
+
+		err := Run(provider, nil, migrations...)
+		require.EqualError(t, err, "migrate store store to version 2 : upgrade error")
+
+		version, err := Version(provider, "store")
+		require.NoError(t, err)
+		require.Equal(t, 1, version)
+
+		fail = false
+
+		require.NoError(t, Run(provider, nil, migrations...))
+		require.Equal(t, []int{1, 2}, applied)
+	})
+
+	t.Run("test run - invalid migrations", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		err := Run(provider, nil, Migration{Version: 1})
+		require.EqualError(t, err, "migration store is mandatory")
+
+		err = Run(provider, nil, Migration{Store: "store"})
+		require.EqualError(t, err, "invalid version 0 of migration of store store")
+
+		err = Run(provider, nil, Migration{Store: "store", Version: 1}, Migration{Store: "store", Version: 1})
+		require.EqualError(t, err, "duplicate version 1 of migration of store store")
+
+		err = Run(provider, nil, Migration{Store: "store", Version: 1, Transient: true})
+		require.EqualError(t, err, "transient storage provider is mandatory for transient migrations")
+
+		require.NoError(t, Run(provider, mem.NewProvider(),
+			Migration{Store: "store", Version: 1}, Migration{Store: "store", Version: 1, Transient: true}))
+	})
+
+	t.Run("test run - storage errors", func(t *testing.T) {
+		err := Run(&mockstorage.MockStoreProvider{ErrOpenStoreHandle: errors.New("open error")}, nil,
+			Migration{Store: "store", Version: 1})
+		require.EqualError(t, err, "open schema version store : open error")
+
+		err = Run(mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+			Store:  map[string][]byte{},
+			ErrGet: errors.New("get error"),
+		}), nil, Migration{Store: "store", Version: 1})
+		require.EqualError(t, err, "get version of store store : get error")
+
+		err = Run(mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+			Store:  map[string][]byte{},
+			ErrPut: errors.New("put error"),
+		}), nil, Migration{Store: "store", Version: 1})
+		require.EqualError(t, err, "save version 1 of store store : put error")
+
+		err = Run(mockstorage.NewCustomMockStoreProvider(&mockstorage.MockStore{
+			Store: map[string][]byte{"store": []byte("one")},
+		}), nil, Migration{Store: "store", Version: 1})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid version of store store")
+	})
+}
@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/internal/pagination"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
+)
+
+// Migrations returns the schema migrations of the verifiable store, refer migration.Run().
+func Migrations() []migration.Migration {
+	return []migration.Migration{
+		{
+			Store:       NameSpace,
+			Version:     1,
+			Description: "tag credential and presentation records with their types",
+			Upgrade:     tagRecords,
+		},
+		{
+			Store:       NameSpace,
+			Version:     2,
+			Description: "index credential and presentation records in their sort orders",
+			Upgrade:     indexRecords,
+		},
+	}
+}
+
+// tagRecords adds the type tags to the credential and presentation records saved before records were tagged,
+// so that GetCredentialsByType and GetPresentationsByType find them.
+func tagRecords(store storage.Store) error {
+	var operations []storage.Operation
+
+	for searchKey, tagName := range map[string]string{
+		credentialNameKey:   credentialTypeTagName,
+		presentationNameKey: presentationTypeTagName,
+	} {
+		ops, err := tagOperations(store, searchKey, tagName)
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, ops...)
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return store.Batch(operations)
+}
+
+func tagOperations(store storage.Store, searchKey, tagName string) ([]storage.Operation, error) {
+	itr := store.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey))
+	defer itr.Release()
+
+	var operations []storage.Operation
+
+	for itr.Next() {
+		var r record
+
+		if err := json.Unmarshal(itr.Value(), &r); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal record : %w", err)
+		}
+
+		operations = append(operations, storage.Operation{
+			Key:   string(itr.Key()),
+			Value: append([]byte(nil), itr.Value()...),
+			Tags:  typeTags(tagName, r.Type),
+		})
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read records : %w", err)
+	}
+
+	return operations, nil
+}
+
+// indexRecords adds the index entries of the credential and presentation records saved before records were indexed,
+// so that QueryCredentials and QueryPresentations find them.
+func indexRecords(store storage.Store) error {
+	var operations []storage.Operation
+
+	for searchKey, index := range map[string]*pagination.Index{
+		credentialNameKey:   credentialIndex,
+		presentationNameKey: presentationIndex,
+	} {
+		ops, err := indexOperations(store, searchKey, index)
+		if err != nil {
+			return err
+		}
+
+		operations = append(operations, ops...)
+	}
+
+	if len(operations) == 0 {
+		return nil
+	}
+
+	return store.Batch(operations)
+}
+
+func indexOperations(store storage.Store, searchKey string, index *pagination.Index) ([]storage.Operation, error) {
+	itr := store.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey))
+	defer itr.Release()
+
+	var operations []storage.Operation
+
+	for itr.Next() {
+		var r record
+
+		if err := json.Unmarshal(itr.Value(), &r); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal record : %w", err)
+		}
+
+		entry := indexEntry(string(itr.Key())[len(searchKey):], r.Created)
+		operations = append(operations, index.Operations(entry, nil)...)
+	}
+
+	if err := itr.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read records : %w", err)
+	}
+
+	return operations, nil
+}
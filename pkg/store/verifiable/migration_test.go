@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifiable
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
+	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
+)
+
+func TestMigrations(t *testing.T) {
+	t.Run("test migrations - untagged records found by type", func(t *testing.T) {
+		provider := mem.NewProvider()
+
+		store, err := provider.OpenStore(NameSpace)
+		require.NoError(t, err)
+
+		// records saved without tags by earlier versions
+		vcRecord, err := json.Marshal(&record{ID: "vc1", Type: []string{"VerifiableCredential"}})
+		require.NoError(t, err)
+		require.NoError(t, store.Put(credentialNameDataKey("vc1"), vcRecord))
+
+		vpRecord, err := json.Marshal(&record{ID: "vp1", Type: []string{"VerifiablePresentation"}})
+		require.NoError(t, err)
+		require.NoError(t, store.Put(presentationNameDataKey("vp1"), vpRecord))
+
+		s, err := New(&mockprovider.Provider{StorageProviderValue: provider})
+		require.NoError(t, err)
+
+		records, err := s.GetCredentialsByType("VerifiableCredential")
+		require.NoError(t, err)
+		require.Empty(t, records)
+
+		require.NoError(t, migration.Run(provider, nil, Migrations()...))
+
+		records, err = s.GetCredentialsByType("VerifiableCredential")
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, "vc1", records[0].Name)
+
+		records, err = s.GetPresentationsByType("VerifiablePresentation")
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, "vp1", records[0].Name)
+
+		page, err := s.QueryCredentials(&Query{})
+		require.NoError(t, err)
+		require.Len(t, page.Records, 1)
+		require.Equal(t, "vc1", page.Records[0].Name)
+
+		page, err = s.QueryPresentations(&Query{SortBy: SortByName})
+		require.NoError(t, err)
+		require.Len(t, page.Records, 1)
+		require.Equal(t, "vp1", page.Records[0].Name)
+	})
+
+	t.Run("test migrations - errors", func(t *testing.T) {
+		err := tagRecords(&mockstore.MockStore{Store: map[string][]byte{credentialNameDataKey("vc1"): []byte("--")}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal record")
+
+		err = tagRecords(&mockstore.MockStore{Store: map[string][]byte{}, ErrItr: errors.New("iterator error")})
+		require.EqualError(t, err, "failed to read records : iterator error")
+
+		err = indexRecords(&mockstore.MockStore{Store: map[string][]byte{credentialNameDataKey("vc1"): []byte("--")}})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to unmarshal record")
+
+		err = indexRecords(&mockstore.MockStore{Store: map[string][]byte{}, ErrItr: errors.New("iterator error")})
+		require.EqualError(t, err, "failed to read records : iterator error")
+	})
+}
@@ -6,6 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 
 package verifiable
 
+import (
+	"time"
+
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+)
+
 // Record model containing name, ID and other fields of interest
 type Record struct {
 	Name      string   `json:"name,omitempty"`
@@ -13,4 +19,54 @@ type Record struct {
 	Context   []string `json:"context,omitempty"`
 	Type      []string `json:"type,omitempty"`
 	SubjectID string   `json:"subjectId,omitempty"`
+	// Created is the time the record was saved, zero for records saved by earlier versions
+	Created time.Time `json:"created"`
+}
+
+// RecordKind tells whether a record is the one of a credential or of a presentation.
+type RecordKind string
+
+const (
+	// CredentialRecord is the kind of the records of credentials.
+	CredentialRecord RecordKind = "credential"
+
+	// PresentationRecord is the kind of the records of presentations.
+	PresentationRecord RecordKind = "presentation"
+)
+
+// RecordEvent is sent when a named credential or presentation is saved or removed.
+type RecordEvent struct {
+	Kind      RecordKind
+	Name      string
+	Operation storage.ChangeOperation
+}
+
+// SortField is a field credential and presentation records can be sorted by.
+type SortField string
+
+const (
+	// SortByCreated sorts records by the time they were saved, the default.
+	SortByCreated SortField = "created"
+
+	// SortByName sorts records by name.
+	SortByName SortField = "name"
+)
+
+// Query holds the criteria of a credential or presentation records search. Empty filters match every record.
+type Query struct {
+	Type       string
+	SubjectID  string
+	SortBy     SortField
+	Descending bool
+	// Cursor is the next cursor of the previous page, empty for the first page
+	Cursor string
+	// Limit is the maximum number of records of the page, zero for no limit
+	Limit int
+}
+
+// RecordPage is a page of credential or presentation records.
+type RecordPage struct {
+	Records []*Record
+	// NextCursor is the cursor of the next page, empty on the last page
+	NextCursor string
 }
@@ -10,11 +10,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
 	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/internal/pagination"
+	"github.com/hyperledger/aries-framework-go/pkg/store/internal/storechange"
 )
 
 const (
@@ -23,26 +27,51 @@ const (
 
 	credentialNameKey              = "vcname_"
 	presentationNameKey            = "vpname_"
+	credentialIndexKeyPrefix       = "vcidx"
+	presentationIndexKeyPrefix     = "vpidx"
 	credentialNameDataKeyPattern   = credentialNameKey + "%s"
 	presentationNameDataKeyPattern = presentationNameKey + "%s"
 
 	// limitPattern for the iterator
 	limitPattern = "%s" + storage.EndKeySuffix
+
+	// tags holding the types of the saved credentials and presentations
+	credentialTypeTagName   = "credentialType"
+	presentationTypeTagName = "presentationType"
 )
 
 // ErrNotFound signals that the entry for the given DID and key is not present in the store.
 var ErrNotFound = errors.New("did not found under given key")
 
+// ErrInvalidCursor is returned when the cursor of a query can't be used, e.g. when it was returned for
+// another sort order.
+var ErrInvalidCursor = pagination.ErrInvalidCursor
+
 type record struct {
-	ID        string   `json:"id,omitempty"`
-	Context   []string `json:"context,omitempty"`
-	Type      []string `json:"type,omitempty"`
-	SubjectID string   `json:"subjectId,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	Context   []string  `json:"context,omitempty"`
+	Type      []string  `json:"type,omitempty"`
+	SubjectID string    `json:"subjectId,omitempty"`
+	Created   time.Time `json:"created"`
 }
 
+// credentialIndex and presentationIndex keep the credential and presentation records in the sort orders they can
+// be queried in, refer pagination.Index.
+var (
+	credentialIndex = &pagination.Index{ //nolint:gochecknoglobals
+		Prefix: credentialIndexKeyPrefix,
+		Orders: []string{string(SortByCreated), string(SortByName)},
+	}
+	presentationIndex = &pagination.Index{ //nolint:gochecknoglobals
+		Prefix: presentationIndexKeyPrefix,
+		Orders: []string{string(SortByCreated), string(SortByName)},
+	}
+)
+
 // Store stores vc
 type Store struct {
 	store storage.Store
+	relay *storechange.Relay
 }
 
 type provider interface {
@@ -51,12 +80,50 @@ type provider interface {
 
 // New returns a new vc store
 func New(ctx provider) (*Store, error) {
-	store, err := ctx.StorageProvider().OpenStore(NameSpace)
+	provider := ctx.StorageProvider()
+
+	store, err := provider.OpenStore(NameSpace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open vc store: %w", err)
 	}
 
-	return &Store{store: store}, nil
+	return &Store{store: store, relay: storechange.New(provider)}, nil
+}
+
+// RegisterRecordEvent registers a channel receiving an event whenever a named credential or presentation
+// is saved or removed. storage.ErrChangeFeedNotSupported is returned when the storage provider doesn't
+// report its changes.
+func (s *Store) RegisterRecordEvent(ch chan<- RecordEvent) error {
+	if ch == nil {
+		return errors.New("channel is mandatory")
+	}
+
+	return s.relay.Subscribe(ch, func(change storage.Change, done <-chan struct{}) {
+		if change.Store != NameSpace {
+			return
+		}
+
+		event := RecordEvent{Operation: change.Operation}
+
+		switch {
+		case strings.HasPrefix(change.Key, credentialNameKey):
+			event.Kind, event.Name = CredentialRecord, getCredentialName(change.Key)
+		case strings.HasPrefix(change.Key, presentationNameKey):
+			event.Kind, event.Name = PresentationRecord, getPresentationName(change.Key)
+		default:
+			return
+		}
+
+		select {
+		case ch <- event:
+		case <-done:
+		}
+	})
+}
+
+// UnregisterRecordEvent stops sending record events to a channel. Refer RegisterRecordEvent().
+func (s *Store) UnregisterRecordEvent(ch chan<- RecordEvent) error {
+	return s.relay.Unsubscribe(ch)
 }
 
 // SaveCredential saves a verifiable credential.
@@ -85,17 +152,21 @@ func (s *Store) SaveCredential(name string, vc *verifiable.Credential) error {
 		id = uuid.New().String()
 	}
 
-	if e := s.store.Put(id, vcBytes); e != nil {
-		return fmt.Errorf("failed to put vc: %w", e)
-	}
-
-	recordBytes, err := getRecord(id, getVCSubjectID(vc), vc.Context, vc.Types)
+	recordBytes, indexOps, err := getRecord(credentialIndex, name, id, getVCSubjectID(vc), vc.Context, vc.Types)
 	if err != nil {
 		return fmt.Errorf("failed to prepare record: %w", err)
 	}
 
-	if err := s.store.Put(credentialNameDataKey(name), recordBytes); err != nil {
-		return fmt.Errorf("store vc name to id map : %w", err)
+	err = s.store.Batch(append([]storage.Operation{
+		{Key: id, Value: vcBytes},
+		{
+			Key:   credentialNameDataKey(name),
+			Value: recordBytes,
+			Tags:  typeTags(credentialTypeTagName, vc.Types),
+		},
+	}, indexOps...))
+	if err != nil {
+		return fmt.Errorf("failed to put vc and vc name to id map : %w", err)
 	}
 
 	return nil
@@ -127,17 +198,21 @@ func (s *Store) SavePresentation(name string, vp *verifiable.Presentation) error
 		id = uuid.New().String()
 	}
 
-	recordBytes, err := getRecord(id, vp.Holder, vp.Context, vp.Type)
+	recordBytes, indexOps, err := getRecord(presentationIndex, name, id, vp.Holder, vp.Context, vp.Type)
 	if err != nil {
 		return fmt.Errorf("failed to prepare record: %w", err)
 	}
 
-	if err := s.store.Put(id, vpBytes); err != nil {
-		return fmt.Errorf("failed to put vp: %w", err)
-	}
-
-	if err := s.store.Put(presentationNameDataKey(name), recordBytes); err != nil {
-		return fmt.Errorf("store vp name to id map : %w", err)
+	err = s.store.Batch(append([]storage.Operation{
+		{Key: id, Value: vpBytes},
+		{
+			Key:   presentationNameDataKey(name),
+			Value: recordBytes,
+			Tags:  typeTags(presentationTypeTagName, vp.Type),
+		},
+	}, indexOps...))
+	if err != nil {
+		return fmt.Errorf("failed to put vp and vp name to id map : %w", err)
 	}
 
 	return nil
@@ -217,8 +292,102 @@ func (s *Store) GetPresentations() ([]*Record, error) {
 	return s.getAllRecords(presentationNameDataKey(""), getPresentationName)
 }
 
+// GetCredentialsByType retrieves the verifiable credential records of credentials having the given type.
+func (s *Store) GetCredentialsByType(credentialType string) ([]*Record, error) {
+	return s.queryRecords(credentialTypeTagName, credentialType, getCredentialName)
+}
+
+// GetPresentationsByType retrieves the verifiable presentation records of presentations having the given type.
+func (s *Store) GetPresentationsByType(presentationType string) ([]*Record, error) {
+	return s.queryRecords(presentationTypeTagName, presentationType, getPresentationName)
+}
+
+// QueryCredentials retrieves the page of verifiable credential records matching the query, in the requested order.
+func (s *Store) QueryCredentials(q *Query) (*RecordPage, error) {
+	return s.searchRecords(q, credentialIndex, credentialNameDataKey)
+}
+
+// QueryPresentations retrieves the page of verifiable presentation records matching the query,
+// in the requested order.
+func (s *Store) QueryPresentations(q *Query) (*RecordPage, error) {
+	return s.searchRecords(q, presentationIndex, presentationNameDataKey)
+}
+
+// searchRecords reads the page of records through the index, up to the first record of the next page. Records are
+// loaded from their name key.
+func (s *Store) searchRecords(q *Query, index *pagination.Index, nameKey func(string) string) (*RecordPage, error) {
+	sortBy := q.SortBy
+	if sortBy == "" {
+		sortBy = SortByCreated
+	}
+
+	if sortBy != SortByCreated && sortBy != SortByName {
+		return nil, fmt.Errorf("invalid sort field : %s", sortBy)
+	}
+
+	load := func(_ int, name string) (interface{}, error) {
+		recordBytes, err := s.store.Get(nameKey(name))
+		if errors.Is(err, storage.ErrDataNotFound) {
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to get record : %w", err)
+		}
+
+		var r record
+
+		if err = json.Unmarshal(recordBytes, &r); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal record : %w", err)
+		}
+
+		if (q.Type != "" && !containsString(r.Type, q.Type)) || (q.SubjectID != "" && q.SubjectID != r.SubjectID) {
+			return nil, nil
+		}
+
+		return &Record{
+			Name:      name,
+			ID:        r.ID,
+			Context:   r.Context,
+			Type:      r.Type,
+			SubjectID: r.SubjectID,
+			Created:   r.Created,
+		}, nil
+	}
+
+	records, next, err := index.Read([]storage.Store{s.store},
+		pagination.Order{Key: string(sortBy), Descending: q.Descending}, q.Cursor, q.Limit, load)
+	if err != nil {
+		return nil, fmt.Errorf("failed to page records : %w", err)
+	}
+
+	page := &RecordPage{Records: make([]*Record, len(records)), NextCursor: next}
+
+	for i, r := range records {
+		page.Records[i] = r.(*Record)
+	}
+
+	return page, nil
+}
+
+func (s *Store) queryRecords(tagName, tagValue string, keyPrefix func(string) string) ([]*Record, error) {
+	if tagValue == "" {
+		return nil, errors.New("type is mandatory")
+	}
+
+	itr, err := s.store.Query(tagName + storage.TagNameValueSeparator + tagValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query records : %w", err)
+	}
+
+	return readRecords(itr, keyPrefix)
+}
+
 func (s *Store) getAllRecords(searchKey string, keyPrefix func(string) string) ([]*Record, error) {
-	itr := s.store.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey))
+	return readRecords(s.store.Iterator(searchKey, fmt.Sprintf(limitPattern, searchKey)), keyPrefix)
+}
+
+func readRecords(itr storage.StoreIterator, keyPrefix func(string) string) ([]*Record, error) {
 	defer itr.Release()
 
 	var records []*Record
@@ -237,6 +406,7 @@ func (s *Store) getAllRecords(searchKey string, keyPrefix func(string) string) (
 			Context:   r.Context,
 			Type:      r.Type,
 			SubjectID: r.SubjectID,
+			Created:   r.Created,
 		}
 
 		records = append(records, record)
@@ -245,6 +415,16 @@ func (s *Store) getAllRecords(searchKey string, keyPrefix func(string) string) (
 	return records, nil
 }
 
+func typeTags(name string, types []string) []storage.Tag {
+	tags := make([]storage.Tag, len(types))
+
+	for i, t := range types {
+		tags[i] = storage.Tag{Name: name, Value: t}
+	}
+
+	return tags
+}
+
 func getVCSubjectID(vc *verifiable.Credential) string {
 	if subject, ok := vc.Subject.(map[string]interface{}); ok {
 		if s, ok := subject["id"].(string); ok {
@@ -255,13 +435,38 @@ func getVCSubjectID(vc *verifiable.Credential) string {
 	return ""
 }
 
-func getRecord(id, subjectID string, contexts, types []string) ([]byte, error) {
-	recordBytes, err := json.Marshal(&record{id, contexts, types, subjectID})
+// getRecord returns the record of a credential or presentation, along with the operations adding it to the index.
+func getRecord(index *pagination.Index, name, id, subjectID string, contexts, types []string) ([]byte,
+	[]storage.Operation, error) {
+	created := time.Now().UTC()
+
+	recordBytes, err := json.Marshal(&record{id, contexts, types, subjectID, created})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal vc record: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal vc record: %w", err)
+	}
+
+	return recordBytes, index.Operations(indexEntry(name, created), nil), nil
+}
+
+// indexEntry returns the index entry of the record of the given name.
+func indexEntry(name string, created time.Time) *pagination.Entry {
+	return &pagination.Entry{
+		ID: name,
+		SortValues: map[string]string{
+			string(SortByCreated): pagination.TimeValue(created),
+			string(SortByName):    name,
+		},
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
 	}
 
-	return recordBytes, nil
+	return false
 }
 
 func credentialNameDataKey(name string) string {
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"testing"
@@ -17,6 +18,8 @@ import (
 
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
 	mockstore "github.com/hyperledger/aries-framework-go/pkg/mock/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/storage/mem"
 )
 
 const sampleCredentialName = "sampleVCName"
@@ -365,7 +368,7 @@ func TestGetVC(t *testing.T) {
 
 func TestGetCredentialIDBasedOnName(t *testing.T) {
 	t.Run("test get credential based on name - success", func(t *testing.T) {
-		rbytes, err := getRecord(sampleCredentialID, "", nil, nil)
+		rbytes, _, err := getRecord(credentialIndex, sampleCredentialName, sampleCredentialID, "", nil, nil)
 		require.NoError(t, err)
 
 		store := make(map[string][]byte)
@@ -443,6 +446,139 @@ func TestGetCredentials(t *testing.T) {
 	})
 }
 
+func TestQueryCredentials(t *testing.T) {
+	s, err := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+	require.NoError(t, err)
+
+	for i, name := range []string{"vc-b", "vc-a", "vc-c"} {
+		types := []string{"VerifiableCredential"}
+		if i != 1 {
+			types = append(types, "UniversityDegreeCredential")
+		}
+
+		require.NoError(t, s.SaveCredential(name, &verifiable.Credential{
+			ID:      "http://example.edu/credentials/" + name,
+			Types:   types,
+			Subject: map[string]interface{}{"id": "did:example:" + strconv.Itoa(i%2)},
+		}))
+	}
+
+	names := func(records []*Record) []string {
+		result := make([]string, len(records))
+		for i, r := range records {
+			result[i] = r.Name
+		}
+
+		return result
+	}
+
+	t.Run("test query credentials - sorted by creation time by default", func(t *testing.T) {
+		page, err := s.QueryCredentials(&Query{})
+		require.NoError(t, err)
+		require.Len(t, page.Records, 3)
+		require.Empty(t, page.NextCursor)
+
+		for i := 1; i < len(page.Records); i++ {
+			require.False(t, page.Records[i].Created.IsZero())
+			require.False(t, page.Records[i].Created.Before(page.Records[i-1].Created))
+		}
+	})
+
+	t.Run("test query credentials - pages sorted by name", func(t *testing.T) {
+		page, err := s.QueryCredentials(&Query{SortBy: SortByName, Descending: true, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, []string{"vc-c", "vc-b"}, names(page.Records))
+		require.NotEmpty(t, page.NextCursor)
+
+		page, err = s.QueryCredentials(&Query{SortBy: SortByName, Descending: true, Limit: 2, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		require.Equal(t, []string{"vc-a"}, names(page.Records))
+		require.Empty(t, page.NextCursor)
+	})
+
+	t.Run("test query credentials - filters", func(t *testing.T) {
+		page, err := s.QueryCredentials(&Query{Type: "UniversityDegreeCredential", SortBy: SortByName})
+		require.NoError(t, err)
+		require.Equal(t, []string{"vc-b", "vc-c"}, names(page.Records))
+
+		page, err = s.QueryCredentials(&Query{SubjectID: "did:example:1"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"vc-a"}, names(page.Records))
+
+		page, err = s.QueryPresentations(&Query{})
+		require.NoError(t, err)
+		require.Empty(t, page.Records)
+	})
+
+	t.Run("test query credentials - invalid query", func(t *testing.T) {
+		_, err := s.QueryCredentials(&Query{SortBy: "type"})
+		require.EqualError(t, err, "invalid sort field : type")
+
+		_, err = s.QueryCredentials(&Query{Cursor: "--"})
+		require.True(t, errors.Is(err, ErrInvalidCursor))
+	})
+}
+
+func TestGetCredentialsByType(t *testing.T) {
+	t.Run("test get credentials by type", func(t *testing.T) {
+		s, err := New(&mockprovider.Provider{
+			StorageProviderValue: mockstore.NewMockStoreProvider(),
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, s.SaveCredential("vc1", &verifiable.Credential{
+			ID:    "http://example.edu/credentials/1",
+			Types: []string{"VerifiableCredential", "UniversityDegreeCredential"},
+		}))
+		require.NoError(t, s.SaveCredential("vc2", &verifiable.Credential{
+			ID:    "http://example.edu/credentials/2",
+			Types: []string{"VerifiableCredential"},
+		}))
+		require.NoError(t, s.SavePresentation("vp1", &verifiable.Presentation{
+			ID:   "http://example.edu/presentations/1",
+			Type: []string{"VerifiablePresentation"},
+		}))
+
+		records, err := s.GetCredentialsByType("VerifiableCredential")
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+
+		records, err = s.GetCredentialsByType("UniversityDegreeCredential")
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, "vc1", records[0].Name)
+		require.Equal(t, "http://example.edu/credentials/1", records[0].ID)
+
+		records, err = s.GetCredentialsByType("VerifiablePresentation")
+		require.NoError(t, err)
+		require.Empty(t, records)
+
+		records, err = s.GetPresentationsByType("VerifiablePresentation")
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, "vp1", records[0].Name)
+	})
+
+	t.Run("test get credentials by type - failure", func(t *testing.T) {
+		s, err := New(&mockprovider.Provider{
+			StorageProviderValue: mockstore.NewCustomMockStoreProvider(&mockstore.MockStore{
+				Store:    make(map[string][]byte),
+				ErrQuery: fmt.Errorf("error query"),
+			}),
+		})
+		require.NoError(t, err)
+
+		records, err := s.GetCredentialsByType("")
+		require.EqualError(t, err, "type is mandatory")
+		require.Empty(t, records)
+
+		records, err = s.GetCredentialsByType("VerifiableCredential")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error query")
+		require.Empty(t, records)
+	})
+}
+
 func TestSaveVP(t *testing.T) {
 	t.Run("test save vp - success", func(t *testing.T) {
 		s, err := New(&mockprovider.Provider{
@@ -579,7 +715,7 @@ func TestGetVP(t *testing.T) {
 
 func TestGetPresentationIDBasedOnName(t *testing.T) {
 	t.Run("test get presentation based on name - success", func(t *testing.T) {
-		rbytes, err := getRecord(samplePresentationID, "", nil, nil)
+		rbytes, _, err := getRecord(presentationIndex, samplePresentationName, samplePresentationID, "", nil, nil)
 		require.NoError(t, err)
 
 		store := make(map[string][]byte)
@@ -670,3 +806,36 @@ func TestGetPresentations(t *testing.T) {
 		require.Equal(t, records[0].SubjectID, udVP.Holder)
 	})
 }
+
+func TestRecordEvents(t *testing.T) {
+	t.Run("test record events - saved credentials and presentations are reported", func(t *testing.T) {
+		s, err := New(&mockprovider.Provider{StorageProviderValue: mem.NewProvider()})
+		require.NoError(t, err)
+
+		events := make(chan RecordEvent)
+		require.NoError(t, s.RegisterRecordEvent(events))
+
+		go func() {
+			require.NoError(t, s.SaveCredential(sampleCredentialName, &verifiable.Credential{ID: sampleCredentialID}))
+			require.NoError(t, s.SavePresentation(samplePresentationName, &verifiable.Presentation{ID: samplePresentationID}))
+		}()
+
+		require.Equal(t, RecordEvent{Kind: CredentialRecord, Name: sampleCredentialName, Operation: storage.ChangePut},
+			<-events)
+		require.Equal(t, RecordEvent{
+			Kind: PresentationRecord, Name: samplePresentationName, Operation: storage.ChangePut,
+		}, <-events)
+
+		require.NoError(t, s.UnregisterRecordEvent(events))
+	})
+
+	t.Run("test record events - storage provider without change feed", func(t *testing.T) {
+		s, err := New(&mockprovider.Provider{StorageProviderValue: mockstore.NewMockStoreProvider()})
+		require.NoError(t, err)
+
+		err = s.RegisterRecordEvent(make(chan RecordEvent))
+		require.True(t, errors.Is(err, storage.ErrChangeFeedNotSupported))
+
+		require.EqualError(t, s.RegisterRecordEvent(nil), "channel is mandatory")
+	})
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/common/log"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 const (
@@ -18,8 +18,8 @@ const (
 var logger = log.New("aries-framework/vdri/indy")
 
 type provider interface {
-	LegacyKMS() legacykms.KeyManager
-	Signer() legacykms.Signer
+	KMS() kms.KeyManager
+	Signer() kms.Signer
 }
 
 // VDRI implements building new peer did
@@ -27,16 +27,16 @@ type VDRI struct {
 	didMethod  string
 	prefix     string
 	vdrAddress string
-	kms        legacykms.KMS
+	kms        kms.KeyManager
 }
 
 // New return new instance of indy vdri
-func New(didMethod, genesisURL string, kms legacykms.KMS, opts ...Option) (*VDRI, error) {
+func New(didMethod, genesisURL string, km kms.KeyManager, opts ...Option) (*VDRI, error) {
 	vdri := &VDRI{
 		didMethod:  didMethod,
 		prefix:     fmt.Sprintf("did:%s:", didMethod),
 		vdrAddress: fmt.Sprintf("%s:%d", DefaultVDRHost, DefaulVDRPort),
-		kms:        kms,
+		kms:        km,
 	}
 
 	for _, opt := range opts {
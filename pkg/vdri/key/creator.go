@@ -22,20 +22,25 @@ const (
 	schemaV1                   = "https://w3id.org/did/v1"
 	ed25519VerificationKey2018 = "Ed25519VerificationKey2018"
 	x25519KeyAgreementKey2019  = "X25519KeyAgreementKey2019"
+	bls12381G2Key2020          = "Bls12381G2Key2020"
 )
 
 const (
-	ed25519pub = 0xed // Ed25519 public key in multicodec table
-	x25519pub  = 0xec // Curve25519 public key in multicodec table
+	ed25519pub    = 0xed // Ed25519 public key in multicodec table
+	x25519pub     = 0xec // Curve25519 public key in multicodec table
+	bls12381g2pub = 0xeb // BLS12-381 G2 public key in multicodec table
 )
 
 // Build builds new DID document.
 func (v *VDRI) Build(pubKey *vdriapi.PubKey, opts ...vdriapi.DocOpts) (*did.Doc, error) {
-	if pubKey.Type != ed25519VerificationKey2018 {
+	switch pubKey.Type {
+	case ed25519VerificationKey2018:
+		return createDoc(base58.Decode(pubKey.Value))
+	case bls12381G2Key2020:
+		return createBLS12381G2Doc(base58.Decode(pubKey.Value)), nil
+	default:
 		return nil, fmt.Errorf("not supported public key type: %s", pubKey.Type)
 	}
-
-	return createDoc(base58.Decode(pubKey.Value))
 }
 
 func createDoc(pubKeyValue []byte) (*did.Doc, error) {
@@ -50,6 +55,23 @@ func createDoc(pubKeyValue []byte) (*did.Doc, error) {
 		return nil, err
 	}
 
+	doc := newDoc(didKey, pubKey)
+	doc.KeyAgreement = []did.VerificationMethod{{PublicKey: *keyAgreement}}
+
+	return doc, nil
+}
+
+// createBLS12381G2Doc creates the DID document of a BBS+ public key (compressed BLS12-381 G2 point), such keys
+// can't be used for key agreement.
+func createBLS12381G2Doc(pubKeyValue []byte) *did.Doc {
+	methodID := keyFingerprint(multicodec(bls12381g2pub), pubKeyValue)
+	didKey := fmt.Sprintf("did:key:%s", methodID)
+	keyID := fmt.Sprintf("%s#%s", didKey, methodID)
+
+	return newDoc(didKey, did.NewPublicKeyFromBytes(keyID, bls12381G2Key2020, didKey, pubKeyValue))
+}
+
+func newDoc(didKey string, pubKey *did.PublicKey) *did.Doc {
 	// Created/Updated time
 	t := time.Now()
 
@@ -61,10 +83,9 @@ func createDoc(pubKeyValue []byte) (*did.Doc, error) {
 		AssertionMethod:      []did.VerificationMethod{{PublicKey: *pubKey}},
 		CapabilityDelegation: []did.VerificationMethod{{PublicKey: *pubKey}},
 		CapabilityInvocation: []did.VerificationMethod{{PublicKey: *pubKey}},
-		KeyAgreement:         []did.VerificationMethod{{PublicKey: *keyAgreement}},
 		Created:              &t,
 		Updated:              &t,
-	}, nil
+	}
 }
 
 func keyFingerprint(multicodecValue, pubKeyValue []byte) string {
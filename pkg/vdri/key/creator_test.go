@@ -23,6 +23,9 @@ const (
 
 	pubKeyBase58       = "B12NYF8RrR3h41TDCTJojY59usg3mbtbjnFs7Eud1Y6u"
 	keyAgreementBase58 = "JhNWeSVLMYccCk7iopQW4guaSJTojqpMEELgSLhKwRr"
+
+	bls12381G2DIDKey       = "did:key:zUC7K4ndUaGZgV7Cp2yJy6JtMoUHY6u7tkcSYUvPrEidqBmLCTLmi6d5WvwnUqejscAkERJ3bfjEiSYtdPkRSE8kSa11hFBr4sTgnbZ95SJj19PN2jdvJjyzpSZgxkyyxNnBNnY" //nolint:lll
+	bls12381G2PubKeyBase58 = "25EEkQtcLKsEzQ6JTo9cg4W7NHpaurn4Wg6LaNPFq6JQXnrP91SDviUz7KrJVMJd76CtAZFsRLYzvgX2JGxo2ccUHtuHk7ELCWwrkBDfrXCFVfqJKDootee9iVaF6NpdJtBE"            //nolint:lll
 )
 
 func TestBuild(t *testing.T) {
@@ -70,6 +73,21 @@ func TestBuild(t *testing.T) {
 
 		assertDoc(t, doc)
 	})
+
+	t.Run("build with BLS12381G2 key type", func(t *testing.T) {
+		v := newVDRI(t)
+
+		pubKey := &vdriapi.PubKey{
+			Type:  bls12381G2Key2020,
+			Value: bls12381G2PubKeyBase58,
+		}
+
+		doc, err := v.Build(pubKey)
+		require.NoError(t, err)
+		require.NotNil(t, doc)
+
+		assertBLS12381G2Doc(t, doc)
+	})
 }
 
 func newVDRI(t *testing.T) *VDRI {
@@ -120,6 +138,22 @@ func assertDoc(t *testing.T, doc *did.Doc) {
 	assertPubKey(t, expectedKeyAgreement, &doc.KeyAgreement[0].PublicKey)
 }
 
+func assertBLS12381G2Doc(t *testing.T, doc *did.Doc) {
+	require.Equal(t, bls12381G2DIDKey, doc.ID)
+
+	expectedPubKey := &did.PublicKey{
+		ID:         bls12381G2DIDKey + "#" + bls12381G2DIDKey[len("did:key:"):],
+		Type:       bls12381G2Key2020,
+		Controller: bls12381G2DIDKey,
+		Value:      base58.Decode(bls12381G2PubKeyBase58),
+	}
+
+	assertPubKey(t, expectedPubKey, &doc.PublicKey[0])
+	assertPubKey(t, expectedPubKey, &doc.AssertionMethod[0].PublicKey)
+	assertPubKey(t, expectedPubKey, &doc.Authentication[0].PublicKey)
+	require.Empty(t, doc.KeyAgreement)
+}
+
 func assertPubKey(t *testing.T, expectedPubKey, actualPubKey *did.PublicKey) {
 	require.NotNil(t, actualPubKey)
 	require.Equal(t, expectedPubKey.ID, actualPubKey.ID)
@@ -28,11 +28,15 @@ func (v *VDRI) Read(didKey string, opts ...vdriapi.ResolveOpts) (*did.Doc, error
 		return nil, fmt.Errorf("invalid did:key method ID: %s", parsed.MethodSpecificID)
 	}
 
-	pubKey, err := pubKeyFromFingerprint(parsed.MethodSpecificID)
+	code, pubKey, err := pubKeyFromFingerprint(parsed.MethodSpecificID)
 	if err != nil {
 		return nil, err
 	}
 
+	if code == bls12381g2pub {
+		return createBLS12381G2Doc(pubKey), nil
+	}
+
 	return createDoc(pubKey)
 }
 
@@ -41,13 +45,16 @@ func isValidMethodID(id string) bool {
 	return r.MatchString(id)
 }
 
-func pubKeyFromFingerprint(fingerprint string) ([]byte, error) {
+func pubKeyFromFingerprint(fingerprint string) (uint64, []byte, error) {
 	// did:key:MULTIBASE(base58-btc, MULTICODEC(public-key-type, raw-public-key-bytes))
 	// https://w3c-ccg.github.io/did-method-key/#format
 	mc := base58.Decode(fingerprint[1:]) // skip leading "z"
-	if !bytes.Equal(multicodec(ed25519pub), mc[:2]) {
-		return nil, fmt.Errorf("not supported public key (multicodec code: %#x)", mc[0])
+
+	for _, code := range []uint64{ed25519pub, bls12381g2pub} {
+		if bytes.Equal(multicodec(code), mc[:2]) {
+			return code, mc[2:], nil
+		}
 	}
 
-	return mc[2:], nil
+	return 0, nil, fmt.Errorf("not supported public key (multicodec code: %#x)", mc[0])
 }
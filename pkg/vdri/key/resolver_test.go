@@ -49,4 +49,14 @@ func TestRead(t *testing.T) {
 
 		assertDoc(t, doc)
 	})
+
+	t.Run("resolve BLS12381G2 key", func(t *testing.T) {
+		v := newVDRI(t)
+
+		doc, err := v.Read(bls12381G2DIDKey)
+		require.NoError(t, err)
+		require.NotNil(t, doc)
+
+		assertBLS12381G2Doc(t, doc)
+	})
 }
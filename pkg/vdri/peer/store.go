@@ -15,6 +15,8 @@ import (
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
+	"github.com/hyperledger/aries-framework-go/pkg/storage"
+	"github.com/hyperledger/aries-framework-go/pkg/store/migration"
 )
 
 type docDelta struct {
@@ -23,22 +25,39 @@ type docDelta struct {
 	ModifiedAt time.Time             `json:"when,omitempty"`
 }
 
-// Store saves Peer DID Document along with user key/signature.
+// Migrations returns the schema migrations of the peer DID store, refer migration.Run().
+// Version 1 is the schema of the document deltas above.
+func Migrations() []migration.Migration {
+	return []migration.Migration{{Store: StoreNamespace, Version: 1, Description: "baseline of the document deltas"}}
+}
+
+// Store saves Peer DID Document along with user key/signature. The first document stored for a DID is its genesis
+// document, storing an updated document of the DID records it as a new delta.
 func (v *VDRI) Store(doc *did.Doc, by *[]vdriapi.ModifiedBy) error {
 	if doc == nil || doc.ID == "" {
 		return errors.New("DID and document are mandatory")
 	}
 
-	var deltas []docDelta
+	deltas, err := v.getDeltas(doc.ID)
+	if err != nil && !errors.Is(err, storage.ErrDataNotFound) {
+		return fmt.Errorf("delta data fetch from store failed: %w", err)
+	}
 
-	// For now, assume the doc is a genesis document
+	// each delta holds the document as it is after the change
 	jsonDoc, err := doc.JSONBytes()
 	if err != nil {
 		return fmt.Errorf("JSON marshalling of document failed: %w", err)
 	}
 
+	change := base64.URLEncoding.EncodeToString(jsonDoc)
+
+	// storing the current document again doesn't change it
+	if len(deltas) > 0 && deltas[len(deltas)-1].Change == change {
+		return nil
+	}
+
 	docDelta := &docDelta{
-		Change:     base64.URLEncoding.EncodeToString(jsonDoc),
+		Change:     change,
 		ModifiedBy: by,
 		ModifiedAt: time.Now(),
 	}
@@ -53,7 +72,7 @@ func (v *VDRI) Store(doc *did.Doc, by *[]vdriapi.ModifiedBy) error {
 	return v.store.Put(doc.ID, val)
 }
 
-// Get returns Peer DID Document
+// Get returns Peer DID Document, updated with its latest delta.
 func (v *VDRI) Get(id string) (*did.Doc, error) {
 	if id == "" {
 		return nil, errors.New("ID is mandatory")
@@ -64,8 +83,11 @@ func (v *VDRI) Get(id string) (*did.Doc, error) {
 		return nil, fmt.Errorf("delta data fetch from store failed: %w", err)
 	}
 
-	// For now, assume storage contains only one delta(genesis document)
-	delta := deltas[0]
+	if len(deltas) == 0 {
+		return nil, fmt.Errorf("no document delta for %s", id)
+	}
+
+	delta := deltas[len(deltas)-1]
 
 	doc, err := base64.URLEncoding.DecodeString(delta.Change)
 	if err != nil {
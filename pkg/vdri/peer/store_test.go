@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
+	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	"github.com/hyperledger/aries-framework-go/pkg/mock/storage"
 )
 
@@ -70,6 +71,58 @@ func TestPeerDIDStore(t *testing.T) {
 	require.Contains(t, err.Error(), "delta data fetch from store failed")
 }
 
+func TestPeerDIDStore_Update(t *testing.T) {
+	prov := storage.NewMockStoreProvider()
+	dbstore, err := prov.OpenStore(StoreNamespace)
+	require.NoError(t, err)
+
+	store, err := New(prov)
+	require.NoError(t, err)
+
+	didID := "did:peer:1234"
+	genesis := &did.Doc{Context: []string{did.Context}, ID: didID}
+
+	err = store.Store(genesis, nil)
+	require.NoError(t, err)
+
+	updated := &did.Doc{Context: []string{did.Context}, ID: didID, Service: []did.Service{{
+		ID:              "#agent",
+		Type:            "did-communication",
+		ServiceEndpoint: "http://example.com",
+	}}}
+
+	// storing the same document again doesn't add a delta
+	for i := 0; i < 2; i++ {
+		err = store.Store(updated, &[]vdriapi.ModifiedBy{{Key: "key", Sig: "sig"}})
+		require.NoError(t, err)
+	}
+
+	deltas, err := store.getDeltas(didID)
+	require.NoError(t, err)
+	require.Len(t, deltas, 2)
+	require.Equal(t, &[]vdriapi.ModifiedBy{{Key: "key", Sig: "sig"}}, deltas[1].ModifiedBy)
+
+	doc, err := store.Get(didID)
+	require.NoError(t, err)
+	require.Len(t, doc.Service, 1)
+	require.Equal(t, "http://example.com", doc.Service[0].ServiceEndpoint)
+
+	// no delta
+	err = dbstore.Put("did:peer:5678", []byte("[]"))
+	require.NoError(t, err)
+
+	_, err = store.Get("did:peer:5678")
+	require.EqualError(t, err, "no document delta for did:peer:5678")
+
+	// not json deltas
+	err = dbstore.Put("did:peer:5678", []byte("not json"))
+	require.NoError(t, err)
+
+	err = store.Store(&did.Doc{ID: "did:peer:5678"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "delta data fetch from store failed")
+}
+
 func TestVDRI_Close(t *testing.T) {
 	t.Run("test success", func(t *testing.T) {
 		v, err := New(&storage.MockStoreProvider{})
@@ -7,13 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package vdri
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/btcsuite/btcutil/base58"
+
 	diddoc "github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 const (
@@ -25,20 +28,20 @@ type Option func(opts *Registry)
 
 // provider contains dependencies for the did creator
 type provider interface {
-	LegacyKMS() legacykms.KeyManager
+	KMS() kms.KeyManager
 }
 
 // Registry vdri registry
 type Registry struct {
 	vdri               []vdriapi.VDRI
-	crypto             legacykms.KeyManager
+	kms                kms.KeyManager
 	defServiceEndpoint string
 	defServiceType     string
 }
 
 // New return new instance of vdri
 func New(ctx provider, opts ...Option) *Registry {
-	baseVDRI := &Registry{crypto: ctx.LegacyKMS()}
+	baseVDRI := &Registry{kms: ctx.KMS()}
 
 	// Apply options
 	for _, opt := range opts {
@@ -93,7 +96,7 @@ func (r *Registry) Create(didMethod string, opts ...vdriapi.DocOpts) (*diddoc.Do
 		opt(docOpts)
 	}
 
-	_, base58PubKey, err := r.crypto.CreateKeySet()
+	keyID, pubKey, err := r.kms.CreateAndExportPubKeyBytes(kms.ED25519Type)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create DID: %w", err)
 	}
@@ -103,7 +106,7 @@ func (r *Registry) Create(didMethod string, opts ...vdriapi.DocOpts) (*diddoc.Do
 		return nil, err
 	}
 
-	doc, err := method.Build(&vdriapi.PubKey{Value: base58PubKey, Type: docOpts.KeyType},
+	doc, err := method.Build(&vdriapi.PubKey{Value: base58.Encode(pubKey), Type: docOpts.KeyType},
 		r.applyDefaultDocOpts(docOpts, opts...)...)
 	if err != nil {
 		return nil, err
@@ -113,6 +116,17 @@ func (r *Registry) Create(didMethod string, opts ...vdriapi.DocOpts) (*diddoc.Do
 		return nil, err
 	}
 
+	// record the DID of the key, so that the DID document is updated when the key is rotated
+	for _, pk := range doc.PublicKey {
+		if bytes.Equal(pk.Value, pubKey) {
+			if err := r.kms.UpdateMetadata(keyID, kms.WithDID(doc.ID, pk.ID)); err != nil {
+				return nil, fmt.Errorf("failed to record the DID of its key: %w", err)
+			}
+
+			break
+		}
+	}
+
 	return doc, nil
 }
 
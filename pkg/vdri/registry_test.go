@@ -10,12 +10,13 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	vdriapi "github.com/hyperledger/aries-framework-go/pkg/framework/aries/api/vdri"
 	mockprovider "github.com/hyperledger/aries-framework-go/pkg/internal/mock/provider"
-	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms/legacykms"
+	mockkms "github.com/hyperledger/aries-framework-go/pkg/mock/kms"
 	mockvdri "github.com/hyperledger/aries-framework-go/pkg/mock/vdri"
 )
 
@@ -143,14 +144,14 @@ func TestRegistry_Store(t *testing.T) {
 func TestRegistry_Create(t *testing.T) {
 	t.Run("test error from create key", func(t *testing.T) {
 		registry := New(&mockprovider.Provider{
-			LegacyKMSValue: &mockkms.CloseableKMS{CreateKeyErr: fmt.Errorf("create key error")}})
+			KMSValue: &mockkms.KeyManager{CrAndExportPubKeyErr: fmt.Errorf("create key error")}})
 		doc, err := registry.Create("1:id:123")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "create key error")
 		require.Nil(t, doc)
 	})
 	t.Run("test did method not supported", func(t *testing.T) {
-		registry := New(&mockprovider.Provider{LegacyKMSValue: &mockkms.CloseableKMS{}},
+		registry := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}},
 			WithVDRI(&mockvdri.MockVDRI{AcceptValue: false}))
 		doc, err := registry.Create("id")
 		require.Error(t, err)
@@ -158,7 +159,7 @@ func TestRegistry_Create(t *testing.T) {
 		require.Nil(t, doc)
 	})
 	t.Run("test opts is passed", func(t *testing.T) {
-		registry := New(&mockprovider.Provider{LegacyKMSValue: &mockkms.CloseableKMS{}},
+		registry := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}},
 			WithVDRI(&mockvdri.MockVDRI{AcceptValue: true,
 				BuildFunc: func(pubKey *vdriapi.PubKey, opts ...vdriapi.DocOpts) (doc *did.Doc, e error) {
 					docOpts := &vdriapi.CreateDIDOpts{}
@@ -173,7 +174,7 @@ func TestRegistry_Create(t *testing.T) {
 		require.NoError(t, err)
 	})
 	t.Run("test error from build doc", func(t *testing.T) {
-		registry := New(&mockprovider.Provider{LegacyKMSValue: &mockkms.CloseableKMS{}},
+		registry := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}},
 			WithVDRI(&mockvdri.MockVDRI{AcceptValue: true,
 				BuildFunc: func(pubKey *vdriapi.PubKey, opts ...vdriapi.DocOpts) (doc *did.Doc, e error) {
 					return nil, fmt.Errorf("build did error")
@@ -184,7 +185,7 @@ func TestRegistry_Create(t *testing.T) {
 		require.Nil(t, doc)
 	})
 	t.Run("test error from store doc", func(t *testing.T) {
-		registry := New(&mockprovider.Provider{LegacyKMSValue: &mockkms.CloseableKMS{}},
+		registry := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}},
 			WithVDRI(&mockvdri.MockVDRI{AcceptValue: true, StoreErr: fmt.Errorf("store error"),
 				BuildFunc: func(pubKey *vdriapi.PubKey, opts ...vdriapi.DocOpts) (doc *did.Doc, e error) {
 					return &did.Doc{ID: "1:id:123"}, nil
@@ -194,8 +195,22 @@ func TestRegistry_Create(t *testing.T) {
 		require.Contains(t, err.Error(), "store error")
 		require.Nil(t, doc)
 	})
+	t.Run("test error from record the DID of the key", func(t *testing.T) {
+		registry := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{
+			CrAndExportPubKeyValue: []byte("key"), UpdateErr: fmt.Errorf("update metadata error")}},
+			WithVDRI(&mockvdri.MockVDRI{AcceptValue: true,
+				BuildFunc: func(pubKey *vdriapi.PubKey, opts ...vdriapi.DocOpts) (doc *did.Doc, e error) {
+					return &did.Doc{ID: "1:id:123", PublicKey: []did.PublicKey{
+						{ID: "1:id:123#key1", Value: base58.Decode(pubKey.Value)},
+					}}, nil
+				}}))
+		doc, err := registry.Create("id")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "update metadata error")
+		require.Nil(t, doc)
+	})
 	t.Run("test success", func(t *testing.T) {
-		registry := New(&mockprovider.Provider{LegacyKMSValue: &mockkms.CloseableKMS{}},
+		registry := New(&mockprovider.Provider{KMSValue: &mockkms.KeyManager{}},
 			WithVDRI(&mockvdri.MockVDRI{AcceptValue: true,
 				BuildFunc: func(pubKey *vdriapi.PubKey, opts ...vdriapi.DocOpts) (doc *did.Doc, e error) {
 					return &did.Doc{ID: "1:id:123"}, nil
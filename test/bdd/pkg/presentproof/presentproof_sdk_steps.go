@@ -22,7 +22,7 @@ import (
 	"github.com/hyperledger/aries-framework-go/pkg/didcomm/protocol/decorator"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/did"
 	"github.com/hyperledger/aries-framework-go/pkg/doc/verifiable"
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 	"github.com/hyperledger/aries-framework-go/test/bdd/pkg/context"
 )
 
@@ -298,11 +298,11 @@ func (a *SDKSteps) getConnection(agent1, agent2 string) (*didexchange.Connection
 }
 
 type signer struct {
-	kms   legacykms.Signer
+	kms   kms.Signer
 	keyID string
 }
 
-func newSigner(kms legacykms.Signer, keyID string) *signer {
+func newSigner(kms kms.Signer, keyID string) *signer {
 	return &signer{kms: kms, keyID: keyID}
 }
 
@@ -7,15 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package verifiable
 
 import (
-	"github.com/hyperledger/aries-framework-go/pkg/kms/legacykms"
+	"github.com/hyperledger/aries-framework-go/pkg/kms"
 )
 
 type signer struct {
-	kms   legacykms.Signer
+	kms   kms.Signer
 	keyID string
 }
 
-func newSigner(kms legacykms.Signer, keyID string) *signer {
+func newSigner(kms kms.Signer, keyID string) *signer {
 	return &signer{kms: kms, keyID: keyID}
 }
 